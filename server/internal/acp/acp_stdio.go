@@ -202,7 +202,22 @@ type SessionPromptParams struct {
 
 // SessionPromptResult is the response from session/prompt
 type SessionPromptResult struct {
-	StopReason string `json:"stopReason"` // "end_turn", "max_tokens", "cancelled", etc.
+	StopReason string       `json:"stopReason"`      // "end_turn", "max_tokens", "cancelled", etc.
+	Usage      *PromptUsage `json:"usage,omitempty"` // only set by agents that report usage
+}
+
+// PromptUsage is the token usage an agent reports for a prompt turn
+type PromptUsage struct {
+	InputTokens      int `json:"inputTokens"`
+	OutputTokens     int `json:"outputTokens"`
+	CachedReadTokens int `json:"cachedReadTokens,omitempty"`
+	TotalTokens      int `json:"totalTokens,omitempty"`
+}
+
+// UsageCost is a monetary amount reported in a usage_update
+type UsageCost struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
 }
 
 // SessionUpdateParams represents a session/update notification
@@ -220,6 +235,9 @@ type SessionUpdate struct {
 	Kind          string        `json:"kind,omitempty"`
 	Status        string        `json:"status,omitempty"`
 	Entries       []PlanEntry   `json:"entries,omitempty"`
+	Cost          *UsageCost    `json:"cost,omitempty"` // usage_update: cumulative session cost
+	Used          int           `json:"used,omitempty"` // usage_update: tokens in the session's context
+	Size          int           `json:"size,omitempty"` // usage_update: context window size
 }
 
 // PlanEntry represents a plan entry
@@ -134,6 +134,7 @@ type Run struct {
 	AwaitRequest *string    `json:"await_request,omitempty"`
 	Output       []Message  `json:"output"`
 	Error        *Error     `json:"error,omitempty"`
+	Usage        *RunUsage  `json:"usage,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
 	FinishedAt   *time.Time `json:"finished_at,omitempty"`
 }
@@ -6,7 +6,9 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,6 +16,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/config"
 	"github.com/diane-assistant/diane/internal/store"
 )
 
@@ -69,6 +72,8 @@ type Manager struct {
 	sessionStore store.ACPSessionStore
 	agentStore   store.ACPAgentStore
 	reaperCancel context.CancelFunc
+
+	usageRecorder UsageRecorder
 }
 
 // NewManager creates a new ACP manager
@@ -95,7 +100,7 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	configPath := filepath.Join(home, ".diane", "acp-agents.json")
+	configPath := filepath.Join(home, config.DirName(), "acp-agents.json")
 	m := &Manager{
 		configPath:   configPath,
 		clients:      make(map[string]*Client),
@@ -863,8 +868,21 @@ func (m *Manager) GetAvailablePort(startPort int) int {
 	return startPort + 100
 }
 
+// DefaultRunTimeout bounds an agent run when the caller doesn't set a deadline
+const DefaultRunTimeout = 5 * time.Minute
+
 // RunAgent runs a prompt against an agent (supports both ACP and stdio types)
+// with DefaultRunTimeout
 func (m *Manager) RunAgent(name, prompt string) (*Run, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRunTimeout)
+	defer cancel()
+	return m.RunAgentContext(ctx, name, prompt)
+}
+
+// RunAgentContext runs a prompt against an agent until ctx is done. When the
+// deadline passes the agent's turn is cancelled and the run is marked failed
+// with a "timeout" error.
+func (m *Manager) RunAgentContext(ctx context.Context, name, prompt string) (*Run, error) {
 	agent, err := m.GetAgent(name)
 	if err != nil {
 		return nil, err
@@ -876,7 +894,7 @@ func (m *Manager) RunAgent(name, prompt string) (*Run, error) {
 
 	// Handle simple stdio agents (just run command with prompt as arg)
 	if agent.Type == "stdio" {
-		return m.runSimpleStdioAgent(agent, prompt)
+		return m.runSimpleStdioAgent(ctx, agent, prompt)
 	}
 
 	// If agent has no URL, it implies an emergent agent via internal execution
@@ -886,14 +904,16 @@ func (m *Manager) RunAgent(name, prompt string) (*Run, error) {
 
 	// Handle ACP agents (proper ACP protocol over stdio)
 	if agent.Type == "acp" || agent.Type == "" {
-		return m.runACPAgent(agent, prompt)
+		run, err := m.runACPAgent(ctx, agent, prompt)
+		m.recordUsage(run)
+		return run, err
 	}
 
 	return nil, fmt.Errorf("unknown agent type: %s", agent.Type)
 }
 
 // runACPAgent runs a prompt against an ACP agent using the proper protocol
-func (m *Manager) runACPAgent(agent *AgentConfig, prompt string) (*Run, error) {
+func (m *Manager) runACPAgent(ctx context.Context, agent *AgentConfig, prompt string) (*Run, error) {
 	// Create run record
 	runID := make([]byte, 16)
 	rand.Read(runID)
@@ -929,9 +949,6 @@ func (m *Manager) runACPAgent(agent *AgentConfig, prompt string) (*Run, error) {
 	}
 
 	// Create stdio client
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
 	client, err := NewStdioClient(cmdPath, args, agent.WorkDir, agent.Env)
 	if err != nil {
 		now := time.Now()
@@ -975,21 +992,41 @@ func (m *Manager) runACPAgent(agent *AgentConfig, prompt string) (*Run, error) {
 		return run, nil
 	}
 
-	// Collect output from updates
+	// Collect output from updates. The session is new, so its cumulative
+	// usage is this run's usage.
 	var outputText string
+	meter := m.newUsageMeter(run, 0, 0)
 
 	// Send prompt
 	result, err := client.Prompt(ctx, sessionID, prompt, func(update *SessionUpdateParams) {
-		if update.Update.SessionUpdate == "agent_message_chunk" && update.Update.Content != nil {
-			if update.Update.Content.Type == "text" {
+		switch update.Update.SessionUpdate {
+		case "agent_message_chunk":
+			if update.Update.Content != nil && update.Update.Content.Type == "text" {
 				outputText += update.Update.Content.Text
 			}
+		case "usage_update":
+			meter.update(&update.Update)
 		}
 	})
 
 	now := time.Now()
 	run.FinishedAt = &now
 
+	var promptUsage *PromptUsage
+	if result != nil {
+		promptUsage = result.Usage
+	}
+	run.Usage = meter.usage(promptUsage)
+
+	if err != nil && ctx.Err() != nil {
+		// Tell the agent to stop its turn rather than just abandoning it
+		if cancelErr := client.Cancel(sessionID); cancelErr != nil {
+			slog.Debug("Failed to cancel agent session", "agent", agent.Name, "error", cancelErr)
+		}
+		run.Status = RunStatusFailed
+		run.Error = runDeadlineError(ctx, run.CreatedAt)
+		return run, nil
+	}
 	if err != nil {
 		run.Status = RunStatusFailed
 		run.Error = &Error{
@@ -1017,14 +1054,11 @@ func (m *Manager) runACPAgent(agent *AgentConfig, prompt string) (*Run, error) {
 }
 
 // runSimpleStdioAgent runs a prompt against a simple stdio-based agent
-func (m *Manager) runSimpleStdioAgent(agent *AgentConfig, prompt string) (*Run, error) {
+func (m *Manager) runSimpleStdioAgent(ctx context.Context, agent *AgentConfig, prompt string) (*Run, error) {
 	// Build command with args and prompt
 	args := append([]string{}, agent.Args...)
 	args = append(args, prompt)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
 	cmd := exec.CommandContext(ctx, agent.Command, args...)
 	if agent.WorkDir != "" {
 		cmd.Dir = agent.WorkDir
@@ -1054,6 +1088,11 @@ func (m *Manager) runSimpleStdioAgent(agent *AgentConfig, prompt string) (*Run,
 	now := time.Now()
 	run.FinishedAt = &now
 
+	if err != nil && ctx.Err() != nil {
+		run.Status = RunStatusFailed
+		run.Error = runDeadlineError(ctx, run.CreatedAt)
+		return run, nil
+	}
 	if err != nil {
 		run.Status = RunStatusFailed
 		run.Error = &Error{
@@ -1071,6 +1110,17 @@ func (m *Manager) runSimpleStdioAgent(agent *AgentConfig, prompt string) (*Run,
 	return run, nil
 }
 
+// runDeadlineError describes a run stopped because ctx ended
+func runDeadlineError(ctx context.Context, started time.Time) *Error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &Error{
+			Code:    "timeout",
+			Message: fmt.Sprintf("agent run timed out after %v", time.Since(started).Round(time.Second)),
+		}
+	}
+	return &Error{Code: "cancelled", Message: "agent run cancelled"}
+}
+
 func (m *Manager) toStore(a AgentConfig) store.ACPAgentConfig {
 	return store.ACPAgentConfig{
 		Name:        a.Name,
@@ -12,6 +12,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 const (
@@ -74,7 +76,7 @@ func NewRegistryClient() (*RegistryClient, error) {
 		return nil, err
 	}
 
-	cachePath := filepath.Join(home, ".diane", "acp-registry.json")
+	cachePath := filepath.Join(home, config.DirName(), "acp-registry.json")
 
 	return &RegistryClient{
 		CachePath: cachePath,
@@ -274,6 +276,43 @@ func (a *RegistryAgent) CheckInstalled() bool {
 	return false
 }
 
+// SetupCommand returns the command that installs the agent ahead of time,
+// or "" if there is none. NPX agents are fetched by npx on first run, so
+// installing one means adding its package globally.
+func (a *RegistryAgent) SetupCommand() string {
+	if a.IsNPXBased() {
+		return fmt.Sprintf("npm install -g %s", a.Distribution.NPX.Package)
+	}
+	return ""
+}
+
+// CheckSetUp checks if the agent itself is installed, not just something
+// able to fetch it: the global npm package for NPX agents, the binary for
+// binary ones
+func (a *RegistryAgent) CheckSetUp() bool {
+	if a.IsNPXBased() {
+		return npmPackageInstalled(npmPackageName(a.Distribution.NPX.Package))
+	}
+	return a.CheckInstalled()
+}
+
+// npmPackageInstalled reports whether an npm package is installed globally
+func npmPackageInstalled(name string) bool {
+	if _, err := exec.LookPath("npm"); err != nil {
+		return false
+	}
+	return exec.Command("npm", "ls", "-g", "--depth=0", name).Run() == nil
+}
+
+// npmPackageName strips the version from a package spec such as
+// "@scope/agent@1.2.0"
+func npmPackageName(spec string) string {
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		return spec[:i]
+	}
+	return spec
+}
+
 // GalleryEntry represents a pre-configured agent for the gallery
 type GalleryEntry struct {
 	ID          string   `json:"id"`
@@ -474,6 +513,8 @@ func (g *Gallery) GetInstallInfo(id string) (*InstallInfo, error) {
 		info.Env = env
 		info.Available = agent.CheckInstalled()
 	}
+	info.SetupCmd = agent.SetupCommand()
+	info.Installed = agent.CheckSetUp()
 
 	if agent.IsNPXBased() {
 		info.InstallType = "npx"
@@ -504,6 +545,10 @@ type InstallInfo struct {
 	Env         map[string]string `json:"env,omitempty"`
 	Available   bool              `json:"available"`
 	Error       string            `json:"error,omitempty"`
+	// SetupCmd installs the agent ahead of time (InstallCmd is how it is
+	// run); Installed reports whether it has been
+	SetupCmd  string `json:"setup_cmd,omitempty"`
+	Installed bool   `json:"installed"`
 	// Workspace-specific fields
 	WorkDir    string `json:"workdir,omitempty"`     // Suggested working directory
 	WorkDirArg string `json:"workdir_arg,omitempty"` // CLI arg for setting workdir (e.g., "--cwd", "--include-directories")
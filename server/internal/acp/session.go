@@ -43,6 +43,12 @@ type SessionState struct {
 	Title        string
 	Models       *ModelsInfo
 	Modes        *ModesInfo
+
+	// reportedCost and reportedUsed are the cumulative cost and context
+	// tokens from the agent's last usage_update, so each turn records only
+	// its own share
+	reportedCost float64
+	reportedUsed int
 }
 
 // SessionInfo is the JSON-serializable snapshot of a session returned by the API.
@@ -207,6 +213,12 @@ func (m *Manager) StartSession(agentName string, workDir string, title string) (
 // The full prompt+response (including tool calls and timing) is persisted as an
 // ACPSessionMessage.
 func (m *Manager) PromptSession(sessionID string, prompt string) (*Run, error) {
+	return m.PromptSessionStream(sessionID, prompt, nil)
+}
+
+// PromptSessionStream is PromptSession with onText called for each chunk of
+// agent text as it arrives, so callers can stream the response.
+func (m *Manager) PromptSessionStream(sessionID string, prompt string, onText func(string)) (*Run, error) {
 	if m.sessionStore == nil {
 		return nil, fmt.Errorf("session store not configured")
 	}
@@ -232,9 +244,22 @@ func (m *Manager) PromptSession(sessionID string, prompt string) (*Run, error) {
 	turnNumber := state.TurnCount
 	startTime := time.Now()
 
+	// Build the Run up front: its ID ties the usage recorded while the turn
+	// streams to the final record.
+	runID := make([]byte, 16)
+	rand.Read(runID)
+	run := &Run{
+		AgentName:  state.AgentName,
+		SessionID:  sessionID,
+		RunID:      hex.EncodeToString(runID),
+		TurnNumber: turnNumber,
+		CreatedAt:  startTime,
+	}
+
 	// Collect streamed output.
 	var outputText string
 	var toolCalls []store.ACPToolCall
+	meter := m.newUsageMeter(run, state.reportedCost, state.reportedUsed)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
@@ -244,6 +269,9 @@ func (m *Manager) PromptSession(sessionID string, prompt string) (*Run, error) {
 		case "agent_message_chunk":
 			if update.Update.Content != nil && update.Update.Content.Type == "text" {
 				outputText += update.Update.Content.Text
+				if onText != nil {
+					onText(update.Update.Content.Text)
+				}
 			}
 		case "tool_call":
 			toolCalls = append(toolCalls, store.ACPToolCall{
@@ -252,23 +280,14 @@ func (m *Manager) PromptSession(sessionID string, prompt string) (*Run, error) {
 				Kind:       update.Update.Kind,
 				Status:     update.Update.Status,
 			})
+		case "usage_update":
+			meter.update(&update.Update)
 		}
 	})
 
 	durationMs := int(time.Since(startTime).Milliseconds())
 	state.LastActiveAt = time.Now().UTC()
 
-	// Build the Run response.
-	runID := make([]byte, 16)
-	rand.Read(runID)
-	run := &Run{
-		AgentName:  state.AgentName,
-		SessionID:  sessionID,
-		RunID:      hex.EncodeToString(runID),
-		TurnNumber: turnNumber,
-		CreatedAt:  startTime,
-	}
-
 	// Build the persistent message record.
 	msgID := make([]byte, 16)
 	rand.Read(msgID)
@@ -307,6 +326,21 @@ func (m *Manager) PromptSession(sessionID string, prompt string) (*Run, error) {
 		msg.StopReason = result.StopReason
 	}
 
+	var promptUsage *PromptUsage
+	if result != nil {
+		promptUsage = result.Usage
+	}
+	// Attribute the turn's usage even if it was cut short, and move the
+	// session's baseline past it so the next turn records only its own
+	run.Usage = meter.usage(promptUsage)
+	m.recordUsage(run)
+	if meter.cost != nil {
+		state.reportedCost = meter.cost.Amount
+	}
+	if meter.used > 0 {
+		state.reportedUsed = meter.used
+	}
+
 	// Mark idle after prompt completes.
 	state.Status = SessionIdle
 
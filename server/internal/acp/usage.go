@@ -0,0 +1,138 @@
+package acp
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// usageProgressInterval is how often a streaming run's partial usage is
+// recorded after the first usage_update, so long turns show up in usage
+// while they run without a write per update
+const usageProgressInterval = 15 * time.Second
+
+// RunUsage is the token usage and cost an agent reported for a run. Agents
+// that don't report usage leave Run.Usage nil.
+type RunUsage struct {
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CachedTokens int     `json:"cached_tokens,omitempty"`
+	Cost         float64 `json:"cost,omitempty"` // USD
+	// Partial means the agent never reported the turn's final usage, e.g.
+	// because it is still streaming or was cancelled. The tokens are then
+	// the growth of the agent's context seen so far, counted as input.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// UsageRecorder stores the usage of a run, e.g. in the usage table. It is
+// called with a run's partial usage while it streams and again when it
+// finishes, so it must replace earlier records of the same RunID.
+type UsageRecorder func(run *Run) error
+
+// SetUsageRecorder sets where runs that report usage are recorded
+func (m *Manager) SetUsageRecorder(r UsageRecorder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usageRecorder = r
+}
+
+// recordUsage passes a run's usage to the recorder, if both are set
+func (m *Manager) recordUsage(run *Run) {
+	if run == nil || run.Usage == nil {
+		return
+	}
+	m.mu.RLock()
+	recorder := m.usageRecorder
+	m.mu.RUnlock()
+	if recorder == nil {
+		return
+	}
+	if err := recorder(run); err != nil {
+		slog.Warn("Failed to record agent usage", "agent", run.AgentName, "run_id", run.RunID, "error", err)
+	}
+}
+
+// newRunUsage builds a run's usage from the prompt result and the cost the
+// agent reported during the turn. Only USD costs are kept, since the usage
+// table is in USD. Returns nil when the agent reported neither.
+func newRunUsage(usage *PromptUsage, cost *UsageCost) *RunUsage {
+	var u RunUsage
+	if usage != nil {
+		u.InputTokens = usage.InputTokens
+		u.OutputTokens = usage.OutputTokens
+		u.CachedTokens = usage.CachedReadTokens
+	}
+	if cost != nil && strings.EqualFold(cost.Currency, "USD") {
+		u.Cost = cost.Amount
+	}
+	if usage == nil && u.Cost == 0 {
+		return nil
+	}
+	return &u
+}
+
+// usageMeter follows the usage_updates of a streaming turn, recording the
+// partial usage as it goes so an aborted or stuck turn is still accounted
+// for. The base values are the session's cumulative figures before the turn.
+type usageMeter struct {
+	m        *Manager
+	run      Run // identity of the metered run
+	baseCost float64
+	baseUsed int
+	cost     *UsageCost
+	used     int
+	recorded time.Time
+}
+
+func (m *Manager) newUsageMeter(run *Run, baseCost float64, baseUsed int) *usageMeter {
+	return &usageMeter{
+		m:        m,
+		run:      Run{AgentName: run.AgentName, SessionID: run.SessionID, RunID: run.RunID, TurnNumber: run.TurnNumber, CreatedAt: run.CreatedAt},
+		baseCost: baseCost,
+		baseUsed: baseUsed,
+	}
+}
+
+// update takes a usage_update. The partial usage is recorded on the first
+// one and then at most every usageProgressInterval.
+func (u *usageMeter) update(update *SessionUpdate) {
+	if update.Cost != nil {
+		u.cost = update.Cost
+	}
+	if update.Used > 0 {
+		u.used = update.Used
+	}
+	if !u.recorded.IsZero() && time.Since(u.recorded) < usageProgressInterval {
+		return
+	}
+	u.recorded = time.Now()
+	progress := u.run
+	progress.Status = RunStatusInProgress
+	progress.Usage = u.usage(nil)
+	u.m.recordUsage(&progress)
+}
+
+// turnCost returns the cost reported for the turn so far
+func (u *usageMeter) turnCost() *UsageCost {
+	if u.cost == nil {
+		return nil
+	}
+	return &UsageCost{Amount: u.cost.Amount - u.baseCost, Currency: u.cost.Currency}
+}
+
+// usage returns the turn's usage from the agent's final report, or the
+// partial usage seen while streaming when there is none
+func (u *usageMeter) usage(final *PromptUsage) *RunUsage {
+	if final != nil {
+		return newRunUsage(final, u.turnCost())
+	}
+	var seen *PromptUsage
+	if tokens := u.used - u.baseUsed; tokens > 0 {
+		seen = &PromptUsage{InputTokens: tokens}
+	}
+	usage := newRunUsage(seen, u.turnCost())
+	if usage != nil {
+		usage.Partial = true
+	}
+	return usage
+}
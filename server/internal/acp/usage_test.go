@@ -0,0 +1,75 @@
+package acp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageMeter(t *testing.T) {
+	var recorded []Run
+	m := &Manager{}
+	m.SetUsageRecorder(func(run *Run) error {
+		recorded = append(recorded, *run)
+		return nil
+	})
+
+	// The session had already used 1000 tokens and $1 before this turn
+	run := &Run{AgentName: "gemini", SessionID: "s1", RunID: "r1", TurnNumber: 2, CreatedAt: time.Now()}
+	meter := m.newUsageMeter(run, 1.0, 1000)
+
+	meter.update(&SessionUpdate{Cost: &UsageCost{Amount: 1.25, Currency: "USD"}, Used: 1600})
+	if len(recorded) != 1 {
+		t.Fatalf("expected the first usage_update to be recorded, got %d records", len(recorded))
+	}
+	got := recorded[0]
+	if got.RunID != "r1" || got.Status != RunStatusInProgress || got.Usage == nil {
+		t.Fatalf("unexpected progress record %+v", got)
+	}
+	if u := got.Usage; !u.Partial || u.InputTokens != 600 || u.Cost != 0.25 {
+		t.Errorf("expected the turn's growth over the baseline as partial usage, got %+v", u)
+	}
+
+	// Further updates within the interval are only remembered
+	meter.update(&SessionUpdate{Cost: &UsageCost{Amount: 1.5, Currency: "USD"}, Used: 2000})
+	if len(recorded) != 1 {
+		t.Errorf("expected updates within %v not to be recorded, got %d records", usageProgressInterval, len(recorded))
+	}
+
+	// A cancelled turn never gets final usage: the latest seen is partial
+	if u := meter.usage(nil); u == nil || !u.Partial || u.InputTokens != 1000 || u.Cost != 0.5 {
+		t.Errorf("expected partial usage for a cancelled turn, got %+v", u)
+	}
+
+	// The agent's final report replaces the partial figures
+	u := meter.usage(&PromptUsage{InputTokens: 900, OutputTokens: 150, CachedReadTokens: 300})
+	if u == nil || u.Partial || u.InputTokens != 900 || u.OutputTokens != 150 || u.CachedTokens != 300 || u.Cost != 0.5 {
+		t.Errorf("expected the final usage, got %+v", u)
+	}
+}
+
+func TestUsageMeterNothingReported(t *testing.T) {
+	m := &Manager{}
+	m.SetUsageRecorder(func(run *Run) error {
+		t.Errorf("expected nothing to be recorded, got %+v", run.Usage)
+		return nil
+	})
+	meter := m.newUsageMeter(&Run{RunID: "r1"}, 0, 0)
+
+	// A usage_update without growth or a USD cost has nothing to record
+	meter.update(&SessionUpdate{Cost: &UsageCost{Amount: 3, Currency: "EUR"}})
+	if u := meter.usage(nil); u != nil {
+		t.Errorf("expected no usage, got %+v", u)
+	}
+}
+
+func TestNewRunUsage(t *testing.T) {
+	if u := newRunUsage(nil, nil); u != nil {
+		t.Errorf("expected nil without usage or cost, got %+v", u)
+	}
+	if u := newRunUsage(nil, &UsageCost{Amount: 0.1, Currency: "usd"}); u == nil || u.Cost != 0.1 {
+		t.Errorf("expected a cost-only usage, got %+v", u)
+	}
+	if u := newRunUsage(&PromptUsage{InputTokens: 5}, &UsageCost{Amount: 2, Currency: "EUR"}); u == nil || u.Cost != 0 || u.InputTokens != 5 {
+		t.Errorf("expected a non-USD cost to be dropped, got %+v", u)
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/diane-assistant/diane/internal/acp"
 	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/internal/cron"
 	"github.com/diane-assistant/diane/internal/db"
 	"github.com/diane-assistant/diane/internal/emergent"
 	"github.com/diane-assistant/diane/internal/models"
@@ -38,6 +40,29 @@ type MCPServerStatus struct {
 	Builtin       bool   `json:"builtin,omitempty"`
 	RequiresAuth  bool   `json:"requires_auth,omitempty"`
 	Authenticated bool   `json:"authenticated,omitempty"`
+	// Degraded is set for a builtin provider whose recent calls are failing
+	// because its backend is unreachable; Error holds the latest failure
+	Degraded bool `json:"degraded,omitempty"`
+	// InFlight is the number of tool calls currently executing on the server
+	InFlight       int `json:"in_flight"`
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+}
+
+// MCPServerRestartResult reports the outcome of restarting one MCP server in a batch
+type MCPServerRestartResult struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ReloadResult reports which MCP servers a config reload affected. Servers
+// not listed kept running untouched.
+type ReloadResult struct {
+	Status       string   `json:"status"`
+	Started      []string `json:"started,omitempty"`
+	Stopped      []string `json:"stopped,omitempty"`
+	Restarted    []string `json:"restarted,omitempty"`
+	Reconfigured []string `json:"reconfigured,omitempty"`
 }
 
 // Status represents the overall Diane status
@@ -67,6 +92,26 @@ type ToolInfo struct {
 	Server      string                 `json:"server"`
 	Builtin     bool                   `json:"builtin"`
 	InputSchema map[string]interface{} `json:"input_schema,omitempty"`
+
+	// Category groups tools by what they work with (e.g. "email", "files"),
+	// across servers. Empty for tools of proxied servers.
+	Category string `json:"category,omitempty"`
+
+	// Set when the tool has a customization: Description is then the custom
+	// one, OriginalDescription the server's own, and HiddenArgs are left out
+	// of InputSchema
+	Customized          bool     `json:"customized,omitempty"`
+	OriginalDescription string   `json:"original_description,omitempty"`
+	HiddenArgs          []string `json:"hidden_args,omitempty"`
+}
+
+// ToolCustomization overrides how a tool is offered to MCP clients. An empty
+// Description keeps the tool's own; empty Description and HiddenArgs remove
+// the customization.
+type ToolCustomization struct {
+	Tool        string   `json:"tool"`
+	Description string   `json:"description,omitempty"`
+	HiddenArgs  []string `json:"hidden_args,omitempty"`
 }
 
 // PromptArgument represents an argument for a prompt
@@ -97,15 +142,70 @@ type ResourceInfo struct {
 
 // Job represents a scheduled job
 type Job struct {
-	ID         int64     `json:"id"`
-	Name       string    `json:"name"`
-	Command    string    `json:"command"`
-	Schedule   string    `json:"schedule"`
-	Enabled    bool      `json:"enabled"`
-	ActionType string    `json:"action_type,omitempty"`
-	AgentName  *string   `json:"agent_name,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID             int64   `json:"id"`
+	Name           string  `json:"name"`
+	Command        string  `json:"command"`
+	Schedule       string  `json:"schedule"`
+	ScheduleText   string  `json:"schedule_text,omitempty"` // natural-language phrase, if any
+	Enabled        bool    `json:"enabled"`
+	ActionType     string  `json:"action_type,omitempty"`
+	AgentName      *string `json:"agent_name,omitempty"`
+	MaxOutputBytes int     `json:"max_output_bytes,omitempty"` // 0 = the global limit
+	Concurrency    string  `json:"concurrency,omitempty"`      // "skip", "queue" or "allow"
+	TimeoutSeconds int     `json:"timeout_seconds,omitempty"`  // 0 = no limit
+	// Env and Workdir are the extra environment variables and directory the
+	// command runs with; EffectiveWorkdir fills in the default for display
+	Env              map[string]string `json:"env,omitempty"`
+	Workdir          string            `json:"workdir,omitempty"`
+	EffectiveWorkdir string            `json:"effective_workdir,omitempty"`
+	// DependsOn names the job whose successful retried runs also fire this one
+	DependsOn    string `json:"depends_on,omitempty"`
+	OutputFormat string `json:"output_format,omitempty"` // "json", or empty for text
+	// NotifyTemplate names the notify template sent when a run fails
+	NotifyTemplate string `json:"notify_template,omitempty"`
+	// Paused is set while a pause is in effect; PausedUntil is when it
+	// ends, nil for a pause that lasts until the job is resumed
+	Paused      bool       `json:"paused,omitempty"`
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	// NextRun is when the job next fires in the daemon's local time, nil
+	// when it's disabled, paused until resumed, or its schedule never fires
+	NextRun   *time.Time `json:"next_run,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// JobSpec is the portable definition of a job used by jobs export and import
+type JobSpec struct {
+	Name           string            `json:"name"`
+	Schedule       string            `json:"schedule"`
+	Command        string            `json:"command,omitempty"`
+	Enabled        *bool             `json:"enabled,omitempty"` // nil means enabled
+	ActionType     string            `json:"action_type,omitempty"`
+	AgentName      string            `json:"agent_name,omitempty"`
+	MaxOutputBytes int               `json:"max_output_bytes,omitempty"`
+	Concurrency    string            `json:"concurrency,omitempty"` // empty means skip
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	Workdir        string            `json:"workdir,omitempty"` // empty means the home directory
+	// DependsOn names the job whose successful retried runs also fire this one
+	DependsOn      string `json:"depends_on,omitempty"`
+	OutputFormat   string `json:"output_format,omitempty"` // empty means text
+	NotifyTemplate string `json:"notify_template,omitempty"`
+}
+
+// NotifyTemplate is a named notification message template, a Go
+// text/template rendered against values such as job_name and exit_code
+type NotifyTemplate struct {
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// JobImportResult is the outcome of importing one job
+type JobImportResult struct {
+	Name   string `json:"name"`
+	Action string `json:"action"` // "created", "updated", "unchanged", "deleted", "invalid" or "failed"
+	Error  string `json:"error,omitempty"`
 }
 
 // JobExecution represents a job execution log entry
@@ -119,6 +219,26 @@ type JobExecution struct {
 	Stdout    string     `json:"stdout,omitempty"`
 	Stderr    string     `json:"stderr,omitempty"`
 	Error     *string    `json:"error,omitempty"`
+
+	// Workdir is the directory the job runs in now, which a run predating a
+	// workdir change may not have used
+	Workdir string `json:"workdir,omitempty"`
+
+	// StdoutTruncated and StderrTruncated count the bytes cut from each
+	// stream to fit the job's output limit
+	StdoutTruncated int `json:"stdout_truncated,omitempty"`
+	StderrTruncated int `json:"stderr_truncated,omitempty"`
+
+	// Status is "skipped" for a fire that didn't run because the previous
+	// run was still going
+	Status string `json:"status,omitempty"`
+
+	// RetryOf is the ID of the execution this one retries, if any
+	RetryOf *int64 `json:"retry_of,omitempty"`
+
+	// Output is the structured data parsed from stdout for a job whose
+	// output format is json
+	Output any `json:"output,omitempty"`
 }
 
 // DoctorCheck represents a single diagnostic check result
@@ -139,23 +259,88 @@ type StatusProvider interface {
 	GetStatus() Status
 	GetMCPServers() []MCPServerStatus
 	GetAllTools() []ToolInfo
+	SetToolCustomization(c ToolCustomization) error
 	GetAllPrompts() []PromptInfo
 	GetAllResources() []ResourceInfo
 	GetPromptContent(serverName string, promptName string) (json.RawMessage, error)
 	ReadResourceContent(serverName string, uri string) (json.RawMessage, error)
+	CallToolInContext(name string, args map[string]interface{}, contextName string) (json.RawMessage, error)
 	RestartMCPServer(name string) error
-	ReloadConfig() error
+	RestartMCPServers(onlyFailed bool) ([]MCPServerRestartResult, error)
+	SetMCPServerEnabled(name string, enabled bool) error
+	// GetSSEClients lists the clients connected to the MCP HTTP server's
+	// SSE transport
+	GetSSEClients() ([]SSEClient, error)
+	ReloadConfig() (*ReloadResult, error)
+	RestartDaemon() error
 	GetJobs() ([]Job, error)
 	GetJobLogs(jobName string, limit int) ([]JobExecution, error)
 	ToggleJob(name string, enabled bool) error
+	// PauseJob stops a job firing until the given time, or until ResumeJob
+	// when until is nil, without disabling it
+	PauseJob(name string, until *time.Time) error
+	ResumeJob(name string) error
+	// RetryJobExecution re-runs the job behind a failed execution and
+	// returns the new execution once it finishes
+	RetryJobExecution(ctx context.Context, id int64) (*JobExecution, error)
+	ImportJobs(specs []JobSpec, prune bool) ([]JobImportResult, error)
+	ListNotifyTemplates() ([]NotifyTemplate, error)
+	// SaveNotifyTemplate creates or replaces a template after checking
+	// that it parses
+	SaveNotifyTemplate(t NotifyTemplate) error
+	// TestNotifyTemplate renders a saved template against sample job
+	// values overridden by data
+	TestNotifyTemplate(name string, data map[string]any) (string, error)
 	GetAgentLogs(agentName string, limit int) ([]AgentLog, error)
 	CreateAgentLog(agentName, direction, messageType string, content, errMsg *string, durationMs *int) error
 	// OAuth methods
 	GetOAuthServers() []OAuthServerInfo
 	GetOAuthStatus(serverName string) (map[string]interface{}, error)
 	StartOAuthLogin(serverName string) (*DeviceCodeInfo, error)
-	PollOAuthToken(serverName string, deviceCode string, interval int) error
+	PollOAuthToken(serverName string, deviceCode string, interval int, expiresIn int) error
 	DeleteOAuthToken(serverName string) error
+	SetOAuthToken(serverName string, token OAuthTokenImport) (*OAuthTokenResult, error)
+	RefreshOAuthToken(serverName string) (*OAuthTokenResult, error)
+	// ClockOffset measures the local clock against a reference clock
+	ClockOffset() (*ClockOffset, error)
+	// CheckBackends verifies that each enabled provider's external backend
+	// is reachable with the configured credentials
+	CheckBackends(ctx context.Context) []DoctorCheck
+}
+
+// ClockOffset is how far a reference clock was ahead of the local one: a
+// positive Offset means the local clock is slow
+type ClockOffset struct {
+	Source     string        `json:"source"` // "master" or "ntp <server>"
+	Offset     time.Duration `json:"offset"`
+	MeasuredAt time.Time     `json:"measured_at"`
+}
+
+// ToolCallError is a JSON-RPC error from calling a tool, such as an unknown
+// tool (-32601) or invalid arguments (-32602). Failures inside the tool come
+// back as a result with IsError set instead.
+type ToolCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"error"`
+}
+
+func (e *ToolCallError) Error() string {
+	return e.Message
+}
+
+// ToolCallResult is the result of a tool call
+type ToolCallResult struct {
+	Content []ToolContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// ToolContent is one content block of a tool result: text, or base64 image
+// data with its MIME type
+type ToolContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
 }
 
 // OAuthServerInfo represents an MCP server with OAuth configuration
@@ -175,6 +360,23 @@ type DeviceCodeInfo struct {
 	DeviceCode      string `json:"device_code"`
 }
 
+// OAuthTokenImport is an existing token supplied directly instead of
+// obtaining one through the device flow
+type OAuthTokenImport struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// OAuthTokenResult reports the outcome of storing a server's token. Warning is
+// set when the server could not be started with it.
+type OAuthTokenResult struct {
+	Status    string     `json:"status"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Warning   string     `json:"warning,omitempty"`
+}
+
 // AgentLog represents an agent communication log entry
 type AgentLog struct {
 	ID          int64     `json:"id"`
@@ -187,6 +389,16 @@ type AgentLog struct {
 	Timestamp   time.Time `json:"timestamp"`
 }
 
+// ConfigInfo is the configuration the daemon is running with
+type ConfigInfo struct {
+	// Path is the config file the daemon read (or would have read)
+	Path string `json:"path"`
+	// EnvOverrides lists the environment variables overriding file values
+	EnvOverrides []string `json:"env_overrides,omitempty"`
+	// Config is the effective configuration, with secrets masked
+	Config config.Config `json:"config"`
+}
+
 // Server is the Unix socket HTTP API server
 type Server struct {
 	socketPath       string
@@ -207,6 +419,7 @@ type Server struct {
 	slaveManager     *slave.Manager
 	pairLimiter      *pairing.RateLimiter // rate limiter for pairing attempts
 	questionsService *emergent.QuestionsService
+	config           config.Config // effective configuration, for GET /config
 }
 
 // buildProviderStore creates the ProviderStore backed by Emergent.
@@ -283,7 +496,7 @@ func NewServer(statusProvider StatusProvider, database *db.DB, cfg config.Config
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	socketPath := filepath.Join(home, ".diane", "diane.sock")
+	socketPath := filepath.Join(home, config.DirName(), "diane.sock")
 
 	// Remove existing socket if it exists
 	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
@@ -335,7 +548,7 @@ func NewServer(statusProvider StatusProvider, database *db.DB, cfg config.Config
 	providerStore := buildProviderStore()
 	if providerStore != nil {
 		// Initialize models registry
-		modelsRegistry := models.NewRegistry(filepath.Join(home, ".diane"))
+		modelsRegistry := models.NewRegistry(filepath.Join(home, config.DirName()))
 		// Load registry in background (don't block startup)
 		go func() {
 			if err := modelsRegistry.Load(); err != nil {
@@ -345,6 +558,9 @@ func NewServer(statusProvider StatusProvider, database *db.DB, cfg config.Config
 			}
 		}()
 		providersAPI = NewProvidersAPI(database, providerStore, modelsRegistry)
+		if acpManager != nil {
+			acpManager.SetUsageRecorder(providersAPI.RecordAgentUsage)
+		}
 	}
 
 	// Initialize MCP Servers API
@@ -391,6 +607,7 @@ func NewServer(statusProvider StatusProvider, database *db.DB, cfg config.Config
 		slaveManager:     slaveManager,
 		pairLimiter:      pairing.NewRateLimiter(),
 		questionsService: questionsService,
+		config:           cfg,
 	}, nil
 }
 
@@ -408,6 +625,7 @@ func slaveReadOnlyMiddleware(next http.Handler) http.Handler {
 		"/health",
 		"/doctor",
 		"/status",
+		"/daemon/restart",
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -437,7 +655,8 @@ func slaveReadOnlyMiddleware(next http.Handler) http.Handler {
 // readOnlyMiddleware wraps a handler to reject non-GET/HEAD requests with 405.
 // This is used on the TCP HTTP listener when no API key is configured,
 // enforcing read-only access from remote clients.
-// The /pair endpoint is exempt to allow pairing without auth.
+// The /pair endpoint is exempt to allow pairing without auth. The /debug/
+// endpoints describe live MCP sessions and are refused outright.
 func readOnlyMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Allow pairing endpoints without auth
@@ -445,6 +664,11 @@ func readOnlyMiddleware(next http.Handler) http.Handler {
 			next.ServeHTTP(w, r)
 			return
 		}
+		if strings.HasPrefix(r.URL.Path, "/debug/") {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="diane"`)
+			http.Error(w, "Debug endpoints require an API key (set http.api_key) or the local socket", http.StatusUnauthorized)
+			return
+		}
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
 			w.Header().Set("Allow", "GET, HEAD")
 			http.Error(w, "Method not allowed (read-only listener)", http.StatusMethodNotAllowed)
@@ -507,17 +731,27 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/health", s.handleHealth)
 	mux.HandleFunc("/doctor", s.handleDoctor)
 	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/config", s.handleConfig)
 	mux.HandleFunc("/tools", s.handleTools)
+	mux.HandleFunc("/tools/call", s.handleToolCall)
+	mux.HandleFunc("/tools/customize", s.handleToolCustomize)
 	mux.HandleFunc("/prompts", s.handlePrompts)
 	mux.HandleFunc("/prompts/get", s.handlePromptGet)
 	mux.HandleFunc("/resources", s.handleResources)
 	mux.HandleFunc("/resources/read", s.handleResourceRead)
 	mux.HandleFunc("/mcp-servers", s.handleMCPServers)
+	mux.HandleFunc("/mcp-servers/restart", s.handleMCPServersRestart)
 	mux.HandleFunc("/mcp-servers/", s.handleMCPServerAction)
+	mux.HandleFunc("/debug/sse-clients", s.handleSSEClients)
 	mux.HandleFunc("/reload", s.handleReload)
+	mux.HandleFunc("/daemon/restart", s.handleDaemonRestart)
 	mux.HandleFunc("/jobs", s.handleJobs)
 	mux.HandleFunc("/jobs/logs", s.handleJobLogs)
+	mux.HandleFunc("/jobs/import", s.handleJobImport)
+	mux.HandleFunc("/jobs/executions/", s.handleJobExecutionAction)
 	mux.HandleFunc("/jobs/", s.handleJobAction)
+	mux.HandleFunc("/notify/templates", s.handleNotifyTemplates)
+	mux.HandleFunc("/notify/templates/test", s.handleNotifyTemplateTest)
 	mux.HandleFunc("/agents", s.handleAgents)
 	mux.HandleFunc("/agents/logs", s.handleAgentLogs)
 	mux.HandleFunc("/agents/", s.handleAgentAction)
@@ -687,9 +921,11 @@ func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	// 3. MCP HTTP server (port 8765)
+	// 3. MCP HTTP server (port 8765, shifted by the profile's port offset)
+	mcpPort := config.MCPHTTPPort()
+	mcpBase := fmt.Sprintf("http://localhost:%d", mcpPort)
 	httpClient := &http.Client{Timeout: 3 * time.Second}
-	resp, err := httpClient.Get("http://localhost:8765/health")
+	resp, err := httpClient.Get(mcpBase + "/health")
 	if err != nil {
 		healthy = false
 		checks = append(checks, DoctorCheck{
@@ -703,7 +939,7 @@ func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
 			checks = append(checks, DoctorCheck{
 				Name:    "mcp_http",
 				Status:  "ok",
-				Message: "MCP HTTP server listening on :8765",
+				Message: fmt.Sprintf("MCP HTTP server listening on :%d", mcpPort),
 			})
 		} else {
 			healthy = false
@@ -716,7 +952,7 @@ func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 4. MCP SSE endpoint
-	sseReq, _ := http.NewRequest(http.MethodGet, "http://localhost:8765/mcp/sse", nil)
+	sseReq, _ := http.NewRequest(http.MethodGet, mcpBase+"/mcp/sse", nil)
 	sseCtx, sseCancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer sseCancel()
 	sseReq = sseReq.WithContext(sseCtx)
@@ -750,7 +986,7 @@ func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
 
 	// 5. MCP Streamable endpoint
 	initBody := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"diane-doctor","version":"1.0"}}}`
-	streamResp, err := httpClient.Post("http://localhost:8765/mcp", "application/json", strings.NewReader(initBody))
+	streamResp, err := httpClient.Post(mcpBase+"/mcp", "application/json", strings.NewReader(initBody))
 	if err != nil {
 		healthy = false
 		checks = append(checks, DoctorCheck{
@@ -804,7 +1040,7 @@ func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
 
 	// 7. Database
 	home, _ := os.UserHomeDir()
-	dbPath := filepath.Join(home, ".diane", "cron.db")
+	dbPath := filepath.Join(home, config.DirName(), "cron.db")
 	if info, err := os.Stat(dbPath); err != nil {
 		healthy = false
 		checks = append(checks, DoctorCheck{
@@ -820,8 +1056,19 @@ func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	// 8. PID file
-	pidPath := filepath.Join(home, ".diane", "mcp.pid")
+	// 8. Clock skew, which breaks cron schedules and certificate validity
+	checks = append(checks, clockCheck(s.statusProvider))
+
+	// 9. Provider backends: reachable, and accepting our credentials
+	for _, check := range s.statusProvider.CheckBackends(r.Context()) {
+		if check.Status == "fail" {
+			healthy = false
+		}
+		checks = append(checks, check)
+	}
+
+	// 10. PID file
+	pidPath := filepath.Join(home, config.DirName(), "mcp.pid")
 	if _, err := os.Stat(pidPath); err != nil {
 		checks = append(checks, DoctorCheck{
 			Name:    "pid_file",
@@ -845,6 +1092,39 @@ func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(report)
 }
 
+// clockSkewThreshold is how far the clock may be off before doctor warns
+const clockSkewThreshold = 30 * time.Second
+
+// clockCheck compares the local clock against the master's (for slaves) or
+// an NTP server's. Skew is a warning, as is not being able to measure it.
+func clockCheck(sp StatusProvider) DoctorCheck {
+	check := DoctorCheck{Name: "clock"}
+	offset, err := sp.ClockOffset()
+	if err != nil {
+		check.Status = "warn"
+		check.Message = fmt.Sprintf("Could not check the clock: %v", err)
+		return check
+	}
+
+	skew := offset.Offset
+	if skew < 0 {
+		skew = -skew
+	}
+	direction := "behind"
+	if offset.Offset < 0 {
+		direction = "ahead of"
+	}
+	if skew > clockSkewThreshold {
+		check.Status = "warn"
+		check.Message = fmt.Sprintf("Clock is %s %s %s; cron jobs may fire at the wrong time and certificates may be rejected",
+			skew.Round(time.Second), direction, offset.Source)
+		return check
+	}
+	check.Status = "ok"
+	check.Message = fmt.Sprintf("Clock within %s of %s", skew.Round(time.Millisecond), offset.Source)
+	return check
+}
+
 // handleStatus returns the full status
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -858,6 +1138,23 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(status)
 }
 
+// handleConfig returns the daemon's effective configuration
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	info := ConfigInfo{
+		Path:         config.Path(),
+		EnvOverrides: config.EnvOverrides(),
+		Config:       s.config.Masked(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
 // handleTools returns the list of all available tools
 func (s *Server) handleTools(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -871,6 +1168,78 @@ func (s *Server) handleTools(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(tools)
 }
 
+// handleToolCall calls a tool through the daemon's tool dispatch, filtered
+// by a context if one is given
+func (s *Server) handleToolCall(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+		Context   string                 `json:"context"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	result, err := s.statusProvider.CallToolInContext(req.Name, req.Arguments, req.Context)
+	if err != nil {
+		status := http.StatusInternalServerError
+		callErr, ok := err.(*ToolCallError)
+		if !ok {
+			callErr = &ToolCallError{Message: err.Error()}
+		}
+		switch callErr.Code {
+		case -32601:
+			status = http.StatusNotFound
+		case -32602:
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(callErr)
+		return
+	}
+	w.Write(result)
+}
+
+// handleToolCustomize sets or removes a tool's customization
+// POST /tools/customize with {"tool": ..., "description": ..., "hidden_args": [...]}
+func (s *Server) handleToolCustomize(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	var c ToolCustomization
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil || c.Tool == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "tool is required"})
+		return
+	}
+
+	if err := s.statusProvider.SetToolCustomization(c); err != nil {
+		code := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			code = http.StatusNotFound
+		}
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
 // handlePrompts returns the list of all available prompts
 func (s *Server) handlePrompts(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -960,7 +1329,7 @@ func (s *Server) handleMCPServers(w http.ResponseWriter, r *http.Request) {
 
 // handleMCPServerAction handles actions on specific MCP servers
 func (s *Server) handleMCPServerAction(w http.ResponseWriter, r *http.Request) {
-	// Parse the path: /mcp-servers/{name}/restart
+	// Parse the path: /mcp-servers/{name}/{restart|enable|disable}
 	path := strings.TrimPrefix(r.URL.Path, "/mcp-servers/")
 	parts := strings.Split(path, "/")
 
@@ -980,17 +1349,68 @@ func (s *Server) handleMCPServerAction(w http.ResponseWriter, r *http.Request) {
 		}
 		if err := s.statusProvider.RestartMCPServer(serverName); err != nil {
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
+			// Throttled restarts say when to retry (see mcpproxy.RestartThrottledError)
+			var throttled interface{ RetryAfter() time.Duration }
+			if errors.As(err, &throttled) {
+				w.Header().Set("Retry-After", strconv.Itoa(int((throttled.RetryAfter()+time.Second-1)/time.Second)))
+				w.WriteHeader(http.StatusTooManyRequests)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
 			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{"status": "restarted", "server": serverName})
+	case "enable", "disable":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		enabled := action == "enable"
+		if err := s.statusProvider.SetMCPServerEnabled(serverName, enabled); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "server": serverName, "enabled": enabled})
 	default:
 		http.Error(w, "Unknown action", http.StatusBadRequest)
 	}
 }
 
+// handleMCPServersRestart restarts all enabled MCP servers, or only the failed ones.
+// POST /mcp-servers/restart with optional {"only_failed": true}
+func (s *Server) handleMCPServersRestart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		OnlyFailed bool `json:"only_failed"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	results, err := s.statusProvider.RestartMCPServers(body.OnlyFailed)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
 // handleReload reloads the MCP configuration
 func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -998,15 +1418,37 @@ func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.statusProvider.ReloadConfig(); err != nil {
+	result, err := s.statusProvider.ReloadConfig()
+	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
+	result.Status = "reloaded"
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "reloaded"})
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleDaemonRestart asks the daemon to shut down gracefully and re-exec itself.
+// The response is sent before shutdown starts. On the TCP listener this is only
+// reachable with the API key: without one the listener is read-only.
+func (s *Server) handleDaemonRestart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := s.statusProvider.RestartDaemon(); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": "restarting"})
 }
 
 // handleJobs returns the list of scheduled jobs
@@ -1028,6 +1470,91 @@ func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(jobs)
 }
 
+// handleSSEClients lists the clients connected to the SSE transport, for
+// debugging notification delivery
+func (s *Server) handleSSEClients(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clients, err := s.statusProvider.GetSSEClients()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clients)
+}
+
+// handleNotifyTemplates lists notify templates (GET) or saves one (POST
+// with {"name": ..., "body": ...})
+func (s *Server) handleNotifyTemplates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		templates, err := s.statusProvider.ListNotifyTemplates()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(templates)
+	case http.MethodPost:
+		var t NotifyTemplate
+		if err := json.NewDecoder(r.Body).Decode(&t); err != nil || t.Name == "" || t.Body == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "name and body are required"})
+			return
+		}
+		if err := s.statusProvider.SaveNotifyTemplate(t); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+	}
+}
+
+// handleNotifyTemplateTest renders a saved notify template
+// POST /notify/templates/test with {"name": ..., "data": {...}}
+func (s *Server) handleNotifyTemplateTest(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	var req struct {
+		Name string         `json:"name"`
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "name is required"})
+		return
+	}
+
+	message, err := s.statusProvider.TestNotifyTemplate(req.Name, req.Data)
+	if err != nil {
+		code := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			code = http.StatusNotFound
+		}
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
 // handleJobLogs returns job execution logs
 func (s *Server) handleJobLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -1056,9 +1583,163 @@ func (s *Server) handleJobLogs(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(logs)
 }
 
+// handleJobImport creates, updates and optionally deletes jobs to match the
+// posted definitions. Every definition is validated first; if any is invalid
+// nothing is applied.
+func (s *Server) handleJobImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	var body struct {
+		Jobs  []JobSpec `json:"jobs"`
+		Prune bool      `json:"prune"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if results, ok := validateJobSpecs(body.Jobs); !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":   "invalid job definitions, nothing was applied",
+			"results": results,
+		})
+		return
+	}
+
+	results, err := s.statusProvider.ImportJobs(body.Jobs, body.Prune)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+}
+
+func validOutputFormat(format string) bool {
+	_, err := cron.ParseOutputFormat(format)
+	return err == nil
+}
+
+func validConcurrency(policy string) bool {
+	_, err := cron.ParseConcurrency(policy)
+	return err == nil
+}
+
+// validateJobSpecs checks every job definition, returning a result for each
+// and whether all of them are valid
+func validateJobSpecs(specs []JobSpec) ([]JobImportResult, bool) {
+	results := make([]JobImportResult, len(specs))
+	seen := make(map[string]bool, len(specs))
+	valid := true
+	for i, spec := range specs {
+		results[i] = JobImportResult{Name: spec.Name, Action: "valid"}
+		var problem string
+		switch {
+		case spec.Name == "":
+			problem = "name is required"
+		case seen[spec.Name]:
+			problem = "duplicate job name"
+		case spec.ActionType != "" && spec.ActionType != "shell" && spec.ActionType != "agent":
+			problem = fmt.Sprintf("unknown action_type %q (expected shell or agent)", spec.ActionType)
+		case spec.ActionType == "agent" && spec.AgentName == "":
+			problem = "agent_name is required for agent jobs"
+		case spec.ActionType != "agent" && spec.Command == "":
+			problem = "command is required"
+		case spec.MaxOutputBytes < 0:
+			problem = "max_output_bytes must not be negative"
+		case spec.TimeoutSeconds < 0:
+			problem = "timeout_seconds must not be negative"
+		case spec.Concurrency != "" && !validConcurrency(spec.Concurrency):
+			problem = fmt.Sprintf("unknown concurrency %q (expected skip, queue or allow)", spec.Concurrency)
+		case spec.DependsOn != "" && spec.ActionType == "agent":
+			problem = "depends_on is only supported for shell jobs"
+		case !validOutputFormat(spec.OutputFormat):
+			problem = fmt.Sprintf("unknown output_format %q (expected text or json)", spec.OutputFormat)
+		default:
+			if _, err := cron.Parse(spec.Schedule); err != nil {
+				problem = "invalid schedule: " + err.Error()
+			}
+			if problem == "" {
+				if err := cron.ValidateRunEnvironment(spec.Env, spec.Workdir); err != nil {
+					problem = err.Error()
+				}
+			}
+		}
+		seen[spec.Name] = true
+		if problem != "" {
+			results[i].Action = "invalid"
+			results[i].Error = problem
+			valid = false
+		}
+	}
+
+	// Jobs may depend on each other within the file, but not in a cycle
+	parents := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		parents[spec.Name] = spec.DependsOn
+	}
+	for i, spec := range specs {
+		if results[i].Action == "invalid" || spec.DependsOn == "" {
+			continue
+		}
+		chain := make(map[string]string)
+		for cur := spec.Name; cur != ""; {
+			if _, done := chain[cur]; done {
+				break
+			}
+			next := parents[cur]
+			if _, inFile := parents[next]; !inFile {
+				next = "" // runs after a job outside the file
+			}
+			chain[cur] = next
+			cur = next
+		}
+		if err := cron.CheckDependencies(chain); err != nil {
+			results[i].Action = "invalid"
+			results[i].Error = err.Error()
+			valid = false
+		}
+	}
+	return results, valid
+}
+
+// handleJobExecutionAction handles actions on a specific execution:
+// POST /jobs/executions/{id}/retry
+func (s *Server) handleJobExecutionAction(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/jobs/executions/"), "/")
+	if len(parts) != 2 || parts[1] != "retry" {
+		http.Error(w, "Invalid path", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid execution ID", http.StatusBadRequest)
+		return
+	}
+
+	execution, err := s.statusProvider.RetryJobExecution(r.Context(), id)
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(execution)
+}
+
 // handleJobAction handles actions on specific jobs
 func (s *Server) handleJobAction(w http.ResponseWriter, r *http.Request) {
-	// Parse the path: /jobs/{name}/toggle
+	// Parse the path: /jobs/{name}/{toggle|pause|resume}
 	path := strings.TrimPrefix(r.URL.Path, "/jobs/")
 	parts := strings.Split(path, "/")
 
@@ -1094,6 +1775,44 @@ func (s *Server) handleJobAction(w http.ResponseWriter, r *http.Request) {
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "job": jobName, "enabled": body.Enabled})
+	case "pause":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var body struct {
+			Until *time.Time `json:"until,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.statusProvider.PauseJob(jobName, body.Until); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "job": jobName, "paused_until": body.Until})
+	case "resume":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := s.statusProvider.ResumeJob(jobName); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "job": jobName})
 	default:
 		http.Error(w, "Unknown action", http.StatusBadRequest)
 	}
@@ -1102,7 +1821,7 @@ func (s *Server) handleJobAction(w http.ResponseWriter, r *http.Request) {
 // GetSocketPath returns the socket path for clients
 func GetSocketPath() string {
 	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".diane", "diane.sock")
+	return filepath.Join(home, config.DirName(), "diane.sock")
 }
 
 // CheckProcessRunning checks if a process with the given PID is running
@@ -1429,7 +2148,8 @@ func (s *Server) handleAgentAction(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var body struct {
-			Prompt string `json:"prompt"`
+			Prompt  string `json:"prompt"`
+			Timeout int    `json:"timeout,omitempty"` // Seconds; 0 = acp.DefaultRunTimeout
 		}
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
@@ -1443,12 +2163,25 @@ func (s *Server) handleAgentAction(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if body.Timeout < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "timeout must not be negative"})
+			return
+		}
+		timeout := acp.DefaultRunTimeout
+		if body.Timeout > 0 {
+			timeout = time.Duration(body.Timeout) * time.Second
+		}
+		// Derive from the request so the run also stops if the caller gives up
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
 		// Log the request
 		promptContent := body.Prompt
 		s.statusProvider.CreateAgentLog(agentName, "request", "run", &promptContent, nil, nil)
 
 		startTime := time.Now()
-		run, err := s.acpManager.RunAgent(agentName, body.Prompt)
+		run, err := s.acpManager.RunAgentContext(ctx, agentName, body.Prompt)
 		durationMs := int(time.Since(startTime).Milliseconds())
 
 		if err != nil {
@@ -1927,6 +2660,7 @@ func (s *Server) handleAuthAction(w http.ResponseWriter, r *http.Request) {
 		var body struct {
 			DeviceCode string `json:"device_code"`
 			Interval   int    `json:"interval"`
+			ExpiresIn  int    `json:"expires_in"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
@@ -1934,7 +2668,7 @@ func (s *Server) handleAuthAction(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := s.statusProvider.PollOAuthToken(serverName, body.DeviceCode, body.Interval); err != nil {
+		if err := s.statusProvider.PollOAuthToken(serverName, body.DeviceCode, body.Interval, body.ExpiresIn); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 			return
@@ -1942,6 +2676,48 @@ func (s *Server) handleAuthAction(w http.ResponseWriter, r *http.Request) {
 
 		json.NewEncoder(w).Encode(map[string]string{"status": "authenticated"})
 
+	case "token":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+			return
+		}
+
+		var token OAuthTokenImport
+		if err := json.NewDecoder(r.Body).Decode(&token); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+			return
+		}
+		if token.AccessToken == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "access_token is required"})
+			return
+		}
+
+		result, err := s.statusProvider.SetOAuthToken(serverName, token)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(result)
+
+	case "refresh":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+			return
+		}
+
+		result, err := s.statusProvider.RefreshOAuthToken(serverName)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(result)
+
 	case "logout":
 		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
 			w.WriteHeader(http.StatusMethodNotAllowed)
@@ -2062,7 +2838,7 @@ func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
 //	POST   /agents/{name}/sessions             → StartSession
 //	GET    /agents/{name}/sessions              → ListSessions for agent
 //	GET    /agents/{name}/sessions/{id}         → GetSessionInfo
-//	POST   /agents/{name}/sessions/{id}/prompt  → PromptSession
+//	POST   /agents/{name}/sessions/{id}/prompt  → PromptSession (?stream=true for NDJSON chunks)
 //	POST   /agents/{name}/sessions/{id}/config  → SetSessionConfig
 //	DELETE /agents/{name}/sessions/{id}         → CloseSession
 //	GET    /agents/{name}/sessions/{id}/messages → GetSessionMessages
@@ -2163,6 +2939,11 @@ func (s *Server) handleAgentSessions(w http.ResponseWriter, r *http.Request, age
 			return
 		}
 
+		if r.URL.Query().Get("stream") == "true" {
+			s.streamSessionPrompt(w, sessionID, body.Prompt)
+			return
+		}
+
 		run, err := s.acpManager.PromptSession(sessionID, body.Prompt)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -2215,6 +2996,35 @@ func (s *Server) handleAgentSessions(w http.ResponseWriter, r *http.Request, age
 	}
 }
 
+// SessionStreamEvent is one line of a streamed session prompt response:
+// a chunk of agent text, then finally the completed run or an error
+type SessionStreamEvent struct {
+	Text  string   `json:"text,omitempty"`
+	Run   *acp.Run `json:"run,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// streamSessionPrompt prompts a session and writes each chunk of agent text
+// as a newline-delimited JSON event as it arrives, ending with the run
+func (s *Server) streamSessionPrompt(w http.ResponseWriter, sessionID, prompt string) {
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	run, err := s.acpManager.PromptSessionStream(sessionID, prompt, func(text string) {
+		enc.Encode(SessionStreamEvent{Text: text})
+		if flusher != nil {
+			flusher.Flush()
+		}
+	})
+	if err != nil {
+		enc.Encode(SessionStreamEvent{Error: err.Error()})
+		return
+	}
+	enc.Encode(SessionStreamEvent{Run: run})
+}
+
 // handleQuestions handles GET /questions
 // Returns the list of agent questions, filtered by ?status= (default: pending).
 func (s *Server) handleQuestions(w http.ResponseWriter, r *http.Request) {
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
@@ -32,8 +33,15 @@ func NewClient() *Client {
 // The httpClient should have a Transport that routes requests appropriately.
 // All client methods use "http://unix/..." URLs, so the Transport must handle that.
 func NewClientWithHTTPClient(httpClient *http.Client) *Client {
+	hc := *httpClient
+	inner := hc.Transport
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	hc.Transport = &unreachableTransport{inner: inner}
+
 	return &Client{
-		httpClient: httpClient,
+		httpClient: &hc,
 		socketPath: "",
 	}
 }
@@ -45,9 +53,11 @@ func NewClientWithTimeout(timeout time.Duration) *Client {
 	return &Client{
 		socketPath: socketPath,
 		httpClient: &http.Client{
-			Transport: &http.Transport{
-				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-					return net.Dial("unix", socketPath)
+			Transport: &unreachableTransport{
+				inner: &http.Transport{
+					DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+						return net.Dial("unix", socketPath)
+					},
 				},
 			},
 			Timeout: timeout,
@@ -55,6 +65,14 @@ func NewClientWithTimeout(timeout time.Duration) *Client {
 	}
 }
 
+// WithTimeout returns a copy of the client that uses a different request
+// timeout, e.g. for long-running calls
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	hc := *c.httpClient
+	hc.Timeout = timeout
+	return &Client{httpClient: &hc, socketPath: c.socketPath}
+}
+
 // Health checks if the API server is responding
 func (c *Client) Health() error {
 	resp, err := c.httpClient.Get("http://unix/health")
@@ -64,7 +82,7 @@ func (c *Client) Health() error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unhealthy: status %d", resp.StatusCode)
+		return statusErrorf(resp.StatusCode, "unhealthy: status %d", resp.StatusCode)
 	}
 	return nil
 }
@@ -99,7 +117,7 @@ func (c *Client) GetUsage(providerID int64, service string, from, to time.Time,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get usage failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "get usage failed: status %d", resp.StatusCode)
 	}
 
 	var usage UsageResponse
@@ -129,7 +147,7 @@ func (c *Client) GetUsageSummary(from, to time.Time) (*UsageSummaryResponse, err
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get usage summary failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "get usage summary failed: status %d", resp.StatusCode)
 	}
 
 	var summary UsageSummaryResponse
@@ -151,7 +169,7 @@ func (c *Client) GetHosts() ([]HostInfo, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get hosts failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "get hosts failed: status %d", resp.StatusCode)
 	}
 
 	var hosts []HostInfo
@@ -173,7 +191,7 @@ func (c *Client) GetSlaves() ([]SlaveInfo, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get slaves: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "failed to get slaves: status %d", resp.StatusCode)
 	}
 
 	var slaves []SlaveInfo
@@ -184,6 +202,55 @@ func (c *Client) GetSlaves() ([]SlaveInfo, error) {
 	return slaves, nil
 }
 
+// GetSlaveLogs fetches a connected slave's recent log entries and status.
+// level filters to entries at or above it (debug, info, warn, error).
+func (c *Client) GetSlaveLogs(hostname string, limit int, level string) (*SlaveLogs, error) {
+	url := fmt.Sprintf("http://unix/slaves/logs/%s?limit=%d", hostname, limit)
+	if level != "" {
+		url += fmt.Sprintf("&level=%s", level)
+	}
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slave logs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, statusErrorf(resp.StatusCode, "failed to get slave logs: %s", bytes.TrimSpace(body))
+	}
+
+	var logs SlaveLogs
+	if err := json.NewDecoder(resp.Body).Decode(&logs); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &logs, nil
+}
+
+// TestSlave pings a registered slave over its existing connection and
+// reports its status, latency, tool count and certificate expiry
+func (c *Client) TestSlave(hostname string) (*SlaveTestResult, error) {
+	resp, err := c.httpClient.Get("http://unix/slaves/test/" + hostname)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test slave: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, statusErrorf(resp.StatusCode, "failed to test slave: %s", bytes.TrimSpace(body))
+	}
+
+	var result SlaveTestResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
 // GetPendingPairingRequests retrieves all pending pairing requests
 func (c *Client) GetPendingPairingRequests() ([]PairingRequest, error) {
 	resp, err := c.httpClient.Get("http://unix/slaves/pending")
@@ -193,7 +260,7 @@ func (c *Client) GetPendingPairingRequests() ([]PairingRequest, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get pending requests: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "failed to get pending requests: status %d", resp.StatusCode)
 	}
 
 	var requests []PairingRequest
@@ -232,9 +299,9 @@ func (c *Client) ApprovePairingRequest(hostname, code string) (*ApprovePairingRe
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
 		if errResp.Error != "" {
-			return nil, fmt.Errorf("approval failed: %s", errResp.Error)
+			return nil, statusErrorf(resp.StatusCode, "approval failed: %s", errResp.Error)
 		}
-		return nil, fmt.Errorf("approval failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "approval failed: status %d", resp.StatusCode)
 	}
 
 	var result ApprovePairingResponse
@@ -265,9 +332,9 @@ func (c *Client) DenyPairingRequest(hostname, code string) error {
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
 		if errResp.Error != "" {
-			return fmt.Errorf("denial failed: %s", errResp.Error)
+			return statusErrorf(resp.StatusCode, "denial failed: %s", errResp.Error)
 		}
-		return fmt.Errorf("denial failed: status %d", resp.StatusCode)
+		return statusErrorf(resp.StatusCode, "denial failed: status %d", resp.StatusCode)
 	}
 
 	return nil
@@ -293,9 +360,9 @@ func (c *Client) RevokeSlave(hostname string) error {
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
 		if errResp.Error != "" {
-			return fmt.Errorf("revocation failed: %s", errResp.Error)
+			return statusErrorf(resp.StatusCode, "revocation failed: %s", errResp.Error)
 		}
-		return fmt.Errorf("revocation failed: status %d", resp.StatusCode)
+		return statusErrorf(resp.StatusCode, "revocation failed: status %d", resp.StatusCode)
 	}
 
 	return nil
@@ -318,7 +385,7 @@ func (c *Client) GetRevokedSlaves() ([]RevokedCredentialInfo, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get revoked slaves: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "failed to get revoked slaves: status %d", resp.StatusCode)
 	}
 
 	var revoked []RevokedCredentialInfo
@@ -338,7 +405,7 @@ func (c *Client) GetStatus() (*Status, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "status request failed: %d", resp.StatusCode)
 	}
 
 	var status Status
@@ -349,6 +416,26 @@ func (c *Client) GetStatus() (*Status, error) {
 	return &status, nil
 }
 
+// GetConfig returns the configuration the daemon is running with
+func (c *Client) GetConfig() (*ConfigInfo, error) {
+	resp, err := c.httpClient.Get("http://unix/config")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusErrorf(resp.StatusCode, "config request failed: %d", resp.StatusCode)
+	}
+
+	var info ConfigInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	return &info, nil
+}
+
 // GetMCPServers returns the list of MCP servers
 func (c *Client) GetMCPServers() ([]MCPServerStatus, error) {
 	resp, err := c.httpClient.Get("http://unix/mcp-servers")
@@ -358,7 +445,7 @@ func (c *Client) GetMCPServers() ([]MCPServerStatus, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("MCP servers request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "MCP servers request failed: %d", resp.StatusCode)
 	}
 
 	var servers []MCPServerStatus
@@ -383,17 +470,80 @@ func (c *Client) RestartMCPServer(name string) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("restart failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "restart failed: %s", errResp.Error)
+	}
+
+	return nil
+}
+
+// EnableMCPServer brings a runtime-disabled MCP server back into rotation
+func (c *Client) EnableMCPServer(name string) error {
+	return c.setMCPServerEnabled(name, "enable")
+}
+
+// DisableMCPServer takes an MCP server out of rotation without changing its config
+func (c *Client) DisableMCPServer(name string) error {
+	return c.setMCPServerEnabled(name, "disable")
+}
+
+func (c *Client) setMCPServerEnabled(name, action string) error {
+	url := fmt.Sprintf("http://unix/mcp-servers/%s/%s", name, action)
+	resp, err := c.httpClient.Post(url, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to %s server: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error != "" {
+			return statusErrorf(resp.StatusCode, "%s failed: %s", action, errResp.Error)
+		}
+		return statusErrorf(resp.StatusCode, "%s failed: status %d", action, resp.StatusCode)
 	}
 
 	return nil
 }
 
-// ReloadConfig reloads the MCP configuration
-func (c *Client) ReloadConfig() error {
+// RestartMCPServers restarts all enabled MCP servers, or only those currently
+// disconnected or in an error state when onlyFailed is true
+func (c *Client) RestartMCPServers(onlyFailed bool) ([]MCPServerRestartResult, error) {
+	body, _ := json.Marshal(map[string]bool{"only_failed": onlyFailed})
+
+	resp, err := c.httpClient.Post("http://unix/mcp-servers/restart", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restart servers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error != "" {
+			return nil, statusErrorf(resp.StatusCode, "restart failed: %s", errResp.Error)
+		}
+		return nil, statusErrorf(resp.StatusCode, "restart failed: status %d", resp.StatusCode)
+	}
+
+	var results []MCPServerRestartResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode restart results: %w", err)
+	}
+
+	return results, nil
+}
+
+// ReloadConfig reloads the MCP configuration and reports which servers
+// were started, stopped, restarted or reconfigured
+func (c *Client) ReloadConfig() (*ReloadResult, error) {
 	resp, err := c.httpClient.Post("http://unix/reload", "application/json", nil)
 	if err != nil {
-		return fmt.Errorf("failed to reload config: %w", err)
+		return nil, fmt.Errorf("failed to reload config: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -402,7 +552,32 @@ func (c *Client) ReloadConfig() error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("reload failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "reload failed: %s", errResp.Error)
+	}
+
+	var result ReloadResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// RestartDaemon asks the daemon to gracefully restart itself. It returns once
+// the restart has been accepted; the daemon is briefly unreachable afterwards.
+func (c *Client) RestartDaemon() error {
+	resp, err := c.httpClient.Post("http://unix/daemon/restart", "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to restart daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return statusErrorf(resp.StatusCode, "restart failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -430,7 +605,7 @@ func (c *Client) ListContexts() ([]ContextInfo, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("contexts request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "contexts request failed: %d", resp.StatusCode)
 	}
 
 	var contexts []ContextInfo
@@ -461,9 +636,9 @@ func (c *Client) CreateContext(name, description string) (*ContextResponse, erro
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
 		if errResp.Error != "" {
-			return nil, fmt.Errorf("create context failed: %s", errResp.Error)
+			return nil, statusErrorf(resp.StatusCode, "create context failed: %s", errResp.Error)
 		}
-		return nil, fmt.Errorf("create context failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "create context failed: status %d", resp.StatusCode)
 	}
 
 	var ctx ContextResponse
@@ -494,9 +669,9 @@ func (c *Client) DeleteContext(name string) error {
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
 		if errResp.Error != "" {
-			return fmt.Errorf("delete context failed: %s", errResp.Error)
+			return statusErrorf(resp.StatusCode, "delete context failed: %s", errResp.Error)
 		}
-		return fmt.Errorf("delete context failed: status %d", resp.StatusCode)
+		return statusErrorf(resp.StatusCode, "delete context failed: status %d", resp.StatusCode)
 	}
 
 	return nil
@@ -517,9 +692,9 @@ func (c *Client) SetDefaultContext(name string) error {
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
 		if errResp.Error != "" {
-			return fmt.Errorf("set default context failed: %s", errResp.Error)
+			return statusErrorf(resp.StatusCode, "set default context failed: %s", errResp.Error)
 		}
-		return fmt.Errorf("set default context failed: status %d", resp.StatusCode)
+		return statusErrorf(resp.StatusCode, "set default context failed: status %d", resp.StatusCode)
 	}
 
 	return nil
@@ -535,7 +710,7 @@ func (c *Client) GetContextDetail(name string) (*ContextDetailResponse, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get context detail failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "get context detail failed: status %d", resp.StatusCode)
 	}
 
 	var detail ContextDetailResponse
@@ -556,7 +731,7 @@ func (c *Client) SyncContextTools(name string) (int, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("sync context tools failed: status %d", resp.StatusCode)
+		return 0, statusErrorf(resp.StatusCode, "sync context tools failed: status %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -579,7 +754,7 @@ func (c *Client) GetAvailableServersForContext(name string) ([]AvailableServer,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get available servers failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "get available servers failed: status %d", resp.StatusCode)
 	}
 
 	var servers []AvailableServer
@@ -590,6 +765,69 @@ func (c *Client) GetAvailableServersForContext(name string) ([]AvailableServer,
 	return servers, nil
 }
 
+// GetContextServerInitOptions returns the init options a context sets for a server
+func (c *Client) GetContextServerInitOptions(contextName, serverName string) (map[string]any, error) {
+	url := fmt.Sprintf("http://unix/contexts/%s/servers/%s/init-options", contextName, serverName)
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get init options: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusErrorf(resp.StatusCode, "get init options failed: status %d", resp.StatusCode)
+	}
+
+	var options map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&options); err != nil {
+		return nil, fmt.Errorf("failed to decode init options: %w", err)
+	}
+
+	return options, nil
+}
+
+// SetContextServerInitOptions sets the init options a context passes to a
+// server's initialize call. Empty options clear them.
+func (c *Client) SetContextServerInitOptions(contextName, serverName string, options map[string]any) error {
+	url := fmt.Sprintf("http://unix/contexts/%s/servers/%s/init-options", contextName, serverName)
+	method := http.MethodPut
+	var body io.Reader
+	if len(options) == 0 {
+		method = http.MethodDelete
+	} else {
+		data, err := json.Marshal(options)
+		if err != nil {
+			return fmt.Errorf("failed to encode init options: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to set init options: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error != "" {
+			return statusErrorf(resp.StatusCode, "set init options failed: %s", errResp.Error)
+		}
+		return statusErrorf(resp.StatusCode, "set init options failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // --- Job Management Methods ---
 
 // ListJobs returns all scheduled jobs
@@ -601,7 +839,7 @@ func (c *Client) ListJobs() ([]Job, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("list jobs failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "list jobs failed: status %d", resp.StatusCode)
 	}
 
 	var jobs []Job
@@ -612,6 +850,90 @@ func (c *Client) ListJobs() ([]Job, error) {
 	return jobs, nil
 }
 
+// GetSSEClients lists the clients connected to the MCP HTTP server's SSE
+// transport
+func (c *Client) GetSSEClients() ([]SSEClient, error) {
+	resp, err := c.httpClient.Get("http://unix/debug/sse-clients")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSE clients: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusErrorf(resp.StatusCode, "list SSE clients failed: status %d", resp.StatusCode)
+	}
+
+	var clients []SSEClient
+	if err := json.NewDecoder(resp.Body).Decode(&clients); err != nil {
+		return nil, fmt.Errorf("failed to decode SSE clients: %w", err)
+	}
+
+	return clients, nil
+}
+
+// ListNotifyTemplates returns the saved notify templates
+func (c *Client) ListNotifyTemplates() ([]NotifyTemplate, error) {
+	resp, err := c.httpClient.Get("http://unix/notify/templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notify templates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, statusErrorf(resp.StatusCode, "list notify templates failed: %s", errResp.Error)
+	}
+
+	var templates []NotifyTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&templates); err != nil {
+		return nil, fmt.Errorf("failed to decode notify templates: %w", err)
+	}
+	return templates, nil
+}
+
+// SaveNotifyTemplate creates or replaces a notify template
+func (c *Client) SaveNotifyTemplate(t NotifyTemplate) error {
+	body, _ := json.Marshal(t)
+	resp, err := c.httpClient.Post("http://unix/notify/templates", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to save notify template: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return statusErrorf(resp.StatusCode, "save notify template failed: %s", errResp.Error)
+	}
+	return nil
+}
+
+// TestNotifyTemplate renders a saved notify template against sample job
+// values overridden by data, and returns the message
+func (c *Client) TestNotifyTemplate(name string, data map[string]any) (string, error) {
+	body, _ := json.Marshal(map[string]any{"name": name, "data": data})
+	resp, err := c.httpClient.Post("http://unix/notify/templates/test", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to test notify template: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK {
+		return "", statusErrorf(resp.StatusCode, "test notify template failed: %s", result.Error)
+	}
+	return result.Message, nil
+}
+
 // GetJobLogs returns execution logs for a job
 func (c *Client) GetJobLogs(name string, limit int) ([]JobExecution, error) {
 	url := fmt.Sprintf("http://unix/jobs/logs?limit=%d", limit)
@@ -626,7 +948,7 @@ func (c *Client) GetJobLogs(name string, limit int) ([]JobExecution, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get job logs failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "get job logs failed: status %d", resp.StatusCode)
 	}
 
 	var logs []JobExecution
@@ -649,12 +971,171 @@ func (c *Client) ToggleJob(name string, enabled bool) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("toggle job failed: status %d", resp.StatusCode)
+		return statusErrorf(resp.StatusCode, "toggle job failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// PauseJob stops a job firing until the given time, or until ResumeJob
+// when until is nil, without disabling it
+func (c *Client) PauseJob(name string, until *time.Time) error {
+	url := fmt.Sprintf("http://unix/jobs/%s/pause", name)
+	body, _ := json.Marshal(map[string]*time.Time{"until": until})
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to pause job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusErrorf(resp.StatusCode, "pause job failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ResumeJob lifts a job's pause
+func (c *Client) ResumeJob(name string) error {
+	url := fmt.Sprintf("http://unix/jobs/%s/resume", name)
+
+	resp, err := c.httpClient.Post(url, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to resume job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusErrorf(resp.StatusCode, "resume job failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// RetryJobExecution re-runs the job behind a failed execution and returns
+// the new execution. The daemon waits for the run to finish, so callers
+// should allow up to cron.RunTimeout.
+func (c *Client) RetryJobExecution(id int64) (*JobExecution, error) {
+	resp, err := c.httpClient.Post(fmt.Sprintf("http://unix/jobs/executions/%d/retry", id), "application/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry execution: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error != "" {
+			return nil, statusErrorf(resp.StatusCode, "retry failed: %s", errResp.Error)
+		}
+		return nil, statusErrorf(resp.StatusCode, "retry failed: status %d", resp.StatusCode)
+	}
+
+	var execution JobExecution
+	if err := json.NewDecoder(resp.Body).Decode(&execution); err != nil {
+		return nil, fmt.Errorf("failed to decode execution: %w", err)
+	}
+	return &execution, nil
+}
+
+// ImportJobs creates, updates and, with prune, deletes jobs to match specs.
+// When the daemon rejects invalid definitions the per-job results are
+// returned along with the error.
+func (c *Client) ImportJobs(specs []JobSpec, prune bool) ([]JobImportResult, error) {
+	body, _ := json.Marshal(map[string]interface{}{"jobs": specs, "prune": prune})
+
+	resp, err := c.httpClient.Post("http://unix/jobs/import", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to import jobs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Results []JobImportResult `json:"results"`
+		Error   string            `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode import results: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if result.Error != "" {
+			return result.Results, statusErrorf(resp.StatusCode, "import jobs failed: %s", result.Error)
+		}
+		return result.Results, statusErrorf(resp.StatusCode, "import jobs failed: status %d", resp.StatusCode)
+	}
+
+	return result.Results, nil
+}
+
+// GetTools returns every tool the daemon serves, with its input schema
+func (c *Client) GetTools() ([]ToolInfo, error) {
+	resp, err := c.httpClient.Get("http://unix/tools")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tools: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusErrorf(resp.StatusCode, "tools request failed: %d", resp.StatusCode)
 	}
 
+	var tools []ToolInfo
+	if err := json.NewDecoder(resp.Body).Decode(&tools); err != nil {
+		return nil, fmt.Errorf("failed to decode tools: %w", err)
+	}
+
+	return tools, nil
+}
+
+// SetToolCustomization sets or, given no description and no hidden
+// arguments, removes a tool's customization
+func (c *Client) SetToolCustomization(custom ToolCustomization) error {
+	body, _ := json.Marshal(custom)
+	resp, err := c.httpClient.Post("http://unix/tools/customize", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to customize tool: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return statusErrorf(resp.StatusCode, "customize tool failed: %s", errResp.Error)
+	}
 	return nil
 }
 
+// CallTool calls a tool by name through the daemon, as a client in
+// contextName would (empty for no context filtering)
+func (c *Client) CallTool(name string, args map[string]interface{}, contextName string) (*ToolCallResult, error) {
+	body, _ := json.Marshal(map[string]interface{}{"name": name, "arguments": args, "context": contextName})
+
+	resp, err := c.httpClient.Post("http://unix/tools/call", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tool: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var callErr ToolCallError
+		if err := json.NewDecoder(resp.Body).Decode(&callErr); err == nil && callErr.Message != "" {
+			return nil, statusErrorf(resp.StatusCode, "%s", callErr.Message)
+		}
+		return nil, statusErrorf(resp.StatusCode, "tool call failed: status %d", resp.StatusCode)
+	}
+
+	var result ToolCallResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode tool result: %w", err)
+	}
+	return &result, nil
+}
+
 // Doctor runs diagnostic checks and returns a report
 func (c *Client) Doctor() (*DoctorReport, error) {
 	resp, err := c.httpClient.Get("http://unix/doctor")
@@ -664,7 +1145,7 @@ func (c *Client) Doctor() (*DoctorReport, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("doctor request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "doctor request failed: %d", resp.StatusCode)
 	}
 
 	var report DoctorReport
@@ -684,7 +1165,7 @@ func (c *Client) ListAgents() ([]acp.AgentConfig, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("agents request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "agents request failed: %d", resp.StatusCode)
 	}
 
 	var agents []acp.AgentConfig
@@ -709,7 +1190,7 @@ func (c *Client) GetAgent(name string) (*acp.AgentConfig, error) {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("get agent failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "get agent failed: %s", errResp.Error)
 	}
 
 	var agent acp.AgentConfig
@@ -738,7 +1219,7 @@ func (c *Client) AddAgent(agent acp.AgentConfig) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("add agent failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "add agent failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -763,7 +1244,7 @@ func (c *Client) RemoveAgent(name string) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("remove agent failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "remove agent failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -784,7 +1265,7 @@ func (c *Client) ToggleAgent(name string, enabled bool) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("toggle agent failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "toggle agent failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -804,7 +1285,7 @@ func (c *Client) TestAgent(name string) (*acp.AgentTestResult, error) {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("test agent failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "test agent failed: %s", errResp.Error)
 	}
 
 	var result acp.AgentTestResult
@@ -815,10 +1296,15 @@ func (c *Client) TestAgent(name string) (*acp.AgentTestResult, error) {
 	return &result, nil
 }
 
-// RunAgent runs a prompt against an ACP agent
-func (c *Client) RunAgent(name, prompt, remoteAgentName string) (*acp.Run, error) {
+// RunAgent runs a prompt against an ACP agent. A timeout of 0 uses the
+// daemon's default run timeout; others are rounded up to whole seconds.
+func (c *Client) RunAgent(name, prompt, remoteAgentName string, timeout time.Duration) (*acp.Run, error) {
 	url := fmt.Sprintf("http://unix/agents/%s/run", name)
-	body, _ := json.Marshal(map[string]string{"prompt": prompt, "agent_name": remoteAgentName})
+	req := map[string]interface{}{"prompt": prompt, "agent_name": remoteAgentName}
+	if timeout > 0 {
+		req["timeout"] = int(math.Ceil(timeout.Seconds()))
+	}
+	body, _ := json.Marshal(req)
 	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to run agent: %w", err)
@@ -830,7 +1316,7 @@ func (c *Client) RunAgent(name, prompt, remoteAgentName string) (*acp.Run, error
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("run agent failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "run agent failed: %s", errResp.Error)
 	}
 
 	var run acp.Run
@@ -855,7 +1341,7 @@ func (c *Client) GetAgentLogs(agentName string, limit int) ([]AgentLog, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("agent logs request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "agent logs request failed: %d", resp.StatusCode)
 	}
 
 	var logs []AgentLog
@@ -880,7 +1366,7 @@ func (c *Client) ListGallery(featured bool) ([]acp.GalleryEntry, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("gallery request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "gallery request failed: %d", resp.StatusCode)
 	}
 
 	var entries []acp.GalleryEntry
@@ -905,7 +1391,7 @@ func (c *Client) GetGalleryAgent(id string) (*acp.InstallInfo, error) {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("get gallery agent failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "get gallery agent failed: %s", errResp.Error)
 	}
 
 	var info acp.InstallInfo
@@ -954,7 +1440,7 @@ func (c *Client) InstallGalleryAgentWithOptions(id, name, workdir string, port i
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("install agent failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "install agent failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -973,7 +1459,7 @@ func (c *Client) RefreshGallery() error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("refresh gallery failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "refresh gallery failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -988,7 +1474,7 @@ func (c *Client) GetMCPServerConfigs() ([]MCPServerResponse, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("MCP server configs request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "MCP server configs request failed: %d", resp.StatusCode)
 	}
 
 	var configs []MCPServerResponse
@@ -1001,15 +1487,19 @@ func (c *Client) GetMCPServerConfigs() ([]MCPServerResponse, error) {
 
 // CreateMCPServerRequest represents a request to create an MCP server
 type CreateMCPServerRequest struct {
-	Name    string            `json:"name"`
-	Enabled *bool             `json:"enabled,omitempty"`
-	Type    string            `json:"type"`
-	Command string            `json:"command,omitempty"`
-	Args    []string          `json:"args,omitempty"`
-	Env     map[string]string `json:"env,omitempty"`
-	URL     string            `json:"url,omitempty"`
-	Headers map[string]string `json:"headers,omitempty"`
-	OAuth   *db.OAuthConfig   `json:"oauth,omitempty"`
+	Name           string            `json:"name"`
+	Enabled        *bool             `json:"enabled,omitempty"`
+	Type           string            `json:"type"`
+	Command        string            `json:"command,omitempty"`
+	Args           []string          `json:"args,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	OAuth          *db.OAuthConfig   `json:"oauth,omitempty"`
+	ToolTimeout    int               `json:"tool_timeout,omitempty"`    // Seconds; 0 = proxy default
+	MaxConcurrency int               `json:"max_concurrency,omitempty"` // Concurrent tool calls; 0 = unlimited
+	StartupTimeout int               `json:"startup_timeout,omitempty"` // Seconds to wait for a stdio server to initialize; 0 = proxy default
+	Priority       int               `json:"priority,omitempty"`        // Tool listing order; higher first, 0 = default
 }
 
 // CreateMCPServer creates a new MCP server
@@ -1026,12 +1516,12 @@ func (c *Client) CreateMCPServer(req CreateMCPServerRequest) (*MCPServerResponse
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusConflict {
-		return nil, fmt.Errorf("server with name '%s' already exists", req.Name)
+		return nil, statusErrorf(resp.StatusCode, "server with name '%s' already exists", req.Name)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("create MCP server request failed: %d - %s", resp.StatusCode, string(body))
+		return nil, statusErrorf(resp.StatusCode, "create MCP server request failed: %d - %s", resp.StatusCode, string(body))
 	}
 
 	var server MCPServerResponse
@@ -1044,13 +1534,17 @@ func (c *Client) CreateMCPServer(req CreateMCPServerRequest) (*MCPServerResponse
 
 // UpdateMCPServerRequest represents a request to update an MCP server
 type UpdateMCPServerRequest struct {
-	Name    *string            `json:"name,omitempty"`
-	Enabled *bool              `json:"enabled,omitempty"`
-	Command *string            `json:"command,omitempty"`
-	Args    *[]string          `json:"args,omitempty"`
-	Env     *map[string]string `json:"env,omitempty"`
-	URL     *string            `json:"url,omitempty"`
-	Headers *map[string]string `json:"headers,omitempty"`
+	Name           *string            `json:"name,omitempty"`
+	Enabled        *bool              `json:"enabled,omitempty"`
+	Command        *string            `json:"command,omitempty"`
+	Args           *[]string          `json:"args,omitempty"`
+	Env            *map[string]string `json:"env,omitempty"`
+	URL            *string            `json:"url,omitempty"`
+	Headers        *map[string]string `json:"headers,omitempty"`
+	ToolTimeout    *int               `json:"tool_timeout,omitempty"`    // Seconds; 0 = proxy default
+	MaxConcurrency *int               `json:"max_concurrency,omitempty"` // Concurrent tool calls; 0 = unlimited
+	StartupTimeout *int               `json:"startup_timeout,omitempty"` // Seconds to wait for a stdio server to initialize; 0 = proxy default
+	Priority       *int               `json:"priority,omitempty"`        // Tool listing order; higher first, 0 = default
 }
 
 // UpdateMCPServerConfig updates an MCP server configuration
@@ -1078,7 +1572,7 @@ func (c *Client) UpdateMCPServerConfig(id int64, req UpdateMCPServerRequest) (*M
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("update failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "update failed: %s", errResp.Error)
 	}
 
 	var server MCPServerResponse
@@ -1108,7 +1602,7 @@ func (c *Client) DeleteMCPServerConfig(id int64) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("delete failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "delete failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -1123,7 +1617,7 @@ func (c *Client) ListOAuthServers() ([]OAuthServerInfo, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OAuth servers request failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "OAuth servers request failed: %d", resp.StatusCode)
 	}
 
 	var servers []OAuthServerInfo
@@ -1148,7 +1642,7 @@ func (c *Client) GetOAuthStatus(serverName string) (map[string]interface{}, erro
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("OAuth status failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "OAuth status failed: %s", errResp.Error)
 	}
 
 	var status map[string]interface{}
@@ -1173,7 +1667,7 @@ func (c *Client) StartOAuthLogin(serverName string) (*DeviceCodeInfo, error) {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("OAuth login failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "OAuth login failed: %s", errResp.Error)
 	}
 
 	var deviceInfo DeviceCodeInfo
@@ -1184,12 +1678,15 @@ func (c *Client) StartOAuthLogin(serverName string) (*DeviceCodeInfo, error) {
 	return &deviceInfo, nil
 }
 
-// PollOAuthToken polls for the OAuth token after user authorization
-func (c *Client) PollOAuthToken(serverName string, deviceCode string, interval int) error {
+// PollOAuthToken polls for the OAuth token after user authorization. It
+// blocks until the user authorizes, denies, or the device code's expiresIn
+// seconds run out.
+func (c *Client) PollOAuthToken(serverName string, deviceCode string, interval int, expiresIn int) error {
 	url := fmt.Sprintf("http://unix/auth/%s/poll", serverName)
 	body, _ := json.Marshal(map[string]interface{}{
 		"device_code": deviceCode,
 		"interval":    interval,
+		"expires_in":  expiresIn,
 	})
 	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
 	if err != nil {
@@ -1202,12 +1699,64 @@ func (c *Client) PollOAuthToken(serverName string, deviceCode string, interval i
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("OAuth poll failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "OAuth poll failed: %s", errResp.Error)
 	}
 
 	return nil
 }
 
+// SetOAuthToken stores an existing token for a server and restarts it
+func (c *Client) SetOAuthToken(serverName string, token OAuthTokenImport) (*OAuthTokenResult, error) {
+	url := fmt.Sprintf("http://unix/auth/%s/token", serverName)
+	body, _ := json.Marshal(token)
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set OAuth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, statusErrorf(resp.StatusCode, "set token failed: %s", errResp.Error)
+	}
+
+	var result OAuthTokenResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode set token result: %w", err)
+	}
+
+	return &result, nil
+}
+
+// RefreshOAuthToken forces a server's token to be refreshed using its stored
+// refresh token, and restarts the server
+func (c *Client) RefreshOAuthToken(serverName string) (*OAuthTokenResult, error) {
+	url := fmt.Sprintf("http://unix/auth/%s/refresh", serverName)
+	resp, err := c.httpClient.Post(url, "application/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh OAuth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, statusErrorf(resp.StatusCode, "refresh failed: %s", errResp.Error)
+	}
+
+	var result OAuthTokenResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode refresh result: %w", err)
+	}
+
+	return &result, nil
+}
+
 // LogoutOAuth removes the OAuth token for a server
 func (c *Client) LogoutOAuth(serverName string) error {
 	url := fmt.Sprintf("http://unix/auth/%s/logout", serverName)
@@ -1222,7 +1771,7 @@ func (c *Client) LogoutOAuth(serverName string) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("logout failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "logout failed: %s", errResp.Error)
 	}
 
 	return nil
@@ -1244,7 +1793,7 @@ func (c *Client) ListProviders(typeFilter string) ([]ProviderResponse, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("list providers failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "list providers failed: status %d", resp.StatusCode)
 	}
 
 	var providers []ProviderResponse
@@ -1274,9 +1823,9 @@ func (c *Client) CreateProvider(req CreateProviderRequest) (*ProviderResponse, e
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
 		if errResp.Error != "" {
-			return nil, fmt.Errorf("create provider failed: %s", errResp.Error)
+			return nil, statusErrorf(resp.StatusCode, "create provider failed: %s", errResp.Error)
 		}
-		return nil, fmt.Errorf("create provider failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "create provider failed: status %d", resp.StatusCode)
 	}
 
 	var provider ProviderResponse
@@ -1312,7 +1861,7 @@ func (c *Client) UpdateProvider(id int64, req UpdateProviderRequest) (*ProviderR
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("update provider failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "update provider failed: %s", errResp.Error)
 	}
 
 	var provider ProviderResponse
@@ -1338,7 +1887,7 @@ func (c *Client) DeleteProvider(id int64) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("delete provider failed: status %d", resp.StatusCode)
+		return statusErrorf(resp.StatusCode, "delete provider failed: status %d", resp.StatusCode)
 	}
 
 	return nil
@@ -1354,7 +1903,7 @@ func (c *Client) EnableProvider(id int64) (*ProviderResponse, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("enable provider failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "enable provider failed: status %d", resp.StatusCode)
 	}
 
 	var provider ProviderResponse
@@ -1375,7 +1924,7 @@ func (c *Client) DisableProvider(id int64) (*ProviderResponse, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("disable provider failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "disable provider failed: status %d", resp.StatusCode)
 	}
 
 	var provider ProviderResponse
@@ -1396,7 +1945,7 @@ func (c *Client) SetDefaultProvider(id int64) (*ProviderResponse, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("set default provider failed: status %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "set default provider failed: status %d", resp.StatusCode)
 	}
 
 	var provider ProviderResponse
@@ -1422,7 +1971,7 @@ func (c *Client) TestProvider(id int64) (*ProviderTestResult, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return &result, fmt.Errorf("test failed: %s", result.Message)
+		return &result, statusErrorf(resp.StatusCode, "test failed: %s", result.Message)
 	}
 
 	return &result, nil
@@ -1451,7 +2000,7 @@ func (c *Client) ListProviderModels(service, providerType, projectID string) ([]
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("list models failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "list models failed: %s", errResp.Error)
 	}
 
 	var result ListModelsResponse
@@ -1481,7 +2030,7 @@ func (c *Client) StartSession(agentName, workDir, title string) (*acp.SessionInf
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("start session failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "start session failed: %s", errResp.Error)
 	}
 
 	var info acp.SessionInfo
@@ -1513,7 +2062,7 @@ func (c *Client) ListSessions(agentName, status string) ([]*acp.SessionInfo, err
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("list sessions failed: %d", resp.StatusCode)
+		return nil, statusErrorf(resp.StatusCode, "list sessions failed: %d", resp.StatusCode)
 	}
 
 	var sessions []*acp.SessionInfo
@@ -1537,7 +2086,7 @@ func (c *Client) GetSessionInfo(agentName, sessionID string) (*acp.SessionInfo,
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("get session failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "get session failed: %s", errResp.Error)
 	}
 
 	var info acp.SessionInfo
@@ -1562,7 +2111,7 @@ func (c *Client) PromptSession(agentName, sessionID, prompt string) (*acp.Run, e
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("prompt session failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "prompt session failed: %s", errResp.Error)
 	}
 
 	var run acp.Run
@@ -1572,6 +2121,45 @@ func (c *Client) PromptSession(agentName, sessionID, prompt string) (*acp.Run, e
 	return &run, nil
 }
 
+// PromptSessionStream sends a prompt to a session, calling onText with each
+// chunk of agent text as it arrives, and returns the completed run.
+func (c *Client) PromptSessionStream(agentName, sessionID, prompt string, onText func(string)) (*acp.Run, error) {
+	url := fmt.Sprintf("http://unix/agents/%s/sessions/%s/prompt?stream=true", agentName, sessionID)
+	body, _ := json.Marshal(map[string]string{"prompt": prompt})
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, statusErrorf(resp.StatusCode, "prompt session failed: %s", errResp.Error)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var event SessionStreamEvent
+		if err := dec.Decode(&event); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("prompt stream ended without a result")
+			}
+			return nil, fmt.Errorf("failed to decode prompt stream: %w", err)
+		}
+		switch {
+		case event.Error != "":
+			return nil, fmt.Errorf("prompt session failed: %s", event.Error)
+		case event.Run != nil:
+			return event.Run, nil
+		case event.Text != "" && onText != nil:
+			onText(event.Text)
+		}
+	}
+}
+
 // SetSessionConfig sets a configuration option on a live session.
 func (c *Client) SetSessionConfig(agentName, sessionID, configID, value string) error {
 	url := fmt.Sprintf("http://unix/agents/%s/sessions/%s/config", agentName, sessionID)
@@ -1587,7 +2175,7 @@ func (c *Client) SetSessionConfig(agentName, sessionID, configID, value string)
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("set session config failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "set session config failed: %s", errResp.Error)
 	}
 	return nil
 }
@@ -1610,7 +2198,7 @@ func (c *Client) CloseSession(agentName, sessionID string) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("close session failed: %s", errResp.Error)
+		return statusErrorf(resp.StatusCode, "close session failed: %s", errResp.Error)
 	}
 	return nil
 }
@@ -1629,7 +2217,7 @@ func (c *Client) GetSessionMessages(agentName, sessionID string) ([]*store.ACPSe
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("get session messages failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "get session messages failed: %s", errResp.Error)
 	}
 
 	var messages []*store.ACPSessionMessage
@@ -1662,7 +2250,7 @@ func (c *Client) ListQuestions(status string) ([]emergent.AgentQuestion, error)
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("list questions failed: %s", errResp.Error)
+		return nil, statusErrorf(resp.StatusCode, "list questions failed: %s", errResp.Error)
 	}
 
 	var questions []emergent.AgentQuestion
@@ -1694,7 +2282,7 @@ func (c *Client) RespondToQuestion(id, response string) error {
 			Error string `json:"error"`
 		}
 		json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("respond to question failed (status %d): %s", resp.StatusCode, errResp.Error)
+		return statusErrorf(resp.StatusCode, "respond to question failed (status %d): %s", resp.StatusCode, errResp.Error)
 	}
 	return nil
 }
@@ -40,6 +40,11 @@ func (api *ContextsAPI) routeServersAction(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if pathParts[1] == "init-options" && len(pathParts) == 2 {
+		api.handleServerInitOptions(w, r, contextName, serverName)
+		return
+	}
+
 	if pathParts[1] == "tools" {
 		if len(pathParts) == 2 {
 			api.handleServerTools(w, r, contextName, serverName)
@@ -290,3 +295,47 @@ func (api *ContextsAPI) handleToolAction(w http.ResponseWriter, r *http.Request,
 		"enabled": body.Enabled,
 	})
 }
+
+// handleServerInitOptions handles GET/PUT/DELETE /contexts/{name}/servers/{server}/init-options.
+// The options are passed to a stdio server's initialize call in this context.
+func (api *ContextsAPI) handleServerInitOptions(w http.ResponseWriter, r *http.Request, contextName, serverName string) {
+	switch r.Method {
+	case http.MethodGet:
+		options, err := api.db.GetServerInitOptions(context.Background(), contextName, serverName)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if options == nil {
+			options = map[string]any{}
+		}
+		json.NewEncoder(w).Encode(options)
+
+	case http.MethodPut, http.MethodDelete:
+		var options map[string]any
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: expected a JSON object"})
+				return
+			}
+		}
+
+		if err := api.db.SetServerInitOptions(context.Background(), contextName, serverName, options); err != nil {
+			if err == db.ErrServerNotInContext {
+				w.WriteHeader(http.StatusBadRequest)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+	}
+}
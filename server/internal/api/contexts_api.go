@@ -3,9 +3,11 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/diane-assistant/diane/internal/config"
 	"github.com/diane-assistant/diane/internal/db"
 	"github.com/diane-assistant/diane/internal/store"
 )
@@ -297,7 +299,7 @@ func (api *ContextsAPI) handleConnect(w http.ResponseWriter, r *http.Request, co
 	}
 
 	// Base URL for Diane MCP server
-	baseURL := "http://localhost:8765"
+	baseURL := fmt.Sprintf("http://localhost:%d", config.MCPHTTPPort())
 
 	info := ConnectInfo{
 		Context: contextName,
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors for the failure classes callers most often need to tell
+// apart. Client methods wrap them, so test with errors.Is.
+var (
+	// ErrDaemonUnreachable means the request never got an HTTP response,
+	// typically because the daemon isn't running.
+	ErrDaemonUnreachable = errors.New("daemon unreachable")
+	// ErrUnauthorized means the daemon rejected the request's credentials
+	// (HTTP 401 or 403).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound means the requested resource doesn't exist (HTTP 404).
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the request clashes with existing state (HTTP 409).
+	ErrConflict = errors.New("conflict")
+)
+
+// StatusError is returned when the daemon answers with an unexpected HTTP
+// status. It matches ErrNotFound, ErrConflict and ErrUnauthorized via
+// errors.Is according to StatusCode.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// Is reports whether the status code belongs to the given sentinel's class
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// statusErrorf builds a StatusError with a formatted message
+func statusErrorf(statusCode int, format string, args ...interface{}) error {
+	return &StatusError{StatusCode: statusCode, Message: fmt.Sprintf(format, args...)}
+}
+
+// unreachableTransport tags round-trip failures with ErrDaemonUnreachable.
+// http.Client wraps them in *url.Error, which keeps the chain intact, so every
+// client method's "failed to ...: %w" error matches without further changes.
+type unreachableTransport struct {
+	inner http.RoundTripper
+}
+
+func (t *unreachableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDaemonUnreachable, err)
+	}
+	return resp, nil
+}
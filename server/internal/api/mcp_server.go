@@ -2,14 +2,21 @@ package api
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/logger"
 	"github.com/google/uuid"
 )
 
@@ -27,8 +34,19 @@ type MCPHTTPServer struct {
 	tlsConfig       *tls.Config
 	tlsCertPath     string
 	tlsKeyPath      string
+	heartbeat       time.Duration // between SSE comment frames; <0 = off
+	adminToken      string        // bearer token required for AdminContextName; empty = refused
 }
 
+// AdminContextName is the privileged context whose clients may manage
+// contexts. Over HTTP it's only granted to clients that send the
+// configured admin token.
+const AdminContextName = "admin"
+
+// DefaultSSEHeartbeat is how often an open SSE stream gets a comment frame
+// when SetSSEHeartbeat isn't called
+const DefaultSSEHeartbeat = 15 * time.Second
+
 // MCPHandler interface for handling MCP requests
 type MCPHandler interface {
 	HandleRequest(req MCPRequest) MCPResponse
@@ -40,11 +58,39 @@ type MCPHandler interface {
 // mcpSession represents an active MCP session
 type mcpSession struct {
 	id          string
-	context     string // Context name for filtering tools (from ?context= query param)
+	context     string   // Context name for filtering tools (from ?context= query param)
+	tools       []string // Tool name globs further narrowing the context's tools (from ?tools=)
 	initialized bool
 	createdAt   time.Time
 	eventChan   chan []byte
 	closeChan   chan struct{}
+	streaming   bool // an SSE stream is open; guarded by sessionsMu
+
+	// Delivery counters for the SSE stream, reported by SSEClients
+	lastSend        atomic.Int64 // unix nanoseconds of the last frame written
+	framesSent      atomic.Int64 // every frame, heartbeats included
+	messagesSent    atomic.Int64 // responses and notifications
+	messagesDropped atomic.Int64 // lost because the event queue was full
+}
+
+// SSEClient describes a client connected to the SSE transport
+type SSEClient struct {
+	// ID tells sessions apart for display. The session ID itself is what
+	// /mcp/message accepts as proof of the session, so only a hash of it
+	// is shown.
+	ID          string     `json:"id"`
+	Context     string     `json:"context,omitempty"`
+	Initialized bool       `json:"initialized"`
+	ConnectedAt time.Time  `json:"connected_at"`
+	LastSendAt  *time.Time `json:"last_send_at,omitempty"`
+	// FramesSent counts every frame written, heartbeats included;
+	// MessagesSent just the responses and notifications among them
+	FramesSent   int64 `json:"frames_sent"`
+	MessagesSent int64 `json:"messages_sent"`
+	// MessagesDropped counts messages lost because the client wasn't
+	// reading fast enough, and Queued those waiting to be written
+	MessagesDropped int64 `json:"messages_dropped"`
+	Queued          int   `json:"queued"`
 }
 
 // MCPRequest represents a JSON-RPC request
@@ -53,6 +99,10 @@ type MCPRequest struct {
 	ID      interface{}     `json:"id"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
+
+	// RequestID identifies the request in logs and proxied calls. It comes
+	// from the X-Request-ID header, or is generated.
+	RequestID string `json:"-"`
 }
 
 // MCPResponse represents a JSON-RPC response
@@ -79,6 +129,7 @@ func NewMCPHTTPServer(statusProvider StatusProvider, mcpHandler MCPHandler, port
 		port:            port,
 		securePort:      securePort,
 		routeRegistrars: make([]func(*http.ServeMux), 0),
+		heartbeat:       DefaultSSEHeartbeat,
 	}
 }
 
@@ -94,6 +145,40 @@ func (s *MCPHTTPServer) SetTLS(tlsConfig *tls.Config, certPath, keyPath string)
 	s.tlsKeyPath = keyPath
 }
 
+// SetSSEHeartbeat sets how often open SSE streams get a comment frame to
+// keep proxies from dropping them as idle. 0 restores the default and a
+// negative interval turns heartbeats off.
+func (s *MCPHTTPServer) SetSSEHeartbeat(interval time.Duration) {
+	if interval == 0 {
+		interval = DefaultSSEHeartbeat
+	}
+	s.heartbeat = interval
+}
+
+// SetAdminToken sets the bearer token a client must send in the
+// Authorization header to connect with ?context=admin. With no token the
+// admin context can't be used over HTTP.
+func (s *MCPHTTPServer) SetAdminToken(token string) {
+	s.adminToken = token
+}
+
+// authorizeContext checks that a client may connect with contextName: any
+// context but the admin one is open, and the admin one needs the admin
+// token
+func (s *MCPHTTPServer) authorizeContext(r *http.Request, contextName string) error {
+	if contextName != AdminContextName {
+		return nil
+	}
+	if s.adminToken == "" {
+		return fmt.Errorf("the %s context is not available over HTTP (set mcp_http.admin_token to enable it)", AdminContextName)
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+		return fmt.Errorf("the %s context requires the admin token", AdminContextName)
+	}
+	return nil
+}
+
 // Start starts the MCP HTTP server
 func (s *MCPHTTPServer) Start() error {
 	mux := http.NewServeMux()
@@ -186,8 +271,8 @@ func (s *MCPHTTPServer) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, MCP-Protocol-Version, MCP-Session-Id")
-		w.Header().Set("Access-Control-Expose-Headers", "MCP-Session-Id")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, MCP-Protocol-Version, MCP-Session-Id, "+logger.RequestIDHeader)
+		w.Header().Set("Access-Control-Expose-Headers", "MCP-Session-Id, "+logger.RequestIDHeader)
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -212,8 +297,13 @@ func (s *MCPHTTPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse context from query parameter
+	// Parse context and tool filter from query parameters
 	contextName := r.URL.Query().Get("context")
+	tools, err := parseToolFilter(r.URL.Query().Get("tools"))
+	if err != nil {
+		s.writeError(w, -32602, err.Error(), nil)
+		return
+	}
 
 	// Parse request
 	var req MCPRequest
@@ -221,6 +311,8 @@ func (s *MCPHTTPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, -32700, "Parse error", nil)
 		return
 	}
+	req.RequestID = requestID(r)
+	w.Header().Set(logger.RequestIDHeader, req.RequestID)
 
 	// Get or create session
 	sessionID := r.Header.Get("MCP-Session-Id")
@@ -229,7 +321,14 @@ func (s *MCPHTTPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
 	// Handle initialize specially
 	if req.Method == "initialize" {
 		if session == nil {
+			if err := s.authorizeContext(r, contextName); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				s.writeError(w, -32600, err.Error(), nil)
+				return
+			}
 			session = s.createSessionWithContext(contextName)
+			session.tools = tools
 		}
 		session.initialized = true
 		w.Header().Set("MCP-Session-Id", session.id)
@@ -241,12 +340,7 @@ func (s *MCPHTTPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Handle the request with context
-	var resp MCPResponse
-	if session.context != "" {
-		resp = s.mcpHandler.HandleRequestWithContext(req, session.context)
-	} else {
-		resp = s.mcpHandler.HandleRequest(req)
-	}
+	resp := s.handleSessionRequest(session, req)
 	resp.JSONRPC = "2.0"
 	resp.ID = req.ID
 
@@ -261,16 +355,31 @@ func (s *MCPHTTPServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse context from query parameter
+	// Parse context and tool filter from query parameters
 	contextName := r.URL.Query().Get("context")
+	tools, err := parseToolFilter(r.URL.Query().Get("tools"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.authorizeContext(r, contextName); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
-	// Create session with context
+	// Create session with context. It lives as long as the stream: however
+	// the stream ends, the session is removed.
 	session := s.createSessionWithContext(contextName)
+	session.tools = tools
+	s.sessionsMu.Lock()
+	session.streaming = true
+	s.sessionsMu.Unlock()
+	defer s.removeSession(session.id)
 
 	// Send endpoint event — use matching path prefix based on request path
 	messagePath := "/mcp/message"
@@ -282,38 +391,45 @@ func (s *MCPHTTPServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		messageURL += fmt.Sprintf("&context=%s", contextName)
 	}
 	fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", messageURL)
+	session.sent(false)
 
 	flusher, ok := w.(http.Flusher)
 	if ok {
 		flusher.Flush()
 	}
 
-	// Keep connection alive and forward events
+	// Forward events, with a heartbeat comment in between so intermediaries
+	// see traffic and a dead client is noticed by the failed write
+	var heartbeat <-chan time.Time
+	if s.heartbeat > 0 {
+		ticker := time.NewTicker(s.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
 	for {
 		select {
 		case event := <-session.eventChan:
 			_, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", string(event))
 			if err != nil {
 				slog.Debug("SSE client write failed, closing session", "session", session.id, "error", err)
-				s.removeSession(session.id)
 				return
 			}
+			session.sent(true)
 			if ok {
 				flusher.Flush()
 			}
 		case <-session.closeChan:
 			return
 		case <-r.Context().Done():
-			s.removeSession(session.id)
+			slog.Debug("SSE client disconnected, closing session", "session", session.id)
 			return
-		case <-time.After(30 * time.Second):
-			// Send keepalive
+		case <-heartbeat:
 			_, err := fmt.Fprintf(w, ": keepalive\n\n")
 			if err != nil {
 				slog.Debug("SSE client keepalive failed, closing session", "session", session.id, "error", err)
-				s.removeSession(session.id)
 				return
 			}
+			session.sent(false)
 			if ok {
 				flusher.Flush()
 			}
@@ -340,6 +456,8 @@ func (s *MCPHTTPServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, -32700, "Parse error", nil)
 		return
 	}
+	req.RequestID = requestID(r)
+	w.Header().Set(logger.RequestIDHeader, req.RequestID)
 
 	// Handle initialize
 	if req.Method == "initialize" {
@@ -350,12 +468,7 @@ func (s *MCPHTTPServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Handle the request with context
-	var resp MCPResponse
-	if session.context != "" {
-		resp = s.mcpHandler.HandleRequestWithContext(req, session.context)
-	} else {
-		resp = s.mcpHandler.HandleRequest(req)
-	}
+	resp := s.handleSessionRequest(session, req)
 	resp.JSONRPC = "2.0"
 	resp.ID = req.ID
 
@@ -364,6 +477,7 @@ func (s *MCPHTTPServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	select {
 	case session.eventChan <- respBytes:
 	default:
+		session.messagesDropped.Add(1)
 		slog.Warn("Session event channel full", "session", sessionID)
 	}
 
@@ -372,6 +486,15 @@ func (s *MCPHTTPServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "accepted"})
 }
 
+// requestID returns the request ID a client sent in the X-Request-ID header,
+// or a new one
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(logger.RequestIDHeader); id != "" && len(id) <= logger.MaxRequestIDLength {
+		return id
+	}
+	return logger.NewRequestID()
+}
+
 // writeError writes a JSON-RPC error response
 func (s *MCPHTTPServer) writeError(w http.ResponseWriter, code int, message string, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -424,6 +547,52 @@ func (s *MCPHTTPServer) removeSession(id string) {
 	s.sessionsMu.Unlock()
 }
 
+// sent records a frame written to the session's SSE stream
+func (session *mcpSession) sent(message bool) {
+	session.lastSend.Store(time.Now().UnixNano())
+	session.framesSent.Add(1)
+	if message {
+		session.messagesSent.Add(1)
+	}
+}
+
+// SSEClients lists the clients with an open SSE stream, oldest first
+func (s *MCPHTTPServer) SSEClients() []SSEClient {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	clients := make([]SSEClient, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		if !session.streaming {
+			continue
+		}
+		c := SSEClient{
+			ID:              displaySessionID(session.id),
+			Context:         session.context,
+			Initialized:     session.initialized,
+			ConnectedAt:     session.createdAt,
+			FramesSent:      session.framesSent.Load(),
+			MessagesSent:    session.messagesSent.Load(),
+			MessagesDropped: session.messagesDropped.Load(),
+			Queued:          len(session.eventChan),
+		}
+		if ns := session.lastSend.Load(); ns != 0 {
+			t := time.Unix(0, ns)
+			c.LastSendAt = &t
+		}
+		clients = append(clients, c)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ConnectedAt.Before(clients[j].ConnectedAt) })
+	return clients
+}
+
+// displaySessionID returns a short hash of a session ID that identifies the
+// session without being usable to post into it
+func displaySessionID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:6])
+}
+
 // cleanupSessions removes expired sessions
 func (s *MCPHTTPServer) cleanupSessions() {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -433,8 +602,9 @@ func (s *MCPHTTPServer) cleanupSessions() {
 		s.sessionsMu.Lock()
 		now := time.Now()
 		for id, session := range s.sessions {
-			// Remove sessions older than 1 hour that aren't active
-			if now.Sub(session.createdAt) > time.Hour {
+			// Remove sessions older than 1 hour that aren't active. An SSE
+			// session is removed when its stream ends instead.
+			if !session.streaming && now.Sub(session.createdAt) > time.Hour {
 				close(session.closeChan)
 				delete(s.sessions, id)
 			}
@@ -461,6 +631,7 @@ func (s *MCPHTTPServer) SendNotification(method string, params interface{}) {
 			case session.eventChan <- notifBytes:
 			default:
 				// Channel full, skip
+				session.messagesDropped.Add(1)
 			}
 		}
 	}
@@ -479,6 +650,7 @@ func (s *MCPHTTPServer) StreamMCP(w http.ResponseWriter, r *http.Request) {
 		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
 			continue
 		}
+		req.RequestID = logger.NewRequestID()
 
 		resp := s.mcpHandler.HandleRequest(req)
 		resp.JSONRPC = "2.0"
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMCPHandler answers every request with an empty result and records
+// the contexts requests were handled in
+type fakeMCPHandler struct {
+	mu       sync.Mutex
+	contexts []string
+	result   json.RawMessage
+}
+
+func (h *fakeMCPHandler) HandleRequest(req MCPRequest) MCPResponse {
+	return h.HandleRequestWithContext(req, "")
+}
+
+func (h *fakeMCPHandler) HandleRequestWithContext(req MCPRequest, contextName string) MCPResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.contexts = append(h.contexts, contextName)
+	result := h.result
+	if result == nil {
+		result = json.RawMessage(`{}`)
+	}
+	return MCPResponse{Result: result}
+}
+
+func (h *fakeMCPHandler) GetTools() ([]ToolInfo, error) { return nil, nil }
+
+func (h *fakeMCPHandler) GetToolsForContext(string) ([]ToolInfo, error) { return nil, nil }
+
+// postMCP sends one JSON-RPC request to the streamable HTTP endpoint
+func postMCP(s *MCPHTTPServer, query, token, sessionID, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/mcp"+query, strings.NewReader(body))
+	r.Header.Set("Accept", "application/json")
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	if sessionID != "" {
+		r.Header.Set("MCP-Session-Id", sessionID)
+	}
+	w := httptest.NewRecorder()
+	s.handleMCP(w, r)
+	return w
+}
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+
+func TestAdminContextRequiresToken(t *testing.T) {
+	handler := &fakeMCPHandler{}
+	s := NewMCPHTTPServer(nil, handler, 0, 0)
+
+	tests := []struct {
+		name       string
+		adminToken string
+		query      string
+		token      string
+		wantStatus int
+	}{
+		{"other contexts are open", "", "?context=personal", "", http.StatusOK},
+		{"admin disabled without a token", "", "?context=admin", "anything", http.StatusForbidden},
+		{"admin without the header", "s3cret", "?context=admin", "", http.StatusForbidden},
+		{"admin with a wrong token", "s3cret", "?context=admin", "guess", http.StatusForbidden},
+		{"admin with the token", "s3cret", "?context=admin", "s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		s.SetAdminToken(tt.adminToken)
+		w := postMCP(s, tt.query, tt.token, "", initializeRequest)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status %d, want %d (%s)", tt.name, w.Code, tt.wantStatus, w.Body)
+		}
+		if tt.wantStatus == http.StatusForbidden && w.Header().Get("MCP-Session-Id") != "" {
+			t.Errorf("%s: a refused client should not get a session", tt.name)
+		}
+	}
+	if got := strings.Join(handler.contexts, ","); got != "personal,admin" {
+		t.Errorf("requests were handled in contexts %q, want only the allowed ones", got)
+	}
+
+	// An admin session keeps its context; later requests needn't resend
+	// the token, and a query parameter can't switch a session into admin
+	s.SetAdminToken("s3cret")
+	w := postMCP(s, "?context=personal", "", "", initializeRequest)
+	sessionID := w.Header().Get("MCP-Session-Id")
+	handler.contexts = nil
+	postMCP(s, "?context=admin", "", sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	if got := strings.Join(handler.contexts, ","); got != "personal" {
+		t.Errorf("expected the session's own context, got %q", got)
+	}
+}
+
+func TestAdminContextSSERequiresToken(t *testing.T) {
+	s := NewMCPHTTPServer(nil, &fakeMCPHandler{}, 0, 0)
+	s.SetAdminToken("s3cret")
+
+	r := httptest.NewRequest(http.MethodGet, "/mcp/sse?context=admin", nil)
+	w := httptest.NewRecorder()
+	s.handleSSE(w, r)
+	if w.Code != http.StatusForbidden || len(s.SSEClients()) != 0 {
+		t.Errorf("expected the SSE stream to be refused, got %d", w.Code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r = httptest.NewRequest(http.MethodGet, "/mcp/sse?context=admin", nil).WithContext(ctx)
+	r.Header.Set("Authorization", "Bearer s3cret")
+	w = httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		s.handleSSE(w, r)
+		close(done)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(s.SSEClients()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	clients := s.SSEClients()
+	cancel()
+	<-done
+	if len(clients) != 1 || clients[0].Context != AdminContextName {
+		t.Errorf("expected an admin SSE session with the token, got %+v", clients)
+	}
+}
+
+func TestSSEClientsHideSessionID(t *testing.T) {
+	s := NewMCPHTTPServer(nil, &fakeMCPHandler{}, 0, 0)
+	s.sessions["secret-session"] = &mcpSession{
+		id:        "secret-session",
+		context:   AdminContextName,
+		createdAt: time.Now(),
+		eventChan: make(chan []byte, 1),
+		streaming: true,
+	}
+
+	clients := s.SSEClients()
+	if len(clients) != 1 {
+		t.Fatalf("expected one client, got %+v", clients)
+	}
+	if id := clients[0].ID; id == "" || strings.Contains("secret-session", id) || id != displaySessionID("secret-session") {
+		t.Errorf("expected a hash of the session ID, got %q", id)
+	}
+}
+
+func TestReadOnlyMiddlewareRefusesDebug(t *testing.T) {
+	handler := readOnlyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for path, want := range map[string]int{
+		"/debug/sse-clients": http.StatusUnauthorized,
+		"/status":            http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("GET %s: got %d, want %d", path, w.Code, want)
+		}
+	}
+}
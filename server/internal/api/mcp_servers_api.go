@@ -25,20 +25,24 @@ func NewMCPServersAPI(mcpStore store.MCPServerStore, statusProvider StatusProvid
 
 // MCPServerResponse represents an MCP server in API responses
 type MCPServerResponse struct {
-	ID        int64             `json:"id"`
-	Name      string            `json:"name"`
-	Enabled   bool              `json:"enabled"`
-	Type      string            `json:"type"` // stdio, sse, http, builtin
-	Command   string            `json:"command,omitempty"`
-	Args      []string          `json:"args,omitempty"`
-	Env       map[string]string `json:"env,omitempty"`
-	URL       string            `json:"url,omitempty"`
-	Headers   map[string]string `json:"headers,omitempty"`
-	OAuth     *db.OAuthConfig   `json:"oauth,omitempty"`
-	NodeID    string            `json:"node_id,omitempty"`
-	NodeMode  string            `json:"node_mode,omitempty"`
-	CreatedAt string            `json:"created_at"`
-	UpdatedAt string            `json:"updated_at"`
+	ID             int64             `json:"id"`
+	Name           string            `json:"name"`
+	Enabled        bool              `json:"enabled"`
+	Type           string            `json:"type"` // stdio, sse, http, builtin
+	Command        string            `json:"command,omitempty"`
+	Args           []string          `json:"args,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	OAuth          *db.OAuthConfig   `json:"oauth,omitempty"`
+	NodeID         string            `json:"node_id,omitempty"`
+	NodeMode       string            `json:"node_mode,omitempty"`
+	ToolTimeout    int               `json:"tool_timeout,omitempty"`    // Seconds; 0 = proxy default
+	MaxConcurrency int               `json:"max_concurrency,omitempty"` // Concurrent tool calls; 0 = unlimited
+	StartupTimeout int               `json:"startup_timeout,omitempty"` // Seconds to wait for a stdio server to initialize; 0 = proxy default
+	Priority       int               `json:"priority,omitempty"`        // Tool listing order; higher first, 0 = default
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
 }
 
 // RegisterRoutes registers MCP server API routes on the given mux
@@ -105,20 +109,24 @@ func (api *MCPServersAPI) listServers(w http.ResponseWriter, r *http.Request) {
 
 	for _, s := range servers {
 		response = append(response, MCPServerResponse{
-			ID:        s.ID,
-			Name:      s.Name,
-			Enabled:   s.Enabled,
-			Type:      s.Type,
-			Command:   s.Command,
-			Args:      s.Args,
-			Env:       s.Env,
-			URL:       s.URL,
-			Headers:   s.Headers,
-			OAuth:     s.OAuth,
-			NodeID:    s.NodeID,
-			NodeMode:  s.NodeMode,
-			CreatedAt: s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: s.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:             s.ID,
+			Name:           s.Name,
+			Enabled:        s.Enabled,
+			Type:           s.Type,
+			Command:        s.Command,
+			Args:           s.Args,
+			Env:            s.Env,
+			URL:            s.URL,
+			Headers:        s.Headers,
+			OAuth:          s.OAuth,
+			NodeID:         s.NodeID,
+			NodeMode:       s.NodeMode,
+			ToolTimeout:    s.ToolTimeout,
+			MaxConcurrency: s.MaxConcurrency,
+			StartupTimeout: s.StartupTimeout,
+			Priority:       s.Priority,
+			CreatedAt:      s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:      s.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		})
 	}
 
@@ -128,17 +136,21 @@ func (api *MCPServersAPI) listServers(w http.ResponseWriter, r *http.Request) {
 // createServer creates a new MCP server
 func (api *MCPServersAPI) createServer(w http.ResponseWriter, r *http.Request) {
 	var body struct {
-		Name     string            `json:"name"`
-		Enabled  *bool             `json:"enabled,omitempty"`
-		Type     string            `json:"type"`
-		Command  string            `json:"command,omitempty"`
-		Args     []string          `json:"args,omitempty"`
-		Env      map[string]string `json:"env,omitempty"`
-		URL      string            `json:"url,omitempty"`
-		Headers  map[string]string `json:"headers,omitempty"`
-		OAuth    *db.OAuthConfig   `json:"oauth,omitempty"`
-		NodeID   string            `json:"node_id,omitempty"`
-		NodeMode string            `json:"node_mode,omitempty"`
+		Name           string            `json:"name"`
+		Enabled        *bool             `json:"enabled,omitempty"`
+		Type           string            `json:"type"`
+		Command        string            `json:"command,omitempty"`
+		Args           []string          `json:"args,omitempty"`
+		Env            map[string]string `json:"env,omitempty"`
+		URL            string            `json:"url,omitempty"`
+		Headers        map[string]string `json:"headers,omitempty"`
+		OAuth          *db.OAuthConfig   `json:"oauth,omitempty"`
+		NodeID         string            `json:"node_id,omitempty"`
+		NodeMode       string            `json:"node_mode,omitempty"`
+		ToolTimeout    int               `json:"tool_timeout,omitempty"`    // Seconds; 0 = proxy default
+		MaxConcurrency int               `json:"max_concurrency,omitempty"` // Concurrent tool calls; 0 = unlimited
+		StartupTimeout int               `json:"startup_timeout,omitempty"` // Seconds to wait for a stdio server to initialize; 0 = proxy default
+		Priority       int               `json:"priority,omitempty"`        // Tool listing order; higher first, 0 = default
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -203,18 +215,40 @@ func (api *MCPServersAPI) createServer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if body.ToolTimeout < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "tool_timeout must not be negative"})
+		return
+	}
+
+	if body.MaxConcurrency < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "max_concurrency must not be negative"})
+		return
+	}
+
+	if body.StartupTimeout < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "startup_timeout must not be negative"})
+		return
+	}
+
 	server := &db.MCPServer{
-		Name:     body.Name,
-		Enabled:  enabled,
-		Type:     body.Type,
-		Command:  body.Command,
-		Args:     body.Args,
-		Env:      body.Env,
-		URL:      body.URL,
-		Headers:  body.Headers,
-		OAuth:    body.OAuth,
-		NodeID:   body.NodeID,
-		NodeMode: nodeMode,
+		Name:           body.Name,
+		Enabled:        enabled,
+		Type:           body.Type,
+		Command:        body.Command,
+		Args:           body.Args,
+		Env:            body.Env,
+		URL:            body.URL,
+		Headers:        body.Headers,
+		OAuth:          body.OAuth,
+		NodeID:         body.NodeID,
+		NodeMode:       nodeMode,
+		ToolTimeout:    body.ToolTimeout,
+		MaxConcurrency: body.MaxConcurrency,
+		StartupTimeout: body.StartupTimeout,
+		Priority:       body.Priority,
 	}
 
 	if err := api.db.CreateMCPServer(context.Background(), server); err != nil {
@@ -230,20 +264,24 @@ func (api *MCPServersAPI) createServer(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(MCPServerResponse{
-		ID:        server.ID,
-		Name:      server.Name,
-		Enabled:   server.Enabled,
-		Type:      server.Type,
-		Command:   server.Command,
-		Args:      server.Args,
-		Env:       server.Env,
-		URL:       server.URL,
-		Headers:   server.Headers,
-		OAuth:     server.OAuth,
-		NodeID:    server.NodeID,
-		NodeMode:  server.NodeMode,
-		CreatedAt: server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             server.ID,
+		Name:           server.Name,
+		Enabled:        server.Enabled,
+		Type:           server.Type,
+		Command:        server.Command,
+		Args:           server.Args,
+		Env:            server.Env,
+		URL:            server.URL,
+		Headers:        server.Headers,
+		OAuth:          server.OAuth,
+		NodeID:         server.NodeID,
+		NodeMode:       server.NodeMode,
+		ToolTimeout:    server.ToolTimeout,
+		MaxConcurrency: server.MaxConcurrency,
+		StartupTimeout: server.StartupTimeout,
+		Priority:       server.Priority,
+		CreatedAt:      server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	})
 }
 
@@ -295,20 +333,24 @@ func (api *MCPServersAPI) getServer(w http.ResponseWriter, id int64) {
 	}
 
 	json.NewEncoder(w).Encode(MCPServerResponse{
-		ID:        server.ID,
-		Name:      server.Name,
-		Enabled:   server.Enabled,
-		Type:      server.Type,
-		Command:   server.Command,
-		Args:      server.Args,
-		Env:       server.Env,
-		URL:       server.URL,
-		Headers:   server.Headers,
-		OAuth:     server.OAuth,
-		NodeID:    server.NodeID,
-		NodeMode:  server.NodeMode,
-		CreatedAt: server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             server.ID,
+		Name:           server.Name,
+		Enabled:        server.Enabled,
+		Type:           server.Type,
+		Command:        server.Command,
+		Args:           server.Args,
+		Env:            server.Env,
+		URL:            server.URL,
+		Headers:        server.Headers,
+		OAuth:          server.OAuth,
+		NodeID:         server.NodeID,
+		NodeMode:       server.NodeMode,
+		ToolTimeout:    server.ToolTimeout,
+		MaxConcurrency: server.MaxConcurrency,
+		StartupTimeout: server.StartupTimeout,
+		Priority:       server.Priority,
+		CreatedAt:      server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	})
 }
 
@@ -328,17 +370,21 @@ func (api *MCPServersAPI) updateServer(w http.ResponseWriter, r *http.Request, i
 	}
 
 	var body struct {
-		Name     *string            `json:"name,omitempty"`
-		Enabled  *bool              `json:"enabled,omitempty"`
-		Type     *string            `json:"type,omitempty"`
-		Command  *string            `json:"command,omitempty"`
-		Args     *[]string          `json:"args,omitempty"`
-		Env      *map[string]string `json:"env,omitempty"`
-		URL      *string            `json:"url,omitempty"`
-		Headers  *map[string]string `json:"headers,omitempty"`
-		OAuth    *db.OAuthConfig    `json:"oauth,omitempty"`
-		NodeID   *string            `json:"node_id,omitempty"`
-		NodeMode *string            `json:"node_mode,omitempty"`
+		Name           *string            `json:"name,omitempty"`
+		Enabled        *bool              `json:"enabled,omitempty"`
+		Type           *string            `json:"type,omitempty"`
+		Command        *string            `json:"command,omitempty"`
+		Args           *[]string          `json:"args,omitempty"`
+		Env            *map[string]string `json:"env,omitempty"`
+		URL            *string            `json:"url,omitempty"`
+		Headers        *map[string]string `json:"headers,omitempty"`
+		OAuth          *db.OAuthConfig    `json:"oauth,omitempty"`
+		NodeID         *string            `json:"node_id,omitempty"`
+		NodeMode       *string            `json:"node_mode,omitempty"`
+		ToolTimeout    *int               `json:"tool_timeout,omitempty"`    // Seconds; 0 = proxy default
+		MaxConcurrency *int               `json:"max_concurrency,omitempty"` // Concurrent tool calls; 0 = unlimited
+		StartupTimeout *int               `json:"startup_timeout,omitempty"` // Seconds to wait for a stdio server to initialize; 0 = proxy default
+		Priority       *int               `json:"priority,omitempty"`        // Tool listing order; higher first, 0 = default
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -392,6 +438,33 @@ func (api *MCPServersAPI) updateServer(w http.ResponseWriter, r *http.Request, i
 		}
 		server.NodeMode = *body.NodeMode
 	}
+	if body.ToolTimeout != nil {
+		if *body.ToolTimeout < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "tool_timeout must not be negative"})
+			return
+		}
+		server.ToolTimeout = *body.ToolTimeout
+	}
+	if body.MaxConcurrency != nil {
+		if *body.MaxConcurrency < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "max_concurrency must not be negative"})
+			return
+		}
+		server.MaxConcurrency = *body.MaxConcurrency
+	}
+	if body.StartupTimeout != nil {
+		if *body.StartupTimeout < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "startup_timeout must not be negative"})
+			return
+		}
+		server.StartupTimeout = *body.StartupTimeout
+	}
+	if body.Priority != nil {
+		server.Priority = *body.Priority
+	}
 
 	// Validate node_id is provided when node_mode is "specific"
 	if server.NodeMode == "specific" && server.NodeID == "" {
@@ -412,20 +485,24 @@ func (api *MCPServersAPI) updateServer(w http.ResponseWriter, r *http.Request, i
 	}
 
 	json.NewEncoder(w).Encode(MCPServerResponse{
-		ID:        server.ID,
-		Name:      server.Name,
-		Enabled:   server.Enabled,
-		Type:      server.Type,
-		Command:   server.Command,
-		Args:      server.Args,
-		Env:       server.Env,
-		URL:       server.URL,
-		Headers:   server.Headers,
-		OAuth:     server.OAuth,
-		NodeID:    server.NodeID,
-		NodeMode:  server.NodeMode,
-		CreatedAt: server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             server.ID,
+		Name:           server.Name,
+		Enabled:        server.Enabled,
+		Type:           server.Type,
+		Command:        server.Command,
+		Args:           server.Args,
+		Env:            server.Env,
+		URL:            server.URL,
+		Headers:        server.Headers,
+		OAuth:          server.OAuth,
+		NodeID:         server.NodeID,
+		NodeMode:       server.NodeMode,
+		ToolTimeout:    server.ToolTimeout,
+		MaxConcurrency: server.MaxConcurrency,
+		StartupTimeout: server.StartupTimeout,
+		Priority:       server.Priority,
+		CreatedAt:      server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	})
 }
 
@@ -496,20 +573,24 @@ func (api *MCPServersAPI) handlePlacements(w http.ResponseWriter, r *http.Reques
 			HostID:   p.HostID,
 			Enabled:  p.Enabled,
 			Server: MCPServerResponse{
-				ID:        p.Server.ID,
-				Name:      p.Server.Name,
-				Enabled:   p.Server.Enabled,
-				Type:      p.Server.Type,
-				Command:   p.Server.Command,
-				Args:      p.Server.Args,
-				Env:       p.Server.Env,
-				URL:       p.Server.URL,
-				Headers:   p.Server.Headers,
-				OAuth:     p.Server.OAuth,
-				NodeID:    p.Server.NodeID,
-				NodeMode:  p.Server.NodeMode,
-				CreatedAt: p.Server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-				UpdatedAt: p.Server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				ID:             p.Server.ID,
+				Name:           p.Server.Name,
+				Enabled:        p.Server.Enabled,
+				Type:           p.Server.Type,
+				Command:        p.Server.Command,
+				Args:           p.Server.Args,
+				Env:            p.Server.Env,
+				URL:            p.Server.URL,
+				Headers:        p.Server.Headers,
+				OAuth:          p.Server.OAuth,
+				NodeID:         p.Server.NodeID,
+				NodeMode:       p.Server.NodeMode,
+				ToolTimeout:    p.Server.ToolTimeout,
+				MaxConcurrency: p.Server.MaxConcurrency,
+				StartupTimeout: p.Server.StartupTimeout,
+				Priority:       p.Server.Priority,
+				CreatedAt:      p.Server.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+				UpdatedAt:      p.Server.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			},
 			CreatedAt: p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt: p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// parseToolFilter reads the ?tools= query parameter: a comma-separated list
+// of glob patterns (path.Match syntax) such as "google_*,job_*". An empty
+// parameter means no filter.
+func parseToolFilter(param string) ([]string, error) {
+	var patterns []string
+	for _, p := range strings.Split(param, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid tools pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// toolAllowed reports whether a session's tool filter lets a tool through.
+// A session without a filter allows every tool.
+func (s *mcpSession) toolAllowed(name string) bool {
+	if len(s.tools) == 0 {
+		return true
+	}
+	for _, p := range s.tools {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// handleSessionRequest runs a request for the session: within its context,
+// if it has one, and narrowed by its tool filter
+func (s *MCPHTTPServer) handleSessionRequest(session *mcpSession, req MCPRequest) MCPResponse {
+	if req.Method == "tools/call" && len(session.tools) > 0 {
+		var call struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(req.Params, &call); err == nil && !session.toolAllowed(call.Name) {
+			return MCPResponse{
+				Error: &MCPError{
+					Code:    -32601,
+					Message: fmt.Sprintf("Tool %s is not available in this session", call.Name),
+				},
+			}
+		}
+	}
+
+	var resp MCPResponse
+	if session.context != "" {
+		resp = s.mcpHandler.HandleRequestWithContext(req, session.context)
+	} else {
+		resp = s.mcpHandler.HandleRequest(req)
+	}
+
+	if req.Method == "tools/list" && len(session.tools) > 0 && resp.Error == nil {
+		resp.Result = filterToolsResult(resp.Result, session.toolAllowed)
+	}
+	return resp
+}
+
+// filterToolsResult drops the tools a session's filter excludes from a
+// tools/list result, leaving the rest of the result (e.g. nextCursor) as is
+func filterToolsResult(result json.RawMessage, allowed func(string) bool) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(result, &fields); err != nil {
+		return result
+	}
+	var tools []json.RawMessage
+	if err := json.Unmarshal(fields["tools"], &tools); err != nil {
+		return result
+	}
+
+	kept := make([]json.RawMessage, 0, len(tools))
+	for _, raw := range tools {
+		var tool struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(raw, &tool) == nil && allowed(tool.Name) {
+			kept = append(kept, raw)
+		}
+	}
+	fields["tools"], _ = json.Marshal(kept)
+
+	filtered, err := json.Marshal(fields)
+	if err != nil {
+		return result
+	}
+	return filtered
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseToolFilter(t *testing.T) {
+	tests := []struct {
+		param   string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{" , ", "", false},
+		{"google_*", "google_*", false},
+		{"google_*, job_list ,", "google_*|job_list", false},
+		{"file_[ab]*", "file_[ab]*", false},
+		{"google_[", "", true},
+		{"job_*,bad\\", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseToolFilter(tt.param)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: err = %v, wantErr %v", tt.param, err, tt.wantErr)
+			continue
+		}
+		if strings.Join(got, "|") != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.param, got, tt.want)
+		}
+	}
+}
+
+func TestToolAllowed(t *testing.T) {
+	tests := []struct {
+		filter []string
+		tool   string
+		want   bool
+	}{
+		{nil, "anything", true},
+		{[]string{"google_*"}, "google_gmail_search", true},
+		{[]string{"google_*"}, "job_list", false},
+		{[]string{"google_*", "job_list"}, "job_list", true},
+		{[]string{"job_list"}, "job_list_all", false},
+		{[]string{"file_?"}, "file_a", true},
+	}
+	for _, tt := range tests {
+		s := &mcpSession{tools: tt.filter}
+		if got := s.toolAllowed(tt.tool); got != tt.want {
+			t.Errorf("filter %q, tool %q: got %v, want %v", tt.filter, tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestFilterToolsResult(t *testing.T) {
+	result := json.RawMessage(`{"tools":[{"name":"google_search","inputSchema":{}},{"name":"job_list"},{"name":"google_send"}],"nextCursor":"page-2"}`)
+	allowed := (&mcpSession{tools: []string{"google_*"}}).toolAllowed
+
+	var got struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+		NextCursor string `json:"nextCursor"`
+	}
+	if err := json.Unmarshal(filterToolsResult(result, allowed), &got); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range got.Tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "google_search,google_send" {
+		t.Errorf("kept tools %v, want only the google ones", names)
+	}
+	if got.NextCursor != "page-2" {
+		t.Errorf("nextCursor = %q, want it kept", got.NextCursor)
+	}
+
+	// Results that aren't a tool list pass through untouched
+	for _, raw := range []string{`"text"`, `{"tools":"none"}`} {
+		if out := filterToolsResult(json.RawMessage(raw), allowed); string(out) != raw {
+			t.Errorf("%s: got %s, want it unchanged", raw, out)
+		}
+	}
+}
+
+func TestToolFilterSession(t *testing.T) {
+	handler := &fakeMCPHandler{result: json.RawMessage(`{"tools":[{"name":"google_search"},{"name":"job_list"}]}`)}
+	s := NewMCPHTTPServer(nil, handler, 0, 0)
+
+	w := postMCP(s, "?tools=google_[", "", "", initializeRequest)
+	var resp MCPResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Code != -32602 || w.Header().Get("MCP-Session-Id") != "" {
+		t.Errorf("expected an invalid glob to be rejected with -32602, got %s", w.Body)
+	}
+
+	w = postMCP(s, "?tools=google_*", "", "", initializeRequest)
+	sessionID := w.Header().Get("MCP-Session-Id")
+	handler.contexts = nil
+
+	w = postMCP(s, "", "", sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"job_list","arguments":{}}}`)
+	resp = MCPResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Code != -32601 || !strings.Contains(resp.Error.Message, "not available in this session") {
+		t.Errorf("expected a call outside the filter to be refused, got %s", w.Body)
+	}
+	if len(handler.contexts) != 0 {
+		t.Error("a refused call should not reach the handler")
+	}
+
+	w = postMCP(s, "", "", sessionID, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+	if body := w.Body.String(); !strings.Contains(body, "google_search") || strings.Contains(body, "job_list") {
+		t.Errorf("expected tools/list narrowed to the filter, got %s", body)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/acp"
 	"github.com/diane-assistant/diane/internal/db"
 	"github.com/diane-assistant/diane/internal/models"
 	"github.com/diane-assistant/diane/internal/store"
@@ -62,8 +63,12 @@ type ProviderResponse struct {
 	AuthType   string         `json:"auth_type"`
 	AuthConfig map[string]any `json:"auth_config,omitempty"`
 	Config     map[string]any `json:"config"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
+	// DefaultModel and Params are the config's "model" and "params",
+	// which Diane applies to calls through the provider
+	DefaultModel string         `json:"default_model,omitempty"`
+	Params       map[string]any `json:"params,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 }
 
 // CreateProviderRequest is the request format for creating a provider
@@ -83,6 +88,11 @@ type UpdateProviderRequest struct {
 	IsDefault  *bool           `json:"is_default,omitempty"`
 	AuthConfig *map[string]any `json:"auth_config,omitempty"`
 	Config     *map[string]any `json:"config,omitempty"`
+	// DefaultModel replaces the default model; "" clears it
+	DefaultModel *string `json:"default_model,omitempty"`
+	// Params is merged into the default call parameters; a null value
+	// removes that parameter
+	Params map[string]any `json:"params,omitempty"`
 }
 
 func providerToResponse(p *db.Provider, maskSecrets bool) ProviderResponse {
@@ -105,17 +115,19 @@ func providerToResponse(p *db.Provider, maskSecrets bool) ProviderResponse {
 	}
 
 	return ProviderResponse{
-		ID:         p.ID,
-		Name:       p.Name,
-		Type:       string(p.Type),
-		Service:    p.Service,
-		Enabled:    p.Enabled,
-		IsDefault:  p.IsDefault,
-		AuthType:   string(p.AuthType),
-		AuthConfig: authConfig,
-		Config:     p.Config,
-		CreatedAt:  p.CreatedAt,
-		UpdatedAt:  p.UpdatedAt,
+		ID:           p.ID,
+		Name:         p.Name,
+		Type:         string(p.Type),
+		Service:      p.Service,
+		Enabled:      p.Enabled,
+		IsDefault:    p.IsDefault,
+		AuthType:     string(p.AuthType),
+		AuthConfig:   authConfig,
+		Config:       p.Config,
+		DefaultModel: p.DefaultModel(),
+		Params:       p.Params(),
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
 	}
 }
 
@@ -347,6 +359,9 @@ func (api *ProvidersAPI) updateProvider(w http.ResponseWriter, r *http.Request,
 	if req.Config != nil {
 		provider.Config = *req.Config
 	}
+	if req.DefaultModel != nil || req.Params != nil {
+		provider.SetDefaults(req.DefaultModel, req.Params)
+	}
 
 	if err := api.providers.UpdateProvider(provider); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -642,6 +657,31 @@ func (api *ProvidersAPI) testOllama(ctx context.Context, provider *db.Provider)
 // LLM Provider Testing
 // =============================================================================
 
+// vertexParamNames maps provider params to Gemini generationConfig fields
+var vertexParamNames = map[string]string{
+	"max_tokens":  "maxOutputTokens",
+	"top_p":       "topP",
+	"top_k":       "topK",
+	"temperature": "temperature",
+}
+
+// vertexGenerationConfig overlays a provider's params on base. Params are
+// named as elsewhere in Diane (max_tokens, top_p, ...); any other name is
+// passed through as a generationConfig field.
+func vertexGenerationConfig(base, params map[string]any) map[string]any {
+	config := make(map[string]any, len(base)+len(params))
+	for k, v := range base {
+		config[k] = v
+	}
+	for k, v := range params {
+		if name, ok := vertexParamNames[k]; ok {
+			k = name
+		}
+		config[k] = v
+	}
+	return config
+}
+
 func (api *ProvidersAPI) testLLMProvider(ctx context.Context, provider *db.Provider) ProviderTestResult {
 	switch provider.Service {
 	case "vertex_ai_llm":
@@ -657,7 +697,7 @@ func (api *ProvidersAPI) testLLMProvider(ctx context.Context, provider *db.Provi
 func (api *ProvidersAPI) testVertexAILLM(ctx context.Context, provider *db.Provider) ProviderTestResult {
 	projectID := provider.GetConfigString("project_id")
 	location := provider.GetConfigString("location")
-	model := provider.GetConfigString("model")
+	model := provider.DefaultModel()
 	account := provider.GetAuthString("oauth_account")
 
 	if projectID == "" {
@@ -705,10 +745,10 @@ func (api *ProvidersAPI) testVertexAILLM(ctx context.Context, provider *db.Provi
 				},
 			},
 		},
-		"generationConfig": map[string]any{
+		"generationConfig": vertexGenerationConfig(map[string]any{
 			"maxOutputTokens": 50,
 			"temperature":     0.1,
-		},
+		}, provider.Params()),
 	}
 	reqBytes, _ := json.Marshal(reqBody)
 
@@ -1079,6 +1119,7 @@ func (api *ProvidersAPI) handleGoogleAuthStart(w http.ResponseWriter, r *http.Re
 			"https://www.googleapis.com/auth/gmail.modify",
 			"https://www.googleapis.com/auth/calendar",
 			"https://www.googleapis.com/auth/drive.readonly",
+			"https://www.googleapis.com/auth/drive.file",
 		}
 	}
 
@@ -1350,6 +1391,8 @@ type UsageRecord struct {
 	OutputTokens int       `json:"output_tokens"`
 	CachedTokens int       `json:"cached_tokens"`
 	Cost         float64   `json:"cost"`
+	RunID        string    `json:"run_id,omitempty"`
+	Partial      bool      `json:"partial,omitempty"` // usage so far of a run that hasn't reported final usage
 	CreatedAt    time.Time `json:"created_at"`
 }
 
@@ -1426,6 +1469,8 @@ func (api *ProvidersAPI) handleUsage(w http.ResponseWriter, r *http.Request) {
 			OutputTokens: u.OutputTokens,
 			CachedTokens: u.CachedTokens,
 			Cost:         u.Cost,
+			RunID:        u.RunID,
+			Partial:      u.Partial,
 			CreatedAt:    u.CreatedAt,
 		}
 		totalCost += u.Cost
@@ -1534,3 +1579,34 @@ func (api *ProvidersAPI) RecordUsage(providerID int64, service, model string, in
 	_, err := api.db.RecordUsage(usage)
 	return err
 }
+
+// AgentUsageService is the usage service for ACP agent runs. Their usage
+// is attributed to the agent by name in the model column.
+const AgentUsageService = "acp_agent"
+
+// RecordAgentUsage records the usage an ACP agent reported for a run. The
+// cost is whatever the agent reported, since its model isn't known here.
+// It is called again as a streaming run progresses and when it ends; each
+// call replaces the run's record.
+func (api *ProvidersAPI) RecordAgentUsage(run *acp.Run) error {
+	if run.Usage == nil {
+		return nil
+	}
+	metadata, _ := json.Marshal(map[string]string{
+		"run_id":     run.RunID,
+		"session_id": run.SessionID,
+		"status":     string(run.Status),
+	})
+	_, err := api.db.RecordUsage(&db.Usage{
+		Service:      AgentUsageService,
+		Model:        run.AgentName,
+		InputTokens:  run.Usage.InputTokens,
+		OutputTokens: run.Usage.OutputTokens,
+		CachedTokens: run.Usage.CachedTokens,
+		Cost:         run.Usage.Cost,
+		Metadata:     string(metadata),
+		RunID:        run.RunID,
+		Partial:      run.Usage.Partial,
+	})
+	return err
+}
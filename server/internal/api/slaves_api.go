@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/logger"
 	"github.com/diane-assistant/diane/internal/slave"
 )
 
@@ -24,6 +26,24 @@ type SlaveInfo struct {
 	ExpiresAt   string `json:"expires_at"`
 	Enabled     bool   `json:"enabled"`
 	Platform    string `json:"platform,omitempty"`
+	ServedCalls int64  `json:"served_calls"`
+}
+
+// SlaveLogs is a slave's recent log tail and status for API responses
+type SlaveLogs struct {
+	Hostname string         `json:"hostname"`
+	Status   *Status        `json:"status,omitempty"`
+	Entries  []logger.Entry `json:"entries"`
+}
+
+// SlaveTestResult is the outcome of a connectivity check against a slave
+type SlaveTestResult struct {
+	Hostname  string  `json:"hostname"`
+	Status    string  `json:"status"` // connected, unresponsive or disconnected
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+	ToolCount int     `json:"tool_count"`
+	ExpiresAt string  `json:"expires_at"`
+	Error     string  `json:"error,omitempty"`
 }
 
 // PairingRequest represents a pairing request for API responses
@@ -41,6 +61,7 @@ type PairRequestBody struct {
 	Hostname string `json:"hostname"`
 	CSR      string `json:"csr"`
 	Platform string `json:"platform"`
+	Token    string `json:"token,omitempty"` // pre-shared auto-approval token
 }
 
 // ApproveRequestBody is the request body for approving a pairing request
@@ -74,18 +95,20 @@ func (s *Server) handleSlaves(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	served := s.slaveManager.ServedCalls()
 	response := make([]SlaveInfo, 0, len(slaves))
 	for _, slave := range slaves {
 		info := SlaveInfo{
-			Hostname:   slave.HostID,
-			Status:     string(slave.Status),
-			Version:    slave.Version,
-			ToolCount:  slave.ToolCount,
-			CertSerial: slave.CertSerial,
-			Platform:   slave.Platform,
-			IssuedAt:   slave.IssuedAt.Format(time.RFC3339),
-			ExpiresAt:  slave.ExpiresAt.Format(time.RFC3339),
-			Enabled:    slave.Enabled,
+			Hostname:    slave.HostID,
+			Status:      string(slave.Status),
+			Version:     slave.Version,
+			ToolCount:   slave.ToolCount,
+			CertSerial:  slave.CertSerial,
+			Platform:    slave.Platform,
+			IssuedAt:    slave.IssuedAt.Format(time.RFC3339),
+			ExpiresAt:   slave.ExpiresAt.Format(time.RFC3339),
+			Enabled:     slave.Enabled,
+			ServedCalls: served[slave.HostID],
 		}
 
 		if slave.LastHeartbeat != nil {
@@ -177,9 +200,10 @@ func (s *Server) handlePairSlave(w http.ResponseWriter, r *http.Request) {
 	slog.Info("Pairing initiated", "hostname", req.Hostname, "code", code)
 
 	response := map[string]interface{}{
-		"success":      true,
-		"message":      "Pairing initiated",
-		"pairing_code": code,
+		"success":       true,
+		"message":       "Pairing initiated",
+		"pairing_code":  code,
+		"auto_approved": s.slaveManager.GetPairingService().AutoApprove(req.Hostname, code, req.Token),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -578,6 +602,131 @@ func (s *Server) handleSlaveUpgrade(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// handleSlaveLogs handles GET /api/slaves/logs/{hostname}?limit=N&level=L
+func (s *Server) handleSlaveLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract hostname from path: /api/slaves/logs/{hostname}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/slaves/logs/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		http.Error(w, "Hostname required", http.StatusBadRequest)
+		return
+	}
+
+	hostname := parts[0]
+
+	if s.slaveManager == nil {
+		http.Error(w, "Slave manager not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	level := r.URL.Query().Get("level")
+	if _, err := logger.ParseLevel(level); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logs, err := s.slaveManager.GetSlaveLogs(hostname, limit, level)
+	if err != nil {
+		slog.Warn("Failed to fetch slave logs", "hostname", hostname, "error", err)
+		http.Error(w, fmt.Sprintf("Failed to fetch slave logs: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	response := SlaveLogs{
+		Hostname: hostname,
+		Entries:  logs.Entries,
+	}
+	if len(logs.Status) > 0 {
+		var status Status
+		if err := json.Unmarshal(logs.Status, &status); err == nil {
+			response.Status = &status
+		}
+	}
+	if response.Entries == nil {
+		response.Entries = []logger.Entry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleSlaveTest handles GET /api/slaves/test/{hostname} - ping a slave
+// over its existing connection
+func (s *Server) handleSlaveTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract hostname from path: /api/slaves/test/{hostname}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/slaves/test/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		http.Error(w, "Hostname required", http.StatusBadRequest)
+		return
+	}
+
+	hostname := parts[0]
+
+	if s.slaveManager == nil {
+		http.Error(w, "Slave manager not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	slaves, err := s.slaveManager.GetRegistry().GetAllSlaves()
+	if err != nil {
+		slog.Error("Failed to list slaves", "error", err)
+		http.Error(w, "Failed to retrieve slave info", http.StatusInternalServerError)
+		return
+	}
+
+	var slaveInfo *slave.SlaveInfo
+	for _, s := range slaves {
+		if s.HostID == hostname {
+			slaveInfo = s
+			break
+		}
+	}
+
+	if slaveInfo == nil {
+		http.Error(w, "Slave not found", http.StatusNotFound)
+		return
+	}
+
+	result := SlaveTestResult{
+		Hostname:  hostname,
+		Status:    "disconnected",
+		ToolCount: slaveInfo.ToolCount,
+		ExpiresAt: slaveInfo.ExpiresAt.Format(time.RFC3339),
+	}
+
+	if s.slaveManager.GetRegistry().IsConnected(hostname) {
+		latency, err := s.slaveManager.PingSlave(hostname)
+		if err != nil {
+			result.Status = "unresponsive"
+			result.Error = err.Error()
+		} else {
+			result.Status = "connected"
+			result.LatencyMs = float64(latency.Microseconds()) / 1000
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
 // RegisterSlaveRoutes registers all slave management routes
 func RegisterSlaveRoutes(mux *http.ServeMux, server *Server) {
 	mux.HandleFunc("/slaves", server.handleSlaves)
@@ -591,6 +740,8 @@ func RegisterSlaveRoutes(mux *http.ServeMux, server *Server) {
 	mux.HandleFunc("/slaves/health", server.handleSlaveHealth)
 	mux.HandleFunc("/slaves/restart/", server.handleSlaveRestart)
 	mux.HandleFunc("/slaves/upgrade/", server.handleSlaveUpgrade)
+	mux.HandleFunc("/slaves/logs/", server.handleSlaveLogs)
+	mux.HandleFunc("/slaves/test/", server.handleSlaveTest)
 	mux.HandleFunc("/slaves/", server.handleSlaveAction)
 }
 
@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -21,7 +24,7 @@ func newAgentsCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to list agents: %w", err)
 			}
 
-			if tryJSON(cmd, agents) {
+			if tryOutput(cmd, agents) {
 				return nil
 			}
 
@@ -65,8 +68,10 @@ func newAgentCmd(client *api.Client) *cobra.Command {
 	agentCmd.AddCommand(newAgentDisableCmd(client))
 	agentCmd.AddCommand(newAgentTestCmd(client))
 	agentCmd.AddCommand(newAgentRunCmd(client))
+	agentCmd.AddCommand(newAgentChatCmd(client))
 	agentCmd.AddCommand(newAgentInfoCmd(client))
 	agentCmd.AddCommand(newAgentLogsCmd(client))
+	agentCmd.AddCommand(newAgentInstallBinaryCmd(client))
 
 	return agentCmd
 }
@@ -174,7 +179,7 @@ func newAgentTestCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to test agent: %w", err)
 			}
 
-			if tryJSON(cmd, result) {
+			if tryOutput(cmd, result) {
 				return nil
 			}
 
@@ -213,23 +218,38 @@ func newAgentTestCmd(client *api.Client) *cobra.Command {
 	}
 }
 
+// agentRunGrace is how much longer than the run timeout the CLI waits for
+// the daemon's response
+const agentRunGrace = 30 * time.Second
+
 func newAgentRunCmd(client *api.Client) *cobra.Command {
-	return &cobra.Command{
-		Use:   "run <name> <prompt>",
-		Short: "Run a prompt against an ACP agent",
-		Args:  cobra.ExactArgs(2),
+	cmd := &cobra.Command{
+		Use:               "run <name> <prompt>",
+		Short:             "Run a prompt against an ACP agent",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeFirstArg(agentNames(client)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			prompt := args[1]
 
-			// Use a longer timeout for agent runs
-			runClient := api.NewClientWithTimeout(5 * time.Minute)
-			run, err := runClient.RunAgent(name, prompt, "")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			if timeout <= 0 {
+				timeout = acp.DefaultRunTimeout
+			} else if timeout < time.Second {
+				// The daemon takes whole seconds; anything shorter would
+				// be sent as 0 and silently fall back to its default
+				return fmt.Errorf("--timeout must be at least 1s, got %s", timeout)
+			}
+
+			// The daemon enforces the deadline on the agent itself; wait a
+			// little longer so its timeout result reaches us
+			runClient := client.WithTimeout(timeout + agentRunGrace)
+			run, err := runClient.RunAgent(name, prompt, "", timeout)
 			if err != nil {
 				return fmt.Errorf("failed to run agent: %w", err)
 			}
 
-			if tryJSON(cmd, run) {
+			if tryOutput(cmd, run) {
 				return nil
 			}
 
@@ -249,6 +269,36 @@ func newAgentRunCmd(client *api.Client) *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().Duration("timeout", acp.DefaultRunTimeout, "Maximum time for the agent run, e.g. 30s or 20m")
+
+	return cmd
+}
+
+func newAgentInstallBinaryCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install-binary <gallery-id>",
+		Short: "Install a gallery agent on this machine",
+		Long: `Install a gallery agent ahead of time, streaming the install command's
+output. NPX agents are installed globally with npm install -g; binary
+agents point to their download instead. Nothing runs if the agent is
+already installed, and otherwise the exact command is shown and must be
+confirmed unless --yes is given. Use 'diane gallery install --run-install'
+to configure the agent and install it in one step.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info, err := client.GetGalleryAgent(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get gallery agent: %w", err)
+			}
+			yes, _ := cmd.Flags().GetBool("yes")
+			return runInstallCmd(cmd, info, yes)
+		},
+	}
+
+	cmd.Flags().BoolP("yes", "y", false, "Run the install command without asking for confirmation")
+
+	return cmd
 }
 
 func newAgentInfoCmd(client *api.Client) *cobra.Command {
@@ -264,7 +314,7 @@ func newAgentInfoCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get agent: %w", err)
 			}
 
-			if tryJSON(cmd, agent) {
+			if tryOutput(cmd, agent) {
 				return nil
 			}
 
@@ -311,9 +361,19 @@ func newAgentLogsCmd(client *api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "logs [name]",
 		Short: "Show agent communication logs",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Show agent communication logs, newest first.
+
+With --export, the selected logs are written to a JSON file with their full
+request and response content instead, for sharing with an agent's
+maintainer. Add --redact to mask tokens, keys and passwords in the export.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			limit, _ := cmd.Flags().GetInt("limit")
+			exportPath, _ := cmd.Flags().GetString("export")
+			redact, _ := cmd.Flags().GetBool("redact")
+			if redact && exportPath == "" {
+				return fmt.Errorf("--redact requires --export")
+			}
 
 			agentName := ""
 			if len(args) > 0 {
@@ -325,7 +385,22 @@ func newAgentLogsCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get agent logs: %w", err)
 			}
 
-			if tryJSON(cmd, logs) {
+			if exportPath != "" {
+				if redact {
+					logs = redactAgentLogs(logs)
+				}
+				data, err := json.MarshalIndent(logs, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to encode agent logs: %w", err)
+				}
+				if err := os.WriteFile(exportPath, append(data, '\n'), 0600); err != nil {
+					return fmt.Errorf("failed to write %s: %w", exportPath, err)
+				}
+				PrintSuccess(fmt.Sprintf("Exported %d agent logs to %s", len(logs), exportPath))
+				return nil
+			}
+
+			if tryOutput(cmd, logs) {
 				return nil
 			}
 
@@ -373,6 +448,47 @@ func newAgentLogsCmd(client *api.Client) *cobra.Command {
 	}
 
 	cmd.Flags().IntP("limit", "n", 50, "Maximum number of log entries to show")
+	cmd.Flags().String("export", "", "Write the logs with full content to this JSON file")
+	cmd.Flags().Bool("redact", false, "Mask secrets in exported content (requires --export)")
 
 	return cmd
 }
+
+// secretPatterns match secrets that commonly turn up in agent payloads. The
+// first group of each match is kept and the rest replaced by redactedValue.
+var secretPatterns = []*regexp.Regexp{
+	// "api_key": "value", token=value, password: value
+	regexp.MustCompile(`(?i)("?[\w-]*(?:api[_-]?key|token|secret|password|passwd|credential)[\w-]*"?\s*[:=]\s*"?)[^\s",}&]+`),
+	// Authorization: Bearer value
+	regexp.MustCompile(`(?i)(\b(?:bearer|basic)\s+)[A-Za-z0-9._~+/=-]+`),
+	// Well-known key formats (OpenAI/Anthropic, GitHub, Slack, AWS)
+	regexp.MustCompile(`()\b(?:sk-[A-Za-z0-9_-]{16,}|gh[pousr]_[A-Za-z0-9]{20,}|xox[abprs]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16})\b`),
+}
+
+const redactedValue = "[REDACTED]"
+
+// redactSecrets masks anything in s that looks like a secret
+func redactSecrets(s string) string {
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllString(s, "${1}"+redactedValue)
+	}
+	return s
+}
+
+// redactAgentLogs returns a copy of logs with secrets masked in their
+// content and errors
+func redactAgentLogs(logs []api.AgentLog) []api.AgentLog {
+	redacted := make([]api.AgentLog, len(logs))
+	for i, l := range logs {
+		if l.Content != nil {
+			c := redactSecrets(*l.Content)
+			l.Content = &c
+		}
+		if l.Error != nil {
+			e := redactSecrets(*l.Error)
+			l.Error = &e
+		}
+		redacted[i] = l
+	}
+	return redacted
+}
@@ -3,6 +3,8 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -50,9 +52,51 @@ func newAuthCmd(client *api.Client) *cobra.Command {
 		},
 	}
 
+	// set-token subcommand
+	var tokenOpts setTokenOptions
+	setTokenCmd := &cobra.Command{
+		Use:   "set-token <server> [token]",
+		Short: "Store an existing access token for a server",
+		Long: titleStyle.Render("Set Token") + `
+  Store an access token you already have (for example a personal access
+  token) instead of going through the device flow, then restart the server
+  to use it. If the token is omitted it is read from stdin, which keeps it
+  out of your shell history.`,
+		Example: `  diane auth set-token github-server ghp_xxx
+  pass show github/pat | diane auth set-token github-server --expires-in 2160h`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				tokenOpts.accessToken = args[1]
+			} else {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read token from stdin: %w", err)
+				}
+				tokenOpts.accessToken = string(data)
+			}
+			return oauthSetToken(cmd, client, args[0], tokenOpts)
+		},
+	}
+	setTokenCmd.Flags().StringVar(&tokenOpts.refreshToken, "refresh-token", "", "Refresh token to store alongside the access token")
+	setTokenCmd.Flags().StringVar(&tokenOpts.tokenType, "token-type", "", "Token type used in the Authorization header (default Bearer)")
+	setTokenCmd.Flags().DurationVar(&tokenOpts.expiresIn, "expires-in", 0, "How long until the token expires (default: never)")
+
+	// refresh subcommand
+	refreshCmd := &cobra.Command{
+		Use:   "refresh <server>",
+		Short: "Refresh a server's token now using its stored refresh token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return oauthRefresh(cmd, client, args[0])
+		},
+	}
+
 	cmd.AddCommand(loginCmd)
 	cmd.AddCommand(statusCmd)
 	cmd.AddCommand(logoutCmd)
+	cmd.AddCommand(setTokenCmd)
+	cmd.AddCommand(refreshCmd)
 
 	return cmd
 }
@@ -63,7 +107,7 @@ func listOAuthServers(cmd *cobra.Command, client *api.Client) error {
 		return fmt.Errorf("failed to list OAuth servers: %w", err)
 	}
 
-	if tryJSON(cmd, servers) {
+	if tryOutput(cmd, servers) {
 		return nil
 	}
 
@@ -111,11 +155,18 @@ func oauthLogin(cmd *cobra.Command, client *api.Client, serverName string) error
 	fmt.Printf("  %s\n\n", urlStyle.Render(deviceInfo.VerificationURI))
 	fmt.Printf("  Enter this code: %s\n\n", codeStyle.Render(deviceInfo.UserCode))
 
-	fmt.Printf("  Waiting for authorization...")
+	expiry := time.Duration(deviceInfo.ExpiresIn) * time.Second
+	if expiry <= 0 {
+		expiry = defaultDeviceCodeExpiry
+	}
+	stopCountdown := showCountdown("Waiting for authorization...", time.Now().Add(expiry))
 
-	// Poll with a long-timeout client
-	pollClient := api.NewClientWithTimeout(10 * time.Minute)
-	if err := pollClient.PollOAuthToken(serverName, deviceInfo.DeviceCode, deviceInfo.Interval); err != nil {
+	// Poll with a client that outlasts the device code; the daemon gives up
+	// once the code expires
+	pollClient := api.NewClientWithTimeout(expiry + time.Minute)
+	err = pollClient.PollOAuthToken(serverName, deviceInfo.DeviceCode, deviceInfo.Interval, deviceInfo.ExpiresIn)
+	stopCountdown()
+	if err != nil {
 		fmt.Println()
 		PrintError(fmt.Sprintf("Authentication failed: %v", err))
 		return nil
@@ -126,6 +177,38 @@ func oauthLogin(cmd *cobra.Command, client *api.Client, serverName string) error
 	return nil
 }
 
+// defaultDeviceCodeExpiry matches the daemon's polling limit for providers
+// that don't say how long their device codes last
+const defaultDeviceCodeExpiry = 15 * time.Minute
+
+// showCountdown prints msg followed by the time left until deadline,
+// rewriting the line every second until the returned stop is called
+func showCountdown(msg string, deadline time.Time) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			left := time.Until(deadline).Round(time.Second)
+			if left < 0 {
+				left = 0
+			}
+			fmt.Printf("\r  %s %d:%02d remaining ", msg, int(left.Minutes()), int(left.Seconds())%60)
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
 func oauthStatus(cmd *cobra.Command, client *api.Client, serverName string) error {
 	status, err := client.GetOAuthStatus(serverName)
 	if err != nil {
@@ -149,3 +232,66 @@ func oauthLogout(client *api.Client, serverName string) error {
 	PrintSuccess(fmt.Sprintf("Logged out from '%s'", serverName))
 	return nil
 }
+
+type setTokenOptions struct {
+	accessToken  string
+	refreshToken string
+	tokenType    string
+	expiresIn    time.Duration
+}
+
+func oauthSetToken(cmd *cobra.Command, client *api.Client, serverName string, opts setTokenOptions) error {
+	token := api.OAuthTokenImport{
+		AccessToken:  strings.TrimSpace(opts.accessToken),
+		TokenType:    opts.tokenType,
+		RefreshToken: strings.TrimSpace(opts.refreshToken),
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("no access token given")
+	}
+	if opts.expiresIn < 0 {
+		return fmt.Errorf("--expires-in must not be negative")
+	}
+	if opts.expiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(opts.expiresIn)
+	}
+
+	result, err := client.SetOAuthToken(serverName, token)
+	if err != nil {
+		return fmt.Errorf("failed to set token: %w", err)
+	}
+
+	if tryOutput(cmd, result) {
+		return nil
+	}
+
+	printTokenResult(result, fmt.Sprintf("Stored token for '%s' and restarted it", serverName))
+	return nil
+}
+
+func oauthRefresh(cmd *cobra.Command, client *api.Client, serverName string) error {
+	result, err := client.RefreshOAuthToken(serverName)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	if tryOutput(cmd, result) {
+		return nil
+	}
+
+	printTokenResult(result, fmt.Sprintf("Refreshed token for '%s' and restarted it", serverName))
+	return nil
+}
+
+// printTokenResult reports a stored or refreshed token, with its expiry, or
+// the warning if the server didn't start with it
+func printTokenResult(result *api.OAuthTokenResult, msg string) {
+	if result.Warning != "" {
+		PrintWarning(result.Warning)
+		return
+	}
+	if result.ExpiresAt != nil {
+		msg += fmt.Sprintf(" (expires %s)", result.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	}
+	PrintSuccess(msg)
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/diane-assistant/diane/internal/acp"
+	"github.com/diane-assistant/diane/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// chatTurn is one exchange kept for /save
+type chatTurn struct {
+	Prompt   string
+	Response string
+}
+
+func newAgentChatCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chat <name>",
+		Short: "Chat with an agent interactively in a multi-turn session",
+		Long: `Start a session with an agent and send each line you type as a prompt,
+streaming the agent's reply. The session is kept across turns until EOF
+(Ctrl-D) or /exit.
+
+Commands:
+  /reset        Close the session and start a new one
+  /save <file>  Write the transcript so far to a Markdown file
+  /exit         Close the session and quit`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agentName := args[0]
+			workDir, _ := cmd.Flags().GetString("workdir")
+			title, _ := cmd.Flags().GetString("title")
+
+			// Spawning can be slow, and each turn may run up to the daemon's
+			// run timeout
+			startClient := client.WithTimeout(60 * time.Second)
+			promptClient := client.WithTimeout(acp.DefaultRunTimeout + agentRunGrace)
+
+			info, err := startClient.StartSession(agentName, workDir, title)
+			if err != nil {
+				return fmt.Errorf("failed to start session: %w", err)
+			}
+			sessionID := info.SessionID
+			defer func() {
+				client.CloseSession(agentName, sessionID)
+			}()
+
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			promptStyle := lipgloss.NewStyle().Foreground(highlight).Bold(true)
+
+			PrintSuccess(fmt.Sprintf("Chatting with %s (session %s)", agentName, sessionID))
+			fmt.Println(dimStyle.Render("  /reset, /save <file>, /exit or Ctrl-D to quit"))
+
+			var transcript []chatTurn
+			scanner := bufio.NewScanner(cmd.InOrStdin())
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for {
+				fmt.Print(promptStyle.Render("> "))
+				if !scanner.Scan() {
+					fmt.Println()
+					break
+				}
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+
+				switch {
+				case line == "/exit" || line == "/quit":
+					return nil
+				case line == "/reset":
+					client.CloseSession(agentName, sessionID)
+					info, err := startClient.StartSession(agentName, workDir, title)
+					if err != nil {
+						return fmt.Errorf("failed to start session: %w", err)
+					}
+					sessionID = info.SessionID
+					transcript = nil
+					PrintSuccess(fmt.Sprintf("New session %s", sessionID))
+					continue
+				case line == "/save" || strings.HasPrefix(line, "/save "):
+					path := strings.TrimSpace(strings.TrimPrefix(line, "/save"))
+					if path == "" {
+						PrintError("Usage: /save <file>")
+						continue
+					}
+					if err := saveChatTranscript(path, agentName, transcript); err != nil {
+						PrintError(fmt.Sprintf("Failed to save transcript: %v", err))
+						continue
+					}
+					PrintSuccess(fmt.Sprintf("Saved %d turns to %s", len(transcript), path))
+					continue
+				case strings.HasPrefix(line, "/"):
+					PrintWarning(fmt.Sprintf("Unknown command: %s", line))
+					continue
+				}
+
+				var response strings.Builder
+				run, err := promptClient.PromptSessionStream(agentName, sessionID, line, func(text string) {
+					response.WriteString(text)
+					fmt.Print(text)
+				})
+				if response.Len() > 0 && !strings.HasSuffix(response.String(), "\n") {
+					fmt.Println()
+				}
+				if err != nil {
+					PrintError(err.Error())
+					continue
+				}
+				if run.Error != nil {
+					PrintError(fmt.Sprintf("Session error: %s", run.Error.Message))
+				}
+				transcript = append(transcript, chatTurn{Prompt: line, Response: response.String()})
+			}
+			return scanner.Err()
+		},
+	}
+
+	cmd.Flags().StringP("workdir", "w", "", "Working directory for the session")
+	cmd.Flags().StringP("title", "t", "", "Optional session title")
+
+	return cmd
+}
+
+// saveChatTranscript writes the chat so far as Markdown
+func saveChatTranscript(path, agentName string, transcript []chatTurn) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Chat with %s\n\n", agentName)
+	for _, turn := range transcript {
+		fmt.Fprintf(&sb, "## You\n\n%s\n\n## %s\n\n%s\n\n", turn.Prompt, agentName, strings.TrimSpace(turn.Response))
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
@@ -3,19 +3,25 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/diane-assistant/diane/internal/acp"
 	"github.com/diane-assistant/diane/internal/api"
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/internal/logger"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // ---------------------------------------------------------------------------
@@ -218,6 +224,8 @@ func fixtureInstallInfo(id string) acp.InstallInfo {
 		InstallType: "npx",
 		InstallCmd:  "npx @google/codey",
 		Available:   true,
+		SetupCmd:    "npm install -g @google/codey",
+		Installed:   true,
 	}
 }
 
@@ -333,8 +341,26 @@ func newMockServer(overrides map[string]http.HandlerFunc) *httptest.Server {
 				json.NewEncoder(w).Encode(map[string]string{"status": "restarted"})
 				return
 			}
+			// Catch /mcp-servers/{name}/enable and /disable
+			if strings.HasSuffix(r.URL.Path, "/enable") || strings.HasSuffix(r.URL.Path, "/disable") {
+				jsonOK(w, map[string]interface{}{"status": "ok"})
+				return
+			}
 			w.WriteHeader(http.StatusNotFound)
 		},
+		"/mcp-servers/restart": func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				OnlyFailed bool `json:"only_failed"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			results := []api.MCPServerRestartResult{
+				{Name: "broken-server", Success: true},
+			}
+			if !body.OnlyFailed {
+				results = append([]api.MCPServerRestartResult{{Name: "brave-search", Success: true}}, results...)
+			}
+			jsonOK(w, results)
+		},
 		"/mcp-servers-config": func(w http.ResponseWriter, r *http.Request) {
 			switch r.Method {
 			case http.MethodGet:
@@ -649,6 +675,51 @@ func TestHealthCommand_ServerDown(t *testing.T) {
 	}
 }
 
+func TestExitCode_DaemonUnreachable(t *testing.T) {
+	ts := newMockServer(nil)
+	ts.Close()
+
+	root := newTestRootCmd(ts)
+	_, err := executeCmd(root, "reload")
+	if !errors.Is(err, api.ErrDaemonUnreachable) {
+		t.Fatalf("expected ErrDaemonUnreachable, got: %v", err)
+	}
+	if code := ExitCode(err); code != ExitDaemonUnreachable {
+		t.Errorf("expected exit code %d, got %d", ExitDaemonUnreachable, code)
+	}
+}
+
+func TestExitCode_FromHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   int
+	}{
+		{http.StatusNotFound, ExitNotFound},
+		{http.StatusConflict, ExitConflict},
+		{http.StatusUnauthorized, ExitUnauthorized},
+		{http.StatusForbidden, ExitUnauthorized},
+		{http.StatusInternalServerError, ExitError},
+	}
+	for _, tt := range tests {
+		ts := newMockServer(map[string]http.HandlerFunc{
+			"/mcp-servers-config/7": func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			},
+		})
+
+		root := newTestRootCmd(ts)
+		_, err := executeCmd(root, "mcp", "delete", "7")
+		ts.Close()
+
+		if err == nil {
+			t.Fatalf("status %d: expected error", tt.status)
+		}
+		if code := ExitCode(err); code != tt.want {
+			t.Errorf("status %d: expected exit code %d, got %d (err: %v)", tt.status, tt.want, code, err)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Tests: Status command
 // ---------------------------------------------------------------------------
@@ -768,6 +839,43 @@ func TestStatusCommand_ServerDown(t *testing.T) {
 	}
 }
 
+func TestStatusWatch_RecoversFromUnreachableDaemon(t *testing.T) {
+	stop := make(chan struct{})
+	calls := 0
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/status": func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			close(stop)
+			jsonOK(w, fixtureStatus())
+		},
+	})
+	defer ts.Close()
+
+	out := captureStdout(func() {
+		watchStatus(newTestClient(ts), 10*time.Millisecond, stop)
+	})
+	if !strings.Contains(out, "Daemon unreachable, retrying") {
+		t.Errorf("expected unreachable notice on first refresh, got: %q", out)
+	}
+	if !strings.Contains(out, "PID") {
+		t.Errorf("expected dashboard once the daemon responds, got: %q", out)
+	}
+}
+
+func TestStatusCommand_WatchRejectsJSON(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	if _, err := executeCmd(root, "status", "--watch", "--json"); err == nil {
+		t.Fatal("expected error combining --watch with --json")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Tests: Doctor command
 // ---------------------------------------------------------------------------
@@ -841,6 +949,54 @@ func TestDoctorCommand_JSON(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Tests: --profile
+// ---------------------------------------------------------------------------
+
+func TestApplyProfileFlag(t *testing.T) {
+	t.Setenv("DIANE_PROFILE", "")
+
+	args, err := ApplyProfileFlag([]string{"diane", "--profile", "work", "status", "--", "--profile=x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(args, " ") != "diane status -- --profile=x" {
+		t.Errorf("unexpected remaining args: %v", args)
+	}
+	if os.Getenv("DIANE_PROFILE") != "work" {
+		t.Errorf("expected DIANE_PROFILE=work, got %q", os.Getenv("DIANE_PROFILE"))
+	}
+
+	args, err = ApplyProfileFlag([]string{"diane", "serve", "--profile=personal"})
+	if err != nil || len(args) != 2 || os.Getenv("DIANE_PROFILE") != "personal" {
+		t.Errorf("unexpected result for --profile=: %v, %v, %q", args, err, os.Getenv("DIANE_PROFILE"))
+	}
+
+	if _, err := ApplyProfileFlag([]string{"diane", "--profile", "../oops", "status"}); err == nil {
+		t.Error("expected an invalid profile name to be rejected")
+	}
+}
+
+func TestApplyConfigFlag(t *testing.T) {
+	t.Setenv("DIANE_CONFIG", "")
+
+	args, err := ApplyConfigFlag([]string{"diane", "serve", "--config", "alt.json", "--", "--config=x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(args, " ") != "diane serve -- --config=x" {
+		t.Errorf("unexpected remaining args: %v", args)
+	}
+	if got := os.Getenv("DIANE_CONFIG"); !filepath.IsAbs(got) || filepath.Base(got) != "alt.json" {
+		t.Errorf("expected an absolute DIANE_CONFIG, got %q", got)
+	}
+
+	args, err = ApplyConfigFlag([]string{"diane", "--config=/etc/diane.json", "status"})
+	if err != nil || len(args) != 2 || os.Getenv("DIANE_CONFIG") != "/etc/diane.json" {
+		t.Errorf("unexpected result for --config=: %v, %v, %q", args, err, os.Getenv("DIANE_CONFIG"))
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Tests: MCP Servers command
 // ---------------------------------------------------------------------------
@@ -886,6 +1042,58 @@ func TestMCPServersCommand_JSON(t *testing.T) {
 	}
 }
 
+func TestMCPServersCommand_OutputJSON(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "mcp-servers", "-o", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var servers []api.MCPServerStatus
+	if err := json.Unmarshal([]byte(out), &servers); err != nil {
+		t.Fatalf("expected valid JSON, got parse error: %v\nOutput: %q", err, out)
+	}
+}
+
+func TestMCPServersCommand_OutputYAML(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "mcp-servers", "--output", "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var servers []map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out), &servers); err != nil {
+		t.Fatalf("expected valid YAML, got parse error: %v\nOutput: %q", err, out)
+	}
+	if len(servers) != 4 {
+		t.Fatalf("expected 4 servers, got %d", len(servers))
+	}
+	// Keys follow the JSON field names
+	if servers[0]["name"] != "filesystem" {
+		t.Errorf("expected first server name 'filesystem', got: %v", servers[0]["name"])
+	}
+	if strings.Contains(out, "{") {
+		t.Errorf("expected block-style YAML, got: %q", out)
+	}
+}
+
+func TestOutputFlag_Invalid(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	if _, err := executeCmd(root, "mcp-servers", "--output", "xml"); err == nil {
+		t.Fatal("expected error for unknown output format")
+	}
+}
+
 func TestMCPServersCommand_Empty(t *testing.T) {
 	ts := newMockServer(map[string]http.HandlerFunc{
 		"/mcp-servers": func(w http.ResponseWriter, r *http.Request) {
@@ -904,6 +1112,33 @@ func TestMCPServersCommand_Empty(t *testing.T) {
 	}
 }
 
+func TestMCPServersCommand_Degraded(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.MCPServerStatus{{
+				Name:      "file_registry",
+				Enabled:   true,
+				Connected: true,
+				Builtin:   true,
+				Degraded:  true,
+				Error:     "file_registry backend unreachable: connection refused",
+			}})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "mcp-servers")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"degraded", "file_registry backend unreachable"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got: %q", want, out)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Tests: MCP add / add-stdio commands
 // ---------------------------------------------------------------------------
@@ -957,239 +1192,388 @@ func TestMCPAddCommand_WithHeaders(t *testing.T) {
 	}
 }
 
-func TestMCPAddStdioCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestMCPAddCommand_OAuth(t *testing.T) {
+	var receivedReq api.CreateMCPServerRequest
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers-config": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&receivedReq)
+			jsonStatus(w, http.StatusCreated, api.MCPServerResponse{ID: 5, Name: receivedReq.Name, Type: receivedReq.Type})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "mcp", "add-stdio", "my-stdio-srv", "npx", "--arg", "-y", "--arg", "@modelcontextprotocol/server-filesystem")
+	out, err := executeCmd(root, "mcp", "add", "remote-srv", "https://mcp.example.com/sse",
+		"--type", "sse",
+		"--oauth-client-id", "abc123",
+		"--oauth-device-url", "https://auth.example.com/device",
+		"--oauth-token-url", "https://auth.example.com/token",
+		"--oauth-scope", "mcp:read")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Added stdio server") {
-		t.Errorf("expected stdio success message, got: %q", out)
+	if receivedReq.OAuth == nil || receivedReq.OAuth.ClientID != "abc123" || receivedReq.OAuth.TokenURL != "https://auth.example.com/token" {
+		t.Fatalf("expected oauth config, got: %+v", receivedReq.OAuth)
 	}
-}
-
-func TestMCPAddCommand_MissingArgs(t *testing.T) {
-	ts := newMockServer(nil)
-	defer ts.Close()
-
-	root := newTestRootCmd(ts)
-	_, err := executeCmd(root, "mcp", "add", "only-name")
-	if err == nil {
-		t.Error("expected error for missing URL argument")
+	if !reflect.DeepEqual(receivedReq.OAuth.Scopes, []string{"mcp:read"}) {
+		t.Errorf("expected scopes [mcp:read], got: %v", receivedReq.OAuth.Scopes)
+	}
+	if !strings.Contains(out, "diane auth login remote-srv") {
+		t.Errorf("expected login hint, got: %q", out)
 	}
 }
 
-func TestMCPEditCommand(t *testing.T) {
+func TestMCPAddStdioCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "mcp", "edit", "1", "--name", "updated-srv")
+	out, err := executeCmd(root, "mcp", "add-stdio", "my-stdio-srv", "npx", "--arg", "-y", "--arg", "@modelcontextprotocol/server-filesystem")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "updated") {
-		t.Errorf("expected success message, got: %q", out)
+	if !strings.Contains(out, "Added stdio server") {
+		t.Errorf("expected stdio success message, got: %q", out)
 	}
 }
 
-func TestMCPDeleteCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestMCPAddStdioCommand_StartupTimeout(t *testing.T) {
+	var receivedReq api.CreateMCPServerRequest
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers-config": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&receivedReq)
+			jsonStatus(w, http.StatusCreated, api.MCPServerResponse{ID: 7, Name: "slow-srv", Type: "stdio"})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "mcp", "delete", "1")
+	out, err := executeCmd(root, "mcp", "add-stdio", "slow-srv", "uvx", "--startup-timeout", "90")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "deleted") {
-		t.Errorf("expected success message, got: %q", out)
+	if !strings.Contains(out, "Added stdio server") {
+		t.Errorf("expected stdio success message, got: %q", out)
+	}
+	if receivedReq.StartupTimeout != 90 {
+		t.Errorf("expected startup_timeout 90, got: %d", receivedReq.StartupTimeout)
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Reload / Restart commands
-// ---------------------------------------------------------------------------
+func TestMCPAddStdioCommand_EnvFile(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(envFile, []byte(`# credentials
+export API_KEY="sk-live-1234567890"
+REGION=eu-west-1 # inline comment
+GREETING='hello # not a comment'
+`), 0600)
 
-func TestReloadCommand(t *testing.T) {
-	ts := newMockServer(nil)
+	var receivedReq api.CreateMCPServerRequest
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers-config": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&receivedReq)
+			jsonStatus(w, http.StatusCreated, api.MCPServerResponse{ID: 8, Name: "env-srv", Type: "stdio", Env: receivedReq.Env})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "reload")
+	out, err := executeCmd(root, "mcp", "add-stdio", "env-srv", "uvx", "--env-file", envFile, "--env", "REGION=us-east-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "reloaded") {
-		t.Errorf("expected reload success message, got: %q", out)
+	want := map[string]string{"API_KEY": "sk-live-1234567890", "REGION": "us-east-1", "GREETING": "hello # not a comment"}
+	if !reflect.DeepEqual(receivedReq.Env, want) {
+		t.Errorf("expected env %v, got: %v", want, receivedReq.Env)
+	}
+	if strings.Contains(out, "sk-live-1234567890") {
+		t.Errorf("secret value leaked in output: %q", out)
+	}
+	if !strings.Contains(out, "API_KEY=sk-l****7890") {
+		t.Errorf("expected masked API_KEY in output, got: %q", out)
 	}
 }
 
-func TestReloadCommand_Failure(t *testing.T) {
-	ts := newMockServer(map[string]http.HandlerFunc{
-		"/reload": func(w http.ResponseWriter, r *http.Request) {
-			jsonStatus(w, http.StatusInternalServerError, map[string]string{"error": "config parse error"})
-		},
-	})
-	defer ts.Close()
+func TestParseEnvFile_Malformed(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(envFile, []byte("GOOD=1\nnot a pair\n"), 0600)
 
-	root := newTestRootCmd(ts)
-	_, err := executeCmd(root, "reload")
-	if err == nil {
-		t.Error("expected error for failed reload")
+	if _, err := parseEnvFile(envFile); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("expected error on line 2, got: %v", err)
 	}
 }
 
-func TestRestartCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestMCPImportCommand(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "claude_desktop_config.json")
+	os.WriteFile(config, []byte(`{"mcpServers": {
+		"filesystem": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem"], "env": {"ROOT": "/tmp"}},
+		"existing": {"command": "uvx", "args": ["mcp-server-git"]},
+		"remote": {"url": "https://mcp.example.com/sse", "headers": {"Authorization": "Bearer x"}},
+		"broken": {"args": ["nothing to run"]}
+	}}`), 0600)
+
+	created := make(map[string]api.CreateMCPServerRequest)
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers-config": func(w http.ResponseWriter, r *http.Request) {
+			var req api.CreateMCPServerRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Name == "existing" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			created[req.Name] = req
+			jsonStatus(w, http.StatusCreated, api.MCPServerResponse{ID: int64(len(created)), Name: req.Name, Type: req.Type})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "restart", "brave-search")
+	out, err := executeCmd(root, "mcp", "import", config, "--json")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "restarted") {
-		t.Errorf("expected restart success message, got: %q", out)
+	var results []mcpImportResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("failed to parse output: %v\n%s", err, out)
 	}
-	if !strings.Contains(out, "brave-search") {
-		t.Errorf("expected server name in output, got: %q", out)
+	status := make(map[string]string)
+	for _, r := range results {
+		status[r.Name] = r.Status
+	}
+	want := map[string]string{"broken": "skipped", "existing": "conflict", "filesystem": "created", "remote": "created"}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("expected %v, got %v", want, status)
+	}
+
+	fs := created["filesystem"]
+	if fs.Type != "stdio" || fs.Command != "npx" || len(fs.Args) != 2 || fs.Env["ROOT"] != "/tmp" {
+		t.Errorf("unexpected stdio request: %+v", fs)
+	}
+	if remote := created["remote"]; remote.Type != "sse" || remote.Headers["Authorization"] != "Bearer x" {
+		t.Errorf("unexpected remote request: %+v", remote)
 	}
 }
 
-func TestRestartCommand_MissingArg(t *testing.T) {
-	ts := newMockServer(nil)
-	defer ts.Close()
+func TestParseMCPImport_Shapes(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want api.CreateMCPServerRequest
+	}{
+		{
+			name: "vscode",
+			doc:  `{"servers": {"gh": {"type": "http", "url": "https://api.example.com/mcp"}}}`,
+			want: api.CreateMCPServerRequest{Name: "gh", Type: "http", URL: "https://api.example.com/mcp"},
+		},
+		{
+			name: "vscode settings",
+			doc:  `{"mcp": {"servers": {"gh": {"type": "sse", "url": "https://api.example.com/events"}}}}`,
+			want: api.CreateMCPServerRequest{Name: "gh", Type: "sse", URL: "https://api.example.com/events"},
+		},
+		{
+			name: "opencode",
+			doc:  `{"mcp": {"gh": {"type": "local", "command": ["npx", "-y", "gh-mcp"], "environment": {"TOKEN": "t"}, "enabled": false}}}`,
+			want: api.CreateMCPServerRequest{Name: "gh", Type: "stdio", Command: "npx", Args: []string{"-y", "gh-mcp"}, Env: map[string]string{"TOKEN": "t"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseMCPImport([]byte(tt.doc))
+			if err != nil || len(entries) != 1 || entries[0].Req == nil {
+				t.Fatalf("expected one importable entry, got %+v (%v)", entries, err)
+			}
+			got := *entries[0].Req
+			wantEnabled := tt.name != "opencode"
+			if got.Enabled == nil || *got.Enabled != wantEnabled {
+				t.Errorf("expected enabled=%v, got %v", wantEnabled, got.Enabled)
+			}
+			got.Enabled = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
 
-	root := newTestRootCmd(ts)
-	_, err := executeCmd(root, "restart")
-	if err == nil {
-		t.Error("expected error for missing server name argument")
+	if _, err := parseMCPImport([]byte(`{"other": {}}`)); err == nil {
+		t.Error("expected an error for a file without a servers section")
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Agents command
-// ---------------------------------------------------------------------------
-
-func TestAgentsCommand(t *testing.T) {
+func TestMCPAddCommand_MissingArgs(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agents")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if !strings.Contains(out, "ACP Agents") {
-		t.Errorf("expected title 'ACP Agents', got: %q", out)
-	}
-	for _, name := range []string{"codey", "researcher", "disabled-agent"} {
-		if !strings.Contains(out, name) {
-			t.Errorf("expected agent '%s' in output, got: %q", name, out)
-		}
+	_, err := executeCmd(root, "mcp", "add", "only-name")
+	if err == nil {
+		t.Error("expected error for missing URL argument")
 	}
 }
 
-func TestAgentsCommand_JSON(t *testing.T) {
+func TestMCPEditCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agents", "--json")
+	out, err := executeCmd(root, "mcp", "edit", "1", "--name", "updated-srv")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	var agents []acp.AgentConfig
-	if err := json.Unmarshal([]byte(out), &agents); err != nil {
-		t.Fatalf("expected valid JSON, got parse error: %v\nOutput: %q", err, out)
-	}
-	if len(agents) != 3 {
-		t.Errorf("expected 3 agents, got %d", len(agents))
+	if !strings.Contains(out, "updated") {
+		t.Errorf("expected success message, got: %q", out)
 	}
 }
 
-func TestAgentsCommand_Empty(t *testing.T) {
+func TestMCPEditCommand_ToolTimeout(t *testing.T) {
+	var receivedReq api.UpdateMCPServerRequest
 	ts := newMockServer(map[string]http.HandlerFunc{
-		"/agents": func(w http.ResponseWriter, r *http.Request) {
-			jsonOK(w, []acp.AgentConfig{})
+		"/mcp-servers-config/": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&receivedReq)
+			jsonOK(w, api.MCPServerResponse{ID: 1, Name: "slow-srv", Type: "stdio"})
 		},
 	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agents")
+	_, err := executeCmd(root, "mcp", "edit", "1", "--tool-timeout", "120")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No agents configured") {
-		t.Errorf("expected empty agents message, got: %q", out)
+	if receivedReq.ToolTimeout == nil || *receivedReq.ToolTimeout != 120 {
+		t.Errorf("expected tool_timeout 120, got: %v", receivedReq.ToolTimeout)
+	}
+	if receivedReq.Name != nil {
+		t.Errorf("expected name to be left unchanged, got: %q", *receivedReq.Name)
 	}
 }
 
-func TestAgentAddCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestMCPEditCommand_MaxConcurrency(t *testing.T) {
+	var receivedReq api.UpdateMCPServerRequest
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers-config/": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&receivedReq)
+			jsonOK(w, api.MCPServerResponse{ID: 1, Name: "fragile-srv", Type: "stdio"})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "add", "new-agent", "http://localhost:9000", "My new agent")
+	_, err := executeCmd(root, "mcp", "edit", "1", "--max-concurrency", "1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "added") {
-		t.Errorf("expected add success message, got: %q", out)
+	if receivedReq.MaxConcurrency == nil || *receivedReq.MaxConcurrency != 1 {
+		t.Errorf("expected max_concurrency 1, got: %v", receivedReq.MaxConcurrency)
+	}
+	if receivedReq.ToolTimeout != nil {
+		t.Errorf("expected tool_timeout to be left unchanged, got: %d", *receivedReq.ToolTimeout)
 	}
 }
 
-func TestAgentAddCommand_MissingArgs(t *testing.T) {
-	ts := newMockServer(nil)
+func TestMCPEditCommand_Priority(t *testing.T) {
+	var receivedReq api.UpdateMCPServerRequest
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers-config/": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&receivedReq)
+			jsonOK(w, api.MCPServerResponse{ID: 1, Name: "noisy-srv", Type: "stdio"})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	_, err := executeCmd(root, "agent", "add", "only-name")
-	if err == nil {
-		t.Error("expected error for missing URL argument")
+	if _, err := executeCmd(newTestRootCmd(ts), "mcp", "edit", "1", "--priority=-5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receivedReq.Priority == nil || *receivedReq.Priority != -5 {
+		t.Errorf("expected priority -5, got: %v", receivedReq.Priority)
 	}
 }
 
-func TestAgentRemoveCommand(t *testing.T) {
+func TestMCPReorderCommand(t *testing.T) {
+	priorities := make(map[string]int)
+	reloaded := false
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers-config": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.MCPServerResponse{
+				{ID: -1, Name: "weather", Type: "builtin"},
+				{ID: 3, Name: "weather", Type: "builtin"},
+				{ID: 4, Name: "github", Type: "http"},
+				{ID: 7, Name: "context7", Type: "stdio"},
+			})
+		},
+		"/mcp-servers-config/": func(w http.ResponseWriter, r *http.Request) {
+			var req api.UpdateMCPServerRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			priorities[strings.TrimPrefix(r.URL.Path, "/mcp-servers-config/")] = *req.Priority
+			jsonOK(w, api.MCPServerResponse{})
+		},
+		"/reload": func(w http.ResponseWriter, r *http.Request) {
+			reloaded = true
+			jsonOK(w, map[string]string{"status": "reloaded"})
+		},
+	})
+	defer ts.Close()
+
+	out, err := executeCmd(newTestRootCmd(ts), "mcp", "reorder", "context7", "github")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if priorities["7"] != 2 || priorities["4"] != 1 || !reloaded {
+		t.Errorf("unexpected priorities %v (reloaded: %v)", priorities, reloaded)
+	}
+	if !strings.Contains(out, "context7, github") {
+		t.Errorf("expected success message, got: %q", out)
+	}
+
+	priorities = make(map[string]int)
+	_, err = executeCmd(newTestRootCmd(ts), "mcp", "reorder", "github", "weather")
+	if ExitCode(err) != ExitNotFound || len(priorities) != 0 {
+		t.Errorf("expected a not-found error and no changes for a builtin server, got %v, %v", err, priorities)
+	}
+}
+
+func TestMCPDeleteCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "remove", "codey")
+	out, err := executeCmd(root, "mcp", "delete", "1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "removed") {
-		t.Errorf("expected remove success message, got: %q", out)
+	if !strings.Contains(out, "deleted") {
+		t.Errorf("expected success message, got: %q", out)
 	}
 }
 
-func TestAgentRemoveCommand_Alias(t *testing.T) {
-	ts := newMockServer(nil)
+func TestMCPDisableCommand(t *testing.T) {
+	var gotPath string
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers/": func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			jsonOK(w, map[string]interface{}{"status": "ok"})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "rm", "codey")
+	out, err := executeCmd(root, "mcp", "disable", "brave-search")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "removed") {
-		t.Errorf("expected remove success via alias, got: %q", out)
+	if gotPath != "/mcp-servers/brave-search/disable" {
+		t.Errorf("expected disable endpoint, got: %q", gotPath)
+	}
+	if !strings.Contains(out, "disabled") {
+		t.Errorf("expected disable success message, got: %q", out)
 	}
 }
 
-func TestAgentEnableCommand(t *testing.T) {
+func TestMCPEnableCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "enable", "disabled-agent")
+	out, err := executeCmd(root, "mcp", "enable", "brave-search")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1198,749 +1582,2280 @@ func TestAgentEnableCommand(t *testing.T) {
 	}
 }
 
-func TestAgentDisableCommand(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: Reload / Restart commands
+// ---------------------------------------------------------------------------
+
+func TestReloadCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "disable", "codey")
+	out, err := executeCmd(root, "reload")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "disabled") {
-		t.Errorf("expected disable success message, got: %q", out)
+	if !strings.Contains(out, "reloaded") {
+		t.Errorf("expected reload success message, got: %q", out)
 	}
 }
 
-func TestAgentTestCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestReloadCommand_Affected(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/reload": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, api.ReloadResult{Status: "reloaded", Started: []string{"context7"}, Restarted: []string{"github"}})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "test", "codey")
+	out, err := executeCmd(newTestRootCmd(ts), "reload")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Agent Test") {
-		t.Errorf("expected 'Agent Test' title, got: %q", out)
+	for _, want := range []string{"Started:", "context7", "Restarted:", "github"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got: %q", want, out)
+		}
 	}
-	if !strings.Contains(out, "connected") {
-		t.Errorf("expected status 'connected', got: %q", out)
+	if strings.Contains(out, "Stopped") {
+		t.Errorf("unaffected categories should be omitted, got: %q", out)
 	}
-	if !strings.Contains(out, "1.0.0") {
-		t.Errorf("expected version '1.0.0', got: %q", out)
+}
+
+func TestReloadCommand_Failure(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/reload": func(w http.ResponseWriter, r *http.Request) {
+			jsonStatus(w, http.StatusInternalServerError, map[string]string{"error": "config parse error"})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	_, err := executeCmd(root, "reload")
+	if err == nil {
+		t.Error("expected error for failed reload")
 	}
 }
 
-func TestAgentTestCommand_JSON(t *testing.T) {
+func TestRestartCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "test", "codey", "--json")
+	out, err := executeCmd(root, "restart", "brave-search")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	var result acp.AgentTestResult
-	if err := json.Unmarshal([]byte(out), &result); err != nil {
-		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	if !strings.Contains(out, "restarted") {
+		t.Errorf("expected restart success message, got: %q", out)
 	}
-	if result.Status != "connected" {
-		t.Errorf("expected connected status, got: %q", result.Status)
+	if !strings.Contains(out, "brave-search") {
+		t.Errorf("expected server name in output, got: %q", out)
 	}
 }
 
-func TestAgentInfoCommand(t *testing.T) {
+func TestRestartCommand_MissingArg(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "info", "codey")
+	_, err := executeCmd(root, "restart")
+	if err == nil {
+		t.Error("expected error for missing server name argument")
+	}
+}
+
+func TestRestartCommand_All(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "restart", "--all")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Agent: codey") {
-		t.Errorf("expected agent title, got: %q", out)
-	}
-	if !strings.Contains(out, "enabled") {
-		t.Errorf("expected enabled status, got: %q", out)
+	for _, name := range []string{"brave-search", "broken-server"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("expected server '%s' in output, got: %q", name, out)
+		}
 	}
 }
 
-func TestAgentInfoCommand_JSON(t *testing.T) {
+func TestRestartCommand_Failed(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "info", "codey", "--json")
+	out, err := executeCmd(root, "restart", "--failed")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	var agent acp.AgentConfig
-	if err := json.Unmarshal([]byte(out), &agent); err != nil {
-		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	if !strings.Contains(out, "broken-server") {
+		t.Errorf("expected failed server in output, got: %q", out)
 	}
-	if agent.Name != "codey" {
-		t.Errorf("expected agent name 'codey', got: %q", agent.Name)
+	if strings.Contains(out, "brave-search") {
+		t.Errorf("expected healthy server to be skipped, got: %q", out)
 	}
 }
 
-func TestAgentLogsCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestRestartCommand_ReportsPartialFailure(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers/restart": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.MCPServerRestartResult{
+				{Name: "brave-search", Success: true},
+				{Name: "broken-server", Error: "command not found"},
+			})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "logs")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	out, err := executeCmd(root, "restart", "--all")
+	if err == nil {
+		t.Fatal("expected error when a server fails to restart")
 	}
-	if !strings.Contains(out, "Agent Logs") {
-		t.Errorf("expected 'Agent Logs' title, got: %q", out)
+	if !strings.Contains(out, "brave-search") || !strings.Contains(out, "command not found") {
+		t.Errorf("expected per-server results in output, got: %q", out)
 	}
-	if !strings.Contains(out, "codey") {
-		t.Errorf("expected agent name 'codey' in logs, got: %q", out)
+}
+
+func TestRestartCommand_AllRejectsName(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	_, err := executeCmd(root, "restart", "--all", "brave-search")
+	if err == nil {
+		t.Error("expected error when combining --all with a server name")
 	}
 }
 
-func TestAgentLogsCommand_Empty(t *testing.T) {
+func TestRestartDaemonCommand_WaitsForNewProcess(t *testing.T) {
+	startedAt := time.Now().Add(-time.Hour)
+	restarted := false
 	ts := newMockServer(map[string]http.HandlerFunc{
-		"/agents/": func(w http.ResponseWriter, r *http.Request) {
-			if strings.HasPrefix(r.URL.Path, "/agents/logs") {
-				jsonOK(w, []api.AgentLog{})
-				return
+		"/status": func(w http.ResponseWriter, r *http.Request) {
+			s := fixtureStatus()
+			s.StartedAt = startedAt
+			if restarted {
+				s.StartedAt = startedAt.Add(time.Hour)
 			}
+			jsonOK(w, s)
+		},
+		"/daemon/restart": func(w http.ResponseWriter, r *http.Request) {
+			restarted = true
+			jsonStatus(w, http.StatusAccepted, map[string]string{"status": "restarting"})
 		},
 	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "agent", "logs")
+	out, err := executeCmd(root, "restart-daemon", "--timeout", "5s")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No agent logs") {
-		t.Errorf("expected empty logs message, got: %q", out)
+	if !strings.Contains(out, "Daemon restarted") {
+		t.Errorf("expected restart confirmation, got: %q", out)
+	}
+}
+
+func TestRestartDaemonCommand_Rejected(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/daemon/restart": func(w http.ResponseWriter, r *http.Request) {
+			jsonStatus(w, http.StatusConflict, map[string]string{"error": "self-restart is only supported in serve mode"})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	_, err := executeCmd(root, "restart-daemon")
+	if err == nil || !strings.Contains(err.Error(), "serve mode") {
+		t.Errorf("expected serve mode error, got: %v", err)
 	}
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Gallery command
+// Tests: Agents command
 // ---------------------------------------------------------------------------
 
-func TestGalleryCommand_DefaultsList(t *testing.T) {
+func TestAgentsCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery")
+	out, err := executeCmd(root, "agents")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Agent Gallery") {
-		t.Errorf("expected 'Agent Gallery' title, got: %q", out)
+	if !strings.Contains(out, "ACP Agents") {
+		t.Errorf("expected title 'ACP Agents', got: %q", out)
 	}
-	for _, name := range []string{"codey", "claude-code", "research-bot"} {
+	for _, name := range []string{"codey", "researcher", "disabled-agent"} {
 		if !strings.Contains(out, name) {
-			t.Errorf("expected gallery entry '%s', got: %q", name, out)
+			t.Errorf("expected agent '%s' in output, got: %q", name, out)
 		}
 	}
 }
 
-func TestGalleryListCommand(t *testing.T) {
+func TestAgentsCommand_JSON(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "list")
+	out, err := executeCmd(root, "agents", "--json")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Agent Gallery") {
-		t.Errorf("expected gallery title, got: %q", out)
+
+	var agents []acp.AgentConfig
+	if err := json.Unmarshal([]byte(out), &agents); err != nil {
+		t.Fatalf("expected valid JSON, got parse error: %v\nOutput: %q", err, out)
+	}
+	if len(agents) != 3 {
+		t.Errorf("expected 3 agents, got %d", len(agents))
 	}
 }
 
-func TestGalleryFeaturedCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestAgentsCommand_Empty(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/agents": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []acp.AgentConfig{})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "featured")
+	out, err := executeCmd(root, "agents")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Featured Agents") {
-		t.Errorf("expected 'Featured Agents' title, got: %q", out)
-	}
-	// research-bot is not featured, should NOT appear
-	if strings.Contains(out, "research-bot") {
-		t.Errorf("expected 'research-bot' to NOT appear in featured list, got: %q", out)
+	if !strings.Contains(out, "No agents configured") {
+		t.Errorf("expected empty agents message, got: %q", out)
 	}
 }
 
-func TestGalleryInfoCommand(t *testing.T) {
+func TestAgentAddCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "info", "codey")
+	out, err := executeCmd(root, "agent", "add", "new-agent", "http://localhost:9000", "My new agent")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Gallery Agent") {
-		t.Errorf("expected gallery info title, got: %q", out)
+	if !strings.Contains(out, "added") {
+		t.Errorf("expected add success message, got: %q", out)
 	}
-	if !strings.Contains(out, "2.0.0") {
-		t.Errorf("expected version '2.0.0', got: %q", out)
+}
+
+func TestAgentAddCommand_MissingArgs(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	_, err := executeCmd(root, "agent", "add", "only-name")
+	if err == nil {
+		t.Error("expected error for missing URL argument")
 	}
-	if !strings.Contains(out, "npx") {
-		t.Errorf("expected install type 'npx', got: %q", out)
+}
+
+func TestAgentRunCommand_Timeout(t *testing.T) {
+	var received map[string]interface{}
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/agents/": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&received)
+			jsonOK(w, acp.Run{
+				AgentName: "researcher",
+				Status:    acp.RunStatusFailed,
+				Error:     &acp.Error{Code: "timeout", Message: "agent run timed out after 20s"},
+			})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "agent", "run", "researcher", "summarise the news", "--timeout", "20s")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Available") {
-		t.Errorf("expected 'Available' status, got: %q", out)
+	if received["timeout"] != float64(20) {
+		t.Errorf("expected timeout 20 in request, got: %v", received["timeout"])
+	}
+	if !strings.Contains(out, "timed out after 20s") {
+		t.Errorf("expected timeout error in output, got: %q", out)
 	}
 }
 
-func TestGalleryInfoCommand_JSON(t *testing.T) {
+func TestAgentRunCommand_SubSecondTimeout(t *testing.T) {
+	called := false
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/agents/": func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			jsonOK(w, acp.Run{AgentName: "researcher"})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	_, err := executeCmd(root, "agent", "run", "researcher", "summarise the news", "--timeout", "500ms")
+	if err == nil || !strings.Contains(err.Error(), "at least 1s") {
+		t.Fatalf("expected sub-second timeout to be rejected, got: %v", err)
+	}
+	if called {
+		t.Error("expected no request to the daemon")
+	}
+}
+
+func TestAgentChatCommand(t *testing.T) {
+	var prompts []string
+	closed := 0
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/agents/": func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/sessions"):
+				jsonOK(w, acp.SessionInfo{SessionID: "sess-1234567890ab", AgentName: "codey"})
+			case strings.HasSuffix(r.URL.Path, "/prompt"):
+				if r.URL.Query().Get("stream") != "true" {
+					t.Errorf("expected a streamed prompt, got %s", r.URL.String())
+				}
+				var body map[string]string
+				json.NewDecoder(r.Body).Decode(&body)
+				prompts = append(prompts, body["prompt"])
+				enc := json.NewEncoder(w)
+				enc.Encode(api.SessionStreamEvent{Text: "Hello "})
+				enc.Encode(api.SessionStreamEvent{Text: "there"})
+				enc.Encode(api.SessionStreamEvent{Run: &acp.Run{Status: acp.RunStatusCompleted}})
+			case r.Method == http.MethodDelete:
+				closed++
+				jsonOK(w, map[string]string{"status": "closed"})
+			}
+		},
+	})
+	defer ts.Close()
+
+	transcript := filepath.Join(t.TempDir(), "chat.md")
+	root := newTestRootCmd(ts)
+	root.SetIn(strings.NewReader("hi\n\n/save " + transcript + "\n/exit\n"))
+	out, err := executeCmd(root, "agent", "chat", "codey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(prompts, []string{"hi"}) {
+		t.Errorf("expected one prompt, got: %v", prompts)
+	}
+	if !strings.Contains(out, "Hello there") {
+		t.Errorf("expected streamed reply, got: %q", out)
+	}
+	if closed != 1 {
+		t.Errorf("expected session to be closed once, got %d", closed)
+	}
+	saved, err := os.ReadFile(transcript)
+	if err != nil {
+		t.Fatalf("transcript not saved: %v", err)
+	}
+	if !strings.Contains(string(saved), "## You\n\nhi\n\n## codey\n\nHello there") {
+		t.Errorf("unexpected transcript: %q", saved)
+	}
+}
+
+func TestAgentRemoveCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "info", "codey", "--json")
+	out, err := executeCmd(root, "agent", "remove", "codey")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !strings.Contains(out, "removed") {
+		t.Errorf("expected remove success message, got: %q", out)
+	}
+}
 
-	var info acp.InstallInfo
-	if err := json.Unmarshal([]byte(out), &info); err != nil {
+func TestAgentRemoveCommand_Alias(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "agent", "rm", "codey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "removed") {
+		t.Errorf("expected remove success via alias, got: %q", out)
+	}
+}
+
+func TestAgentEnableCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "agent", "enable", "disabled-agent")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "enabled") {
+		t.Errorf("expected enable success message, got: %q", out)
+	}
+}
+
+func TestAgentDisableCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "agent", "disable", "codey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "disabled") {
+		t.Errorf("expected disable success message, got: %q", out)
+	}
+}
+
+func TestAgentTestCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "agent", "test", "codey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Agent Test") {
+		t.Errorf("expected 'Agent Test' title, got: %q", out)
+	}
+	if !strings.Contains(out, "connected") {
+		t.Errorf("expected status 'connected', got: %q", out)
+	}
+	if !strings.Contains(out, "1.0.0") {
+		t.Errorf("expected version '1.0.0', got: %q", out)
+	}
+}
+
+func TestAgentTestCommand_JSON(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "agent", "test", "codey", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result acp.AgentTestResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
 		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
 	}
-	if info.ID != "codey" {
-		t.Errorf("expected id 'codey', got: %q", info.ID)
+	if result.Status != "connected" {
+		t.Errorf("expected connected status, got: %q", result.Status)
 	}
 }
 
-func TestGalleryInstallCommand(t *testing.T) {
+func TestAgentInfoCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "install", "codey")
+	out, err := executeCmd(root, "agent", "info", "codey")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "configured") {
-		t.Errorf("expected install success message, got: %q", out)
+	if !strings.Contains(out, "Agent: codey") {
+		t.Errorf("expected agent title, got: %q", out)
 	}
-	if !strings.Contains(out, "npx @google/codey") {
-		t.Errorf("expected install command hint, got: %q", out)
+	if !strings.Contains(out, "enabled") {
+		t.Errorf("expected enabled status, got: %q", out)
 	}
 }
 
-func TestGalleryInstallCommand_WithOptions(t *testing.T) {
+func TestAgentInfoCommand_JSON(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "install", "codey", "--name", "my-codey", "--port", "9000")
+	out, err := executeCmd(root, "agent", "info", "codey", "--json")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "my-codey") {
-		t.Errorf("expected custom agent name 'my-codey', got: %q", out)
+
+	var agent acp.AgentConfig
+	if err := json.Unmarshal([]byte(out), &agent); err != nil {
+		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	}
+	if agent.Name != "codey" {
+		t.Errorf("expected agent name 'codey', got: %q", agent.Name)
 	}
 }
 
-func TestGalleryRefreshCommand(t *testing.T) {
+func TestAgentLogsCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "refresh")
+	out, err := executeCmd(root, "agent", "logs")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "refreshed") {
-		t.Errorf("expected refresh success message, got: %q", out)
+	if !strings.Contains(out, "Agent Logs") {
+		t.Errorf("expected 'Agent Logs' title, got: %q", out)
+	}
+	if !strings.Contains(out, "codey") {
+		t.Errorf("expected agent name 'codey' in logs, got: %q", out)
 	}
 }
 
-func TestGalleryCommand_Empty(t *testing.T) {
+func TestAgentLogsCommand_Empty(t *testing.T) {
 	ts := newMockServer(map[string]http.HandlerFunc{
-		"/gallery": func(w http.ResponseWriter, r *http.Request) {
-			if r.Method == http.MethodGet {
-				jsonOK(w, []acp.GalleryEntry{})
+		"/agents/": func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/agents/logs") {
+				jsonOK(w, []api.AgentLog{})
+				return
 			}
 		},
 	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "gallery", "list")
+	out, err := executeCmd(root, "agent", "logs")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No gallery entries") {
-		t.Errorf("expected empty gallery message, got: %q", out)
+	if !strings.Contains(out, "No agent logs") {
+		t.Errorf("expected empty logs message, got: %q", out)
+	}
+}
+
+func TestAgentLogsCommand_Export(t *testing.T) {
+	content := `{"prompt":"hi","api_key":"abc123secret"} Authorization: Bearer tok.en-value sk-abcdefghijklmnopqrstuv`
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/agents/": func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/agents/logs") {
+				jsonOK(w, []api.AgentLog{{ID: 1, AgentName: "codey", Direction: "request", MessageType: "run", Content: &content}})
+				return
+			}
+		},
+	})
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "logs.json")
+	root := newTestRootCmd(ts)
+	if _, err := executeCmd(root, "agent", "logs", "codey", "--export", path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var logs []api.AgentLog
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if err := json.Unmarshal(data, &logs); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Content == nil || *logs[0].Content != content {
+		t.Fatalf("expected full content in export, got: %s", data)
+	}
+
+	root = newTestRootCmd(ts)
+	if _, err := executeCmd(root, "agent", "logs", "codey", "--export", path, "--redact"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	for _, secret := range []string{"abc123secret", "tok.en-value", "sk-abcdefghijklmnopqrstuv"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("expected %q to be redacted, got: %s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), `\"prompt\":\"hi\"`) {
+		t.Errorf("expected non-secret content to be kept, got: %s", data)
+	}
+
+	root = newTestRootCmd(ts)
+	if _, err := executeCmd(root, "agent", "logs", "--redact"); err == nil {
+		t.Error("expected error for --redact without --export")
 	}
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Context command
+// Tests: Gallery command
 // ---------------------------------------------------------------------------
 
-func TestContextCommand_DefaultsList(t *testing.T) {
+func TestGalleryCommand_DefaultsList(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Agent Gallery") {
+		t.Errorf("expected 'Agent Gallery' title, got: %q", out)
+	}
+	for _, name := range []string{"codey", "claude-code", "research-bot"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("expected gallery entry '%s', got: %q", name, out)
+		}
+	}
+}
+
+func TestGalleryListCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Agent Gallery") {
+		t.Errorf("expected gallery title, got: %q", out)
+	}
+}
+
+func TestGalleryFeaturedCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "featured")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Featured Agents") {
+		t.Errorf("expected 'Featured Agents' title, got: %q", out)
+	}
+	// research-bot is not featured, should NOT appear
+	if strings.Contains(out, "research-bot") {
+		t.Errorf("expected 'research-bot' to NOT appear in featured list, got: %q", out)
+	}
+}
+
+func TestGalleryInfoCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "info", "codey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Gallery Agent") {
+		t.Errorf("expected gallery info title, got: %q", out)
+	}
+	if !strings.Contains(out, "2.0.0") {
+		t.Errorf("expected version '2.0.0', got: %q", out)
+	}
+	if !strings.Contains(out, "npx") {
+		t.Errorf("expected install type 'npx', got: %q", out)
+	}
+	if !strings.Contains(out, "Available") {
+		t.Errorf("expected 'Available' status, got: %q", out)
+	}
+}
+
+func TestGalleryInfoCommand_JSON(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "info", "codey", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var info acp.InstallInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	}
+	if info.ID != "codey" {
+		t.Errorf("expected id 'codey', got: %q", info.ID)
+	}
+}
+
+func TestGalleryInstallCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "install", "codey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "configured") {
+		t.Errorf("expected install success message, got: %q", out)
+	}
+	if !strings.Contains(out, "npx @google/codey") {
+		t.Errorf("expected install command hint, got: %q", out)
+	}
+}
+
+func TestGalleryInstallCommand_WithOptions(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "install", "codey", "--name", "my-codey", "--port", "9000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "my-codey") {
+		t.Errorf("expected custom agent name 'my-codey', got: %q", out)
+	}
+}
+
+func TestAgentInstallBinaryCommand(t *testing.T) {
+	// npx is on PATH but the agent's package isn't installed; the fake npm
+	// reports it missing and records an install
+	home, bin := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", bin)
+	os.WriteFile(filepath.Join(bin, "npx"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+	os.WriteFile(filepath.Join(bin, "npm"), []byte("#!/bin/sh\n[ \"$1\" = ls ] && exit 1\necho \"npm-ran $*\"\n"), 0755)
+	registry := `{"agents": [{"id": "codey", "name": "Codey", "distribution": {"npx": {"package": "@google/codey@2.0.0"}}}]}`
+	os.MkdirAll(filepath.Join(home, config.DirName()), 0755)
+	os.WriteFile(filepath.Join(home, config.DirName(), "acp-registry.json"), []byte(registry), 0644)
+
+	gallery, err := acp.NewGallery()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := gallery.GetInstallInfo("codey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Available || info.Installed || info.SetupCmd != "npm install -g @google/codey@2.0.0" {
+		t.Fatalf("unexpected install info: %+v", info)
+	}
+
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/gallery/": func(w http.ResponseWriter, r *http.Request) {
+			if id := strings.TrimPrefix(r.URL.Path, "/gallery/"); id != "codey" {
+				binary := fixtureInstallInfo(id)
+				binary.InstallType, binary.InstallCmd, binary.SetupCmd, binary.Installed = "binary", "", "", false
+				binary.DownloadURL = "https://example.com/agent.tar.gz"
+				jsonOK(w, binary)
+				return
+			}
+			jsonOK(w, info)
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	root.SetIn(strings.NewReader("n\n"))
+	out, err := executeCmd(root, "agent", "install-binary", "codey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "npm install -g @google/codey@2.0.0") || !strings.Contains(out, "Cancelled") || strings.Contains(out, "npm-ran") {
+		t.Errorf("expected the command to be shown and then declined, got: %q", out)
+	}
+
+	root = newTestRootCmd(ts)
+	out, err = executeCmd(root, "agent", "install-binary", "codey", "--yes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "npm-ran install -g @google/codey@2.0.0") || !strings.Contains(out, "installed") {
+		t.Errorf("expected npm to install the package, got: %q", out)
+	}
+
+	root = newTestRootCmd(ts)
+	if _, err := executeCmd(root, "agent", "install-binary", "binary-only", "--yes"); err == nil || !strings.Contains(err.Error(), "https://example.com/agent.tar.gz") {
+		t.Errorf("expected an error pointing at the download URL, got: %v", err)
+	}
+}
+
+func TestGalleryInstallCommand_RunInstall(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "install", "codey", "--run-install", "--yes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "configured") || !strings.Contains(out, "already installed") {
+		t.Errorf("expected the agent to be configured and found installed, got: %q", out)
+	}
+}
+
+func TestGalleryRefreshCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "refresh")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "refreshed") {
+		t.Errorf("expected refresh success message, got: %q", out)
+	}
+}
+
+func TestGalleryCommand_Empty(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/gallery": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				jsonOK(w, []acp.GalleryEntry{})
+			}
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "gallery", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No gallery entries") {
+		t.Errorf("expected empty gallery message, got: %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: Context command
+// ---------------------------------------------------------------------------
+
+func TestContextCommand_DefaultsList(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Contexts") {
+		t.Errorf("expected 'Contexts' title, got: %q", out)
+	}
+	for _, name := range []string{"personal", "work", "testing"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("expected context '%s' in output, got: %q", name, out)
+		}
+	}
+}
+
+func TestContextListCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "personal") {
+		t.Errorf("expected 'personal' context, got: %q", out)
+	}
+}
+
+func TestContextListCommand_JSON(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "list", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var contexts []api.ContextInfo
+	if err := json.Unmarshal([]byte(out), &contexts); err != nil {
+		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	}
+	if len(contexts) != 3 {
+		t.Errorf("expected 3 contexts, got %d", len(contexts))
+	}
+}
+
+func TestContextListCommand_Empty(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/contexts": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.ContextInfo{})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No contexts") {
+		t.Errorf("expected empty contexts message, got: %q", out)
+	}
+}
+
+func TestContextCreateCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "create", "new-ctx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "created") {
+		t.Errorf("expected success message, got: %q", out)
+	}
+}
+
+func TestContextDeleteCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "delete", "old-ctx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "deleted") {
+		t.Errorf("expected success message, got: %q", out)
+	}
+}
+
+func TestContextSetDefaultCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "set-default", "work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "default") {
+		t.Errorf("expected success message, got: %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: Auth command
+// ---------------------------------------------------------------------------
+
+func TestContextDiffCommand(t *testing.T) {
+	details := map[string]api.ContextDetailResponse{
+		"work": {
+			Context: api.ContextResponse{Name: "work"},
+			Servers: []api.ContextServerResponse{
+				{Name: "github", Enabled: true, Tools: []api.ToolStatusResponse{
+					{Name: "create_issue", Enabled: true},
+					{Name: "list_issues", Enabled: true},
+				}},
+				{Name: "jobs", Enabled: true},
+			},
+		},
+		"personal": {
+			Context: api.ContextResponse{Name: "personal"},
+			Servers: []api.ContextServerResponse{
+				{Name: "github", Enabled: true, Tools: []api.ToolStatusResponse{
+					{Name: "create_issue", Enabled: false},
+				}},
+				{Name: "jobs", Enabled: false},
+				{Name: "google", Enabled: true},
+			},
+		},
+	}
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/contexts/": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, details[strings.TrimPrefix(r.URL.Path, "/contexts/")])
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "diff", "work", "personal", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var diff contextDiff
+	if err := json.Unmarshal([]byte(out), &diff); err != nil {
+		t.Fatalf("failed to parse output: %v\n%s", err, out)
+	}
+	want := []contextServerDiff{
+		// list_issues has no override in personal, so it's enabled there too
+		{Server: "github", OnlyA: []string{"create_issue"}, OnlyB: []string{}, Both: []string{"list_issues"}},
+		{Server: "google", OnlyA: []string{}, OnlyB: []string{"*"}, Both: []string{}},
+		{Server: "jobs", OnlyA: []string{"*"}, OnlyB: []string{}, Both: []string{}},
+	}
+	if !reflect.DeepEqual(diff.Servers, want) {
+		t.Errorf("expected %+v, got %+v", want, diff.Servers)
+	}
+
+	root = newTestRootCmd(ts)
+	out, err = executeCmd(root, "context", "diff", "work", "personal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "only in work: create_issue") || !strings.Contains(out, "3 tool(s) enabled in only one context") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestContextInitOptionsCommand(t *testing.T) {
+	var stored map[string]any
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/contexts/": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/contexts/work/servers/files/init-options" {
+				jsonStatus(w, http.StatusNotFound, map[string]string{"error": "unexpected path " + r.URL.Path})
+				return
+			}
+			switch r.Method {
+			case http.MethodPut:
+				json.NewDecoder(r.Body).Decode(&stored)
+				jsonOK(w, map[string]string{"status": "updated"})
+			case http.MethodDelete:
+				stored = nil
+				jsonOK(w, map[string]string{"status": "updated"})
+			default:
+				jsonOK(w, stored)
+			}
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	if _, err := executeCmd(root, "context", "init-options", "work", "files", `{"rootDir": "/work"}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored["rootDir"] != "/work" {
+		t.Errorf("expected the options to be stored, got %v", stored)
+	}
+
+	root = newTestRootCmd(ts)
+	out, err := executeCmd(root, "context", "init-options", "work", "files")
+	if err != nil || !strings.Contains(out, `"rootDir": "/work"`) {
+		t.Errorf("expected the options to be shown, got %q, %v", out, err)
+	}
+
+	root = newTestRootCmd(ts)
+	if _, err := executeCmd(root, "context", "init-options", "work", "files", "not json"); err == nil {
+		t.Error("expected an error for options that aren't a JSON object")
+	}
+
+	root = newTestRootCmd(ts)
+	if _, err := executeCmd(root, "context", "init-options", "work", "files", "--clear"); err != nil || stored != nil {
+		t.Errorf("expected the options to be cleared, got %v, %v", stored, err)
+	}
+}
+
+func TestAuthCommand_DefaultsList(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "OAuth Servers") {
+		t.Errorf("expected 'OAuth Servers' title, got: %q", out)
+	}
+	if !strings.Contains(out, "linear") {
+		t.Errorf("expected 'linear' server, got: %q", out)
+	}
+	if !strings.Contains(out, "github-server") {
+		t.Errorf("expected 'github-server', got: %q", out)
+	}
+}
+
+func TestAuthCommand_JSON(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var servers []api.OAuthServerInfo
+	if err := json.Unmarshal([]byte(out), &servers); err != nil {
+		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	}
+	if len(servers) != 2 {
+		t.Errorf("expected 2 OAuth servers, got %d", len(servers))
+	}
+}
+
+func TestAuthCommand_Empty(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/auth": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.OAuthServerInfo{})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No OAuth") {
+		t.Errorf("expected empty OAuth message, got: %q", out)
+	}
+}
+
+func TestAuthStatusCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth", "status", "linear")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// auth status outputs raw JSON
+	if !strings.Contains(out, "authenticated") {
+		t.Errorf("expected 'authenticated' in output, got: %q", out)
+	}
+}
+
+func TestAuthLogoutCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth", "logout", "linear")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Logged out") {
+		t.Errorf("expected logout success message, got: %q", out)
+	}
+}
+
+func TestAuthSetTokenCommand(t *testing.T) {
+	var received api.OAuthTokenImport
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/auth/": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/auth/github-server/token" {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			json.NewDecoder(r.Body).Decode(&received)
+			jsonOK(w, api.OAuthTokenResult{Status: "authenticated", ExpiresAt: &received.ExpiresAt})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth", "set-token", "github-server", "ghp_abc",
+		"--refresh-token", "r1", "--expires-in", "24h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.AccessToken != "ghp_abc" || received.RefreshToken != "r1" {
+		t.Errorf("unexpected token sent: %+v", received)
+	}
+	if d := time.Until(received.ExpiresAt); d < 23*time.Hour || d > 25*time.Hour {
+		t.Errorf("expected expiry about 24h out, got %v", received.ExpiresAt)
+	}
+	if !strings.Contains(out, "Stored token for 'github-server'") || !strings.Contains(out, "expires") {
+		t.Errorf("expected success message with expiry, got: %q", out)
+	}
+
+	// Without a token argument it is read from stdin
+	root = newTestRootCmd(ts)
+	root.SetIn(strings.NewReader("ghp_from_stdin\n"))
+	if _, err := executeCmd(root, "auth", "set-token", "github-server"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.AccessToken != "ghp_from_stdin" || !received.ExpiresAt.IsZero() {
+		t.Errorf("unexpected token sent from stdin: %+v", received)
+	}
+}
+
+func TestAuthSetTokenCommand_Warning(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/auth/": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, api.OAuthTokenResult{
+				Status:  "authenticated",
+				Warning: "token saved, but the server did not start with it (it may be invalid): 401 Unauthorized",
+			})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth", "set-token", "github-server", "bad")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "may be invalid") {
+		t.Errorf("expected invalid token warning, got: %q", out)
+	}
+}
+
+func TestAuthRefreshCommand(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/auth/": func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/auth/linear/refresh":
+				jsonOK(w, api.OAuthTokenResult{Status: "authenticated", ExpiresAt: &expiresAt})
+			case "/auth/github-server/refresh":
+				jsonStatus(w, http.StatusBadRequest, map[string]string{
+					"error": "no refresh token stored for github-server; run 'diane auth login github-server' to sign in again",
+				})
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "auth", "refresh", "linear")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Refreshed token for 'linear'") || !strings.Contains(out, expiresAt.Format("2006-01-02 15:04")) {
+		t.Errorf("expected refresh message with the new expiry, got: %q", out)
+	}
+
+	root = newTestRootCmd(ts)
+	_, err = executeCmd(root, "auth", "refresh", "github-server")
+	if err == nil || !strings.Contains(err.Error(), "auth login github-server") {
+		t.Errorf("expected an error pointing at auth login, got: %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: Tools / Prompts / Resources commands
+// ---------------------------------------------------------------------------
+
+func TestToolsCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "tools")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Tools") {
+		t.Errorf("expected 'Tools' title, got: %q", out)
+	}
+	if !strings.Contains(out, "filesystem") {
+		t.Errorf("expected 'filesystem' server, got: %q", out)
+	}
+}
+
+func TestToolsCommand_ServerFilter(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "tools", "--server", "filesystem")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "filesystem") {
+		t.Errorf("expected filtered server, got: %q", out)
+	}
+	// Other servers should not appear (brave-search etc.)
+	if strings.Contains(out, "brave-search") {
+		t.Errorf("expected 'brave-search' to be filtered out, got: %q", out)
+	}
+}
+
+func TestToolsCommand_NoMatch(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "tools", "--server", "nonexistent")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No server found") {
+		t.Errorf("expected no-match message, got: %q", out)
+	}
+}
+
+func TestToolsCallCommand(t *testing.T) {
+	var got struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+		Context   string                 `json:"context"`
+	}
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/tools/call": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			jsonOK(w, api.ToolCallResult{Content: []api.ToolContent{{Type: "text", Text: "Sunny, 21C"}}})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "tools", "call", "weather_forecast",
+		"--json", `{"location":"Oslo","units":"metric"}`,
+		"--arg", "days=3", "--arg", "hourly=true", "--arg", "tags=[\"a\",\"b\"]", "--arg", "units=imperial",
+		"--context", "work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Sunny, 21C") {
+		t.Errorf("expected the tool output, got: %q", out)
+	}
+	if got.Name != "weather_forecast" || got.Context != "work" {
+		t.Errorf("unexpected call %q in context %q", got.Name, got.Context)
+	}
+	want := map[string]interface{}{
+		"location": "Oslo", "units": "imperial", "days": float64(3), "hourly": true,
+		"tags": []interface{}{"a", "b"},
+	}
+	if !reflect.DeepEqual(got.Arguments, want) {
+		t.Errorf("arguments = %v, want %v", got.Arguments, want)
+	}
+}
+
+func TestToolsCallCommand_Errors(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/tools/call": func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Name string `json:"name"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Name == "missing" {
+				jsonStatus(w, http.StatusNotFound, api.ToolCallError{Code: -32601, Message: "Tool not found: missing"})
+				return
+			}
+			jsonOK(w, api.ToolCallResult{Content: []api.ToolContent{{Type: "text", Text: "disk full"}}, IsError: true})
+		},
+	})
+	defer ts.Close()
+
+	_, err := executeCmd(newTestRootCmd(ts), "tools", "call", "missing")
+	if err == nil || !strings.Contains(err.Error(), "Tool not found") || ExitCode(err) != ExitNotFound {
+		t.Errorf("expected a not-found error, got %v", err)
+	}
+
+	out, err := executeCmd(newTestRootCmd(ts), "tools", "call", "backup")
+	if err == nil || !strings.Contains(out, "disk full") {
+		t.Errorf("expected the tool's error output and a failure, got %q, %v", out, err)
+	}
+
+	if _, err := executeCmd(newTestRootCmd(ts), "tools", "call", "backup", "--arg", "novalue"); err == nil {
+		t.Error("expected an --arg without = to be rejected")
+	}
+}
+
+func TestToolsCategoryFilter(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/tools": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.ToolInfo{
+				{Name: "gmail_search", Description: "Search email", Server: "google", Builtin: true, Category: "email"},
+				{Name: "drive_search", Description: "Search Drive\nwith a query", Server: "google", Builtin: true, Category: "files"},
+				{Name: "file_registry_get", Description: "Get a file", Server: "file_registry", Builtin: true, Category: "files"},
+				{Name: "fetch", Description: "Fetch a URL", Server: "web"},
+			})
+		},
+	})
+	defer ts.Close()
+
+	out, err := executeCmd(newTestRootCmd(ts), "tools", "--category", "files")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"drive_search", "file_registry_get", "Tools in files (2)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
+	}
+	for _, unwanted := range []string{"gmail_search", "fetch", "with a query"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("did not expect %q in output, got: %q", unwanted, out)
+		}
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "tools", "--category", "files", "--server", "google", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var tools []api.ToolInfo
+	if err := json.Unmarshal([]byte(out), &tools); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(tools) != 1 || tools[0].Name != "drive_search" {
+		t.Errorf("expected only drive_search, got %+v", tools)
+	}
+}
+
+func TestToolsDescribeCommand(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/tools": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.ToolInfo{{
+				Name: "weather_forecast", Description: "Get the forecast", Server: "weather", Builtin: true,
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"location": map[string]interface{}{"type": "string", "description": "City name"},
+						"days":     map[string]interface{}{"type": "integer", "description": "Days ahead", "default": 3},
+						"units":    map[string]interface{}{"type": "string", "enum": []string{"metric", "imperial"}},
+					},
+					"required": []string{"location"},
+				},
+			}})
+		},
+	})
+	defer ts.Close()
+
+	out, err := executeCmd(newTestRootCmd(ts), "tools", "describe", "weather_forecast")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Get the forecast", "location", "City name", "string (metric|imperial)", "3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
+	}
+	if strings.Index(out, "location") > strings.Index(out, "days") {
+		t.Errorf("expected required arguments first, got: %q", out)
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "tools", "describe", "weather_forecast", "-o", "json")
+	if err != nil || !strings.Contains(out, `"required"`) {
+		t.Errorf("expected the raw schema, got %q, %v", out, err)
+	}
+
+	_, err = executeCmd(newTestRootCmd(ts), "tools", "describe", "nope")
+	if ExitCode(err) != ExitNotFound {
+		t.Errorf("expected a not-found error, got %v", err)
+	}
+}
+
+func TestToolsExportCommand(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/tools": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.ToolInfo{
+				{Name: "weather_forecast", Description: "Get the forecast", Server: "weather", Builtin: true,
+					InputSchema: map[string]interface{}{
+						"type":       "object",
+						"properties": map[string]interface{}{"location": map[string]interface{}{"type": "string"}},
+						"required":   []string{"location"},
+					}},
+				{Name: "read_file", Description: "Read a file", Server: "filesystem"},
+			})
+		},
+	})
+	defer ts.Close()
+
+	out, err := executeCmd(newTestRootCmd(ts), "tools", "export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var catalog struct {
+		Tools []api.ToolInfo `json:"tools"`
+	}
+	if err := json.Unmarshal([]byte(out), &catalog); err != nil || len(catalog.Tools) != 2 || catalog.Tools[0].Server != "weather" {
+		t.Fatalf("expected the catalog, got %q (%v)", out, err)
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "tools", "export", "--format", "json-schema")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var bundle struct {
+		Defs map[string]map[string]interface{} `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(out), &bundle); err != nil {
+		t.Fatalf("invalid JSON Schema bundle: %v", err)
+	}
+	if bundle.Defs["weather_forecast"]["description"] != "Get the forecast" || bundle.Defs["read_file"]["type"] != "object" {
+		t.Errorf("unexpected definitions: %v", bundle.Defs)
+	}
+
+	file := filepath.Join(t.TempDir(), "openapi.json")
+	out, err = executeCmd(newTestRootCmd(ts), "tools", "export", "--format", "openapi", "--file", file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Exported 2 tools") {
+		t.Errorf("expected a success message, got %q", out)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("expected the file to be written: %v", err)
+	}
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil || doc.OpenAPI != "3.1.0" || len(doc.Components.Schemas) != 2 {
+		t.Errorf("unexpected OpenAPI document %s (%v)", data, err)
+	}
+
+	if _, err := executeCmd(newTestRootCmd(ts), "tools", "export", "--format", "yaml"); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
+
+func TestToolsSetDescriptionCommand(t *testing.T) {
+	var got api.ToolCustomization
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/tools/customize": func(w http.ResponseWriter, r *http.Request) {
+			got = api.ToolCustomization{}
+			json.NewDecoder(r.Body).Decode(&got)
+			if got.Tool == "nope" {
+				jsonStatus(w, http.StatusNotFound, map[string]string{"error": "tool not found: nope"})
+				return
+			}
+			jsonOK(w, map[string]string{"status": "ok"})
+		},
+		"/tools": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.ToolInfo{{
+				Name: "weather_forecast", Description: "Forecast for my commute", Server: "weather", Builtin: true,
+				Customized: true, OriginalDescription: "Get the forecast", HiddenArgs: []string{"units"},
+			}})
+		},
+	})
+	defer ts.Close()
+
+	out, err := executeCmd(newTestRootCmd(ts), "tools", "set-description", "weather_forecast", "Forecast for my commute", "--hide-arg", "units")
+	if err != nil || !strings.Contains(out, "Customized weather_forecast") {
+		t.Fatalf("unexpected result: %q, %v", out, err)
+	}
+	if got.Description != "Forecast for my commute" || len(got.HiddenArgs) != 1 || got.HiddenArgs[0] != "units" {
+		t.Errorf("unexpected customization sent: %+v", got)
+	}
+
+	if _, err := executeCmd(newTestRootCmd(ts), "tools", "set-description", "weather_forecast", "--reset"); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if got.Description != "" || len(got.HiddenArgs) != 0 {
+		t.Errorf("expected an empty customization for --reset, got %+v", got)
+	}
+
+	if _, err := executeCmd(newTestRootCmd(ts), "tools", "set-description", "weather_forecast"); err == nil {
+		t.Error("expected an error without a description, --hide-arg or --reset")
+	}
+	if _, err := executeCmd(newTestRootCmd(ts), "tools", "set-description", "nope", "x"); ExitCode(err) != ExitNotFound {
+		t.Errorf("expected a not-found error, got %v", err)
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "tools", "describe", "weather_forecast")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Customized", "Forecast for my commute", "Original: Get the forecast", "Hidden arguments: units"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
+	}
+}
+
+func TestPromptsCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "prompts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Prompts") {
+		t.Errorf("expected 'Prompts' title, got: %q", out)
+	}
+}
+
+func TestPromptsCommand_None(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers": func(w http.ResponseWriter, r *http.Request) {
+			servers := []api.MCPServerStatus{
+				{Name: "srv1", Enabled: true, Connected: true, ToolCount: 5},
+			}
+			jsonOK(w, servers)
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "prompts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No prompts") {
+		t.Errorf("expected no-prompts message, got: %q", out)
+	}
+}
+
+func TestResourcesCommand_None(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/mcp-servers": func(w http.ResponseWriter, r *http.Request) {
+			servers := []api.MCPServerStatus{
+				{Name: "srv1", Enabled: true, Connected: true, ToolCount: 5},
+			}
+			jsonOK(w, servers)
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "resources")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No resources") {
+		t.Errorf("expected no-resources message, got: %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: Hosts / Usage / Provider / Jobs
+// ---------------------------------------------------------------------------
+
+func TestHostsCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "hosts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Connected Hosts") {
+		t.Errorf("expected title 'Connected Hosts', got: %q", out)
+	}
+	if !strings.Contains(out, "master") {
+		t.Errorf("expected 'master' host, got: %q", out)
+	}
+	if !strings.Contains(out, "slave-1") {
+		t.Errorf("expected 'slave-1' host, got: %q", out)
+	}
+}
+
+func TestUsageCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "usage")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Usage Summary") {
+		t.Errorf("expected 'Usage Summary' title, got: %q", out)
+	}
+	if !strings.Contains(out, "openai") {
+		t.Errorf("expected 'openai' provider, got: %q", out)
+	}
+	if !strings.Contains(out, "1.234") {
+		t.Errorf("expected total cost '1.234', got: %q", out)
+	}
+}
+
+func TestUsageCommand_AgentUsage(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/usage/summary": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, api.UsageSummaryResponse{
+				TotalCost: 0.42,
+				Summary: []api.UsageSummaryRecord{
+					{Service: api.AgentUsageService, Model: "gemini", TotalRequests: 3, TotalInput: 1200, TotalOutput: 300, TotalCost: 0.42},
+				},
+			})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "usage")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "acp_agent") || !strings.Contains(out, "gemini") {
+		t.Errorf("expected the agent's usage row, got: %q", out)
+	}
+	if !strings.Contains(out, "0.4200") {
+		t.Errorf("expected the agent's cost, got: %q", out)
+	}
+}
+
+func TestProviderCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "context")
+	out, err := executeCmd(root, "provider")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Contexts") {
-		t.Errorf("expected 'Contexts' title, got: %q", out)
-	}
-	for _, name := range []string{"personal", "work", "testing"} {
-		if !strings.Contains(out, name) {
-			t.Errorf("expected context '%s' in output, got: %q", name, out)
-		}
+	// Default is list
+	if !strings.Contains(out, "Providers") {
+		t.Errorf("expected 'Providers' title, got: %q", out)
 	}
 }
 
-func TestContextListCommand(t *testing.T) {
+func TestProviderListCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "context", "list")
+	out, err := executeCmd(root, "provider", "list")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "personal") {
-		t.Errorf("expected 'personal' context, got: %q", out)
+	if !strings.Contains(out, "Providers") {
+		t.Errorf("expected 'Providers' title, got: %q", out)
+	}
+	if !strings.Contains(out, "openai") {
+		t.Errorf("expected 'openai' provider, got: %q", out)
 	}
 }
 
-func TestContextListCommand_JSON(t *testing.T) {
+func TestProviderModelsCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "context", "list", "--json")
+	out, err := executeCmd(root, "provider", "models", "--service", "openai")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	var contexts []api.ContextInfo
-	if err := json.Unmarshal([]byte(out), &contexts); err != nil {
-		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	if !strings.Contains(out, "Available Models") {
+		t.Errorf("expected 'Available Models' title, got: %q", out)
 	}
-	if len(contexts) != 3 {
-		t.Errorf("expected 3 contexts, got %d", len(contexts))
+	if !strings.Contains(out, "GPT-4") {
+		t.Errorf("expected 'GPT-4' model, got: %q", out)
 	}
 }
 
-func TestContextListCommand_Empty(t *testing.T) {
+func TestProviderEditDefaults(t *testing.T) {
+	var got map[string]interface{}
 	ts := newMockServer(map[string]http.HandlerFunc{
-		"/contexts": func(w http.ResponseWriter, r *http.Request) {
-			jsonOK(w, []api.ContextInfo{})
+		"/providers/": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			jsonOK(w, api.ProviderResponse{ID: 1, Name: "gemini", Enabled: true})
 		},
 	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "context", "list")
+	_, err := executeCmd(newTestRootCmd(ts), "provider", "edit", "1",
+		"--model", "gemini-2.5-pro", "--param", "temperature=0.2", "--param", "max_tokens=1024", "--param", "top_k=")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No contexts") {
-		t.Errorf("expected empty contexts message, got: %q", out)
+	params, _ := got["params"].(map[string]interface{})
+	if got["default_model"] != "gemini-2.5-pro" || params["temperature"] != 0.2 || params["max_tokens"] != float64(1024) {
+		t.Errorf("unexpected request: %v", got)
+	}
+	if v, ok := params["top_k"]; !ok || v != nil {
+		t.Errorf("expected top_k=null to remove the param, got %v", params)
+	}
+	if _, ok := got["config"]; ok {
+		t.Errorf("defaults should not replace the whole config: %v", got)
+	}
+
+	if _, err := executeCmd(newTestRootCmd(ts), "provider", "edit", "1", "--param", "temperature"); err == nil {
+		t.Error("expected an error for a param without a value")
 	}
 }
 
-func TestContextCreateCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestProviderListShowsDefaults(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/providers": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.ProviderResponse{{ID: 1, Name: "gemini", Type: "llm", Service: "vertex_ai_llm", Enabled: true,
+				DefaultModel: "gemini-2.5-pro", Params: map[string]any{"temperature": 0.2, "max_tokens": 1024}}})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "context", "create", "new-ctx")
+	out, err := executeCmd(newTestRootCmd(ts), "provider", "list")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "created") {
-		t.Errorf("expected success message, got: %q", out)
+	for _, want := range []string{"Model", "gemini-2.5-pro", "max_tokens=1024,temperature=0.2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
 	}
 }
 
-func TestContextDeleteCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestProviderTestAllCommand(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/providers": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, append(fixtureProviders(), api.ProviderResponse{ID: 3, Name: "off", Type: "llm"}))
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "context", "delete", "old-ctx")
+	out, err := executeCmd(newTestRootCmd(ts), "provider", "test", "--all")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "deleted") {
-		t.Errorf("expected success message, got: %q", out)
+	for _, want := range []string{"Provider Tests", "openai", "local-embedding", "123ms", "All 2 providers passed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
+	}
+	if strings.Contains(out, "off") {
+		t.Errorf("disabled provider should not be tested, got: %q", out)
+	}
+
+	if _, err := executeCmd(newTestRootCmd(ts), "provider", "test"); err == nil {
+		t.Error("expected an error without an id or --all")
 	}
 }
 
-func TestContextSetDefaultCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestProviderTestAllCommand_Failure(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/providers/": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/providers/2/test" {
+				jsonStatus(w, http.StatusBadRequest, api.ProviderTestResult{Message: "connection refused", ResponseTime: 7})
+				return
+			}
+			jsonOK(w, api.ProviderTestResult{Success: true, Message: "Connected", ResponseTime: 123})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "context", "set-default", "work")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	out, err := executeCmd(newTestRootCmd(ts), "provider", "test", "--all", "--json")
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 providers failed") {
+		t.Fatalf("expected a failure error, got %v", err)
 	}
-	if !strings.Contains(out, "default") {
-		t.Errorf("expected success message, got: %q", out)
+	var entries []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(entries) != 2 || entries[1]["name"] != "local-embedding" || entries[1]["success"] != false ||
+		entries[1]["message"] != "connection refused" || entries[0]["response_time_ms"] != float64(123) {
+		t.Errorf("unexpected entries: %v", entries)
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Auth command
-// ---------------------------------------------------------------------------
-
-func TestAuthCommand_DefaultsList(t *testing.T) {
+func TestJobsCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "auth")
+	out, err := executeCmd(root, "jobs")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "OAuth Servers") {
-		t.Errorf("expected 'OAuth Servers' title, got: %q", out)
-	}
-	if !strings.Contains(out, "linear") {
-		t.Errorf("expected 'linear' server, got: %q", out)
-	}
-	if !strings.Contains(out, "github-server") {
-		t.Errorf("expected 'github-server', got: %q", out)
+	// Default is list
+	if !strings.Contains(out, "Scheduled Jobs") {
+		t.Errorf("expected 'Scheduled Jobs' title, got: %q", out)
 	}
 }
 
-func TestAuthCommand_JSON(t *testing.T) {
+func TestJobsListCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "auth", "--json")
+	out, err := executeCmd(root, "jobs", "list")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	var servers []api.OAuthServerInfo
-	if err := json.Unmarshal([]byte(out), &servers); err != nil {
-		t.Fatalf("expected valid JSON, got: %v\nOutput: %q", err, out)
+	if !strings.Contains(out, "Scheduled Jobs") {
+		t.Errorf("expected 'Scheduled Jobs' title, got: %q", out)
 	}
-	if len(servers) != 2 {
-		t.Errorf("expected 2 OAuth servers, got %d", len(servers))
+	if !strings.Contains(out, "nightly-backup") {
+		t.Errorf("expected 'nightly-backup' job, got: %q", out)
 	}
 }
 
-func TestAuthCommand_Empty(t *testing.T) {
+func TestJobsListCommand_Workdir(t *testing.T) {
 	ts := newMockServer(map[string]http.HandlerFunc{
-		"/auth": func(w http.ResponseWriter, r *http.Request) {
-			jsonOK(w, []api.OAuthServerInfo{})
+		"/jobs": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.Job{{
+				ID: 1, Name: "deploy", Command: "make deploy", Schedule: "@daily", Enabled: true,
+				Workdir: "/srv/app", EffectiveWorkdir: "/srv/app",
+			}})
 		},
 	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "auth")
+	out, err := executeCmd(root, "jobs", "list")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No OAuth") {
-		t.Errorf("expected empty OAuth message, got: %q", out)
+	if !strings.Contains(out, "Workdir") || !strings.Contains(out, "/srv/app") {
+		t.Errorf("expected the job's workdir, got: %q", out)
 	}
 }
 
-func TestAuthStatusCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsListCommand_DependsOn(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.Job{
+				{ID: 1, Name: "backup", Command: "backup.sh", Schedule: "@daily", Enabled: true},
+				{ID: 2, Name: "upload", Command: "upload.sh", Enabled: true, DependsOn: "backup"},
+				{ID: 3, Name: "report", Command: "report.sh", Schedule: "@weekly", Enabled: true, DependsOn: "upload"},
+			})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "auth", "status", "linear")
+	out, err := executeCmd(newTestRootCmd(ts), "jobs", "list")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	// auth status outputs raw JSON
-	if !strings.Contains(out, "authenticated") {
-		t.Errorf("expected 'authenticated' in output, got: %q", out)
+	if !strings.Contains(out, "after backup") || !strings.Contains(out, "@weekly, after upload") {
+		t.Errorf("expected each job's parent in the schedule column, got: %q", out)
 	}
 }
 
-func TestAuthLogoutCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsListCommand_Paused(t *testing.T) {
+	until := time.Now().Add(2 * time.Hour)
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.Job{
+				{ID: 1, Name: "backup", Command: "backup.sh", Schedule: "@hourly", Enabled: true, Paused: true, PausedUntil: &until},
+				{ID: 2, Name: "sync", Command: "sync.sh", Schedule: "@hourly", Enabled: true, Paused: true},
+			})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "auth", "logout", "linear")
+	out, err := executeCmd(root, "jobs", "list")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Logged out") {
-		t.Errorf("expected logout success message, got: %q", out)
+	if !strings.Contains(out, "paused (resumes in 2h 0m)") {
+		t.Errorf("expected a timed pause to show when it ends, got: %q", out)
+	}
+	if strings.Contains(out, "disabled") || !strings.Contains(out, "paused ") {
+		t.Errorf("expected paused jobs not to show as disabled, got: %q", out)
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Tools / Prompts / Resources commands
-// ---------------------------------------------------------------------------
-
-func TestToolsCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsPauseCommand(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]*time.Time
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs/": func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			gotBody = nil
+			json.NewDecoder(r.Body).Decode(&gotBody)
+			jsonOK(w, map[string]string{"status": "ok"})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "tools")
+	before := time.Now()
+	out, err := executeCmd(newTestRootCmd(ts), "jobs", "pause", "backup", "--for", "2h")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Tools") {
-		t.Errorf("expected 'Tools' title, got: %q", out)
+	if gotPath != "/jobs/backup/pause" {
+		t.Errorf("unexpected request path %q", gotPath)
 	}
-	if !strings.Contains(out, "filesystem") {
-		t.Errorf("expected 'filesystem' server, got: %q", out)
+	until := gotBody["until"]
+	if until == nil || until.Before(before.Add(2*time.Hour)) || until.After(time.Now().Add(2*time.Hour)) {
+		t.Errorf("expected a pause ending in 2h, got %v", until)
+	}
+	if !strings.Contains(out, "paused until") {
+		t.Errorf("expected confirmation, got: %q", out)
 	}
-}
-
-func TestToolsCommand_ServerFilter(t *testing.T) {
-	ts := newMockServer(nil)
-	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "tools", "--server", "filesystem")
-	if err != nil {
+	if _, err := executeCmd(newTestRootCmd(ts), "jobs", "pause", "backup"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "filesystem") {
-		t.Errorf("expected filtered server, got: %q", out)
-	}
-	// Other servers should not appear (brave-search etc.)
-	if strings.Contains(out, "brave-search") {
-		t.Errorf("expected 'brave-search' to be filtered out, got: %q", out)
+	if gotBody["until"] != nil {
+		t.Errorf("expected a pause without --for to last until resumed, got %v", gotBody["until"])
 	}
-}
-
-func TestToolsCommand_NoMatch(t *testing.T) {
-	ts := newMockServer(nil)
-	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "tools", "--server", "nonexistent")
+	out, err = executeCmd(newTestRootCmd(ts), "jobs", "resume", "backup")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No server found") {
-		t.Errorf("expected no-match message, got: %q", out)
+	if gotPath != "/jobs/backup/resume" || !strings.Contains(out, "resumed") {
+		t.Errorf("expected a resume request and confirmation, got %q: %q", gotPath, out)
 	}
 }
 
-func TestPromptsCommand(t *testing.T) {
+func TestJobsLogsCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "prompts")
+	out, err := executeCmd(root, "jobs", "logs")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Prompts") {
-		t.Errorf("expected 'Prompts' title, got: %q", out)
+	if !strings.Contains(out, "Job Logs") {
+		t.Errorf("expected 'Job Logs' title, got: %q", out)
+	}
+	if !strings.Contains(out, "Backup completed") {
+		t.Errorf("expected log output, got: %q", out)
 	}
 }
 
-func TestPromptsCommand_None(t *testing.T) {
-	ts := newMockServer(map[string]http.HandlerFunc{
-		"/mcp-servers": func(w http.ResponseWriter, r *http.Request) {
-			servers := []api.MCPServerStatus{
-				{Name: "srv1", Enabled: true, Connected: true, ToolCount: 5},
-			}
-			jsonOK(w, servers)
-		},
+// mixedJobLogs serves a window with successes, failures and a skip
+func mixedJobLogs(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	zero, one := 0, 1
+	reason := "previous run still in progress"
+	jsonOK(w, []api.JobExecution{
+		{ID: 5, JobID: 1, JobName: "backup", StartedAt: now, EndedAt: &now, ExitCode: &one, Stderr: "disk full"},
+		{ID: 4, JobID: 1, JobName: "backup", StartedAt: now, EndedAt: &now, ExitCode: &zero, Stdout: "backup ok"},
+		{ID: 3, JobID: 1, JobName: "backup", StartedAt: now, EndedAt: &now, ExitCode: &zero, Stdout: "backup ok"},
+		{ID: 2, JobID: 2, JobName: "sync", StartedAt: now, EndedAt: &now, Error: &reason, Status: "skipped"},
+		{ID: 1, JobID: 2, JobName: "sync", StartedAt: now},
 	})
+}
+
+func TestJobsLogsCommand_FailedOnly(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{"/jobs/logs": mixedJobLogs})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "prompts")
+	out, err := executeCmd(root, "jobs", "logs", "--failed-only")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No prompts") {
-		t.Errorf("expected no-prompts message, got: %q", out)
+	if !strings.Contains(out, "disk full") || !strings.Contains(out, "skipped") {
+		t.Errorf("expected the failure and the skip, got: %q", out)
+	}
+	if strings.Contains(out, "backup ok") || strings.Contains(out, "running") {
+		t.Errorf("expected successes and running executions to be filtered out, got: %q", out)
 	}
 }
 
-func TestResourcesCommand_None(t *testing.T) {
-	ts := newMockServer(map[string]http.HandlerFunc{
-		"/mcp-servers": func(w http.ResponseWriter, r *http.Request) {
-			servers := []api.MCPServerStatus{
-				{Name: "srv1", Enabled: true, Connected: true, ToolCount: 5},
-			}
-			jsonOK(w, servers)
-		},
-	})
+func TestJobsLogsCommand_Summary(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{"/jobs/logs": mixedJobLogs})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "resources")
+	out, err := executeCmd(root, "jobs", "logs", "--summary", "-o", "json")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "No resources") {
-		t.Errorf("expected no-resources message, got: %q", out)
+	var summaries []jobLogSummary
+	if err := json.Unmarshal([]byte(out), &summaries); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	want := []jobLogSummary{
+		{Job: "backup", Runs: 3, Succeeded: 2, Failed: 1, SuccessRate: 200.0 / 3},
+		{Job: "sync", Runs: 1, Failed: 1, Running: 1},
+	}
+	if !reflect.DeepEqual(summaries, want) {
+		t.Errorf("got %+v, want %+v", summaries, want)
 	}
-}
-
-// ---------------------------------------------------------------------------
-// Tests: Hosts / Usage / Provider / Jobs
-// ---------------------------------------------------------------------------
-
-func TestHostsCommand(t *testing.T) {
-	ts := newMockServer(nil)
-	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "hosts")
+	root = newTestRootCmd(ts)
+	out, err = executeCmd(root, "jobs", "logs", "--summary")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Connected Hosts") {
-		t.Errorf("expected title 'Connected Hosts', got: %q", out)
-	}
-	if !strings.Contains(out, "master") {
-		t.Errorf("expected 'master' host, got: %q", out)
+	if !strings.Contains(out, "67%") || !strings.Contains(out, "+1 running") {
+		t.Errorf("expected the success rate and running count, got: %q", out)
 	}
-	if !strings.Contains(out, "slave-1") {
-		t.Errorf("expected 'slave-1' host, got: %q", out)
+
+	root = newTestRootCmd(ts)
+	if _, err := executeCmd(root, "jobs", "logs", "--summary", "--failed-only"); err == nil {
+		t.Error("expected --summary and --failed-only to be rejected together")
 	}
 }
 
-func TestUsageCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsLogsCommand_Truncated(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs/logs": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.JobExecution{{
+				ID: 1, JobID: 1, JobName: "chatty", StartedAt: time.Now(),
+				Stdout: "start\n[truncated 9000 bytes]\nend", StdoutTruncated: 9000,
+			}})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "usage")
+	out, err := executeCmd(root, "jobs", "logs")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Usage Summary") {
-		t.Errorf("expected 'Usage Summary' title, got: %q", out)
-	}
-	if !strings.Contains(out, "openai") {
-		t.Errorf("expected 'openai' provider, got: %q", out)
-	}
-	if !strings.Contains(out, "1.234") {
-		t.Errorf("expected total cost '1.234', got: %q", out)
+	if !strings.Contains(out, "[truncated]") {
+		t.Errorf("expected truncated output to be marked, got: %q", out)
 	}
 }
 
-func TestProviderCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsLogsCommand_Skipped(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs/logs": func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			reason := "previous run still in progress"
+			jsonOK(w, []api.JobExecution{{
+				ID: 2, JobID: 1, JobName: "slow", StartedAt: now, EndedAt: &now,
+				Error: &reason, Status: "skipped",
+			}})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "provider")
+	out, err := executeCmd(root, "jobs", "logs")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	// Default is list
-	if !strings.Contains(out, "Providers") {
-		t.Errorf("expected 'Providers' title, got: %q", out)
+	if !strings.Contains(out, "skipped") || strings.Contains(out, "failed") {
+		t.Errorf("expected the fire to show as skipped, got: %q", out)
 	}
 }
 
-func TestProviderListCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsLogsCommand_InvalidJSONOutput(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs/logs": func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			code := 0
+			parseErr := "invalid JSON output: invalid character 'd' looking for beginning of value"
+			jsonOK(w, []api.JobExecution{{
+				ID: 3, JobID: 1, JobName: "count", StartedAt: now, EndedAt: &now,
+				ExitCode: &code, Stdout: "done", Error: &parseErr,
+			}})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "provider", "list")
+	out, err := executeCmd(newTestRootCmd(ts), "jobs", "logs")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Providers") {
-		t.Errorf("expected 'Providers' title, got: %q", out)
-	}
-	if !strings.Contains(out, "openai") {
-		t.Errorf("expected 'openai' provider, got: %q", out)
+	if !strings.Contains(out, "failed") || strings.Contains(out, "success") {
+		t.Errorf("expected a run whose output didn't parse to show as failed, got: %q", out)
 	}
 }
 
-func TestProviderModelsCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsRetryCommand(t *testing.T) {
+	var gotPath string
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs/executions/": func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			now := time.Now()
+			code := 0
+			retryOf := int64(7)
+			jsonOK(w, api.JobExecution{
+				ID: 8, JobID: 1, JobName: "nightly-backup", StartedAt: now, EndedAt: &now,
+				ExitCode: &code, Stdout: "Backup completed", RetryOf: &retryOf,
+			})
+		},
+	})
 	defer ts.Close()
 
 	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "provider", "models", "--service", "openai")
+	out, err := executeCmd(root, "jobs", "retry", "7")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(out, "Available Models") {
-		t.Errorf("expected 'Available Models' title, got: %q", out)
+	if gotPath != "/jobs/executions/7/retry" {
+		t.Errorf("unexpected request path %q", gotPath)
 	}
-	if !strings.Contains(out, "GPT-4") {
-		t.Errorf("expected 'GPT-4' model, got: %q", out)
+	if !strings.Contains(out, "Backup completed") || !strings.Contains(out, "succeeded") {
+		t.Errorf("expected the retry's output and outcome, got: %q", out)
+	}
+
+	if _, err := executeCmd(newTestRootCmd(ts), "jobs", "retry", "latest"); err == nil {
+		t.Error("expected an invalid execution ID to be rejected")
 	}
 }
 
-func TestJobsCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsNextCommand(t *testing.T) {
+	soon := time.Now().Add(90 * time.Minute)
+	later := time.Now().Add(26 * time.Hour)
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.Job{
+				{ID: 1, Name: "nightly-backup", Schedule: "@daily", Enabled: true, NextRun: &later},
+				{ID: 2, Name: "sync-mail", Schedule: "30 * * * *", Enabled: true, NextRun: &soon},
+				{ID: 3, Name: "cleanup", Schedule: "* * * * *", Enabled: false},
+			})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "jobs")
+	out, err := executeCmd(newTestRootCmd(ts), "jobs", "next")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	// Default is list
-	if !strings.Contains(out, "Scheduled Jobs") {
-		t.Errorf("expected 'Scheduled Jobs' title, got: %q", out)
+	if !strings.Contains(out, "sync-mail runs in 1h 29m") && !strings.Contains(out, "sync-mail runs in 1h 30m") {
+		t.Errorf("expected the soonest job and the time until it runs, got: %q", out)
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "jobs", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Next Run") || !strings.Contains(out, soon.Format("Mon Jan 2 15:04")) {
+		t.Errorf("expected a next run column, got: %q", out)
 	}
 }
 
-func TestJobsListCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsExportImportCommand(t *testing.T) {
+	var imported struct {
+		Jobs  []api.JobSpec `json:"jobs"`
+		Prune bool          `json:"prune"`
+	}
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs/import": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&imported)
+			jsonOK(w, map[string]interface{}{"results": []api.JobImportResult{
+				{Name: "nightly-backup", Action: "unchanged"},
+				{Name: "cleanup", Action: "updated"},
+				{Name: "old-job", Action: "deleted"},
+			}})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "jobs", "list")
+	path := filepath.Join(t.TempDir(), "jobs.yaml")
+	out, err := executeCmd(newTestRootCmd(ts), "jobs", "export", path)
 	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+		t.Fatalf("export: %v", err)
 	}
-	if !strings.Contains(out, "Scheduled Jobs") {
-		t.Errorf("expected 'Scheduled Jobs' title, got: %q", out)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if !strings.Contains(out, "nightly-backup") {
-		t.Errorf("expected 'nightly-backup' job, got: %q", out)
+	if !strings.Contains(string(data), "name: nightly-backup") || !strings.Contains(string(data), "enabled: false") {
+		t.Errorf("expected YAML job definitions, got:\n%s", data)
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "jobs", "import", path, "--prune")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !imported.Prune || len(imported.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs imported with prune, got %+v", imported)
+	}
+	if cleanup := imported.Jobs[1]; cleanup.Name != "cleanup" || cleanup.Enabled == nil || *cleanup.Enabled {
+		t.Errorf("expected the disabled cleanup job to round-trip, got %+v", cleanup)
+	}
+	if !strings.Contains(out, "1 unchanged, 1 updated, 1 deleted") {
+		t.Errorf("expected a result summary, got: %q", out)
 	}
 }
 
-func TestJobsLogsCommand(t *testing.T) {
-	ts := newMockServer(nil)
+func TestJobsImportCommand_Invalid(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/jobs/import": func(w http.ResponseWriter, r *http.Request) {
+			jsonStatus(w, http.StatusBadRequest, map[string]interface{}{
+				"error": "invalid job definitions, nothing was applied",
+				"results": []api.JobImportResult{
+					{Name: "typo", Action: "invalid", Error: "invalid schedule: expected 5 fields"},
+				},
+			})
+		},
+	})
 	defer ts.Close()
 
-	root := newTestRootCmd(ts)
-	out, err := executeCmd(root, "jobs", "logs")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if !strings.Contains(out, "Job Logs") {
-		t.Errorf("expected 'Job Logs' title, got: %q", out)
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	os.WriteFile(path, []byte(`[{"name": "typo", "schedule": "* * * *", "command": "true"}]`), 0644)
+
+	out, err := executeCmd(newTestRootCmd(ts), "jobs", "import", path)
+	if err == nil || !strings.Contains(err.Error(), "nothing was applied") {
+		t.Fatalf("expected the import to be rejected, got %v", err)
 	}
-	if !strings.Contains(out, "Backup completed") {
-		t.Errorf("expected log output, got: %q", out)
+	if !strings.Contains(out, "expected 5 fields") {
+		t.Errorf("expected the per-job error, got: %q", out)
 	}
 }
 
@@ -2002,6 +3917,75 @@ func TestSlaveListCommand_Empty(t *testing.T) {
 	}
 }
 
+func TestSlaveLogsCommand(t *testing.T) {
+	var gotQuery string
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/slaves/logs/": func(w http.ResponseWriter, r *http.Request) {
+			gotQuery = r.URL.RawQuery
+			jsonOK(w, api.SlaveLogs{
+				Hostname: "node-1",
+				Status:   &api.Status{Version: "v1.2.3", Uptime: "2h", TotalTools: 12},
+				Entries: []logger.Entry{
+					{Time: time.Now(), Level: "WARN", Message: "Reconnection failed", Attrs: "attempt=3"},
+				},
+			})
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "slave", "logs", "node-1", "-n", "20", "--level", "warn")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"node-1", "v1.2.3", "Reconnection failed", "attempt=3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got: %q", want, out)
+		}
+	}
+	if !strings.Contains(gotQuery, "limit=20") || !strings.Contains(gotQuery, "level=warn") {
+		t.Errorf("expected limit and level in query, got %q", gotQuery)
+	}
+}
+
+func TestSlaveTestCommand(t *testing.T) {
+	var gotPath string
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/slaves/test/": func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			jsonOK(w, api.SlaveTestResult{
+				Hostname:  "node-1",
+				Status:    "connected",
+				LatencyMs: 12.5,
+				ToolCount: 7,
+				ExpiresAt: time.Now().AddDate(0, 0, 200).Format(time.RFC3339),
+			})
+		},
+	})
+	defer ts.Close()
+
+	out, err := executeCmd(newTestRootCmd(ts), "slave", "test", "node-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/slaves/test/node-1" {
+		t.Errorf("unexpected path %q", gotPath)
+	}
+	for _, want := range []string{"node-1", "connected", "12.5ms", "7"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got: %q", want, out)
+		}
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "slave", "test", "node-1", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, `"latency_ms": 12.5`) {
+		t.Errorf("expected JSON output, got: %q", out)
+	}
+}
+
 func TestSlavePendingCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
@@ -2054,6 +4038,35 @@ func TestSlaveApproveCommand(t *testing.T) {
 	}
 }
 
+func TestSlaveApproveCommand_Payload(t *testing.T) {
+	var got api.ApproveRequestBody
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/slaves/approve": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			jsonOK(w, api.ApprovePairingResponse{Success: true, Message: "Approved"})
+		},
+	})
+	defer ts.Close()
+
+	payload := pairingPayload("https://master.example.com:8766", "new node", "123456")
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "slave", "approve", payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Hostname != "new node" || got.PairingCode != "123-456" {
+		t.Errorf("expected the payload's host and normalized code to be approved, got %+v", got)
+	}
+	if !strings.Contains(out, "https://master.example.com:8766") {
+		t.Errorf("expected the master URL to be shown, got: %q", out)
+	}
+
+	root = newTestRootCmd(ts)
+	if _, err := executeCmd(root, "slave", "approve", "diane-pair:?host=new-node"); err == nil {
+		t.Error("expected an error for a payload without a code")
+	}
+}
+
 func TestSlaveDenyCommand(t *testing.T) {
 	ts := newMockServer(nil)
 	defer ts.Close()
@@ -2467,7 +4480,7 @@ func TestTryJSON_Enabled(t *testing.T) {
 		Use: "child",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			data := map[string]string{"key": "value"}
-			if tryJSON(cmd, data) {
+			if tryOutput(cmd, data) {
 				return nil
 			}
 			fmt.Println("not json")
@@ -2485,7 +4498,7 @@ func TestTryJSON_Enabled(t *testing.T) {
 		t.Errorf("expected JSON output with key/value, got: %q", out)
 	}
 	if strings.Contains(out, "not json") {
-		t.Errorf("expected tryJSON to short-circuit, but 'not json' appeared")
+		t.Errorf("expected tryOutput to short-circuit, but 'not json' appeared")
 	}
 }
 
@@ -2497,7 +4510,7 @@ func TestTryJSON_Disabled(t *testing.T) {
 		Use: "child",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			data := map[string]string{"key": "value"}
-			if tryJSON(cmd, data) {
+			if tryOutput(cmd, data) {
 				return nil
 			}
 			fmt.Println("not json")
@@ -2549,7 +4562,7 @@ func TestHelpOutput_AgentSubcommands(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	for _, sub := range []string{"add", "remove", "enable", "disable", "test", "run", "info", "logs"} {
+	for _, sub := range []string{"add", "remove", "enable", "disable", "test", "run", "chat", "info", "logs"} {
 		if !strings.Contains(out, sub) {
 			t.Errorf("expected agent help to list '%s', got: %q", sub, out)
 		}
@@ -2651,3 +4664,210 @@ func TestHelpOutput_JobsSubcommands(t *testing.T) {
 		}
 	}
 }
+
+func TestCertExpiry(t *testing.T) {
+	soon := time.Now().Add(10*24*time.Hour + time.Hour)
+	later := time.Now().AddDate(1, 0, 0)
+	past := time.Now().Add(-48 * time.Hour)
+
+	if got := certExpiry(soon.Format(time.RFC3339)); !strings.HasSuffix(got, "(10d left)") {
+		t.Errorf("expected days-left marker for soon-expiring cert, got %q", got)
+	}
+	if got := certExpiry(later.Format(time.RFC3339)); got != later.Format("2006-01-02") {
+		t.Errorf("expected plain date, got %q", got)
+	}
+	if got := certExpiry(past.Format(time.RFC3339)); !strings.HasSuffix(got, "(expired)") {
+		t.Errorf("expected expired marker, got %q", got)
+	}
+	if got := certExpiry(""); got != "unknown" {
+		t.Errorf("expected unknown for missing date, got %q", got)
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		root := newTestRootCmd(ts)
+		out, err := executeCmd(root, "completion", shell)
+		if err != nil {
+			t.Fatalf("completion %s: unexpected error: %v", shell, err)
+		}
+		if !strings.Contains(out, "diane") {
+			t.Errorf("completion %s: expected a script for diane, got: %q", shell, out)
+		}
+	}
+
+	root := newTestRootCmd(ts)
+	if _, err := executeCmd(root, "completion", "tcsh"); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestDynamicCompletion(t *testing.T) {
+	ts := newMockServer(nil)
+	defer ts.Close()
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"restart", ""}, []string{"filesystem", "brave-search"}},
+		{[]string{"agent", "run", ""}, []string{"codey", "researcher"}},
+		{[]string{"context", "set-default", ""}, []string{"personal", "work"}},
+	}
+	for _, tt := range tests {
+		root := newTestRootCmd(ts)
+		out, err := executeCmd(root, append([]string{cobra.ShellCompRequestCmd}, tt.args...)...)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tt.args, err)
+		}
+		for _, name := range tt.want {
+			if !strings.Contains(out, name+"\n") {
+				t.Errorf("%v: expected completion %q, got: %q", tt.args, name, out)
+			}
+		}
+	}
+
+	// Only the first argument is completed
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, cobra.ShellCompRequestCmd, "agent", "run", "codey", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "researcher") {
+		t.Errorf("expected no completions for the prompt, got: %q", out)
+	}
+}
+
+func TestConfigShowCommand(t *testing.T) {
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/config": func(w http.ResponseWriter, r *http.Request) {
+			info := api.ConfigInfo{Path: "/home/me/.diane/config.json", EnvOverrides: []string{"DIANE_TOOL_TIMEOUT"}}
+			info.Config.HTTP.Port = 8080
+			info.Config.HTTP.APIKey = "********"
+			info.Config.Proxy.ToolTimeout = 90
+			jsonOK(w, info)
+		},
+	})
+	defer ts.Close()
+
+	root := newTestRootCmd(ts)
+	out, err := executeCmd(root, "config", "show")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"/home/me/.diane/config.json", "DIANE_TOOL_TIMEOUT", `"tool_timeout": 90`, `"api_key": "********"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
+	}
+
+	root = newTestRootCmd(ts)
+	out, err = executeCmd(root, "config", "show", "-o", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var info api.ConfigInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", out, err)
+	}
+	if info.Config.HTTP.Port != 8080 {
+		t.Errorf("unexpected config in JSON output: %+v", info.Config)
+	}
+}
+
+func TestMCPClientsCommand(t *testing.T) {
+	sent := time.Now().Add(-10 * time.Second)
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/debug/sse-clients": func(w http.ResponseWriter, r *http.Request) {
+			jsonOK(w, []api.SSEClient{
+				{ID: "a1", Context: "personal", Initialized: true, ConnectedAt: time.Now().Add(-time.Hour), LastSendAt: &sent, FramesSent: 240, MessagesSent: 12, MessagesDropped: 3},
+				{ID: "b2", ConnectedAt: time.Now()},
+			})
+		},
+	})
+	defer ts.Close()
+
+	out, err := executeCmd(newTestRootCmd(ts), "mcp", "clients")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"SSE Clients", "a1", "personal", "240", "12", "10s ago", "(not initialized)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
+	}
+
+	empty := newMockServer(map[string]http.HandlerFunc{
+		"/debug/sse-clients": func(w http.ResponseWriter, r *http.Request) { jsonOK(w, []api.SSEClient{}) },
+	})
+	defer empty.Close()
+	if out, _ := executeCmd(newTestRootCmd(empty), "mcp", "clients"); !strings.Contains(out, "No SSE clients connected") {
+		t.Errorf("expected the empty message, got: %q", out)
+	}
+}
+
+func TestNotifyTemplateCommands(t *testing.T) {
+	var saved api.NotifyTemplate
+	var testReq struct {
+		Name string         `json:"name"`
+		Data map[string]any `json:"data"`
+	}
+	ts := newMockServer(map[string]http.HandlerFunc{
+		"/notify/templates": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				json.NewDecoder(r.Body).Decode(&saved)
+				jsonOK(w, map[string]string{"status": "ok"})
+				return
+			}
+			jsonOK(w, []api.NotifyTemplate{
+				{Name: "job-failed", Body: "{{.job_name}} failed\n(exit {{.exit_code}})", UpdatedAt: time.Now().Add(-time.Hour)},
+			})
+		},
+		"/notify/templates/test": func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&testReq)
+			if testReq.Name != "job-failed" {
+				jsonStatus(w, http.StatusNotFound, map[string]string{"error": "notify template not found: " + testReq.Name})
+				return
+			}
+			jsonOK(w, map[string]string{"message": "backup failed (exit 2)"})
+		},
+	})
+	defer ts.Close()
+
+	if _, err := executeCmd(newTestRootCmd(ts), "notify", "template", "add", "job-failed", "{{.job_name}} failed"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if saved.Name != "job-failed" || saved.Body != "{{.job_name}} failed" {
+		t.Errorf("unexpected template sent: %+v", saved)
+	}
+	if _, err := executeCmd(newTestRootCmd(ts), "notify", "template", "add", "empty"); err == nil {
+		t.Error("expected add without a template or --file to fail")
+	}
+
+	out, err := executeCmd(newTestRootCmd(ts), "notify", "template", "list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, want := range []string{"Notify Templates", "job-failed", "{{.job_name}} failed (exit {{.exit_code}})", "1h"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %q", want, out)
+		}
+	}
+
+	out, err = executeCmd(newTestRootCmd(ts), "notify", "template", "test", "job-failed", "--data", `{"job_name": "backup", "exit_code": 2}`)
+	if err != nil {
+		t.Fatalf("test: %v", err)
+	}
+	if !strings.Contains(out, "backup failed (exit 2)") || testReq.Data["job_name"] != "backup" || testReq.Data["exit_code"] != float64(2) {
+		t.Errorf("unexpected test output %q for request %+v", out, testReq)
+	}
+	if _, err := executeCmd(newTestRootCmd(ts), "notify", "template", "test", "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected an unknown template to be reported, got %v", err)
+	}
+	if _, err := executeCmd(newTestRootCmd(ts), "notify", "template", "test", "job-failed", "--data", "{"); err == nil {
+		t.Error("expected invalid --data to be rejected")
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/diane-assistant/diane/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate a shell completion script",
+		Long: `Print a completion script for the given shell. Server, agent and context
+names are completed from the running daemon.
+
+  bash:       source <(diane completion bash)
+  zsh:        diane completion zsh > "${fpath[1]}/_diane"
+  fish:       diane completion fish > ~/.config/fish/completions/diane.fish
+  powershell: diane completion powershell | Out-String | Invoke-Expression`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, out := cmd.Root(), cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("unsupported shell %q", args[0])
+		},
+	}
+}
+
+// completeFirstArg completes a command's first argument with the names
+// returned by list. Later arguments and daemon errors complete nothing.
+func completeFirstArg(list func() ([]string, error)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, _ := list()
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// mcpServerNames lists the names of the daemon's MCP servers
+func mcpServerNames(client *api.Client) func() ([]string, error) {
+	return func() ([]string, error) {
+		servers, err := client.GetMCPServers()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(servers))
+		for _, s := range servers {
+			names = append(names, s.Name)
+		}
+		return names, nil
+	}
+}
+
+// agentNames lists the names of the configured ACP agents
+func agentNames(client *api.Client) func() ([]string, error) {
+	return func() ([]string, error) {
+		agents, err := client.ListAgents()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(agents))
+		for _, a := range agents {
+			names = append(names, a.Name)
+		}
+		return names, nil
+	}
+}
+
+// contextNames lists the names of the daemon's contexts
+func contextNames(client *api.Client) func() ([]string, error) {
+	return func() ([]string, error) {
+		contexts, err := client.ListContexts()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(contexts))
+		for _, c := range contexts {
+			names = append(names, c.Name)
+		}
+		return names, nil
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diane-assistant/diane/internal/api"
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// ApplyConfigFlag handles the global --config flag. Like --profile, the
+// config file is read before Cobra parses flags, so the flag is removed from
+// args and exported as DIANE_CONFIG (made absolute, so a re-executed daemon
+// finds the same file). Arguments after "--" are left alone.
+func ApplyConfigFlag(args []string) ([]string, error) {
+	out := make([]string, 0, len(args))
+	path := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			out = append(out, args[i:]...)
+			i = len(args)
+		case arg == "--config" && i+1 < len(args):
+			path = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--config="):
+			path = strings.TrimPrefix(arg, "--config=")
+		default:
+			out = append(out, arg)
+		}
+	}
+	if path != "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --config path %q: %w", path, err)
+		}
+		os.Setenv(config.ConfigEnv, abs)
+	}
+	return out, nil
+}
+
+func newConfigCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect Diane configuration",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Show the configuration the daemon is running with",
+		Long: `Show the daemon's effective configuration: the config file it read, merged
+with environment variable overrides. Secrets are masked. Settings only read
+at startup take effect after 'diane restart-daemon'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info, err := client.GetConfig()
+			if err != nil {
+				return fmt.Errorf("failed to get config: %w", err)
+			}
+
+			if tryOutput(cmd, info) {
+				return nil
+			}
+
+			fmt.Println(titleStyle.Render("Configuration"))
+			fmt.Println()
+			fmt.Printf("  File:          %s\n", info.Path)
+			if len(info.EnvOverrides) > 0 {
+				fmt.Printf("  Env overrides: %s\n", strings.Join(info.EnvOverrides, ", "))
+			} else {
+				fmt.Printf("  Env overrides: none\n")
+			}
+			fmt.Println()
+
+			data, err := json.MarshalIndent(info.Config, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode config: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	})
+
+	return cmd
+}
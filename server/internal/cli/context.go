@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/diane-assistant/diane/internal/api"
@@ -65,9 +68,10 @@ func newContextCmd(client *api.Client) *cobra.Command {
 
 	// set-default subcommand
 	setDefaultCmd := &cobra.Command{
-		Use:   "set-default <name>",
-		Short: "Set the default context",
-		Args:  cobra.ExactArgs(1),
+		Use:               "set-default <name>",
+		Short:             "Set the default context",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeFirstArg(contextNames(client)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			if err := client.SetDefaultContext(name); err != nil {
@@ -90,7 +94,7 @@ func newContextCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get context info: %w", err)
 			}
 
-			if tryJSON(cmd, detail) {
+			if tryOutput(cmd, detail) {
 				return nil
 			}
 
@@ -151,7 +155,7 @@ func newContextCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get available servers: %w", err)
 			}
 
-			if tryJSON(cmd, servers) {
+			if tryOutput(cmd, servers) {
 				return nil
 			}
 
@@ -199,6 +203,100 @@ func newContextCmd(client *api.Client) *cobra.Command {
 		},
 	}
 
+	// diff subcommand
+	diffCmd := &cobra.Command{
+		Use:   "diff <a> <b>",
+		Short: "Compare the tools two contexts enable",
+		Long: `Show which tools are enabled in only one of two contexts, or in both,
+grouped by server. A tool counts as enabled when its server is enabled in
+the context and the tool isn't switched off there. Servers whose tools
+were never synced into either context are compared as a whole ("*").`,
+		Args: cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 1 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			names, _ := contextNames(client)()
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := client.GetContextDetail(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get context %s: %w", args[0], err)
+			}
+			b, err := client.GetContextDetail(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to get context %s: %w", args[1], err)
+			}
+
+			diff := diffContexts(a, b)
+			if tryOutput(cmd, diff) {
+				return nil
+			}
+
+			showCommon, _ := cmd.Flags().GetBool("show-common")
+			printContextDiff(diff, showCommon)
+			return nil
+		},
+	}
+	diffCmd.Flags().Bool("show-common", false, "List the tools enabled in both contexts, not just their count")
+
+	// init-options subcommand
+	initOptionsCmd := &cobra.Command{
+		Use:   "init-options <context> <server> [json]",
+		Short: "Show or set the init options a context passes to a server",
+		Long: `Show or set the options a context passes to a stdio server's initialize
+call as initializationOptions. When a context sets options, its tool calls
+to that server go to a separate instance started with them, so one server
+entry can be configured differently per context.
+
+  diane context init-options work files '{"rootDir": "/home/me/work"}'
+  diane context init-options work files --clear`,
+		Args:              cobra.RangeArgs(2, 3),
+		ValidArgsFunction: completeFirstArg(contextNames(client)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contextName, serverName := args[0], args[1]
+			clearOptions, _ := cmd.Flags().GetBool("clear")
+
+			if len(args) == 3 || clearOptions {
+				if len(args) == 3 && clearOptions {
+					return fmt.Errorf("give either options or --clear, not both")
+				}
+				var options map[string]any
+				if len(args) == 3 {
+					if err := json.Unmarshal([]byte(args[2]), &options); err != nil {
+						return fmt.Errorf("options must be a JSON object: %w", err)
+					}
+				}
+				if err := client.SetContextServerInitOptions(contextName, serverName, options); err != nil {
+					return fmt.Errorf("failed to set init options: %w", err)
+				}
+				if len(options) == 0 {
+					PrintSuccess(fmt.Sprintf("Cleared init options for %s in context '%s'", serverName, contextName))
+				} else {
+					PrintSuccess(fmt.Sprintf("Set init options for %s in context '%s'", serverName, contextName))
+				}
+				return nil
+			}
+
+			options, err := client.GetContextServerInitOptions(contextName, serverName)
+			if err != nil {
+				return fmt.Errorf("failed to get init options: %w", err)
+			}
+			if tryOutput(cmd, options) {
+				return nil
+			}
+			if len(options) == 0 {
+				fmt.Printf("No init options for %s in context '%s'.\n", serverName, contextName)
+				return nil
+			}
+			data, _ := json.MarshalIndent(options, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+	initOptionsCmd.Flags().Bool("clear", false, "Remove the context's init options for the server")
+
 	cmd.AddCommand(listCmd)
 	cmd.AddCommand(createCmd)
 	cmd.AddCommand(deleteCmd)
@@ -206,6 +304,8 @@ func newContextCmd(client *api.Client) *cobra.Command {
 	cmd.AddCommand(infoCmd)
 	cmd.AddCommand(serversCmd)
 	cmd.AddCommand(syncCmd)
+	cmd.AddCommand(diffCmd)
+	cmd.AddCommand(initOptionsCmd)
 
 	return cmd
 }
@@ -216,7 +316,7 @@ func listContexts(cmd *cobra.Command, client *api.Client) error {
 		return fmt.Errorf("failed to list contexts: %w", err)
 	}
 
-	if tryJSON(cmd, contexts) {
+	if tryOutput(cmd, contexts) {
 		return nil
 	}
 
@@ -244,3 +344,138 @@ func listContexts(cmd *cobra.Command, client *api.Client) error {
 
 	return nil
 }
+
+// contextServerDiff is how one server's enabled tools differ between two
+// contexts
+type contextServerDiff struct {
+	Server string   `json:"server"`
+	OnlyA  []string `json:"only_a"`
+	OnlyB  []string `json:"only_b"`
+	Both   []string `json:"both"`
+}
+
+// contextDiff compares the tools enabled in contexts A and B
+type contextDiff struct {
+	A       string              `json:"a"`
+	B       string              `json:"b"`
+	Servers []contextServerDiff `json:"servers"`
+}
+
+// allToolsMarker stands for every tool of a server whose tools were never
+// synced into either context, so only the server's enablement is known
+const allToolsMarker = "*"
+
+// diffContexts compares two contexts' effective tool enablement. Tools
+// without an override in a context are enabled there, as long as their
+// server is.
+func diffContexts(a, b *api.ContextDetailResponse) contextDiff {
+	serversA := contextServersByName(a)
+	serversB := contextServersByName(b)
+
+	names := make([]string, 0, len(serversA)+len(serversB))
+	for name := range serversA {
+		names = append(names, name)
+	}
+	for name := range serversB {
+		if _, ok := serversA[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	diff := contextDiff{A: a.Context.Name, B: b.Context.Name, Servers: []contextServerDiff{}}
+	for _, name := range names {
+		srvA, srvB := serversA[name], serversB[name]
+
+		toolSet := make(map[string]bool)
+		for _, srv := range []*api.ContextServerResponse{srvA, srvB} {
+			if srv != nil {
+				for _, t := range srv.Tools {
+					toolSet[t.Name] = true
+				}
+			}
+		}
+		if len(toolSet) == 0 {
+			toolSet[allToolsMarker] = true
+		}
+		tools := make([]string, 0, len(toolSet))
+		for t := range toolSet {
+			tools = append(tools, t)
+		}
+		sort.Strings(tools)
+
+		sd := contextServerDiff{Server: name, OnlyA: []string{}, OnlyB: []string{}, Both: []string{}}
+		for _, t := range tools {
+			inA, inB := contextToolEnabled(srvA, t), contextToolEnabled(srvB, t)
+			switch {
+			case inA && inB:
+				sd.Both = append(sd.Both, t)
+			case inA:
+				sd.OnlyA = append(sd.OnlyA, t)
+			case inB:
+				sd.OnlyB = append(sd.OnlyB, t)
+			}
+		}
+		if len(sd.OnlyA)+len(sd.OnlyB)+len(sd.Both) > 0 {
+			diff.Servers = append(diff.Servers, sd)
+		}
+	}
+	return diff
+}
+
+func contextServersByName(detail *api.ContextDetailResponse) map[string]*api.ContextServerResponse {
+	servers := make(map[string]*api.ContextServerResponse, len(detail.Servers))
+	for i := range detail.Servers {
+		servers[detail.Servers[i].Name] = &detail.Servers[i]
+	}
+	return servers
+}
+
+// contextToolEnabled reports whether a tool is enabled through a context's
+// server entry, which is nil if the server isn't in the context
+func contextToolEnabled(srv *api.ContextServerResponse, tool string) bool {
+	if srv == nil || !srv.Enabled {
+		return false
+	}
+	for _, t := range srv.Tools {
+		if t.Name == tool {
+			return t.Enabled
+		}
+	}
+	return true
+}
+
+func printContextDiff(diff contextDiff, showCommon bool) {
+	fmt.Println()
+	fmt.Printf("  %s\n", titleStyle.Render(fmt.Sprintf("Context diff: %s vs %s", diff.A, diff.B)))
+
+	differences := 0
+	for _, sd := range diff.Servers {
+		if len(sd.OnlyA) == 0 && len(sd.OnlyB) == 0 && !showCommon {
+			continue
+		}
+		differences += len(sd.OnlyA) + len(sd.OnlyB)
+
+		fmt.Println()
+		fmt.Println(headerStyle.Render(sd.Server))
+		if len(sd.OnlyA) > 0 {
+			fmt.Printf("  only in %s: %s\n", diff.A, strings.Join(sd.OnlyA, ", "))
+		}
+		if len(sd.OnlyB) > 0 {
+			fmt.Printf("  only in %s: %s\n", diff.B, strings.Join(sd.OnlyB, ", "))
+		}
+		if showCommon && len(sd.Both) > 0 {
+			fmt.Printf("  in both: %s\n", strings.Join(sd.Both, ", "))
+		} else if len(sd.Both) > 0 {
+			fmt.Printf("  in both: %d tool(s)\n", len(sd.Both))
+		}
+	}
+
+	fmt.Println()
+	if differences == 0 {
+		PrintSuccess("Both contexts enable the same tools")
+	} else {
+		fmt.Printf("  %d tool(s) enabled in only one context\n", differences)
+	}
+	fmt.Println()
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/diane-assistant/diane/internal/api"
@@ -13,13 +14,15 @@ func newDoctorCmd(client *api.Client) *cobra.Command {
 		Use:   "doctor",
 		Short: "Run diagnostic checks on the Diane installation",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			report, err := client.Doctor()
+			// Backend checks call out to every configured provider, so allow
+			// more than the default request timeout
+			report, err := client.WithTimeout(30 * time.Second).Doctor()
 			if err != nil {
 				PrintError(fmt.Sprintf("Could not run diagnostics: %v", err))
 				return nil
 			}
 
-			if tryJSON(cmd, report) {
+			if tryOutput(cmd, report) {
 				return nil
 			}
 
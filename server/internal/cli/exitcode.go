@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/diane-assistant/diane/internal/api"
+)
+
+// Exit codes returned by diane-ctl so scripts can react to specific failures.
+const (
+	ExitOK                = 0
+	ExitError             = 1 // any failure not covered below
+	ExitDaemonUnreachable = 3
+	ExitUnauthorized      = 4
+	ExitNotFound          = 5
+	ExitConflict          = 6
+)
+
+// ExitCode maps an error returned from the root command to a process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, api.ErrDaemonUnreachable):
+		return ExitDaemonUnreachable
+	case errors.Is(err, api.ErrUnauthorized):
+		return ExitUnauthorized
+	case errors.Is(err, api.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, api.ErrConflict):
+		return ExitConflict
+	}
+	return ExitError
+}
@@ -2,8 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/diane-assistant/diane/internal/acp"
 	"github.com/diane-assistant/diane/internal/api"
 	"github.com/spf13/cobra"
 )
@@ -58,7 +62,7 @@ func galleryListRun(cmd *cobra.Command, client *api.Client, featured bool) error
 		return fmt.Errorf("failed to list gallery: %w", err)
 	}
 
-	if tryJSON(cmd, entries) {
+	if tryOutput(cmd, entries) {
 		return nil
 	}
 
@@ -109,7 +113,7 @@ func newGalleryInfoCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get gallery agent: %w", err)
 			}
 
-			if tryJSON(cmd, info) {
+			if tryOutput(cmd, info) {
 				return nil
 			}
 
@@ -125,6 +129,9 @@ func newGalleryInfoCmd(client *api.Client) *cobra.Command {
 			if info.InstallCmd != "" {
 				fmt.Printf("  Command:     %s\n", info.InstallCmd)
 			}
+			if info.SetupCmd != "" {
+				fmt.Printf("  Setup:       %s\n", info.SetupCmd)
+			}
 			if info.Available {
 				PrintSuccess("Available on this system")
 			} else {
@@ -169,6 +176,12 @@ func newGalleryInstallCmd(client *api.Client) *cobra.Command {
 
 			PrintSuccess(fmt.Sprintf("Agent '%s' configured", agentName))
 
+			if runInstall, _ := cmd.Flags().GetBool("run-install"); runInstall {
+				fmt.Println()
+				yes, _ := cmd.Flags().GetBool("yes")
+				return runInstallCmd(cmd, info, yes)
+			}
+
 			if info.InstallCmd != "" {
 				fmt.Printf("\n  Install the agent binary with:\n    %s\n",
 					lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render(info.InstallCmd))
@@ -181,6 +194,8 @@ func newGalleryInstallCmd(client *api.Client) *cobra.Command {
 	cmd.Flags().String("name", "", "Custom name for the agent")
 	cmd.Flags().String("workdir", "", "Working directory for the agent")
 	cmd.Flags().Int("port", 0, "Port for ACP server")
+	cmd.Flags().Bool("run-install", false, "Also install the agent on this machine (see 'diane agent install-binary')")
+	cmd.Flags().BoolP("yes", "y", false, "Run the install command without asking for confirmation")
 
 	return cmd
 }
@@ -199,3 +214,44 @@ func newGalleryRefreshCmd(client *api.Client) *cobra.Command {
 		},
 	}
 }
+
+// runInstallCmd runs a gallery agent's setup command on this machine,
+// streaming its output. The exact command is shown first and has to be
+// confirmed unless yes is set, so nothing runs without the user seeing it.
+func runInstallCmd(cmd *cobra.Command, info *acp.InstallInfo, yes bool) error {
+	if info.Installed {
+		PrintSuccess(fmt.Sprintf("Agent '%s' is already installed", info.ID))
+		return nil
+	}
+	if info.SetupCmd == "" {
+		if info.DownloadURL != "" {
+			return fmt.Errorf("agent %s has no install command; download it from %s", info.ID, info.DownloadURL)
+		}
+		return fmt.Errorf("agent %s has no install command", info.ID)
+	}
+
+	argv := strings.Fields(info.SetupCmd)
+	fmt.Printf("  Install command:\n    %s\n\n",
+		lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render(info.SetupCmd))
+
+	if !yes {
+		fmt.Print("Run this command? [y/N]: ")
+		var response string
+		fmt.Fscanln(cmd.InOrStdin(), &response)
+		if r := strings.ToLower(response); r != "y" && r != "yes" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	install := exec.Command(argv[0], argv[1:]...)
+	install.Stdin = os.Stdin
+	install.Stdout = os.Stdout
+	install.Stderr = os.Stderr
+	if err := install.Run(); err != nil {
+		return fmt.Errorf("install command failed: %w", err)
+	}
+
+	PrintSuccess(fmt.Sprintf("Agent '%s' installed", info.ID))
+	return nil
+}
@@ -19,7 +19,7 @@ func newHostsCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get hosts: %w", err)
 			}
 
-			if tryJSON(cmd, hosts) {
+			if tryOutput(cmd, hosts) {
 				return nil
 			}
 
@@ -96,7 +96,7 @@ func newUsageCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get usage summary: %w", err)
 			}
 
-			if tryJSON(cmd, summary) {
+			if tryOutput(cmd, summary) {
 				return nil
 			}
 
@@ -113,8 +113,13 @@ func newUsageCmd(client *api.Client) *cobra.Command {
 			var rows [][]string
 
 			for _, s := range summary.Summary {
+				// Agent runs aren't tied to a provider
+				provider := s.ProviderName
+				if provider == "" {
+					provider = "-"
+				}
 				rows = append(rows, []string{
-					s.ProviderName,
+					provider,
 					s.Service,
 					s.Model,
 					fmt.Sprintf("%d", s.TotalRequests),
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/diane-assistant/diane/internal/api"
+	"github.com/diane-assistant/diane/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +18,8 @@ func newInfoCmd(client *api.Client) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			home, _ := os.UserHomeDir()
 			dianeBin := filepath.Join(home, ".diane", "bin", "diane")
+			mcpURL := fmt.Sprintf("http://localhost:%d", config.MCPHTTPPort())
+			dianeDir := "~/" + config.DirName()
 
 			status := "not running"
 			httpStatus := "unavailable"
@@ -24,7 +27,7 @@ func newInfoCmd(client *api.Client) *cobra.Command {
 
 			if err := client.Health(); err == nil {
 				status = "running"
-				httpStatus = "http://localhost:8765"
+				httpStatus = mcpURL
 				if s, err := client.GetStatus(); err == nil {
 					toolCount = s.TotalTools
 				}
@@ -85,7 +88,7 @@ func newInfoCmd(client *api.Client) *cobra.Command {
     "mcp": {
       "diane-personal": {
         "type": "remote",
-        "url": "http://localhost:8765/mcp/sse?context=personal",
+        "url": "` + mcpURL + `/mcp/sse?context=personal",
         "oauth": false
       }
     }
@@ -130,24 +133,24 @@ func newInfoCmd(client *api.Client) *cobra.Command {
 			fmt.Println()
 			fmt.Println("  Diane exposes an HTTP Streamable MCP endpoint when running:")
 			fmt.Println()
-			fmt.Printf("    URL:     %s\n", valStyle.Render("http://localhost:8765/mcp"))
-			fmt.Printf("    SSE:     %s\n", valStyle.Render("http://localhost:8765/mcp/sse"))
-			fmt.Printf("    Health:  %s\n", valStyle.Render("http://localhost:8765/health"))
+			fmt.Printf("    URL:     %s\n", valStyle.Render(mcpURL+"/mcp"))
+			fmt.Printf("    SSE:     %s\n", valStyle.Render(mcpURL+"/mcp/sse"))
+			fmt.Printf("    Health:  %s\n", valStyle.Render(mcpURL+"/health"))
 			fmt.Println()
 
 			// Testing
 			fmt.Println(sectionHeader.Render("TESTING CONNECTION"))
 			fmt.Println()
 			fmt.Println("  Test HTTP endpoint:")
-			fmt.Printf("    %s\n", valStyle.Render("curl http://localhost:8765/health"))
+			fmt.Printf("    %s\n", valStyle.Render("curl "+mcpURL+"/health"))
 			fmt.Println()
 
 			// More info
 			fmt.Println(sectionHeader.Render("MORE INFO"))
 			fmt.Println()
-			fmt.Printf("  Documentation:    %s\n", dimStyle.Render("~/.diane/MCP.md"))
-			fmt.Printf("  Database:         %s\n", dimStyle.Render("~/.diane/cron.db"))
-			fmt.Printf("  Logs:             %s\n", dimStyle.Render("~/.diane/server.log"))
+			fmt.Printf("  Documentation:    %s\n", dimStyle.Render(dianeDir+"/MCP.md"))
+			fmt.Printf("  Database:         %s\n", dimStyle.Render(dianeDir+"/cron.db"))
+			fmt.Printf("  Logs:             %s\n", dimStyle.Render(dianeDir+"/server.log"))
 			fmt.Println()
 			fmt.Println("  Commands:")
 			fmt.Printf("    %s        Full status with all MCP servers\n", valStyle.Render("diane status"))
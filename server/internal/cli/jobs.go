@@ -1,10 +1,17 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/diane-assistant/diane/internal/api"
+	"github.com/diane-assistant/diane/internal/cron"
 	"github.com/spf13/cobra"
 )
 
@@ -31,9 +38,16 @@ func newJobsCmd(client *api.Client) *cobra.Command {
 	logsCmd := &cobra.Command{
 		Use:   "logs [name]",
 		Short: "Show job execution logs",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Show recent job executions, newest first.
+
+--failed-only keeps the executions that exited non-zero, hit an error or
+were skipped. --summary instead prints each job's success and failure
+counts and success rate; both work on the last --limit executions.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			limit, _ := cmd.Flags().GetInt("limit")
+			failedOnly, _ := cmd.Flags().GetBool("failed-only")
+			summary, _ := cmd.Flags().GetBool("summary")
 			name := ""
 			if len(args) > 0 {
 				name = args[0]
@@ -44,12 +58,29 @@ func newJobsCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get job logs: %w", err)
 			}
 
-			if tryJSON(cmd, logs) {
+			if summary {
+				return printJobLogSummary(cmd, logs)
+			}
+			if failedOnly {
+				failed := make([]api.JobExecution, 0, len(logs))
+				for _, l := range logs {
+					if executionFailed(l) {
+						failed = append(failed, l)
+					}
+				}
+				logs = failed
+			}
+
+			if tryOutput(cmd, logs) {
 				return nil
 			}
 
 			if len(logs) == 0 {
-				fmt.Println("No job logs found.")
+				if failedOnly {
+					fmt.Println("No failed executions found.")
+				} else {
+					fmt.Println("No job logs found.")
+				}
 				return nil
 			}
 
@@ -60,14 +91,16 @@ func newJobsCmd(client *api.Client) *cobra.Command {
 			}
 			fmt.Printf("  %s\n", titleStyle.Render(title))
 
-			headers := []string{"Job", "Status", "Duration", "Started", "Output"}
+			headers := []string{"ID", "Job", "Status", "Duration", "Started", "Workdir", "Output"}
 			var rows [][]string
 
 			for _, l := range logs {
 				status := "running"
 				duration := "-"
-				if l.EndedAt != nil {
-					if l.ExitCode != nil && *l.ExitCode == 0 {
+				if l.Status != "" {
+					status = l.Status
+				} else if l.EndedAt != nil {
+					if !executionFailed(l) {
 						status = "success"
 					} else {
 						status = "failed"
@@ -87,12 +120,25 @@ func newJobsCmd(client *api.Client) *cobra.Command {
 				if len(output) > 50 {
 					output = output[:47] + "..."
 				}
+				if l.StdoutTruncated > 0 || l.StderrTruncated > 0 {
+					output = "[truncated] " + output
+				}
+				if l.RetryOf != nil {
+					output = fmt.Sprintf("[retry of %d] %s", *l.RetryOf, output)
+				}
+
+				workdir := "-"
+				if l.Workdir != "" {
+					workdir = l.Workdir
+				}
 
 				rows = append(rows, []string{
+					strconv.FormatInt(l.ID, 10),
 					l.JobName,
 					status,
 					duration,
 					l.StartedAt.Format(time.RFC3339),
+					workdir,
 					output,
 				})
 			}
@@ -104,6 +150,83 @@ func newJobsCmd(client *api.Client) *cobra.Command {
 		},
 	}
 	logsCmd.Flags().IntP("limit", "n", 50, "Maximum number of log entries to show")
+	logsCmd.Flags().Bool("failed-only", false, "Show only failed or skipped executions")
+	logsCmd.Flags().Bool("summary", false, "Show per-job success and failure counts instead of entries")
+	logsCmd.MarkFlagsMutuallyExclusive("failed-only", "summary")
+
+	// next subcommand
+	nextCmd := &cobra.Command{
+		Use:   "next",
+		Short: "Show the job that will run next",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := client.ListJobs()
+			if err != nil {
+				return fmt.Errorf("failed to list jobs: %w", err)
+			}
+
+			var next *api.Job
+			for i := range jobs {
+				j := &jobs[i]
+				if j.NextRun != nil && (next == nil || j.NextRun.Before(*next.NextRun)) {
+					next = j
+				}
+			}
+
+			if tryOutput(cmd, next) {
+				return nil
+			}
+			if next == nil {
+				fmt.Println("No upcoming job runs.")
+				return nil
+			}
+
+			until := time.Until(*next.NextRun)
+			if until < 0 {
+				until = 0
+			}
+			PrintSuccess(fmt.Sprintf("%s runs in %s (%s)", next.Name, formatDuration(until), next.NextRun.Local().Format("Mon Jan 2 15:04")))
+			return nil
+		},
+	}
+
+	// retry subcommand
+	retryCmd := &cobra.Command{
+		Use:   "retry <execution-id>",
+		Short: "Re-run the job behind a failed execution",
+		Long: `Re-run the job behind a failed or skipped execution (IDs are shown by
+jobs logs) without waiting for its schedule. The run is recorded as a new
+execution linked to the failed one, uses the job's current command, and is
+waited for. Only shell jobs can be retried.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("invalid execution ID %q", args[0])
+			}
+
+			// The daemon runs the job to completion before answering
+			execution, err := client.WithTimeout(cron.RunTimeout + agentRunGrace).RetryJobExecution(id)
+			if err != nil {
+				return fmt.Errorf("failed to retry execution: %w", err)
+			}
+
+			if tryOutput(cmd, execution) {
+				return nil
+			}
+
+			if output := strings.TrimSpace(execution.Stdout + execution.Stderr); output != "" {
+				fmt.Println(output)
+			}
+			switch {
+			case execution.Error != nil:
+				return fmt.Errorf("retry of execution %d (execution %d) failed: %s", id, execution.ID, *execution.Error)
+			case execution.ExitCode != nil && *execution.ExitCode != 0:
+				return fmt.Errorf("retry of execution %d (execution %d) exited with code %d", id, execution.ID, *execution.ExitCode)
+			}
+			PrintSuccess(fmt.Sprintf("Retry of execution %d (execution %d) succeeded", id, execution.ID))
+			return nil
+		},
+	}
 
 	// enable subcommand
 	enableCmd := &cobra.Command{
@@ -135,10 +258,188 @@ func newJobsCmd(client *api.Client) *cobra.Command {
 		},
 	}
 
+	// pause subcommand
+	pauseCmd := &cobra.Command{
+		Use:   "pause <name>",
+		Short: "Pause a scheduled job without disabling it",
+		Long: `Stop a job firing until it is resumed or, with --for, until the given
+time has passed. Unlike disable, the job stays enabled and is shown as
+paused, and a timed pause lifts itself.`,
+		Example: `  diane jobs pause backup --for 2h
+  diane jobs pause backup`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			duration, _ := cmd.Flags().GetDuration("for")
+			if duration < 0 {
+				return fmt.Errorf("--for must be positive")
+			}
+			var until *time.Time
+			if duration > 0 {
+				t := time.Now().Add(duration)
+				until = &t
+			}
+			if err := client.PauseJob(name, until); err != nil {
+				return fmt.Errorf("failed to pause job: %w", err)
+			}
+			if until == nil {
+				PrintSuccess(fmt.Sprintf("Job '%s' paused until resumed", name))
+			} else {
+				PrintSuccess(fmt.Sprintf("Job '%s' paused until %s", name, until.Format("Mon Jan 2 15:04")))
+			}
+			return nil
+		},
+	}
+	pauseCmd.Flags().Duration("for", 0, "How long to pause for, e.g. 2h (default: until resumed)")
+
+	// resume subcommand
+	resumeCmd := &cobra.Command{
+		Use:   "resume <name>",
+		Short: "Resume a paused job",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := client.ResumeJob(name); err != nil {
+				return fmt.Errorf("failed to resume job: %w", err)
+			}
+			PrintSuccess(fmt.Sprintf("Job '%s' resumed", name))
+			return nil
+		},
+	}
+
+	// export subcommand
+	exportCmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export job definitions to a JSON or YAML file",
+		Long: `Write every job's definition (name, schedule, command, enabled, action
+type, agent, output limit, concurrency policy, timeout, environment
+variables and working directory) to a file, or to stdout
+when no file is given. The format is taken from --format, else the file
+extension (.yaml/.yml), else JSON.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			if format == "" {
+				format = formatJSON
+				if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+					format = formatYAML
+				}
+			}
+
+			jobs, err := client.ListJobs()
+			if err != nil {
+				return fmt.Errorf("failed to list jobs: %w", err)
+			}
+			specs := make([]api.JobSpec, 0, len(jobs))
+			for _, j := range jobs {
+				enabled := j.Enabled
+				spec := api.JobSpec{
+					Name:           j.Name,
+					Schedule:       j.Schedule,
+					Command:        j.Command,
+					Enabled:        &enabled,
+					ActionType:     j.ActionType,
+					MaxOutputBytes: j.MaxOutputBytes,
+					TimeoutSeconds: j.TimeoutSeconds,
+					Concurrency:    j.Concurrency,
+					Env:            j.Env,
+					Workdir:        j.Workdir,
+					DependsOn:      j.DependsOn,
+					OutputFormat:   j.OutputFormat,
+					NotifyTemplate: j.NotifyTemplate,
+				}
+				if j.AgentName != nil {
+					spec.AgentName = *j.AgentName
+				}
+				specs = append(specs, spec)
+			}
+
+			var data []byte
+			switch strings.ToLower(format) {
+			case formatJSON:
+				data, err = json.MarshalIndent(specs, "", "  ")
+				data = append(data, '\n')
+			case formatYAML:
+				data, err = marshalYAML(specs)
+			default:
+				return fmt.Errorf("invalid --format %q (expected json or yaml)", format)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to encode jobs: %w", err)
+			}
+
+			if path == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			PrintSuccess(fmt.Sprintf("Exported %d jobs to %s", len(specs), path))
+			return nil
+		},
+	}
+	exportCmd.Flags().String("format", "", "Output format: json or yaml (default: from file extension)")
+
+	// import subcommand
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create or update jobs from a JSON or YAML file",
+		Long: `Create or update jobs to match the definitions in a file written by
+jobs export. Every schedule is validated first; if any definition is invalid
+nothing is applied. With --prune, jobs not in the file are deleted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prune, _ := cmd.Flags().GetBool("prune")
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+			var specs []api.JobSpec
+			if err := unmarshalYAML(data, &specs); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", args[0], err)
+			}
+
+			results, err := client.ImportJobs(specs, prune)
+			if tryOutput(cmd, results) {
+				return err
+			}
+			if len(results) > 0 {
+				printJobImportResults(results)
+			}
+			if err != nil {
+				return err
+			}
+
+			var failed int
+			for _, r := range results {
+				if r.Action == "failed" {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d jobs failed to import", failed, len(results))
+			}
+			return nil
+		},
+	}
+	importCmd.Flags().Bool("prune", false, "Delete jobs that are not in the file")
+
 	cmd.AddCommand(listCmd)
 	cmd.AddCommand(logsCmd)
+	cmd.AddCommand(nextCmd)
+	cmd.AddCommand(retryCmd)
 	cmd.AddCommand(enableCmd)
 	cmd.AddCommand(disableCmd)
+	cmd.AddCommand(pauseCmd)
+	cmd.AddCommand(resumeCmd)
+	cmd.AddCommand(exportCmd)
+	cmd.AddCommand(importCmd)
 
 	return cmd
 }
@@ -149,7 +450,7 @@ func listJobs(cmd *cobra.Command, client *api.Client) error {
 		return fmt.Errorf("failed to list jobs: %w", err)
 	}
 
-	if tryJSON(cmd, jobs) {
+	if tryOutput(cmd, jobs) {
 		return nil
 	}
 
@@ -161,13 +462,19 @@ func listJobs(cmd *cobra.Command, client *api.Client) error {
 	fmt.Println()
 	fmt.Printf("  %s\n", titleStyle.Render("Scheduled Jobs"))
 
-	headers := []string{"Name", "Schedule", "Status", "Command"}
+	headers := []string{"Name", "Schedule", "Status", "Next Run", "Workdir", "Command"}
 	var rows [][]string
 
 	for _, j := range jobs {
 		status := "disabled"
 		if j.Enabled {
 			status = "enabled"
+			if j.Paused {
+				status = "paused"
+				if j.PausedUntil != nil {
+					status = fmt.Sprintf("paused (resumes in %s)", formatDuration(time.Until(*j.PausedUntil).Round(time.Minute)))
+				}
+			}
 		}
 
 		cmdStr := j.Command
@@ -175,10 +482,35 @@ func listJobs(cmd *cobra.Command, client *api.Client) error {
 			cmdStr = fmt.Sprintf("Agent: %s", *j.AgentName)
 		}
 
+		schedule := j.Schedule
+		if j.ScheduleText != "" {
+			schedule = fmt.Sprintf("%s (%s)", j.Schedule, j.ScheduleText)
+		}
+		if j.DependsOn != "" {
+			after := "after " + j.DependsOn
+			if schedule == "" {
+				schedule = after
+			} else {
+				schedule = fmt.Sprintf("%s, %s", schedule, after)
+			}
+		}
+
+		nextRun := "-"
+		if j.NextRun != nil {
+			nextRun = j.NextRun.Local().Format("Mon Jan 2 15:04")
+		}
+
+		workdir := "-"
+		if j.EffectiveWorkdir != "" {
+			workdir = j.EffectiveWorkdir
+		}
+
 		rows = append(rows, []string{
 			j.Name,
-			j.Schedule,
+			schedule,
 			status,
+			nextRun,
+			workdir,
 			cmdStr,
 		})
 	}
@@ -188,3 +520,110 @@ func listJobs(cmd *cobra.Command, client *api.Client) error {
 
 	return nil
 }
+
+// printJobImportResults renders one row per job followed by a count of each
+// outcome, e.g. "2 created, 1 updated"
+func printJobImportResults(results []api.JobImportResult) {
+	fmt.Println()
+	fmt.Printf("  %s\n", titleStyle.Render("Job Import"))
+
+	headers := []string{"Job", "Result", "Error"}
+	var rows [][]string
+	counts := make(map[string]int)
+	var order []string
+	for _, r := range results {
+		rows = append(rows, []string{r.Name, r.Action, r.Error})
+		if counts[r.Action] == 0 {
+			order = append(order, r.Action)
+		}
+		counts[r.Action]++
+	}
+	RenderTable(headers, rows)
+
+	var summary []string
+	for _, action := range order {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[action], action))
+	}
+	fmt.Printf("\n  %s\n\n", strings.Join(summary, ", "))
+}
+
+// executionFailed reports whether an execution finished without succeeding:
+// it exited non-zero, hit an error or was skipped
+func executionFailed(l api.JobExecution) bool {
+	return l.EndedAt != nil && (l.Error != nil || (l.ExitCode != nil && *l.ExitCode != 0))
+}
+
+// jobLogSummary is one job's row in jobs logs --summary
+type jobLogSummary struct {
+	Job         string  `json:"job"`
+	Runs        int     `json:"runs"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	Running     int     `json:"running,omitempty"`
+	SuccessRate float64 `json:"success_rate"` // percent of finished runs
+}
+
+// printJobLogSummary prints per-job outcome counts over logs. Runs still in
+// progress are counted separately and left out of the success rate.
+func printJobLogSummary(cmd *cobra.Command, logs []api.JobExecution) error {
+	byJob := make(map[string]*jobLogSummary)
+	for _, l := range logs {
+		name := l.JobName
+		if name == "" {
+			name = fmt.Sprintf("#%d", l.JobID)
+		}
+		s, ok := byJob[name]
+		if !ok {
+			s = &jobLogSummary{Job: name}
+			byJob[name] = s
+		}
+		switch {
+		case l.EndedAt == nil:
+			s.Running++
+		case executionFailed(l):
+			s.Runs++
+			s.Failed++
+		default:
+			s.Runs++
+			s.Succeeded++
+		}
+	}
+
+	summaries := make([]jobLogSummary, 0, len(byJob))
+	for _, s := range byJob {
+		if s.Runs > 0 {
+			s.SuccessRate = float64(s.Succeeded) * 100 / float64(s.Runs)
+		}
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Job < summaries[j].Job })
+
+	if tryOutput(cmd, summaries) {
+		return nil
+	}
+	if len(summaries) == 0 {
+		fmt.Println("No job logs found.")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("  %s\n", titleStyle.Render(fmt.Sprintf("Job Summary (last %d executions)", len(logs))))
+
+	headers := []string{"Job", "Runs", "Succeeded", "Failed", "Success Rate"}
+	var rows [][]string
+	for _, s := range summaries {
+		rate := "-"
+		if s.Runs > 0 {
+			rate = fmt.Sprintf("%.0f%%", s.SuccessRate)
+		}
+		runs := strconv.Itoa(s.Runs)
+		if s.Running > 0 {
+			runs = fmt.Sprintf("%d (+%d running)", s.Runs, s.Running)
+		}
+		rows = append(rows, []string{s.Job, runs, strconv.Itoa(s.Succeeded), strconv.Itoa(s.Failed), rate})
+	}
+	RenderTable(headers, rows)
+	fmt.Println()
+
+	return nil
+}
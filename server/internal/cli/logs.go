@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 func newLogsCmd() *cobra.Command {
@@ -22,7 +24,7 @@ func newLogsCmd() *cobra.Command {
 				os.Exit(1)
 			}
 
-			logPath := filepath.Join(home, ".diane", "server.log")
+			logPath := filepath.Join(home, config.DirName(), "server.log")
 
 			lines, _ := cmd.Flags().GetInt("lines")
 			follow, _ := cmd.Flags().GetBool("follow")
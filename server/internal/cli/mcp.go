@@ -1,11 +1,17 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"net/http"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/diane-assistant/diane/internal/api"
+	"github.com/diane-assistant/diane/internal/db"
 	"github.com/spf13/cobra"
 )
 
@@ -20,7 +26,7 @@ func newMCPServersCmd(client *api.Client) *cobra.Command {
 				return nil
 			}
 
-			if tryJSON(cmd, servers) {
+			if tryOutput(cmd, servers) {
 				return nil
 			}
 
@@ -49,6 +55,9 @@ func newMCPServersCmd(client *api.Client) *cobra.Command {
 
 			for _, srv := range servers {
 				dot := GetStatusDot(srv.Connected, srv.Error != "")
+				if srv.Degraded {
+					dot = warnDot.String()
+				}
 
 				// Determine server type from config, fall back to inference
 				serverType := "stdio"
@@ -63,6 +72,8 @@ func newMCPServersCmd(client *api.Client) *cobra.Command {
 				status := "disconnected"
 				if !srv.Enabled {
 					status = "disabled"
+				} else if srv.Degraded {
+					status = "degraded"
 				} else if srv.Connected {
 					status = "connected"
 				}
@@ -97,13 +108,77 @@ func newMCPCmd(client *api.Client) *cobra.Command {
 
 	mcpCmd.AddCommand(newMCPAddCmd(client))
 	mcpCmd.AddCommand(newMCPAddStdioCmd(client))
+	mcpCmd.AddCommand(newMCPImportCmd(client))
 	mcpCmd.AddCommand(newMCPEditCmd(client))
+	mcpCmd.AddCommand(newMCPReorderCmd(client))
 	mcpCmd.AddCommand(newMCPDeleteCmd(client))
+	mcpCmd.AddCommand(newMCPEnableCmd(client))
+	mcpCmd.AddCommand(newMCPDisableCmd(client))
 	mcpCmd.AddCommand(newMCPInstallCmd(client))
+	mcpCmd.AddCommand(newMCPClientsCmd(client))
 
 	return mcpCmd
 }
 
+func newMCPClientsCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clients",
+		Short: "List clients connected over SSE and what they've been sent",
+		Long: `List the clients connected to the MCP HTTP server's SSE transport
+(/mcp/sse), with when each connected, when a frame was last written to it
+and how many frames and messages it has been sent. Heartbeats count as
+frames; responses and notifications such as tools/list_changed count as
+messages. Dropped messages were lost because the client wasn't reading.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clients, err := client.GetSSEClients()
+			if err != nil {
+				return fmt.Errorf("failed to list SSE clients: %w", err)
+			}
+
+			if tryOutput(cmd, clients) {
+				return nil
+			}
+			if len(clients) == 0 {
+				fmt.Println("No SSE clients connected.")
+				return nil
+			}
+
+			fmt.Println()
+			fmt.Printf("  %s\n", titleStyle.Render("SSE Clients"))
+
+			headers := []string{"ID", "Context", "Connected", "Last Send", "Frames", "Messages", "Dropped"}
+			var rows [][]string
+			for _, c := range clients {
+				contextName := c.Context
+				if contextName == "" {
+					contextName = "-"
+				}
+				if !c.Initialized {
+					contextName += " (not initialized)"
+				}
+				lastSend := "-"
+				if c.LastSendAt != nil {
+					lastSend = formatDuration(time.Since(*c.LastSendAt)) + " ago"
+				}
+				rows = append(rows, []string{
+					c.ID,
+					contextName,
+					formatDuration(time.Since(c.ConnectedAt)) + " ago",
+					lastSend,
+					strconv.FormatInt(c.FramesSent, 10),
+					strconv.FormatInt(c.MessagesSent, 10),
+					strconv.FormatInt(c.MessagesDropped, 10),
+				})
+			}
+
+			RenderTable(headers, rows)
+			fmt.Println()
+			return nil
+		},
+	}
+}
+
 func newMCPAddCmd(client *api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add <name> <url>",
@@ -127,16 +202,34 @@ func newMCPAddCmd(client *api.Client) *cobra.Command {
 				}
 			}
 
+			toolTimeout, _ := cmd.Flags().GetInt("tool-timeout")
+
 			req := api.CreateMCPServerRequest{
-				Name:    name,
-				Type:    serverType,
-				URL:     url,
-				Enabled: &enabled,
+				Name:        name,
+				Type:        serverType,
+				URL:         url,
+				Enabled:     &enabled,
+				ToolTimeout: toolTimeout,
 			}
 			if len(headerMap) > 0 {
 				req.Headers = headerMap
 			}
 
+			// OAuth-authenticated servers get a fresh Authorization header
+			// from the stored token on every connect and request
+			oauthProvider, _ := cmd.Flags().GetString("oauth-provider")
+			oauthClientID, _ := cmd.Flags().GetString("oauth-client-id")
+			if oauthProvider != "" || oauthClientID != "" {
+				req.OAuth = &db.OAuthConfig{Provider: oauthProvider, ClientID: oauthClientID}
+				req.OAuth.DeviceAuthURL, _ = cmd.Flags().GetString("oauth-device-url")
+				req.OAuth.TokenURL, _ = cmd.Flags().GetString("oauth-token-url")
+				req.OAuth.Scopes, _ = cmd.Flags().GetStringSlice("oauth-scope")
+				if oauthProvider == "" && req.OAuth.TokenURL == "" {
+					PrintError("--oauth-token-url is required with --oauth-client-id")
+					return nil
+				}
+			}
+
 			server, err := client.CreateMCPServer(req)
 			if err != nil {
 				PrintError(fmt.Sprintf("Failed to add server: %v", err))
@@ -144,6 +237,9 @@ func newMCPAddCmd(client *api.Client) *cobra.Command {
 			}
 
 			PrintSuccess(fmt.Sprintf("Added %s server '%s' (id: %d)", server.Type, server.Name, server.ID))
+			if req.OAuth != nil {
+				fmt.Printf("  Run 'diane auth login %s' to authenticate\n", server.Name)
+			}
 			return nil
 		},
 	}
@@ -151,6 +247,12 @@ func newMCPAddCmd(client *api.Client) *cobra.Command {
 	cmd.Flags().String("type", "http", "Server type (http or sse)")
 	cmd.Flags().StringSlice("header", nil, "HTTP header as key=value (repeatable)")
 	cmd.Flags().Bool("enabled", true, "Enable the server immediately")
+	cmd.Flags().Int("tool-timeout", 0, "Per-call tool timeout in seconds (0 = daemon default)")
+	cmd.Flags().String("oauth-provider", "", "Well-known OAuth provider supplying the Authorization header")
+	cmd.Flags().String("oauth-client-id", "", "OAuth client ID for the device flow")
+	cmd.Flags().String("oauth-device-url", "", "OAuth device authorization URL")
+	cmd.Flags().String("oauth-token-url", "", "OAuth token URL")
+	cmd.Flags().StringSlice("oauth-scope", nil, "OAuth scope (repeatable)")
 
 	return cmd
 }
@@ -166,9 +268,21 @@ func newMCPAddStdioCmd(client *api.Client) *cobra.Command {
 
 			cmdArgs, _ := cmd.Flags().GetStringSlice("arg")
 			envSlice, _ := cmd.Flags().GetStringSlice("env")
+			envFile, _ := cmd.Flags().GetString("env-file")
 			enabled, _ := cmd.Flags().GetBool("enabled")
 
 			envMap := make(map[string]string)
+			if envFile != "" {
+				fileEnv, err := parseEnvFile(envFile)
+				if err != nil {
+					PrintError(fmt.Sprintf("Failed to read env file: %v", err))
+					return nil
+				}
+				for k, v := range fileEnv {
+					envMap[k] = v
+				}
+			}
+			// Explicit --env flags override the env file
 			for _, e := range envSlice {
 				parts := strings.SplitN(e, "=", 2)
 				if len(parts) == 2 {
@@ -178,11 +292,16 @@ func newMCPAddStdioCmd(client *api.Client) *cobra.Command {
 				}
 			}
 
+			toolTimeout, _ := cmd.Flags().GetInt("tool-timeout")
+			startupTimeout, _ := cmd.Flags().GetInt("startup-timeout")
+
 			req := api.CreateMCPServerRequest{
-				Name:    name,
-				Type:    "stdio",
-				Command: command,
-				Enabled: &enabled,
+				Name:           name,
+				Type:           "stdio",
+				Command:        command,
+				Enabled:        &enabled,
+				ToolTimeout:    toolTimeout,
+				StartupTimeout: startupTimeout,
 			}
 			if len(cmdArgs) > 0 {
 				req.Args = cmdArgs
@@ -198,13 +317,27 @@ func newMCPAddStdioCmd(client *api.Client) *cobra.Command {
 			}
 
 			PrintSuccess(fmt.Sprintf("Added stdio server '%s' (id: %d)", server.Name, server.ID))
+			if len(server.Env) > 0 {
+				keys := make([]string, 0, len(server.Env))
+				for k := range server.Env {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				fmt.Println("  Environment:")
+				for _, k := range keys {
+					fmt.Printf("    %s=%s\n", k, maskEnvValue(server.Env[k]))
+				}
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringSlice("arg", nil, "Command argument (repeatable)")
 	cmd.Flags().StringSlice("env", nil, "Environment variable as KEY=VALUE (repeatable)")
+	cmd.Flags().String("env-file", "", "Load environment variables from a dotenv file (--env takes precedence)")
 	cmd.Flags().Bool("enabled", true, "Enable the server immediately")
+	cmd.Flags().Int("tool-timeout", 0, "Per-call tool timeout in seconds (0 = daemon default)")
+	cmd.Flags().Int("startup-timeout", 0, "Seconds to wait for the server to initialize after starting (0 = daemon default, 30s)")
 
 	return cmd
 }
@@ -248,6 +381,30 @@ func newMCPEditCmd(client *api.Client) *cobra.Command {
 				hasChanges = true
 			}
 
+			if cmd.Flags().Changed("tool-timeout") {
+				toolTimeout, _ := cmd.Flags().GetInt("tool-timeout")
+				req.ToolTimeout = &toolTimeout
+				hasChanges = true
+			}
+
+			if cmd.Flags().Changed("max-concurrency") {
+				maxConcurrency, _ := cmd.Flags().GetInt("max-concurrency")
+				req.MaxConcurrency = &maxConcurrency
+				hasChanges = true
+			}
+
+			if cmd.Flags().Changed("startup-timeout") {
+				startupTimeout, _ := cmd.Flags().GetInt("startup-timeout")
+				req.StartupTimeout = &startupTimeout
+				hasChanges = true
+			}
+
+			if cmd.Flags().Changed("priority") {
+				priority, _ := cmd.Flags().GetInt("priority")
+				req.Priority = &priority
+				hasChanges = true
+			}
+
 			if !hasChanges {
 				PrintWarning("No changes specified")
 				return nil
@@ -266,10 +423,59 @@ func newMCPEditCmd(client *api.Client) *cobra.Command {
 	cmd.Flags().String("enabled", "", "Enable or disable (true/false)")
 	cmd.Flags().String("url", "", "Update server URL")
 	cmd.Flags().String("command", "", "Update command")
+	cmd.Flags().Int("tool-timeout", 0, "Per-call tool timeout in seconds (0 = daemon default)")
+	cmd.Flags().Int("max-concurrency", 0, "Max concurrent tool calls; extra calls queue (0 = unlimited)")
+	cmd.Flags().Int("startup-timeout", 0, "Seconds a stdio server may take to initialize (0 = daemon default, 30s)")
+	cmd.Flags().Int("priority", 0, "Tool listing order: higher lists the server's tools earlier (0 = with builtin tools, negative = after them)")
 
 	return cmd
 }
 
+func newMCPReorderCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reorder <name>...",
+		Short: "Set which servers' tools are listed first",
+		Long: `Give the named servers descending priorities so MCP clients see their
+tools first, in the order given and ahead of builtin tools. Servers not
+named keep their priority; set one directly with 'mcp edit <id> --priority N'.
+
+Tools are listed by server priority (highest first), then builtin tools
+before proxied ones, then by name.`,
+		Example: `  diane-ctl mcp reorder github context7`,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			servers, err := client.GetMCPServerConfigs()
+			if err != nil {
+				return fmt.Errorf("failed to list servers: %w", err)
+			}
+			ids := make(map[string]int64)
+			for _, s := range servers {
+				if s.ID > 0 && s.Type != "builtin" {
+					ids[s.Name] = s.ID
+				}
+			}
+
+			for _, name := range args {
+				if _, ok := ids[name]; !ok {
+					return &api.StatusError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("no proxied MCP server named %q (builtin servers can't be reordered)", name)}
+				}
+			}
+			for i, name := range args {
+				priority := len(args) - i
+				if _, err := client.UpdateMCPServerConfig(ids[name], api.UpdateMCPServerRequest{Priority: &priority}); err != nil {
+					return fmt.Errorf("failed to update %s: %w", name, err)
+				}
+			}
+
+			if _, err := client.ReloadConfig(); err != nil {
+				return fmt.Errorf("priorities saved, but reload failed (run 'diane-ctl reload'): %w", err)
+			}
+			PrintSuccess(fmt.Sprintf("Tools of %s are now listed first", strings.Join(args, ", ")))
+			return nil
+		},
+	}
+}
+
 func newMCPDeleteCmd(client *api.Client) *cobra.Command {
 	return &cobra.Command{
 		Use:   "delete <id>",
@@ -291,6 +497,41 @@ func newMCPDeleteCmd(client *api.Client) *cobra.Command {
 	}
 }
 
+func newMCPEnableCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable <name>",
+		Short: "Bring a runtime-disabled MCP server back into rotation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := client.EnableMCPServer(name); err != nil {
+				return fmt.Errorf("failed to enable server: %w", err)
+			}
+			PrintSuccess(fmt.Sprintf("Server '%s' enabled", name))
+			return nil
+		},
+	}
+}
+
+func newMCPDisableCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable <name>",
+		Short: "Take an MCP server out of rotation without changing its config",
+		Long: "Stops the server's connection and hides its tools until it is re-enabled\n" +
+			"with 'mcp enable'. The stored configuration is left untouched, and the\n" +
+			"server stays disabled across 'reload'.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := client.DisableMCPServer(name); err != nil {
+				return fmt.Errorf("failed to disable server: %w", err)
+			}
+			PrintSuccess(fmt.Sprintf("Server '%s' disabled", name))
+			return nil
+		},
+	}
+}
+
 func newMCPInstallCmd(client *api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "install <target>",
@@ -312,3 +553,92 @@ func newMCPInstallCmd(client *api.Client) *cobra.Command {
 
 	return cmd
 }
+
+// parseEnvFile reads a dotenv-style file: KEY=VALUE lines, optionally
+// prefixed with "export", with blank lines and # comments ignored. Values
+// may be single-quoted (taken literally) or double-quoted (with \n, \" and
+// \\ escapes); unquoted values end at an inline " #" comment.
+func parseEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNo)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		env[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// parseEnvValue unquotes a dotenv value
+func parseEnvValue(v string) (string, error) {
+	if v == "" {
+		return "", nil
+	}
+	switch v[0] {
+	case '\'':
+		end := strings.IndexByte(v[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quote")
+		}
+		return v[1 : end+1], nil
+	case '"':
+		var sb strings.Builder
+		for i := 1; i < len(v); i++ {
+			switch c := v[i]; {
+			case c == '"':
+				return sb.String(), nil
+			case c == '\\' && i+1 < len(v):
+				i++
+				switch v[i] {
+				case 'n':
+					sb.WriteByte('\n')
+				case 't':
+					sb.WriteByte('\t')
+				default:
+					sb.WriteByte(v[i])
+				}
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated double quote")
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v, nil
+}
+
+// maskEnvValue hides an environment value for display, keeping only enough
+// of long values to tell them apart
+func maskEnvValue(v string) string {
+	if len(v) > 8 {
+		return v[:4] + "****" + v[len(v)-4:]
+	}
+	return "****"
+}
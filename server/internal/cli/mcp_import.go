@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/diane-assistant/diane/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// mcpImportEntry is one server read from another MCP host's config. Req is
+// nil when the entry can't be imported, with Skip saying why.
+type mcpImportEntry struct {
+	Name string
+	Req  *api.CreateMCPServerRequest
+	Skip string
+}
+
+// mcpImportResult reports what importing one server did
+type mcpImportResult struct {
+	Name    string `json:"name"`
+	Type    string `json:"type,omitempty"`
+	Status  string `json:"status"` // created, would create, skipped, conflict or failed
+	ID      int64  `json:"id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// hostServerConfig is the union of the server entry shapes MCP hosts use:
+// Claude Desktop, Claude Code, Cursor and Windsurf (command/args/env or
+// url/headers), VS Code (the same with a type), and opencode (type
+// local/remote, the command as an argv array, environment, enabled).
+type hostServerConfig struct {
+	Type        string            `json:"type"`
+	Command     json.RawMessage   `json:"command"`
+	Args        []string          `json:"args"`
+	Env         map[string]string `json:"env"`
+	Environment map[string]string `json:"environment"`
+	URL         string            `json:"url"`
+	ServerURL   string            `json:"serverUrl"`
+	Headers     map[string]string `json:"headers"`
+	Enabled     *bool             `json:"enabled"`
+	Disabled    bool              `json:"disabled"`
+}
+
+func newMCPImportCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Add the MCP servers from another host's config file",
+		Long: `Read MCP server definitions from another MCP host's JSON config and add
+each one to Diane. Recognized layouts:
+
+  {"mcpServers": {...}}         Claude Desktop, Claude Code, Cursor, Windsurf
+  {"servers": {...}}            VS Code (.vscode/mcp.json)
+  {"mcp": {"servers": {...}}}   VS Code settings.json
+  {"mcp": {...}}                opencode
+
+Servers with a command become stdio servers; servers with a URL become
+http servers, or sse ones when the host says so. Servers whose name is
+already configured are reported as conflicts and left untouched. Servers
+disabled in the source file are imported disabled.`,
+		Example: `  diane-ctl mcp import ~/Library/Application\ Support/Claude/claude_desktop_config.json
+  diane-ctl mcp import opencode.json --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+			entries, err := parseMCPImport(data)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", args[0], err)
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			enabled, _ := cmd.Flags().GetBool("enabled")
+
+			results := make([]mcpImportResult, 0, len(entries))
+			failures := 0
+			for _, e := range entries {
+				result := mcpImportResult{Name: e.Name, Status: "skipped", Message: e.Skip}
+				if e.Req != nil {
+					result.Type = e.Req.Type
+					if !enabled {
+						e.Req.Enabled = &enabled
+					}
+					switch server, err := createImportedServer(client, e.Req, dryRun); {
+					case dryRun:
+						result.Status = "would create"
+					case errors.Is(err, api.ErrConflict):
+						result.Status = "conflict"
+						result.Message = "a server with this name already exists"
+					case err != nil:
+						result.Status = "failed"
+						result.Message = err.Error()
+						failures++
+					default:
+						result.Status = "created"
+						result.ID = server.ID
+					}
+				}
+				results = append(results, result)
+			}
+			summaryErr := func() error {
+				if failures > 0 {
+					return fmt.Errorf("%d of %d servers failed to import", failures, len(entries))
+				}
+				return nil
+			}
+
+			if tryOutput(cmd, results) {
+				return summaryErr()
+			}
+
+			if len(results) == 0 {
+				PrintWarning("No MCP servers found in " + args[0])
+				return nil
+			}
+
+			fmt.Println()
+			fmt.Printf("  %s\n", titleStyle.Render("MCP Import"))
+
+			headers := []string{"Name", "Type", "Result", "Message"}
+			var rows [][]string
+			counts := make(map[string]int)
+			for _, r := range results {
+				message := r.Message
+				if r.ID > 0 {
+					message = fmt.Sprintf("id %d", r.ID)
+				}
+				rows = append(rows, []string{r.Name, r.Type, r.Status, message})
+				counts[r.Status]++
+			}
+			RenderTable(headers, rows)
+			fmt.Println()
+
+			if dryRun {
+				fmt.Printf("  %d would be created, %d skipped (dry run, nothing changed)\n\n", counts["would create"], counts["skipped"])
+			} else {
+				fmt.Printf("  %d created, %d skipped, %d conflicts, %d failed\n\n", counts["created"], counts["skipped"], counts["conflict"], counts["failed"])
+			}
+			return summaryErr()
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would be imported without adding anything")
+	cmd.Flags().Bool("enabled", true, "Enable imported servers (servers disabled in the file stay disabled)")
+
+	return cmd
+}
+
+// createImportedServer adds one imported server, or does nothing on a dry run
+func createImportedServer(client *api.Client, req *api.CreateMCPServerRequest, dryRun bool) (*api.MCPServerResponse, error) {
+	if dryRun {
+		return nil, nil
+	}
+	return client.CreateMCPServer(*req)
+}
+
+// parseMCPImport finds the servers section of an MCP host config and maps
+// each entry to a create request, in name order
+func parseMCPImport(data []byte) ([]mcpImportEntry, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	section, ok := doc["mcpServers"]
+	if !ok {
+		section, ok = doc["servers"]
+	}
+	if !ok {
+		if section, ok = doc["mcp"]; ok {
+			// VS Code nests its servers under "servers" in settings.json;
+			// opencode keeps them directly under "mcp"
+			var nested map[string]json.RawMessage
+			if json.Unmarshal(section, &nested) == nil {
+				if servers, found := nested["servers"]; found && !looksLikeServer(servers) {
+					section = servers
+				}
+			}
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("no mcpServers, servers or mcp section found")
+	}
+
+	var servers map[string]json.RawMessage
+	if err := json.Unmarshal(section, &servers); err != nil {
+		return nil, fmt.Errorf("servers section is not an object: %w", err)
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]mcpImportEntry, 0, len(names))
+	for _, name := range names {
+		entry := mcpImportEntry{Name: name}
+		var cfg hostServerConfig
+		if err := json.Unmarshal(servers[name], &cfg); err != nil {
+			entry.Skip = fmt.Sprintf("unreadable entry: %v", err)
+		} else {
+			entry.Req, err = importRequest(name, cfg)
+			if err != nil {
+				entry.Skip = err.Error()
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// looksLikeServer reports whether a JSON value is a single server entry
+// rather than a map of them, to tell opencode's server named "servers" from
+// VS Code's servers section
+func looksLikeServer(raw json.RawMessage) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(raw, &fields) != nil {
+		return false
+	}
+	for _, key := range []string{"command", "url", "type"} {
+		if _, ok := fields[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// importRequest maps a host's server entry to a create request
+func importRequest(name string, cfg hostServerConfig) (*api.CreateMCPServerRequest, error) {
+	enabled := !cfg.Disabled && (cfg.Enabled == nil || *cfg.Enabled)
+	req := &api.CreateMCPServerRequest{Name: name, Enabled: &enabled}
+
+	command, args, err := importCommand(cfg.Command)
+	if err != nil {
+		return nil, err
+	}
+	url := cfg.URL
+	if url == "" {
+		url = cfg.ServerURL
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case "", "stdio", "local", "http", "streamable-http", "streamablehttp", "remote", "sse":
+	default:
+		return nil, fmt.Errorf("unsupported server type %q", cfg.Type)
+	}
+
+	switch {
+	case command != "":
+		req.Type = "stdio"
+		req.Command = command
+		req.Args = append(args, cfg.Args...)
+		req.Env = cfg.Env
+		if len(req.Env) == 0 {
+			req.Env = cfg.Environment
+		}
+	case url != "":
+		req.Type = "http"
+		if strings.EqualFold(cfg.Type, "sse") || (cfg.Type == "" && strings.HasSuffix(strings.TrimRight(url, "/"), "/sse")) {
+			req.Type = "sse"
+		}
+		req.URL = url
+		req.Headers = cfg.Headers
+	default:
+		return nil, fmt.Errorf("entry has neither a command nor a url")
+	}
+	return req, nil
+}
+
+// importCommand reads a command given as a string, or as opencode's argv
+// array whose first element is the command
+func importCommand(raw json.RawMessage) (string, []string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil, nil
+	}
+	var command string
+	if json.Unmarshal(raw, &command) == nil {
+		return command, nil, nil
+	}
+	var argv []string
+	if err := json.Unmarshal(raw, &argv); err != nil {
+		return "", nil, fmt.Errorf("command must be a string or an array of strings")
+	}
+	if len(argv) == 0 {
+		return "", nil, nil
+	}
+	return argv[0], argv[1:], nil
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func newNotifyCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Manage notification settings",
+	}
+	cmd.AddCommand(newNotifyTemplateCmd(client))
+	return cmd
+}
+
+func newNotifyTemplateCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage notify templates",
+		Long: `Notify templates are named Go text/template messages stored by the
+daemon. The notification tools render one when given its name as the
+template argument, and a job with a notify_template sends its template
+whenever a run fails, so alerts share one format.
+
+A job's template renders against:
+  .job_name   the job's name
+  .exit_code  the run's exit code (-1 if it didn't exit)
+  .error      why the run failed, if not just a non-zero exit
+  .output     the last 500 bytes of the run's stderr, or stdout
+  .timestamp  when the run ended (RFC3339)`,
+	}
+	cmd.AddCommand(newNotifyTemplateAddCmd(client))
+	cmd.AddCommand(newNotifyTemplateListCmd(client))
+	cmd.AddCommand(newNotifyTemplateTestCmd(client))
+	return cmd
+}
+
+func newNotifyTemplateAddCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name> [template]",
+		Short: "Add or replace a notify template",
+		Example: `  diane notify template add job-failed '❌ {{.job_name}} failed (exit {{.exit_code}}) at {{.timestamp}}{{with .output}}
+{{.}}{{end}}'
+  diane notify template add daily-report --file report.tmpl`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, _ := cmd.Flags().GetString("file")
+			var body string
+			switch {
+			case len(args) == 2 && file != "":
+				return fmt.Errorf("give the template or --file, not both")
+			case len(args) == 2:
+				body = args[1]
+			case file != "":
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				body = string(data)
+			default:
+				return fmt.Errorf("give the template or --file")
+			}
+			if strings.TrimSpace(body) == "" {
+				return fmt.Errorf("template is empty")
+			}
+
+			if err := client.SaveNotifyTemplate(api.NotifyTemplate{Name: args[0], Body: body}); err != nil {
+				return err
+			}
+			PrintSuccess(fmt.Sprintf("Saved notify template '%s'", args[0]))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("file", "", "Read the template from a file")
+
+	return cmd
+}
+
+func newNotifyTemplateListCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List notify templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			templates, err := client.ListNotifyTemplates()
+			if err != nil {
+				return fmt.Errorf("failed to list notify templates: %w", err)
+			}
+
+			if tryOutput(cmd, templates) {
+				return nil
+			}
+			if len(templates) == 0 {
+				fmt.Println("No notify templates. Add one with: diane notify template add <name> <template>")
+				return nil
+			}
+
+			fmt.Println()
+			fmt.Printf("  %s\n", titleStyle.Render("Notify Templates"))
+
+			headers := []string{"Name", "Template", "Updated"}
+			var rows [][]string
+			for _, t := range templates {
+				body := strings.Join(strings.Fields(t.Body), " ")
+				if len(body) > 50 {
+					body = body[:47] + "..."
+				}
+				updated := "-"
+				if !t.UpdatedAt.IsZero() {
+					updated = formatDuration(time.Since(t.UpdatedAt)) + " ago"
+				}
+				rows = append(rows, []string{t.Name, body, updated})
+			}
+
+			RenderTable(headers, rows)
+			fmt.Println()
+			return nil
+		},
+	}
+}
+
+func newNotifyTemplateTestCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test <name>",
+		Short: "Render a notify template without sending it",
+		Long: `Render a notify template as a failed run of an example job would,
+and print the message. --data overrides or adds values.`,
+		Example: `  diane notify template test job-failed
+  diane notify template test job-failed --data '{"job_name": "backup", "exit_code": 2}'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data map[string]any
+			if raw, _ := cmd.Flags().GetString("data"); raw != "" {
+				if err := json.Unmarshal([]byte(raw), &data); err != nil {
+					return fmt.Errorf("invalid --data JSON: %w", err)
+				}
+			}
+
+			message, err := client.TestNotifyTemplate(args[0], data)
+			if err != nil {
+				return err
+			}
+			fmt.Println(message)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("data", "", "Template values as a JSON object")
+
+	return cmd
+}
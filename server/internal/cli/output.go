@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats accepted by the global --output flag
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// validateOutputFormat rejects unknown --output values before a command runs
+func validateOutputFormat(cmd *cobra.Command) error {
+	switch outputFormat(cmd) {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	}
+	output, _ := cmd.Flags().GetString("output")
+	return fmt.Errorf("invalid --output %q (expected table, json or yaml)", output)
+}
+
+// outputFormat returns the requested output format. --json is kept as an
+// alias for --output json.
+func outputFormat(cmd *cobra.Command) string {
+	if jsonFlag, _ := cmd.Flags().GetBool("json"); jsonFlag {
+		return formatJSON
+	}
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return formatTable
+	}
+	return strings.ToLower(output)
+}
+
+// tryOutput prints v in the requested structured format and returns true, or
+// returns false when the command should render its usual table view.
+func tryOutput(cmd *cobra.Command, v interface{}) bool {
+	var out []byte
+	var err error
+	switch outputFormat(cmd) {
+	case formatJSON:
+		out, err = json.MarshalIndent(v, "", "  ")
+	case formatYAML:
+		out, err = marshalYAML(v)
+	default:
+		return false
+	}
+	if err != nil {
+		return false
+	}
+	fmt.Println(strings.TrimRight(string(out), "\n"))
+	return true
+}
+
+// marshalYAML renders v as YAML using the same field names and ordering as
+// its JSON form. Going through JSON means the api types' json tags apply,
+// rather than yaml.v3's lowercased Go field names.
+func marshalYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalYAML decodes YAML (or JSON, which is valid YAML) into v using
+// v's json tags, mirroring marshalYAML
+func unmarshalYAML(data []byte, v interface{}) error {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	converted, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(converted, v)
+}
+
+// blockStyle clears the flow/quoting styles that decoding JSON leaves on
+// every node, so the encoder picks plain block-style YAML.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/diane-assistant/diane/internal/config"
+)
+
+// ApplyProfileFlag handles the global --profile flag. Paths, the socket and
+// ports are chosen when the API client and daemon are set up, before Cobra
+// parses flags, so the flag is removed from args and exported as
+// DIANE_PROFILE for everything that follows (including a re-executed
+// daemon). Arguments after "--" are left alone.
+func ApplyProfileFlag(args []string) ([]string, error) {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			out = append(out, args[i:]...)
+			i = len(args)
+		case arg == "--profile" && i+1 < len(args):
+			os.Setenv(config.ProfileEnv, args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--profile="):
+			os.Setenv(config.ProfileEnv, strings.TrimPrefix(arg, "--profile="))
+		default:
+			out = append(out, arg)
+		}
+	}
+	if err := config.ValidateProfile(config.Profile()); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -2,8 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/diane-assistant/diane/internal/api"
 	"github.com/spf13/cobra"
@@ -128,6 +131,22 @@ func newProviderCmd(client *api.Client) *cobra.Command {
 				hasChanges = true
 			}
 
+			if cmd.Flags().Changed("model") {
+				model, _ := cmd.Flags().GetString("model")
+				req.DefaultModel = &model
+				hasChanges = true
+			}
+
+			paramSlice, _ := cmd.Flags().GetStringSlice("param")
+			if len(paramSlice) > 0 {
+				params, err := parseProviderParams(paramSlice)
+				if err != nil {
+					return err
+				}
+				req.Params = params
+				hasChanges = true
+			}
+
 			if !hasChanges {
 				PrintWarning("No changes specified")
 				return nil
@@ -144,6 +163,8 @@ func newProviderCmd(client *api.Client) *cobra.Command {
 	editCmd.Flags().String("name", "", "New name")
 	editCmd.Flags().StringSlice("config", nil, "Update config (key=value)")
 	editCmd.Flags().StringSlice("auth", nil, "Update auth (key=value)")
+	editCmd.Flags().String("model", "", "Default model for calls through the provider (\"\" to clear)")
+	editCmd.Flags().StringSlice("param", nil, "Set a default call parameter, e.g. temperature=0.2 or max_tokens=1024 (key= removes it)")
 
 	// delete subcommand
 	deleteCmd := &cobra.Command{
@@ -167,10 +188,23 @@ func newProviderCmd(client *api.Client) *cobra.Command {
 
 	// test subcommand
 	testCmd := &cobra.Command{
-		Use:   "test <id>",
-		Short: "Test a provider connection",
-		Args:  cobra.ExactArgs(1),
+		Use:   "test [id]",
+		Short: "Test a provider connection, or every enabled provider with --all",
+		Long: titleStyle.Render("Test Providers") + `
+  Test one provider's connection, or with --all every enabled provider at
+  once. --all prints a pass/fail table and exits non-zero if any provider
+  fails, so it can gate a script.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				return testAllProviders(cmd, client)
+			}
+
 			id, err := strconv.ParseInt(args[0], 10, 64)
 			if err != nil {
 				return fmt.Errorf("invalid provider ID: %w", err)
@@ -181,7 +215,7 @@ func newProviderCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("test failed: %w", err)
 			}
 
-			if tryJSON(cmd, result) {
+			if tryOutput(cmd, result) {
 				return nil
 			}
 
@@ -194,6 +228,8 @@ func newProviderCmd(client *api.Client) *cobra.Command {
 		},
 	}
 
+	testCmd.Flags().Bool("all", false, "Test every enabled provider concurrently")
+
 	// enable subcommand
 	enableCmd := &cobra.Command{
 		Use:   "enable <id>",
@@ -273,7 +309,7 @@ func newProviderCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to list models: %w", err)
 			}
 
-			if tryJSON(cmd, models) {
+			if tryOutput(cmd, models) {
 				return nil
 			}
 
@@ -330,7 +366,7 @@ func listProviders(cmd *cobra.Command, client *api.Client) error {
 		return fmt.Errorf("failed to list providers: %w", err)
 	}
 
-	if tryJSON(cmd, providers) {
+	if tryOutput(cmd, providers) {
 		return nil
 	}
 
@@ -342,7 +378,7 @@ func listProviders(cmd *cobra.Command, client *api.Client) error {
 	fmt.Println()
 	fmt.Printf("  %s\n", titleStyle.Render("Providers"))
 
-	headers := []string{"ID", "Name", "Service", "Type", "Status", "Default"}
+	headers := []string{"ID", "Name", "Service", "Type", "Status", "Default", "Model", "Params"}
 	var rows [][]string
 
 	for _, p := range providers {
@@ -356,6 +392,14 @@ func listProviders(cmd *cobra.Command, client *api.Client) error {
 			isDefault = "*"
 		}
 
+		model, params := "-", "-"
+		if p.DefaultModel != "" {
+			model = p.DefaultModel
+		}
+		if len(p.Params) > 0 {
+			params = formatProviderParams(p.Params)
+		}
+
 		rows = append(rows, []string{
 			fmt.Sprintf("%d", p.ID),
 			p.Name,
@@ -363,6 +407,8 @@ func listProviders(cmd *cobra.Command, client *api.Client) error {
 			p.Type,
 			status,
 			isDefault,
+			model,
+			params,
 		})
 	}
 
@@ -371,3 +417,144 @@ func listProviders(cmd *cobra.Command, client *api.Client) error {
 
 	return nil
 }
+
+// providerTestTimeout bounds a provider test; the daemon gives each test 30s
+const providerTestTimeout = 35 * time.Second
+
+// providerTestEntry is one row of provider test --all
+type providerTestEntry struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Service string `json:"service"`
+	api.ProviderTestResult
+}
+
+// testAllProviders tests every enabled provider concurrently and fails if
+// any of them does
+func testAllProviders(cmd *cobra.Command, client *api.Client) error {
+	providers, err := client.ListProviders("")
+	if err != nil {
+		return fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	var enabled []api.ProviderResponse
+	for _, p := range providers {
+		if p.Enabled {
+			enabled = append(enabled, p)
+		}
+	}
+
+	testClient := client.WithTimeout(providerTestTimeout)
+	entries := make([]providerTestEntry, len(enabled))
+	var wg sync.WaitGroup
+	for i, p := range enabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry := providerTestEntry{ID: p.ID, Name: p.Name, Type: p.Type, Service: p.Service}
+			start := time.Now()
+			result, err := testClient.TestProvider(p.ID)
+			switch {
+			case result != nil:
+				entry.ProviderTestResult = *result
+			case err != nil:
+				// The test never got an answer, so time it here
+				entry.Message = err.Error()
+				entry.ResponseTime = float64(time.Since(start).Milliseconds())
+			}
+			entries[i] = entry
+		}()
+	}
+	wg.Wait()
+
+	failures := 0
+	for _, e := range entries {
+		if !e.Success {
+			failures++
+		}
+	}
+	summaryErr := func() error {
+		if failures > 0 {
+			return fmt.Errorf("%d of %d providers failed", failures, len(entries))
+		}
+		return nil
+	}
+
+	if tryOutput(cmd, entries) {
+		return summaryErr()
+	}
+
+	if len(entries) == 0 {
+		PrintWarning("No enabled providers to test")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("  %s\n", titleStyle.Render("Provider Tests"))
+
+	headers := []string{"ID", "Name", "Type", "Result", "Time", "Message"}
+	var rows [][]string
+	for _, e := range entries {
+		result := "pass"
+		if !e.Success {
+			result = "FAIL"
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", e.ID),
+			e.Name,
+			e.Type,
+			result,
+			fmt.Sprintf("%.0fms", e.ResponseTime),
+			e.Message,
+		})
+	}
+
+	RenderTable(headers, rows)
+	fmt.Println()
+
+	if failures == 0 {
+		PrintSuccess(fmt.Sprintf("All %d providers passed", len(entries)))
+	}
+	return summaryErr()
+}
+
+// parseProviderParams parses --param key=value flags. Numbers and booleans
+// are sent as such so they reach the LLM API typed; an empty value removes
+// the parameter.
+func parseProviderParams(flags []string) (map[string]any, error) {
+	params := make(map[string]any, len(flags))
+	for _, f := range flags {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --param %q: expected key=value", f)
+		}
+		switch {
+		case value == "":
+			params[key] = nil
+		case value == "true" || value == "false":
+			params[key] = value == "true"
+		default:
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				params[key] = n
+			} else {
+				params[key] = value
+			}
+		}
+	}
+	return params, nil
+}
+
+// formatProviderParams renders params as sorted key=value pairs
+func formatProviderParams(params map[string]any) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, params[k])
+	}
+	return strings.Join(pairs, ",")
+}
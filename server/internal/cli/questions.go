@@ -43,7 +43,7 @@ func listQuestions(cmd *cobra.Command, client *api.Client) error {
 		return fmt.Errorf("failed to list questions: %w", err)
 	}
 
-	if tryJSON(cmd, questions) {
+	if tryOutput(cmd, questions) {
 		return nil
 	}
 
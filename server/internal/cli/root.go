@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/diane-assistant/diane/internal/api"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 )
 
@@ -29,8 +31,21 @@ func NewRootCmd(client *api.Client, version string) *cobra.Command {
 	}
 
 	// Global flags
-	rootCmd.PersistentFlags().Bool("json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().Bool("json", false, "Output in JSON format (alias for --output json)")
+	rootCmd.PersistentFlags().StringP("output", "o", formatTable, "Output format: table, json or yaml")
 	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output")
+	// Applied by ApplyProfileFlag before the command runs; declared here so it
+	// shows in help and parses anywhere on the command line
+	rootCmd.PersistentFlags().String("profile", "", "Run against a separate Diane instance in ~/.diane-<profile> (env DIANE_PROFILE)")
+	// Applied by ApplyConfigFlag, like --profile
+	rootCmd.PersistentFlags().String("config", "", "Read configuration from this file instead of config.json (env DIANE_CONFIG)")
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+			lipgloss.SetColorProfile(termenv.Ascii)
+		}
+		return validateOutputFormat(cmd)
+	}
 
 	// Add all command groups
 	rootCmd.AddCommand(newStatusCmd(client))
@@ -40,6 +55,7 @@ func NewRootCmd(client *api.Client, version string) *cobra.Command {
 	rootCmd.AddCommand(newMCPCmd(client))
 	rootCmd.AddCommand(newReloadCmd(client))
 	rootCmd.AddCommand(newRestartCmd(client))
+	rootCmd.AddCommand(newRestartDaemonCmd(client))
 	rootCmd.AddCommand(newAgentsCmd(client))
 	rootCmd.AddCommand(newAgentCmd(client))
 	rootCmd.AddCommand(newSessionsCmd(client))
@@ -49,18 +65,21 @@ func NewRootCmd(client *api.Client, version string) *cobra.Command {
 	rootCmd.AddCommand(newContextCmd(client))
 	rootCmd.AddCommand(newProviderCmd(client))
 	rootCmd.AddCommand(newJobsCmd(client))
+	rootCmd.AddCommand(newNotifyCmd(client))
 	rootCmd.AddCommand(newToolsCmd(client))
 	rootCmd.AddCommand(newPromptsCmd(client))
 	rootCmd.AddCommand(newResourcesCmd(client))
 	rootCmd.AddCommand(newHostsCmd(client))
 	rootCmd.AddCommand(newUsageCmd(client))
 	rootCmd.AddCommand(newInfoCmd(client))
+	rootCmd.AddCommand(newConfigCmd(client))
 	rootCmd.AddCommand(newPairCmd())
 	rootCmd.AddCommand(newLogsCmd())
 	rootCmd.AddCommand(newSlaveCmd(client))
 	rootCmd.AddCommand(newUpgradeCmd())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newQuestionsCmd(client))
+	rootCmd.AddCommand(newCompletionCmd())
 
 	return rootCmd
 }
@@ -74,7 +93,7 @@ func newHealthCmd(client *api.Client) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := client.Health(); err != nil {
 				PrintError(fmt.Sprintf("Diane is not running: %v", err))
-				os.Exit(1)
+				os.Exit(ExitCode(err))
 			}
 			PrintSuccess("Diane is running")
 			return nil
@@ -87,29 +106,154 @@ func newReloadCmd(client *api.Client) *cobra.Command {
 		Use:   "reload",
 		Short: "Reload MCP configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := client.ReloadConfig(); err != nil {
+			result, err := client.ReloadConfig()
+			if err != nil {
 				return fmt.Errorf("reload failed: %w", err)
 			}
+
+			if tryOutput(cmd, result) {
+				return nil
+			}
+
 			PrintSuccess("Configuration reloaded")
+			changes := []struct {
+				label   string
+				servers []string
+			}{
+				{"Started", result.Started},
+				{"Stopped", result.Stopped},
+				{"Restarted", result.Restarted},
+				{"Reconfigured", result.Reconfigured},
+			}
+			changed := false
+			for _, c := range changes {
+				if len(c.servers) > 0 {
+					fmt.Printf("  %-13s %s\n", c.label+":", strings.Join(c.servers, ", "))
+					changed = true
+				}
+			}
+			if !changed {
+				fmt.Println("  No MCP servers changed")
+			}
 			return nil
 		},
 	}
 }
 
 func newRestartCmd(client *api.Client) *cobra.Command {
-	return &cobra.Command{
-		Use:   "restart <server-name>",
-		Short: "Restart a specific MCP server",
-		Args:  cobra.ExactArgs(1),
+	cmd := &cobra.Command{
+		Use:   "restart [server-name]",
+		Short: "Restart a specific MCP server, or several with --all/--failed",
+		Args: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool("all")
+			failed, _ := cmd.Flags().GetBool("failed")
+			if all && failed {
+				return fmt.Errorf("--all and --failed cannot be used together")
+			}
+			if all || failed {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		ValidArgsFunction: completeFirstArg(mcpServerNames(client)),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := args[0]
-			if err := client.RestartMCPServer(name); err != nil {
+			all, _ := cmd.Flags().GetBool("all")
+			failed, _ := cmd.Flags().GetBool("failed")
+
+			if !all && !failed {
+				name := args[0]
+				if err := client.RestartMCPServer(name); err != nil {
+					return fmt.Errorf("restart failed: %w", err)
+				}
+				PrintSuccess(fmt.Sprintf("Server '%s' restarted", name))
+				return nil
+			}
+
+			results, err := client.RestartMCPServers(failed)
+			if err != nil {
 				return fmt.Errorf("restart failed: %w", err)
 			}
-			PrintSuccess(fmt.Sprintf("Server '%s' restarted", name))
+
+			if tryOutput(cmd, results) {
+				return nil
+			}
+
+			if len(results) == 0 {
+				if failed {
+					PrintSuccess("No failed servers to restart")
+				} else {
+					PrintWarning("No enabled MCP servers to restart")
+				}
+				return nil
+			}
+
+			failures := 0
+			for _, r := range results {
+				if r.Success {
+					PrintSuccess(fmt.Sprintf("Server '%s' restarted", r.Name))
+				} else {
+					failures++
+					PrintError(fmt.Sprintf("Server '%s' failed to restart: %s", r.Name, r.Error))
+				}
+			}
+
+			if failures > 0 {
+				return fmt.Errorf("%d of %d servers failed to restart", failures, len(results))
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().Bool("all", false, "Restart every enabled MCP server")
+	cmd.Flags().Bool("failed", false, "Restart only servers that are disconnected or in an error state")
+
+	return cmd
+}
+
+func newRestartDaemonCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart-daemon",
+		Short: "Gracefully restart the Diane daemon in place",
+		Long: "Asks a daemon running in serve mode to drain in-flight work, release its\n" +
+			"lock and re-exec itself with the same arguments and environment. Use this\n" +
+			"after changing settings that are only read at startup, such as the listen port.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+
+			before, err := client.GetStatus()
+			if err != nil {
+				return fmt.Errorf("restart failed: %w", err)
+			}
+
+			if err := client.RestartDaemon(); err != nil {
+				return err
+			}
+
+			if !wait {
+				PrintSuccess("Daemon restart requested")
+				return nil
+			}
+
+			// The PID survives exec, so a newer start time is what marks the new process
+			deadline := time.Now().Add(timeout)
+			for time.Now().Before(deadline) {
+				time.Sleep(500 * time.Millisecond)
+				status, err := client.GetStatus()
+				if err == nil && status.StartedAt.After(before.StartedAt) {
+					PrintSuccess(fmt.Sprintf("Daemon restarted (pid %d, version %s)", status.PID, status.Version))
+					return nil
+				}
+			}
+			return fmt.Errorf("daemon did not come back within %v", timeout)
+		},
+	}
+
+	cmd.Flags().Bool("wait", true, "Wait for the daemon to come back up")
+	cmd.Flags().Duration("timeout", 30*time.Second, "How long to wait for the daemon to come back")
+
+	return cmd
 }
 
 func newVersionCmd() *cobra.Command {
@@ -125,8 +269,7 @@ func newVersionCmd() *cobra.Command {
 // --- JSON output helper ---
 
 func outputJSON(cmd *cobra.Command, v interface{}) error {
-	jsonFlag, _ := cmd.Flags().GetBool("json")
-	if jsonFlag {
+	if outputFormat(cmd) == formatJSON {
 		out, err := json.MarshalIndent(v, "", "  ")
 		if err != nil {
 			return err
@@ -137,20 +280,6 @@ func outputJSON(cmd *cobra.Command, v interface{}) error {
 	return fmt.Errorf("not json mode")
 }
 
-// tryJSON returns true if --json was set and data was printed
-func tryJSON(cmd *cobra.Command, v interface{}) bool {
-	jsonFlag, _ := cmd.Flags().GetBool("json")
-	if !jsonFlag {
-		return false
-	}
-	out, err := json.MarshalIndent(v, "", "  ")
-	if err != nil {
-		return false
-	}
-	fmt.Println(string(out))
-	return true
-}
-
 // formatDuration formats a duration as human-readable
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -23,7 +23,7 @@ func newSessionsCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to list sessions: %w", err)
 			}
 
-			if tryJSON(cmd, sessions) {
+			if tryOutput(cmd, sessions) {
 				return nil
 			}
 
@@ -102,7 +102,7 @@ func newSessionStartCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to start session: %w", err)
 			}
 
-			if tryJSON(cmd, info) {
+			if tryOutput(cmd, info) {
 				return nil
 			}
 
@@ -143,7 +143,7 @@ func newSessionPromptCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to prompt session: %w", err)
 			}
 
-			if tryJSON(cmd, run) {
+			if tryOutput(cmd, run) {
 				return nil
 			}
 
@@ -180,7 +180,7 @@ func newSessionInfoCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get session: %w", err)
 			}
 
-			if tryJSON(cmd, info) {
+			if tryOutput(cmd, info) {
 				return nil
 			}
 
@@ -267,7 +267,7 @@ func newSessionMessagesCmd(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get session messages: %w", err)
 			}
 
-			if tryJSON(cmd, messages) {
+			if tryOutput(cmd, messages) {
 				return nil
 			}
 
@@ -22,7 +22,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/diane-assistant/diane/internal/api"
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/internal/logger"
+	"github.com/diane-assistant/diane/internal/qr"
 	"github.com/spf13/cobra"
 )
 
@@ -41,6 +45,8 @@ func newSlaveCmd(client *api.Client) *cobra.Command {
 	slaveCmd.AddCommand(newSlaveListCmd(client))
 	slaveCmd.AddCommand(newSlaveRevokeCmd(client))
 	slaveCmd.AddCommand(newSlaveRevokedCmd(client))
+	slaveCmd.AddCommand(newSlaveLogsCmd(client))
+	slaveCmd.AddCommand(newSlaveTestCmd(client))
 
 	return slaveCmd
 }
@@ -48,7 +54,7 @@ func newSlaveCmd(client *api.Client) *cobra.Command {
 // --- Slave-side commands ---
 
 func newSlavePairCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "pair <master-url>",
 		Short: "Initiate pairing with master (run on slave)",
 		Long: `Initiate pairing with the master Diane server.
@@ -68,7 +74,11 @@ The pairing process will:
   5. Save credentials and start the slave daemon
 
 After running this command, approve the request on the master with:
-  diane slave approve`,
+  diane slave approve
+
+With --qr the master URL, host and pairing code are also shown as a QR
+code. Scan it and pass the scanned text to 'diane slave approve' on the
+master to approve this exact request.`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
 				return fmt.Errorf("master URL is required\n\nUsage: diane slave pair <master-url>\n\nExamples:\n  diane slave pair https://master.example.com:8766\n  diane slave pair https://100.71.82.7:8766\n\nThe master URL should point to your master Diane server on port 8766 (HTTPS).")
@@ -138,6 +148,9 @@ After running this command, approve the request on the master with:
 				"csr":      string(csrPEM),
 				"platform": runtime.GOOS,
 			}
+			if token, _ := cmd.Flags().GetString("token"); token != "" {
+				reqBody["token"] = token
+			}
 			jsonBody, _ := json.Marshal(reqBody)
 
 			fmt.Println("\nConnecting to master...")
@@ -180,9 +193,10 @@ After running this command, approve the request on the master with:
 			defer resp.Body.Close()
 
 			var pairResp struct {
-				Success     bool   `json:"success"`
-				Message     string `json:"message"`
-				PairingCode string `json:"pairing_code"`
+				Success      bool   `json:"success"`
+				Message      string `json:"message"`
+				PairingCode  string `json:"pairing_code"`
+				AutoApproved bool   `json:"auto_approved"`
 			}
 			if err := json.NewDecoder(resp.Body).Decode(&pairResp); err != nil {
 				return fmt.Errorf("error decoding response: %w", err)
@@ -192,11 +206,28 @@ After running this command, approve the request on the master with:
 			fmt.Println("--------------------------------------------------")
 			fmt.Printf("PAIRING CODE: %s\n", pairResp.PairingCode)
 			fmt.Println("--------------------------------------------------")
-			fmt.Println("\nOn the master server, run:")
-			fmt.Println("  diane slave approve")
-			fmt.Println("\nThis will show pending requests. Verify the code matches")
-			fmt.Println("and confirm to approve.")
-			fmt.Println("\nWaiting for approval... (Press Ctrl+C to cancel)")
+			if pairResp.AutoApproved {
+				fmt.Println("\nThe master approved this request automatically.")
+				fmt.Println("Fetching certificate...")
+			} else {
+				if showQR, _ := cmd.Flags().GetBool("qr"); showQR {
+					payload := pairingPayload(masterURL, hostname, pairResp.PairingCode)
+					code, err := qr.Encode(payload)
+					if err != nil {
+						PrintWarning(fmt.Sprintf("Cannot show a QR code: %v", err))
+					} else {
+						fmt.Println()
+						fmt.Print(code.Terminal())
+						fmt.Println("\nScan the code, then on the master run:")
+						fmt.Printf("  diane slave approve '%s'\n", payload)
+					}
+				}
+				fmt.Println("\nOn the master server, run:")
+				fmt.Println("  diane slave approve")
+				fmt.Println("\nThis will show pending requests. Verify the code matches")
+				fmt.Println("and confirm to approve.")
+				fmt.Println("\nWaiting for approval... (Press Ctrl+C to cancel)")
+			}
 
 			// Poll for approval
 			ticker := time.NewTicker(2 * time.Second)
@@ -226,7 +257,7 @@ After running this command, approve the request on the master with:
 					PrintSuccess("Pairing approved!")
 
 					home, _ := os.UserHomeDir()
-					dianeDir := filepath.Join(home, ".diane")
+					dianeDir := filepath.Join(home, config.DirName())
 					os.MkdirAll(dianeDir, 0755)
 
 					// Save private key
@@ -278,6 +309,11 @@ After running this command, approve the request on the master with:
 			return nil
 		},
 	}
+
+	cmd.Flags().Bool("qr", false, "Also show the pairing request as a QR code to scan and approve on the master")
+	cmd.Flags().String("token", "", "Pre-shared token matching the master's slave.auto_approve_token, to be approved without an operator")
+
+	return cmd
 }
 
 func newSlaveStartCmd() *cobra.Command {
@@ -328,7 +364,7 @@ func slaveStart() {
 	}
 
 	home, _ := os.UserHomeDir()
-	logPath := filepath.Join(home, ".diane", "slave.log")
+	logPath := filepath.Join(home, config.DirName(), "slave.log")
 	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err == nil {
 		slaveCmd.Stdout = logFile
@@ -342,7 +378,7 @@ func slaveStart() {
 	}
 
 	// Save PID
-	pidPath := filepath.Join(home, ".diane", "diane.pid")
+	pidPath := filepath.Join(home, config.DirName(), "diane.pid")
 	os.WriteFile(pidPath, []byte(fmt.Sprintf("%d", slaveCmd.Process.Pid)), 0644)
 
 	PrintSuccess(fmt.Sprintf("Diane daemon started (PID: %d)", slaveCmd.Process.Pid))
@@ -416,7 +452,7 @@ func newSlavePendingCmd(client *api.Client) *cobra.Command {
 				return nil
 			}
 
-			if tryJSON(cmd, reqs) {
+			if tryOutput(cmd, reqs) {
 				return nil
 			}
 
@@ -434,22 +470,36 @@ func newSlavePendingCmd(client *api.Client) *cobra.Command {
 
 func newSlaveApproveCmd(client *api.Client) *cobra.Command {
 	return &cobra.Command{
-		Use:   "approve [hostname] [code]",
+		Use:   "approve [hostname code | scanned-payload]",
 		Short: "Approve a pairing request (run on master)",
+		Long: `Approve a pairing request. Without arguments, pending requests are listed
+to choose from. Give a hostname and pairing code, or the text scanned from
+the QR code shown by 'diane slave pair --qr'.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				// Interactive mode
 				return slaveApproveInteractive(client)
 			}
 
-			hostname := args[0]
-			if len(args) < 2 {
-				return fmt.Errorf("approve command requires hostname and pairing code")
-			}
+			var hostname, code string
+			if len(args) == 1 && strings.HasPrefix(args[0], pairingPayloadPrefix) {
+				var masterURL string
+				var err error
+				hostname, code, masterURL, err = parsePairingPayload(args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Pairing request from %s to %s\n", hostname, masterURL)
+			} else {
+				hostname = args[0]
+				if len(args) < 2 {
+					return fmt.Errorf("approve command requires hostname and pairing code")
+				}
 
-			code := args[1]
-			if len(args) > 2 {
-				code = args[1] + " " + args[2]
+				code = args[1]
+				if len(args) > 2 {
+					code = args[1] + " " + args[2]
+				}
 			}
 			code = normalizePairingCode(code)
 
@@ -559,11 +609,11 @@ func newSlaveListCmd(client *api.Client) *cobra.Command {
 				return nil
 			}
 
-			if tryJSON(cmd, slaves) {
+			if tryOutput(cmd, slaves) {
 				return nil
 			}
 
-			headers := []string{"HOSTNAME", "STATUS", "TOOLS", "LAST SEEN"}
+			headers := []string{"HOSTNAME", "STATUS", "TOOLS", "LAST SEEN", "CERT EXPIRES"}
 			var rows [][]string
 			for _, s := range slaves {
 				lastSeen := "never"
@@ -571,7 +621,7 @@ func newSlaveListCmd(client *api.Client) *cobra.Command {
 					t, _ := time.Parse(time.RFC3339, s.LastSeen)
 					lastSeen = t.Format(time.Kitchen)
 				}
-				rows = append(rows, []string{s.Hostname, s.Status, fmt.Sprintf("%d", s.ToolCount), lastSeen})
+				rows = append(rows, []string{s.Hostname, s.Status, fmt.Sprintf("%d", s.ToolCount), lastSeen, certExpiry(s.ExpiresAt)})
 			}
 			RenderTable(headers, rows)
 			return nil
@@ -579,6 +629,177 @@ func newSlaveListCmd(client *api.Client) *cobra.Command {
 	}
 }
 
+func newSlaveLogsCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs <hostname>",
+		Short: "Show a slave's status and recent logs (run on master)",
+		Long: `Fetch a connected slave's status and the tail of its in-memory log buffer
+over the existing secure connection. Only entries that passed the slave's
+own log level are available.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hostname := args[0]
+			lines, _ := cmd.Flags().GetInt("lines")
+			level, _ := cmd.Flags().GetString("level")
+			follow, _ := cmd.Flags().GetBool("follow")
+
+			logs, err := client.GetSlaveLogs(hostname, lines, level)
+			if err != nil {
+				return fmt.Errorf("error: %w", err)
+			}
+
+			if tryOutput(cmd, logs) {
+				return nil
+			}
+
+			printSlaveStatus(logs)
+			printSlaveLogEntries(logs.Entries)
+			if !follow {
+				return nil
+			}
+
+			fmt.Fprintf(os.Stderr, "\nFollowing logs from %s (Ctrl+C to stop)...\n", hostname)
+			var last time.Time
+			if n := len(logs.Entries); n > 0 {
+				last = logs.Entries[n-1].Time
+			}
+			for {
+				time.Sleep(2 * time.Second)
+				logs, err := client.GetSlaveLogs(hostname, lines, level)
+				if err != nil {
+					return fmt.Errorf("error: %w", err)
+				}
+				var fresh []logger.Entry
+				for _, e := range logs.Entries {
+					if e.Time.After(last) {
+						fresh = append(fresh, e)
+						last = e.Time
+					}
+				}
+				printSlaveLogEntries(fresh)
+			}
+		},
+	}
+
+	cmd.Flags().IntP("lines", "n", 100, "Number of log entries to show")
+	cmd.Flags().String("level", "", "Minimum level to show: debug, info, warn or error")
+	cmd.Flags().BoolP("follow", "f", false, "Keep polling for new entries")
+	return cmd
+}
+
+func newSlaveTestCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "test <hostname>",
+		Short: "Check connectivity and latency to a slave (run on master)",
+		Long: `Ping a slave over its existing secure connection and report whether it is
+connected, the measured round-trip latency, its tool count and when its
+certificate expires.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hostname := args[0]
+			result, err := client.TestSlave(hostname)
+			if err != nil {
+				return fmt.Errorf("error: %w", err)
+			}
+
+			if tryOutput(cmd, result) {
+				return nil
+			}
+
+			fmt.Println(titleStyle.Render(fmt.Sprintf("Slave Test: %s", hostname)))
+			fmt.Println()
+
+			statusColor := lipgloss.Color("82") // green
+			switch result.Status {
+			case "disconnected":
+				statusColor = lipgloss.Color("196") // red
+			case "unresponsive":
+				statusColor = lipgloss.Color("208") // orange
+			}
+
+			fmt.Printf("  Status:       %s\n", lipgloss.NewStyle().Foreground(statusColor).Bold(true).Render(result.Status))
+			if result.Status == "connected" {
+				fmt.Printf("  Latency:      %.1fms\n", result.LatencyMs)
+			}
+			fmt.Printf("  Tools:        %d\n", result.ToolCount)
+			fmt.Printf("  Cert expires: %s\n", certExpiry(result.ExpiresAt))
+			if result.Error != "" {
+				fmt.Printf("  Error:        %s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(result.Error))
+			}
+
+			return nil
+		},
+	}
+}
+
+// printSlaveStatus prints the status summary a slave returned with its logs
+func printSlaveStatus(logs *api.SlaveLogs) {
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Slave: %s", logs.Hostname)))
+	st := logs.Status
+	if st == nil {
+		fmt.Println("  Status: not reported")
+		fmt.Println()
+		return
+	}
+
+	connected, failed := 0, 0
+	for _, srv := range st.MCPServers {
+		if !srv.Enabled {
+			continue
+		}
+		if srv.Connected {
+			connected++
+		} else {
+			failed++
+		}
+	}
+
+	fmt.Printf("  Version:     %s (%s/%s)\n", st.Version, st.Platform, st.Architecture)
+	fmt.Printf("  Uptime:      %s\n", st.Uptime)
+	fmt.Printf("  Tools:       %d\n", st.TotalTools)
+	fmt.Printf("  MCP servers: %d connected, %d failed\n", connected, failed)
+	if st.SlaveMode {
+		master := "connected"
+		if !st.SlaveConnected {
+			master = "disconnected"
+			if st.SlaveError != "" {
+				master += " (" + st.SlaveError + ")"
+			}
+		}
+		fmt.Printf("  Master:      %s\n", master)
+	}
+	fmt.Println()
+}
+
+// printSlaveLogEntries prints log entries one per line, oldest first
+func printSlaveLogEntries(entries []logger.Entry) {
+	for _, e := range entries {
+		line := fmt.Sprintf("%s %-5s %s", e.Time.Local().Format("15:04:05"), e.Level, e.Message)
+		if e.Attrs != "" {
+			line += " " + e.Attrs
+		}
+		fmt.Println(line)
+	}
+}
+
+// certExpiry formats a certificate expiry date, flagging certificates that
+// expire within 30 days (slaves renew automatically inside that window)
+func certExpiry(expiresAt string) string {
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return "unknown"
+	}
+	days := int(time.Until(t).Hours() / 24)
+	switch {
+	case days < 0:
+		return t.Format("2006-01-02") + " (expired)"
+	case days < 30:
+		return fmt.Sprintf("%s (%dd left)", t.Format("2006-01-02"), days)
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
 func newSlaveRevokeCmd(client *api.Client) *cobra.Command {
 	return &cobra.Command{
 		Use:   "revoke <hostname>",
@@ -610,7 +831,7 @@ func newSlaveRevokedCmd(client *api.Client) *cobra.Command {
 				return nil
 			}
 
-			if tryJSON(cmd, revoked) {
+			if tryOutput(cmd, revoked) {
 				return nil
 			}
 
@@ -626,6 +847,29 @@ func newSlaveRevokedCmd(client *api.Client) *cobra.Command {
 	}
 }
 
+// pairingPayloadPrefix starts the pairing payload shown as a QR code by
+// slave pair --qr
+const pairingPayloadPrefix = "diane-pair:"
+
+// pairingPayload encodes what the master needs to approve a pairing request
+func pairingPayload(masterURL, hostname, code string) string {
+	q := url.Values{"master": {masterURL}, "host": {hostname}, "code": {code}}
+	return pairingPayloadPrefix + "?" + q.Encode()
+}
+
+// parsePairingPayload decodes a payload made by pairingPayload
+func parsePairingPayload(payload string) (hostname, code, masterURL string, err error) {
+	q, err := url.ParseQuery(strings.TrimPrefix(strings.TrimPrefix(payload, pairingPayloadPrefix), "?"))
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid pairing payload: %w", err)
+	}
+	hostname, code, masterURL = q.Get("host"), q.Get("code"), q.Get("master")
+	if hostname == "" || code == "" {
+		return "", "", "", fmt.Errorf("invalid pairing payload: host and code are required")
+	}
+	return hostname, code, masterURL, nil
+}
+
 // normalizePairingCode normalizes the pairing code format (e.g., "123 456" -> "123-456")
 func normalizePairingCode(code string) string {
 	code = strings.ReplaceAll(code, " ", "-")
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -11,17 +14,39 @@ import (
 )
 
 func newStatusCmd(client *api.Client) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show Diane daemon status and MCP server overview",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				if outputFormat(cmd) != formatTable {
+					return fmt.Errorf("--watch only supports table output")
+				}
+				interval, _ := cmd.Flags().GetDuration("interval")
+				if interval <= 0 {
+					return fmt.Errorf("--interval must be positive")
+				}
+
+				stop := make(chan struct{})
+				sigCh := make(chan os.Signal, 1)
+				signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+				defer signal.Stop(sigCh)
+				go func() {
+					<-sigCh
+					close(stop)
+				}()
+
+				watchStatus(client, interval, stop)
+				return nil
+			}
+
 			status, err := client.GetStatus()
 			if err != nil {
 				PrintError(fmt.Sprintf("Could not reach Diane daemon: %v", err))
 				return nil
 			}
 
-			if tryJSON(cmd, status) {
+			if tryOutput(cmd, status) {
 				return nil
 			}
 
@@ -29,6 +54,43 @@ func newStatusCmd(client *api.Client) *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolP("watch", "w", false, "Continuously redraw the status until interrupted")
+	cmd.Flags().Duration("interval", 2*time.Second, "Refresh interval for --watch")
+
+	return cmd
+}
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// watchStatus redraws the status dashboard every interval until stop is closed.
+// If the daemon can't be reached it keeps polling and says so, rather than exiting.
+func watchStatus(client *api.Client, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	for {
+		status, err := client.GetStatus()
+
+		fmt.Print(clearScreen)
+		if err != nil {
+			fmt.Println()
+			PrintWarning("Daemon unreachable, retrying...")
+			fmt.Printf("  %s\n\n", dim.Render(err.Error()))
+		} else {
+			renderStatusDashboard(status)
+		}
+		fmt.Println(dim.Render(fmt.Sprintf("  Updated %s, refreshing every %v (Ctrl+C to exit)",
+			time.Now().Format("15:04:05"), interval)))
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 func renderStatusDashboard(s *api.Status) {
@@ -107,6 +169,9 @@ func renderStatusDashboard(s *api.Status) {
 
 	for _, srv := range s.MCPServers {
 		dot := GetStatusDot(srv.Connected, srv.Error != "")
+		if srv.Degraded {
+			dot = warnDot.String()
+		}
 
 		nameStyle := lipgloss.NewStyle().Width(maxNameLen + 1)
 		name := nameStyle.Render(srv.Name)
@@ -121,7 +186,9 @@ func renderStatusDashboard(s *api.Status) {
 
 		// Build the detail string
 		var detail string
-		if srv.Error != "" {
+		if srv.Degraded {
+			detail = lipgloss.NewStyle().Foreground(warning).Render("degraded: " + srv.Error)
+		} else if srv.Error != "" {
 			detail = lipgloss.NewStyle().Foreground(errorColor).Render("error: " + srv.Error)
 		} else if !srv.Enabled {
 			detail = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("disabled")
@@ -1,8 +1,16 @@
 package cli
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/diane-assistant/diane/internal/api"
 	"github.com/spf13/cobra"
 )
@@ -12,13 +20,17 @@ func newToolsCmd(client *api.Client) *cobra.Command {
 		Use:   "tools",
 		Short: "List available tools across MCP servers",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if category, _ := cmd.Flags().GetString("category"); category != "" {
+				return listToolsInCategory(cmd, client, category)
+			}
+
 			servers, err := client.GetMCPServers()
 			if err != nil {
 				PrintError(fmt.Sprintf("Failed to get MCP servers: %v", err))
 				return nil
 			}
 
-			if tryJSON(cmd, servers) {
+			if tryOutput(cmd, servers) {
 				return nil
 			}
 
@@ -71,10 +83,485 @@ func newToolsCmd(client *api.Client) *cobra.Command {
 	}
 
 	cmd.Flags().String("server", "", "Filter by server name")
+	cmd.Flags().String("category", "", "List the tools in a category (e.g. email, calendar, files, finance)")
+
+	cmd.AddCommand(newToolsCallCmd(client))
+	cmd.AddCommand(newToolsDescribeCmd(client))
+	cmd.AddCommand(newToolsSetDescriptionCmd(client))
+	cmd.AddCommand(newToolsExportCmd(client))
 
 	return cmd
 }
 
+// listToolsInCategory lists the tools in category, optionally only those of
+// the --server server
+func listToolsInCategory(cmd *cobra.Command, client *api.Client, category string) error {
+	all, err := client.GetTools()
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to get tools: %v", err))
+		return nil
+	}
+
+	serverFilter, _ := cmd.Flags().GetString("server")
+	var filtered []api.ToolInfo
+	for _, t := range all {
+		if t.Category != category || (serverFilter != "" && t.Server != serverFilter) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+
+	if tryOutput(cmd, filtered) {
+		return nil
+	}
+
+	if len(filtered) == 0 {
+		PrintWarning(fmt.Sprintf("No tools found in category '%s'", category))
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("  %s\n", titleStyle.Render(fmt.Sprintf("Tools in %s (%d)", category, len(filtered))))
+
+	headers := []string{"Tool", "Server", "Description"}
+	var rows [][]string
+	for _, t := range filtered {
+		desc := t.Description
+		if i := strings.IndexByte(desc, '\n'); i >= 0 {
+			desc = desc[:i]
+		}
+		rows = append(rows, []string{t.Name, t.Server, desc})
+	}
+
+	RenderTable(headers, rows)
+	fmt.Println()
+	return nil
+}
+
+func newToolsCallCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call <name>",
+		Short: "Call a tool directly through the daemon",
+		Long: `Call a tool by name through the daemon's tool dispatch and print the
+result. Arguments are given as repeated --arg key=value, where each value is
+parsed as JSON if it can be (so numbers, booleans, arrays and objects work)
+and taken as a string otherwise, or as a single --json object. --arg values
+override keys from --json.
+
+Use --context to call the tool as a client in that context would, so tools
+the context doesn't enable are refused.`,
+		Example: `  diane-ctl tools call weather_forecast --arg location=Oslo --arg days=3
+  diane-ctl tools call job_add --json '{"name":"ping","schedule":"@hourly","command":"echo hi"}'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonArgs, _ := cmd.Flags().GetString("json")
+			pairs, _ := cmd.Flags().GetStringArray("arg")
+			contextName, _ := cmd.Flags().GetString("context")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+
+			toolArgs, err := parseToolArgs(jsonArgs, pairs)
+			if err != nil {
+				return err
+			}
+
+			result, err := client.WithTimeout(timeout).CallTool(args[0], toolArgs, contextName)
+			if err != nil {
+				return fmt.Errorf("failed to call %s: %w", args[0], err)
+			}
+
+			if tryOutput(cmd, result) {
+				if result.IsError {
+					return fmt.Errorf("tool %s reported an error", args[0])
+				}
+				return nil
+			}
+
+			for _, block := range result.Content {
+				switch block.Type {
+				case "text":
+					fmt.Println(block.Text)
+				case "image":
+					size := base64.StdEncoding.DecodedLen(len(block.Data))
+					fmt.Printf("[image: %s, ~%d bytes]\n", block.MimeType, size)
+				default:
+					fmt.Printf("[%s content]\n", block.Type)
+				}
+			}
+			if result.IsError {
+				return fmt.Errorf("tool %s reported an error", args[0])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArray("arg", nil, "Argument as key=value, the value parsed as JSON if possible (repeatable)")
+	cmd.Flags().String("json", "", "All arguments as a JSON object")
+	cmd.Flags().String("context", "", "Call the tool as a client in this context would")
+	cmd.Flags().Duration("timeout", 2*time.Minute, "How long to wait for the tool")
+
+	return cmd
+}
+
+func newToolsDescribeCmd(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Show a tool's description and arguments",
+		Long: `Show a tool's description and the arguments its input schema accepts:
+name, type, whether it's required, default and description. With --json (or
+-o json/yaml) the raw input schema is printed instead.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tools, err := client.GetTools()
+			if err != nil {
+				return err
+			}
+			var tool *api.ToolInfo
+			for i := range tools {
+				if tools[i].Name == args[0] {
+					tool = &tools[i]
+					break
+				}
+			}
+			if tool == nil {
+				return &api.StatusError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("tool %q not found", args[0])}
+			}
+
+			if tryOutput(cmd, tool.InputSchema) {
+				return nil
+			}
+
+			fmt.Println()
+			fmt.Printf("  %s\n", titleStyle.Render(tool.Name))
+			serverType := "stdio"
+			if tool.Builtin {
+				serverType = "builtin"
+			}
+			fmt.Printf("  Server: %s %s\n", tool.Server, GetTypeBadge(serverType))
+			if tool.Category != "" {
+				fmt.Printf("  Category: %s\n", tool.Category)
+			}
+			if tool.Customized {
+				fmt.Printf("  %s\n", lipgloss.NewStyle().Foreground(warning).Render("Customized (diane-ctl tools set-description --reset to undo)"))
+			}
+			if tool.Description != "" {
+				fmt.Printf("\n  %s\n", tool.Description)
+			}
+			if tool.OriginalDescription != "" {
+				fmt.Printf("\n  Original: %s\n", tool.OriginalDescription)
+			}
+			if len(tool.HiddenArgs) > 0 {
+				fmt.Printf("\n  Hidden arguments: %s\n", strings.Join(tool.HiddenArgs, ", "))
+			}
+			fmt.Println()
+
+			rows := schemaArgumentRows(tool.InputSchema)
+			if len(rows) == 0 {
+				fmt.Println("  No arguments.")
+				fmt.Println()
+				return nil
+			}
+			RenderTable([]string{"Argument", "Type", "Required", "Default", "Description"}, rows)
+			fmt.Println()
+			return nil
+		},
+	}
+}
+
+func newToolsSetDescriptionCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-description <tool> [description]",
+		Short: "Override the description a tool is offered with",
+		Long: `Override the description MCP clients see for a tool, to steer the model
+towards how you use it. --hide-arg leaves an optional argument out of the
+tool's input schema, so the model won't set it. Customizations are stored
+by the daemon and survive reloads and restarts; tools describe marks
+customized tools and shows the original description.
+
+Without a description the tool keeps its own and only --hide-arg applies.
+--reset removes the customization.`,
+		Example: `  diane-ctl tools set-description gmail_search_emails "Search my work inbox. Always add in:inbox."
+  diane-ctl tools set-description weather_get_weather --hide-arg units
+  diane-ctl tools set-description gmail_search_emails --reset`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reset, _ := cmd.Flags().GetBool("reset")
+			hidden, _ := cmd.Flags().GetStringSlice("hide-arg")
+
+			custom := api.ToolCustomization{Tool: args[0], HiddenArgs: hidden}
+			if len(args) == 2 {
+				custom.Description = strings.TrimSpace(args[1])
+			}
+			switch {
+			case reset && (custom.Description != "" || len(hidden) > 0):
+				return fmt.Errorf("--reset can't be combined with a description or --hide-arg")
+			case !reset && custom.Description == "" && len(hidden) == 0:
+				return fmt.Errorf("give a description, --hide-arg or --reset")
+			}
+
+			if err := client.SetToolCustomization(custom); err != nil {
+				return err
+			}
+			if reset {
+				PrintSuccess(fmt.Sprintf("Removed customization of %s", args[0]))
+			} else {
+				PrintSuccess(fmt.Sprintf("Customized %s", args[0]))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("hide-arg", nil, "Optional argument to hide from the tool's schema (repeatable)")
+	cmd.Flags().Bool("reset", false, "Remove the tool's customization")
+
+	return cmd
+}
+
+// Tool catalog export formats
+const (
+	exportCatalog    = "catalog"
+	exportJSONSchema = "json-schema"
+	exportOpenAPI    = "openapi"
+)
+
+func newToolsExportCmd(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the tool catalog as JSON for integration tooling",
+		Long: `Write every tool the daemon offers, with its server, builtin flag,
+description and input schema, as a single JSON document. Customized
+descriptions and hidden arguments are exported as MCP clients see them.
+
+Formats:
+  catalog      the tool list as returned by the API (default)
+  json-schema  a JSON Schema bundle with one definition per tool's arguments
+  openapi      an OpenAPI 3.1 document for POST /tools/call, one request
+               schema per tool`,
+		Example: `  diane-ctl tools export > tools.json
+  diane-ctl tools export --format openapi --file diane-openapi.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			file, _ := cmd.Flags().GetString("file")
+
+			tools, err := client.GetTools()
+			if err != nil {
+				return err
+			}
+
+			var doc interface{}
+			switch format {
+			case exportCatalog:
+				doc = map[string]interface{}{"tools": tools}
+			case exportJSONSchema:
+				doc = toolsJSONSchema(tools)
+			case exportOpenAPI:
+				doc = toolsOpenAPI(tools)
+			default:
+				return fmt.Errorf("unknown format %q (use %s, %s or %s)", format, exportCatalog, exportJSONSchema, exportOpenAPI)
+			}
+
+			data, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", format, err)
+			}
+			if file == "" {
+				fmt.Println(string(data))
+				return nil
+			}
+			if err := os.WriteFile(file, append(data, '\n'), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file, err)
+			}
+			PrintSuccess(fmt.Sprintf("Exported %d tools to %s", len(tools), file))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("format", exportCatalog, "Document format: catalog, json-schema or openapi")
+	cmd.Flags().String("file", "", "Write to this file instead of stdout")
+
+	return cmd
+}
+
+// exportInputSchema returns a tool's input schema for export, with the
+// tool's description and an object schema for tools that declare none
+func exportInputSchema(tool api.ToolInfo) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object"}
+	for k, v := range tool.InputSchema {
+		schema[k] = v
+	}
+	if tool.Description != "" {
+		schema["description"] = tool.Description
+	}
+	return schema
+}
+
+// toolsJSONSchema bundles the tools' argument schemas as JSON Schema
+// definitions keyed by tool name
+func toolsJSONSchema(tools []api.ToolInfo) map[string]interface{} {
+	defs := make(map[string]interface{}, len(tools))
+	for _, tool := range tools {
+		schema := exportInputSchema(tool)
+		schema["title"] = tool.Name
+		schema["x-diane-server"] = tool.Server
+		schema["x-diane-builtin"] = tool.Builtin
+		defs[tool.Name] = schema
+	}
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "Diane tool arguments",
+		"$defs":   defs,
+	}
+}
+
+// toolsOpenAPI describes POST /tools/call as an OpenAPI 3.1 document whose
+// request body is one of the tools' call requests
+func toolsOpenAPI(tools []api.ToolInfo) map[string]interface{} {
+	schemas := make(map[string]interface{}, len(tools))
+	refs := make([]interface{}, 0, len(tools))
+	mapping := make(map[string]string, len(tools))
+	for _, tool := range tools {
+		ref := "#/components/schemas/" + tool.Name
+		schemas[tool.Name] = map[string]interface{}{
+			"type":            "object",
+			"description":     tool.Description,
+			"required":        []string{"name", "arguments"},
+			"x-diane-server":  tool.Server,
+			"x-diane-builtin": tool.Builtin,
+			"properties": map[string]interface{}{
+				"name":      map[string]interface{}{"type": "string", "const": tool.Name},
+				"arguments": exportInputSchema(tool),
+				"context":   map[string]interface{}{"type": "string", "description": "Context to call the tool in"},
+			},
+		}
+		refs = append(refs, map[string]interface{}{"$ref": ref})
+		mapping[tool.Name] = ref
+	}
+
+	version := strings.TrimPrefix(cliVersion, "v")
+	if version == "" {
+		version = "dev"
+	}
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":   "Diane tools",
+			"version": version,
+		},
+		"paths": map[string]interface{}{
+			"/tools/call": map[string]interface{}{
+				"post": map[string]interface{}{
+					"operationId": "callTool",
+					"summary":     "Call a tool",
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"oneOf":         refs,
+									"discriminator": map[string]interface{}{"propertyName": "name", "mapping": mapping},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "The tool's result"},
+						"400": map[string]interface{}{"description": "Invalid arguments"},
+						"404": map[string]interface{}{"description": "Unknown tool"},
+					},
+				},
+			},
+		},
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// schemaArgumentRows renders an input schema's properties as table rows,
+// required arguments first, then by name
+func schemaArgumentRows(schema map[string]interface{}) [][]string {
+	props, _ := schema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, r := range list {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if required[names[i]] != required[names[j]] {
+			return required[names[i]]
+		}
+		return names[i] < names[j]
+	})
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		prop, _ := props[name].(map[string]interface{})
+		req := "no"
+		if required[name] {
+			req = "yes"
+		}
+		def := ""
+		if v, ok := prop["default"]; ok {
+			b, _ := json.Marshal(v)
+			def = string(b)
+		}
+		desc, _ := prop["description"].(string)
+		rows = append(rows, []string{name, schemaType(prop), req, def, desc})
+	}
+	return rows
+}
+
+// schemaType describes a property's type, e.g. "string", "array of integer"
+// or "string (metric|imperial)"
+func schemaType(prop map[string]interface{}) string {
+	t, _ := prop["type"].(string)
+	if t == "" {
+		t = "any"
+	}
+	if t == "array" {
+		if items, ok := prop["items"].(map[string]interface{}); ok {
+			t = "array of " + schemaType(items)
+		}
+	}
+	if enum, ok := prop["enum"].([]interface{}); ok && len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = fmt.Sprint(v)
+		}
+		t += " (" + strings.Join(values, "|") + ")"
+	}
+	return t
+}
+
+// parseToolArgs builds tool arguments from a JSON object and key=value pairs,
+// the pairs taking precedence
+func parseToolArgs(jsonArgs string, pairs []string) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	if jsonArgs != "" {
+		if err := json.Unmarshal([]byte(jsonArgs), &args); err != nil {
+			return nil, fmt.Errorf("invalid --json (expected a JSON object): %w", err)
+		}
+	}
+	for _, pair := range pairs {
+		key, raw, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --arg %q (expected key=value)", pair)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		args[key] = value
+	}
+	return args, nil
+}
+
 func newPromptsCmd(client *api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "prompts",
@@ -86,7 +573,7 @@ func newPromptsCmd(client *api.Client) *cobra.Command {
 				return nil
 			}
 
-			if tryJSON(cmd, servers) {
+			if tryOutput(cmd, servers) {
 				return nil
 			}
 
@@ -152,7 +639,7 @@ func newResourcesCmd(client *api.Client) *cobra.Command {
 				return nil
 			}
 
-			if tryJSON(cmd, servers) {
+			if tryOutput(cmd, servers) {
 				return nil
 			}
 
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 // Config holds Diane server configuration.
@@ -20,6 +21,32 @@ type Config struct {
 
 	// Slave configuration for connecting to a master server
 	Slave SlaveConfig `json:"slave"`
+
+	// Proxy holds defaults for proxied MCP servers
+	Proxy ProxyConfig `json:"proxy"`
+
+	// MCPHTTP configures the MCP HTTP/SSE endpoint network clients use
+	MCPHTTP MCPHTTPConfig `json:"mcp_http"`
+
+	// HTTPRequest configures the optional http_request builtin tool
+	HTTPRequest HTTPRequestConfig `json:"http_request"`
+
+	// ShellExec configures the optional shell_exec builtin tool
+	ShellExec ShellExecConfig `json:"shell_exec"`
+
+	// Downloads restricts what the downloads tools may fetch
+	Downloads DownloadsConfig `json:"downloads"`
+
+	// Jobs holds defaults for scheduled jobs
+	Jobs JobsConfig `json:"jobs"`
+
+	// ToolCache configures reuse of results from cacheable builtin tools
+	ToolCache ToolCacheConfig `json:"tool_cache"`
+
+	// NTPServer is the server "diane doctor" checks the clock against
+	// (default pool.ntp.org). Slaves check against their master instead.
+	// Env override: DIANE_NTP_SERVER
+	NTPServer string `json:"ntp_server,omitempty"`
 }
 
 // HTTPConfig holds settings for the optional TCP HTTP listener.
@@ -41,26 +68,176 @@ type SlaveConfig struct {
 
 	// MasterURL is the WebSocket URL of the master server (e.g., "wss://master:8766").
 	MasterURL string `json:"master_url"`
+
+	// ToolRouting chooses where a tool exposed by both this master and a
+	// connected slave runs: "local-first" (default), "slave-first" or "round-robin".
+	ToolRouting string `json:"tool_routing"`
+
+	// ReconnectGrace is how many seconds tool calls to a slave that has just
+	// disconnected are held waiting for it to reconnect. If 0, 5s is used;
+	// a negative value fails such calls immediately.
+	ReconnectGrace int `json:"reconnect_grace"`
+
+	// CertRenewDays is how many days before its client certificate expires
+	// a slave asks the master for a new one. If 0, 30 days is used.
+	CertRenewDays int `json:"cert_renew_days"`
+
+	// AutoApproveHosts lists hostname glob patterns (e.g. "lab-*") whose
+	// pairing requests the master approves without an operator. Hostnames are
+	// claimed by the slave, so only use this on trusted networks. Empty by default.
+	AutoApproveHosts []string `json:"auto_approve_hosts,omitempty"`
+
+	// AutoApproveToken is a pre-shared token; pairing requests sent with it
+	// ('diane slave pair --token') are approved automatically. Empty by default.
+	AutoApproveToken string `json:"auto_approve_token,omitempty"`
+}
+
+// ProxyConfig holds defaults applied to every proxied MCP server.
+type ProxyConfig struct {
+	// ToolTimeout is the default per-call tool timeout in seconds.
+	// Individual servers may override it. If 0, the proxy default (60s) is used.
+	// Env override: DIANE_TOOL_TIMEOUT
+	ToolTimeout int `json:"tool_timeout"`
+
+	// MaxResultBytes caps resource reads and tool results passed through the
+	// proxy. Oversized text tool results are truncated with a note; other
+	// oversized content is refused. If 0, the proxy default (10 MiB) is used.
+	// Env override: DIANE_MAX_RESULT_BYTES
+	MaxResultBytes int `json:"max_result_bytes"`
+
+	// Pool tunes the keep-alive connection pool each HTTP and SSE server gets
+	Pool PoolConfig `json:"pool"`
+}
+
+// PoolConfig holds connection pool settings for HTTP and SSE MCP servers.
+// Zero values use the proxy defaults.
+type PoolConfig struct {
+	// MaxIdleConnsPerHost is how many idle connections are kept open to each
+	// server. If 0, the default (10) is used.
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host"`
+
+	// MaxConnsPerHost caps the connections open to each server at once.
+	// If 0, there is no limit.
+	MaxConnsPerHost int `json:"max_conns_per_host"`
+
+	// IdleConnTimeout is how long, in seconds, an idle connection is kept
+	// before it is closed. If 0, the default (90s) is used.
+	IdleConnTimeout int `json:"idle_conn_timeout"`
 }
 
-// Load reads configuration from the config file, then applies
-// environment variable overrides. Config file locations checked in order:
-//  1. DIANE_CONFIG env var (if set)
-//  2. ~/.diane/config.json
-//
-// Missing file is not an error.
+// MCPHTTPConfig holds settings for the MCP HTTP/SSE endpoint.
+type MCPHTTPConfig struct {
+	// SSEHeartbeat is how often, in seconds, a comment frame is sent on each
+	// open SSE stream so proxies and load balancers don't drop it as idle.
+	// If 0, 15s is used; a negative value turns heartbeats off.
+	// Env override: DIANE_SSE_HEARTBEAT
+	SSEHeartbeat int `json:"sse_heartbeat"`
+	// AdminToken is the bearer token a client must send to connect with
+	// ?context=admin, which may manage contexts. Empty disables the admin
+	// context over HTTP.
+	// Env override: DIANE_ADMIN_TOKEN
+	AdminToken string `json:"admin_token,omitempty"`
+}
+
+// HTTPRequestConfig holds settings for the http_request builtin tool.
+// The tool is off unless Enabled is set and AllowedHosts is non-empty.
+type HTTPRequestConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// AllowedHosts lists the only hosts the tool may contact, e.g.
+	// "api.example.com", "*.example.com" or "localhost:8123".
+	AllowedHosts []string `json:"allowed_hosts"`
+
+	// MaxResponseBytes caps the response body returned. If 0, 64 KiB.
+	MaxResponseBytes int64 `json:"max_response_bytes"`
+
+	// Timeout is the per-request timeout in seconds. If 0, 30s.
+	Timeout int `json:"timeout"`
+}
+
+// ShellExecConfig holds settings for the shell_exec builtin tool.
+// The tool is off unless Enabled is set and AllowedCommands is non-empty.
+type ShellExecConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// AllowedCommands lists the exact binaries that may be run, by name
+	// ("git") or absolute path ("/usr/local/bin/backup.sh").
+	AllowedCommands []string `json:"allowed_commands"`
+
+	// AllowShellMetacharacters runs commands through sh so pipes and
+	// redirects work. Every command used must still be allowed.
+	AllowShellMetacharacters bool `json:"allow_shell_metacharacters"`
+
+	// Timeout is the maximum run time in seconds. If 0, 30s.
+	Timeout int `json:"timeout"`
+
+	// MaxOutputBytes caps captured stdout and stderr each. If 0, 64 KiB.
+	MaxOutputBytes int `json:"max_output_bytes"`
+}
+
+// DownloadsConfig restricts the downloads builtin tools. Every limit is
+// optional; with none set, any http(s) URL may be downloaded.
+type DownloadsConfig struct {
+	// MaxBytes caps a single download. A response whose Content-Length is
+	// larger is refused, and one that streams past it is aborted. If 0, no cap.
+	MaxBytes int64 `json:"max_bytes,omitempty"`
+
+	// AllowedContentTypes lists the media types that may be downloaded, e.g.
+	// "application/pdf" or "image/*". If empty, any type.
+	AllowedContentTypes []string `json:"allowed_content_types,omitempty"`
+
+	// AllowedHosts lists the hosts downloads may come from, in the same form
+	// as http_request.allowed_hosts; redirects must stay on the list. If
+	// empty, any host.
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+}
+
+// JobsConfig holds defaults for scheduled jobs.
+type JobsConfig struct {
+	// MaxOutputBytes caps the stdout and stderr stored for each execution;
+	// longer output is cut from the middle. Jobs may set their own limit.
+	// If 0, 256 KiB.
+	// Env override: DIANE_JOB_MAX_OUTPUT_BYTES
+	MaxOutputBytes int `json:"max_output_bytes"`
+}
+
+// ToolCacheConfig holds settings for the tool result cache. Builtin tools
+// that declare themselves cacheable (weather, places, finance reports) reuse
+// results of identical calls for a short time; callers can pass no_cache.
+type ToolCacheConfig struct {
+	// Disabled turns the cache off for all tools.
+	// Env override: DIANE_TOOL_CACHE=off
+	Disabled bool `json:"disabled"`
+}
+
+// ConfigEnv names the environment variable holding the config file path.
+// The daemon's --config flag sets it, so a restarted daemon keeps it.
+const ConfigEnv = "DIANE_CONFIG"
+
+// Path returns the config file location: DIANE_CONFIG if set, else
+// config.json in the active profile's directory. It is "" only when the home
+// directory is unknown.
+func Path() string {
+	if p := os.Getenv(ConfigEnv); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		slog.Warn("Failed to get home directory for config", "error", err)
+		return ""
+	}
+	return filepath.Join(home, DirName(), "config.json")
+}
+
+// Load reads configuration from the config file (see Path), then applies
+// environment variable overrides. Missing file is not an error.
 func Load() Config {
 	var cfg Config
 
-	configPath := os.Getenv("DIANE_CONFIG")
+	configPath := Path()
 	if configPath == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			slog.Warn("Failed to get home directory for config", "error", err)
-			applyEnvOverrides(&cfg)
-			return cfg
-		}
-		configPath = filepath.Join(home, ".diane", "config.json")
+		applyEnvOverrides(&cfg)
+		return cfg
 	}
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -81,11 +258,35 @@ func Load() Config {
 	return cfg
 }
 
-// applyEnvOverrides applies environment variable overrides to the config.
-// Env vars take precedence over config file values.
-func applyEnvOverrides(cfg *Config) {
+// EnvOverrides returns the environment variables currently overriding
+// config file values
+func EnvOverrides() []string {
+	var cfg Config
+	return applyEnvOverrides(&cfg)
+}
+
+// maskedSecret replaces secret values in Masked
+const maskedSecret = "********"
+
+// Masked returns a copy of c that is safe to display, with secrets replaced
+func (c Config) Masked() Config {
+	if c.HTTP.APIKey != "" {
+		c.HTTP.APIKey = maskedSecret
+	}
+	if c.Slave.AutoApproveToken != "" {
+		c.Slave.AutoApproveToken = maskedSecret
+	}
+	return c
+}
+
+// applyEnvOverrides applies environment variable overrides to the config and
+// returns the variables that took effect. Env vars take precedence over
+// config file values.
+func applyEnvOverrides(cfg *Config) []string {
+	var applied []string
 	if os.Getenv("DIANE_DEBUG") == "1" {
 		cfg.Debug = true
+		applied = append(applied, "DIANE_DEBUG")
 	}
 
 	// DIANE_HTTP_ADDR overrides port (for backward compatibility)
@@ -95,13 +296,71 @@ func applyEnvOverrides(cfg *Config) {
 		port := parsePort(addr)
 		if port > 0 {
 			cfg.HTTP.Port = port
+			applied = append(applied, "DIANE_HTTP_ADDR")
 		}
 	}
 
 	// DIANE_API_KEY overrides api_key
 	if key := os.Getenv("DIANE_API_KEY"); key != "" {
 		cfg.HTTP.APIKey = key
+		applied = append(applied, "DIANE_API_KEY")
+	}
+
+	// DIANE_TOOL_TIMEOUT overrides proxy.tool_timeout (seconds)
+	if v := os.Getenv("DIANE_TOOL_TIMEOUT"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			cfg.Proxy.ToolTimeout = secs
+			applied = append(applied, "DIANE_TOOL_TIMEOUT")
+		}
+	}
+
+	// DIANE_MAX_RESULT_BYTES overrides proxy.max_result_bytes
+	if v := os.Getenv("DIANE_MAX_RESULT_BYTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.Proxy.MaxResultBytes = n
+			applied = append(applied, "DIANE_MAX_RESULT_BYTES")
+		}
+	}
+
+	// DIANE_TOOL_CACHE=off (or 0/false) disables the tool result cache
+	switch os.Getenv("DIANE_TOOL_CACHE") {
+	case "off", "0", "false":
+		cfg.ToolCache.Disabled = true
+		applied = append(applied, "DIANE_TOOL_CACHE")
+	case "on", "1", "true":
+		cfg.ToolCache.Disabled = false
+		applied = append(applied, "DIANE_TOOL_CACHE")
+	}
+
+	// DIANE_NTP_SERVER overrides ntp_server
+	if v := os.Getenv("DIANE_NTP_SERVER"); v != "" {
+		cfg.NTPServer = v
+		applied = append(applied, "DIANE_NTP_SERVER")
+	}
+
+	// DIANE_SSE_HEARTBEAT overrides mcp_http.sse_heartbeat (seconds)
+	if v := os.Getenv("DIANE_SSE_HEARTBEAT"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			cfg.MCPHTTP.SSEHeartbeat = secs
+			applied = append(applied, "DIANE_SSE_HEARTBEAT")
+		}
+	}
+
+	// DIANE_ADMIN_TOKEN overrides mcp_http.admin_token
+	if v := os.Getenv("DIANE_ADMIN_TOKEN"); v != "" {
+		cfg.MCPHTTP.AdminToken = v
+		applied = append(applied, "DIANE_ADMIN_TOKEN")
 	}
+
+	// DIANE_JOB_MAX_OUTPUT_BYTES overrides jobs.max_output_bytes
+	if v := os.Getenv("DIANE_JOB_MAX_OUTPUT_BYTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.Jobs.MaxOutputBytes = n
+			applied = append(applied, "DIANE_JOB_MAX_OUTPUT_BYTES")
+		}
+	}
+
+	return applied
 }
 
 // parsePort extracts the port number from an address string like ":8080" or "0.0.0.0:8080".
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFromConfigEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.json")
+	if err := os.WriteFile(path, []byte(`{"http":{"port":8080,"api_key":"file-key"},"proxy":{"tool_timeout":5}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, path)
+	t.Setenv("DIANE_TOOL_TIMEOUT", "90")
+	for _, env := range []string{"DIANE_DEBUG", "DIANE_HTTP_ADDR", "DIANE_API_KEY", "DIANE_MAX_RESULT_BYTES", "DIANE_JOB_MAX_OUTPUT_BYTES", "DIANE_NTP_SERVER"} {
+		t.Setenv(env, "")
+	}
+
+	if Path() != path {
+		t.Errorf("Path() = %s, want %s", Path(), path)
+	}
+	cfg := Load()
+	if cfg.HTTP.Port != 8080 || cfg.Proxy.ToolTimeout != 90 {
+		t.Errorf("expected file values with env overrides applied, got %+v", cfg)
+	}
+	if got := strings.Join(EnvOverrides(), ","); got != "DIANE_TOOL_TIMEOUT" {
+		t.Errorf("EnvOverrides() = %s", got)
+	}
+
+	masked := cfg.Masked()
+	if masked.HTTP.APIKey == "file-key" || masked.HTTP.APIKey == "" {
+		t.Errorf("expected the API key to be masked, got %q", masked.HTTP.APIKey)
+	}
+	if cfg.HTTP.APIKey != "file-key" {
+		t.Error("Masked should not change the original config")
+	}
+	if (Config{}).Masked().HTTP.APIKey != "" {
+		t.Error("an unset API key should stay empty")
+	}
+	if got := (Config{Slave: SlaveConfig{AutoApproveToken: "s3cret"}}).Masked().Slave.AutoApproveToken; got != maskedSecret {
+		t.Errorf("expected the auto-approve token to be masked, got %q", got)
+	}
+}
+
+func TestNTPServerEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"ntp_server":"ntp.example.com"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, path)
+
+	t.Setenv("DIANE_NTP_SERVER", "")
+	if got := Load().NTPServer; got != "ntp.example.com" {
+		t.Errorf("NTPServer = %q, want the file's ntp.example.com", got)
+	}
+	t.Setenv("DIANE_NTP_SERVER", "time.example.org:1123")
+	if got := Load().NTPServer; got != "time.example.org:1123" {
+		t.Errorf("NTPServer = %q, want the env override", got)
+	}
+}
+
+func TestSSEHeartbeatEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"mcp_http":{"sse_heartbeat":20}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, path)
+
+	t.Setenv("DIANE_SSE_HEARTBEAT", "")
+	if got := Load().MCPHTTP.SSEHeartbeat; got != 20 {
+		t.Errorf("SSEHeartbeat = %d, want the file's 20", got)
+	}
+	t.Setenv("DIANE_SSE_HEARTBEAT", "-1")
+	if got := Load().MCPHTTP.SSEHeartbeat; got != -1 {
+		t.Errorf("SSEHeartbeat = %d, want the env override", got)
+	}
+}
+
+func TestAdminTokenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"mcp_http":{"admin_token":"from-file"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, path)
+
+	t.Setenv("DIANE_ADMIN_TOKEN", "")
+	if got := Load().MCPHTTP.AdminToken; got != "from-file" {
+		t.Errorf("AdminToken = %q, want the file's", got)
+	}
+	t.Setenv("DIANE_ADMIN_TOKEN", "from-env")
+	if got := Load().MCPHTTP.AdminToken; got != "from-env" {
+		t.Errorf("AdminToken = %q, want the env override", got)
+	}
+}
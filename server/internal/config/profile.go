@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// ProfileEnv names the environment variable selecting the active profile.
+// The global --profile flag sets it before anything else runs, so the daemon
+// and diane-ctl agree on it, and a restarted daemon keeps it.
+const ProfileEnv = "DIANE_PROFILE"
+
+// Ports of the default profile's MCP listeners. Other profiles add
+// PortOffset to both.
+const (
+	defaultMCPHTTPPort  = 8765 // MCP over HTTP
+	defaultMCPHTTPSPort = 8766 // MCP over HTTPS, used by slaves
+)
+
+var profileNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,31}$`)
+
+// Profile returns the active profile name, or "" for the default profile
+func Profile() string {
+	return os.Getenv(ProfileEnv)
+}
+
+// ValidateProfile checks a profile name is usable in a directory name:
+// letters, digits, '-' and '_', at most 32 characters
+func ValidateProfile(name string) error {
+	if name != "" && !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile %q (use letters, digits, '-' and '_', up to 32 characters)", name)
+	}
+	return nil
+}
+
+// DirName returns the name of the active profile's base directory under the
+// home directory: ".diane" for the default profile and ".diane-<profile>"
+// otherwise. Everything a daemon owns lives there — config, secrets,
+// database, socket, lock and PID files — so profiles never share state.
+func DirName() string {
+	if p := Profile(); p != "" {
+		return ".diane-" + p
+	}
+	return ".diane"
+}
+
+// PortOffset is added to the MCP HTTP and HTTPS ports so profiles' daemons
+// can run side by side. The default profile uses offset 0; a named profile
+// uses 10 * (1 + fnv32a(name) % 100), i.e. a multiple of 10 from 10 to 1000,
+// so its ports fall between 8775 and 9766. Two names can still collide; set
+// DIANE_PORT_OFFSET in one of them to pick another offset.
+func PortOffset() int {
+	if v := os.Getenv("DIANE_PORT_OFFSET"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	p := Profile()
+	if p == "" {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(p))
+	return 10 * int(1+h.Sum32()%100)
+}
+
+// MCPHTTPPort returns the active profile's MCP HTTP port (8765 by default)
+func MCPHTTPPort() int {
+	return defaultMCPHTTPPort + PortOffset()
+}
+
+// MCPHTTPSPort returns the active profile's MCP HTTPS port (8766 by default)
+func MCPHTTPSPort() int {
+	return defaultMCPHTTPSPort + PortOffset()
+}
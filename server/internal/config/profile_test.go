@@ -0,0 +1,44 @@
+package config
+
+import "testing"
+
+func TestProfileDirAndPorts(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	t.Setenv("DIANE_PORT_OFFSET", "")
+	if DirName() != ".diane" || MCPHTTPPort() != 8765 || MCPHTTPSPort() != 8766 {
+		t.Errorf("default profile: dir %s, ports %d/%d", DirName(), MCPHTTPPort(), MCPHTTPSPort())
+	}
+
+	t.Setenv(ProfileEnv, "work")
+	if DirName() != ".diane-work" {
+		t.Errorf("work profile dir = %s", DirName())
+	}
+	off := PortOffset()
+	if off < 10 || off > 1000 || off%10 != 0 {
+		t.Errorf("work profile offset %d outside 10..1000 in steps of 10", off)
+	}
+	if MCPHTTPPort() != 8765+off || MCPHTTPSPort() != 8766+off {
+		t.Errorf("work profile ports %d/%d for offset %d", MCPHTTPPort(), MCPHTTPSPort(), off)
+	}
+	if PortOffset() != off {
+		t.Error("offset should be stable for a profile name")
+	}
+
+	t.Setenv("DIANE_PORT_OFFSET", "40")
+	if MCPHTTPPort() != 8805 {
+		t.Errorf("DIANE_PORT_OFFSET should override the derived offset, got port %d", MCPHTTPPort())
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	for _, ok := range []string{"", "work", "home_2", "A-b"} {
+		if err := ValidateProfile(ok); err != nil {
+			t.Errorf("%q: unexpected error %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"../etc", "a b", "-x", "work/x", "waytoolongprofilenamethatexceedsthirtytwo"} {
+		if err := ValidateProfile(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
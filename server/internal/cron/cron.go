@@ -0,0 +1,259 @@
+// Package cron parses the cron expressions used for job schedules and
+// computes when they next fire.
+//
+// The accepted grammar is:
+//
+//	[second] minute hour day-of-month month day-of-week
+//
+// Five fields is standard cron and fires at second 0. A sixth field may only
+// be added at the front, for seconds (as in Go's robfig/cron); the Quartz
+// forms with a trailing year or "?" are rejected rather than guessed at.
+// The @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly
+// macros stand for their usual five-field expressions.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression. Each field is a bit set of the
+// values it matches.
+type Schedule struct {
+	second, minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record an unrestricted day field: when both day
+	// fields are restricted, a day matching either one fires (as in cron(8))
+	domStar, dowStar bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondField = field{name: "second", min: 0, max: 59}
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as Sunday and folded onto 0
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a five-field cron expression (minute, hour, day of month,
+// month, day of week), the same with a leading seconds field, or one of the
+// @hourly/@daily/@weekly/@monthly/@yearly macros. Fields accept *, values,
+// ranges (1-5), lists (1,3,5), steps (*/15, 0-30/10) and three-letter month
+// and weekday names.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expanded, ok := macros[strings.ToLower(expr)]; ok {
+		expr = expanded
+	} else if strings.HasPrefix(expr, "@") {
+		return nil, fmt.Errorf("unknown schedule macro %q (expected @yearly, @annually, @monthly, @weekly, @daily, @midnight or @hourly)", expr)
+	}
+
+	fields := strings.Fields(expr)
+	if strings.Contains(expr, "?") {
+		return nil, fmt.Errorf("\"?\" is not supported in %q; use * instead", expr)
+	}
+	s := &Schedule{second: 1}
+	var err error
+	switch len(fields) {
+	case 5:
+	case 6:
+		// A trailing four-digit number is a Quartz year, not a day of week
+		if last := fields[5]; len(last) == 4 && strings.Trim(last, "0123456789") == "" {
+			return nil, fmt.Errorf("6-field schedules take a leading seconds field, but %q ends in what looks like a year; years are not supported", expr)
+		}
+		if s.second, err = parseField(fields[0], secondField); err != nil {
+			return nil, err
+		}
+		fields = fields[1:]
+	case 7:
+		return nil, fmt.Errorf("7-field schedules (with seconds and year) are not supported in %q; drop the year", expr)
+	default:
+		return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week) or 6 with a leading seconds field, got %d in %q", len(fields), expr)
+	}
+
+	if s.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		b, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func parseRange(expr string, f field) (uint64, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(expr, "/")
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepExpr)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepExpr, f.name)
+		}
+		step = n
+	}
+
+	var lo, hi int
+	switch {
+	case rangeExpr == "*":
+		lo, hi = f.min, f.max
+	case strings.Contains(rangeExpr, "-"):
+		loExpr, hiExpr, _ := strings.Cut(rangeExpr, "-")
+		var err error
+		if lo, err = parseValue(loExpr, f); err != nil {
+			return 0, err
+		}
+		if hi, err = parseValue(hiExpr, f); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q in %s field: start is after end", rangeExpr, f.name)
+		}
+	default:
+		v, err := parseValue(rangeExpr, f)
+		if err != nil {
+			return 0, err
+		}
+		lo, hi = v, v
+		// "5/15" means every 15 starting at 5
+		if hasStep {
+			hi = f.max
+		}
+	}
+
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func parseValue(expr string, f field) (int, error) {
+	if v, ok := f.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", expr, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// HasSeconds reports whether the schedule fires at any second other than
+// the start of a minute
+func (s *Schedule) HasSeconds() bool {
+	return s.second != 1
+}
+
+// Next returns the first time after t that the schedule fires, in t's
+// location, or the zero time if it never does (e.g. "0 0 30 2 *").
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Second).Add(time.Second)
+
+	// Five years covers every satisfiable combination of day and month,
+	// including February 29th
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if s.second&(1<<uint(t.Second())) == 0 {
+			if s.second>>uint(t.Second()) == 0 {
+				t = t.Truncate(time.Minute).Add(time.Minute)
+			} else {
+				t = t.Add(time.Second)
+			}
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// NextN returns up to n times after t that the schedule fires, fewer if it
+// stops firing
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	var times []time.Time
+	for len(times) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
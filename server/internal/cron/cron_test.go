@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"* * * *":             "expected 5 fields",
+		"60 * * * *":          "minute 60 out of range 0-59",
+		"* 24 * * *":          "hour 24 out of range 0-23",
+		"* * 0 * *":           "day of month 0 out of range 1-31",
+		"* * * 13 *":          "month 13 out of range 1-12",
+		"*/0 * * * *":         "invalid step",
+		"5-1 * * * *":         "start is after end",
+		"* * * foo *":         `invalid value "foo" in month field`,
+		"@fortnightly":        "unknown schedule macro",
+		"1,,2 * * * *":        "invalid value",
+		"60 * * * * *":        "second 60 out of range 0-59",
+		"0 0 12 * * 2025":     "looks like a year",
+		"0 0 12 * * ? 2025":   "\"?\" is not supported",
+		"0 0 12 * * mon 2025": "7-field schedules",
+		"* * * * * * * *":     "got 8",
+		"@reboot":             "unknown schedule macro",
+	}
+	for expr, want := range tests {
+		_, err := Parse(expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want it to contain %q", expr, err, want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2024, 1, 31, 10, 7, 30, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 31, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 31, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{"30 8 * * mon-fri", time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 * 5", time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)}, // 1st or Friday, whichever first
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"*/10 * * * * *", time.Date(2024, 1, 31, 10, 7, 40, 0, time.UTC)},
+		{"15 * * * * *", time.Date(2024, 1, 31, 10, 8, 15, 0, time.UTC)},
+		{"0 0 9 * * *", time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("February 30th should never fire, got %v", got)
+	}
+}
+
+func TestHasSeconds(t *testing.T) {
+	for expr, want := range map[string]bool{
+		"* * * * *":      false,
+		"@daily":         false,
+		"0 * * * * *":    false,
+		"*/30 * * * * *": true,
+	} {
+		s, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", expr, err)
+		}
+		if got := s.HasSeconds(); got != want {
+			t.Errorf("HasSeconds(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CheckDependencies validates the jobs a set of jobs depend on. parents maps
+// each job's name to the name of the job it runs after, or "" when it
+// doesn't depend on one. Every parent must be in the set, and following
+// parents must never lead back to where it started.
+func CheckDependencies(parents map[string]string) error {
+	names := make([]string, 0, len(parents))
+	for name := range parents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	acyclic := make(map[string]bool, len(parents))
+	for _, name := range names {
+		var path []string
+		onPath := make(map[string]bool)
+		for cur := name; cur != "" && !acyclic[cur]; cur = parents[cur] {
+			if onPath[cur] {
+				start := 0
+				for path[start] != cur {
+					start++
+				}
+				return fmt.Errorf("dependency cycle: %s -> %s", strings.Join(path[start:], " -> "), cur)
+			}
+			parent, ok := parents[cur]
+			if !ok {
+				return fmt.Errorf("job %q depends on unknown job %q", path[len(path)-1], cur)
+			}
+			onPath[cur] = true
+			path = append(path, cur)
+			if parent == "" {
+				break
+			}
+		}
+		for _, n := range path {
+			acyclic[n] = true
+		}
+	}
+	return nil
+}
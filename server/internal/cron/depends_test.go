@@ -0,0 +1,26 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	chain := map[string]string{"backup": "", "upload": "backup", "notify": "upload"}
+	if err := CheckDependencies(chain); err != nil {
+		t.Errorf("a chain should be accepted, got %v", err)
+	}
+
+	err := CheckDependencies(map[string]string{"a": "c", "b": "a", "c": "b", "d": "a"})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle: a -> c -> b -> a") {
+		t.Errorf("expected the cycle to be reported, got %v", err)
+	}
+	if err := CheckDependencies(map[string]string{"a": "a"}); err == nil {
+		t.Error("expected a job depending on itself to be rejected")
+	}
+
+	err = CheckDependencies(map[string]string{"upload": "backup"})
+	if err == nil || !strings.Contains(err.Error(), `"upload" depends on unknown job "backup"`) {
+		t.Errorf("expected an unknown parent to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package cron
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NaturalPrefix marks a schedule written in plain English, e.g.
+// "@natural every weekday at 9am", to be resolved with ParseNatural
+const NaturalPrefix = "@natural"
+
+// IsNatural reports whether schedule uses NaturalPrefix
+func IsNatural(schedule string) bool {
+	fields := strings.Fields(schedule)
+	return len(fields) > 0 && strings.EqualFold(fields[0], NaturalPrefix)
+}
+
+var weekdayNumbers = map[string]int{
+	"sunday": 0, "monday": 1, "tuesday": 2, "wednesday": 3,
+	"thursday": 4, "friday": 5, "saturday": 6,
+}
+
+var (
+	everyIntervalRe = regexp.MustCompile(`^every (\d+) (minute|hour)s?$`)
+	monthDayRe      = regexp.MustCompile(`^(?:on )?the (\d{1,2})(?:st|nd|rd|th)? of (?:every|each) month$|^(?:every|each) month on the (\d{1,2})(?:st|nd|rd|th)?$`)
+	timeRe          = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))? ?(am|pm)?$`)
+)
+
+// ParseNatural resolves a plain-English schedule into a five-field cron
+// expression. The NaturalPrefix is optional. It understands phrases like:
+//
+//	every minute, every 15 minutes, every hour, every 2 hours
+//	hourly, daily, weekly, monthly
+//	every day at 9am, every weekday at 9:30am, every weekend at noon
+//	every monday and thursday at 18:00, tuesdays at 7pm
+//	on the 1st of every month at midnight, every month on the 15th
+//
+// Times default to midnight when left out.
+func ParseNatural(phrase string) (string, error) {
+	text := strings.ToLower(strings.Join(strings.Fields(phrase), " "))
+	if IsNatural(text) {
+		text = strings.TrimSpace(text[len(NaturalPrefix):])
+	}
+	if text == "" {
+		return "", fmt.Errorf("empty schedule phrase")
+	}
+
+	switch text {
+	case "every minute":
+		return "* * * * *", nil
+	case "every hour", "hourly":
+		return "0 * * * *", nil
+	case "daily":
+		return "0 0 * * *", nil
+	case "weekly":
+		return "0 0 * * 0", nil
+	case "monthly":
+		return "0 0 1 * *", nil
+	}
+
+	if m := everyIntervalRe.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "minute" {
+			if n < 1 || n > 59 {
+				return "", fmt.Errorf("every %d minutes: interval must be 1-59", n)
+			}
+			return fmt.Sprintf("*/%d * * * *", n), nil
+		}
+		if n < 1 || n > 23 {
+			return "", fmt.Errorf("every %d hours: interval must be 1-23", n)
+		}
+		return fmt.Sprintf("0 */%d * * *", n), nil
+	}
+
+	// Split off "at <time>"
+	minute, hour := 0, 0
+	if day, at, ok := strings.Cut(text, " at "); ok {
+		var err error
+		if minute, hour, err = parseTimeOfDay(at); err != nil {
+			return "", err
+		}
+		text = day
+	}
+
+	if m := monthDayRe.FindStringSubmatch(text); m != nil {
+		day := m[1]
+		if day == "" {
+			day = m[2]
+		}
+		n, _ := strconv.Atoi(day)
+		if n < 1 || n > 31 {
+			return "", fmt.Errorf("day of month %d out of range 1-31", n)
+		}
+		return fmt.Sprintf("%d %d %d * *", minute, hour, n), nil
+	}
+
+	dow, err := parseDays(text)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d %d * * %s", minute, hour, dow), nil
+}
+
+// parseDays turns "every day", "weekdays", "every monday and friday" and the
+// like into a day-of-week field
+func parseDays(text string) (string, error) {
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "every "), "on ")
+	switch text {
+	case "day", "daily":
+		return "*", nil
+	case "weekday", "weekdays":
+		return "1-5", nil
+	case "weekend", "weekends", "weekend day", "weekend days":
+		return "0,6", nil
+	}
+
+	text = strings.ReplaceAll(text, " and ", ",")
+	var days []string
+	seen := make(map[int]bool)
+	for _, name := range strings.Split(text, ",") {
+		name = strings.TrimSuffix(strings.TrimSpace(name), "s")
+		n, ok := weekdayNumbers[name]
+		if !ok {
+			return "", fmt.Errorf("don't understand %q in schedule (try e.g. \"every weekday at 9am\")", name)
+		}
+		if !seen[n] {
+			seen[n] = true
+			days = append(days, strconv.Itoa(n))
+		}
+	}
+	return strings.Join(days, ","), nil
+}
+
+// parseTimeOfDay parses "9am", "9:30 pm", "21:00", "noon" or "midnight"
+func parseTimeOfDay(text string) (minute, hour int, err error) {
+	text = strings.TrimSpace(text)
+	switch text {
+	case "noon", "midday":
+		return 0, 12, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	m := timeRe.FindStringSubmatch(text)
+	if m == nil {
+		return 0, 0, fmt.Errorf("don't understand time %q (try e.g. 9am, 9:30pm or 21:00)", text)
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("invalid time %q", text)
+		}
+		if hour == 12 {
+			hour = 0
+		}
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, fmt.Errorf("invalid time %q", text)
+		}
+	}
+	if minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time %q", text)
+	}
+	return minute, hour, nil
+}
@@ -0,0 +1,47 @@
+package cron
+
+import "testing"
+
+func TestParseNatural(t *testing.T) {
+	tests := map[string]string{
+		"@natural every weekday at 9am":      "0 9 * * 1-5",
+		"every 15 minutes":                   "*/15 * * * *",
+		"every 2 hours":                      "0 */2 * * *",
+		"hourly":                             "0 * * * *",
+		"every day at 9:30 pm":               "30 21 * * *",
+		"every weekend at noon":              "0 12 * * 0,6",
+		"every Monday and Thursday at 18:00": "0 18 * * 1,4",
+		"tuesdays, fridays at 12am":          "0 0 * * 2,5",
+		"on the 1st of every month at 8am":   "0 8 1 * *",
+		"every month on the 15th":            "0 0 15 * *",
+		"@NATURAL   every   sunday":          "0 0 * * 0",
+	}
+	for phrase, want := range tests {
+		got, err := ParseNatural(phrase)
+		if err != nil {
+			t.Errorf("ParseNatural(%q): %v", phrase, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseNatural(%q) = %q, want %q", phrase, got, want)
+		}
+		if _, err := Parse(got); err != nil {
+			t.Errorf("ParseNatural(%q) resolved to unparseable %q: %v", phrase, got, err)
+		}
+	}
+}
+
+func TestParseNaturalInvalid(t *testing.T) {
+	for _, phrase := range []string{
+		"@natural",
+		"every blue moon",
+		"every day at 25:00",
+		"every day at 13pm",
+		"every 90 minutes",
+		"on the 32nd of every month",
+	} {
+		if got, err := ParseNatural(phrase); err == nil {
+			t.Errorf("ParseNatural(%q) = %q, want an error", phrase, got)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Output formats say how a job's stdout is read once it succeeds
+const (
+	OutputText = "text" // kept as is
+	OutputJSON = "json" // parsed and stored as structured data
+)
+
+// ParseOutputFormat validates an output format, returning "" for text so
+// plain jobs keep the zero value
+func ParseOutputFormat(format string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "", OutputText:
+		return "", nil
+	case OutputJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid output format %q (expected text or json)", format)
+	}
+}
+
+// ParseOutput reads a successful run's stdout according to format. JSON
+// output is decoded into a value; text output gives nil.
+func ParseOutput(format, stdout string) (any, error) {
+	if format != OutputJSON {
+		return nil, nil
+	}
+	var v any
+	if err := json.Unmarshal([]byte(stdout), &v); err != nil {
+		return nil, fmt.Errorf("invalid JSON output: %w", err)
+	}
+	return v, nil
+}
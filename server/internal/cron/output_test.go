@@ -0,0 +1,34 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	for in, want := range map[string]string{"": "", "text": "", " JSON ": OutputJSON} {
+		if got, err := ParseOutputFormat(in); err != nil || got != want {
+			t.Errorf("ParseOutputFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseOutputFormat("yaml"); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	v, err := ParseOutput(OutputJSON, "{\"files\": 3, \"ok\": true}\n")
+	if err != nil {
+		t.Fatalf("ParseOutput: %v", err)
+	}
+	if m, ok := v.(map[string]any); !ok || m["files"] != float64(3) || m["ok"] != true {
+		t.Errorf("unexpected value %#v", v)
+	}
+
+	if _, err := ParseOutput(OutputJSON, "done: 3 files"); err == nil || !strings.Contains(err.Error(), "invalid JSON output") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+	if v, err := ParseOutput("", "not json"); v != nil || err != nil {
+		t.Errorf("text output should not be parsed, got %v, %v", v, err)
+	}
+}
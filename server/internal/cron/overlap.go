@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Concurrency policies decide what happens when a job fires while its
+// previous run is still going
+const (
+	ConcurrencySkip  = "skip"  // don't start; record the fire as skipped
+	ConcurrencyQueue = "queue" // start once the previous run finishes
+	ConcurrencyAllow = "allow" // start anyway, overlapping the previous run
+)
+
+// DefaultConcurrency is the policy for jobs that don't set one
+const DefaultConcurrency = ConcurrencySkip
+
+// ParseConcurrency validates a concurrency policy, returning
+// DefaultConcurrency for an empty one
+func ParseConcurrency(policy string) (string, error) {
+	switch p := strings.ToLower(strings.TrimSpace(policy)); p {
+	case "":
+		return DefaultConcurrency, nil
+	case ConcurrencySkip, ConcurrencyQueue, ConcurrencyAllow:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid concurrency policy %q (expected skip, queue or allow)", policy)
+	}
+}
+
+// ErrStillRunning is returned by Limiter.Acquire under the skip policy when
+// the job's previous run hasn't finished
+var ErrStillRunning = errors.New("previous run still in progress")
+
+// Limiter applies jobs' concurrency policies. Whatever starts a run calls
+// Acquire first and the returned release once the run finishes.
+type Limiter struct {
+	mu    sync.Mutex
+	slots map[int64]chan struct{} // one-run slot per job, for skip and queue
+}
+
+// NewLimiter creates a Limiter with no runs in progress
+func NewLimiter() *Limiter {
+	return &Limiter{slots: make(map[int64]chan struct{})}
+}
+
+func (l *Limiter) slot(jobID int64) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.slots[jobID]
+	if !ok {
+		s = make(chan struct{}, 1)
+		l.slots[jobID] = s
+	}
+	return s
+}
+
+// Acquire claims a run of jobID under policy. Under skip it fails with
+// ErrStillRunning while a previous run holds the job; under queue it waits
+// for that run to finish or ctx to end; under allow it never waits. An
+// empty policy means DefaultConcurrency.
+func (l *Limiter) Acquire(ctx context.Context, jobID int64, policy string) (release func(), err error) {
+	policy, err = ParseConcurrency(policy)
+	if err != nil {
+		return nil, err
+	}
+	if policy == ConcurrencyAllow {
+		return func() {}, nil
+	}
+
+	s := l.slot(jobID)
+	if policy == ConcurrencySkip {
+		select {
+		case s <- struct{}{}:
+		default:
+			return nil, ErrStillRunning
+		}
+	} else {
+		select {
+		case s <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { <-s }) }, nil
+}
@@ -0,0 +1,87 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseConcurrency(t *testing.T) {
+	if p, err := ParseConcurrency(""); err != nil || p != ConcurrencySkip {
+		t.Errorf("empty policy = %q, %v; want skip", p, err)
+	}
+	if p, err := ParseConcurrency(" Queue "); err != nil || p != ConcurrencyQueue {
+		t.Errorf("policy = %q, %v; want queue", p, err)
+	}
+	if _, err := ParseConcurrency("sometimes"); err == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+}
+
+func TestLimiterSkip(t *testing.T) {
+	l := NewLimiter()
+	release, err := l.Acquire(context.Background(), 1, ConcurrencySkip)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if _, err := l.Acquire(context.Background(), 1, ConcurrencySkip); !errors.Is(err, ErrStillRunning) {
+		t.Errorf("overlapping run: got %v, want ErrStillRunning", err)
+	}
+	if r, err := l.Acquire(context.Background(), 2, ConcurrencySkip); err != nil {
+		t.Errorf("other jobs should not be blocked: %v", err)
+	} else {
+		r()
+	}
+
+	release()
+	release() // releasing twice is harmless
+	if r, err := l.Acquire(context.Background(), 1, ConcurrencySkip); err != nil {
+		t.Errorf("run after release: %v", err)
+	} else {
+		r()
+	}
+}
+
+func TestLimiterQueue(t *testing.T) {
+	l := NewLimiter()
+	release, _ := l.Acquire(context.Background(), 1, ConcurrencyQueue)
+
+	started := make(chan struct{})
+	go func() {
+		r, err := l.Acquire(context.Background(), 1, ConcurrencyQueue)
+		if err == nil {
+			r()
+		}
+		close(started)
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("queued run started before the previous one finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("queued run did not start after the previous one finished")
+	}
+
+	release, _ = l.Acquire(context.Background(), 1, ConcurrencyQueue)
+	defer release()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Acquire(ctx, 1, ConcurrencyQueue); !errors.Is(err, context.Canceled) {
+		t.Errorf("queued run with a cancelled context: got %v", err)
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	l := NewLimiter()
+	for i := 0; i < 3; i++ {
+		if _, err := l.Acquire(context.Background(), 1, ConcurrencyAllow); err != nil {
+			t.Fatalf("allow should never block: %v", err)
+		}
+	}
+}
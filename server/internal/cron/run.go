@@ -0,0 +1,140 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RunTimeout bounds a shell job started on demand rather than by the
+// schedule, such as a retry
+const RunTimeout = 10 * time.Minute
+
+// killGrace is how long RunShell waits for output pipes to close after
+// killing a command, in case something outside its process group holds them
+const killGrace = 5 * time.Second
+
+// RunOptions controls how RunShell runs a command
+type RunOptions struct {
+	// Dir is the working directory; empty means the current directory
+	Dir string
+	// Env is layered over the daemon's environment
+	Env map[string]string
+	// Timeout caps the run; zero means no limit beyond ctx
+	Timeout time.Duration
+}
+
+// RunShell runs command with sh -c until it exits, ctx ends or opts.Timeout
+// passes. The command gets its own process group, and the whole group is
+// killed when the run is cut short, so children a shell spawned don't
+// outlive it. A command that runs and exits non-zero is reported through
+// exitCode with a nil error; err is set when it couldn't be started or was
+// cut short, in which case exitCode is -1.
+func RunShell(ctx context.Context, command string, opts RunOptions) (exitCode int, stdout, stderr string, err error) {
+	runCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	cmd.Dir = opts.Dir
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = killGrace
+	if len(opts.Env) > 0 {
+		// Later entries win, so these override inherited variables
+		cmd.Env = os.Environ()
+		for _, k := range sortedKeys(opts.Env) {
+			cmd.Env = append(cmd.Env, k+"="+opts.Env[k])
+		}
+	}
+
+	err = cmd.Run()
+	if ctxErr := runCtx.Err(); ctxErr != nil {
+		switch {
+		case ctx.Err() == nil:
+			ctxErr = fmt.Errorf("timed out after %s", formatTimeout(opts.Timeout))
+		case errors.Is(ctxErr, context.DeadlineExceeded):
+			ctxErr = fmt.Errorf("timed out")
+		}
+		return -1, outBuf.String(), errBuf.String(), ctxErr
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), outBuf.String(), errBuf.String(), nil
+	}
+	if err != nil {
+		return -1, outBuf.String(), errBuf.String(), err
+	}
+	return 0, outBuf.String(), errBuf.String(), nil
+}
+
+// ValidateRunEnvironment checks a job's environment variables and working
+// directory. Variable names must be non-empty and free of '=' and NUL, and
+// the working directory, when set, must be an absolute path since the daemon's
+// own directory is arbitrary.
+func ValidateRunEnvironment(env map[string]string, workdir string) error {
+	for _, k := range sortedKeys(env) {
+		if k == "" || strings.ContainsAny(k, "=\x00") {
+			return fmt.Errorf("invalid environment variable name %q", k)
+		}
+		if strings.ContainsRune(env[k], 0) {
+			return fmt.Errorf("environment variable %s contains a NUL byte", k)
+		}
+	}
+	if workdir != "" && !filepath.IsAbs(workdir) {
+		return fmt.Errorf("workdir must be an absolute path, got %q", workdir)
+	}
+	return nil
+}
+
+// EffectiveWorkdir is the directory a job with the given workdir runs in:
+// workdir itself, or the user's home directory when it's empty
+func EffectiveWorkdir(workdir string) string {
+	if workdir != "" {
+		return workdir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return home
+	}
+	return ""
+}
+
+// FormatEnv renders env as sorted KEY=value pairs separated by spaces
+func FormatEnv(env map[string]string) string {
+	pairs := make([]string, 0, len(env))
+	for _, k := range sortedKeys(env) {
+		pairs = append(pairs, k+"="+env[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// formatTimeout renders d as whole seconds, e.g. "90s", which is how job
+// timeouts are configured
+func formatTimeout(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64(d.Round(time.Second)/time.Second))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
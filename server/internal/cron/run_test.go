@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunShell(t *testing.T) {
+	code, stdout, stderr, err := RunShell(context.Background(), "echo out; echo err >&2; exit 3", RunOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != 3 || strings.TrimSpace(stdout) != "out" || strings.TrimSpace(stderr) != "err" {
+		t.Errorf("got code %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if code, _, _, err := RunShell(ctx, "sleep 5", RunOptions{}); err == nil || code != -1 {
+		t.Errorf("timed out run = %d, %v; want -1 and an error", code, err)
+	}
+}
+
+func TestRunShellEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DIANE_TEST_INHERITED", "kept")
+	t.Setenv("DIANE_TEST_OVERRIDDEN", "old")
+	_, stdout, _, err := RunShell(context.Background(),
+		`echo "$PWD $DIANE_TEST_INHERITED $DIANE_TEST_OVERRIDDEN"`,
+		RunOptions{Dir: dir, Env: map[string]string{"DIANE_TEST_OVERRIDDEN": "new"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := dir + " kept new"; strings.TrimSpace(stdout) != want {
+		t.Errorf("got %q, want %q", strings.TrimSpace(stdout), want)
+	}
+}
+
+func TestRunShellTimeoutKillsProcessGroup(t *testing.T) {
+	// sh forks sleep, which holds the output pipes open; if only sh were
+	// killed the run would hang until killGrace
+	start := time.Now()
+	code, _, _, err := RunShell(context.Background(), "sleep 30; echo done", RunOptions{Timeout: time.Second})
+	if elapsed := time.Since(start); elapsed > killGrace/2 {
+		t.Errorf("run took %s; the child sleep survived the kill", elapsed)
+	}
+	if code != -1 || err == nil || err.Error() != "timed out after 1s" {
+		t.Errorf("got %d, %v; want -1 and \"timed out after 1s\"", code, err)
+	}
+}
+
+func TestValidateRunEnvironment(t *testing.T) {
+	if err := ValidateRunEnvironment(map[string]string{"PATH": "/bin"}, "/srv/app"); err != nil {
+		t.Errorf("valid environment rejected: %v", err)
+	}
+	if err := ValidateRunEnvironment(nil, ""); err != nil {
+		t.Errorf("empty environment rejected: %v", err)
+	}
+	for _, tc := range []struct {
+		env     map[string]string
+		workdir string
+	}{
+		{map[string]string{"": "x"}, ""},
+		{map[string]string{"A=B": "x"}, ""},
+		{nil, "relative/dir"},
+	} {
+		if err := ValidateRunEnvironment(tc.env, tc.workdir); err == nil {
+			t.Errorf("ValidateRunEnvironment(%v, %q) = nil, want an error", tc.env, tc.workdir)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	_ "modernc.org/sqlite"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 // DB represents the database connection
@@ -19,15 +21,51 @@ type DB struct {
 // Job represents a scheduled job in the database
 // TODO(emergent-migration): Move to Emergent graph object type "job". See db/jobs.go for plan.
 type Job struct {
-	ID         int64
-	Name       string
-	Command    string
-	Schedule   string
-	Enabled    bool
-	ActionType string  // "shell" (default) or "agent"
-	AgentName  *string // Agent name for agent actions
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID       int64
+	Name     string
+	Command  string
+	Schedule string
+	// ScheduleText is the natural-language phrase Schedule was resolved
+	// from, e.g. "every weekday at 9am", or empty for a plain cron schedule
+	ScheduleText string
+	Enabled      bool
+	ActionType   string  // "shell" (default) or "agent"
+	AgentName    *string // Agent name for agent actions
+	// MaxOutputBytes caps the stdout and stderr stored per execution,
+	// overriding the global limit. 0 uses the global limit.
+	MaxOutputBytes int
+	// Concurrency is what happens when the job fires while its previous
+	// run is still going: "skip" (default), "queue" or "allow"
+	Concurrency string
+	// Env holds variables set for the command on top of the daemon's
+	// environment, and Workdir the directory it runs in (empty = the
+	// user's home directory)
+	Env     map[string]string
+	Workdir string
+	// TimeoutSeconds caps each run, after which the command's whole
+	// process group is killed and the run fails (0 = no limit)
+	TimeoutSeconds int
+	// DependsOn names the job this one runs after: each successful run of
+	// that job fires this one, and a failed run skips it. Empty when the
+	// job only runs on its Schedule, which may then be empty too.
+	DependsOn string
+	// OutputFormat is "json" when a successful run's stdout is parsed and
+	// stored as structured data on the execution, empty for plain text
+	OutputFormat string
+	// NotifyTemplate names the notify template sent when a run fails,
+	// empty for no failure notification
+	NotifyTemplate string
+	// Paused stops the scheduler firing the job without disabling it,
+	// until PausedUntil passes or, when PausedUntil is nil, until resumed
+	Paused      bool
+	PausedUntil *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsPaused reports whether the job's pause is still in effect at now
+func (j *Job) IsPaused(now time.Time) bool {
+	return j.Paused && (j.PausedUntil == nil || now.Before(*j.PausedUntil))
 }
 
 // JobExecution represents a job execution log entry
@@ -41,8 +79,23 @@ type JobExecution struct {
 	Stdout    string
 	Stderr    string
 	Error     *string
+	// StdoutTruncated and StderrTruncated count the bytes dropped from the
+	// middle of each stream to fit the job's output limit
+	StdoutTruncated int
+	StderrTruncated int
+	// Status is ExecutionStatusSkipped for a fire that didn't run because
+	// the previous run was still going, and empty otherwise
+	Status string
+	// RetryOf is the ID of the execution this one retries, if any
+	RetryOf *int64
+	// Output is the parsed stdout of a run of a job with output format
+	// json, nil otherwise
+	Output any
 }
 
+// ExecutionStatusSkipped marks an execution recorded for a skipped fire
+const ExecutionStatusSkipped = "skipped"
+
 // New creates a new database connection
 // If path is empty, uses ~/.diane/cron.db
 func New(path string) (*DB, error) {
@@ -51,7 +104,7 @@ func New(path string) (*DB, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to get home directory: %w", err)
 		}
-		dianeDir := filepath.Join(home, ".diane")
+		dianeDir := filepath.Join(home, config.DirName())
 		if err := os.MkdirAll(dianeDir, 0755); err != nil {
 			return nil, fmt.Errorf("failed to create .diane directory: %w", err)
 		}
@@ -323,6 +376,12 @@ func (db *DB) migrate() error {
 	// Create index for efficient node-based queries
 	db.conn.Exec(`CREATE INDEX IF NOT EXISTS idx_mcp_servers_node ON mcp_servers(node_id, node_mode)`)
 
+	// Migration: Track streaming agent runs in usage, so a run's partial
+	// usage can be updated in place and finalized when it ends
+	db.conn.Exec(`ALTER TABLE usage ADD COLUMN run_id TEXT`)
+	db.conn.Exec(`ALTER TABLE usage ADD COLUMN partial INTEGER NOT NULL DEFAULT 0`)
+	db.conn.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_usage_run_id ON usage(run_id)`)
+
 	// Migration: Create mcp_server_placements table for host-based MCP deployment
 	db.conn.Exec(`
 		CREATE TABLE IF NOT EXISTS mcp_server_placements (
@@ -19,20 +19,24 @@ type OAuthConfig struct {
 
 // MCPServer represents an MCP server in the database
 type MCPServer struct {
-	ID        int64             `json:"id"`
-	Name      string            `json:"name"`
-	Enabled   bool              `json:"enabled"`
-	Type      string            `json:"type"` // stdio, sse, http, builtin
-	Command   string            `json:"command,omitempty"`
-	Args      []string          `json:"args,omitempty"`
-	Env       map[string]string `json:"env,omitempty"`
-	URL       string            `json:"url,omitempty"`
-	Headers   map[string]string `json:"headers,omitempty"`
-	OAuth     *OAuthConfig      `json:"oauth,omitempty"`
-	NodeID    string            `json:"node_id,omitempty"`   // Target slave hostname
-	NodeMode  string            `json:"node_mode,omitempty"` // "master", "specific", "any"
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	ID             int64             `json:"id"`
+	Name           string            `json:"name"`
+	Enabled        bool              `json:"enabled"`
+	Type           string            `json:"type"` // stdio, sse, http, builtin
+	Command        string            `json:"command,omitempty"`
+	Args           []string          `json:"args,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	OAuth          *OAuthConfig      `json:"oauth,omitempty"`
+	NodeID         string            `json:"node_id,omitempty"`         // Target slave hostname
+	NodeMode       string            `json:"node_mode,omitempty"`       // "master", "specific", "any"
+	ToolTimeout    int               `json:"tool_timeout,omitempty"`    // Seconds; 0 = proxy default
+	MaxConcurrency int               `json:"max_concurrency,omitempty"` // Concurrent tool calls; 0 = unlimited
+	StartupTimeout int               `json:"startup_timeout,omitempty"` // Seconds to wait for a stdio server to initialize; 0 = proxy default
+	Priority       int               `json:"priority,omitempty"`        // Tool listing order; higher first, 0 = default
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
 }
 
 // ListMCPServers returns all MCP servers
@@ -316,6 +320,8 @@ func (db *DB) EnsureBuiltinServers() error {
 		{Name: "github-bot", Type: "builtin"},
 		{Name: "downloads", Type: "builtin"},
 		{Name: "file_registry", Type: "builtin"},
+		{Name: "http_request", Type: "builtin"},
+		{Name: "shell_exec", Type: "builtin"},
 	}
 
 	for _, builtin := range builtins {
@@ -541,6 +541,56 @@ func (p *Provider) GetConfigBool(key string) bool {
 	return false
 }
 
+// DefaultModel returns the model Diane uses for calls through the provider
+// that don't name one: the "model" config setting
+func (p *Provider) DefaultModel() string {
+	return p.GetConfigString("model")
+}
+
+// Params returns the provider's default call parameters, such as
+// temperature or max_tokens, from the "params" config setting. The map is
+// a copy.
+func (p *Provider) Params() map[string]any {
+	raw, _ := p.Config["params"].(map[string]any)
+	params := make(map[string]any, len(raw))
+	for k, v := range raw {
+		params[k] = v
+	}
+	return params
+}
+
+// SetDefaults updates the default model and merges params into the default
+// call parameters. A nil model is left alone and an empty one cleared; a
+// param set to nil is removed.
+func (p *Provider) SetDefaults(model *string, params map[string]any) {
+	if p.Config == nil {
+		p.Config = make(map[string]any)
+	}
+	if model != nil {
+		if *model == "" {
+			delete(p.Config, "model")
+		} else {
+			p.Config["model"] = *model
+		}
+	}
+	if params == nil {
+		return
+	}
+	merged := p.Params()
+	for k, v := range params {
+		if v == nil {
+			delete(merged, k)
+		} else {
+			merged[k] = v
+		}
+	}
+	if len(merged) == 0 {
+		delete(p.Config, "params")
+	} else {
+		p.Config["params"] = merged
+	}
+}
+
 // GetAuthString returns a string value from auth config
 func (p *Provider) GetAuthString(key string) string {
 	if p.AuthConfig == nil {
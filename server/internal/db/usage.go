@@ -9,7 +9,7 @@ import (
 // Usage represents a single API usage record
 type Usage struct {
 	ID           int64
-	ProviderID   int64
+	ProviderID   int64  // 0 for usage not tied to a provider, e.g. ACP agent runs
 	ProviderName string // for display
 	Service      string // e.g., "vertex_ai_llm", "openai"
 	Model        string
@@ -18,6 +18,8 @@ type Usage struct {
 	CachedTokens int
 	Cost         float64 // calculated cost in USD
 	Metadata     string  // JSON metadata (e.g., request details)
+	RunID        string  // set for agent runs, whose usage is recorded while they stream
+	Partial      bool    // the run hasn't reported final usage (yet)
 	CreatedAt    time.Time
 }
 
@@ -34,17 +36,29 @@ type UsageSummary struct {
 	TotalCost     float64
 }
 
-// RecordUsage records a new usage entry
+// RecordUsage records a new usage entry. An entry with a RunID replaces the
+// run's earlier entry, keeping its creation time, so a streaming run can
+// record its usage so far and then its final usage.
 func (db *DB) RecordUsage(u *Usage) (int64, error) {
-	result, err := db.conn.Exec(`
-		INSERT INTO usage (provider_id, service, model, input_tokens, output_tokens, cached_tokens, cost, metadata, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		u.ProviderID, u.Service, u.Model, u.InputTokens, u.OutputTokens, u.CachedTokens, u.Cost, u.Metadata, time.Now(),
-	)
+	var id int64
+	err := db.conn.QueryRow(`
+		INSERT INTO usage (provider_id, service, model, input_tokens, output_tokens, cached_tokens, cost, metadata, run_id, partial, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(run_id) DO UPDATE SET
+			input_tokens = excluded.input_tokens,
+			output_tokens = excluded.output_tokens,
+			cached_tokens = excluded.cached_tokens,
+			cost = excluded.cost,
+			metadata = excluded.metadata,
+			partial = excluded.partial
+		RETURNING id`,
+		sql.NullInt64{Int64: u.ProviderID, Valid: u.ProviderID != 0}, u.Service, u.Model, u.InputTokens, u.OutputTokens, u.CachedTokens, u.Cost, u.Metadata,
+		sql.NullString{String: u.RunID, Valid: u.RunID != ""}, u.Partial, time.Now(),
+	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to record usage: %w", err)
 	}
-	return result.LastInsertId()
+	return id, nil
 }
 
 // GetUsageByProvider returns usage records for a specific provider
@@ -54,7 +68,7 @@ func (db *DB) GetUsageByProvider(providerID int64, from, to time.Time, limit int
 	}
 
 	rows, err := db.conn.Query(`
-		SELECT u.id, u.provider_id, p.name, u.service, u.model, u.input_tokens, u.output_tokens, u.cached_tokens, u.cost, u.metadata, u.created_at
+		SELECT u.id, COALESCE(u.provider_id, 0), COALESCE(p.name, ''), u.service, u.model, u.input_tokens, u.output_tokens, u.cached_tokens, u.cost, u.metadata, COALESCE(u.run_id, ''), u.partial, u.created_at
 		FROM usage u
 		LEFT JOIN providers p ON u.provider_id = p.id
 		WHERE u.provider_id = ? AND u.created_at >= ? AND u.created_at <= ?
@@ -77,7 +91,7 @@ func (db *DB) GetUsageByService(service string, from, to time.Time, limit int) (
 	}
 
 	rows, err := db.conn.Query(`
-		SELECT u.id, u.provider_id, p.name, u.service, u.model, u.input_tokens, u.output_tokens, u.cached_tokens, u.cost, u.metadata, u.created_at
+		SELECT u.id, COALESCE(u.provider_id, 0), COALESCE(p.name, ''), u.service, u.model, u.input_tokens, u.output_tokens, u.cached_tokens, u.cost, u.metadata, COALESCE(u.run_id, ''), u.partial, u.created_at
 		FROM usage u
 		LEFT JOIN providers p ON u.provider_id = p.id
 		WHERE u.service = ? AND u.created_at >= ? AND u.created_at <= ?
@@ -100,7 +114,7 @@ func (db *DB) GetAllUsage(from, to time.Time, limit int) ([]*Usage, error) {
 	}
 
 	rows, err := db.conn.Query(`
-		SELECT u.id, u.provider_id, COALESCE(p.name, ''), u.service, u.model, u.input_tokens, u.output_tokens, u.cached_tokens, u.cost, u.metadata, u.created_at
+		SELECT u.id, COALESCE(u.provider_id, 0), COALESCE(p.name, ''), u.service, u.model, u.input_tokens, u.output_tokens, u.cached_tokens, u.cost, u.metadata, COALESCE(u.run_id, ''), u.partial, u.created_at
 		FROM usage u
 		LEFT JOIN providers p ON u.provider_id = p.id
 		WHERE u.created_at >= ? AND u.created_at <= ?
@@ -122,7 +136,7 @@ func scanUsageRows(rows *sql.Rows) ([]*Usage, error) {
 		u := &Usage{}
 		var metadata sql.NullString
 		if err := rows.Scan(&u.ID, &u.ProviderID, &u.ProviderName, &u.Service, &u.Model,
-			&u.InputTokens, &u.OutputTokens, &u.CachedTokens, &u.Cost, &metadata, &u.CreatedAt); err != nil {
+			&u.InputTokens, &u.OutputTokens, &u.CachedTokens, &u.Cost, &metadata, &u.RunID, &u.Partial, &u.CreatedAt); err != nil {
 			return nil, err
 		}
 		u.Metadata = metadata.String
@@ -134,7 +148,7 @@ func scanUsageRows(rows *sql.Rows) ([]*Usage, error) {
 // GetUsageSummary returns aggregated usage stats grouped by provider and model
 func (db *DB) GetUsageSummary(from, to time.Time) ([]*UsageSummary, error) {
 	rows, err := db.conn.Query(`
-		SELECT u.provider_id, COALESCE(p.name, '') as provider_name, u.service, u.model,
+		SELECT COALESCE(u.provider_id, 0), COALESCE(p.name, '') as provider_name, u.service, u.model,
 			COUNT(*) as total_requests,
 			SUM(u.input_tokens) as total_input,
 			SUM(u.output_tokens) as total_output,
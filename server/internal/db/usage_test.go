@@ -0,0 +1,136 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	d, err := New(filepath.Join(t.TempDir(), "cron.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func allUsage(t *testing.T, d *DB) []*Usage {
+	t.Helper()
+	usages, err := d.GetAllUsage(time.Now().Add(-time.Hour), time.Now().Add(time.Hour), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return usages
+}
+
+func TestRecordUsageWithoutRunID(t *testing.T) {
+	d := newTestDB(t)
+
+	// Usage without a run ID is stored with a NULL run_id, so entries
+	// never conflict with each other
+	for i := 0; i < 2; i++ {
+		if _, err := d.RecordUsage(&Usage{Service: "openai", Model: "gpt", InputTokens: 10}); err != nil {
+			t.Fatalf("RecordUsage: %v", err)
+		}
+	}
+	var nulls int
+	if err := d.conn.QueryRow(`SELECT COUNT(*) FROM usage WHERE run_id IS NULL`).Scan(&nulls); err != nil {
+		t.Fatal(err)
+	}
+	if nulls != 2 {
+		t.Errorf("expected 2 rows with a NULL run_id, got %d", nulls)
+	}
+	for _, u := range allUsage(t, d) {
+		if u.RunID != "" || u.Partial || u.ProviderID != 0 {
+			t.Errorf("unexpected usage %+v", u)
+		}
+	}
+}
+
+func TestRecordUsageReplacesRun(t *testing.T) {
+	d := newTestDB(t)
+
+	partialID, err := d.RecordUsage(&Usage{Service: "acp_agent", Model: "gemini", InputTokens: 600, Cost: 0.25, RunID: "r1", Partial: true})
+	if err != nil {
+		t.Fatalf("RecordUsage: %v", err)
+	}
+	before := allUsage(t, d)
+	if len(before) != 1 || !before[0].Partial {
+		t.Fatalf("expected one partial record, got %+v", before)
+	}
+
+	// The final record replaces the partial one, keeping its creation time
+	finalID, err := d.RecordUsage(&Usage{Service: "acp_agent", Model: "gemini", InputTokens: 900, OutputTokens: 150, Cost: 0.5, RunID: "r1"})
+	if err != nil {
+		t.Fatalf("RecordUsage: %v", err)
+	}
+	if finalID != partialID {
+		t.Errorf("expected the run's record (%d) to be updated in place, got %d", partialID, finalID)
+	}
+	after := allUsage(t, d)
+	if len(after) != 1 {
+		t.Fatalf("expected one record for the run, got %d", len(after))
+	}
+	u := after[0]
+	if u.Partial || u.InputTokens != 900 || u.OutputTokens != 150 || u.Cost != 0.5 || u.RunID != "r1" {
+		t.Errorf("expected the final usage, got %+v", u)
+	}
+	if !u.CreatedAt.Equal(before[0].CreatedAt) {
+		t.Errorf("created_at changed from %v to %v", before[0].CreatedAt, u.CreatedAt)
+	}
+
+	if _, err := d.RecordUsage(&Usage{Service: "acp_agent", Model: "gemini", InputTokens: 1, RunID: "r2"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(allUsage(t, d)); n != 2 {
+		t.Errorf("expected another run to get its own record, got %d records", n)
+	}
+}
+
+func TestUsageMigration(t *testing.T) {
+	// A database from before runs were tracked in usage
+	path := filepath.Join(t.TempDir(), "cron.db")
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`
+		CREATE TABLE usage (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider_id INTEGER,
+			service TEXT NOT NULL,
+			model TEXT NOT NULL,
+			input_tokens INTEGER NOT NULL DEFAULT 0,
+			output_tokens INTEGER NOT NULL DEFAULT 0,
+			cached_tokens INTEGER NOT NULL DEFAULT 0,
+			cost REAL NOT NULL DEFAULT 0,
+			metadata TEXT,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT INTO usage (service, model, input_tokens, created_at) VALUES ('openai', 'gpt', 42, ?);`, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	d, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer d.Close()
+
+	usages := allUsage(t, d)
+	if len(usages) != 1 || usages[0].InputTokens != 42 || usages[0].RunID != "" || usages[0].Partial {
+		t.Fatalf("expected the old row with no run and not partial, got %+v", usages)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := d.RecordUsage(&Usage{Service: "acp_agent", Model: "gemini", InputTokens: 7 + i, RunID: "r1"}); err != nil {
+			t.Fatalf("RecordUsage after migration: %v", err)
+		}
+	}
+	if n := len(allUsage(t, d)); n != 2 {
+		t.Errorf("expected the run's records to be upserted on the migrated table, got %d rows", n)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 const (
@@ -60,7 +62,7 @@ func loadConfigFrom(configPath, envPrefix string) (*Config, error) {
 	if configPath == "" {
 		home, err := os.UserHomeDir()
 		if err == nil {
-			configPath = filepath.Join(home, ".diane", "secrets", configFileName)
+			configPath = filepath.Join(home, config.DirName(), "secrets", configFileName)
 		}
 	}
 
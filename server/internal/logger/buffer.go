@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is how many recent log entries are kept in memory
+const DefaultBufferSize = 1000
+
+// Entry is a log record kept in the recent-log buffer
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Attrs   string    `json:"attrs,omitempty"` // key=value pairs, space separated
+}
+
+// Buffer is a fixed-size ring of recent log entries
+type Buffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	levels  []slog.Level
+	next    int
+	full    bool
+}
+
+// NewBuffer creates a buffer holding up to size entries
+func NewBuffer(size int) *Buffer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Buffer{
+		entries: make([]Entry, size),
+		levels:  make([]slog.Level, size),
+	}
+}
+
+func (b *Buffer) add(level slog.Level, e Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = e
+	b.levels[b.next] = level
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Recent returns up to limit of the newest entries at or above minLevel,
+// oldest first. A limit of 0 or less returns every matching entry.
+func (b *Buffer) Recent(limit int, minLevel slog.Level) []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := b.next
+	if b.full {
+		count = len(b.entries)
+	}
+
+	// Walk backwards from the newest entry, then reverse
+	var out []Entry
+	for i := 0; i < count && (limit <= 0 || len(out) < limit); i++ {
+		idx := (b.next - 1 - i + len(b.entries)) % len(b.entries)
+		if b.levels[idx] >= minLevel {
+			out = append(out, b.entries[idx])
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// recent holds the entries logged through the handler installed by Init
+var recent = NewBuffer(DefaultBufferSize)
+
+// Recent returns the newest entries logged since Init, oldest first. Only
+// records that passed the configured log level are kept.
+func Recent(limit int, minLevel slog.Level) []Entry {
+	return recent.Recent(limit, minLevel)
+}
+
+// ParseLevel parses a level name such as "debug", "info", "warn" or "error".
+// An empty name is info.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", name)
+	}
+	return level, nil
+}
+
+// bufferHandler copies every record it handles into a Buffer before
+// passing it on, so the buffer sees exactly what the log file sees
+type bufferHandler struct {
+	next  slog.Handler
+	buf   *Buffer
+	attrs string // attributes added via WithAttrs
+	group string
+}
+
+func newBufferHandler(next slog.Handler, buf *Buffer) *bufferHandler {
+	return &bufferHandler{next: next, buf: buf}
+}
+
+func (h *bufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *bufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	var sb strings.Builder
+	sb.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&sb, h.group, a)
+		return true
+	})
+	h.buf.add(r.Level, Entry{
+		Time:    r.Time,
+		Level:   r.Level.String(),
+		Message: r.Message,
+		Attrs:   strings.TrimSpace(sb.String()),
+	})
+	return h.next.Handle(ctx, r)
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var sb strings.Builder
+	sb.WriteString(h.attrs)
+	for _, a := range attrs {
+		writeAttr(&sb, h.group, a)
+	}
+	return &bufferHandler{next: h.next.WithAttrs(attrs), buf: h.buf, attrs: sb.String(), group: h.group}
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	group := name
+	if h.group != "" {
+		group = h.group + "." + name
+	}
+	return &bufferHandler{next: h.next.WithGroup(name), buf: h.buf, attrs: h.attrs, group: group}
+}
+
+func writeAttr(sb *strings.Builder, group string, a slog.Attr) {
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	key := a.Key
+	if group != "" {
+		key = group + "." + key
+	}
+	fmt.Fprintf(sb, " %s=%v", key, a.Value.Resolve())
+}
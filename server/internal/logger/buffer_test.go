@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestBufferRecent(t *testing.T) {
+	buf := NewBuffer(3)
+	for i, level := range []slog.Level{slog.LevelInfo, slog.LevelWarn, slog.LevelDebug, slog.LevelError} {
+		buf.add(level, Entry{Message: string(rune('a' + i))})
+	}
+
+	// "a" was overwritten; the rest come back oldest first
+	got := buf.Recent(0, slog.LevelDebug)
+	if len(got) != 3 || got[0].Message != "b" || got[2].Message != "d" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+	if got := buf.Recent(0, slog.LevelWarn); len(got) != 2 || got[0].Message != "b" {
+		t.Errorf("level filter: unexpected entries %+v", got)
+	}
+	if got := buf.Recent(1, slog.LevelDebug); len(got) != 1 || got[0].Message != "d" {
+		t.Errorf("limit should keep the newest entry, got %+v", got)
+	}
+}
+
+func TestBufferHandlerRespectsLevel(t *testing.T) {
+	buf := NewBuffer(10)
+	next := slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})
+	log := slog.New(newBufferHandler(next, buf)).With("component", "test")
+
+	log.Debug("hidden")
+	log.InfoContext(context.Background(), "shown", "count", 2)
+
+	got := buf.Recent(0, slog.LevelDebug)
+	if len(got) != 1 {
+		t.Fatalf("expected only the info entry, got %+v", got)
+	}
+	if got[0].Message != "shown" || got[0].Attrs != "component=test count=2" || got[0].Level != "INFO" {
+		t.Errorf("unexpected entry: %+v", got[0])
+	}
+}
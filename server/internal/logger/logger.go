@@ -69,6 +69,12 @@ func Init(cfg Config) error {
 		handler = slog.NewTextHandler(writer, opts)
 	}
 
+	// Keep recent entries in memory so they can be fetched remotely
+	handler = newBufferHandler(handler, recent)
+
+	// Tag records logged with a request's context with its ID
+	handler = requestIDHandler{next: handler}
+
 	// Add component attribute if specified
 	logger := slog.New(handler)
 	if cfg.Component != "" {
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the HTTP header a request ID is accepted from and
+// returned in
+const RequestIDHeader = "X-Request-ID"
+
+// MaxRequestIDLength is the longest request ID accepted from a client;
+// longer ones are replaced so they can't bloat every log line
+const MaxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// NewRequestID returns a fresh, random request ID
+func NewRequestID() string {
+	return uuid.New().String()
+}
+
+// WithRequestID returns a copy of ctx carrying id. Records logged with the
+// returned context (slog.InfoContext and friends) get a request_id attribute.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDHandler adds the request ID of a record's context to the record
+type requestIDHandler struct {
+	next slog.Handler
+}
+
+func (h requestIDHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	buf := NewBuffer(10)
+	next := slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})
+	log := slog.New(requestIDHandler{next: newBufferHandler(next, buf)}).With("component", "test")
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	if got := RequestID(ctx); got != "req-1" {
+		t.Fatalf("RequestID = %q, want req-1", got)
+	}
+	if got := RequestID(WithRequestID(context.Background(), "")); got != "" {
+		t.Errorf("an empty ID should not be attached, got %q", got)
+	}
+
+	log.InfoContext(ctx, "tagged")
+	log.Info("untagged")
+
+	got := buf.Recent(0, slog.LevelDebug)
+	if len(got) != 2 {
+		t.Fatalf("expected two entries, got %+v", got)
+	}
+	if got[0].Attrs != "component=test request_id=req-1" {
+		t.Errorf("expected the request ID on the first entry, got %q", got[0].Attrs)
+	}
+	if got[1].Attrs != "component=test" {
+		t.Errorf("expected no request ID on the second entry, got %q", got[1].Attrs)
+	}
+}
@@ -0,0 +1,112 @@
+package mcpproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingClient is a Client whose tool calls block until release is
+// closed or the call's context ends
+type blockingClient struct {
+	Client
+	release chan struct{}
+}
+
+func (c *blockingClient) CallToolContext(ctx context.Context, tool string, _ map[string]interface{}) (json.RawMessage, error) {
+	select {
+	case <-c.release:
+		return json.RawMessage(`{"content":[{"type":"text","text":"done"}]}`), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestCallWithContext(t *testing.T) {
+	data, err := CallWithContext(context.Background(), func() (json.RawMessage, error) {
+		return json.RawMessage(`"ok"`), nil
+	})
+	if err != nil || string(data) != `"ok"` {
+		t.Errorf("got %s, %v; want the call's result", data, err)
+	}
+
+	// A call with no way to cancel is abandoned once ctx ends
+	release := make(chan struct{})
+	defer close(release)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err = CallWithContext(ctx, func() (json.RawMessage, error) {
+		<-release
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestToolRouteTimeout(t *testing.T) {
+	client := &blockingClient{release: make(chan struct{})}
+	route := toolRoute{
+		client:   client,
+		server:   "slow",
+		tool:     "wait",
+		timeout:  50 * time.Millisecond,
+		limiter:  newCallLimiter(0),
+		maxBytes: DefaultMaxResultBytes,
+	}
+
+	_, err := route.call(context.Background(), nil)
+	var timeoutErr *ToolTimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Server != "slow" || timeoutErr.Tool != "wait" {
+		t.Fatalf("expected a ToolTimeoutError, got %v", err)
+	}
+
+	// Cancelling the caller cancels the downstream call without reporting
+	// a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	route.timeout = time.Minute
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err = route.call(ctx, nil)
+	if !errors.Is(err, context.Canceled) || errors.As(err, &timeoutErr) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	close(client.release)
+	if result, err := route.call(context.Background(), nil); err != nil || len(result) == 0 {
+		t.Errorf("expected a call that finishes in time to succeed, got %s, %v", result, err)
+	}
+}
+
+func TestToolRouteBusy(t *testing.T) {
+	limiter := newCallLimiter(1)
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer limiter.release()
+
+	route := toolRoute{
+		client:   &blockingClient{release: make(chan struct{})},
+		server:   "fragile",
+		tool:     "work",
+		timeout:  30 * time.Millisecond,
+		limiter:  limiter,
+		maxBytes: DefaultMaxResultBytes,
+	}
+	_, err := route.call(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "server fragile is at its concurrency limit (1)") {
+		t.Fatalf("expected the concurrency limit error, got %v", err)
+	}
+	var timeoutErr *ToolTimeoutError
+	if errors.As(err, &timeoutErr) {
+		t.Errorf("waiting for a slot should not be reported as a tool timeout, got %v", err)
+	}
+}
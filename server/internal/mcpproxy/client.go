@@ -2,7 +2,9 @@ package mcpproxy
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,6 +14,14 @@ import (
 	"time"
 )
 
+// DefaultStartupTimeout bounds how long a stdio server may take to answer
+// initialize after it is spawned, unless its config sets StartupTimeout
+const DefaultStartupTimeout = 30 * time.Second
+
+// errProcessExited reports that a stdio server's stdout closed before it
+// answered initialize
+var errProcessExited = errors.New("process exited before initializing")
+
 // MCPClient represents a connection to an MCP server
 type MCPClient struct {
 	Name                string
@@ -80,8 +90,20 @@ type MCPMessage struct {
 	Params  json.RawMessage `json:"params,omitempty"`
 }
 
-// NewMCPClient creates a new MCP client and starts the server process
-func NewMCPClient(name string, command string, args []string, env map[string]string) (*MCPClient, error) {
+// NewMCPClient creates a new MCP client and starts the server process. The
+// process must answer initialize within startupTimeout (0 = DefaultStartupTimeout).
+func NewMCPClient(name string, command string, args []string, env map[string]string, startupTimeout time.Duration) (*MCPClient, error) {
+	return NewMCPClientWithInitOptions(name, command, args, env, startupTimeout, nil)
+}
+
+// NewMCPClientWithInitOptions creates a new MCP client whose initialize
+// request carries initOptions as its initializationOptions, for servers that
+// take runtime configuration from the client (nil = none)
+func NewMCPClientWithInitOptions(name string, command string, args []string, env map[string]string, startupTimeout time.Duration, initOptions map[string]interface{}) (*MCPClient, error) {
+	if startupTimeout <= 0 {
+		startupTimeout = DefaultStartupTimeout
+	}
+
 	cmd := exec.Command(command, args...)
 
 	// Set environment variables
@@ -148,8 +170,11 @@ func NewMCPClient(name string, command string, args []string, env map[string]str
 	}()
 
 	// Initialize the MCP connection
-	if err := client.initialize(); err != nil {
+	if err := client.initialize(startupTimeout, initOptions); err != nil {
 		client.Close()
+		if errors.Is(err, errProcessExited) {
+			return nil, client.exitError()
+		}
 		return nil, fmt.Errorf("failed to initialize: %w", err)
 	}
 
@@ -231,6 +256,21 @@ func (c *MCPClient) sendRequest(method string, params json.RawMessage) (json.Raw
 
 // sendRequestWithTimeout sends a request and waits for response with a specific timeout
 func (c *MCPClient) sendRequestWithTimeout(method string, params json.RawMessage, timeout time.Duration) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := c.sendRequestContext(ctx, method, params)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out after %v", method, timeout)
+	}
+	return result, err
+}
+
+// sendRequestContext sends a request and waits for the response until ctx is done.
+// If ctx ends first the request is abandoned and the server is sent a
+// notifications/cancelled so it can stop work; any late response is dropped
+// by messageLoop, leaving the connection usable for subsequent requests.
+func (c *MCPClient) sendRequestContext(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
 	// Generate unique request ID
 	c.mu.Lock()
 	c.nextID++
@@ -262,7 +302,7 @@ func (c *MCPClient) sendRequestWithTimeout(method string, params json.RawMessage
 		return nil, fmt.Errorf("failed to send %s: %w", method, err)
 	}
 
-	// Wait for response with timeout
+	// Wait for response or cancellation
 	select {
 	case resp, ok := <-respCh:
 		if !ok {
@@ -272,17 +312,54 @@ func (c *MCPClient) sendRequestWithTimeout(method string, params json.RawMessage
 			return nil, fmt.Errorf("%s error: %s", method, resp.Error.Message)
 		}
 		return resp.Result, nil
-	case <-time.After(timeout):
+	case <-ctx.Done():
 		c.pendingMu.Lock()
 		delete(c.pending, reqID)
 		c.pendingMu.Unlock()
-		return nil, fmt.Errorf("%s timed out after %v", method, timeout)
+		c.sendCancelled(reqID, ctx.Err())
+		return nil, fmt.Errorf("%s: %w", method, ctx.Err())
 	}
 }
 
-// initialize sends the initialize request to the MCP server
-func (c *MCPClient) initialize() error {
+// sendCancelled tells the server that the client is no longer waiting for reqID
+func (c *MCPClient) sendCancelled(reqID int, reason error) {
+	params, err := json.Marshal(map[string]interface{}{
+		"requestId": reqID,
+		"reason":    reason.Error(),
+	})
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	err = c.encoder.Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "notifications/cancelled",
+		"params":  json.RawMessage(params),
+	})
+	c.mu.Unlock()
+
+	if err != nil {
+		slog.Debug("Failed to send cancellation", "server", c.Name, "request_id", reqID, "error", err)
+	}
+}
+
+// initialize sends the initialize request to the MCP server and waits up to
+// timeout for the response
+func (c *MCPClient) initialize(timeout time.Duration, initOptions map[string]interface{}) error {
 	params := json.RawMessage(`{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"diane","version":"1.0.0"}}`)
+	if len(initOptions) > 0 {
+		var err error
+		params, err = json.Marshal(map[string]interface{}{
+			"protocolVersion":       "2024-11-05",
+			"capabilities":          map[string]interface{}{},
+			"clientInfo":            map[string]string{"name": "diane", "version": "1.0.0"},
+			"initializationOptions": initOptions,
+		})
+		if err != nil {
+			return fmt.Errorf("invalid initialization options: %w", err)
+		}
+	}
 
 	// For initialize, we can't use the async messageLoop yet (it's not started)
 	// So we do a synchronous request here
@@ -297,12 +374,30 @@ func (c *MCPClient) initialize() error {
 	defer c.mu.Unlock()
 
 	if err := c.encoder.Encode(req); err != nil {
+		if errors.Is(err, syscall.EPIPE) {
+			return errProcessExited
+		}
 		return fmt.Errorf("failed to send initialize: %w", err)
 	}
 
+	// Decode blocks until the server writes; the caller kills the process on
+	// timeout, which unblocks it
 	var resp MCPResponse
-	if err := c.decoder.Decode(&resp); err != nil {
-		return fmt.Errorf("failed to read initialize response: %w", err)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.decoder.Decode(&resp)
+	}()
+
+	select {
+	case err := <-done:
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return errProcessExited
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read initialize response: %w", err)
+		}
+	case <-time.After(timeout):
+		return fmt.Errorf("startup timed out: no initialize response within %v", timeout)
 	}
 
 	if resp.Error != nil {
@@ -395,6 +490,16 @@ func (c *MCPClient) CallTool(toolName string, arguments map[string]interface{})
 	return c.sendRequestWithTimeout("tools/call", params, 30*time.Second)
 }
 
+// CallToolContext calls a tool on the MCP server, giving up when ctx is done.
+func (c *MCPClient) CallToolContext(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	params, err := toolCallParams(ctx, toolName, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sendRequestContext(ctx, "tools/call", params)
+}
+
 // ListPrompts requests the list of prompts from the MCP server
 func (c *MCPClient) ListPrompts() ([]map[string]interface{}, error) {
 	result, err := c.sendRequestWithTimeout("prompts/list", nil, 5*time.Second)
@@ -554,6 +659,22 @@ func (c *MCPClient) Close() error {
 	return nil
 }
 
+// exitError describes a process that exited before answering initialize,
+// with its exit status and last stderr lines. Call after Close.
+func (c *MCPClient) exitError() error {
+	msg := errProcessExited.Error()
+	if c.cmd.ProcessState != nil {
+		msg += " (" + c.cmd.ProcessState.String() + ")"
+	}
+	c.mu.Lock()
+	stderr := c.stderrOutput
+	c.mu.Unlock()
+	if stderr != "" {
+		msg += ": " + stderr
+	}
+	return errors.New(msg)
+}
+
 // GetDisconnectChan returns a channel that is closed when the process exits unexpectedly
 func (c *MCPClient) GetDisconnectChan() <-chan struct{} {
 	return c.disconnectChan
@@ -0,0 +1,24 @@
+package mcpproxy
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewMCPClientStartupTimeout(t *testing.T) {
+	_, err := NewMCPClient("slow", "sh", []string{"-c", "sleep 5"}, nil, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "startup timed out") {
+		t.Fatalf("expected startup timeout, got %v", err)
+	}
+}
+
+func TestNewMCPClientProcessExited(t *testing.T) {
+	_, err := NewMCPClient("broken", "sh", []string{"-c", "echo missing API key >&2; exit 3"}, nil, 5*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "process exited before initializing") {
+		t.Fatalf("expected process exit error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "timed out") {
+		t.Errorf("exit should not be reported as a timeout: %v", err)
+	}
+}
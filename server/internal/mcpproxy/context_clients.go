@@ -0,0 +1,132 @@
+package mcpproxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// InitOptionsProvider is implemented by context filters that can supply
+// per-context initialization options for a server. When a context sets
+// options for a stdio server, its tool calls go to a separate instance of
+// the server started with those options as the initialize request's
+// initializationOptions, so one server entry can behave differently per
+// context (e.g. a different working directory) without duplicate entries.
+type InitOptionsProvider interface {
+	// GetServerInitOptions returns the options a context sets for a server,
+	// or nil when it sets none
+	GetServerInitOptions(contextName, serverName string) (map[string]interface{}, error)
+}
+
+// contextClient is a server instance started for one context
+type contextClient struct {
+	client  *MCPClient
+	options string // the JSON-encoded options it was started with
+}
+
+// contextClientKey identifies a server's instance for a context
+func contextClientKey(serverName, contextName string) string {
+	return serverName + "\x00" + contextName
+}
+
+// contextClientFor returns the client that serves route in contextName: the
+// shared client, or the context's own instance when the context sets init
+// options for a stdio server. The instance is started on first use and
+// restarted when the options change or the process has exited.
+func (p *Proxy) contextClientFor(route toolRoute, contextName string, contextFilter ContextFilter) (Client, error) {
+	provider, ok := contextFilter.(InitOptionsProvider)
+	if !ok || contextName == "" {
+		return route.client, nil
+	}
+	if _, stdio := route.client.(*MCPClient); !stdio {
+		return route.client, nil
+	}
+
+	options, err := provider.GetServerInitOptions(contextName, route.server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load init options for %s in context %s: %w", route.server, contextName, err)
+	}
+	if len(options) == 0 {
+		p.closeContextClient(route.server, contextName)
+		return route.client, nil
+	}
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid init options for %s in context %s: %w", route.server, contextName, err)
+	}
+
+	config := p.GetServerConfig(route.server)
+	if config == nil {
+		return nil, fmt.Errorf("server not found: %s", route.server)
+	}
+
+	key := contextClientKey(route.server, contextName)
+	p.contextClientsMu.Lock()
+	if cc, ok := p.contextClients[key]; ok && cc.options == string(encoded) && cc.client.IsConnected() {
+		p.contextClientsMu.Unlock()
+		return cc.client, nil
+	}
+	closes := p.contextClientClosesFor(route.server)
+	p.contextClientsMu.Unlock()
+
+	// Starting can take up to the startup timeout, so it happens outside
+	// the lock: restarts and reloads close instances while holding p.mu
+	slog.Info("Starting MCP server instance for context", "server", route.server, "context", contextName)
+	client, err := NewMCPClientWithInitOptions(config.Name, config.Command, config.Args, config.Env,
+		time.Duration(config.StartupTimeout)*time.Second, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s for context %s: %w", route.server, contextName, err)
+	}
+
+	p.contextClientsMu.Lock()
+	defer p.contextClientsMu.Unlock()
+	if p.contextClientClosesFor(route.server) != closes {
+		// Instances were stopped meanwhile (e.g. the server restarted), so
+		// this one may run an outdated config
+		client.Close()
+		return nil, fmt.Errorf("%s was restarted while starting its instance for context %s; try again", route.server, contextName)
+	}
+	if cc, ok := p.contextClients[key]; ok {
+		if cc.options == string(encoded) && cc.client.IsConnected() {
+			// Another call started the same instance first
+			client.Close()
+			return cc.client, nil
+		}
+		cc.client.Close()
+	}
+	p.contextClients[key] = &contextClient{client: client, options: string(encoded)}
+	return client, nil
+}
+
+// contextClientClosesFor counts the closeContextClients calls that stopped a
+// server's instances. Caller must hold contextClientsMu.
+func (p *Proxy) contextClientClosesFor(serverName string) int {
+	return p.contextClientCloses[serverName] + p.contextClientCloses[""]
+}
+
+// closeContextClient stops a server's instance for a context, if running
+func (p *Proxy) closeContextClient(serverName, contextName string) {
+	key := contextClientKey(serverName, contextName)
+	p.contextClientsMu.Lock()
+	defer p.contextClientsMu.Unlock()
+	if cc, ok := p.contextClients[key]; ok {
+		cc.client.Close()
+		delete(p.contextClients, key)
+	}
+}
+
+// closeContextClients stops every context instance of a server, or of all
+// servers when serverName is empty
+func (p *Proxy) closeContextClients(serverName string) {
+	p.contextClientsMu.Lock()
+	defer p.contextClientsMu.Unlock()
+	p.contextClientCloses[serverName]++
+	for key, cc := range p.contextClients {
+		if serverName == "" || cc.client.Name == serverName {
+			slog.Info("Stopping MCP server instance for context", "server", cc.client.Name)
+			cc.client.Close()
+			delete(p.contextClients, key)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package mcpproxy
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoInitServer answers initialize and echoes the request to stderr
+var echoInitServer = []string{"-c", `read line; echo "$line" >&2; echo '{"jsonrpc":"2.0","id":0,"result":{}}'; exec sleep 30`}
+
+type fakeInitOptions map[string]map[string]interface{}
+
+func (f fakeInitOptions) IsToolEnabledInContext(contextName, serverName, toolName string) (bool, error) {
+	return true, nil
+}
+
+func (f fakeInitOptions) GetEnabledServersForContext(contextName string) ([]string, error) {
+	return nil, nil
+}
+
+func (f fakeInitOptions) GetDefaultContext() (string, error) {
+	return "", nil
+}
+
+func (f fakeInitOptions) GetServerInitOptions(contextName, serverName string) (map[string]interface{}, error) {
+	return f[contextName], nil
+}
+
+// waitForStderr waits for a client's stderr to contain want
+func waitForStderr(t *testing.T, c *MCPClient, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(c.GetStderrOutput(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("stderr %q does not contain %q", c.GetStderrOutput(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestContextClientFor(t *testing.T) {
+	shared, err := NewMCPClient("files", "sh", echoInitServer, nil, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewMCPClient: %v", err)
+	}
+	defer shared.Close()
+
+	p := &Proxy{
+		config:              &Config{Servers: []ServerConfig{{Name: "files", Type: "stdio", Command: "sh", Args: echoInitServer}}},
+		clients:             map[string]Client{"files": shared},
+		contextClients:      make(map[string]*contextClient),
+		contextClientCloses: make(map[string]int),
+	}
+	defer p.closeContextClients("")
+	route := toolRoute{client: shared, server: "files", tool: "read"}
+	filter := fakeInitOptions{"work": {"rootDir": "/work"}}
+
+	if got, err := p.contextClientFor(route, "personal", filter); err != nil || got != Client(shared) {
+		t.Fatalf("a context without options should use the shared client, got %v, %v", got, err)
+	}
+
+	work, err := p.contextClientFor(route, "work", filter)
+	if err != nil {
+		t.Fatalf("contextClientFor: %v", err)
+	}
+	if work == Client(shared) {
+		t.Fatal("a context with options should get its own instance")
+	}
+	waitForStderr(t, work.(*MCPClient), `"initializationOptions":{"rootDir":"/work"}`)
+
+	if again, _ := p.contextClientFor(route, "work", filter); again != work {
+		t.Error("the instance should be reused while the options are unchanged")
+	}
+
+	filter["work"] = map[string]interface{}{"rootDir": "/other"}
+	changed, err := p.contextClientFor(route, "work", filter)
+	if err != nil || changed == work {
+		t.Fatalf("changed options should start a new instance, got %v, %v", changed, err)
+	}
+	if work.IsConnected() {
+		t.Error("the instance for the old options should be stopped")
+	}
+
+	delete(filter, "work")
+	if got, _ := p.contextClientFor(route, "work", filter); got != Client(shared) {
+		t.Error("clearing the options should go back to the shared client")
+	}
+	if changed.IsConnected() || len(p.contextClients) != 0 {
+		t.Error("clearing the options should stop the context's instance")
+	}
+}
+
+func TestContextClientStartsOutsideLock(t *testing.T) {
+	// Answers initialize only after a delay, like a slow-starting server
+	slowInit := []string{"-c", `sleep 0.3; read line; echo '{"jsonrpc":"2.0","id":0,"result":{}}'; exec sleep 30`}
+	shared, err := NewMCPClient("files", "sh", echoInitServer, nil, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewMCPClient: %v", err)
+	}
+	defer shared.Close()
+
+	p := &Proxy{
+		config:              &Config{Servers: []ServerConfig{{Name: "files", Type: "stdio", Command: "sh", Args: slowInit}}},
+		clients:             map[string]Client{"files": shared},
+		contextClients:      make(map[string]*contextClient),
+		contextClientCloses: make(map[string]int),
+	}
+	defer p.closeContextClients("")
+	route := toolRoute{client: shared, server: "files", tool: "read"}
+	filter := fakeInitOptions{"work": {"rootDir": "/work"}}
+
+	type started struct {
+		client Client
+		err    error
+	}
+	done := make(chan started, 1)
+	go func() {
+		c, err := p.contextClientFor(route, "work", filter)
+		done <- started{c, err}
+	}()
+
+	// A restart while the instance is starting doesn't wait for it...
+	time.Sleep(100 * time.Millisecond)
+	closed := make(chan struct{})
+	go func() {
+		p.closeContextClients("files")
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(150 * time.Millisecond):
+		t.Fatal("closing instances waited for one still starting")
+	}
+
+	// ...and the instance it overtook is stopped rather than kept
+	res := <-done
+	if res.err == nil || !strings.Contains(res.err.Error(), "restarted") {
+		t.Fatalf("expected the start to be abandoned after the restart, got %v, %v", res.client, res.err)
+	}
+	if len(p.contextClients) != 0 {
+		t.Errorf("expected no instance to be kept, got %d", len(p.contextClients))
+	}
+
+	// Without a restart in between the next call gets its instance
+	if c, err := p.contextClientFor(route, "work", filter); err != nil || c == Client(shared) {
+		t.Errorf("expected the context's own instance, got %v, %v", c, err)
+	}
+}
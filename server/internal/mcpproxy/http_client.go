@@ -3,16 +3,19 @@ package mcpproxy
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"net"
 	"net/http"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/logger"
 )
 
 // HTTPClient represents a connection to an MCP server via HTTP Streamable transport
@@ -45,18 +48,8 @@ func NewHTTPClient(name string, url string, headers map[string]string) (*HTTPCli
 
 // NewHTTPClientWithOAuth creates a new HTTP Streamable MCP client with OAuth support
 func NewHTTPClientWithOAuth(name string, url string, headers map[string]string, oauth *OAuthConfig) (*HTTPClient, error) {
-	// Create HTTP client with connection pooling and keepalive
-	transport := &http.Transport{
-		MaxIdleConns:        10,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		DisableKeepAlives:   false,
-		// TCP keepalive to detect dead connections
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-	}
+	// Each server gets its own keep-alive pool, reused across requests
+	transport := newPooledTransport()
 
 	client := &HTTPClient{
 		name:        name,
@@ -81,26 +74,7 @@ func NewHTTPClientWithOAuth(name string, url string, headers map[string]string,
 
 // getAuthorizationHeader returns the Authorization header value if OAuth is configured
 func (c *HTTPClient) getAuthorizationHeader() string {
-	if c.oauthConfig == nil {
-		return ""
-	}
-
-	oauthMgr := GetOAuthManager()
-	if oauthMgr == nil {
-		return ""
-	}
-
-	token := oauthMgr.GetToken(c.name)
-	if token == nil {
-		return ""
-	}
-
-	tokenType := token.TokenType
-	if tokenType == "" {
-		tokenType = "Bearer"
-	}
-
-	return tokenType + " " + token.AccessToken
+	return oauthAuthorizationHeader(c.name, c.oauthConfig)
 }
 
 // RequiresAuth returns true if this client requires OAuth authentication
@@ -157,7 +131,7 @@ func parseSSEResponse(body []byte) ([]byte, error) {
 
 // decodeResponse handles both JSON and SSE formatted responses
 func decodeResponse(resp *http.Response) (*MCPResponse, error) {
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := readBody(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
@@ -276,6 +250,11 @@ func (c *HTTPClient) retryRequest(fn func() (json.RawMessage, error), method str
 
 		lastErr = err
 
+		// The caller gave up; retrying would only outlive its deadline
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+
 		// Check if error is retryable
 		errStr := err.Error()
 		isNetworkError := strings.Contains(errStr, "connection refused") ||
@@ -309,6 +288,23 @@ func (c *HTTPClient) sendRequest(method string, params json.RawMessage) (json.Ra
 }
 
 func (c *HTTPClient) sendRequestWithTimeout(method string, params json.RawMessage, timeout time.Duration) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := c.sendRequestContext(ctx, method, params)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out after %v", method, timeout)
+	}
+	return result, err
+}
+
+// sendRequestContext sends a JSON-RPC request via HTTP POST, aborting the
+// in-flight request (and any further retries) when ctx is done.
+func (c *HTTPClient) sendRequestContext(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	// ctx bounds the request, so skip the client-wide timeout but keep the
+	// shared transport for connection pooling
+	httpClient := &http.Client{Transport: c.httpClient.Transport}
+
 	// Wrap in retry logic for network resilience
 	return c.retryRequest(func() (json.RawMessage, error) {
 		c.mu.Lock()
@@ -331,7 +327,7 @@ func (c *HTTPClient) sendRequestWithTimeout(method string, params json.RawMessag
 			return nil, fmt.Errorf("failed to marshal request: %w", err)
 		}
 
-		httpReq, err := http.NewRequest("POST", c.baseURL, bytes.NewReader(body))
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewReader(body))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
@@ -339,6 +335,9 @@ func (c *HTTPClient) sendRequestWithTimeout(method string, params json.RawMessag
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("Accept", "application/json, text/event-stream")
 		httpReq.Header.Set("MCP-Protocol-Version", "2025-03-26")
+		if id := logger.RequestID(ctx); id != "" {
+			httpReq.Header.Set(logger.RequestIDHeader, id)
+		}
 		if sessionID != "" {
 			httpReq.Header.Set("MCP-Session-Id", sessionID)
 		}
@@ -351,8 +350,7 @@ func (c *HTTPClient) sendRequestWithTimeout(method string, params json.RawMessag
 			httpReq.Header.Set("Authorization", authHeader)
 		}
 
-		// Use the shared HTTP client with connection pooling
-		resp, err := c.httpClient.Do(httpReq)
+		resp, err := httpClient.Do(httpReq)
 		if err != nil {
 			c.mu.Lock()
 			c.lastError = err.Error()
@@ -435,6 +433,16 @@ func (c *HTTPClient) CallTool(toolName string, arguments map[string]interface{})
 	return c.sendRequest("tools/call", params)
 }
 
+// CallToolContext calls a tool on the MCP server, giving up when ctx is done.
+func (c *HTTPClient) CallToolContext(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	params, err := toolCallParams(ctx, toolName, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sendRequestContext(ctx, "tools/call", params)
+}
+
 // ListPrompts requests the list of prompts from the MCP server
 func (c *HTTPClient) ListPrompts() ([]map[string]interface{}, error) {
 	result, err := c.sendRequest("prompts/list", nil)
@@ -593,9 +601,10 @@ func (c *HTTPClient) GetStderrOutput() string {
 	return ""
 }
 
-// Close closes the HTTP connection
+// Close closes the HTTP connection and the server's pooled connections
 func (c *HTTPClient) Close() error {
 	c.connected.Store(false)
+	c.httpClient.CloseIdleConnections()
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package mcpproxy
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 )
@@ -19,6 +20,9 @@ type Client interface {
 	// CallTool calls a tool on the MCP server
 	CallTool(toolName string, arguments map[string]interface{}) (json.RawMessage, error)
 
+	// CallToolContext calls a tool on the MCP server, abandoning the call when ctx is done
+	CallToolContext(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error)
+
 	// ListPrompts returns the list of available prompts
 	ListPrompts() ([]map[string]interface{}, error)
 
@@ -67,3 +71,26 @@ type Client interface {
 	// GetDisconnectChan returns a channel that is closed when the client disconnects unexpectedly
 	GetDisconnectChan() <-chan struct{}
 }
+
+// CallWithContext runs call in the background and returns as soon as it
+// finishes or ctx is done. It is for transports with no way to cancel an
+// in-flight request: an abandoned call runs to completion and its result is
+// discarded.
+func CallWithContext(ctx context.Context, call func() (json.RawMessage, error)) (json.RawMessage, error) {
+	type result struct {
+		data json.RawMessage
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := call()
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,49 @@
+package mcpproxy
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// callLimiter caps the number of tool calls running concurrently against one
+// server. Fragile (often single-threaded) stdio servers can be limited to a
+// few calls at a time; callers beyond the limit wait for a free slot.
+type callLimiter struct {
+	max      int           // 0 = unlimited
+	sem      chan struct{} // nil when unlimited
+	inFlight atomic.Int32
+}
+
+func newCallLimiter(max int) *callLimiter {
+	l := &callLimiter{max: max}
+	if max > 0 {
+		l.sem = make(chan struct{}, max)
+	}
+	return l
+}
+
+// acquire takes a call slot, waiting until one is free or ctx is done
+func (l *callLimiter) acquire(ctx context.Context) error {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	l.inFlight.Add(1)
+	return nil
+}
+
+// release frees a slot taken by acquire
+func (l *callLimiter) release() {
+	l.inFlight.Add(-1)
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+// current returns the number of calls currently holding a slot
+func (l *callLimiter) current() int {
+	return int(l.inFlight.Load())
+}
@@ -0,0 +1,148 @@
+package mcpproxy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// staticServers is a ConfigProvider that always returns the same servers
+type staticServers []ServerConfig
+
+func (s staticServers) LoadMCPServerConfigs() ([]ServerConfig, error) { return s, nil }
+
+func TestCallLimiterCapsConcurrency(t *testing.T) {
+	l := newCallLimiter(2)
+	release := make(chan struct{})
+
+	var mu sync.Mutex
+	peak := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.acquire(context.Background()); err != nil {
+				t.Errorf("acquire: %v", err)
+				return
+			}
+			defer l.release()
+			mu.Lock()
+			if n := l.current(); n > peak {
+				peak = n
+			}
+			mu.Unlock()
+			<-release
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for l.current() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := l.current(); n != 2 {
+		t.Errorf("expected 2 calls in flight, got %d", n)
+	}
+
+	close(release)
+	wg.Wait()
+	if peak != 2 {
+		t.Errorf("expected at most 2 concurrent calls, peak was %d", peak)
+	}
+	if n := l.current(); n != 0 {
+		t.Errorf("expected every slot released, got %d in flight", n)
+	}
+
+	// Unlimited never waits
+	unlimited := newCallLimiter(0)
+	for i := 0; i < 10; i++ {
+		if err := unlimited.acquire(context.Background()); err != nil {
+			t.Fatalf("unlimited acquire: %v", err)
+		}
+	}
+	if n := unlimited.current(); n != 10 {
+		t.Errorf("expected 10 calls in flight, got %d", n)
+	}
+}
+
+func TestCallLimiterWaitGivesUp(t *testing.T) {
+	l := newCallLimiter(1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	if err := l.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled while waiting, got %v", err)
+	}
+	if n := l.current(); n != 1 {
+		t.Errorf("a caller that gave up must not hold a slot, got %d in flight", n)
+	}
+
+	// The slot is still usable once its holder releases it
+	l.release()
+	if err := l.acquire(context.Background()); err != nil {
+		t.Errorf("acquire after release: %v", err)
+	}
+}
+
+func TestLimiterAfterReload(t *testing.T) {
+	p := &Proxy{
+		config:     &Config{Servers: []ServerConfig{{Name: "slow", Enabled: true, Type: "stdio", Command: "slow-mcp", MaxConcurrency: 1}}},
+		clients:    map[string]Client{"slow": nil},
+		disabled:   map[string]bool{},
+		limiters:   make(map[string]*callLimiter),
+		notifyChan: make(chan string, 1),
+	}
+
+	old := p.limiterFor("slow", p.maxConcurrencyFor("slow"))
+	if again := p.limiterFor("slow", p.maxConcurrencyFor("slow")); again != old {
+		t.Error("expected the limiter to be reused while the limit is unchanged")
+	}
+	if err := old.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	p.configProvider = staticServers{{Name: "slow", Enabled: true, Type: "stdio", Command: "slow-mcp", MaxConcurrency: 2}}
+	plan, err := p.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if len(plan.Reconfigure) != 1 || plan.Reconfigure[0] != "slow" {
+		t.Fatalf("expected slow to be reconfigured without a restart, got %+v", plan)
+	}
+
+	l := p.limiterFor("slow", p.maxConcurrencyFor("slow"))
+	if l == old || l.max != 2 {
+		t.Fatalf("expected a new limiter with max 2, got max %d", l.max)
+	}
+	if again := p.limiterFor("slow", p.maxConcurrencyFor("slow")); again != l {
+		t.Error("expected the new limiter to be reused")
+	}
+
+	// Both new slots are free even though a call still holds the old one
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := l.acquire(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("acquire %d on the new limiter: %v", i+1, err)
+		}
+	}
+
+	// The call on the replaced limiter releases it there
+	old.release()
+	if n := old.current(); n != 0 {
+		t.Errorf("expected the old limiter to be drained, got %d in flight", n)
+	}
+	if n := p.inFlight("slow"); n != 2 {
+		t.Errorf("expected 2 calls in flight on the new limiter, got %d", n)
+	}
+}
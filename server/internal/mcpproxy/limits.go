@@ -0,0 +1,142 @@
+package mcpproxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// DefaultMaxResultBytes caps the size of a proxied resource read or tool
+// result when the proxy doesn't configure one
+const DefaultMaxResultBytes = 10 << 20
+
+// maxBodyBytes bounds any single HTTP response body read from a remote MCP
+// server. It is a hard ceiling above the configurable result cap, so a
+// misbehaving server can't exhaust memory before that cap is checked.
+const maxBodyBytes = 256 << 20
+
+// ResultTooLargeError is returned when a resource or tool result exceeds the
+// proxy's size cap and can't be truncated to fit
+type ResultTooLargeError struct {
+	What  string // e.g. "resource github://README.md"
+	Size  int
+	Limit int
+}
+
+func (e *ResultTooLargeError) Error() string {
+	return fmt.Sprintf("%s is %d bytes, over the %d byte limit", e.What, e.Size, e.Limit)
+}
+
+// readBody reads an HTTP response body, refusing anything over maxBodyBytes
+func readBody(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBodyBytes {
+		return nil, fmt.Errorf("response body exceeds %d bytes", maxBodyBytes)
+	}
+	return data, nil
+}
+
+// CheckResourceSize refuses a resource read result larger than limit.
+// Resources are returned whole or not at all: a truncated file or document
+// would be silently wrong. A limit of 0 or less disables the check.
+func CheckResourceSize(uri string, result json.RawMessage, limit int) error {
+	if limit > 0 && len(result) > limit {
+		return &ResultTooLargeError{What: "resource " + uri, Size: len(result), Limit: limit}
+	}
+	return nil
+}
+
+// CapToolResult fits a tool result within limit bytes by truncating its text
+// content blocks, appending a note with the original size. Results that are
+// still too large (e.g. dominated by image data) are refused. A limit of 0 or
+// less disables the cap.
+func CapToolResult(tool string, result json.RawMessage, limit int) (json.RawMessage, error) {
+	if limit <= 0 || len(result) <= limit {
+		return result, nil
+	}
+	tooLarge := &ResultTooLargeError{What: "result of tool " + tool, Size: len(result), Limit: limit}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(result, &fields); err != nil {
+		return nil, tooLarge
+	}
+	var content []map[string]interface{}
+	if err := json.Unmarshal(fields["content"], &content); err != nil {
+		return nil, tooLarge
+	}
+
+	var textBytes int
+	for _, block := range content {
+		if text, ok := block["text"].(string); ok && block["type"] == "text" {
+			textBytes += len(text)
+		}
+	}
+	if textBytes == 0 {
+		return nil, tooLarge
+	}
+
+	note := fmt.Sprintf("\n\n[truncated: tool result was %d bytes, over the %d byte limit]", len(result), limit)
+
+	// JSON escaping makes the encoded text larger than the text itself, so
+	// shrink the budget by the overshoot until the encoded result fits
+	budget := textBytes - (len(result) - limit) - len(note)
+	for attempt := 0; attempt < 4 && budget > 0; attempt++ {
+		capped := make([]map[string]interface{}, len(content))
+		remaining := budget
+		truncated := false
+		for i, block := range content {
+			text, ok := block["text"].(string)
+			if !ok || block["type"] != "text" {
+				capped[i] = block
+				continue
+			}
+			copied := make(map[string]interface{}, len(block))
+			for k, v := range block {
+				copied[k] = v
+			}
+			if len(text) > remaining {
+				text = truncateUTF8(text, remaining)
+				if !truncated {
+					text += note
+					truncated = true
+				}
+			}
+			remaining -= len(text)
+			if remaining < 0 {
+				remaining = 0
+			}
+			copied["text"] = text
+			capped[i] = copied
+		}
+
+		encoded, err := json.Marshal(capped)
+		if err != nil {
+			return nil, tooLarge
+		}
+		fields["content"] = encoded
+		out, err := json.Marshal(fields)
+		if err != nil {
+			return nil, tooLarge
+		}
+		if len(out) <= limit {
+			return out, nil
+		}
+		budget -= len(out) - limit
+	}
+	return nil, tooLarge
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a rune
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
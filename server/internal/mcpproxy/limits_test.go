@@ -0,0 +1,66 @@
+package mcpproxy
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCapToolResult(t *testing.T) {
+	text := strings.Repeat("line \"quoted\"\n", 1000)
+	result, _ := json.Marshal(map[string]interface{}{
+		"content": []map[string]interface{}{{"type": "text", "text": text}},
+		"isError": false,
+	})
+
+	if got, err := CapToolResult("srv_tool", result, len(result)); err != nil || len(got) != len(result) {
+		t.Fatalf("result at the limit should pass through unchanged, got %d bytes, %v", len(got), err)
+	}
+
+	limit := 4096
+	got, err := CapToolResult("srv_tool", result, limit)
+	if err != nil {
+		t.Fatalf("CapToolResult: %v", err)
+	}
+	if len(got) > limit {
+		t.Errorf("capped result is %d bytes, over %d", len(got), limit)
+	}
+	var decoded struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError *bool `json:"isError"`
+	}
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("capped result is not valid JSON: %v", err)
+	}
+	if decoded.IsError == nil {
+		t.Error("other result fields should be preserved")
+	}
+	if !strings.Contains(decoded.Content[0].Text, "[truncated: tool result was") {
+		t.Errorf("missing truncation note in %q", decoded.Content[0].Text[len(decoded.Content[0].Text)-100:])
+	}
+}
+
+func TestCapToolResultRefusesNonText(t *testing.T) {
+	result, _ := json.Marshal(map[string]interface{}{
+		"content": []map[string]interface{}{{"type": "image", "data": strings.Repeat("A", 2048), "mimeType": "image/png"}},
+	})
+
+	_, err := CapToolResult("srv_tool", result, 1024)
+	var tooLarge *ResultTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 1024 || tooLarge.Size != len(result) {
+		t.Fatalf("expected ResultTooLargeError, got %v", err)
+	}
+}
+
+func TestCheckResourceSize(t *testing.T) {
+	if err := CheckResourceSize("srv://big", make(json.RawMessage, 10), 10); err != nil {
+		t.Errorf("resource at the limit refused: %v", err)
+	}
+	err := CheckResourceSize("srv://big", make(json.RawMessage, 11), 10)
+	if err == nil || err.Error() != "resource srv://big is 11 bytes, over the 10 byte limit" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package mcpproxy
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -62,6 +63,13 @@ func (c *MasterProxyClient) CallTool(toolName string, arguments map[string]inter
 	return c.callFunc(c.serverName, toolName, arguments)
 }
 
+// CallToolContext routes the call to the master, returning early if ctx is done.
+func (c *MasterProxyClient) CallToolContext(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	return CallWithContext(ctx, func() (json.RawMessage, error) {
+		return c.CallTool(toolName, arguments)
+	})
+}
+
 // UpdateTools replaces the cached tool list (called when master sends an update).
 func (c *MasterProxyClient) UpdateTools(tools []map[string]interface{}) {
 	c.mu.Lock()
@@ -3,14 +3,18 @@ package mcpproxy
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 // Well-known OAuth providers - convenience shortcuts for common providers
@@ -80,7 +84,7 @@ func NewOAuthManager() (*OAuthManager, error) {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	tokensDir := filepath.Join(home, ".diane", "oauth-tokens")
+	tokensDir := filepath.Join(home, config.DirName(), "oauth-tokens")
 	if err := os.MkdirAll(tokensDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create tokens directory: %w", err)
 	}
@@ -302,17 +306,40 @@ func (m *OAuthManager) StartDeviceFlow(serverName string, config *OAuthProviderC
 	return &deviceResp, nil
 }
 
+// DefaultDeviceCodeExpiry is how long PollForToken keeps polling when the
+// provider didn't say how long its device code is valid for
+const DefaultDeviceCodeExpiry = 15 * time.Minute
+
+// Polling pace; variables so tests can speed them up
+var (
+	minPollInterval = 5 * time.Second
+	slowDownStep    = 5 * time.Second
+)
+
 // PollForToken polls the token endpoint until the user completes authorization
-// Returns the token when successful, or an error if authorization fails/expires
-func (m *OAuthManager) PollForToken(serverName string, config *OAuthProviderConfig, deviceCode string, interval int) (*OAuthToken, error) {
-	if interval < 5 {
-		interval = 5 // Minimum polling interval
+// Returns the token when successful, or an error if authorization fails/expires.
+// Transient failures (network errors, 5xx and 429 responses, unreadable
+// bodies) are retried at the polling interval, so a blip doesn't cost the
+// user their code; polling only stops on access_denied, expired_token,
+// another explicit OAuth error, or once expiresIn seconds have passed.
+func (m *OAuthManager) PollForToken(serverName string, config *OAuthProviderConfig, deviceCode string, interval int, expiresIn int) (*OAuthToken, error) {
+	wait := time.Duration(interval) * time.Second
+	if wait < minPollInterval {
+		wait = minPollInterval // Minimum polling interval
 	}
+	expiry := time.Duration(expiresIn) * time.Second
+	if expiry <= 0 {
+		expiry = DefaultDeviceCodeExpiry
+	}
+	deadline := time.Now().Add(expiry)
 
 	client := &http.Client{Timeout: 30 * time.Second}
 
 	for {
-		time.Sleep(time.Duration(interval) * time.Second)
+		if time.Now().Add(wait).After(deadline) {
+			return nil, fmt.Errorf("device code expired, please try again")
+		}
+		time.Sleep(wait)
 
 		data := url.Values{
 			"client_id":   {config.ClientID},
@@ -332,15 +359,21 @@ func (m *OAuthManager) PollForToken(serverName string, config *OAuthProviderConf
 
 		resp, err := client.Do(req)
 		if err != nil {
-			return nil, fmt.Errorf("token request failed: %w", err)
+			slog.Warn("OAuth token poll failed, retrying", "server", serverName, "error", err)
+			continue
 		}
 
-		body, _ := io.ReadAll(resp.Body)
+		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		if err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			slog.Warn("OAuth token poll failed, retrying", "server", serverName, "status", resp.StatusCode, "error", err)
+			continue
+		}
 
 		var tokenResp TokenResponse
 		if err := json.Unmarshal(body, &tokenResp); err != nil {
-			return nil, fmt.Errorf("failed to parse token response: %w", err)
+			slog.Warn("Unreadable OAuth token poll response, retrying", "server", serverName, "status", resp.StatusCode, "error", err)
+			continue
 		}
 
 		switch tokenResp.Error {
@@ -369,7 +402,7 @@ func (m *OAuthManager) PollForToken(serverName string, config *OAuthProviderConf
 
 		case "slow_down":
 			// We need to slow down, increase interval
-			interval += 5
+			wait += slowDownStep
 			continue
 
 		case "expired_token":
@@ -384,6 +417,111 @@ func (m *OAuthManager) PollForToken(serverName string, config *OAuthProviderConf
 	}
 }
 
+// ErrNoRefreshToken is returned by RefreshToken when the server's stored
+// token can't be refreshed and the user has to log in again
+var ErrNoRefreshToken = errors.New("no refresh token stored")
+
+// RefreshToken exchanges the server's stored refresh token for a new access
+// token and saves it. The stored token may already have expired. Providers
+// that don't rotate refresh tokens omit one from the response, in which case
+// the existing refresh token is kept.
+func (m *OAuthManager) RefreshToken(serverName string, config *OAuthProviderConfig) (*OAuthToken, error) {
+	m.mu.RLock()
+	current, ok := m.tokens[serverName]
+	m.mu.RUnlock()
+	if !ok || current.RefreshToken == "" {
+		return nil, ErrNoRefreshToken
+	}
+
+	data := url.Values{
+		"client_id":     {config.ClientID},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {current.RefreshToken},
+	}
+	if config.ClientSecret != "" {
+		data.Set("client_secret", config.ClientSecret)
+	}
+
+	req, err := http.NewRequest("POST", config.TokenURL, bytes.NewBufferString(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("refresh request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse refresh response (status %d): %w", resp.StatusCode, err)
+	}
+	if tokenResp.Error != "" {
+		return nil, fmt.Errorf("token refresh failed: %s - %s", tokenResp.Error, tokenResp.ErrorDesc)
+	}
+	if resp.StatusCode != http.StatusOK || tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	token := &OAuthToken{
+		AccessToken:  tokenResp.AccessToken,
+		TokenType:    tokenResp.TokenType,
+		RefreshToken: tokenResp.RefreshToken,
+		Scope:        tokenResp.Scope,
+	}
+	if token.TokenType == "" {
+		token.TokenType = current.TokenType
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = current.RefreshToken
+	}
+	if token.Scope == "" {
+		token.Scope = current.Scope
+	}
+	if tokenResp.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+
+	if err := m.SetToken(serverName, token); err != nil {
+		return nil, fmt.Errorf("failed to save token: %w", err)
+	}
+
+	return token, nil
+}
+
+// oauthAuthorizationHeader returns the Authorization header value for a
+// server's current OAuth token, or "" if OAuth isn't configured or there is no
+// valid token. It is looked up on every request so refreshed tokens are used
+// without reconnecting.
+func oauthAuthorizationHeader(serverName string, oauth *OAuthConfig) string {
+	if oauth == nil {
+		return ""
+	}
+
+	oauthMgr := GetOAuthManager()
+	if oauthMgr == nil {
+		return ""
+	}
+
+	token := oauthMgr.GetToken(serverName)
+	if token == nil {
+		return ""
+	}
+
+	tokenType := token.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+
+	return tokenType + " " + token.AccessToken
+}
+
 // global OAuth manager instance
 var globalOAuthManager *OAuthManager
 var oauthManagerOnce sync.Once
@@ -0,0 +1,113 @@
+package mcpproxy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOAuthManagerRefreshToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "r1" {
+			json.NewEncoder(w).Encode(TokenResponse{Error: "invalid_grant", ErrorDesc: "bad refresh token"})
+			return
+		}
+		json.NewEncoder(w).Encode(TokenResponse{AccessToken: "a2", ExpiresIn: 3600})
+	}))
+	defer ts.Close()
+
+	m := &OAuthManager{tokensDir: t.TempDir(), tokens: map[string]*OAuthToken{
+		"srv":   {AccessToken: "a1", TokenType: "Bearer", RefreshToken: "r1", ExpiresAt: time.Now().Add(-time.Hour)},
+		"stale": {AccessToken: "a1", RefreshToken: "revoked"},
+		"bare":  {AccessToken: "pat"},
+	}}
+	config := &OAuthProviderConfig{ClientID: "id", TokenURL: ts.URL}
+
+	token, err := m.RefreshToken("srv", config)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if token.AccessToken != "a2" || token.RefreshToken != "r1" || token.TokenType != "Bearer" {
+		t.Errorf("unexpected refreshed token: %+v", token)
+	}
+	if token.IsExpired() || m.GetToken("srv") == nil {
+		t.Error("refreshed token should be stored and valid")
+	}
+
+	if _, err := m.RefreshToken("stale", config); err == nil {
+		t.Error("expected a rejected refresh token to fail")
+	}
+	for _, name := range []string{"bare", "missing"} {
+		if _, err := m.RefreshToken(name, config); !errors.Is(err, ErrNoRefreshToken) {
+			t.Errorf("%s: got %v, want ErrNoRefreshToken", name, err)
+		}
+	}
+}
+
+func TestOAuthManagerPollForTokenRetriesTransientErrors(t *testing.T) {
+	defer func(i, s time.Duration) { minPollInterval, slowDownStep = i, s }(minPollInterval, slowDownStep)
+	minPollInterval, slowDownStep = time.Millisecond, time.Millisecond
+
+	var polls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>bad gateway</html>"))
+		case 2:
+			json.NewEncoder(w).Encode(TokenResponse{Error: "authorization_pending"})
+		case 3:
+			json.NewEncoder(w).Encode(TokenResponse{Error: "slow_down"})
+		case 4:
+			w.Write([]byte("not json"))
+		default:
+			json.NewEncoder(w).Encode(TokenResponse{AccessToken: "a1", TokenType: "Bearer"})
+		}
+	}))
+	defer ts.Close()
+
+	m := &OAuthManager{tokensDir: t.TempDir(), tokens: map[string]*OAuthToken{}}
+	config := &OAuthProviderConfig{ClientID: "id", TokenURL: ts.URL}
+
+	token, err := m.PollForToken("srv", config, "dc", 0, 60)
+	if err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if token.AccessToken != "a1" || polls != 5 {
+		t.Errorf("got token %+v after %d polls", token, polls)
+	}
+}
+
+func TestOAuthManagerPollForTokenStops(t *testing.T) {
+	defer func(i time.Duration) { minPollInterval = i }(minPollInterval)
+	minPollInterval = 10 * time.Millisecond
+
+	pending := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pending {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(TokenResponse{Error: "access_denied"})
+	}))
+	defer ts.Close()
+
+	m := &OAuthManager{tokensDir: t.TempDir(), tokens: map[string]*OAuthToken{}}
+	config := &OAuthProviderConfig{ClientID: "id", TokenURL: ts.URL}
+
+	// Transient errors are retried only until the device code expires
+	if _, err := m.PollForToken("srv", config, "dc", 0, 1); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expected expiry error, got %v", err)
+	}
+
+	pending = false
+	if _, err := m.PollForToken("srv", config, "dc", 0, 60); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected access denied error, got %v", err)
+	}
+}
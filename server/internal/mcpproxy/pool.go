@@ -0,0 +1,78 @@
+package mcpproxy
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// PoolConfig tunes the connection pool each HTTP and SSE server gets. Every
+// remote server has its own pool, reused across requests and dropped when
+// the server is restarted, disabled or removed.
+type PoolConfig struct {
+	// MaxIdleConnsPerHost is how many idle keep-alive connections are kept
+	// open to the server
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps the connections open to the server at once,
+	// including those in use. 0 means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept before closing
+	IdleConnTimeout time.Duration
+}
+
+// DefaultPoolConfig is used for servers when nothing else is configured
+var DefaultPoolConfig = PoolConfig{
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+var (
+	poolMu     sync.RWMutex
+	poolConfig = DefaultPoolConfig
+)
+
+// SetPoolConfig sets the pool settings for HTTP and SSE servers started from
+// now on. Non-positive fields keep their DefaultPoolConfig value, except
+// MaxConnsPerHost where 0 means no limit.
+func SetPoolConfig(cfg PoolConfig) {
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = DefaultPoolConfig.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = DefaultPoolConfig.IdleConnTimeout
+	}
+	if cfg.MaxConnsPerHost < 0 {
+		cfg.MaxConnsPerHost = 0
+	}
+	poolMu.Lock()
+	poolConfig = cfg
+	poolMu.Unlock()
+}
+
+// currentPoolConfig returns the pool settings in effect
+func currentPoolConfig() PoolConfig {
+	poolMu.RLock()
+	defer poolMu.RUnlock()
+	return poolConfig
+}
+
+// newPooledTransport returns a keep-alive transport for one server's
+// requests, sized by the current pool settings
+func newPooledTransport() *http.Transport {
+	cfg := currentPoolConfig()
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        cfg.MaxIdleConnsPerHost,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout,
+		ForceAttemptHTTP2:   true,
+		// TCP keepalive to detect dead connections
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
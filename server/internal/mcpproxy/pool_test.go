@@ -0,0 +1,26 @@
+package mcpproxy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetPoolConfig(t *testing.T) {
+	defer SetPoolConfig(DefaultPoolConfig)
+
+	SetPoolConfig(PoolConfig{MaxIdleConnsPerHost: 4, MaxConnsPerHost: 8, IdleConnTimeout: 5 * time.Second})
+	tr := newPooledTransport()
+	if tr.MaxIdleConnsPerHost != 4 || tr.MaxConnsPerHost != 8 || tr.IdleConnTimeout != 5*time.Second {
+		t.Errorf("transport = idle %d, max %d, timeout %s; want 4, 8, 5s",
+			tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost, tr.IdleConnTimeout)
+	}
+
+	SetPoolConfig(PoolConfig{MaxConnsPerHost: -1})
+	if got := currentPoolConfig(); got != DefaultPoolConfig {
+		t.Errorf("zero and negative fields should fall back to defaults, got %+v", got)
+	}
+
+	if newPooledTransport() == newPooledTransport() {
+		t.Error("each server should get its own transport")
+	}
+}
@@ -0,0 +1,69 @@
+package mcpproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/diane-assistant/diane/internal/logger"
+)
+
+// SupportedProtocolVersions are the MCP protocol revisions Diane serves,
+// newest first. Revisions are dates, so they compare as strings.
+var SupportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// DefaultProtocolVersion is answered to clients that don't request one
+const DefaultProtocolVersion = "2024-11-05"
+
+// NegotiateProtocolVersion picks the protocol version to answer an
+// initialize request with: the requested one if supported, else the newest
+// supported one older than it, else the newest supported one. fellBack
+// reports whether the answer differs from a requested version.
+func NegotiateProtocolVersion(requested string) (version string, fellBack bool) {
+	if requested == "" {
+		return DefaultProtocolVersion, false
+	}
+	for _, v := range SupportedProtocolVersions {
+		if v <= requested {
+			return v, v != requested
+		}
+	}
+	return SupportedProtocolVersions[0], true
+}
+
+// RequestIDMetaKey is the params._meta key a request ID is accepted from
+// clients and passed on to proxied servers under
+const RequestIDMetaKey = "diane/requestId"
+
+// RequestIDFromParams returns the request ID a client passed in params._meta,
+// or "" if there is none or it is too long
+func RequestIDFromParams(params json.RawMessage) string {
+	var p struct {
+		Meta map[string]interface{} `json:"_meta"`
+	}
+	if len(params) == 0 || json.Unmarshal(params, &p) != nil {
+		return ""
+	}
+	id, _ := p.Meta[RequestIDMetaKey].(string)
+	if len(id) > logger.MaxRequestIDLength {
+		return ""
+	}
+	return id
+}
+
+// toolCallParams encodes the params of a tools/call request, passing on the
+// request ID of ctx, if any, in _meta
+func toolCallParams(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	params := map[string]interface{}{
+		"name":      toolName,
+		"arguments": arguments,
+	}
+	if id := logger.RequestID(ctx); id != "" {
+		params["_meta"] = map[string]interface{}{RequestIDMetaKey: id}
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal params: %w", err)
+	}
+	return data, nil
+}
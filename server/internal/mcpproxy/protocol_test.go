@@ -0,0 +1,61 @@
+package mcpproxy
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/diane-assistant/diane/internal/logger"
+)
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	tests := []struct {
+		requested, want string
+		fellBack        bool
+	}{
+		{"", DefaultProtocolVersion, false},
+		{"2025-03-26", "2025-03-26", false},
+		{"2024-11-05", "2024-11-05", false},
+		{"2025-11-25", "2025-06-18", true}, // newer than we support
+		{"2025-05-01", "2025-03-26", true}, // between revisions
+		{"2024-01-01", "2025-06-18", true}, // older than we support
+	}
+	for _, tt := range tests {
+		got, fellBack := NegotiateProtocolVersion(tt.requested)
+		if got != tt.want || fellBack != tt.fellBack {
+			t.Errorf("NegotiateProtocolVersion(%q) = %q, %v; want %q, %v", tt.requested, got, fellBack, tt.want, tt.fellBack)
+		}
+	}
+}
+
+func TestToolCallParamsRequestID(t *testing.T) {
+	ctx := logger.WithRequestID(context.Background(), "req-42")
+	params, err := toolCallParams(ctx, "search", map[string]interface{}{"q": "x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := RequestIDFromParams(params); got != "req-42" {
+		t.Errorf("RequestIDFromParams = %q, want req-42", got)
+	}
+	var decoded struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &decoded); err != nil || decoded.Name != "search" || decoded.Arguments["q"] != "x" {
+		t.Errorf("unexpected params %s (%v)", params, err)
+	}
+
+	// Without a request ID no _meta is sent
+	params, _ = toolCallParams(context.Background(), "search", nil)
+	if strings.Contains(string(params), "_meta") {
+		t.Errorf("expected no _meta, got %s", params)
+	}
+
+	for _, raw := range []string{``, `{}`, `not json`, `{"_meta":{"diane/requestId":7}}`,
+		`{"_meta":{"diane/requestId":"` + strings.Repeat("x", logger.MaxRequestIDLength+1) + `"}}`} {
+		if got := RequestIDFromParams(json.RawMessage(raw)); got != "" {
+			t.Errorf("RequestIDFromParams(%q) = %q, want none", raw, got)
+		}
+	}
+}
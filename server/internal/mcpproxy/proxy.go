@@ -1,9 +1,13 @@
 package mcpproxy
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 )
@@ -40,6 +44,16 @@ type ServerConfig struct {
 	// Node-aware configuration
 	NodeID   string `json:"node_id,omitempty"`   // Target slave hostname
 	NodeMode string `json:"node_mode,omitempty"` // "master", "specific", "any"
+	// ToolTimeout bounds each tool call in seconds (0 = use the proxy default)
+	ToolTimeout int `json:"tool_timeout,omitempty"`
+	// MaxConcurrency caps concurrent tool calls; extra calls queue (0 = unlimited)
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+	// StartupTimeout bounds how long a stdio server may take to answer
+	// initialize after spawn, in seconds (0 = DefaultStartupTimeout)
+	StartupTimeout int `json:"startup_timeout,omitempty"`
+	// Priority orders the server's tools in tool listings, higher first
+	// (0 = default, the level of builtin tools; negative sorts after them)
+	Priority int `json:"priority,omitempty"`
 	// Legacy remote slave fields (kept for backward compatibility)
 	Hostname string `json:"hostname,omitempty"`  // For remote slaves
 	CertPath string `json:"cert_path,omitempty"` // Client cert path
@@ -47,6 +61,22 @@ type ServerConfig struct {
 	CAPath   string `json:"ca_path,omitempty"`   // CA cert path
 }
 
+// DefaultToolTimeout bounds a proxied tool call when neither the server nor
+// the proxy configures a timeout.
+const DefaultToolTimeout = 60 * time.Second
+
+// ToolTimeoutError is returned when a proxied tool call does not complete
+// within its timeout. The downstream request is cancelled before it is returned.
+type ToolTimeoutError struct {
+	Server  string
+	Tool    string
+	Timeout time.Duration
+}
+
+func (e *ToolTimeoutError) Error() string {
+	return fmt.Sprintf("tool %s on server %s timed out after %v", e.Tool, e.Server, e.Timeout)
+}
+
 // Config represents the MCP proxy configuration
 type Config struct {
 	Servers []ServerConfig `json:"servers"`
@@ -70,6 +100,34 @@ type Proxy struct {
 	initializing   map[string]bool   // Track servers currently initializing
 	initWg         sync.WaitGroup    // Wait group for initial startup
 
+	// disabled tracks servers taken out of rotation at runtime via EnableServer.
+	// It is independent of the stored config and survives Reload.
+	disabled map[string]bool
+
+	// toolTimeout is the default per-call timeout for servers without their own
+	toolTimeout time.Duration
+
+	// maxResultBytes caps resource reads and tool results (see CapToolResult)
+	maxResultBytes int
+
+	// limiters caps concurrent tool calls per server (see ServerConfig.MaxConcurrency).
+	// Guarded by limitersMu rather than mu so it can be updated during lookups.
+	limiters   map[string]*callLimiter
+	limitersMu sync.Mutex
+
+	// restarts throttles RestartServer and the stdio auto-restart watcher
+	restarts *restartGuard
+
+	// contextClients holds stdio server instances started for contexts that
+	// set init options (see InitOptionsProvider), keyed by contextClientKey.
+	// Guarded by contextClientsMu, taken after mu when both are held.
+	contextClients   map[string]*contextClient
+	contextClientsMu sync.Mutex
+	// contextClientCloses counts closeContextClients calls by server name
+	// ("" for all), so an instance that finished starting after its server
+	// was restarted is not kept. Guarded by contextClientsMu.
+	contextClientCloses map[string]int
+
 	// masterContextMappings stores context-to-server mappings received from the master.
 	// This allows the slave to filter master-proxied tools by context, achieving parity
 	// with the master's context filtering. Map: contextName -> set of enabled server names.
@@ -86,12 +144,19 @@ func NewProxy(provider ConfigProvider) (*Proxy, error) {
 	config := &Config{Servers: servers}
 
 	proxy := &Proxy{
-		clients:        make(map[string]Client),
-		config:         config,
-		configProvider: provider,
-		notifyChan:     make(chan string, 10), // Buffered channel for notifications
-		initErrors:     make(map[string]string),
-		initializing:   make(map[string]bool),
+		clients:             make(map[string]Client),
+		config:              config,
+		configProvider:      provider,
+		notifyChan:          make(chan string, 10), // Buffered channel for notifications
+		initErrors:          make(map[string]string),
+		initializing:        make(map[string]bool),
+		disabled:            make(map[string]bool),
+		toolTimeout:         DefaultToolTimeout,
+		maxResultBytes:      DefaultMaxResultBytes,
+		limiters:            make(map[string]*callLimiter),
+		restarts:            newRestartGuard(),
+		contextClients:      make(map[string]*contextClient),
+		contextClientCloses: make(map[string]int),
 	}
 
 	// Start enabled MCP servers concurrently in background
@@ -153,7 +218,7 @@ func (p *Proxy) startClient(config ServerConfig) error {
 
 	switch config.Type {
 	case "sse":
-		client, err = NewSSEClient(config.Name, config.URL, config.Headers)
+		client, err = NewSSEClientWithOAuth(config.Name, config.URL, config.Headers, config.OAuth)
 	case "http":
 		client, err = NewHTTPClientWithOAuth(config.Name, config.URL, config.Headers, config.OAuth)
 	case "remote":
@@ -165,7 +230,7 @@ func (p *Proxy) startClient(config ServerConfig) error {
 			config.CertPath, config.KeyPath, config.CAPath, "unknown", nil)
 	case "stdio", "":
 		// Default to stdio
-		client, err = NewMCPClient(config.Name, config.Command, config.Args, config.Env)
+		client, err = NewMCPClient(config.Name, config.Command, config.Args, config.Env, time.Duration(config.StartupTimeout)*time.Second)
 	default:
 		return fmt.Errorf("unsupported transport type: %s", config.Type)
 	}
@@ -221,8 +286,25 @@ func (p *Proxy) watchAndRestartStdio(config ServerConfig) {
 		// Wait before restarting
 		time.Sleep(backoff)
 
+		// A process that dies right after starting resets the backoff, so the
+		// restart guard shared with RestartServer bounds how often it restarts
+		for {
+			err := p.restarts.allow(config.Name)
+			if err == nil {
+				break
+			}
+			wait := err.(*RestartThrottledError).Wait
+			slog.Warn("STDIO restart throttled", "server", config.Name, "wait", wait.Round(time.Second))
+			time.Sleep(wait)
+		}
+
 		// Close old client cleanly
 		p.mu.Lock()
+		if p.disabled[config.Name] {
+			p.mu.Unlock()
+			slog.Debug("STDIO server disabled at runtime, stopping watcher", "server", config.Name)
+			return
+		}
 		if oldClient, ok := p.clients[config.Name]; ok {
 			oldClient.Close()
 			delete(p.clients, config.Name)
@@ -230,7 +312,7 @@ func (p *Proxy) watchAndRestartStdio(config ServerConfig) {
 		p.mu.Unlock()
 
 		// Create new client
-		newClient, err := NewMCPClient(config.Name, config.Command, config.Args, config.Env)
+		newClient, err := NewMCPClient(config.Name, config.Command, config.Args, config.Env, time.Duration(config.StartupTimeout)*time.Second)
 		if err != nil {
 			slog.Error("Failed to restart STDIO process",
 				"server", config.Name,
@@ -282,6 +364,17 @@ func (p *Proxy) watchAndRestartStdio(config ServerConfig) {
 	}
 }
 
+// clientNames returns the names of the connected clients in sorted order, so
+// aggregated listings come out the same way every time. Callers must hold p.mu.
+func (p *Proxy) clientNames() []string {
+	names := make([]string, 0, len(p.clients))
+	for name := range p.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ListAllTools aggregates tools from all MCP clients
 func (p *Proxy) ListAllTools() ([]map[string]interface{}, error) {
 	p.mu.RLock()
@@ -289,7 +382,8 @@ func (p *Proxy) ListAllTools() ([]map[string]interface{}, error) {
 
 	var allTools []map[string]interface{}
 
-	for serverName, client := range p.clients {
+	for _, serverName := range p.clientNames() {
+		client := p.clients[serverName]
 		tools, err := client.ListTools()
 		if err != nil {
 			slog.Warn("Failed to list tools from server", "server", serverName, "error", err)
@@ -317,30 +411,118 @@ func (p *Proxy) ListAllTools() ([]map[string]interface{}, error) {
 
 // CallTool routes a tool call to the appropriate MCP client
 func (p *Proxy) CallTool(toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	return p.CallToolForContext(context.Background(), "", toolName, arguments, nil)
+}
+
+// SetToolTimeout sets the default timeout for proxied tool calls. Servers with
+// their own ToolTimeout keep it; a non-positive value restores DefaultToolTimeout.
+func (p *Proxy) SetToolTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultToolTimeout
+	}
+	p.mu.Lock()
+	p.toolTimeout = timeout
+	p.mu.Unlock()
+}
+
+// SetMaxResultBytes sets the largest resource read or tool result the proxy
+// passes through. Oversized text tool results are truncated; anything else
+// over the limit is refused. A non-positive value restores DefaultMaxResultBytes.
+func (p *Proxy) SetMaxResultBytes(n int) {
+	if n <= 0 {
+		n = DefaultMaxResultBytes
+	}
+	p.mu.Lock()
+	p.maxResultBytes = n
+	p.mu.Unlock()
+}
+
+// MaxResultBytes returns the current result size cap
+func (p *Proxy) MaxResultBytes() int {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
+	return p.maxResultBytes
+}
 
-	// Parse server name from tool name (format: server_toolname)
-	var serverName, actualToolName string
-	for sName := range p.clients {
-		prefix := sName + "_"
-		if len(toolName) > len(prefix) && toolName[:len(prefix)] == prefix {
-			serverName = sName
-			actualToolName = toolName[len(prefix):]
-			break
+// toolTimeoutFor returns the tool call timeout for a server. Caller must hold p.mu.
+func (p *Proxy) toolTimeoutFor(serverName string) time.Duration {
+	for _, server := range p.config.Servers {
+		if server.Name == serverName && server.ToolTimeout > 0 {
+			return time.Duration(server.ToolTimeout) * time.Second
 		}
 	}
+	return p.toolTimeout
+}
 
-	if serverName == "" {
-		return nil, fmt.Errorf("unknown tool: %s", toolName)
+// maxConcurrencyFor returns a server's concurrent call limit (0 = unlimited). Caller must hold p.mu.
+func (p *Proxy) maxConcurrencyFor(serverName string) int {
+	for _, server := range p.config.Servers {
+		if server.Name == serverName {
+			return server.MaxConcurrency
+		}
 	}
+	return 0
+}
 
-	client, ok := p.clients[serverName]
-	if !ok {
-		return nil, fmt.Errorf("server not found: %s", serverName)
+// limiterFor returns the call limiter for a server, replacing it if the
+// configured limit has changed. Calls already holding a slot on a replaced
+// limiter release it there.
+func (p *Proxy) limiterFor(serverName string, maxConcurrency int) *callLimiter {
+	p.limitersMu.Lock()
+	defer p.limitersMu.Unlock()
+
+	l, ok := p.limiters[serverName]
+	if !ok || l.max != maxConcurrency {
+		l = newCallLimiter(maxConcurrency)
+		p.limiters[serverName] = l
+	}
+	return l
+}
+
+// inFlight returns the number of tool calls currently executing on a server
+func (p *Proxy) inFlight(serverName string) int {
+	p.limitersMu.Lock()
+	defer p.limitersMu.Unlock()
+
+	if l, ok := p.limiters[serverName]; ok {
+		return l.current()
+	}
+	return 0
+}
+
+// call runs the tool, first waiting for a free slot if the server limits
+// concurrency. The timeout covers both the wait and the call itself; if it
+// expires the downstream request is cancelled and a *ToolTimeoutError returned.
+// The request ID of ctx, if any, is passed on to the server.
+func (r toolRoute) call(ctx context.Context, arguments map[string]interface{}) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if err := r.limiter.acquire(ctx); err != nil {
+		slog.WarnContext(ctx, "Proxied tool call gave up waiting for a free slot",
+			"server", r.server, "tool", r.tool, "max_concurrency", r.limiter.max, "timeout", r.timeout)
+		return nil, fmt.Errorf("server %s is at its concurrency limit (%d); no slot freed up within %v", r.server, r.limiter.max, r.timeout)
+	}
+	defer r.limiter.release()
+
+	result, err := r.client.CallToolContext(ctx, r.tool, arguments)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		slog.WarnContext(ctx, "Proxied tool call timed out", "server", r.server, "tool", r.tool, "timeout", r.timeout)
+		return nil, &ToolTimeoutError{Server: r.server, Tool: r.tool, Timeout: r.timeout}
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return client.CallTool(actualToolName, arguments)
+	capped, err := CapToolResult(r.server+"_"+r.tool, result, r.maxBytes)
+	if err != nil {
+		slog.WarnContext(ctx, "Proxied tool result refused", "server", r.server, "tool", r.tool, "size", len(result), "limit", r.maxBytes)
+		return nil, err
+	}
+	if len(capped) < len(result) {
+		slog.WarnContext(ctx, "Proxied tool result truncated", "server", r.server, "tool", r.tool, "size", len(result), "limit", r.maxBytes)
+	}
+	return capped, nil
 }
 
 // ListPromptsForContext returns prompts from servers enabled in the context
@@ -362,7 +544,8 @@ func (p *Proxy) ListPromptsForContext(contextName string, contextFilter ContextF
 		enabledMap[name] = true
 	}
 
-	for serverName, client := range p.clients {
+	for _, serverName := range p.clientNames() {
+		client := p.clients[serverName]
 		// Skip if server is not enabled in this context
 		if !enabledMap[serverName] {
 			continue
@@ -440,7 +623,8 @@ func (p *Proxy) ListAllPrompts() ([]map[string]interface{}, error) {
 
 	var allPrompts []map[string]interface{}
 
-	for serverName, client := range p.clients {
+	for _, serverName := range p.clientNames() {
+		client := p.clients[serverName]
 		prompts, err := client.ListPrompts()
 		if err != nil {
 			slog.Warn("Failed to list prompts from server", "server", serverName, "error", err)
@@ -495,7 +679,8 @@ func (p *Proxy) ListAllResources() ([]map[string]interface{}, error) {
 
 	var allResources []map[string]interface{}
 
-	for serverName, client := range p.clients {
+	for _, serverName := range p.clientNames() {
+		client := p.clients[serverName]
 		resources, err := client.ListResources()
 		if err != nil {
 			slog.Warn("Failed to list resources from server", "server", serverName, "error", err)
@@ -538,7 +723,15 @@ func (p *Proxy) ReadResource(uri string) (json.RawMessage, error) {
 		return nil, fmt.Errorf("server not found: %s", serverName)
 	}
 
-	return client.ReadResource(actualURI)
+	result, err := client.ReadResource(actualURI)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckResourceSize(uri, result, p.maxResultBytes); err != nil {
+		slog.Warn("Proxied resource refused", "uri", uri, "size", len(result), "limit", p.maxResultBytes)
+		return nil, err
+	}
+	return result, nil
 }
 
 // monitorNotifications watches all client notification channels
@@ -561,58 +754,163 @@ func (p *Proxy) NotificationChan() <-chan string {
 	return p.notifyChan
 }
 
-// Reload reloads the MCP configuration and starts/stops servers as needed
-func (p *Proxy) Reload() error {
+// ReloadPlan lists how a reload changes the running servers. Servers in
+// Unchanged keep their connections.
+type ReloadPlan struct {
+	Start       []string `json:"start,omitempty"`       // added, or enabled but not running
+	Stop        []string `json:"stop,omitempty"`        // removed or disabled
+	Restart     []string `json:"restart,omitempty"`     // connection settings changed
+	Reconfigure []string `json:"reconfigure,omitempty"` // only call-time settings changed, applied in place
+	Unchanged   []string `json:"unchanged,omitempty"`
+}
+
+// Changed reports whether the plan affects any server
+func (rp *ReloadPlan) Changed() bool {
+	return len(rp.Start)+len(rp.Stop)+len(rp.Restart)+len(rp.Reconfigure) > 0
+}
+
+// PlanReload loads the MCP configuration and reports what Reload would do,
+// without changing anything
+func (p *Proxy) PlanReload() (*ReloadPlan, error) {
+	servers, err := p.configProvider.LoadMCPServerConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load new config: %w", err)
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.planReloadUnlocked(servers), nil
+}
+
+// planReloadUnlocked diffs the new server configs against the running state
+// (assumes lock is held by caller)
+func (p *Proxy) planReloadUnlocked(servers []ServerConfig) *ReloadPlan {
+	oldServers := make(map[string]ServerConfig)
+	if p.config != nil {
+		for _, s := range p.config.Servers {
+			oldServers[s.Name] = s
+		}
+	}
+	newServers := p.runnableServers(servers)
+
+	plan := &ReloadPlan{}
+	for _, name := range p.clientNames() {
+		if _, exists := newServers[name]; !exists {
+			plan.Stop = append(plan.Stop, name)
+		}
+	}
+
+	names := make([]string, 0, len(newServers))
+	for name := range newServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		newConfig := newServers[name]
+		oldConfig := oldServers[name]
+		_, running := p.clients[name]
+		switch {
+		case !running:
+			plan.Start = append(plan.Start, name)
+		case !sameConnection(oldConfig, newConfig):
+			plan.Restart = append(plan.Restart, name)
+		case !reflect.DeepEqual(oldConfig, newConfig):
+			plan.Reconfigure = append(plan.Reconfigure, name)
+		default:
+			plan.Unchanged = append(plan.Unchanged, name)
+		}
+	}
+	return plan
+}
+
+// runnableServers returns the enabled servers of a supported transport that
+// have not been disabled at runtime, by name
+func (p *Proxy) runnableServers(servers []ServerConfig) map[string]ServerConfig {
+	runnable := make(map[string]ServerConfig)
+	for _, s := range servers {
+		if p.disabled[s.Name] {
+			continue
+		}
+		if s.Enabled && (s.Type == "stdio" || s.Type == "sse" || s.Type == "http" || s.Type == "") {
+			runnable[s.Name] = s
+		}
+	}
+	return runnable
+}
+
+// sameConnection reports whether two configs of a server would start the same
+// client. Call-time settings (tool timeout, concurrency, priority) are read
+// from the current config on each call and don't need a restart.
+func sameConnection(a, b ServerConfig) bool {
+	a.Enabled, b.Enabled = true, true
+	a.ToolTimeout, b.ToolTimeout = 0, 0
+	a.MaxConcurrency, b.MaxConcurrency = 0, 0
+	a.Priority, b.Priority = 0, 0
+	return reflect.DeepEqual(a, b)
+}
+
+// Reload reloads the MCP configuration and applies only the differences:
+// new servers are started, removed ones stopped, and servers whose
+// connection settings changed are restarted. Other servers keep their
+// connections. It returns the plan that was applied.
+func (p *Proxy) Reload() (*ReloadPlan, error) {
 	slog.Info("Reloading MCP configuration")
 
 	servers, err := p.configProvider.LoadMCPServerConfigs()
 	if err != nil {
-		return fmt.Errorf("failed to load new config: %w", err)
+		return nil, fmt.Errorf("failed to load new config: %w", err)
 	}
-	newConfig := &Config{Servers: servers}
 
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Build map of new enabled servers (all supported types)
-	newServers := make(map[string]ServerConfig)
-	for _, s := range newConfig.Servers {
-		if s.Enabled && (s.Type == "stdio" || s.Type == "sse" || s.Type == "http" || s.Type == "") {
-			newServers[s.Name] = s
-		}
+	plan := p.planReloadUnlocked(servers)
+	newServers := p.runnableServers(servers)
+
+	for _, name := range plan.Stop {
+		slog.Info("Stopping removed MCP server", "server", name)
+		p.clients[name].Close()
+		delete(p.clients, name)
+		p.closeContextClients(name)
 	}
 
-	// Stop removed servers
-	for name, client := range p.clients {
-		if _, exists := newServers[name]; !exists {
-			slog.Info("Stopping removed MCP server", "server", name)
-			client.Close()
-			delete(p.clients, name)
+	for _, name := range plan.Restart {
+		slog.Info("Restarting changed MCP server", "server", name)
+		p.clients[name].Close()
+		delete(p.clients, name)
+		p.closeContextClients(name)
+		if err := p.startClientUnlocked(newServers[name]); err != nil {
+			slog.Warn("Failed to restart MCP server", "server", name, "error", err)
 		}
 	}
 
-	// Start new servers
-	for name, serverConfig := range newServers {
-		if _, exists := p.clients[name]; !exists {
-			slog.Info("Starting new MCP server", "server", name)
-			if err := p.startClientUnlocked(serverConfig); err != nil {
-				slog.Warn("Failed to start MCP server", "server", name, "error", err)
-			}
+	for _, name := range plan.Start {
+		slog.Info("Starting new MCP server", "server", name)
+		if err := p.startClientUnlocked(newServers[name]); err != nil {
+			slog.Warn("Failed to start MCP server", "server", name, "error", err)
 		}
 	}
 
-	p.config = newConfig
+	p.config = &Config{Servers: servers}
 
-	// Send notification that tools changed
-	select {
-	case p.notifyChan <- "config-reload":
-		slog.Debug("Sent config-reload notification")
-	default:
-		slog.Warn("Notification channel full, dropping config-reload notification")
+	if plan.Changed() {
+		// Send notification that tools changed
+		select {
+		case p.notifyChan <- "config-reload":
+			slog.Debug("Sent config-reload notification")
+		default:
+			slog.Warn("Notification channel full, dropping config-reload notification")
+		}
 	}
 
-	slog.Info("MCP configuration reload complete")
-	return nil
+	slog.Info("MCP configuration reload complete",
+		"started", plan.Start,
+		"stopped", plan.Stop,
+		"restarted", plan.Restart,
+		"reconfigured", plan.Reconfigure,
+		"unchanged", len(plan.Unchanged))
+	return plan, nil
 }
 
 // startClientUnlocked starts a client (assumes lock is held by caller)
@@ -622,11 +920,11 @@ func (p *Proxy) startClientUnlocked(config ServerConfig) error {
 
 	switch config.Type {
 	case "sse":
-		client, err = NewSSEClient(config.Name, config.URL, config.Headers)
+		client, err = NewSSEClientWithOAuth(config.Name, config.URL, config.Headers, config.OAuth)
 	case "http":
 		client, err = NewHTTPClientWithOAuth(config.Name, config.URL, config.Headers, config.OAuth)
 	case "stdio", "":
-		client, err = NewMCPClient(config.Name, config.Command, config.Args, config.Env)
+		client, err = NewMCPClient(config.Name, config.Command, config.Args, config.Env, time.Duration(config.StartupTimeout)*time.Second)
 	default:
 		err = fmt.Errorf("unsupported transport type: %s", config.Type)
 	}
@@ -679,6 +977,9 @@ type ServerStatus struct {
 	Error         string `json:"error,omitempty"`
 	RequiresAuth  bool   `json:"requires_auth,omitempty"`
 	Authenticated bool   `json:"authenticated,omitempty"`
+	// InFlight is the number of tool calls currently executing on the server
+	InFlight       int `json:"in_flight"`
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
 }
 
 // GetServerStatuses returns the status of all configured MCP servers (non-blocking)
@@ -691,8 +992,10 @@ func (p *Proxy) GetServerStatuses() []ServerStatus {
 
 	for _, server := range p.config.Servers {
 		status := ServerStatus{
-			Name:    server.Name,
-			Enabled: server.Enabled,
+			Name:           server.Name,
+			Enabled:        server.Enabled && !p.disabled[server.Name],
+			InFlight:       p.inFlight(server.Name),
+			MaxConcurrency: server.MaxConcurrency,
 		}
 
 		// Check if this server requires OAuth authentication
@@ -745,7 +1048,8 @@ func (p *Proxy) GetServerStatuses() []ServerStatus {
 	return statuses
 }
 
-// RestartServer restarts a specific MCP server by name
+// RestartServer restarts a specific MCP server by name. Restarts that come
+// too soon or too often return a *RestartThrottledError.
 func (p *Proxy) RestartServer(name string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -763,12 +1067,21 @@ func (p *Proxy) RestartServer(name string) error {
 		return fmt.Errorf("server not found: %s", name)
 	}
 
+	if p.disabled[name] {
+		return fmt.Errorf("server %s is disabled; enable it before restarting", name)
+	}
+
+	if err := p.restarts.allow(name); err != nil {
+		return err
+	}
+
 	// Close existing client if running
 	if client, ok := p.clients[name]; ok {
 		slog.Info("Stopping MCP server for restart", "server", name)
 		client.Close()
 		delete(p.clients, name)
 	}
+	p.closeContextClients(name)
 
 	// Start fresh
 	if serverConfig.Enabled && (serverConfig.Type == "stdio" || serverConfig.Type == "sse" || serverConfig.Type == "http" || serverConfig.Type == "") {
@@ -787,6 +1100,96 @@ func (p *Proxy) RestartServer(name string) error {
 	return nil
 }
 
+// EnableServer takes a server out of rotation (enabled=false) or brings it back
+// (enabled=true) without touching its stored configuration. A disabled server's
+// connection is closed and its tools disappear from listings; it stays disabled
+// across Reload until explicitly re-enabled.
+func (p *Proxy) EnableServer(name string, enabled bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var serverConfig *ServerConfig
+	for _, s := range p.config.Servers {
+		if s.Name == name {
+			serverConfig = &s
+			break
+		}
+	}
+
+	if serverConfig == nil {
+		return fmt.Errorf("server not found: %s", name)
+	}
+
+	if !enabled {
+		p.disabled[name] = true
+		if client, ok := p.clients[name]; ok {
+			slog.Info("Disabling MCP server", "server", name)
+			client.Close()
+			delete(p.clients, name)
+		}
+		p.closeContextClients(name)
+		delete(p.initErrors, name)
+	} else {
+		delete(p.disabled, name)
+		if _, running := p.clients[name]; !running && serverConfig.Enabled &&
+			(serverConfig.Type == "stdio" || serverConfig.Type == "sse" || serverConfig.Type == "http" || serverConfig.Type == "") {
+			slog.Info("Enabling MCP server", "server", name)
+			if err := p.startClientUnlocked(*serverConfig); err != nil {
+				return fmt.Errorf("failed to start %s: %w", name, err)
+			}
+		}
+	}
+
+	// Notify about tool changes
+	select {
+	case p.notifyChan <- name:
+	default:
+	}
+
+	return nil
+}
+
+// IsServerDisabled reports whether a server has been disabled at runtime
+func (p *Proxy) IsServerDisabled(name string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.disabled[name]
+}
+
+// RestartResult reports the outcome of restarting a single MCP server
+type RestartResult struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RestartServers restarts every enabled proxied server, or only those that are
+// currently disconnected or reporting an error when onlyFailed is set.
+// A failure on one server does not stop the remaining restarts.
+func (p *Proxy) RestartServers(onlyFailed bool) []RestartResult {
+	var results []RestartResult
+
+	for _, status := range p.GetServerStatuses() {
+		if !status.Enabled {
+			continue
+		}
+		if onlyFailed && status.Connected && status.Error == "" {
+			continue
+		}
+
+		result := RestartResult{Name: status.Name}
+		if err := p.RestartServer(status.Name); err != nil {
+			slog.Warn("Failed to restart MCP server", "server", status.Name, "error", err)
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
 // GetTotalToolCount returns the total number of tools across all connected servers (uses cache)
 func (p *Proxy) GetTotalToolCount() int {
 	p.mu.RLock()
@@ -814,6 +1217,7 @@ func (p *Proxy) Close() error {
 	}
 
 	p.clients = make(map[string]Client)
+	p.closeContextClients("")
 	return nil
 }
 
@@ -916,6 +1320,20 @@ func (p *Proxy) GetServerConfig(name string) *ServerConfig {
 	return nil
 }
 
+// ServerPriorities returns the Priority of each configured server that has one
+func (p *Proxy) ServerPriorities() map[string]int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	priorities := make(map[string]int)
+	for _, s := range p.config.Servers {
+		if s.Priority != 0 {
+			priorities[s.Name] = s.Priority
+		}
+	}
+	return priorities
+}
+
 // GetServersWithOAuth returns all servers that have OAuth configured
 func (p *Proxy) GetServersWithOAuth() []ServerConfig {
 	p.mu.RLock()
@@ -949,7 +1367,8 @@ func (p *Proxy) ListToolsForContext(contextName string, contextFilter ContextFil
 
 	var allTools []map[string]interface{}
 
-	for serverName, client := range p.clients {
+	for _, serverName := range p.clientNames() {
+		client := p.clients[serverName]
 		tools, err := client.ListTools()
 		if err != nil {
 			slog.Warn("Failed to list tools from server", "server", serverName, "error", err)
@@ -1002,8 +1421,36 @@ func (p *Proxy) ListToolsForContext(contextName string, contextFilter ContextFil
 }
 
 // CallToolForContext routes a tool call to the appropriate MCP client after validating context access
-// If contextFilter is nil or contextName is empty, no context validation is performed
-func (p *Proxy) CallToolForContext(contextName, toolName string, arguments map[string]interface{}, contextFilter ContextFilter) (json.RawMessage, error) {
+// If contextFilter is nil or contextName is empty, no context validation is performed.
+// ctx carries the request ID, if any, passed on to the server.
+func (p *Proxy) CallToolForContext(ctx context.Context, contextName, toolName string, arguments map[string]interface{}, contextFilter ContextFilter) (json.RawMessage, error) {
+	route, err := p.resolveToolForContext(contextName, toolName, contextFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	route.client, err = p.contextClientFor(route, contextName, contextFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return route.call(ctx, arguments)
+}
+
+// toolRoute is where a prefixed tool call is dispatched
+type toolRoute struct {
+	client   Client
+	server   string
+	tool     string
+	timeout  time.Duration
+	limiter  *callLimiter
+	maxBytes int
+}
+
+// resolveToolForContext finds the client serving toolName and checks that it
+// is enabled in the context. The proxy lock is only held for the lookup, so a
+// slow tool can't stall Reload/RestartServer.
+func (p *Proxy) resolveToolForContext(contextName, toolName string, contextFilter ContextFilter) (toolRoute, error) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -1019,12 +1466,12 @@ func (p *Proxy) CallToolForContext(contextName, toolName string, arguments map[s
 	}
 
 	if serverName == "" {
-		return nil, fmt.Errorf("unknown tool: %s", toolName)
+		return toolRoute{}, fmt.Errorf("unknown tool: %s", toolName)
 	}
 
 	client, ok := p.clients[serverName]
 	if !ok {
-		return nil, fmt.Errorf("server not found: %s", serverName)
+		return toolRoute{}, fmt.Errorf("server not found: %s", serverName)
 	}
 
 	_, isMasterProxy := client.(*MasterProxyClient)
@@ -1034,18 +1481,25 @@ func (p *Proxy) CallToolForContext(contextName, toolName string, arguments map[s
 		if isMasterProxy {
 			// For master-proxied clients, use the master's context mappings
 			if !p.isMasterServerInContext(contextName, serverName) {
-				return nil, fmt.Errorf("tool %s is not enabled in context %s", toolName, contextName)
+				return toolRoute{}, fmt.Errorf("tool %s is not enabled in context %s", toolName, contextName)
 			}
 		} else {
 			enabled, err := contextFilter.IsToolEnabledInContext(contextName, serverName, actualToolName)
 			if err != nil {
 				slog.Warn("Failed to check tool context access", "context", contextName, "server", serverName, "tool", actualToolName, "error", err)
-				return nil, fmt.Errorf("failed to verify context access for tool %s", toolName)
+				return toolRoute{}, fmt.Errorf("failed to verify context access for tool %s", toolName)
 			} else if !enabled {
-				return nil, fmt.Errorf("tool %s is not enabled in context %s", toolName, contextName)
+				return toolRoute{}, fmt.Errorf("tool %s is not enabled in context %s", toolName, contextName)
 			}
 		}
 	}
 
-	return client.CallTool(actualToolName, arguments)
+	return toolRoute{
+		client:   client,
+		server:   serverName,
+		tool:     actualToolName,
+		timeout:  p.toolTimeoutFor(serverName),
+		limiter:  p.limiterFor(serverName, p.maxConcurrencyFor(serverName)),
+		maxBytes: p.maxResultBytes,
+	}, nil
 }
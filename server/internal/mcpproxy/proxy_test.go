@@ -0,0 +1,66 @@
+package mcpproxy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClientNamesSorted(t *testing.T) {
+	p := &Proxy{clients: map[string]Client{
+		"zeta":  nil,
+		"alpha": nil,
+		"mid":   nil,
+	}}
+
+	for i := 0; i < 5; i++ {
+		got := strings.Join(p.clientNames(), ",")
+		if got != "alpha,mid,zeta" {
+			t.Fatalf("clientNames() = %s, want alpha,mid,zeta", got)
+		}
+	}
+}
+
+func TestPlanReload(t *testing.T) {
+	p := &Proxy{
+		config: &Config{Servers: []ServerConfig{
+			{Name: "same", Enabled: true, Type: "stdio", Command: "same-mcp"},
+			{Name: "tuned", Enabled: true, Type: "stdio", Command: "tuned-mcp"},
+			{Name: "moved", Enabled: true, Type: "http", URL: "http://old"},
+			{Name: "gone", Enabled: true, Type: "stdio", Command: "gone-mcp"},
+			{Name: "off", Enabled: true, Type: "stdio", Command: "off-mcp"},
+			{Name: "failed", Enabled: true, Type: "stdio", Command: "failed-mcp"},
+		}},
+		clients: map[string]Client{
+			"same": nil, "tuned": nil, "moved": nil, "gone": nil, "off": nil,
+		},
+		disabled: map[string]bool{},
+	}
+
+	plan := p.planReloadUnlocked([]ServerConfig{
+		{Name: "same", Enabled: true, Type: "stdio", Command: "same-mcp"},
+		{Name: "tuned", Enabled: true, Type: "stdio", Command: "tuned-mcp", ToolTimeout: 90, Priority: 5},
+		{Name: "moved", Enabled: true, Type: "http", URL: "http://new"},
+		{Name: "off", Enabled: false, Type: "stdio", Command: "off-mcp"},
+		{Name: "failed", Enabled: true, Type: "stdio", Command: "failed-mcp"},
+		{Name: "added", Enabled: true, Type: "sse", URL: "http://added"},
+	})
+
+	for field, got := range map[string][]string{
+		"added,failed": plan.Start,
+		"gone,off":     plan.Stop,
+		"moved":        plan.Restart,
+		"tuned":        plan.Reconfigure,
+		"same":         plan.Unchanged,
+	} {
+		if strings.Join(got, ",") != field {
+			t.Errorf("expected %s, got %v (plan %+v)", field, got, plan)
+		}
+	}
+	if !plan.Changed() {
+		t.Error("expected the plan to report changes")
+	}
+
+	if (&ReloadPlan{Unchanged: []string{"same"}}).Changed() {
+		t.Error("a plan with only unchanged servers should not report changes")
+	}
+}
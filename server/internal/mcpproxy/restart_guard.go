@@ -0,0 +1,83 @@
+package mcpproxy
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Restart throttling defaults: at least DefaultRestartInterval between two
+// restarts of a server, and at most DefaultMaxRestarts within DefaultRestartWindow
+const (
+	DefaultRestartInterval = 2 * time.Second
+	DefaultMaxRestarts     = 10
+	DefaultRestartWindow   = 5 * time.Minute
+)
+
+// RestartThrottledError is returned when a server has been restarted too
+// recently or too often to be restarted again yet
+type RestartThrottledError struct {
+	Server string
+	Wait   time.Duration
+}
+
+func (e *RestartThrottledError) Error() string {
+	return fmt.Sprintf("restart of %s throttled, try again in %ds", e.Server, int(math.Ceil(e.Wait.Seconds())))
+}
+
+// RetryAfter returns how long to wait before the restart is allowed
+func (e *RestartThrottledError) RetryAfter() time.Duration {
+	return e.Wait
+}
+
+// restartGuard keeps a server that dies right after starting from being
+// restarted in a tight loop, whether by callers of RestartServer or by the
+// stdio auto-restart watcher. Both go through the same guard.
+type restartGuard struct {
+	interval    time.Duration
+	maxRestarts int
+	window      time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	history map[string][]time.Time // restart times per server within window, oldest first
+}
+
+func newRestartGuard() *restartGuard {
+	return &restartGuard{
+		interval:    DefaultRestartInterval,
+		maxRestarts: DefaultMaxRestarts,
+		window:      DefaultRestartWindow,
+		now:         time.Now,
+		history:     make(map[string][]time.Time),
+	}
+}
+
+// allow records a restart of server and returns nil, or returns a
+// *RestartThrottledError without recording anything if the restart is too soon
+func (g *restartGuard) allow(server string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	times := g.history[server]
+	for len(times) > 0 && now.Sub(times[0]) >= g.window {
+		times = times[1:]
+	}
+
+	var wait time.Duration
+	if n := len(times); n > 0 {
+		wait = g.interval - now.Sub(times[n-1])
+	}
+	if len(times) >= g.maxRestarts {
+		wait = max(wait, g.window-now.Sub(times[len(times)-g.maxRestarts]))
+	}
+	if wait > 0 {
+		g.history[server] = times
+		return &RestartThrottledError{Server: server, Wait: wait}
+	}
+
+	g.history[server] = append(times, now)
+	return nil
+}
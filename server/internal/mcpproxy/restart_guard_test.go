@@ -0,0 +1,51 @@
+package mcpproxy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestartGuard(t *testing.T) {
+	now := time.Unix(1000, 0)
+	g := newRestartGuard()
+	g.interval, g.maxRestarts, g.window = 2*time.Second, 3, time.Minute
+	g.now = func() time.Time { return now }
+
+	if err := g.allow("srv"); err != nil {
+		t.Fatalf("first restart: %v", err)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	err := g.allow("srv")
+	var throttled *RestartThrottledError
+	if !errors.As(err, &throttled) || throttled.Wait != 1500*time.Millisecond {
+		t.Fatalf("restart within the interval: got %v, want a 1.5s wait", err)
+	}
+	if !strings.Contains(err.Error(), "try again in 2s") {
+		t.Errorf("error should round the wait up, got %q", err)
+	}
+	if err := g.allow("other"); err != nil {
+		t.Errorf("servers are throttled independently, got %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+	if err := g.allow("srv"); err != nil {
+		t.Fatalf("second restart: %v", err)
+	}
+	now = now.Add(2 * time.Second)
+	if err := g.allow("srv"); err != nil {
+		t.Fatalf("third restart: %v", err)
+	}
+
+	// The window holds 3 restarts; the next is allowed once the first ages out
+	now = now.Add(2 * time.Second)
+	if err := g.allow("srv"); !errors.As(err, &throttled) || throttled.Wait != time.Minute-6500*time.Millisecond {
+		t.Fatalf("restart over the window limit: got %v", err)
+	}
+	now = now.Add(throttled.Wait)
+	if err := g.allow("srv"); err != nil {
+		t.Errorf("restart after the oldest aged out: %v", err)
+	}
+}
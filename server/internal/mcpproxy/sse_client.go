@@ -3,7 +3,9 @@ package mcpproxy
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -14,6 +16,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/logger"
 )
 
 // SSEClient represents a connection to an MCP server via SSE transport
@@ -23,6 +27,7 @@ type SSEClient struct {
 	headers             map[string]string
 	httpClient          *http.Client
 	sseTransport        *http.Transport
+	stream              io.ReadCloser
 	sessionID           string
 	messageEndpoint     string
 	mu                  sync.Mutex
@@ -43,10 +48,19 @@ type SSEClient struct {
 	nextID              int
 	pendingMu           sync.Mutex
 	pending             map[interface{}]chan MCPResponse
+	// OAuth support
+	oauthConfig *OAuthConfig
 }
 
 // NewSSEClient creates a new SSE MCP client
 func NewSSEClient(name string, url string, headers map[string]string) (*SSEClient, error) {
+	return NewSSEClientWithOAuth(name, url, headers, nil)
+}
+
+// NewSSEClientWithOAuth creates a new SSE MCP client with OAuth support. The
+// Authorization header is taken from the OAuth manager's current token on
+// every (re)connect and request, overriding any static Authorization header.
+func NewSSEClientWithOAuth(name string, url string, headers map[string]string, oauth *OAuthConfig) (*SSEClient, error) {
 	// Transport with TCP keep-alive for long-lived SSE connections
 	sseTransport := &http.Transport{
 		DialContext: (&net.Dialer{
@@ -61,7 +75,7 @@ func NewSSEClient(name string, url string, headers map[string]string) (*SSEClien
 		name:          name,
 		baseURL:       strings.TrimSuffix(url, "/"),
 		headers:       headers,
-		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		httpClient:    &http.Client{Timeout: 30 * time.Second, Transport: newPooledTransport()},
 		sseTransport:  sseTransport,
 		notifyChan:    make(chan string, 10),
 		stopChan:      make(chan struct{}),
@@ -69,6 +83,7 @@ func NewSSEClient(name string, url string, headers map[string]string) (*SSEClien
 		lastActivity:  time.Now(),
 		nextID:        1,
 		pending:       make(map[interface{}]chan MCPResponse),
+		oauthConfig:   oauth,
 	}
 
 	// Connect to SSE endpoint to get session ID
@@ -99,9 +114,7 @@ func (c *SSEClient) connect() error {
 
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
-	for k, v := range c.headers {
-		req.Header.Set(k, v)
-	}
+	c.setHeaders(req)
 
 	// Use the SSE transport with TCP keep-alive (no overall timeout — stream is long-lived)
 	sseClient := &http.Client{Transport: c.sseTransport}
@@ -112,11 +125,15 @@ func (c *SSEClient) connect() error {
 
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized && c.oauthConfig != nil {
+			return fmt.Errorf("authentication required: run 'diane auth login %s' to authenticate", c.name)
+		}
 		return fmt.Errorf("SSE connection failed with status %d", resp.StatusCode)
 	}
 
 	c.connected.Store(true)
 	c.mu.Lock()
+	c.stream = resp.Body
 	c.lastActivity = time.Now()
 	c.mu.Unlock()
 
@@ -314,9 +331,7 @@ func (c *SSEClient) reconnect() error {
 
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
-	for k, v := range c.headers {
-		req.Header.Set(k, v)
-	}
+	c.setHeaders(req)
 
 	sseClient := &http.Client{Transport: c.sseTransport}
 	resp, err := sseClient.Do(req)
@@ -326,11 +341,15 @@ func (c *SSEClient) reconnect() error {
 
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized && c.oauthConfig != nil {
+			return fmt.Errorf("authentication required: run 'diane auth login %s' to authenticate", c.name)
+		}
 		return fmt.Errorf("SSE connection failed with status %d", resp.StatusCode)
 	}
 
 	c.connected.Store(true)
 	c.mu.Lock()
+	c.stream = resp.Body
 	c.lastError = ""
 	c.lastActivity = time.Now()
 	c.mu.Unlock()
@@ -446,6 +465,11 @@ func (c *SSEClient) retryRequest(fn func() (json.RawMessage, error), method stri
 
 		lastErr = err
 
+		// The caller gave up; retrying would only outlive its deadline
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+
 		// Check if error is retryable
 		errStr := err.Error()
 		isNetworkError := strings.Contains(errStr, "connection refused") ||
@@ -479,6 +503,19 @@ func (c *SSEClient) sendRequest(method string, params json.RawMessage) (json.Raw
 }
 
 func (c *SSEClient) sendRequestWithTimeout(method string, params json.RawMessage, timeout time.Duration) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := c.sendRequestContext(ctx, method, params)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out", method)
+	}
+	return result, err
+}
+
+// sendRequestContext sends a JSON-RPC request and waits for its response,
+// either in the POST body or over the SSE stream, until ctx is done.
+func (c *SSEClient) sendRequestContext(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
 	// Wrap in retry logic for network resilience
 	return c.retryRequest(func() (json.RawMessage, error) {
 		c.mu.Lock()
@@ -512,7 +549,7 @@ func (c *SSEClient) sendRequestWithTimeout(method string, params json.RawMessage
 			return nil, fmt.Errorf("failed to marshal request: %w", err)
 		}
 
-		httpReq, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
 		if err != nil {
 			c.pendingMu.Lock()
 			delete(c.pending, reqID)
@@ -521,12 +558,15 @@ func (c *SSEClient) sendRequestWithTimeout(method string, params json.RawMessage
 		}
 
 		httpReq.Header.Set("Content-Type", "application/json")
-		for k, v := range c.headers {
-			httpReq.Header.Set(k, v)
+		c.setHeaders(httpReq)
+		if id := logger.RequestID(ctx); id != "" {
+			httpReq.Header.Set(logger.RequestIDHeader, id)
 		}
 
-		client := &http.Client{Timeout: timeout}
-		resp, err := client.Do(httpReq)
+		// ctx bounds the request, so skip the client-wide timeout but keep
+		// the server's pooled transport
+		httpClient := &http.Client{Transport: c.httpClient.Transport}
+		resp, err := httpClient.Do(httpReq)
 		if err != nil {
 			c.pendingMu.Lock()
 			delete(c.pending, reqID)
@@ -544,7 +584,7 @@ func (c *SSEClient) sendRequestWithTimeout(method string, params json.RawMessage
 		}
 
 		// Check if response is in body (synchronous) or via SSE (async)
-		bodyBytes, err := io.ReadAll(resp.Body)
+		bodyBytes, err := readBody(resp.Body)
 		if err != nil {
 			c.pendingMu.Lock()
 			delete(c.pending, reqID)
@@ -576,11 +616,11 @@ func (c *SSEClient) sendRequestWithTimeout(method string, params json.RawMessage
 				return nil, fmt.Errorf("%s error: %s", method, result.Error.Message)
 			}
 			return result.Result, nil
-		case <-time.After(timeout):
+		case <-ctx.Done():
 			c.pendingMu.Lock()
 			delete(c.pending, reqID)
 			c.pendingMu.Unlock()
-			return nil, fmt.Errorf("%s timed out", method)
+			return nil, fmt.Errorf("%s: %w", method, ctx.Err())
 		}
 	}, method)
 }
@@ -592,6 +632,17 @@ func (c *SSEClient) initialize() error {
 	return err
 }
 
+// setHeaders applies the configured headers to a request, then the current
+// OAuth token if the server uses OAuth
+func (c *SSEClient) setHeaders(req *http.Request) {
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	if authHeader := oauthAuthorizationHeader(c.name, c.oauthConfig); authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+}
+
 // GetName returns the client name
 func (c *SSEClient) GetName() string {
 	return c.name
@@ -637,6 +688,16 @@ func (c *SSEClient) CallTool(toolName string, arguments map[string]interface{})
 	return c.sendRequest("tools/call", params)
 }
 
+// CallToolContext calls a tool on the MCP server, giving up when ctx is done.
+func (c *SSEClient) CallToolContext(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	params, err := toolCallParams(ctx, toolName, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sendRequestContext(ctx, "tools/call", params)
+}
+
 // ListPrompts requests the list of prompts from the MCP server
 func (c *SSEClient) ListPrompts() ([]map[string]interface{}, error) {
 	result, err := c.sendRequest("prompts/list", nil)
@@ -795,11 +856,21 @@ func (c *SSEClient) GetStderrOutput() string {
 	return ""
 }
 
-// Close closes the SSE connection
+// Close closes the SSE stream and the server's pooled connections
 func (c *SSEClient) Close() error {
 	c.closing.Store(true)
 	close(c.stopChan)
 	c.connected.Store(false)
+	c.mu.Lock()
+	stream := c.stream
+	c.stream = nil
+	c.mu.Unlock()
+	if stream != nil {
+		// Unblocks the event loop, which otherwise waits for the next event
+		stream.Close()
+	}
+	c.httpClient.CloseIdleConnections()
+	c.sseTransport.CloseIdleConnections()
 	return nil
 }
 
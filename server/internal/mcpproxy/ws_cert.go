@@ -0,0 +1,201 @@
+package mcpproxy
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/slavetypes"
+)
+
+const (
+	// DefaultCertRenewBefore is how long before expiry a slave renews its
+	// client certificate
+	DefaultCertRenewBefore = 30 * 24 * time.Hour
+
+	certCheckInterval = 12 * time.Hour
+	certRenewTimeout  = 60 * time.Second
+)
+
+// SetCertRenewBefore sets how long before expiry the client certificate is
+// renewed. Zero or less restores DefaultCertRenewBefore.
+func (c *WSClient) SetCertRenewBefore(d time.Duration) {
+	if d <= 0 {
+		d = DefaultCertRenewBefore
+	}
+	c.certMu.Lock()
+	c.certRenewBefore = d
+	c.certMu.Unlock()
+}
+
+// certRotationLoop periodically renews the client certificate when it nears
+// expiry. The first check waits a minute so the connection can settle.
+func (c *WSClient) certRotationLoop() {
+	timer := time.NewTimer(time.Minute)
+	defer timer.Stop()
+
+	for range timer.C {
+		if err := c.checkCertificate(); err != nil {
+			slog.Error("Client certificate renewal failed", "error", err, "cert_path", c.certPath)
+			c.SetError(fmt.Sprintf("certificate renewal failed: %v", err))
+		}
+		timer.Reset(certCheckInterval)
+	}
+}
+
+// checkCertificate renews the client certificate if it expires within the
+// renewal window
+func (c *WSClient) checkCertificate() error {
+	cert, err := loadCertificate(c.certPath)
+	if err != nil {
+		return err
+	}
+
+	c.certMu.Lock()
+	renewBefore := c.certRenewBefore
+	c.certMu.Unlock()
+
+	remaining := time.Until(cert.NotAfter)
+	if remaining > renewBefore {
+		slog.Debug("Client certificate not due for renewal", "expires_at", cert.NotAfter.Format(time.RFC3339))
+		return nil
+	}
+
+	slog.Info("Client certificate nearing expiry, requesting renewal",
+		"expires_at", cert.NotAfter.Format(time.RFC3339),
+		"remaining", remaining.Round(time.Hour))
+	return c.renewCertificate(cert.Subject.CommonName)
+}
+
+// renewCertificate asks the master to sign a CSR for a fresh key over the
+// current authenticated connection, then swaps the new key and certificate
+// in on disk. Later (re)connections pick them up without re-pairing; the
+// current connection is unaffected.
+func (c *WSClient) renewCertificate(commonName string) error {
+	key, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: commonName},
+		DNSNames: []string{commonName},
+	}, key)
+	if err != nil {
+		return fmt.Errorf("failed to create CSR: %w", err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	data, err := json.Marshal(slavetypes.CertRenewMessage{CSR: string(csrPEM)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal renewal request: %w", err)
+	}
+
+	msgID := c.getNextID()
+	respChan := make(chan MCPResponse, 1)
+	c.pendingMu.Lock()
+	c.pending[msgID] = respChan
+	c.pendingMu.Unlock()
+
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, msgID)
+		c.pendingMu.Unlock()
+	}()
+
+	if err := c.sendMessage(slavetypes.Message{
+		Type:      slavetypes.MessageTypeCertRenew,
+		ID:        msgID,
+		Timestamp: time.Now(),
+		Data:      data,
+	}); err != nil {
+		return fmt.Errorf("failed to send renewal request: %w", err)
+	}
+
+	var resp MCPResponse
+	select {
+	case resp = <-respChan:
+	case <-time.After(certRenewTimeout):
+		return fmt.Errorf("master did not answer renewal request within %s", certRenewTimeout)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("master refused renewal: %s", resp.Error.Message)
+	}
+
+	var renewed slavetypes.CertRenewResponse
+	if err := json.Unmarshal(resp.Result, &renewed); err != nil {
+		return fmt.Errorf("failed to parse renewal response: %w", err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if _, err := tls.X509KeyPair([]byte(renewed.Certificate), keyPEM); err != nil {
+		return fmt.Errorf("renewed certificate does not match key: %w", err)
+	}
+
+	if err := swapFiles(map[string]fileContent{
+		c.keyPath:  {keyPEM, 0600},
+		c.certPath: {[]byte(renewed.Certificate), 0644},
+		c.caPath:   {[]byte(renewed.CACertificate), 0644},
+	}); err != nil {
+		return err
+	}
+
+	slog.Info("Client certificate renewed", "expires_at", renewed.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// loadCertificate parses the first certificate in a PEM file
+func loadCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client cert: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode client cert PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+type fileContent struct {
+	data []byte
+	perm os.FileMode
+}
+
+// swapFiles writes every file to a temporary path first and only renames
+// them into place once all writes succeeded, so a failed renewal leaves
+// the old key and certificate untouched
+func swapFiles(files map[string]fileContent) error {
+	written := make([]string, 0, len(files))
+	defer func() {
+		for _, tmp := range written {
+			os.Remove(tmp)
+		}
+	}()
+
+	for path, f := range files {
+		if len(f.data) == 0 {
+			continue
+		}
+		tmp := path + ".new"
+		if err := os.WriteFile(tmp, f.data, f.perm); err != nil {
+			return fmt.Errorf("failed to write %s: %w", tmp, err)
+		}
+		written = append(written, tmp)
+	}
+
+	for _, tmp := range written {
+		if err := os.Rename(tmp, tmp[:len(tmp)-len(".new")]); err != nil {
+			return fmt.Errorf("failed to install %s: %w", tmp, err)
+		}
+	}
+	written = nil
+	return nil
+}
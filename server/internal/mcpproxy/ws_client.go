@@ -1,6 +1,7 @@
 package mcpproxy
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -12,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/logger"
 	"github.com/diane-assistant/diane/internal/slavetypes"
 	"github.com/gorilla/websocket"
 )
@@ -22,6 +24,21 @@ type ToolProvider interface {
 	CallTool(name string, arguments map[string]interface{}) (map[string]interface{}, error)
 }
 
+// ContextToolProvider is implemented by tool providers that can tag a call's
+// logs with the request ID the master sent along with it
+type ContextToolProvider interface {
+	CallToolContext(ctx context.Context, name string, arguments map[string]interface{}) (map[string]interface{}, error)
+}
+
+// HealthReporter is implemented by tool providers that can describe the
+// local instance's status, which is sent to the master with log requests
+type HealthReporter interface {
+	HealthStatus() interface{}
+}
+
+// maxLogEntries caps how many log entries are returned to the master
+const maxLogEntries = 1000
+
 // WSClient is a WebSocket-based MCP client for remote slave connections
 type WSClient struct {
 	name         string
@@ -51,14 +68,21 @@ type WSClient struct {
 	lastError           string
 
 	// TLS config
-	certPath string
-	keyPath  string
-	caPath   string
+	certPath        string
+	keyPath         string
+	caPath          string
+	certMu          sync.Mutex
+	certRenewBefore time.Duration // Renew the client cert this long before it expires
 
 	// Reverse proxy: master tools flowing to this slave
 	proxy          *Proxy                        // The slave's local proxy, for registering master tool clients
 	masterClients  map[string]*MasterProxyClient // serverName -> client
 	masterClientMu sync.Mutex
+
+	// Master clock, as seen in the timestamp of its last message
+	clockMu      sync.Mutex
+	masterOffset time.Duration
+	masterSeenAt time.Time
 }
 
 // NewWSClient creates a new WebSocket MCP client
@@ -74,6 +98,8 @@ func NewWSClient(name, hostname, masterAddr, certPath, keyPath, caPath, version
 		certPath:     certPath,
 		keyPath:      keyPath,
 		caPath:       caPath,
+
+		certRenewBefore: DefaultCertRenewBefore,
 	}
 
 	// Connect to master
@@ -87,6 +113,9 @@ func NewWSClient(name, hostname, masterAddr, certPath, keyPath, caPath, version
 	// Start heartbeat
 	go client.heartbeatLoop()
 
+	// Renew the client certificate before it expires
+	go client.certRotationLoop()
+
 	return client, nil
 }
 
@@ -212,6 +241,14 @@ func (c *WSClient) readLoop() {
 
 // handleMessage processes incoming messages from master
 func (c *WSClient) handleMessage(msg slavetypes.Message) {
+	if !msg.Timestamp.IsZero() {
+		now := time.Now()
+		c.clockMu.Lock()
+		c.masterOffset = msg.Timestamp.Sub(now)
+		c.masterSeenAt = now
+		c.clockMu.Unlock()
+	}
+
 	switch msg.Type {
 	case slavetypes.MessageTypeToolCall:
 		c.handleToolCall(msg)
@@ -225,11 +262,24 @@ func (c *WSClient) handleMessage(msg slavetypes.Message) {
 		c.handleUpgrade(msg)
 	case slavetypes.MessageTypeMasterTools:
 		c.handleMasterTools(msg)
+	case slavetypes.MessageTypeLogsRequest:
+		c.handleLogsRequest(msg)
+	case slavetypes.MessageTypePing:
+		c.handlePing(msg)
 	default:
 		slog.Warn("Unknown message type", "type", msg.Type)
 	}
 }
 
+// MasterClockOffset returns how far the master's clock was ahead of the
+// local one when its last message arrived (so it includes the message's
+// transit time), and when that was. ok is false until a message arrives.
+func (c *WSClient) MasterClockOffset() (offset time.Duration, at time.Time, ok bool) {
+	c.clockMu.Lock()
+	defer c.clockMu.Unlock()
+	return c.masterOffset, c.masterSeenAt, !c.masterSeenAt.IsZero()
+}
+
 // handleToolCall processes tool call request from master
 func (c *WSClient) handleToolCall(msg slavetypes.Message) {
 	var callMsg slavetypes.ToolCallMessage
@@ -239,8 +289,11 @@ func (c *WSClient) handleToolCall(msg slavetypes.Message) {
 		return
 	}
 
+	ctx := logger.WithRequestID(context.Background(), callMsg.RequestID)
+	slog.DebugContext(ctx, "Handling tool call from master", "tool", callMsg.Tool)
+
 	// Execute tool locally
-	result, err := c.executeLocalTool(callMsg.Tool, callMsg.Arguments)
+	result, err := c.executeLocalTool(ctx, callMsg.Tool, callMsg.Arguments)
 
 	// Send response
 	response := slavetypes.ToolCallResponse{
@@ -265,13 +318,68 @@ func (c *WSClient) handleToolCall(msg slavetypes.Message) {
 	}
 }
 
+// handleLogsRequest returns the tail of the local log buffer and this
+// instance's status to the master
+func (c *WSClient) handleLogsRequest(msg slavetypes.Message) {
+	var req slavetypes.LogsRequestMessage
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		c.sendErrorResponse(msg.ID, "Invalid logs request")
+		return
+	}
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		c.sendErrorResponse(msg.ID, err.Error())
+		return
+	}
+	if req.Limit <= 0 || req.Limit > maxLogEntries {
+		req.Limit = maxLogEntries
+	}
+
+	response := slavetypes.LogsResponse{
+		Hostname: c.hostname,
+		Entries:  logger.Recent(req.Limit, level),
+	}
+	if reporter, ok := c.toolProvider.(HealthReporter); ok {
+		if status, err := json.Marshal(reporter.HealthStatus()); err == nil {
+			response.Status = status
+		}
+	}
+
+	data, _ := json.Marshal(response)
+	if err := c.sendMessage(slavetypes.Message{
+		Type:      slavetypes.MessageTypeResponse,
+		ID:        msg.ID,
+		Timestamp: time.Now(),
+		Data:      data,
+	}); err != nil {
+		slog.Error("Failed to send logs response", "error", err)
+	}
+}
+
+// handlePing answers the master's round-trip check
+func (c *WSClient) handlePing(msg slavetypes.Message) {
+	if err := c.sendMessage(slavetypes.Message{
+		Type:      slavetypes.MessageTypeResponse,
+		ID:        msg.ID,
+		Timestamp: time.Now(),
+	}); err != nil {
+		slog.Error("Failed to answer ping", "error", err)
+	}
+}
+
 // executeLocalTool executes a tool on the local Diane instance
-func (c *WSClient) executeLocalTool(tool string, arguments map[string]interface{}) (json.RawMessage, error) {
+func (c *WSClient) executeLocalTool(ctx context.Context, tool string, arguments map[string]interface{}) (json.RawMessage, error) {
 	if c.toolProvider == nil {
 		return nil, fmt.Errorf("tool provider not initialized")
 	}
 
-	result, err := c.toolProvider.CallTool(tool, arguments)
+	var result map[string]interface{}
+	var err error
+	if p, ok := c.toolProvider.(ContextToolProvider); ok {
+		result, err = p.CallToolContext(ctx, tool, arguments)
+	} else {
+		result, err = c.toolProvider.CallTool(tool, arguments)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -677,6 +785,14 @@ func (c *WSClient) CallTool(toolName string, arguments map[string]interface{}) (
 	return nil, fmt.Errorf("CallTool should not be called on WSClient directly")
 }
 
+// CallToolContext runs CallTool and stops waiting for it once ctx is done.
+// The call itself is not cancelled and finishes in the background.
+func (c *WSClient) CallToolContext(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	return CallWithContext(ctx, func() (json.RawMessage, error) {
+		return c.CallTool(toolName, arguments)
+	})
+}
+
 // ListPrompts returns the list of available prompts
 func (c *WSClient) ListPrompts() ([]map[string]interface{}, error) {
 	return []map[string]interface{}{}, nil
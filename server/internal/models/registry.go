@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 const (
@@ -89,7 +91,7 @@ type CacheData struct {
 func NewRegistry(cacheDir string) *Registry {
 	if cacheDir == "" {
 		home, _ := os.UserHomeDir()
-		cacheDir = filepath.Join(home, ".diane")
+		cacheDir = filepath.Join(home, config.DirName())
 	}
 	return &Registry{
 		providers:  make(map[string]Provider),
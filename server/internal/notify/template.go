@@ -0,0 +1,88 @@
+// Package notify renders notification message templates. A template is a
+// Go text/template executed against a map of values, so job alerts and
+// other messages share one format instead of each call site building its
+// own text.
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/db"
+)
+
+// OutputSnippetBytes is how much of a run's output a job's template data
+// carries: the tail, where the error usually is
+const OutputSnippetBytes = 500
+
+// Parse checks that body is a valid template
+func Parse(name, body string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %q: %w", name, err)
+	}
+	return t, nil
+}
+
+// Render executes the template body against data
+func Render(name, body string, data map[string]any) (string, error) {
+	t, err := Parse(name, body)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render template %q: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// JobData is the data a job's templates render against:
+//
+//	job_name   the job's name
+//	exit_code  the run's exit code, -1 when it didn't exit
+//	error      why the run failed, if it didn't just exit non-zero
+//	output     the last OutputSnippetBytes of stderr, or of stdout when
+//	           stderr is empty
+//	timestamp  when the run ended (or started), RFC3339
+func JobData(job *db.Job, e *db.JobExecution) map[string]any {
+	exitCode := -1
+	if e.ExitCode != nil {
+		exitCode = *e.ExitCode
+	}
+	errMsg := ""
+	if e.Error != nil {
+		errMsg = *e.Error
+	}
+	output := e.Stderr
+	if strings.TrimSpace(output) == "" {
+		output = e.Stdout
+	}
+	at := e.StartedAt
+	if e.EndedAt != nil {
+		at = *e.EndedAt
+	}
+	return map[string]any{
+		"job_name":  job.Name,
+		"exit_code": exitCode,
+		"error":     errMsg,
+		"output":    Snippet(output, OutputSnippetBytes),
+		"timestamp": at.Format(time.RFC3339),
+	}
+}
+
+// Snippet trims s and keeps at most its last n bytes, marking the cut
+func Snippet(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	s = s[len(s)-n:]
+	// Don't start in the middle of a UTF-8 sequence
+	for len(s) > 0 && s[0]&0xC0 == 0x80 {
+		s = s[1:]
+	}
+	return "…" + s
+}
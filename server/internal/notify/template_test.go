@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/db"
+)
+
+func TestRender(t *testing.T) {
+	got, err := Render("alert", "{{.job_name}} failed ({{.exit_code}}){{if .output}}: {{.output}}{{end}}", map[string]any{
+		"job_name": "backup", "exit_code": 2,
+	})
+	if err != nil || got != "backup failed (2)" {
+		t.Errorf("Render = %q, %v", got, err)
+	}
+
+	if _, err := Render("bad", "{{.job_name", nil); err == nil || !strings.Contains(err.Error(), `invalid template "bad"`) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+}
+
+func TestJobData(t *testing.T) {
+	ended := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	code := 1
+	data := JobData(&db.Job{Name: "backup"}, &db.JobExecution{
+		EndedAt:  &ended,
+		ExitCode: &code,
+		Stdout:   "copied 3 files\n",
+		Stderr:   strings.Repeat("x", OutputSnippetBytes) + "disk full\n",
+	})
+	if data["job_name"] != "backup" || data["exit_code"] != 1 || data["timestamp"] != "2026-03-01T09:00:00Z" {
+		t.Errorf("unexpected data %v", data)
+	}
+	if out := data["output"].(string); !strings.HasPrefix(out, "…") || !strings.HasSuffix(out, "disk full") {
+		t.Errorf("expected the tail of stderr, got %q", out)
+	}
+
+	data = JobData(&db.Job{Name: "backup"}, &db.JobExecution{StartedAt: ended, Stdout: "ok\n"})
+	if data["exit_code"] != -1 || data["output"] != "ok" {
+		t.Errorf("expected stdout and no exit code, got %v", data)
+	}
+}
@@ -0,0 +1,90 @@
+// Package ntp measures the local clock's offset from an NTP server with a
+// single SNTP query (RFC 4330). It is meant for diagnostics, not for
+// keeping time.
+package ntp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultServer is queried when no server is given
+const DefaultServer = "pool.ntp.org"
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and
+// the Unix epoch (1970)
+const ntpEpochOffset = 2208988800
+
+// Offset queries server and returns how far its clock is ahead of the local
+// one: a positive offset means the local clock is slow. server is a host,
+// optionally with a port (default 123).
+func Offset(server string, timeout time.Duration) (time.Duration, error) {
+	if server == "" {
+		server = DefaultServer
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach %s: %w", server, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	// Version 4, client mode; everything else zero
+	req := make([]byte, 48)
+	req[0] = 4<<3 | 3
+
+	sent := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", server, err)
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	received := time.Now()
+	if err != nil {
+		return 0, fmt.Errorf("no answer from %s: %w", server, err)
+	}
+
+	return offsetFromResponse(resp[:n], sent, received)
+}
+
+// offsetFromResponse computes the clock offset from a server response and
+// the local send and receive times, as ((T2 - T1) + (T3 - T4)) / 2
+func offsetFromResponse(resp []byte, sent, received time.Time) (time.Duration, error) {
+	if len(resp) < 48 {
+		return 0, fmt.Errorf("short NTP response (%d bytes)", len(resp))
+	}
+	if mode := resp[0] & 7; mode != 4 {
+		return 0, fmt.Errorf("unexpected NTP mode %d", mode)
+	}
+	if resp[1] == 0 {
+		return 0, fmt.Errorf("NTP server refused the query (kiss-o'-death)")
+	}
+
+	serverReceived := ntpTime(resp[32:40])
+	serverSent := ntpTime(resp[40:48])
+	if serverSent.IsZero() {
+		return 0, fmt.Errorf("NTP response has no transmit time")
+	}
+
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+// ntpTime decodes a 64-bit NTP timestamp, or returns the zero time for an
+// unset one
+func ntpTime(b []byte) time.Time {
+	secs := binary.BigEndian.Uint32(b[:4])
+	frac := binary.BigEndian.Uint32(b[4:])
+	if secs == 0 && frac == 0 {
+		return time.Time{}
+	}
+	nanos := (int64(frac) * 1e9) >> 32
+	return time.Unix(int64(secs)-ntpEpochOffset, nanos)
+}
@@ -0,0 +1,82 @@
+package ntp
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+func putNTPTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b[:4], uint32(t.Unix()+ntpEpochOffset))
+	binary.BigEndian.PutUint32(b[4:], uint32((int64(t.Nanosecond())<<32)/1e9))
+}
+
+// fakeServer answers SNTP queries with a clock running ahead by skew
+func fakeServer(t *testing.T, skew time.Duration) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			now := time.Now().Add(skew)
+			resp := make([]byte, 48)
+			resp[0] = 4<<3 | 4
+			resp[1] = 2
+			putNTPTime(resp[32:40], now)
+			putNTPTime(resp[40:48], now)
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestOffset(t *testing.T) {
+	for _, skew := range []time.Duration{time.Hour, -90 * time.Second, 0} {
+		offset, err := Offset(fakeServer(t, skew), 2*time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := offset - skew; diff > 100*time.Millisecond || diff < -100*time.Millisecond {
+			t.Errorf("Offset = %v, want about %v", offset, skew)
+		}
+	}
+}
+
+func TestOffsetFromResponseRejectsBadResponses(t *testing.T) {
+	now := time.Now()
+	valid := func() []byte {
+		resp := make([]byte, 48)
+		resp[0] = 4<<3 | 4
+		resp[1] = 2
+		putNTPTime(resp[40:48], now)
+		return resp
+	}
+
+	clientMode := valid()
+	clientMode[0] = 4<<3 | 3
+	kissOfDeath := valid()
+	kissOfDeath[1] = 0
+	noTransmit := valid()
+	copy(noTransmit[40:48], make([]byte, 8))
+
+	for name, resp := range map[string][]byte{
+		"short":       valid()[:20],
+		"client mode": clientMode,
+		"kiss":        kissOfDeath,
+		"no transmit": noTransmit,
+	} {
+		if _, err := offsetFromResponse(resp, now, now); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" at 1-M, from the worked example in the standard's tutorials
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := formatBits(0); got != 0b101010000010010 {
+		t.Errorf("format bits for M/mask 0 = %015b", got)
+	}
+	if got := formatBits(5); got != 0b100000011001110 {
+		t.Errorf("format bits for M/mask 5 = %015b", got)
+	}
+
+	c := newCode(7)
+	c.drawFunctionPatterns()
+	var bits int
+	for i := 17; i >= 0; i-- {
+		bits <<= 1
+		if c.modules[i/3][c.size-11+i%3] {
+			bits |= 1
+		}
+	}
+	if bits != 0b000111110010010100 {
+		t.Errorf("version 7 bits = %018b", bits)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"diane",
+		"diane-pair:?code=123-456&host=laptop&master=https%3A%2F%2Fmaster.example.com%3A8766",
+		strings.Repeat("x", 150),
+	} {
+		q, err := Encode(text)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(text), err)
+		}
+		if q.Size != q.Version*4+17 {
+			t.Errorf("size %d for version %d", q.Size, q.Version)
+		}
+		if got := decode(t, q); got != text {
+			t.Errorf("round trip of version %d code = %q, want %q", q.Version, got, text)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("x", 214)); err == nil {
+		t.Error("expected an error for text over the version 10 capacity")
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	q, err := Encode("diane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(q.Terminal(), "\n"), "\n")
+	if want := (q.Size + 2*quietZone + 1) / 2; len(lines) != want {
+		t.Errorf("got %d lines, want %d", len(lines), want)
+	}
+	if !strings.HasPrefix(lines[0], "██") {
+		t.Errorf("expected the quiet zone to be drawn light, got %q", lines[0])
+	}
+}
+
+// decode reads a code back: the format bits give the mask, the data modules
+// are read in placement order, then the blocks are de-interleaved and the
+// byte mode segment parsed. Error correction is checked, not applied.
+func decode(t *testing.T, q *Code) string {
+	t.Helper()
+	c := newCode(q.Version)
+	c.drawFunctionPatterns()
+
+	var format int
+	for i := 14; i >= 9; i-- {
+		format <<= 1
+		if q.Modules[8][14-i] {
+			format |= 1
+		}
+	}
+	for _, pos := range [][2]int{{7, 8}, {8, 8}, {8, 7}} {
+		format <<= 1
+		if q.Modules[pos[1]][pos[0]] {
+			format |= 1
+		}
+	}
+	for i := 5; i >= 0; i-- {
+		format <<= 1
+		if q.Modules[i][8] {
+			format |= 1
+		}
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("unreadable format bits %015b", format)
+	}
+
+	c.modules = q.Modules
+	c.applyMask(mask)
+	defer c.applyMask(mask)
+
+	var raw bitBuffer
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.size; vert++ {
+			y := vert
+			if upward {
+				y = c.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if x := right - j; !c.isFunction[y][x] {
+					raw = append(raw, c.modules[y][x])
+				}
+			}
+		}
+	}
+	codewords := raw[:len(raw)/8*8].bytes()
+
+	info := versions[q.Version]
+	nBlocks := info.blocks1 + info.blocks2
+	blocks := make([][]byte, nBlocks)
+	pos := 0
+	for i := 0; i < max(info.dataLen1, info.dataLen2); i++ {
+		for b := 0; b < nBlocks; b++ {
+			n := info.dataLen1
+			if b >= info.blocks1 {
+				n = info.dataLen2
+			}
+			if i < n {
+				blocks[b] = append(blocks[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+	ec := make([][]byte, nBlocks)
+	for i := 0; i < info.ecPerBlock; i++ {
+		for b := 0; b < nBlocks; b++ {
+			ec[b] = append(ec[b], codewords[pos])
+			pos++
+		}
+	}
+	var data []byte
+	for b := range blocks {
+		if !bytes.Equal(rsRemainder(blocks[b], rsDivisor(info.ecPerBlock)), ec[b]) {
+			t.Errorf("block %d: error correction mismatch", b)
+		}
+		data = append(data, blocks[b]...)
+	}
+
+	var bits bitBuffer
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if bits[i] {
+				v |= 1
+			}
+		}
+		bits = bits[n:]
+		return v
+	}
+	if mode := read(4); mode != 0x4 {
+		t.Fatalf("mode %x, want byte mode", mode)
+	}
+	countBits := 8
+	if q.Version >= 10 {
+		countBits = 16
+	}
+	n := read(countBits)
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = byte(read(8))
+	}
+	return string(out)
+}
@@ -13,6 +13,9 @@ import (
 	"time"
 )
 
+// clientCertValidDays is how long issued and renewed slave certificates last
+const clientCertValidDays = 365
+
 // CertificateAuthority manages slave certificates
 type CertificateAuthority struct {
 	caCert   *x509.Certificate
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/diane-assistant/diane/internal/db"
 	"github.com/diane-assistant/diane/internal/mcpproxy"
+	"github.com/diane-assistant/diane/internal/slavetypes"
 	"github.com/diane-assistant/diane/internal/store"
 )
 
@@ -18,6 +21,18 @@ type Manager struct {
 	db           store.SlaveStore
 	pairing      *PairingService
 	contextStore store.ContextStore
+
+	// Routing of tools exposed by both the master and a slave (see routing.go)
+	routeMu sync.Mutex
+	routing RoutingPreference
+	rrNext  map[string]int   // tool name -> next round-robin offset
+	served  map[string]int64 // host -> calls served through RouteToolCall
+
+	// A disconnected slave stays registered with the proxy for
+	// reconnectGrace, so calls made meanwhile can wait for it
+	reconnectGrace time.Duration
+	offlineMu      sync.Mutex
+	offlineTimers  map[string]*time.Timer // hostname -> pending proxy unregistration
 }
 
 // NewManager creates a new slave manager
@@ -31,6 +46,12 @@ func NewManager(slaveStore store.SlaveStore, proxy *mcpproxy.Proxy, ca *Certific
 		db:           slaveStore,
 		pairing:      pairing,
 		contextStore: contextStore,
+		routing:      RouteLocalFirst,
+		rrNext:       make(map[string]int),
+		served:       make(map[string]int64),
+
+		reconnectGrace: DefaultReconnectGrace,
+		offlineTimers:  make(map[string]*time.Timer),
 	}
 
 	// Start monitoring registry notifications
@@ -54,6 +75,8 @@ func (m *Manager) StartServer(addr string, ca *CertificateAuthority) error {
 		server.SetContextStore(m.contextStore)
 	}
 
+	server.SetReconnectGrace(m.reconnectGrace)
+
 	m.server = server
 	slog.Info("Slave server initialized", "addr", addr)
 	return nil
@@ -90,6 +113,18 @@ func (m *Manager) handleSlaveConnected(notification *RegistryNotification) {
 		return
 	}
 
+	// A slave reconnecting within the grace window is still registered
+	// with its old connection; replace it
+	m.offlineMu.Lock()
+	if timer, ok := m.offlineTimers[notification.HostID]; ok {
+		timer.Stop()
+		delete(m.offlineTimers, notification.HostID)
+	}
+	m.offlineMu.Unlock()
+	if _, ok := m.proxy.GetClient(notification.HostID); ok {
+		m.proxy.UnregisterSlaveClient(notification.HostID)
+	}
+
 	// Create a SlaveProxyClient that wraps the slave connection
 	client := NewSlaveProxyClient(notification.HostID, conn, m.server)
 
@@ -104,15 +139,48 @@ func (m *Manager) handleSlaveConnected(notification *RegistryNotification) {
 	slog.Info("Slave registered with MCP proxy", "hostname", notification.HostID)
 }
 
-// handleSlaveDisconnected removes a slave from the MCP proxy
+// handleSlaveDisconnected removes a slave from the MCP proxy once it has
+// been gone for longer than the reconnect grace window
 func (m *Manager) handleSlaveDisconnected(notification *RegistryNotification) {
+	hostname := notification.HostID
 	slog.Info("Slave disconnected, unregistering from proxy",
-		"hostname", notification.HostID)
+		"hostname", hostname,
+		"grace", m.reconnectGrace)
 
-	if err := m.proxy.UnregisterSlaveClient(notification.HostID); err != nil {
-		slog.Warn("Failed to unregister slave from proxy",
-			"hostname", notification.HostID,
-			"error", err)
+	unregister := func() {
+		m.offlineMu.Lock()
+		delete(m.offlineTimers, hostname)
+		m.offlineMu.Unlock()
+
+		if m.registry.IsConnected(hostname) {
+			return
+		}
+		if err := m.proxy.UnregisterSlaveClient(hostname); err != nil {
+			slog.Warn("Failed to unregister slave from proxy",
+				"hostname", hostname,
+				"error", err)
+		}
+	}
+	if m.reconnectGrace <= 0 {
+		unregister()
+		return
+	}
+
+	m.offlineMu.Lock()
+	if timer, ok := m.offlineTimers[hostname]; ok {
+		timer.Stop()
+	}
+	m.offlineTimers[hostname] = time.AfterFunc(m.reconnectGrace, unregister)
+	m.offlineMu.Unlock()
+}
+
+// SetReconnectGrace sets how long tool calls to a disconnected slave wait for
+// it to reconnect before failing with "slave offline". Zero or less fails
+// immediately. Call before StartServer.
+func (m *Manager) SetReconnectGrace(grace time.Duration) {
+	m.reconnectGrace = grace
+	if m.server != nil {
+		m.server.SetReconnectGrace(grace)
 	}
 }
 
@@ -182,6 +250,24 @@ func (m *Manager) RestartSlave(hostname string) error {
 	return m.server.SendRestartCommand(hostname)
 }
 
+// GetSlaveLogs fetches a connected slave's recent logs and status
+func (m *Manager) GetSlaveLogs(hostname string, limit int, level string) (*slavetypes.LogsResponse, error) {
+	if m.server == nil {
+		return nil, fmt.Errorf("slave server not initialized")
+	}
+
+	return m.server.RequestLogs(hostname, limit, level)
+}
+
+// PingSlave measures the round-trip time to a connected slave
+func (m *Manager) PingSlave(hostname string) (time.Duration, error) {
+	if m.server == nil {
+		return 0, fmt.Errorf("slave server not initialized")
+	}
+
+	return m.server.Ping(hostname)
+}
+
 // UpgradeSlave sends an upgrade command to a specific slave
 func (m *Manager) UpgradeSlave(hostname string) error {
 	if m.server == nil {
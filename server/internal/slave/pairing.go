@@ -3,8 +3,11 @@ package slave
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"fmt"
+	"log/slog"
 	"math/big"
+	"path"
 	"sync"
 	"time"
 
@@ -18,6 +21,11 @@ type PairingService struct {
 	pendingRequests map[string]*PairingRequestInfo
 	mu              sync.RWMutex
 	notifyChannel   chan *PairingNotification
+
+	// Auto-approval rules; both empty (the default) means every request
+	// waits for 'diane slave approve'.
+	autoApproveHosts []string
+	autoApproveToken string
 }
 
 // PairingRequestInfo holds in-memory state for a pairing request
@@ -145,7 +153,7 @@ func (ps *PairingService) ApprovePairingRequest(hostID, pairingCode string) (cer
 	}
 
 	// Sign the CSR
-	certPEM, serialNumber, err := ps.ca.SignCSR(req.CSR, hostID, 365)
+	certPEM, serialNumber, err := ps.ca.SignCSR(req.CSR, hostID, clientCertValidDays)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to sign CSR: %w", err)
 	}
@@ -164,7 +172,7 @@ func (ps *PairingService) ApprovePairingRequest(hostID, pairingCode string) (cer
 
 	// Create or update slave server record in database
 	now := time.Now()
-	expiresAt := now.AddDate(0, 0, 365)
+	expiresAt := now.AddDate(0, 0, clientCertValidDays)
 
 	if existingSlave != nil {
 		// Update existing slave with new credentials
@@ -199,6 +207,60 @@ func (ps *PairingService) ApprovePairingRequest(hostID, pairingCode string) (cer
 	return certPEM, caCertPEM, nil
 }
 
+// SetAutoApprove configures which pairing requests are approved without an
+// operator: hostnames matching one of the glob patterns (path.Match syntax,
+// e.g. "lab-*") or requests carrying the pre-shared token. Empty patterns and
+// token disable auto-approval.
+func (ps *PairingService) SetAutoApprove(hostPatterns []string, token string) error {
+	for _, pattern := range hostPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid auto-approve host pattern %q: %w", pattern, err)
+		}
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.autoApproveHosts = hostPatterns
+	ps.autoApproveToken = token
+	return nil
+}
+
+// autoApproveRule returns the rule that lets hostID's request be approved
+// automatically, or "" if none does.
+func (ps *PairingService) autoApproveRule(hostID, token string) string {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	if ps.autoApproveToken != "" && token != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(ps.autoApproveToken)) == 1 {
+		return "token"
+	}
+	for _, pattern := range ps.autoApproveHosts {
+		if ok, _ := path.Match(pattern, hostID); ok {
+			return "host " + pattern
+		}
+	}
+	return ""
+}
+
+// AutoApprove approves a just-created pairing request if it matches an
+// auto-approval rule, issuing the certificate the slave collects when it next
+// polls. It reports whether the request was approved.
+func (ps *PairingService) AutoApprove(hostID, pairingCode, token string) bool {
+	rule := ps.autoApproveRule(hostID, token)
+	if rule == "" {
+		return false
+	}
+
+	if _, _, err := ps.ApprovePairingRequest(hostID, pairingCode); err != nil {
+		slog.Error("Failed to auto-approve pairing request", "hostname", hostID, "rule", rule, "error", err)
+		return false
+	}
+
+	slog.Info("Auto-approved pairing request", "hostname", hostID, "code", pairingCode, "rule", rule)
+	return true
+}
+
 // GetPairingStatus retrieves the status of a pairing request by code
 func (ps *PairingService) GetPairingStatus(pairingCode string) (string, string, error) {
 	// First check in-memory pending requests
@@ -0,0 +1,42 @@
+package slave
+
+import "testing"
+
+func TestAutoApproveRule(t *testing.T) {
+	ps := &PairingService{}
+	if rule := ps.autoApproveRule("lab-1", ""); rule != "" {
+		t.Fatalf("auto-approval should be off by default, got %q", rule)
+	}
+
+	if err := ps.SetAutoApprove([]string{"["}, ""); err == nil {
+		t.Fatal("expected an error for an invalid host pattern")
+	}
+
+	if err := ps.SetAutoApprove([]string{"lab-*", "*.home.lan"}, "s3cret"); err != nil {
+		t.Fatalf("SetAutoApprove: %v", err)
+	}
+
+	tests := []struct {
+		host, token, want string
+	}{
+		{"lab-1", "", "host lab-*"},
+		{"nas.home.lan", "", "host *.home.lan"},
+		{"laptop", "", ""},
+		{"laptop", "wrong", ""},
+		{"laptop", "s3cret", "token"},
+		{"lab-1", "s3cret", "token"},
+	}
+	for _, tt := range tests {
+		if got := ps.autoApproveRule(tt.host, tt.token); got != tt.want {
+			t.Errorf("autoApproveRule(%q, %q) = %q, want %q", tt.host, tt.token, got, tt.want)
+		}
+	}
+
+	// Clearing the rules turns auto-approval off again
+	if err := ps.SetAutoApprove(nil, ""); err != nil {
+		t.Fatalf("SetAutoApprove: %v", err)
+	}
+	if rule := ps.autoApproveRule("lab-1", ""); rule != "" {
+		t.Fatalf("expected no rule after clearing, got %q", rule)
+	}
+}
@@ -1,9 +1,12 @@
 package slave
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/mcpproxy"
 )
 
 // SlaveProxyClient implements the mcpproxy.Client interface for a slave connection
@@ -47,11 +50,16 @@ func (c *SlaveProxyClient) ListToolsWithTimeout(timeout time.Duration) ([]map[st
 
 // CallTool calls a tool on the slave
 func (c *SlaveProxyClient) CallTool(toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	return c.callTool(context.Background(), toolName, arguments)
+}
+
+// callTool sends a tool call to the slave via the server and waits for the
+// response, passing on the request ID of ctx
+func (c *SlaveProxyClient) callTool(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
 	// Generate call ID
 	callID := fmt.Sprintf("%s-%d", c.hostname, time.Now().UnixNano())
 
-	// Send tool call to slave via server and wait for response
-	result, err := c.server.SendToolCall(c.hostname, callID, toolName, arguments)
+	result, err := c.server.SendToolCall(ctx, c.hostname, callID, toolName, arguments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call tool: %w", err)
 	}
@@ -59,6 +67,15 @@ func (c *SlaveProxyClient) CallTool(toolName string, arguments map[string]interf
 	return result, nil
 }
 
+// CallToolContext calls a tool on the slave, returning early if ctx is done.
+// The slave protocol has no cancellation, so an abandoned call still runs to
+// completion on the slave and its result is discarded.
+func (c *SlaveProxyClient) CallToolContext(ctx context.Context, toolName string, arguments map[string]interface{}) (json.RawMessage, error) {
+	return mcpproxy.CallWithContext(ctx, func() (json.RawMessage, error) {
+		return c.callTool(ctx, toolName, arguments)
+	})
+}
+
 // ListPrompts returns the list of available prompts
 func (c *SlaveProxyClient) ListPrompts() ([]map[string]interface{}, error) {
 	// Prompts not supported on slaves for now
@@ -0,0 +1,140 @@
+package slave
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/diane-assistant/diane/internal/mcpproxy"
+)
+
+// LocalHost is the host recorded for routed calls served by the master itself
+const LocalHost = "master"
+
+// RoutingPreference decides which host serves a tool that both the master
+// and one or more slaves expose under the same name
+type RoutingPreference string
+
+const (
+	RouteLocalFirst RoutingPreference = "local-first"
+	RouteSlaveFirst RoutingPreference = "slave-first"
+	RouteRoundRobin RoutingPreference = "round-robin"
+)
+
+// ParseRoutingPreference validates a configured routing preference. An empty
+// value means local-first.
+func ParseRoutingPreference(s string) (RoutingPreference, error) {
+	switch p := RoutingPreference(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return RouteLocalFirst, nil
+	case RouteLocalFirst, RouteSlaveFirst, RouteRoundRobin:
+		return p, nil
+	default:
+		return RouteLocalFirst, fmt.Errorf("unknown tool routing %q (expected local-first, slave-first or round-robin)", s)
+	}
+}
+
+// LocalToolFunc runs a tool on the master. found is false when the master
+// doesn't have the tool, so routing moves on to the slaves.
+type LocalToolFunc func() (result interface{}, found bool, err error)
+
+// SetRoutingPreference sets how calls to tools shared with slaves are routed
+func (m *Manager) SetRoutingPreference(p RoutingPreference) {
+	m.routeMu.Lock()
+	defer m.routeMu.Unlock()
+	m.routing = p
+}
+
+// GetRoutingPreference returns the current routing preference
+func (m *Manager) GetRoutingPreference() RoutingPreference {
+	m.routeMu.Lock()
+	defer m.routeMu.Unlock()
+	return m.routing
+}
+
+// SlavesWithTool returns the connected slaves exposing a tool, sorted by hostname
+func (m *Manager) SlavesWithTool(name string) []string {
+	var hosts []string
+	for _, conn := range m.registry.GetConnectedSlaves() {
+		for _, tool := range conn.GetTools() {
+			if n, _ := tool["name"].(string); n == name {
+				hosts = append(hosts, conn.HostID)
+				break
+			}
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// RouteToolCall runs a tool that the master and the given slaves both
+// expose, trying hosts in the order set by the routing preference. A host
+// that errors (or a master without the tool) falls through to the next one.
+// It returns the result and the host that served it. ctx carries the
+// request ID passed on to slaves.
+func (m *Manager) RouteToolCall(ctx context.Context, contextName string, contextFilter mcpproxy.ContextFilter, name string, args map[string]interface{}, slaves []string, local LocalToolFunc) (interface{}, string, error) {
+	var lastErr error
+	for _, host := range m.routeOrder(name, slaves) {
+		var result interface{}
+		var err error
+		if host == LocalHost {
+			var found bool
+			result, found, err = local()
+			if !found {
+				continue
+			}
+		} else {
+			result, err = m.proxy.CallToolForContext(ctx, contextName, host+"_"+name, args, contextFilter)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Routed tool call failed, trying next host", "tool", name, "host", host, "error", err)
+			lastErr = err
+			continue
+		}
+
+		m.routeMu.Lock()
+		m.served[host]++
+		m.routeMu.Unlock()
+		slog.InfoContext(ctx, "Routed tool call", "tool", name, "host", host)
+		return result, host, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("tool not found: %s", name)
+	}
+	return nil, "", lastErr
+}
+
+// ServedCalls returns how many routed calls each host has served
+func (m *Manager) ServedCalls() map[string]int64 {
+	m.routeMu.Lock()
+	defer m.routeMu.Unlock()
+
+	served := make(map[string]int64, len(m.served))
+	for host, n := range m.served {
+		served[host] = n
+	}
+	return served
+}
+
+// routeOrder returns the hosts to try for a tool, master included
+func (m *Manager) routeOrder(name string, slaves []string) []string {
+	m.routeMu.Lock()
+	defer m.routeMu.Unlock()
+
+	hosts := make([]string, 0, len(slaves)+1)
+	switch m.routing {
+	case RouteSlaveFirst:
+		hosts = append(append(hosts, slaves...), LocalHost)
+	case RouteRoundRobin:
+		hosts = append(append(hosts, LocalHost), slaves...)
+		start := m.rrNext[name] % len(hosts)
+		m.rrNext[name] = start + 1
+		hosts = append(append([]string{}, hosts[start:]...), hosts[:start]...)
+	default:
+		hosts = append(append(hosts, LocalHost), slaves...)
+	}
+	return hosts
+}
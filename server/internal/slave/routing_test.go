@@ -0,0 +1,114 @@
+package slave
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/diane-assistant/diane/internal/mcpproxy"
+)
+
+func TestParseRoutingPreference(t *testing.T) {
+	for in, want := range map[string]RoutingPreference{
+		"":             RouteLocalFirst,
+		"local-first":  RouteLocalFirst,
+		" Slave-First": RouteSlaveFirst,
+		"round-robin":  RouteRoundRobin,
+	} {
+		got, err := ParseRoutingPreference(in)
+		if err != nil || got != want {
+			t.Errorf("ParseRoutingPreference(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseRoutingPreference("random"); err == nil {
+		t.Error("expected error for unknown preference")
+	}
+}
+
+func TestRouteOrder(t *testing.T) {
+	m := &Manager{rrNext: make(map[string]int), served: make(map[string]int64)}
+	slaves := []string{"laptop", "nas"}
+
+	m.SetRoutingPreference(RouteLocalFirst)
+	if got := m.routeOrder("tool", slaves); !reflect.DeepEqual(got, []string{LocalHost, "laptop", "nas"}) {
+		t.Errorf("local-first: got %v", got)
+	}
+
+	m.SetRoutingPreference(RouteSlaveFirst)
+	if got := m.routeOrder("tool", slaves); !reflect.DeepEqual(got, []string{"laptop", "nas", LocalHost}) {
+		t.Errorf("slave-first: got %v", got)
+	}
+
+	m.SetRoutingPreference(RouteRoundRobin)
+	var firsts []string
+	for i := 0; i < 4; i++ {
+		firsts = append(firsts, m.routeOrder("tool", slaves)[0])
+	}
+	if want := []string{LocalHost, "laptop", "nas", LocalHost}; !reflect.DeepEqual(firsts, want) {
+		t.Errorf("round-robin: got %v, want %v", firsts, want)
+	}
+	if got := m.routeOrder("other", slaves)[0]; got != LocalHost {
+		t.Errorf("round-robin should rotate per tool, got %s first", got)
+	}
+}
+
+// fakeSlaveClient is a connected slave that answers every tool call
+type fakeSlaveClient struct {
+	mcpproxy.Client
+	name  string
+	calls int
+}
+
+func (c *fakeSlaveClient) GetName() string { return c.name }
+
+func (c *fakeSlaveClient) Close() error { return nil }
+
+func (c *fakeSlaveClient) NotificationChan() <-chan string {
+	ch := make(chan string)
+	close(ch)
+	return ch
+}
+
+func (c *fakeSlaveClient) CallToolContext(_ context.Context, tool string, _ map[string]interface{}) (json.RawMessage, error) {
+	c.calls++
+	return json.RawMessage(`{"content":[{"type":"text","text":"from ` + c.name + `"}]}`), nil
+}
+
+type noServers struct{}
+
+func (noServers) LoadMCPServerConfigs() ([]mcpproxy.ServerConfig, error) { return nil, nil }
+
+func TestRouteToolCallFallsBackFromLocal(t *testing.T) {
+	proxy, err := mcpproxy.NewProxy(noServers{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+	laptop := &fakeSlaveClient{name: "laptop"}
+	if err := proxy.RegisterSlaveClient("laptop", laptop); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{proxy: proxy, rrNext: make(map[string]int), served: make(map[string]int64)}
+	ctx := context.Background()
+
+	// A master that serves the call keeps it
+	result, host, err := m.RouteToolCall(ctx, "", nil, "search", nil, []string{"laptop"}, func() (interface{}, bool, error) {
+		return "local result", true, nil
+	})
+	if err != nil || host != LocalHost || result != "local result" || laptop.calls != 0 {
+		t.Fatalf("expected the master to serve the call, got %v from %q (%v)", result, host, err)
+	}
+
+	// A master that fails falls through to the slave
+	result, host, err = m.RouteToolCall(ctx, "", nil, "search", nil, []string{"laptop"}, func() (interface{}, bool, error) {
+		return nil, true, errors.New("backend unavailable")
+	})
+	if err != nil || host != "laptop" || laptop.calls != 1 {
+		t.Fatalf("expected the slave to serve the call, got %v from %q (%v)", result, host, err)
+	}
+	if served := m.ServedCalls(); served[LocalHost] != 1 || served["laptop"] != 1 {
+		t.Errorf("unexpected served counts %v", served)
+	}
+}
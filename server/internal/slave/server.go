@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/logger"
 	"github.com/diane-assistant/diane/internal/mcpproxy"
 	"github.com/diane-assistant/diane/internal/slavetypes"
 	"github.com/diane-assistant/diane/internal/store"
@@ -36,8 +38,18 @@ type Server struct {
 	heartbeatTicker *time.Ticker
 	proxy           *mcpproxy.Proxy    // Master's MCP proxy, for collecting tools to send to slaves
 	contextStore    store.ContextStore // Master's context store, for building context mappings
+
+	// Tool calls to a slave that dropped less than reconnectGrace ago are
+	// held until it reconnects. Both maps are guarded by connMu.
+	reconnectGrace time.Duration
+	disconnectedAt map[string]time.Time     // hostname -> when its connection dropped
+	reconnected    map[string]chan struct{} // hostname -> closed when it reconnects
 }
 
+// DefaultReconnectGrace is how long tool calls wait for a slave that has just
+// disconnected before failing
+const DefaultReconnectGrace = 5 * time.Second
+
 // slaveConnection represents an active WebSocket connection to a slave
 type slaveConnection struct {
 	conn          *websocket.Conn
@@ -63,10 +75,13 @@ func NewServer(ca *CertificateAuthority, registry *Registry, database store.Slav
 				return true
 			},
 		},
-		ctx:          ctx,
-		cancel:       cancel,
-		connections:  make(map[string]*slaveConnection),
-		pendingCalls: make(map[string]chan slavetypes.Message),
+		ctx:            ctx,
+		cancel:         cancel,
+		connections:    make(map[string]*slaveConnection),
+		pendingCalls:   make(map[string]chan slavetypes.Message),
+		reconnectGrace: DefaultReconnectGrace,
+		disconnectedAt: make(map[string]time.Time),
+		reconnected:    make(map[string]chan struct{}),
 	}
 }
 
@@ -182,9 +197,7 @@ func (s *Server) handleConnect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Store connection
-	s.connMu.Lock()
-	s.connections[hostname] = slaveConn
-	s.connMu.Unlock()
+	s.addConnection(slaveConn)
 
 	// Update last seen
 	if err := s.db.UpdateSlaveLastSeen(context.Background(), hostname); err != nil {
@@ -195,12 +208,39 @@ func (s *Server) handleConnect(w http.ResponseWriter, r *http.Request) {
 	s.handleConnection(slaveConn)
 
 	// Cleanup on disconnect
+	if s.removeConnection(slaveConn) {
+		s.registry.Disconnect(hostname)
+	}
+	slog.Info("Slave disconnected", "hostname", hostname)
+}
+
+// addConnection stores a slave's connection and releases any tool calls
+// held while it was away
+func (s *Server) addConnection(conn *slaveConnection) {
 	s.connMu.Lock()
-	delete(s.connections, hostname)
-	s.connMu.Unlock()
+	defer s.connMu.Unlock()
 
-	s.registry.Disconnect(hostname)
-	slog.Info("Slave disconnected", "hostname", hostname)
+	s.connections[conn.hostname] = conn
+	delete(s.disconnectedAt, conn.hostname)
+	if ch, ok := s.reconnected[conn.hostname]; ok {
+		close(ch)
+		delete(s.reconnected, conn.hostname)
+	}
+}
+
+// removeConnection forgets a closed connection and starts its reconnect
+// grace window. It reports false if the slave has already reconnected on a
+// new connection, or was dropped by the heartbeat monitor.
+func (s *Server) removeConnection(conn *slaveConnection) bool {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	if s.connections[conn.hostname] != conn {
+		return false
+	}
+	delete(s.connections, conn.hostname)
+	s.disconnectedAt[conn.hostname] = time.Now()
+	return true
 }
 
 // handlePair handles pairing requests
@@ -214,6 +254,7 @@ func (s *Server) handlePair(w http.ResponseWriter, r *http.Request) {
 		Hostname string `json:"hostname"`
 		CSR      string `json:"csr"`
 		Platform string `json:"platform"`
+		Token    string `json:"token"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -235,8 +276,9 @@ func (s *Server) handlePair(w http.ResponseWriter, r *http.Request) {
 	slog.Info("Pairing initiated via public port", "hostname", req.Hostname, "code", code)
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success":      true,
-		"pairing_code": code,
+		"success":       true,
+		"pairing_code":  code,
+		"auto_approved": s.pairing.AutoApprove(req.Hostname, code, req.Token),
 	})
 }
 
@@ -307,6 +349,8 @@ func (s *Server) handleConnection(conn *slaveConnection) {
 			s.handleToolUpdate(conn, msg)
 		case slavetypes.MessageTypeMasterToolCall:
 			s.handleMasterToolCall(conn, msg)
+		case slavetypes.MessageTypeCertRenew:
+			go s.handleCertRenew(conn, msg)
 		case slavetypes.MessageTypeResponse, slavetypes.MessageTypeError:
 			// Route response to pending call if any
 			s.responseMu.RLock()
@@ -386,19 +430,76 @@ func (s *Server) handleToolUpdate(conn *slaveConnection, msg slavetypes.Message)
 	s.registry.UpdateTools(conn.hostname, tools)
 }
 
-// SendToolCall sends a tool call request to a slave and waits for the response
-func (s *Server) SendToolCall(hostname, callID, tool string, arguments map[string]interface{}) (json.RawMessage, error) {
-	s.connMu.RLock()
+// SetReconnectGrace sets how long tool calls wait for a disconnected slave to
+// come back. Zero or less disables waiting.
+func (s *Server) SetReconnectGrace(grace time.Duration) {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	s.reconnectGrace = grace
+}
+
+// waitForConnection returns the slave's live connection. If the slave
+// dropped within the reconnect grace window it waits for it to reconnect;
+// otherwise it fails fast.
+func (s *Server) waitForConnection(hostname string) (*slaveConnection, error) {
+	s.connMu.Lock()
 	conn, ok := s.connections[hostname]
-	s.connMu.RUnlock()
+	if ok && conn.ctx.Err() == nil {
+		s.connMu.Unlock()
+		return conn, nil
+	}
+
+	since, wasConnected := s.disconnectedAt[hostname]
+	if ok {
+		// The connection is closing but hasn't been cleaned up yet
+		since, wasConnected = time.Now(), true
+	}
+	remaining := s.reconnectGrace - time.Since(since)
+	if !wasConnected || remaining <= 0 {
+		s.connMu.Unlock()
+		return nil, fmt.Errorf("slave offline: %s", hostname)
+	}
 
+	ch, ok := s.reconnected[hostname]
+	if !ok {
+		ch = make(chan struct{})
+		s.reconnected[hostname] = ch
+	}
+	s.connMu.Unlock()
+
+	slog.Info("Holding tool call until slave reconnects", "hostname", hostname, "wait", remaining.Round(time.Millisecond))
+	select {
+	case <-ch:
+	case <-time.After(remaining):
+		return nil, fmt.Errorf("slave offline: %s did not reconnect within %s", hostname, s.reconnectGrace)
+	case <-s.ctx.Done():
+		return nil, fmt.Errorf("slave server stopped")
+	}
+
+	s.connMu.RLock()
+	conn, ok = s.connections[hostname]
+	s.connMu.RUnlock()
 	if !ok {
-		return nil, fmt.Errorf("slave not connected: %s", hostname)
+		return nil, fmt.Errorf("slave offline: %s", hostname)
+	}
+	slog.Info("Slave reconnected, replaying tool call", "hostname", hostname)
+	return conn, nil
+}
+
+// SendToolCall sends a tool call request to a slave and waits for the response.
+// Calls made while the slave is briefly disconnected are held and replayed
+// once it reconnects (see waitForConnection). The request ID of ctx, if any,
+// is sent along so the slave's logs can be correlated with the master's.
+func (s *Server) SendToolCall(ctx context.Context, hostname, callID, tool string, arguments map[string]interface{}) (json.RawMessage, error) {
+	conn, err := s.waitForConnection(hostname)
+	if err != nil {
+		return nil, err
 	}
 
 	callMsg := slavetypes.ToolCallMessage{
 		Tool:      tool,
 		Arguments: arguments,
+		RequestID: logger.RequestID(ctx),
 	}
 
 	data, err := json.Marshal(callMsg)
@@ -428,12 +529,22 @@ func (s *Server) SendToolCall(hostname, callID, tool string, arguments map[strin
 		s.responseMu.Unlock()
 	}()
 
-	// Send message
+	// Send message. A failed write never reached the slave, so it is safe
+	// to replay once the slave is back.
 	if err := s.sendMessage(conn, msg); err != nil {
-		return nil, err
+		conn.cancel()
+		conn.conn.Close()
+		slog.WarnContext(ctx, "Tool call send failed, waiting for slave to reconnect", "hostname", hostname, "error", err)
+		if conn, err = s.waitForConnection(hostname); err != nil {
+			return nil, err
+		}
+		if err := s.sendMessage(conn, msg); err != nil {
+			return nil, err
+		}
 	}
 
-	// Wait for response or timeout
+	// Wait for response or timeout. A call the slave received may already
+	// have run, so it is not replayed if the slave drops before answering.
 	select {
 	case resp := <-respChan:
 		if resp.Type == slavetypes.MessageTypeError {
@@ -459,11 +570,123 @@ func (s *Server) SendToolCall(hostname, callID, tool string, arguments map[strin
 
 		return toolResp.Result, nil
 
+	case <-conn.ctx.Done():
+		return nil, fmt.Errorf("slave %s disconnected before responding", hostname)
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
 	case <-time.After(30 * time.Second):
 		return nil, fmt.Errorf("tool call timed out")
 	}
 }
 
+// RequestLogs fetches a slave's recent log entries and status report
+func (s *Server) RequestLogs(hostname string, limit int, level string) (*slavetypes.LogsResponse, error) {
+	s.connMu.RLock()
+	conn, ok := s.connections[hostname]
+	s.connMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("slave offline: %s", hostname)
+	}
+
+	data, err := json.Marshal(slavetypes.LogsRequestMessage{Limit: limit, Level: level})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal logs request: %w", err)
+	}
+
+	callID := fmt.Sprintf("%s-logs-%d", hostname, time.Now().UnixNano())
+	respChan := make(chan slavetypes.Message, 1)
+	s.responseMu.Lock()
+	s.pendingCalls[callID] = respChan
+	s.responseMu.Unlock()
+
+	defer func() {
+		s.responseMu.Lock()
+		delete(s.pendingCalls, callID)
+		s.responseMu.Unlock()
+	}()
+
+	if err := s.sendMessage(conn, slavetypes.Message{
+		Type:      slavetypes.MessageTypeLogsRequest,
+		ID:        callID,
+		Timestamp: time.Now(),
+		Data:      data,
+	}); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-respChan:
+		if resp.Type == slavetypes.MessageTypeError {
+			var errorResp struct {
+				Error string `json:"error"`
+			}
+			json.Unmarshal(resp.Data, &errorResp)
+			return nil, fmt.Errorf("logs request failed: %s", errorResp.Error)
+		}
+
+		var logs slavetypes.LogsResponse
+		if err := json.Unmarshal(resp.Data, &logs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal logs response: %w", err)
+		}
+		return &logs, nil
+
+	case <-conn.ctx.Done():
+		return nil, fmt.Errorf("slave %s disconnected before responding", hostname)
+
+	case <-time.After(10 * time.Second):
+		// Slaves older than the logs protocol ignore the request
+		return nil, fmt.Errorf("slave %s did not respond to logs request (it may need upgrading)", hostname)
+	}
+}
+
+// Ping measures the round-trip time to a connected slave over its existing
+// connection
+func (s *Server) Ping(hostname string) (time.Duration, error) {
+	s.connMu.RLock()
+	conn, ok := s.connections[hostname]
+	s.connMu.RUnlock()
+
+	if !ok {
+		return 0, fmt.Errorf("slave offline: %s", hostname)
+	}
+
+	callID := fmt.Sprintf("%s-ping-%d", hostname, time.Now().UnixNano())
+	respChan := make(chan slavetypes.Message, 1)
+	s.responseMu.Lock()
+	s.pendingCalls[callID] = respChan
+	s.responseMu.Unlock()
+
+	defer func() {
+		s.responseMu.Lock()
+		delete(s.pendingCalls, callID)
+		s.responseMu.Unlock()
+	}()
+
+	start := time.Now()
+	if err := s.sendMessage(conn, slavetypes.Message{
+		Type:      slavetypes.MessageTypePing,
+		ID:        callID,
+		Timestamp: start,
+	}); err != nil {
+		return 0, err
+	}
+
+	select {
+	case <-respChan:
+		return time.Since(start), nil
+
+	case <-conn.ctx.Done():
+		return 0, fmt.Errorf("slave %s disconnected before responding", hostname)
+
+	case <-time.After(10 * time.Second):
+		// Slaves older than the ping protocol ignore the request
+		return 0, fmt.Errorf("slave %s did not respond to ping (it may need upgrading)", hostname)
+	}
+}
+
 // SendRestartCommand sends a restart command to a slave
 func (s *Server) SendRestartCommand(hostname string) error {
 	s.connMu.RLock()
@@ -740,6 +963,88 @@ func (s *Server) handleMasterToolCall(conn *slaveConnection, msg slavetypes.Mess
 	}
 }
 
+// handleCertRenew re-issues a slave's client certificate. The request
+// arrives over the slave's mTLS connection, so the certificate is only
+// issued for the hostname that connection authenticated as.
+func (s *Server) handleCertRenew(conn *slaveConnection, msg slavetypes.Message) {
+	reply := func(result interface{}, errMsg string) {
+		mcpResp := map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID}
+		if errMsg != "" {
+			mcpResp["error"] = map[string]interface{}{"code": -32000, "message": errMsg}
+		} else {
+			mcpResp["result"] = result
+		}
+		data, _ := json.Marshal(mcpResp)
+		if err := s.sendMessage(conn, slavetypes.Message{
+			Type:      slavetypes.MessageTypeResponse,
+			ID:        msg.ID,
+			Timestamp: time.Now(),
+			Data:      data,
+		}); err != nil {
+			slog.Error("Failed to send certificate renewal response", "hostname", conn.hostname, "error", err)
+		}
+	}
+
+	var req slavetypes.CertRenewMessage
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		reply(nil, "Invalid certificate renewal message")
+		return
+	}
+
+	csrBlock, _ := pem.Decode([]byte(req.CSR))
+	if csrBlock == nil {
+		reply(nil, "Invalid CSR")
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(csrBlock.Bytes)
+	if err != nil {
+		reply(nil, fmt.Sprintf("Invalid CSR: %v", err))
+		return
+	}
+	if csr.Subject.CommonName != conn.hostname {
+		slog.Warn("Rejected certificate renewal for another hostname",
+			"hostname", conn.hostname, "requested", csr.Subject.CommonName)
+		reply(nil, "CSR common name does not match the connected slave")
+		return
+	}
+
+	existing, err := s.db.GetSlaveServerByHostID(context.Background(), conn.hostname)
+	if err != nil || existing == nil {
+		reply(nil, "Unknown slave")
+		return
+	}
+
+	certPEM, serial, err := s.ca.SignCSR([]byte(req.CSR), conn.hostname, clientCertValidDays)
+	if err != nil {
+		reply(nil, fmt.Sprintf("Failed to sign CSR: %v", err))
+		return
+	}
+	caCertPEM, err := s.ca.GetCACertPEM()
+	if err != nil {
+		reply(nil, "Failed to read CA certificate")
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.AddDate(0, 0, clientCertValidDays)
+	if err := s.db.UpdateSlaveServerCredentials(context.Background(), conn.hostname, serial, existing.Platform, now, expiresAt); err != nil {
+		reply(nil, fmt.Sprintf("Failed to record renewed certificate: %v", err))
+		return
+	}
+
+	slog.Info("Renewed slave certificate",
+		"hostname", conn.hostname,
+		"old_serial", existing.CertSerial,
+		"new_serial", serial,
+		"expires_at", expiresAt.Format(time.RFC3339))
+
+	reply(slavetypes.CertRenewResponse{
+		Certificate:   string(certPEM),
+		CACertificate: string(caCertPEM),
+		ExpiresAt:     expiresAt,
+	}, "")
+}
+
 // BroadcastMasterTools sends the current master tools to all connected slaves.
 // Call this when a new MCP server starts on the master.
 func (s *Server) BroadcastMasterTools() {
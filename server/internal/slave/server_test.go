@@ -0,0 +1,60 @@
+package slave
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testConnection(hostname string) *slaveConnection {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &slaveConnection{hostname: hostname, ctx: ctx, cancel: cancel}
+}
+
+func TestWaitForConnection(t *testing.T) {
+	s := NewServer(nil, nil, nil, nil)
+	defer s.cancel()
+	s.SetReconnectGrace(500 * time.Millisecond)
+
+	if _, err := s.waitForConnection("never-seen"); err == nil || !strings.Contains(err.Error(), "slave offline") {
+		t.Fatalf("expected slave offline error, got %v", err)
+	}
+
+	first := testConnection("laptop")
+	s.addConnection(first)
+	if conn, err := s.waitForConnection("laptop"); err != nil || conn != first {
+		t.Fatalf("expected live connection, got %v, %v", conn, err)
+	}
+
+	// A call made during a brief drop is held and gets the new connection
+	first.cancel()
+	s.removeConnection(first)
+	second := testConnection("laptop")
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.addConnection(second)
+	}()
+	if conn, err := s.waitForConnection("laptop"); err != nil || conn != second {
+		t.Fatalf("expected reconnected connection, got %v, %v", conn, err)
+	}
+
+	// Closing an old connection after the slave reconnected is a no-op
+	if s.removeConnection(first) {
+		t.Error("stale connection should not replace the live one")
+	}
+
+	// Past the grace window calls fail fast
+	second.cancel()
+	s.removeConnection(second)
+	start := time.Now()
+	if _, err := s.waitForConnection("laptop"); err == nil {
+		t.Fatal("expected error when slave does not reconnect")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("waited %s, longer than the grace window", elapsed)
+	}
+	if _, err := s.waitForConnection("laptop"); err == nil || !strings.Contains(err.Error(), "slave offline") {
+		t.Fatalf("expected immediate slave offline error, got %v", err)
+	}
+}
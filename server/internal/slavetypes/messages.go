@@ -3,6 +3,8 @@ package slavetypes
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/logger"
 )
 
 // Message types for WebSocket protocol
@@ -17,6 +19,9 @@ const (
 	MessageTypeUpgrade        = "upgrade"
 	MessageTypeMasterTools    = "master_tools"     // Master -> Slave: sends available master tools
 	MessageTypeMasterToolCall = "master_tool_call" // Slave -> Master: requests execution of a master tool
+	MessageTypeCertRenew      = "cert_renew"       // Slave -> Master: requests a new client certificate before expiry
+	MessageTypeLogsRequest    = "logs_request"     // Master -> Slave: requests recent log entries and status
+	MessageTypePing           = "ping"             // Master -> Slave: round-trip check, echoed back as a response
 )
 
 // Message represents a WebSocket message
@@ -38,6 +43,7 @@ type RegisterMessage struct {
 type ToolCallMessage struct {
 	Tool      string                 `json:"tool"`
 	Arguments map[string]interface{} `json:"arguments"`
+	RequestID string                 `json:"request_id,omitempty"` // ID of the client request that caused the call, for log correlation
 }
 
 // ToolCallResponse is sent by slave back to master
@@ -63,3 +69,29 @@ type MasterToolCallMessage struct {
 	Tool      string                 `json:"tool"`      // The tool name (without server prefix)
 	Arguments map[string]interface{} `json:"arguments"` // Tool arguments
 }
+
+// CertRenewMessage is sent by slave to master to re-issue its client
+// certificate over the existing authenticated connection
+type CertRenewMessage struct {
+	CSR string `json:"csr"` // PEM CSR for a fresh key; CN must be the slave's hostname
+}
+
+// CertRenewResponse is returned by master with the re-issued certificate
+type CertRenewResponse struct {
+	Certificate   string    `json:"certificate"`    // PEM client certificate
+	CACertificate string    `json:"ca_certificate"` // PEM CA certificate
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// LogsRequestMessage is sent by master to slave to fetch its recent logs
+type LogsRequestMessage struct {
+	Limit int    `json:"limit"`           // Maximum entries to return (newest)
+	Level string `json:"level,omitempty"` // Minimum level: debug, info, warn or error
+}
+
+// LogsResponse is sent by slave back to master with its log tail and status
+type LogsResponse struct {
+	Hostname string          `json:"hostname"`
+	Status   json.RawMessage `json:"status,omitempty"` // The slave's own status report
+	Entries  []logger.Entry  `json:"entries"`
+}
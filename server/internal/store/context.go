@@ -28,6 +28,11 @@ type ContextStore interface {
 	SetToolEnabled(ctx context.Context, contextName, serverName, toolName string, enabled bool) error
 	BulkSetToolsEnabled(ctx context.Context, contextName, serverName string, tools map[string]bool) error
 
+	// ContextServer init options, passed to the server's initialize call in
+	// this context (see mcpproxy.InitOptionsProvider). Empty options clear them.
+	GetServerInitOptions(ctx context.Context, contextName, serverName string) (map[string]any, error)
+	SetServerInitOptions(ctx context.Context, contextName, serverName string, options map[string]any) error
+
 	// Context queries (used by mcpproxy)
 	GetContextDetail(ctx context.Context, contextName string) (*db.ContextDetail, error)
 	IsToolEnabledInContext(ctx context.Context, contextName, serverName, toolName string) (bool, error)
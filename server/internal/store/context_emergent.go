@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"time"
 
@@ -42,6 +43,13 @@ import (
 //	      },
 //	      ...
 //	    }
+//
+//	ContextServer init options:
+//	  - Stored in context object properties, keyed by server name:
+//	    properties.init_options = {
+//	      "{serverName}": { ...options... },
+//	      ...
+//	    }
 type EmergentContextStore struct {
 	client *sdk.Client
 }
@@ -517,11 +525,16 @@ func (s *EmergentContextStore) RemoveServerFromContext(ctx context.Context, cont
 		}
 	}
 
+	// And its init options
+	initOptions := initOptionsFromObject(obj)
+	delete(initOptions, serverName)
+
 	_, err = s.client.Graph.UpdateObject(ctx, obj.ID, &graph.UpdateObjectRequest{
 		Labels:        newLabels,
 		ReplaceLabels: func() *bool { b := true; return &b }(),
 		Properties: map[string]any{
 			"tool_overrides": toolOverrides,
+			"init_options":   initOptions,
 			"updated_at":     time.Now().UTC().Format(time.RFC3339Nano),
 		},
 	})
@@ -708,6 +721,78 @@ func (s *EmergentContextStore) BulkSetToolsEnabled(ctx context.Context, contextN
 	return nil
 }
 
+// ---------------------------------------------------------------------------
+// ContextServer init options
+// ---------------------------------------------------------------------------
+
+// initOptionsFromObject reads properties.init_options from a context object
+func initOptionsFromObject(obj *graph.GraphObject) map[string]map[string]any {
+	initOptions := make(map[string]map[string]any)
+	if v, ok := obj.Properties["init_options"].(map[string]interface{}); ok {
+		for srvName, opts := range v {
+			if optsMap, ok := opts.(map[string]interface{}); ok {
+				initOptions[srvName] = optsMap
+			}
+		}
+	}
+	return initOptions
+}
+
+func (s *EmergentContextStore) GetServerInitOptions(ctx context.Context, contextName, serverName string) (map[string]any, error) {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  contextType,
+		Label: contextNameLabel(contextName),
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("emergent lookup context: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return nil, nil
+	}
+
+	return initOptionsFromObject(resp.Items[0])[serverName], nil
+}
+
+func (s *EmergentContextStore) SetServerInitOptions(ctx context.Context, contextName, serverName string, options map[string]any) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  contextType,
+		Label: contextNameLabel(contextName),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup context: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("context not found: %s", contextName)
+	}
+
+	obj := resp.Items[0]
+	if !slices.Contains(obj.Labels, serverRefLabel(serverName)) {
+		return db.ErrServerNotInContext
+	}
+
+	initOptions := initOptionsFromObject(obj)
+	if len(options) == 0 {
+		delete(initOptions, serverName)
+	} else {
+		initOptions[serverName] = options
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, obj.ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{
+			"init_options": initOptions,
+			"updated_at":   time.Now().UTC().Format(time.RFC3339Nano),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent set init options: %w", err)
+	}
+
+	slog.Info("emergent: set server init options", "context", contextName, "server", serverName, "count", len(options))
+	return nil
+}
+
 // ---------------------------------------------------------------------------
 // Context queries (used by mcpproxy)
 // ---------------------------------------------------------------------------
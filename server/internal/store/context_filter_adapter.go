@@ -34,6 +34,11 @@ func (a *ContextFilterAdapter) GetEnabledServersForContext(contextName string) (
 	return names, nil
 }
 
+// GetServerInitOptions returns the init options a context sets for a server.
+func (a *ContextFilterAdapter) GetServerInitOptions(contextName, serverName string) (map[string]interface{}, error) {
+	return a.store.GetServerInitOptions(context.Background(), contextName, serverName)
+}
+
 // GetDefaultContext returns the default context name.
 func (a *ContextFilterAdapter) GetDefaultContext() (string, error) {
 	ctx, err := a.store.GetDefaultContext(context.Background())
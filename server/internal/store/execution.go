@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"fmt"
+	"unicode/utf8"
 
 	"github.com/diane-assistant/diane/internal/db"
 )
@@ -11,9 +13,22 @@ type ExecutionStore interface {
 	// CreateJobExecution creates a new execution entry for a job. Returns the execution ID.
 	CreateJobExecution(ctx context.Context, jobID int64) (int64, error)
 
-	// UpdateJobExecution updates an execution with its results.
+	// UpdateJobExecution updates an execution with its results. Stdout and
+	// stderr over the job's output limit are truncated with TruncateOutput.
 	UpdateJobExecution(ctx context.Context, id int64, exitCode int, stdout, stderr string, execErr error) error
 
+	// SetExecutionOutput stores the structured data parsed from a finished
+	// execution's stdout
+	SetExecutionOutput(ctx context.Context, id int64, output any) error
+
+	// RecordSkippedExecution records a fire that didn't run, with status
+	// db.ExecutionStatusSkipped and reason as its error. Returns the execution ID.
+	RecordSkippedExecution(ctx context.Context, jobID int64, reason string) (int64, error)
+
+	// CreateRetryExecution creates a new execution entry for a job that
+	// retries the execution retryOf. Returns the execution ID.
+	CreateRetryExecution(ctx context.Context, jobID, retryOf int64) (int64, error)
+
 	// GetJobExecution retrieves a single execution by its legacy ID.
 	GetJobExecution(ctx context.Context, id int64) (*db.JobExecution, error)
 
@@ -23,3 +38,37 @@ type ExecutionStore interface {
 	// DeleteOldExecutions removes executions older than retentionDays. Returns the count deleted.
 	DeleteOldExecutions(ctx context.Context, retentionDays int) (int64, error)
 }
+
+// DefaultMaxOutputBytes caps the stdout and stderr stored for each job
+// execution when neither the job nor the config sets a limit
+const DefaultMaxOutputBytes = 256 << 10
+
+// TruncateOutput cuts output to at most limit bytes by dropping the middle,
+// where a runaway job's output is least useful, and marking the cut with
+// "[truncated N bytes]". It returns the number of bytes dropped. A limit of
+// 0 or less keeps everything.
+func TruncateOutput(output string, limit int) (string, int) {
+	if limit <= 0 || len(output) <= limit {
+		return output, 0
+	}
+
+	// The marker's length depends on the count it reports; size it for the
+	// largest count first, then fill the remaining budget with head and tail
+	dropped := len(output)
+	marker := fmt.Sprintf("\n[truncated %d bytes]\n", dropped)
+	keep := limit - len(marker)
+	if keep < 0 {
+		keep = 0
+	}
+	headEnd := keep / 2
+	for headEnd > 0 && !utf8.RuneStart(output[headEnd]) {
+		headEnd--
+	}
+	tailStart := len(output) - (keep - headEnd)
+	for tailStart < len(output) && !utf8.RuneStart(output[tailStart]) {
+		tailStart++
+	}
+
+	dropped = tailStart - headEnd
+	return output[:headEnd] + fmt.Sprintf("\n[truncated %d bytes]\n", dropped) + output[tailStart:], dropped
+}
@@ -27,8 +27,15 @@ import (
 //	  - Stdout        -> properties.stdout
 //	  - Stderr        -> properties.stderr
 //	  - Error         -> properties.error (nullable)
+//	  - StdoutTruncated, StderrTruncated -> properties.stdout_truncated,
+//	    properties.stderr_truncated (bytes dropped, omitted when 0)
+//	  - Status        -> properties.status (omitted unless skipped)
+//	  - RetryOf       -> properties.retry_of (omitted unless a retry)
+//	  - Output        -> properties.output (parsed JSON, omitted unless the job's output format is json)
 type EmergentExecutionStore struct {
 	client *sdk.Client
+
+	maxOutputBytes int // global output limit, overridden per job
 }
 
 const (
@@ -37,7 +44,34 @@ const (
 
 // NewEmergentExecutionStore creates a new Emergent-backed ExecutionStore.
 func NewEmergentExecutionStore(client *sdk.Client) *EmergentExecutionStore {
-	return &EmergentExecutionStore{client: client}
+	return &EmergentExecutionStore{client: client, maxOutputBytes: DefaultMaxOutputBytes}
+}
+
+// SetMaxOutputBytes sets the global limit on the stdout and stderr stored
+// per execution, for jobs that don't set their own. 0 or less restores
+// DefaultMaxOutputBytes.
+func (s *EmergentExecutionStore) SetMaxOutputBytes(n int) {
+	if n <= 0 {
+		n = DefaultMaxOutputBytes
+	}
+	s.maxOutputBytes = n
+}
+
+// outputLimit returns the output limit for a job: its own if set, else
+// the global one
+func (s *EmergentExecutionStore) outputLimit(ctx context.Context, jobID int64) int {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(jobID),
+		Limit: 1,
+	})
+	if err != nil || len(resp.Items) == 0 {
+		return s.maxOutputBytes
+	}
+	if j, err := jobFromObject(resp.Items[0]); err == nil && j.MaxOutputBytes > 0 {
+		return j.MaxOutputBytes
+	}
+	return s.maxOutputBytes
 }
 
 // ---------------------------------------------------------------------------
@@ -68,6 +102,15 @@ func executionToProperties(e *db.JobExecution) map[string]any {
 	if e.Error != nil {
 		props["error"] = *e.Error
 	}
+	if e.Status != "" {
+		props["status"] = e.Status
+	}
+	if e.RetryOf != nil {
+		props["retry_of"] = *e.RetryOf
+	}
+	if e.Output != nil {
+		props["output"] = e.Output
+	}
 	return props
 }
 
@@ -106,6 +149,22 @@ func executionFromObject(obj *graph.GraphObject) (*db.JobExecution, error) {
 	if v, ok := obj.Properties["error"].(string); ok {
 		e.Error = &v
 	}
+	if v, ok := obj.Properties["stdout_truncated"]; ok {
+		e.StdoutTruncated = int(toInt64(v))
+	}
+	if v, ok := obj.Properties["stderr_truncated"]; ok {
+		e.StderrTruncated = int(toInt64(v))
+	}
+	if v, ok := obj.Properties["status"].(string); ok {
+		e.Status = v
+	}
+	if v, ok := obj.Properties["retry_of"]; ok && v != nil {
+		retryOf := toInt64(v)
+		e.RetryOf = &retryOf
+	}
+	if v, ok := obj.Properties["output"]; ok {
+		e.Output = v
+	}
 
 	return e, nil
 }
@@ -134,22 +193,36 @@ func (s *EmergentExecutionStore) nextExecLegacyID(ctx context.Context) (int64, e
 // ---------------------------------------------------------------------------
 
 func (s *EmergentExecutionStore) CreateJobExecution(ctx context.Context, jobID int64) (int64, error) {
-	id, err := s.nextExecLegacyID(ctx)
-	if err != nil {
-		return 0, err
-	}
+	return s.createExecution(ctx, &db.JobExecution{JobID: jobID, StartedAt: time.Now().UTC()})
+}
 
+func (s *EmergentExecutionStore) CreateRetryExecution(ctx context.Context, jobID, retryOf int64) (int64, error) {
+	return s.createExecution(ctx, &db.JobExecution{JobID: jobID, StartedAt: time.Now().UTC(), RetryOf: &retryOf})
+}
+
+func (s *EmergentExecutionStore) RecordSkippedExecution(ctx context.Context, jobID int64, reason string) (int64, error) {
 	now := time.Now().UTC()
-	e := &db.JobExecution{
-		ID:        id,
+	return s.createExecution(ctx, &db.JobExecution{
 		JobID:     jobID,
 		StartedAt: now,
+		EndedAt:   &now,
+		Error:     &reason,
+		Status:    db.ExecutionStatusSkipped,
+	})
+}
+
+// createExecution stores e under the next legacy ID and returns the ID
+func (s *EmergentExecutionStore) createExecution(ctx context.Context, e *db.JobExecution) (int64, error) {
+	id, err := s.nextExecLegacyID(ctx)
+	if err != nil {
+		return 0, err
 	}
+	e.ID = id
 
 	props := executionToProperties(e)
 	labels := []string{
 		execLegacyIDLabel(id),
-		execJobIDLabel(jobID),
+		execJobIDLabel(e.JobID),
 	}
 	status := "active"
 
@@ -163,7 +236,7 @@ func (s *EmergentExecutionStore) CreateJobExecution(ctx context.Context, jobID i
 		return 0, fmt.Errorf("emergent create job execution: %w", err)
 	}
 
-	slog.Info("emergent: created job execution", "legacy_id", id, "job_id", jobID, "object_id", obj.ID)
+	slog.Info("emergent: created job execution", "legacy_id", id, "job_id", e.JobID, "status", e.Status, "object_id", obj.ID)
 	return id, nil
 }
 
@@ -183,6 +256,10 @@ func (s *EmergentExecutionStore) UpdateJobExecution(ctx context.Context, id int6
 	obj := resp.Items[0]
 	now := time.Now().UTC()
 
+	limit := s.outputLimit(ctx, toInt64(obj.Properties["job_id"]))
+	stdout, stdoutDropped := TruncateOutput(stdout, limit)
+	stderr, stderrDropped := TruncateOutput(stderr, limit)
+
 	props := map[string]any{
 		"ended_at":  now.Format(time.RFC3339Nano),
 		"exit_code": exitCode,
@@ -192,6 +269,12 @@ func (s *EmergentExecutionStore) UpdateJobExecution(ctx context.Context, id int6
 	if execErr != nil {
 		props["error"] = execErr.Error()
 	}
+	if stdoutDropped > 0 {
+		props["stdout_truncated"] = stdoutDropped
+	}
+	if stderrDropped > 0 {
+		props["stderr_truncated"] = stderrDropped
+	}
 
 	_, err = s.client.Graph.UpdateObject(ctx, obj.ID, &graph.UpdateObjectRequest{
 		Properties: props,
@@ -204,6 +287,28 @@ func (s *EmergentExecutionStore) UpdateJobExecution(ctx context.Context, id int6
 	return nil
 }
 
+func (s *EmergentExecutionStore) SetExecutionOutput(ctx context.Context, id int64, output any) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobExecutionType,
+		Label: execLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup execution for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("execution not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"output": output},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job execution output: %w", err)
+	}
+	return nil
+}
+
 func (s *EmergentExecutionStore) GetJobExecution(ctx context.Context, id int64) (*db.JobExecution, error) {
 	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
 		Type:  jobExecutionType,
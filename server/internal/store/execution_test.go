@@ -0,0 +1,35 @@
+package store
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	if got, dropped := TruncateOutput("short", 100); got != "short" || dropped != 0 {
+		t.Errorf("output under the limit should pass through, got %q, %d", got, dropped)
+	}
+	if got, dropped := TruncateOutput("anything", 0); got != "anything" || dropped != 0 {
+		t.Errorf("a zero limit should disable truncation, got %q, %d", got, dropped)
+	}
+
+	output := "HEAD" + strings.Repeat("é", 5000) + "TAIL"
+	got, dropped := TruncateOutput(output, 1024)
+	if len(got) > 1024 {
+		t.Errorf("truncated output is %d bytes, over the limit", len(got))
+	}
+	if !strings.HasPrefix(got, "HEAD") || !strings.HasSuffix(got, "TAIL") {
+		t.Error("expected both the start and the end of the output to be kept")
+	}
+	if !strings.Contains(got, "[truncated ") || dropped == 0 {
+		t.Errorf("expected a truncation marker, got dropped=%d", dropped)
+	}
+	if !utf8.ValidString(got) {
+		t.Error("truncation split a multi-byte character")
+	}
+	if len(got)-len("\n[truncated  bytes]\n")-len(strconv.Itoa(dropped)) != len(output)-dropped {
+		t.Errorf("dropped count %d doesn't match the bytes removed", dropped)
+	}
+}
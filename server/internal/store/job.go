@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/diane-assistant/diane/internal/db"
 )
@@ -29,6 +30,42 @@ type JobStore interface {
 	// UpdateJobFull applies partial updates including action type and agent name.
 	UpdateJobFull(ctx context.Context, id int64, command, schedule *string, enabled *bool, actionType *string, agentName *string) error
 
+	// SetScheduleText records the natural-language phrase a job's schedule
+	// was resolved from. Changing the schedule later clears it.
+	SetScheduleText(ctx context.Context, id int64, text string) error
+
+	// SetMaxOutputBytes sets the job's per-execution output limit,
+	// overriding the global one. 0 restores the global limit.
+	SetMaxOutputBytes(ctx context.Context, id int64, n int) error
+
+	// SetConcurrency sets what happens when the job fires while its
+	// previous run is still going (see cron.ParseConcurrency).
+	SetConcurrency(ctx context.Context, id int64, policy string) error
+
+	// SetRunEnvironment sets the extra environment variables and working
+	// directory the job's command runs with (see cron.ValidateRunEnvironment).
+	// A nil env and empty workdir restore the defaults.
+	SetRunEnvironment(ctx context.Context, id int64, env map[string]string, workdir string) error
+
+	// SetTimeout sets the job's maximum runtime in seconds (0 = no limit)
+	SetTimeout(ctx context.Context, id int64, seconds int) error
+
+	// SetDependsOn sets the job that this one runs after ("" = none).
+	// Callers check for cycles with cron.CheckDependencies first.
+	SetDependsOn(ctx context.Context, id int64, parent string) error
+
+	// SetOutputFormat sets how the job's stdout is read (see
+	// cron.ParseOutputFormat)
+	SetOutputFormat(ctx context.Context, id int64, format string) error
+
+	// SetNotifyTemplate sets the notify template sent when a run fails;
+	// empty turns failure notifications off
+	SetNotifyTemplate(ctx context.Context, id int64, name string) error
+
+	// SetPaused pauses the job until the given time, or until resumed when
+	// until is nil. paused=false resumes it.
+	SetPaused(ctx context.Context, id int64, paused bool, until *time.Time) error
+
 	// DeleteJob removes a job by its legacy ID.
 	DeleteJob(ctx context.Context, id int64) error
 }
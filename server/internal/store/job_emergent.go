@@ -24,9 +24,20 @@ import (
 //	  - Name (unique)       -> properties.name + label "name:{name}"
 //	  - Command             -> properties.command
 //	  - Schedule            -> properties.schedule
+//	  - ScheduleText        -> properties.schedule_text (empty for plain cron)
 //	  - Enabled             -> properties.enabled (bool) + label "enabled:{true|false}"
 //	  - ActionType          -> properties.action_type
 //	  - AgentName           -> properties.agent_name (nullable)
+//	  - MaxOutputBytes      -> properties.max_output_bytes (0 = global limit)
+//	  - Concurrency         -> properties.concurrency (empty = skip)
+//	  - Env                 -> properties.env (object of strings, omitted when empty)
+//	  - Workdir             -> properties.workdir (empty = home directory)
+//	  - TimeoutSeconds      -> properties.timeout_seconds (0 = no limit)
+//	  - DependsOn           -> properties.depends_on (parent job name, empty = none)
+//	  - OutputFormat        -> properties.output_format (empty = text)
+//	  - NotifyTemplate      -> properties.notify_template (empty = no failure notification)
+//	  - Paused              -> properties.paused (bool)
+//	  - PausedUntil         -> properties.paused_until (RFC3339Nano, empty = until resumed)
 //	  - CreatedAt           -> properties.created_at (RFC3339Nano)
 //	  - UpdatedAt           -> properties.updated_at (RFC3339Nano)
 type EmergentJobStore struct {
@@ -67,12 +78,25 @@ func jobLabels(j *db.Job) []string {
 func jobToProperties(j *db.Job) map[string]any {
 	now := time.Now().UTC()
 	props := map[string]any{
-		"name":        j.Name,
-		"command":     j.Command,
-		"schedule":    j.Schedule,
-		"enabled":     j.Enabled,
-		"action_type": j.ActionType,
-		"updated_at":  now.Format(time.RFC3339Nano),
+		"name":             j.Name,
+		"command":          j.Command,
+		"schedule":         j.Schedule,
+		"schedule_text":    j.ScheduleText,
+		"enabled":          j.Enabled,
+		"action_type":      j.ActionType,
+		"max_output_bytes": j.MaxOutputBytes,
+		"concurrency":      j.Concurrency,
+		"workdir":          j.Workdir,
+		"timeout_seconds":  j.TimeoutSeconds,
+		"depends_on":       j.DependsOn,
+		"output_format":    j.OutputFormat,
+		"notify_template":  j.NotifyTemplate,
+		"paused":           j.Paused,
+		"paused_until":     pausedUntilProperty(j.PausedUntil),
+		"updated_at":       now.Format(time.RFC3339Nano),
+	}
+	if len(j.Env) > 0 {
+		props["env"] = j.Env
 	}
 	if j.ID != 0 {
 		props["legacy_id"] = j.ID
@@ -104,6 +128,9 @@ func jobFromObject(obj *graph.GraphObject) (*db.Job, error) {
 	if v, ok := obj.Properties["schedule"].(string); ok {
 		j.Schedule = v
 	}
+	if v, ok := obj.Properties["schedule_text"].(string); ok {
+		j.ScheduleText = v
+	}
 	if v, ok := obj.Properties["enabled"].(bool); ok {
 		j.Enabled = v
 	}
@@ -116,6 +143,43 @@ func jobFromObject(obj *graph.GraphObject) (*db.Job, error) {
 	if v, ok := obj.Properties["agent_name"].(string); ok {
 		j.AgentName = &v
 	}
+	if v, ok := obj.Properties["max_output_bytes"]; ok {
+		j.MaxOutputBytes = int(toInt64(v))
+	}
+	if v, ok := obj.Properties["concurrency"].(string); ok {
+		j.Concurrency = v
+	}
+	if v, ok := obj.Properties["env"].(map[string]any); ok && len(v) > 0 {
+		j.Env = make(map[string]string, len(v))
+		for k, val := range v {
+			if s, ok := val.(string); ok {
+				j.Env[k] = s
+			}
+		}
+	}
+	if v, ok := obj.Properties["workdir"].(string); ok {
+		j.Workdir = v
+	}
+	if v, ok := obj.Properties["timeout_seconds"]; ok {
+		j.TimeoutSeconds = int(toInt64(v))
+	}
+	if v, ok := obj.Properties["depends_on"].(string); ok {
+		j.DependsOn = v
+	}
+	if v, ok := obj.Properties["output_format"].(string); ok {
+		j.OutputFormat = v
+	}
+	if v, ok := obj.Properties["notify_template"].(string); ok {
+		j.NotifyTemplate = v
+	}
+	if v, ok := obj.Properties["paused"].(bool); ok {
+		j.Paused = v
+	}
+	if v, ok := obj.Properties["paused_until"].(string); ok && v != "" {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			j.PausedUntil = &t
+		}
+	}
 	if v, ok := obj.Properties["created_at"].(string); ok {
 		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
 			j.CreatedAt = t
@@ -135,6 +199,14 @@ func jobFromObject(obj *graph.GraphObject) (*db.Job, error) {
 	return j, nil
 }
 
+// pausedUntilProperty encodes a job's pause end, empty when it has none
+func pausedUntilProperty(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
 // toInt64 converts a JSON number (float64, json.Number, int64) to int64.
 func toInt64(v interface{}) int64 {
 	switch n := v.(type) {
@@ -300,8 +372,9 @@ func (s *EmergentJobStore) UpdateJobFull(ctx context.Context, id int64, command,
 	if command != nil {
 		j.Command = *command
 	}
-	if schedule != nil {
+	if schedule != nil && *schedule != j.Schedule {
 		j.Schedule = *schedule
+		j.ScheduleText = ""
 	}
 	if enabled != nil {
 		j.Enabled = *enabled
@@ -330,6 +403,215 @@ func (s *EmergentJobStore) UpdateJobFull(ctx context.Context, id int64, command,
 	return nil
 }
 
+func (s *EmergentJobStore) SetScheduleText(ctx context.Context, id int64, text string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"schedule_text": text},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job schedule text: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetMaxOutputBytes(ctx context.Context, id int64, n int) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"max_output_bytes": n},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job output limit: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetConcurrency(ctx context.Context, id int64, policy string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"concurrency": policy},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job concurrency: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetRunEnvironment(ctx context.Context, id int64, env map[string]string, workdir string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	// An empty object rather than nil, so clearing the variables overwrites
+	// the stored ones
+	if env == nil {
+		env = map[string]string{}
+	}
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"env": env, "workdir": workdir},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job run environment: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetTimeout(ctx context.Context, id int64, seconds int) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"timeout_seconds": seconds},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job timeout: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetDependsOn(ctx context.Context, id int64, parent string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"depends_on": parent},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job dependency: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetOutputFormat(ctx context.Context, id int64, format string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"output_format": format},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job output format: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetNotifyTemplate(ctx context.Context, id int64, name string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{"notify_template": name},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job notify template: %w", err)
+	}
+	return nil
+}
+
+func (s *EmergentJobStore) SetPaused(ctx context.Context, id int64, paused bool, until *time.Time) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  jobType,
+		Label: jobLegacyIDLabel(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup job for update: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+
+	if !paused {
+		until = nil
+	}
+	_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+		Properties: map[string]any{
+			"paused":       paused,
+			"paused_until": pausedUntilProperty(until),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent update job pause: %w", err)
+	}
+	return nil
+}
+
 func (s *EmergentJobStore) DeleteJob(ctx context.Context, id int64) error {
 	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
 		Type:  jobType,
@@ -32,6 +32,10 @@ import (
 //	  - OAuth               -> properties.oauth (nested JSON)
 //	  - NodeID              -> properties.node_id
 //	  - NodeMode            -> properties.node_mode
+//	  - ToolTimeout         -> properties.tool_timeout (seconds, omitted when 0)
+//	  - MaxConcurrency      -> properties.max_concurrency (omitted when 0 = unlimited)
+//	  - StartupTimeout      -> properties.startup_timeout (seconds, omitted when 0)
+//	  - Priority            -> properties.priority (omitted when 0)
 //	  - CreatedAt           -> object.CreatedAt (built-in)
 //	  - UpdatedAt           -> properties.updated_at (RFC3339Nano)
 //
@@ -77,15 +81,21 @@ func placementHostLabel(hostID string) string { return fmt.Sprintf("placement_ho
 
 // mcpServerToProperties converts a db.MCPServer to Emergent properties.
 func mcpServerToProperties(s *db.MCPServer) map[string]any {
+	// The numeric settings are always written, zero included, so that
+	// updating a server back to its default clears the stored value
 	props := map[string]any{
-		"name":       s.Name,
-		"enabled":    s.Enabled,
-		"type":       s.Type,
-		"command":    s.Command,
-		"url":        s.URL,
-		"node_id":    s.NodeID,
-		"node_mode":  s.NodeMode,
-		"updated_at": time.Now().UTC().Format(time.RFC3339Nano),
+		"name":            s.Name,
+		"enabled":         s.Enabled,
+		"type":            s.Type,
+		"command":         s.Command,
+		"url":             s.URL,
+		"node_id":         s.NodeID,
+		"node_mode":       s.NodeMode,
+		"tool_timeout":    s.ToolTimeout,
+		"max_concurrency": s.MaxConcurrency,
+		"startup_timeout": s.StartupTimeout,
+		"priority":        s.Priority,
+		"updated_at":      time.Now().UTC().Format(time.RFC3339Nano),
 	}
 	if s.ID != 0 {
 		props["legacy_id"] = s.ID
@@ -145,6 +155,34 @@ func mcpServerFromObject(obj *graph.GraphObject) (*db.MCPServer, error) {
 	if v, ok := obj.Properties["node_mode"].(string); ok {
 		s.NodeMode = v
 	}
+	switch n := obj.Properties["tool_timeout"].(type) {
+	case float64:
+		s.ToolTimeout = int(n)
+	case json.Number:
+		secs, _ := n.Int64()
+		s.ToolTimeout = int(secs)
+	}
+	switch n := obj.Properties["max_concurrency"].(type) {
+	case float64:
+		s.MaxConcurrency = int(n)
+	case json.Number:
+		limit, _ := n.Int64()
+		s.MaxConcurrency = int(limit)
+	}
+	switch n := obj.Properties["startup_timeout"].(type) {
+	case float64:
+		s.StartupTimeout = int(n)
+	case json.Number:
+		secs, _ := n.Int64()
+		s.StartupTimeout = int(secs)
+	}
+	switch n := obj.Properties["priority"].(type) {
+	case float64:
+		s.Priority = int(n)
+	case json.Number:
+		priority, _ := n.Int64()
+		s.Priority = int(priority)
+	}
 
 	// Parse JSON fields
 	if v, ok := obj.Properties["args"]; ok && v != nil {
@@ -425,6 +463,8 @@ func (s *EmergentMCPServerStore) EnsureBuiltinServers(ctx context.Context) error
 		{Name: "github-bot", Type: "builtin"},
 		{Name: "downloads", Type: "builtin"},
 		{Name: "file_registry", Type: "builtin"},
+		{Name: "http_request", Type: "builtin"},
+		{Name: "shell_exec", Type: "builtin"},
 	}
 
 	for _, builtin := range builtins {
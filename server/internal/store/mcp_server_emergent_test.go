@@ -0,0 +1,47 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+
+	"github.com/diane-assistant/diane/internal/db"
+)
+
+func TestMCPServerPropertiesKeepZeroSettings(t *testing.T) {
+	// Properties come back from the graph API as decoded JSON
+	roundTrip := func(s *db.MCPServer) *db.MCPServer {
+		t.Helper()
+		data, err := json.Marshal(mcpServerToProperties(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj := &graph.GraphObject{}
+		if err := json.Unmarshal(data, &obj.Properties); err != nil {
+			t.Fatal(err)
+		}
+		got, err := mcpServerFromObject(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	set := roundTrip(&db.MCPServer{Name: "slow", ToolTimeout: 30, MaxConcurrency: 2, StartupTimeout: 90, Priority: 5})
+	if set.ToolTimeout != 30 || set.MaxConcurrency != 2 || set.StartupTimeout != 90 || set.Priority != 5 {
+		t.Errorf("settings didn't round-trip: %+v", set)
+	}
+
+	// An update patches the stored properties, so a reset to the default
+	// must be written rather than left out
+	props := mcpServerToProperties(&db.MCPServer{Name: "slow"})
+	for _, key := range []string{"tool_timeout", "max_concurrency", "startup_timeout", "priority"} {
+		if v, ok := props[key]; !ok || v != 0 {
+			t.Errorf("%s = %v (present %v), want 0 written", key, v, ok)
+		}
+	}
+	if reset := roundTrip(&db.MCPServer{Name: "slow"}); reset.ToolTimeout != 0 || reset.MaxConcurrency != 0 || reset.StartupTimeout != 0 || reset.Priority != 0 {
+		t.Errorf("zero settings didn't round-trip: %+v", reset)
+	}
+}
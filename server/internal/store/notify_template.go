@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// NotifyTemplate is a named notification message template: a Go
+// text/template rendered by notify.Render
+type NotifyTemplate struct {
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotifyTemplateStore defines the interface for notification template storage operations.
+type NotifyTemplateStore interface {
+	ListNotifyTemplates(ctx context.Context) ([]NotifyTemplate, error)
+	// GetNotifyTemplate returns the named template, or an error when there
+	// is none
+	GetNotifyTemplate(ctx context.Context, name string) (*NotifyTemplate, error)
+	SaveNotifyTemplate(ctx context.Context, t NotifyTemplate) error
+	DeleteNotifyTemplate(ctx context.Context, name string) error
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	sdk "github.com/emergent-company/emergent/apps/server-go/pkg/sdk"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+const notifyTemplateType = "notify_template"
+
+// EmergentNotifyTemplateStore implements NotifyTemplateStore using the Emergent graph API.
+//
+// Mapping:
+//
+//	NotifyTemplate:
+//	  - graph object type "notify_template"
+//	  - Name (unique) -> properties.name + label "notify_template:{name}"
+//	  - Body          -> properties.body
+//	  - UpdatedAt     -> properties.updated_at (RFC3339Nano)
+type EmergentNotifyTemplateStore struct {
+	client *sdk.Client
+}
+
+// NewEmergentNotifyTemplateStore creates a new EmergentNotifyTemplateStore.
+func NewEmergentNotifyTemplateStore(client *sdk.Client) *EmergentNotifyTemplateStore {
+	return &EmergentNotifyTemplateStore{client: client}
+}
+
+func notifyTemplateLabel(name string) string { return fmt.Sprintf("notify_template:%s", name) }
+
+// ListNotifyTemplates returns all templates, sorted by name.
+func (s *EmergentNotifyTemplateStore) ListNotifyTemplates(ctx context.Context) ([]NotifyTemplate, error) {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  notifyTemplateType,
+		Limit: 1000,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("emergent list notify templates: %w", err)
+	}
+
+	result := make([]NotifyTemplate, 0, len(resp.Items))
+	for _, obj := range resp.Items {
+		result = append(result, notifyTemplateFromObject(obj))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// GetNotifyTemplate returns the template called name.
+func (s *EmergentNotifyTemplateStore) GetNotifyTemplate(ctx context.Context, name string) (*NotifyTemplate, error) {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  notifyTemplateType,
+		Label: notifyTemplateLabel(name),
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("emergent lookup notify template %q: %w", name, err)
+	}
+	if len(resp.Items) == 0 {
+		return nil, fmt.Errorf("notify template not found: %s", name)
+	}
+	t := notifyTemplateFromObject(resp.Items[0])
+	return &t, nil
+}
+
+// SaveNotifyTemplate creates or replaces the template called t.Name.
+func (s *EmergentNotifyTemplateStore) SaveNotifyTemplate(ctx context.Context, t NotifyTemplate) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  notifyTemplateType,
+		Label: notifyTemplateLabel(t.Name),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup notify template %q: %w", t.Name, err)
+	}
+
+	props := map[string]any{
+		"name":       t.Name,
+		"body":       t.Body,
+		"updated_at": time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if len(resp.Items) > 0 {
+		_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+			Properties: props,
+		})
+		if err != nil {
+			return fmt.Errorf("emergent update notify template %q: %w", t.Name, err)
+		}
+		return nil
+	}
+
+	status := "active"
+	obj, err := s.client.Graph.CreateObject(ctx, &graph.CreateObjectRequest{
+		Type:       notifyTemplateType,
+		Status:     &status,
+		Properties: props,
+		Labels:     []string{notifyTemplateLabel(t.Name)},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent create notify template %q: %w", t.Name, err)
+	}
+
+	slog.Info("emergent: created notify template", "name", t.Name, "object_id", obj.ID)
+	return nil
+}
+
+// DeleteNotifyTemplate removes the template called name, if any.
+func (s *EmergentNotifyTemplateStore) DeleteNotifyTemplate(ctx context.Context, name string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  notifyTemplateType,
+		Label: notifyTemplateLabel(name),
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup notify template %q: %w", name, err)
+	}
+
+	for _, obj := range resp.Items {
+		if err := s.client.Graph.DeleteObject(ctx, obj.ID); err != nil {
+			return fmt.Errorf("emergent delete notify template %q (id: %s): %w", name, obj.ID, err)
+		}
+	}
+	return nil
+}
+
+// notifyTemplateFromObject converts an Emergent GraphObject to a NotifyTemplate.
+func notifyTemplateFromObject(obj *graph.GraphObject) NotifyTemplate {
+	t := NotifyTemplate{
+		Name:      getString(obj.Properties, "name"),
+		Body:      getString(obj.Properties, "body"),
+		UpdatedAt: obj.CreatedAt,
+	}
+	if v, ok := obj.Properties["updated_at"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			t.UpdatedAt = parsed
+		}
+	}
+	return t
+}
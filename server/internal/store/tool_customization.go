@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ToolCustomization overrides how a tool is advertised to MCP clients: a
+// replacement description and arguments to hide from its input schema
+type ToolCustomization struct {
+	ToolName    string    `json:"tool_name"`
+	Description string    `json:"description,omitempty"`
+	HiddenArgs  []string  `json:"hidden_args,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ToolCustomizationStore defines the interface for tool customization storage operations.
+type ToolCustomizationStore interface {
+	ListToolCustomizations(ctx context.Context) ([]ToolCustomization, error)
+	SaveToolCustomization(ctx context.Context, c ToolCustomization) error
+	DeleteToolCustomization(ctx context.Context, toolName string) error
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	sdk "github.com/emergent-company/emergent/apps/server-go/pkg/sdk"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+const toolCustomizationType = "tool_customization"
+
+// EmergentToolCustomizationStore implements ToolCustomizationStore using the Emergent graph API.
+//
+// Mapping:
+//
+//	ToolCustomization:
+//	  - graph object type "tool_customization"
+//	  - ToolName (unique) -> properties.tool_name + label "tool:{name}"
+//	  - Description       -> properties.description
+//	  - HiddenArgs        -> properties.hidden_args (JSON array string)
+//	  - UpdatedAt         -> properties.updated_at (RFC3339Nano)
+type EmergentToolCustomizationStore struct {
+	client *sdk.Client
+}
+
+// NewEmergentToolCustomizationStore creates a new EmergentToolCustomizationStore.
+func NewEmergentToolCustomizationStore(client *sdk.Client) *EmergentToolCustomizationStore {
+	return &EmergentToolCustomizationStore{client: client}
+}
+
+func toolLabel(name string) string { return fmt.Sprintf("tool:%s", name) }
+
+// ListToolCustomizations returns all tool customizations, sorted by tool name.
+func (s *EmergentToolCustomizationStore) ListToolCustomizations(ctx context.Context) ([]ToolCustomization, error) {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  toolCustomizationType,
+		Limit: 1000,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("emergent list tool customizations: %w", err)
+	}
+
+	result := make([]ToolCustomization, 0, len(resp.Items))
+	for _, obj := range resp.Items {
+		result = append(result, toolCustomizationFromObject(obj))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ToolName < result[j].ToolName
+	})
+	return result, nil
+}
+
+// SaveToolCustomization creates or replaces the customization of c.ToolName.
+func (s *EmergentToolCustomizationStore) SaveToolCustomization(ctx context.Context, c ToolCustomization) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  toolCustomizationType,
+		Label: toolLabel(c.ToolName),
+		Limit: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup tool customization %q: %w", c.ToolName, err)
+	}
+
+	props := toolCustomizationToProperties(c)
+	if len(resp.Items) > 0 {
+		_, err = s.client.Graph.UpdateObject(ctx, resp.Items[0].ID, &graph.UpdateObjectRequest{
+			Properties: props,
+		})
+		if err != nil {
+			return fmt.Errorf("emergent update tool customization %q: %w", c.ToolName, err)
+		}
+		return nil
+	}
+
+	status := "active"
+	obj, err := s.client.Graph.CreateObject(ctx, &graph.CreateObjectRequest{
+		Type:       toolCustomizationType,
+		Status:     &status,
+		Properties: props,
+		Labels:     []string{toolLabel(c.ToolName)},
+	})
+	if err != nil {
+		return fmt.Errorf("emergent create tool customization %q: %w", c.ToolName, err)
+	}
+
+	slog.Info("emergent: created tool customization", "tool", c.ToolName, "object_id", obj.ID)
+	return nil
+}
+
+// DeleteToolCustomization removes the customization of a tool, if any.
+func (s *EmergentToolCustomizationStore) DeleteToolCustomization(ctx context.Context, toolName string) error {
+	resp, err := s.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  toolCustomizationType,
+		Label: toolLabel(toolName),
+	})
+	if err != nil {
+		return fmt.Errorf("emergent lookup tool customization %q: %w", toolName, err)
+	}
+
+	for _, obj := range resp.Items {
+		if err := s.client.Graph.DeleteObject(ctx, obj.ID); err != nil {
+			return fmt.Errorf("emergent delete tool customization %q (id: %s): %w", toolName, obj.ID, err)
+		}
+	}
+	return nil
+}
+
+// toolCustomizationToProperties converts a ToolCustomization to Emergent properties.
+func toolCustomizationToProperties(c ToolCustomization) map[string]any {
+	hidden := "[]"
+	if len(c.HiddenArgs) > 0 {
+		if b, err := json.Marshal(c.HiddenArgs); err == nil {
+			hidden = string(b)
+		}
+	}
+	return map[string]any{
+		"tool_name":   c.ToolName,
+		"description": c.Description,
+		"hidden_args": hidden,
+		"updated_at":  time.Now().UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// toolCustomizationFromObject converts an Emergent GraphObject to a ToolCustomization.
+func toolCustomizationFromObject(obj *graph.GraphObject) ToolCustomization {
+	c := ToolCustomization{
+		ToolName:    getString(obj.Properties, "tool_name"),
+		Description: getString(obj.Properties, "description"),
+		UpdatedAt:   obj.CreatedAt,
+	}
+	if v, ok := obj.Properties["hidden_args"].(string); ok && v != "" {
+		_ = json.Unmarshal([]byte(v), &c.HiddenArgs)
+	}
+	if v, ok := obj.Properties["updated_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			c.UpdatedAt = t
+		}
+	}
+	return c
+}
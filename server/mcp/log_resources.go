@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/db"
+	"github.com/diane-assistant/diane/internal/logger"
+	"github.com/diane-assistant/diane/internal/store"
+	"github.com/diane-assistant/diane/mcp/tools"
+	"github.com/diane-assistant/diane/mcp/tools/jobs"
+)
+
+// Log resources let MCP clients read Diane's own logs as context:
+//
+//	diane://logs/server      recent server log entries
+//	diane://logs/job/<name>  recent executions of a job
+//
+// Both take query parameters: level (server: minimum level, default info;
+// job: "error" keeps only failed runs), limit (entries or executions) and
+// max_bytes (the most text returned; older entries are dropped first).
+//
+// Like other operational data, the server log is reserved for the admin
+// context. A job's log is available wherever the job_logs tool is.
+const (
+	serverLogURI     = "diane://logs/server"
+	jobLogURIPrefix  = "diane://logs/job/"
+	logResourceBytes = 64 << 10
+	serverLogLimit   = 200
+	jobLogLimit      = 5
+)
+
+// logResources lists the logs a client connected through contextName may
+// read: the server log and one log per job
+func logResources(contextName string) []tools.Resource {
+	var resources []tools.Resource
+	if contextName == adminContextName {
+		resources = append(resources, tools.Resource{
+			URI:         serverLogURI,
+			Name:        "Diane Server Log",
+			Description: "Recent server log entries since the daemon started. Query parameters: level (debug, info, warn, error; default info), limit (default 200), max_bytes (default 64KB).",
+			MimeType:    "text/plain",
+		})
+	}
+	if jobStore == nil || !jobLogsAllowed(contextName) {
+		return resources
+	}
+	jobList, err := jobStore.ListJobs(context.Background(), false)
+	if err != nil {
+		return resources
+	}
+	for _, j := range jobList {
+		resources = append(resources, tools.Resource{
+			URI:         jobLogURIPrefix + url.PathEscape(j.Name),
+			Name:        "Job Log: " + j.Name,
+			Description: "Output of the job's most recent executions, newest last. Query parameters: level=error (failed runs only), limit (default 5), max_bytes (default 64KB).",
+			MimeType:    "text/plain",
+		})
+	}
+	return resources
+}
+
+// jobLogsAllowed reports whether a client connected through contextName may
+// read job logs: wherever it may call job_logs
+func jobLogsAllowed(contextName string) bool {
+	if contextName == "" || contextName == adminContextName {
+		return true
+	}
+	if contextStore == nil {
+		return false
+	}
+	enabled, err := store.NewContextFilterAdapter(contextStore).IsToolEnabledInContext(contextName, "jobs", "job_logs")
+	return err == nil && enabled
+}
+
+// readLogResource serves a log resource to a client connected through
+// contextName. ok is false when uri isn't one.
+func readLogResource(uri, contextName string) (content *tools.ResourceContent, ok bool, err error) {
+	if !strings.HasPrefix(uri, "diane://logs/") {
+		return nil, false, nil
+	}
+	base, rawQuery, _ := strings.Cut(uri, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid query: %w", err)
+	}
+	maxBytes := min(queryInt(query, "max_bytes", logResourceBytes), maxResultBytes)
+
+	var lines []string
+	switch {
+	case base == serverLogURI:
+		if contextName != adminContextName {
+			return nil, true, fmt.Errorf("%s is only available in the %s context", serverLogURI, adminContextName)
+		}
+		lines, err = serverLogLines(query)
+	case strings.HasPrefix(base, jobLogURIPrefix):
+		if !jobLogsAllowed(contextName) {
+			return nil, true, fmt.Errorf("job logs are not available in context %s", contextName)
+		}
+		name, unescapeErr := url.PathUnescape(strings.TrimPrefix(base, jobLogURIPrefix))
+		if unescapeErr != nil || name == "" {
+			return nil, true, fmt.Errorf("invalid job name in %s", uri)
+		}
+		lines, err = jobLogLines(name, query)
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &tools.ResourceContent{
+		URI:      uri,
+		MimeType: "text/plain",
+		Text:     tailText(lines, maxBytes),
+	}, true, nil
+}
+
+// serverLogLines renders the recent server log entries, oldest first
+func serverLogLines(query url.Values) ([]string, error) {
+	level, err := logger.ParseLevel(query.Get("level"))
+	if err != nil {
+		return nil, err
+	}
+	entries := logger.Recent(queryInt(query, "limit", serverLogLimit), level)
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		line := fmt.Sprintf("%s %s %s", e.Time.Format(time.RFC3339), e.Level, e.Message)
+		if e.Attrs != "" {
+			line += " " + e.Attrs
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// jobLogLines renders a job's recent executions, oldest first
+func jobLogLines(name string, query url.Values) ([]string, error) {
+	if jobStore == nil || executionStore == nil {
+		return nil, fmt.Errorf("stores not initialized")
+	}
+	onlyFailed := false
+	switch level := query.Get("level"); level {
+	case "error":
+		onlyFailed = true
+	default:
+		if _, err := logger.ParseLevel(level); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := context.Background()
+	job, err := jobStore.GetJobByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("job not found: %s", name)
+	}
+	limit := queryInt(query, "limit", jobLogLimit)
+	fetch := limit
+	if onlyFailed {
+		// Failed runs may be interleaved with successful ones
+		fetch = limit * 10
+	}
+	executions, err := executionStore.ListJobExecutions(ctx, &job.ID, fetch, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var picked []*db.JobExecution
+	for _, e := range executions {
+		if onlyFailed && !jobs.Failed(e) {
+			continue
+		}
+		picked = append(picked, e)
+		if len(picked) == limit {
+			break
+		}
+	}
+
+	var lines []string
+	for i := len(picked) - 1; i >= 0; i-- {
+		lines = append(lines, executionLogLines(picked[i])...)
+	}
+	return lines, nil
+}
+
+// executionLogLines renders one execution: a header line, then its output
+func executionLogLines(e *db.JobExecution) []string {
+	status := "running"
+	switch {
+	case e.Status == db.ExecutionStatusSkipped:
+		status = "skipped"
+	case e.Error != nil:
+		status = "error: " + *e.Error
+	case e.ExitCode != nil:
+		status = fmt.Sprintf("exit %d", *e.ExitCode)
+	}
+	if e.EndedAt != nil && e.Status != db.ExecutionStatusSkipped {
+		status += fmt.Sprintf(" after %s", e.EndedAt.Sub(e.StartedAt).Round(time.Millisecond))
+	}
+
+	lines := []string{fmt.Sprintf("=== execution %d at %s: %s", e.ID, e.StartedAt.Format(time.RFC3339), status)}
+	for _, stream := range []struct {
+		name      string
+		text      string
+		truncated int
+	}{{"stdout", e.Stdout, e.StdoutTruncated}, {"stderr", e.Stderr, e.StderrTruncated}} {
+		if stream.text == "" {
+			continue
+		}
+		header := "--- " + stream.name
+		if stream.truncated > 0 {
+			header += fmt.Sprintf(" (%d bytes truncated)", stream.truncated)
+		}
+		lines = append(lines, header)
+		lines = append(lines, strings.Split(strings.TrimRight(stream.text, "\n"), "\n")...)
+	}
+	return lines
+}
+
+// tailText joins lines, dropping the oldest ones as needed to fit maxBytes
+// along with the note saying so
+func tailText(lines []string, maxBytes int) string {
+	budget := maxBytes - 64
+	size, start := 0, len(lines)
+	for start > 0 && size+len(lines[start-1])+1 <= budget {
+		start--
+		size += len(lines[start]) + 1
+	}
+	text := strings.Join(lines[start:], "\n")
+	if start > 0 {
+		text = fmt.Sprintf("[%d earlier lines omitted to fit %d bytes]\n", start, maxBytes) + text
+	}
+	return text
+}
+
+// queryInt reads a positive integer query parameter
+func queryInt(query url.Values, key string, def int) int {
+	if v, err := strconv.Atoi(query.Get(key)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/db"
+	"github.com/diane-assistant/diane/internal/store"
+)
+
+// fakeJobStore serves a single job by name
+type fakeJobStore struct {
+	store.JobStore
+	job *db.Job
+}
+
+func (s *fakeJobStore) GetJobByName(_ context.Context, name string) (*db.Job, error) {
+	if name != s.job.Name {
+		return nil, fmt.Errorf("not found")
+	}
+	return s.job, nil
+}
+
+func (s *fakeJobStore) ListJobs(context.Context, bool) ([]*db.Job, error) {
+	return []*db.Job{s.job}, nil
+}
+
+// fakeExecutionStore lists fixed executions, newest first
+type fakeExecutionStore struct {
+	store.ExecutionStore
+	executions []*db.JobExecution
+}
+
+func (s *fakeExecutionStore) ListJobExecutions(_ context.Context, _ *int64, limit, _ int) ([]*db.JobExecution, error) {
+	return s.executions[:min(limit, len(s.executions))], nil
+}
+
+// useJobStores points the job stores at a job with a successful run, a
+// failed one and another successful one, oldest last
+func useJobStores(t *testing.T) {
+	t.Helper()
+	oldJobs, oldExecutions := jobStore, executionStore
+	t.Cleanup(func() { jobStore, executionStore = oldJobs, oldExecutions })
+
+	now := time.Now()
+	execution := func(id int64, code int, stdout string) *db.JobExecution {
+		started := now.Add(-time.Duration(id) * time.Minute)
+		ended := started.Add(time.Second)
+		return &db.JobExecution{ID: id, JobID: 1, StartedAt: started, EndedAt: &ended, ExitCode: &code, Stdout: stdout}
+	}
+	jobStore = &fakeJobStore{job: &db.Job{ID: 1, Name: "backup"}}
+	executionStore = &fakeExecutionStore{executions: []*db.JobExecution{
+		execution(3, 0, "all good\n"),
+		execution(2, 1, "disk full\n"),
+		execution(1, 0, "first run\n"),
+	}}
+}
+
+func TestTailText(t *testing.T) {
+	lines := []string{"line-00001", "line-00002", "line-00003", "line-00004", "line-00005"}
+
+	if got := tailText(lines, 1024); got != strings.Join(lines, "\n") {
+		t.Errorf("expected every line to fit, got %q", got)
+	}
+
+	// 64 bytes are kept for the note, leaving room for three 11-byte lines
+	got := tailText(lines, 64+33)
+	want := "[2 earlier lines omitted to fit 97 bytes]\nline-00003\nline-00004\nline-00005"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Below the note's budget only the note is left
+	if got := tailText(lines, 10); got != "[5 earlier lines omitted to fit 10 bytes]\n" {
+		t.Errorf("expected just the note, got %q", got)
+	}
+}
+
+func TestQueryInt(t *testing.T) {
+	query := url.Values{"limit": {"25"}, "zero": {"0"}, "negative": {"-3"}, "word": {"many"}}
+	for key, want := range map[string]int{"limit": 25, "zero": 7, "negative": 7, "word": 7, "missing": 7} {
+		if got := queryInt(query, key, 7); got != want {
+			t.Errorf("%s: got %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestJobLogLines(t *testing.T) {
+	useJobStores(t)
+
+	lines, err := jobLogLines("backup", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Join(lines, "\n")
+	if strings.Index(text, "first run") > strings.Index(text, "all good") {
+		t.Errorf("expected executions oldest first, got:\n%s", text)
+	}
+
+	lines, err = jobLogLines("backup", url.Values{"level": {"error"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text = strings.Join(lines, "\n")
+	if !strings.Contains(text, "=== execution 2 ") || !strings.Contains(text, "exit 1") || strings.Contains(text, "all good") || strings.Contains(text, "first run") {
+		t.Errorf("expected only the failed run with level=error, got:\n%s", text)
+	}
+
+	if _, err := jobLogLines("backup", url.Values{"level": {"loud"}}); err == nil {
+		t.Error("expected an invalid level to be rejected")
+	}
+	if _, err := jobLogLines("restore", url.Values{}); err == nil || !strings.Contains(err.Error(), "job not found") {
+		t.Errorf("expected an unknown job to be rejected, got %v", err)
+	}
+}
+
+func TestReadLogResource(t *testing.T) {
+	useJobStores(t)
+
+	if _, ok, _ := readLogResource("diane://downloads/file.txt", ""); ok {
+		t.Error("expected a non-log URI to be left to other providers")
+	}
+	for _, uri := range []string{"diane://logs/job/%zz", "diane://logs/job/", "diane://logs/server?level=%zz"} {
+		if _, ok, err := readLogResource(uri, adminContextName); !ok || err == nil {
+			t.Errorf("%s: expected the URI to be rejected, got ok=%v err=%v", uri, ok, err)
+		}
+	}
+	if _, _, err := readLogResource(serverLogURI+"?level=loud", adminContextName); err == nil {
+		t.Error("expected an invalid server log level to be rejected")
+	}
+
+	content, ok, err := readLogResource(jobLogURIPrefix+"backup?level=error&max_bytes=100000", "")
+	if !ok || err != nil || !strings.Contains(content.Text, "disk full") {
+		t.Fatalf("expected the job log, got %+v, %v, %v", content, ok, err)
+	}
+}
+
+func TestServerLogRequiresAdmin(t *testing.T) {
+	useJobStores(t)
+
+	for _, contextName := range []string{"", "personal"} {
+		if _, ok, err := readLogResource(serverLogURI, contextName); !ok || err == nil || !strings.Contains(err.Error(), "admin context") {
+			t.Errorf("context %q: expected the server log to be refused, got %v", contextName, err)
+		}
+	}
+	if _, _, err := readLogResource(serverLogURI, adminContextName); err != nil {
+		t.Errorf("expected the admin context to read the server log, got %v", err)
+	}
+
+	listed := func(contextName string) bool {
+		for _, r := range logResources(contextName) {
+			if r.URI == serverLogURI {
+				return true
+			}
+		}
+		return false
+	}
+	if listed("") || !listed(adminContextName) {
+		t.Errorf("expected the server log listed only in the admin context")
+	}
+	if resources := logResources(""); len(resources) != 1 || resources[0].URI != jobLogURIPrefix+"backup" {
+		t.Errorf("expected the job log to be listed, got %+v", resources)
+	}
+}
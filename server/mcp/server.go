@@ -3,25 +3,32 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/diane-assistant/diane/internal/api"
 	"github.com/diane-assistant/diane/internal/cli"
 	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/internal/cron"
 	"github.com/diane-assistant/diane/internal/db"
 	"github.com/diane-assistant/diane/internal/emergent"
 	"github.com/diane-assistant/diane/internal/logger"
 	"github.com/diane-assistant/diane/internal/mcpproxy"
+	"github.com/diane-assistant/diane/internal/notify"
+	"github.com/diane-assistant/diane/internal/ntp"
 	"github.com/diane-assistant/diane/internal/slave"
 	"github.com/diane-assistant/diane/internal/store"
 	"github.com/diane-assistant/diane/mcp/tools"
@@ -31,9 +38,12 @@ import (
 	"github.com/diane-assistant/diane/mcp/tools/finance"
 	githubbot "github.com/diane-assistant/diane/mcp/tools/github"
 	"github.com/diane-assistant/diane/mcp/tools/google"
+	"github.com/diane-assistant/diane/mcp/tools/httprequest"
 	"github.com/diane-assistant/diane/mcp/tools/infrastructure"
+	"github.com/diane-assistant/diane/mcp/tools/jobs"
 	"github.com/diane-assistant/diane/mcp/tools/notifications"
 	"github.com/diane-assistant/diane/mcp/tools/places"
+	"github.com/diane-assistant/diane/mcp/tools/shellexec"
 	"github.com/diane-assistant/diane/mcp/tools/weather"
 )
 
@@ -54,6 +64,9 @@ func getVersion() string {
 }
 
 var proxy *mcpproxy.Proxy
+var maxResultBytes = mcpproxy.DefaultMaxResultBytes
+var toolCache = tools.NewResultCache()
+var ntpServer = ntp.DefaultServer
 var slaveManager *slave.Manager
 var slaveClient *mcpproxy.WSClient // Slave client for connecting to master
 var slaveConfig config.SlaveConfig // Slave configuration
@@ -65,18 +78,32 @@ var notificationsProvider *notifications.Provider
 var financeProvider *finance.Provider
 var placesProvider *places.Provider
 var weatherProvider *weather.Provider
-var githubProvider *githubbot.Provider    // GitHub App bot tools
-var downloadsProvider *downloads.Provider // File download tools
-var filesProvider *files.Provider         // File index tools
+var httpRequestProvider *httprequest.Provider // Allowlisted generic HTTP tool
+var shellExecProvider *shellexec.Provider     // Allowlisted command runner
+var githubProvider *githubbot.Provider        // GitHub App bot tools
+var downloadsProvider *downloads.Provider     // File download tools
+var filesProvider *files.Provider             // File index tools
+
+// builtinSetupErrors records why an enabled builtin server failed to start,
+// so doctor can point at the missing configuration
+var builtinSetupErrors = make(map[string]error)
+var providerHealth = tools.NewHealthTracker() // Recent call outcomes per builtin provider
 var apiServer *api.Server
 var mcpHTTPServer *api.MCPHTTPServer
-var database *db.DB                     // Shared database instance
-var contextStore store.ContextStore     // Shared Emergent-backed context store
-var jobStore store.JobStore             // Shared Emergent-backed job store
-var executionStore store.ExecutionStore // Shared Emergent-backed execution store
-var agentStore store.AgentStore         // Shared Emergent-backed agent store
+var database *db.DB                                     // Shared database instance
+var contextStore store.ContextStore                     // Shared Emergent-backed context store
+var jobStore store.JobStore                             // Shared Emergent-backed job store
+var executionStore store.ExecutionStore                 // Shared Emergent-backed execution store
+var agentStore store.AgentStore                         // Shared Emergent-backed agent store
+var toolCustomizationStore store.ToolCustomizationStore // Shared Emergent-backed tool customization store
+var notifyTemplateStore store.NotifyTemplateStore       // Shared Emergent-backed notify template store
 var startTime time.Time
 
+// restartCh is signalled by RestartDaemon to make the serve-mode main loop shut
+// down and re-exec itself. It is nil in stdio mode, where the MCP client owns
+// the process lifecycle.
+var restartCh chan struct{}
+
 // DBConfigProvider implements mcpproxy.ConfigProvider, loading server configs from the Emergent-backed store
 type DBConfigProvider struct {
 	store store.MCPServerStore
@@ -118,17 +145,21 @@ func (p *DBConfigProvider) LoadMCPServerConfigs() ([]mcpproxy.ServerConfig, erro
 			}
 		}
 		configs = append(configs, mcpproxy.ServerConfig{
-			Name:     s.Name,
-			Enabled:  s.Enabled,
-			Type:     s.Type,
-			Command:  s.Command,
-			Args:     s.Args,
-			Env:      s.Env,
-			URL:      s.URL,
-			Headers:  s.Headers,
-			OAuth:    oauth,
-			NodeID:   s.NodeID,
-			NodeMode: s.NodeMode,
+			Name:           s.Name,
+			Enabled:        s.Enabled,
+			Type:           s.Type,
+			Command:        s.Command,
+			Args:           s.Args,
+			Env:            s.Env,
+			URL:            s.URL,
+			Headers:        s.Headers,
+			OAuth:          oauth,
+			NodeID:         s.NodeID,
+			NodeMode:       s.NodeMode,
+			ToolTimeout:    s.ToolTimeout,
+			MaxConcurrency: s.MaxConcurrency,
+			StartupTimeout: s.StartupTimeout,
+			Priority:       s.Priority,
 		})
 	}
 	return configs, nil
@@ -139,7 +170,7 @@ type DianeStatusProvider struct{}
 
 func (d *DianeStatusProvider) GetStatus() api.Status {
 	home, _ := os.UserHomeDir()
-	logFile := filepath.Join(home, ".diane", "server.log")
+	logFile := filepath.Join(home, config.DirName(), "server.log")
 	hostname, _ := os.Hostname()
 
 	status := api.Status{
@@ -180,10 +211,25 @@ func (d *DianeStatusProvider) GetStatus() api.Status {
 	return status
 }
 
+// HealthStatus implements mcpproxy.HealthReporter so a master can see this
+// slave's status alongside its logs
+func (d *DianeStatusProvider) HealthStatus() interface{} {
+	return d.GetStatus()
+}
+
 func (d *DianeStatusProvider) GetMCPServers() []api.MCPServerStatus {
 	return d.getAllMCPServers()
 }
 
+// degradedError is what a degraded provider reports as its error: why its
+// tools can't be listed, or else its last failed call
+func degradedError(h tools.ProviderHealth) string {
+	if h.ToolsError != "" {
+		return h.ToolsError
+	}
+	return h.LastError
+}
+
 // getAllMCPServers returns all MCP servers including builtin providers
 func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 	var servers []api.MCPServerStatus
@@ -193,8 +239,8 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 		Name:        "jobs",
 		Enabled:     true,
 		Connected:   true,
-		ToolCount:   9, // job_list, job_add, job_enable, job_disable, job_delete, job_pause, job_resume, job_logs, server_status
-		PromptCount: 3, // jobs_create_scheduled_task, jobs_review_schedules, jobs_troubleshoot_failures
+		ToolCount:   10, // job_list, job_add, job_enable, job_disable, job_delete, job_pause, job_resume, job_logs, job_retry, server_status
+		PromptCount: 3,  // jobs_create_scheduled_task, jobs_review_schedules, jobs_troubleshoot_failures
 		Builtin:     true,
 	})
 
@@ -204,7 +250,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:      "apple",
 			Enabled:   true,
 			Connected: true,
-			ToolCount: len(appleProvider.Tools()),
+			ToolCount: len(providerTools("apple", appleProvider.Tools)),
 			Builtin:   true,
 		})
 	}
@@ -222,7 +268,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:          "google",
 			Enabled:       true,
 			Connected:     true,
-			ToolCount:     len(googleProvider.Tools()),
+			ToolCount:     len(providerTools("google", googleProvider.Tools)),
 			PromptCount:   promptCount,
 			ResourceCount: resourceCount,
 			Builtin:       true,
@@ -234,7 +280,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:      "infrastructure",
 			Enabled:   true,
 			Connected: true,
-			ToolCount: len(infrastructureProvider.Tools()),
+			ToolCount: len(providerTools("infrastructure", infrastructureProvider.Tools)),
 			Builtin:   true,
 		})
 	}
@@ -248,7 +294,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:        "discord",
 			Enabled:     true,
 			Connected:   true,
-			ToolCount:   len(notificationsProvider.Tools()),
+			ToolCount:   len(providerTools("discord", notificationsProvider.Tools)),
 			PromptCount: promptCount,
 			Builtin:     true,
 		})
@@ -259,7 +305,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:      "finance",
 			Enabled:   true,
 			Connected: true,
-			ToolCount: len(financeProvider.Tools()),
+			ToolCount: len(providerTools("finance", financeProvider.Tools)),
 			Builtin:   true,
 		})
 	}
@@ -269,7 +315,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:      "places",
 			Enabled:   true,
 			Connected: true,
-			ToolCount: len(placesProvider.Tools()),
+			ToolCount: len(providerTools("places", placesProvider.Tools)),
 			Builtin:   true,
 		})
 	}
@@ -279,7 +325,27 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:      "weather",
 			Enabled:   true,
 			Connected: true,
-			ToolCount: len(weatherProvider.Tools()),
+			ToolCount: len(providerTools("weather", weatherProvider.Tools)),
+			Builtin:   true,
+		})
+	}
+
+	if httpRequestProvider != nil {
+		servers = append(servers, api.MCPServerStatus{
+			Name:      "http_request",
+			Enabled:   true,
+			Connected: true,
+			ToolCount: len(providerTools("http_request", httpRequestProvider.Tools)),
+			Builtin:   true,
+		})
+	}
+
+	if shellExecProvider != nil {
+		servers = append(servers, api.MCPServerStatus{
+			Name:      "shell_exec",
+			Enabled:   true,
+			Connected: true,
+			ToolCount: len(providerTools("shell_exec", shellExecProvider.Tools)),
 			Builtin:   true,
 		})
 	}
@@ -289,7 +355,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:      "github-bot",
 			Enabled:   true,
 			Connected: true,
-			ToolCount: len(githubProvider.Tools()),
+			ToolCount: len(providerTools("github-bot", githubProvider.Tools)),
 			Builtin:   true,
 		})
 	}
@@ -303,7 +369,7 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:          "downloads",
 			Enabled:       true,
 			Connected:     true,
-			ToolCount:     len(downloadsProvider.Tools()),
+			ToolCount:     len(providerTools("downloads", downloadsProvider.Tools)),
 			ResourceCount: resourceCount,
 			Builtin:       true,
 		})
@@ -314,26 +380,36 @@ func (d *DianeStatusProvider) getAllMCPServers() []api.MCPServerStatus {
 			Name:      "file_registry",
 			Enabled:   true,
 			Connected: true,
-			ToolCount: len(filesProvider.Tools()),
+			ToolCount: len(providerTools("file_registry", filesProvider.Tools)),
 			Builtin:   true,
 		})
 	}
 
+	// A builtin whose backend has stopped answering since startup is degraded
+	for i := range servers {
+		if h := providerHealth.Health(servers[i].Name); h.Degraded {
+			servers[i].Degraded = true
+			servers[i].Error = degradedError(h)
+		}
+	}
+
 	// Add external MCP servers from proxy
 	if proxy != nil {
 		proxyStatuses := proxy.GetServerStatuses()
 		for _, s := range proxyStatuses {
 			servers = append(servers, api.MCPServerStatus{
-				Name:          s.Name,
-				Enabled:       s.Enabled,
-				Connected:     s.Connected,
-				ToolCount:     s.ToolCount,
-				PromptCount:   s.PromptCount,
-				ResourceCount: s.ResourceCount,
-				Error:         s.Error,
-				Builtin:       false,
-				RequiresAuth:  s.RequiresAuth,
-				Authenticated: s.Authenticated,
+				Name:           s.Name,
+				Enabled:        s.Enabled,
+				Connected:      s.Connected,
+				ToolCount:      s.ToolCount,
+				PromptCount:    s.PromptCount,
+				ResourceCount:  s.ResourceCount,
+				Error:          s.Error,
+				Builtin:        false,
+				RequiresAuth:   s.RequiresAuth,
+				Authenticated:  s.Authenticated,
+				InFlight:       s.InFlight,
+				MaxConcurrency: s.MaxConcurrency,
 			})
 		}
 	}
@@ -348,11 +424,71 @@ func (d *DianeStatusProvider) RestartMCPServer(name string) error {
 	return proxy.RestartServer(name)
 }
 
-func (d *DianeStatusProvider) ReloadConfig() error {
+// RestartMCPServers restarts all enabled proxied servers, or only the failed ones
+func (d *DianeStatusProvider) RestartMCPServers(onlyFailed bool) ([]api.MCPServerRestartResult, error) {
+	if proxy == nil {
+		return nil, fmt.Errorf("proxy not initialized")
+	}
+	proxyResults := proxy.RestartServers(onlyFailed)
+	results := make([]api.MCPServerRestartResult, 0, len(proxyResults))
+	for _, r := range proxyResults {
+		results = append(results, api.MCPServerRestartResult{
+			Name:    r.Name,
+			Success: r.Success,
+			Error:   r.Error,
+		})
+	}
+	return results, nil
+}
+
+// SetMCPServerEnabled disables or re-enables a proxied server at runtime
+func (d *DianeStatusProvider) SetMCPServerEnabled(name string, enabled bool) error {
 	if proxy == nil {
 		return fmt.Errorf("proxy not initialized")
 	}
-	return proxy.Reload()
+	return proxy.EnableServer(name, enabled)
+}
+
+// GetSSEClients lists the clients connected to the MCP HTTP server's SSE
+// transport
+func (d *DianeStatusProvider) GetSSEClients() ([]api.SSEClient, error) {
+	if mcpHTTPServer == nil {
+		return nil, fmt.Errorf("MCP HTTP server not started")
+	}
+	return mcpHTTPServer.SSEClients(), nil
+}
+
+func (d *DianeStatusProvider) ReloadConfig() (*api.ReloadResult, error) {
+	if proxy == nil {
+		return nil, fmt.Errorf("proxy not initialized")
+	}
+	if err := loadToolCustomizations(); err != nil {
+		slog.Warn("Failed to reload tool customizations", "error", err)
+	}
+	plan, err := proxy.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return &api.ReloadResult{
+		Started:      plan.Start,
+		Stopped:      plan.Stop,
+		Restarted:    plan.Restart,
+		Reconfigured: plan.Reconfigure,
+	}, nil
+}
+
+// RestartDaemon requests a graceful self-restart. The actual shutdown and
+// re-exec happen asynchronously in main so the caller gets a response first.
+func (d *DianeStatusProvider) RestartDaemon() error {
+	if restartCh == nil {
+		return fmt.Errorf("self-restart is only supported in serve mode")
+	}
+	select {
+	case restartCh <- struct{}{}:
+	default:
+		// A restart is already pending
+	}
+	return nil
 }
 
 // TODO(emergent-migration): GetJobs, GetJobLogs, ToggleJob, GetAgentLogs, and CreateAgentLog
@@ -370,18 +506,49 @@ func (d *DianeStatusProvider) GetJobs() ([]api.Job, error) {
 		return nil, err
 	}
 
+	now := time.Now()
 	jobs := make([]api.Job, 0, len(dbJobs))
 	for _, j := range dbJobs {
+		paused := j.IsPaused(now)
+		var pausedUntil *time.Time
+		if paused {
+			pausedUntil = j.PausedUntil
+		}
+		var nextRun *time.Time
+		if j.Enabled && (!paused || pausedUntil != nil) {
+			from := now
+			if pausedUntil != nil {
+				from = *pausedUntil
+			}
+			if sched, err := cron.Parse(j.Schedule); err == nil {
+				if next := sched.Next(from); !next.IsZero() {
+					nextRun = &next
+				}
+			}
+		}
 		jobs = append(jobs, api.Job{
-			ID:         j.ID,
-			Name:       j.Name,
-			Command:    j.Command,
-			Schedule:   j.Schedule,
-			Enabled:    j.Enabled,
-			ActionType: j.ActionType,
-			AgentName:  j.AgentName,
-			CreatedAt:  j.CreatedAt,
-			UpdatedAt:  j.UpdatedAt,
+			ID:               j.ID,
+			Name:             j.Name,
+			Command:          j.Command,
+			Schedule:         j.Schedule,
+			ScheduleText:     j.ScheduleText,
+			Enabled:          j.Enabled,
+			ActionType:       j.ActionType,
+			AgentName:        j.AgentName,
+			MaxOutputBytes:   j.MaxOutputBytes,
+			TimeoutSeconds:   j.TimeoutSeconds,
+			Concurrency:      j.Concurrency,
+			Env:              j.Env,
+			Workdir:          j.Workdir,
+			EffectiveWorkdir: cron.EffectiveWorkdir(j.Workdir),
+			DependsOn:        j.DependsOn,
+			OutputFormat:     j.OutputFormat,
+			NotifyTemplate:   j.NotifyTemplate,
+			Paused:           paused,
+			PausedUntil:      pausedUntil,
+			NextRun:          nextRun,
+			CreatedAt:        j.CreatedAt,
+			UpdatedAt:        j.UpdatedAt,
 		})
 	}
 	return jobs, nil
@@ -396,11 +563,13 @@ func (d *DianeStatusProvider) GetJobLogs(jobName string, limit int) ([]api.JobEx
 	ctx := context.Background()
 	var jobID *int64
 	var jobNameMap = make(map[int64]string)
+	var workdirMap = make(map[int64]string)
 
-	// Build job name map for all jobs
+	// Build job name and workdir maps for all jobs
 	allJobs, _ := jobStore.ListJobs(ctx, false)
 	for _, j := range allJobs {
 		jobNameMap[j.ID] = j.Name
+		workdirMap[j.ID] = cron.EffectiveWorkdir(j.Workdir)
 	}
 
 	// If filtering by job name, get the job ID
@@ -419,22 +588,55 @@ func (d *DianeStatusProvider) GetJobLogs(jobName string, limit int) ([]api.JobEx
 
 	execs := make([]api.JobExecution, 0, len(dbExecs))
 	for _, e := range dbExecs {
-		exec := api.JobExecution{
-			ID:        e.ID,
-			JobID:     e.JobID,
-			JobName:   jobNameMap[e.JobID],
-			StartedAt: e.StartedAt,
-			EndedAt:   e.EndedAt,
-			ExitCode:  e.ExitCode,
-			Stdout:    e.Stdout,
-			Stderr:    e.Stderr,
-			Error:     e.Error,
-		}
+		exec := apiJobExecution(e, jobNameMap[e.JobID])
+		exec.Workdir = workdirMap[e.JobID]
 		execs = append(execs, exec)
 	}
 	return execs, nil
 }
 
+// RetryJobExecution re-runs the job behind a failed execution, waiting for
+// the new execution to finish. The jobs that depend on it then fire in the
+// background.
+func (d *DianeStatusProvider) RetryJobExecution(ctx context.Context, id int64) (*api.JobExecution, error) {
+	if jobStore == nil || executionStore == nil {
+		return nil, fmt.Errorf("stores not initialized")
+	}
+
+	e, err := jobs.Retry(ctx, jobStore, executionStore, id)
+	if err != nil {
+		return nil, err
+	}
+	exec := apiJobExecution(e, "")
+	if job, err := jobStore.GetJob(ctx, e.JobID); err == nil {
+		exec.JobName = job.Name
+		exec.Workdir = cron.EffectiveWorkdir(job.Workdir)
+		go jobs.FireDependents(context.WithoutCancel(ctx), jobStore, executionStore, job, e)
+	}
+	return &exec, nil
+}
+
+// apiJobExecution converts a stored execution for the API
+func apiJobExecution(e *db.JobExecution, jobName string) api.JobExecution {
+	return api.JobExecution{
+		ID:        e.ID,
+		JobID:     e.JobID,
+		JobName:   jobName,
+		StartedAt: e.StartedAt,
+		EndedAt:   e.EndedAt,
+		ExitCode:  e.ExitCode,
+		Stdout:    e.Stdout,
+		Stderr:    e.Stderr,
+		Error:     e.Error,
+
+		StdoutTruncated: e.StdoutTruncated,
+		StderrTruncated: e.StderrTruncated,
+		Status:          e.Status,
+		RetryOf:         e.RetryOf,
+		Output:          e.Output,
+	}
+}
+
 // ToggleJob enables or disables a job
 func (d *DianeStatusProvider) ToggleJob(name string, enabled bool) error {
 	if jobStore == nil {
@@ -450,6 +652,272 @@ func (d *DianeStatusProvider) ToggleJob(name string, enabled bool) error {
 	return jobStore.UpdateJob(ctx, job.ID, nil, nil, &enabled)
 }
 
+// PauseJob stops a job firing until the given time, or until resumed when
+// until is nil
+func (d *DianeStatusProvider) PauseJob(name string, until *time.Time) error {
+	if jobStore == nil {
+		return fmt.Errorf("job store not initialized")
+	}
+
+	ctx := context.Background()
+	job, err := jobStore.GetJobByName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("job not found: %s", name)
+	}
+
+	return jobStore.SetPaused(ctx, job.ID, true, until)
+}
+
+// ResumeJob lifts a job's pause
+func (d *DianeStatusProvider) ResumeJob(name string) error {
+	if jobStore == nil {
+		return fmt.Errorf("job store not initialized")
+	}
+
+	ctx := context.Background()
+	job, err := jobStore.GetJobByName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("job not found: %s", name)
+	}
+
+	return jobStore.SetPaused(ctx, job.ID, false, nil)
+}
+
+// ImportJobs creates or updates a job for each spec and, with prune, deletes
+// jobs not among them. Specs are expected to be validated already. A job that
+// fails to apply is reported in its result without stopping the others.
+func (d *DianeStatusProvider) ImportJobs(specs []api.JobSpec, prune bool) ([]api.JobImportResult, error) {
+	if jobStore == nil {
+		return nil, fmt.Errorf("job store not initialized")
+	}
+
+	ctx := context.Background()
+	existing, err := jobStore.ListJobs(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*db.Job, len(existing))
+	for _, j := range existing {
+		byName[j.Name] = j
+	}
+
+	results := make([]api.JobImportResult, 0, len(specs))
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[spec.Name] = true
+		result := api.JobImportResult{Name: spec.Name}
+
+		enabled := spec.Enabled == nil || *spec.Enabled
+		actionType := spec.ActionType
+		if actionType == "" {
+			actionType = "shell"
+		}
+		var agentName *string
+		if spec.AgentName != "" {
+			agentName = &spec.AgentName
+		}
+		concurrency, _ := cron.ParseConcurrency(spec.Concurrency) // validated by the API
+		outputFormat, _ := cron.ParseOutputFormat(spec.OutputFormat)
+
+		job, ok := byName[spec.Name]
+		switch {
+		case !ok:
+			result.Action = "created"
+			var created *db.Job
+			created, err = jobStore.CreateJobWithAction(ctx, spec.Name, spec.Command, spec.Schedule, actionType, agentName)
+			if err == nil && created.Enabled != enabled {
+				err = jobStore.UpdateJob(ctx, created.ID, nil, nil, &enabled)
+			}
+			if err == nil && spec.MaxOutputBytes > 0 {
+				err = jobStore.SetMaxOutputBytes(ctx, created.ID, spec.MaxOutputBytes)
+			}
+			if err == nil && spec.TimeoutSeconds > 0 {
+				err = jobStore.SetTimeout(ctx, created.ID, spec.TimeoutSeconds)
+			}
+			if err == nil {
+				err = jobStore.SetConcurrency(ctx, created.ID, concurrency)
+			}
+			if err == nil && (len(spec.Env) > 0 || spec.Workdir != "") {
+				err = jobStore.SetRunEnvironment(ctx, created.ID, spec.Env, spec.Workdir)
+			}
+			if err == nil && outputFormat != "" {
+				err = jobStore.SetOutputFormat(ctx, created.ID, outputFormat)
+			}
+			if err == nil && spec.NotifyTemplate != "" {
+				err = jobStore.SetNotifyTemplate(ctx, created.ID, spec.NotifyTemplate)
+			}
+		case jobMatchesSpec(job, spec, enabled, actionType, concurrency, outputFormat):
+			result.Action = "unchanged"
+			err = nil
+		default:
+			result.Action = "updated"
+			err = jobStore.UpdateJobFull(ctx, job.ID, &spec.Command, &spec.Schedule, &enabled, &actionType, agentName)
+			if err == nil && job.MaxOutputBytes != spec.MaxOutputBytes {
+				err = jobStore.SetMaxOutputBytes(ctx, job.ID, spec.MaxOutputBytes)
+			}
+			if err == nil && job.TimeoutSeconds != spec.TimeoutSeconds {
+				err = jobStore.SetTimeout(ctx, job.ID, spec.TimeoutSeconds)
+			}
+			if err == nil && jobConcurrency(job) != concurrency {
+				err = jobStore.SetConcurrency(ctx, job.ID, concurrency)
+			}
+			if err == nil && !jobRunEnvironmentMatches(job, spec) {
+				err = jobStore.SetRunEnvironment(ctx, job.ID, spec.Env, spec.Workdir)
+			}
+			if err == nil && job.OutputFormat != outputFormat {
+				err = jobStore.SetOutputFormat(ctx, job.ID, outputFormat)
+			}
+			if err == nil && job.NotifyTemplate != spec.NotifyTemplate {
+				err = jobStore.SetNotifyTemplate(ctx, job.ID, spec.NotifyTemplate)
+			}
+		}
+		if err != nil {
+			result.Action = "failed"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	// Dependencies are set once every job in the file exists, so a job can
+	// depend on one defined after it
+	for i, spec := range specs {
+		job, ok := byName[spec.Name]
+		if results[i].Action == "failed" || (ok && job.DependsOn == spec.DependsOn) || (!ok && spec.DependsOn == "") {
+			continue
+		}
+		if !ok {
+			if job, err = jobStore.GetJobByName(ctx, spec.Name); err != nil {
+				results[i].Action, results[i].Error = "failed", err.Error()
+				continue
+			}
+		}
+		if err := jobs.CheckDependsOn(ctx, jobStore, spec.Name, spec.DependsOn); err != nil {
+			results[i].Action, results[i].Error = "failed", err.Error()
+			continue
+		}
+		if err := jobStore.SetDependsOn(ctx, job.ID, spec.DependsOn); err != nil {
+			results[i].Action, results[i].Error = "failed", err.Error()
+		}
+	}
+
+	if prune {
+		for _, j := range existing {
+			if wanted[j.Name] {
+				continue
+			}
+			result := api.JobImportResult{Name: j.Name, Action: "deleted"}
+			if err := jobStore.DeleteJob(ctx, j.ID); err != nil {
+				result.Action = "failed"
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// jobMatchesSpec reports whether importing spec would leave job as it is
+func jobMatchesSpec(job *db.Job, spec api.JobSpec, enabled bool, actionType, concurrency, outputFormat string) bool {
+	agentName := ""
+	if job.AgentName != nil {
+		agentName = *job.AgentName
+	}
+	return job.Command == spec.Command && job.Schedule == spec.Schedule && job.Enabled == enabled &&
+		job.ActionType == actionType && agentName == spec.AgentName && job.MaxOutputBytes == spec.MaxOutputBytes &&
+		job.TimeoutSeconds == spec.TimeoutSeconds && job.DependsOn == spec.DependsOn && job.OutputFormat == outputFormat &&
+		job.NotifyTemplate == spec.NotifyTemplate && jobConcurrency(job) == concurrency && jobRunEnvironmentMatches(job, spec)
+}
+
+// ListNotifyTemplates returns the saved notify templates
+func (d *DianeStatusProvider) ListNotifyTemplates() ([]api.NotifyTemplate, error) {
+	if notifyTemplateStore == nil {
+		return nil, fmt.Errorf("notify template store not initialized")
+	}
+	list, err := notifyTemplateStore.ListNotifyTemplates(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]api.NotifyTemplate, 0, len(list))
+	for _, t := range list {
+		result = append(result, api.NotifyTemplate{Name: t.Name, Body: t.Body, UpdatedAt: t.UpdatedAt})
+	}
+	return result, nil
+}
+
+// SaveNotifyTemplate creates or replaces a notify template once its body
+// parses
+func (d *DianeStatusProvider) SaveNotifyTemplate(t api.NotifyTemplate) error {
+	if notifyTemplateStore == nil {
+		return fmt.Errorf("notify template store not initialized")
+	}
+	if _, err := notify.Parse(t.Name, t.Body); err != nil {
+		return err
+	}
+	return notifyTemplateStore.SaveNotifyTemplate(context.Background(), store.NotifyTemplate{
+		Name:      t.Name,
+		Body:      t.Body,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// TestNotifyTemplate renders a saved notify template as a failed run of an
+// example job would, with data overriding those values
+func (d *DianeStatusProvider) TestNotifyTemplate(name string, data map[string]any) (string, error) {
+	if notifyTemplateStore == nil {
+		return "", fmt.Errorf("notify template store not initialized")
+	}
+	t, err := notifyTemplateStore.GetNotifyTemplate(context.Background(), name)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	exitCode := 1
+	values := notify.JobData(&db.Job{Name: "example-job"}, &db.JobExecution{
+		StartedAt: now,
+		EndedAt:   &now,
+		ExitCode:  &exitCode,
+		Stderr:    "example output",
+	})
+	maps.Copy(values, data)
+	return notify.Render(t.Name, t.Body, values)
+}
+
+// notifyJobFailure sends a failed run's notification by rendering its job's
+// notify template. It sends in the background so the run isn't held up.
+func notifyJobFailure(job *db.Job, e *db.JobExecution) {
+	if notificationsProvider == nil {
+		slog.Warn("Job failure notification not sent: notifications not available", "job", job.Name)
+		return
+	}
+	go func() {
+		message, err := notificationsProvider.RenderTemplate(context.Background(), job.NotifyTemplate, notify.JobData(job, e))
+		if err != nil {
+			slog.Warn("Job failure notification not sent", "job", job.Name, "template", job.NotifyTemplate, "error", err)
+			return
+		}
+		if err := notificationsProvider.Notify("", message); err != nil {
+			slog.Warn("Job failure notification not sent", "job", job.Name, "error", err)
+		}
+	}()
+}
+
+// jobRunEnvironmentMatches reports whether job already has spec's environment
+// variables and working directory
+func jobRunEnvironmentMatches(job *db.Job, spec api.JobSpec) bool {
+	return maps.Equal(job.Env, spec.Env) && job.Workdir == spec.Workdir
+}
+
+// jobConcurrency returns a job's concurrency policy, filling in the default
+// for jobs stored before policies existed
+func jobConcurrency(job *db.Job) string {
+	if job.Concurrency == "" {
+		return cron.DefaultConcurrency
+	}
+	return job.Concurrency
+}
+
 // GetAgentLogs returns agent communication logs
 func (d *DianeStatusProvider) GetAgentLogs(agentName string, limit int) ([]api.AgentLog, error) {
 	if agentStore == nil {
@@ -603,7 +1071,7 @@ func (d *DianeStatusProvider) StartOAuthLogin(serverName string) (*api.DeviceCod
 }
 
 // PollOAuthToken polls for the OAuth token after user authorization
-func (d *DianeStatusProvider) PollOAuthToken(serverName string, deviceCode string, interval int) error {
+func (d *DianeStatusProvider) PollOAuthToken(serverName string, deviceCode string, interval int, expiresIn int) error {
 	if proxy == nil {
 		return fmt.Errorf("proxy not initialized")
 	}
@@ -627,7 +1095,7 @@ func (d *DianeStatusProvider) PollOAuthToken(serverName string, deviceCode strin
 		return fmt.Errorf("OAuth manager not available")
 	}
 
-	_, err := oauthMgr.PollForToken(serverName, providerConfig, deviceCode, interval)
+	_, err := oauthMgr.PollForToken(serverName, providerConfig, deviceCode, interval, expiresIn)
 	if err != nil {
 		return err
 	}
@@ -651,8 +1119,111 @@ func (d *DianeStatusProvider) DeleteOAuthToken(serverName string) error {
 	return oauthMgr.DeleteToken(serverName)
 }
 
-// GetAllTools returns detailed information about all available tools
+// SetOAuthToken stores a token the user already has for a server, skipping
+// the device flow, and restarts the server with it. A server that fails to
+// start with the token is reported as a warning rather than an error, since
+// the token is stored either way.
+func (d *DianeStatusProvider) SetOAuthToken(serverName string, imported api.OAuthTokenImport) (*api.OAuthTokenResult, error) {
+	if proxy == nil {
+		return nil, fmt.Errorf("proxy not initialized")
+	}
+
+	config := proxy.GetServerConfig(serverName)
+	if config == nil {
+		return nil, fmt.Errorf("server not found: %s", serverName)
+	}
+
+	if config.OAuth == nil {
+		return nil, fmt.Errorf("server %s does not have OAuth configured", serverName)
+	}
+
+	oauthMgr := mcpproxy.GetOAuthManager()
+	if oauthMgr == nil {
+		return nil, fmt.Errorf("OAuth manager not available")
+	}
+
+	token := &mcpproxy.OAuthToken{
+		AccessToken:  imported.AccessToken,
+		TokenType:    imported.TokenType,
+		RefreshToken: imported.RefreshToken,
+		ExpiresAt:    imported.ExpiresAt,
+	}
+	if token.TokenType == "" {
+		token.TokenType = "Bearer"
+	}
+	if token.IsExpired() {
+		return nil, fmt.Errorf("token for %s has already expired", serverName)
+	}
+	if err := oauthMgr.SetToken(serverName, token); err != nil {
+		return nil, fmt.Errorf("failed to save token: %w", err)
+	}
+
+	return restartWithToken(serverName, token), nil
+}
+
+// RefreshOAuthToken forces a refresh of a server's token with its stored
+// refresh token and restarts the server with the new one
+func (d *DianeStatusProvider) RefreshOAuthToken(serverName string) (*api.OAuthTokenResult, error) {
+	if proxy == nil {
+		return nil, fmt.Errorf("proxy not initialized")
+	}
+
+	config := proxy.GetServerConfig(serverName)
+	if config == nil {
+		return nil, fmt.Errorf("server not found: %s", serverName)
+	}
+
+	if config.OAuth == nil {
+		return nil, fmt.Errorf("server %s does not have OAuth configured", serverName)
+	}
+
+	providerConfig := mcpproxy.GetProviderConfig(config.OAuth)
+	if providerConfig == nil {
+		return nil, fmt.Errorf("invalid OAuth configuration for server %s", serverName)
+	}
+
+	oauthMgr := mcpproxy.GetOAuthManager()
+	if oauthMgr == nil {
+		return nil, fmt.Errorf("OAuth manager not available")
+	}
+
+	token, err := oauthMgr.RefreshToken(serverName, providerConfig)
+	if errors.Is(err, mcpproxy.ErrNoRefreshToken) {
+		return nil, fmt.Errorf("no refresh token stored for %s; run 'diane auth login %s' to sign in again", serverName, serverName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return restartWithToken(serverName, token), nil
+}
+
+// restartWithToken restarts a server after its token changed. Starting the
+// server is the only check of the token available, so a failed start is
+// returned as a warning that the token may be invalid.
+func restartWithToken(serverName string, token *mcpproxy.OAuthToken) *api.OAuthTokenResult {
+	result := &api.OAuthTokenResult{Status: "authenticated"}
+	if !token.ExpiresAt.IsZero() {
+		expiresAt := token.ExpiresAt
+		result.ExpiresAt = &expiresAt
+	}
+
+	slog.Info("OAuth token updated, restarting server", "server", serverName)
+	if err := proxy.RestartServer(serverName); err != nil {
+		slog.Warn("Failed to restart server with new OAuth token", "server", serverName, "error", err)
+		result.Warning = fmt.Sprintf("token saved, but the server did not start with it (it may be invalid): %v", err)
+	}
+	return result
+}
+
+// GetAllTools returns detailed information about all available tools, with
+// any tool customizations applied
 func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
+	return sortToolInfos(customizeToolInfos(categorizeToolInfos(d.uncustomizedTools())))
+}
+
+// uncustomizedTools returns every available tool as its server defines it
+func (d *DianeStatusProvider) uncustomizedTools() []api.ToolInfo {
 	var tools []api.ToolInfo
 
 	// Built-in job tools
@@ -665,6 +1236,7 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 		{"job_pause", "Pause all job execution temporarily"},
 		{"job_resume", "Resume paused job execution"},
 		{"job_logs", "View recent execution logs for a job"},
+		{"job_retry", "Re-run the job behind a failed execution"},
 		{"server_status", "Get Diane server status and statistics"},
 	}
 	for _, t := range builtinTools {
@@ -676,9 +1248,21 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 		})
 	}
 
+	// Context management tools (callable from the admin context only) and
+	// context discovery (callable from any)
+	for _, t := range append(contextTools(), contextDiscoverTool()) {
+		tools = append(tools, api.ToolInfo{
+			Name:        t["name"].(string),
+			Description: t["description"].(string),
+			Server:      "contexts",
+			Builtin:     true,
+			InputSchema: t["inputSchema"].(map[string]interface{}),
+		})
+	}
+
 	// Apple tools
 	if appleProvider != nil {
-		for _, tool := range appleProvider.Tools() {
+		for _, tool := range providerTools("apple", appleProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
@@ -691,7 +1275,7 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 
 	// Google tools
 	if googleProvider != nil {
-		for _, tool := range googleProvider.Tools() {
+		for _, tool := range providerTools("google", googleProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
@@ -704,7 +1288,7 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 
 	// Infrastructure tools
 	if infrastructureProvider != nil {
-		for _, tool := range infrastructureProvider.Tools() {
+		for _, tool := range providerTools("infrastructure", infrastructureProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
@@ -717,7 +1301,7 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 
 	// Notifications tools
 	if notificationsProvider != nil {
-		for _, tool := range notificationsProvider.Tools() {
+		for _, tool := range providerTools("discord", notificationsProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
@@ -730,7 +1314,7 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 
 	// Finance tools
 	if financeProvider != nil {
-		for _, tool := range financeProvider.Tools() {
+		for _, tool := range providerTools("finance", financeProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
@@ -743,7 +1327,7 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 
 	// Places tools
 	if placesProvider != nil {
-		for _, tool := range placesProvider.Tools() {
+		for _, tool := range providerTools("places", placesProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
@@ -756,7 +1340,7 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 
 	// Weather tools
 	if weatherProvider != nil {
-		for _, tool := range weatherProvider.Tools() {
+		for _, tool := range providerTools("weather", weatherProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
@@ -767,46 +1351,72 @@ func (d *DianeStatusProvider) GetAllTools() []api.ToolInfo {
 		}
 	}
 
-	// GitHub tools
-	if githubProvider != nil {
-		for _, tool := range githubProvider.Tools() {
+	// HTTP request tool
+	if httpRequestProvider != nil {
+		for _, tool := range providerTools("http_request", httpRequestProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
-				Server:      "github-bot",
+				Server:      "http_request",
 				Builtin:     true,
 				InputSchema: tool.InputSchema,
 			})
 		}
 	}
 
-	// Downloads tools
-	if downloadsProvider != nil {
-		for _, tool := range downloadsProvider.Tools() {
+	// Shell exec tool
+	if shellExecProvider != nil {
+		for _, tool := range providerTools("shell_exec", shellExecProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
-				Server:      "downloads",
+				Server:      "shell_exec",
 				Builtin:     true,
 				InputSchema: tool.InputSchema,
 			})
 		}
 	}
 
-	// Files tools
-	if filesProvider != nil {
-		for _, tool := range filesProvider.Tools() {
+	// GitHub tools
+	if githubProvider != nil {
+		for _, tool := range providerTools("github-bot", githubProvider.Tools) {
 			tools = append(tools, api.ToolInfo{
 				Name:        tool.Name,
 				Description: tool.Description,
-				Server:      "file_registry",
+				Server:      "github-bot",
 				Builtin:     true,
 				InputSchema: tool.InputSchema,
 			})
 		}
 	}
 
-	// External MCP server tools (from proxy)
+	// Downloads tools
+	if downloadsProvider != nil {
+		for _, tool := range providerTools("downloads", downloadsProvider.Tools) {
+			tools = append(tools, api.ToolInfo{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Server:      "downloads",
+				Builtin:     true,
+				InputSchema: tool.InputSchema,
+			})
+		}
+	}
+
+	// Files tools
+	if filesProvider != nil {
+		for _, tool := range providerTools("file_registry", filesProvider.Tools) {
+			tools = append(tools, api.ToolInfo{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Server:      "file_registry",
+				Builtin:     true,
+				InputSchema: tool.InputSchema,
+			})
+		}
+	}
+
+	// External MCP server tools (from proxy)
 	if proxy != nil {
 		proxiedTools, err := proxy.ListAllTools()
 		if err == nil {
@@ -849,8 +1459,101 @@ func (d *DianeStatusProvider) ListTools() ([]map[string]interface{}, error) {
 	return result, nil
 }
 
+// ClockOffset measures the local clock against the master's when running as
+// a slave that has heard from its master, and against an NTP server otherwise
+func (d *DianeStatusProvider) ClockOffset() (*api.ClockOffset, error) {
+	if slaveClient != nil {
+		if offset, at, ok := slaveClient.MasterClockOffset(); ok {
+			return &api.ClockOffset{Source: "master", Offset: offset, MeasuredAt: at}, nil
+		}
+	}
+	offset, err := ntp.Offset(ntpServer, 3*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ClockOffset{Source: "ntp " + ntpServer, Offset: offset, MeasuredAt: time.Now()}, nil
+}
+
+// backendCheckTimeout bounds each backend check run by doctor
+const backendCheckTimeout = 5 * time.Second
+
+// CheckBackends makes a cheap authenticated call to Emergent and to the
+// backend of each enabled provider that has one, in parallel. Enabled
+// servers that failed to start are reported as not configured.
+func (d *DianeStatusProvider) CheckBackends(ctx context.Context) []api.DoctorCheck {
+	checkers := map[string]func(context.Context) error{"emergent": checkEmergent}
+	providers := map[string]tools.BackendChecker{}
+	if googleProvider != nil {
+		providers["google"] = googleProvider
+	}
+	if infrastructureProvider != nil {
+		providers["infrastructure"] = infrastructureProvider
+	}
+	if notificationsProvider != nil {
+		providers["discord"] = notificationsProvider
+	}
+	if githubProvider != nil {
+		providers["github-bot"] = githubProvider
+	}
+	for name, provider := range providers {
+		checkers[name] = provider.CheckBackend
+	}
+	for name, err := range builtinSetupErrors {
+		err := fmt.Errorf("%s %w: %v", name, tools.ErrNotConfigured, err)
+		checkers[name] = func(context.Context) error { return err }
+	}
+
+	names := make([]string, 0, len(checkers))
+	for name := range checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checks := make([]api.DoctorCheck, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, backendCheckTimeout)
+			defer cancel()
+			err := checkers[name](checkCtx)
+			check := api.DoctorCheck{Name: "backend_" + name, Status: tools.BackendCheckStatus(err)}
+			if err != nil {
+				check.Message = err.Error()
+			} else {
+				check.Message = fmt.Sprintf("%s backend reachable and credentials accepted", name)
+			}
+			checks[i] = check
+		}()
+	}
+	wg.Wait()
+	return checks
+}
+
+// checkEmergent verifies the Emergent API key, which jobs, the file
+// registry and the Gmail cache all depend on
+func checkEmergent(ctx context.Context) error {
+	cfg, err := emergent.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("emergent %w: %v", tools.ErrNotConfigured, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(cfg.BaseURL, "/")+"/api/auth/me", nil)
+	if err != nil {
+		return fmt.Errorf("emergent %w: bad base URL: %v", tools.ErrNotConfigured, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	return tools.CheckHTTP("emergent", req)
+}
+
 // CallTool implements mcpproxy.ToolProvider for the slave client
 func (d *DianeStatusProvider) CallTool(name string, arguments map[string]interface{}) (map[string]interface{}, error) {
+	return d.CallToolContext(context.Background(), name, arguments)
+}
+
+// CallToolContext implements mcpproxy.ContextToolProvider, so calls from the
+// master are logged with the master's request ID
+func (d *DianeStatusProvider) CallToolContext(ctx context.Context, name string, arguments map[string]interface{}) (map[string]interface{}, error) {
 	// Construct the params object expected by callTool
 	params := map[string]interface{}{
 		"name":      name,
@@ -862,7 +1565,7 @@ func (d *DianeStatusProvider) CallTool(name string, arguments map[string]interfa
 	}
 
 	// Call the local tool handler
-	resp := callTool(paramsBytes)
+	resp := callTool(ctx, paramsBytes)
 
 	// Check for errors
 	if resp.Error != nil {
@@ -874,6 +1577,14 @@ func (d *DianeStatusProvider) CallTool(name string, arguments map[string]interfa
 		return result, nil
 	}
 
+	// Results truncated to the size cap come back encoded
+	if raw, ok := resp.Result.(json.RawMessage); ok {
+		var result map[string]interface{}
+		if err := json.Unmarshal(raw, &result); err == nil {
+			return result, nil
+		}
+	}
+
 	// If result is nil or not a map, return empty map (success but no data)
 	if resp.Result == nil {
 		return map[string]interface{}{}, nil
@@ -944,6 +1655,25 @@ func (d *DianeStatusProvider) GetAllPrompts() []api.PromptInfo {
 		}
 	}
 
+	// Daily briefing prompt
+	if availableBriefingSources(nil).any() {
+		args := make([]api.PromptArgument, 0, len(briefingPrompt.Arguments))
+		for _, a := range briefingPrompt.Arguments {
+			args = append(args, api.PromptArgument{
+				Name:        a.Name,
+				Description: a.Description,
+				Required:    a.Required,
+			})
+		}
+		prompts = append(prompts, api.PromptInfo{
+			Name:        briefingPrompt.Name,
+			Description: briefingPrompt.Description,
+			Server:      "briefing",
+			Builtin:     true,
+			Arguments:   args,
+		})
+	}
+
 	// External MCP server prompts (from proxy)
 	if proxy != nil {
 		proxiedPrompts, err := proxy.ListAllPrompts()
@@ -1083,6 +1813,12 @@ func (d *DianeStatusProvider) GetPromptContent(serverName string, promptName str
 			}
 		}
 		return nil, fmt.Errorf("discord provider not available")
+	case "briefing":
+		msgs := getBriefingPrompt(promptName, map[string]string{}, availableBriefingSources(nil))
+		if msgs == nil {
+			return nil, fmt.Errorf("prompt not found: %s/%s", serverName, promptName)
+		}
+		return json.Marshal(map[string]interface{}{"messages": msgs})
 	}
 
 	// External MCP server - use proxy
@@ -1095,6 +1831,29 @@ func (d *DianeStatusProvider) GetPromptContent(serverName string, promptName str
 	return nil, fmt.Errorf("prompt not found: %s/%s", serverName, promptName)
 }
 
+// CallToolInContext calls a tool through the same dispatch MCP clients use,
+// including slave routing and, when contextName is set, context filtering
+func (d *DianeStatusProvider) CallToolInContext(name string, args map[string]interface{}, contextName string) (json.RawMessage, error) {
+	params, err := json.Marshal(map[string]interface{}{"name": name, "arguments": args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal params: %w", err)
+	}
+
+	ctx := logger.WithRequestID(context.Background(), logger.NewRequestID())
+	var resp MCPResponse
+	if contextName == "" {
+		resp = routedToolCall(ctx, params, "", callTool)
+	} else {
+		resp = routedToolCall(ctx, params, contextName, func(ctx context.Context, params json.RawMessage) MCPResponse {
+			return callToolForContext(ctx, params, contextName)
+		})
+	}
+	if resp.Error != nil {
+		return nil, &api.ToolCallError{Code: resp.Error.Code, Message: resp.Error.Message}
+	}
+	return json.Marshal(resp.Result)
+}
+
 // ReadResourceContent returns the full content of a resource
 func (d *DianeStatusProvider) ReadResourceContent(serverName string, uri string) (json.RawMessage, error) {
 	// Check builtin providers first
@@ -1108,6 +1867,9 @@ func (d *DianeStatusProvider) ReadResourceContent(serverName string, uri string)
 				if err != nil {
 					return nil, err
 				}
+				if err := checkBuiltinResource(content); err != nil {
+					return nil, err
+				}
 				return json.Marshal(map[string]interface{}{
 					"contents": []interface{}{content},
 				})
@@ -1123,6 +1885,9 @@ func (d *DianeStatusProvider) ReadResourceContent(serverName string, uri string)
 				if err != nil {
 					return nil, err
 				}
+				if err := checkBuiltinResource(content); err != nil {
+					return nil, err
+				}
 				return json.Marshal(map[string]interface{}{
 					"contents": []interface{}{content},
 				})
@@ -1168,6 +1933,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 		{"job_pause", "Pause all job execution temporarily"},
 		{"job_resume", "Resume paused job execution"},
 		{"job_logs", "View recent execution logs for a job"},
+		{"job_retry", "Re-run the job behind a failed execution"},
 		{"server_status", "Get Diane server status and statistics"},
 	}
 	for _, t := range builtinTools {
@@ -1181,6 +1947,21 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 		}
 	}
 
+	// Context management tools are only offered to the admin context;
+	// discovery is offered to every context
+	contextToolDefs := []map[string]interface{}{contextDiscoverTool()}
+	if contextName == adminContextName {
+		contextToolDefs = append(contextTools(), contextToolDefs...)
+	}
+	for _, t := range contextToolDefs {
+		tools = append(tools, api.ToolInfo{
+			Name:        t["name"].(string),
+			Description: t["description"].(string),
+			Server:      "contexts",
+			Builtin:     true,
+		})
+	}
+
 	// Helper function to check and add provider tools
 	addProviderTools := func(providerTools []struct{ Name, Description string }, serverName string) {
 		for _, tool := range providerTools {
@@ -1197,7 +1978,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Apple tools
 	if appleProvider != nil {
-		for _, tool := range appleProvider.Tools() {
+		for _, tool := range providerTools("apple", appleProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "apple", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1211,7 +1992,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Google tools
 	if googleProvider != nil {
-		for _, tool := range googleProvider.Tools() {
+		for _, tool := range providerTools("google", googleProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "google", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1225,7 +2006,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Infrastructure tools
 	if infrastructureProvider != nil {
-		for _, tool := range infrastructureProvider.Tools() {
+		for _, tool := range providerTools("infrastructure", infrastructureProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "infrastructure", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1239,7 +2020,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Notifications tools
 	if notificationsProvider != nil {
-		for _, tool := range notificationsProvider.Tools() {
+		for _, tool := range providerTools("discord", notificationsProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "discord", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1253,7 +2034,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Finance tools
 	if financeProvider != nil {
-		for _, tool := range financeProvider.Tools() {
+		for _, tool := range providerTools("finance", financeProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "finance", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1267,7 +2048,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Places tools
 	if placesProvider != nil {
-		for _, tool := range placesProvider.Tools() {
+		for _, tool := range providerTools("places", placesProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "places", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1281,7 +2062,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Weather tools
 	if weatherProvider != nil {
-		for _, tool := range weatherProvider.Tools() {
+		for _, tool := range providerTools("weather", weatherProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "weather", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1293,9 +2074,37 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 		}
 	}
 
+	// HTTP request tool
+	if httpRequestProvider != nil {
+		for _, tool := range providerTools("http_request", httpRequestProvider.Tools) {
+			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "http_request", tool.Name); enabled {
+				tools = append(tools, api.ToolInfo{
+					Name:        tool.Name,
+					Description: tool.Description,
+					Server:      "http_request",
+					Builtin:     true,
+				})
+			}
+		}
+	}
+
+	// Shell exec tool
+	if shellExecProvider != nil {
+		for _, tool := range providerTools("shell_exec", shellExecProvider.Tools) {
+			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "shell_exec", tool.Name); enabled {
+				tools = append(tools, api.ToolInfo{
+					Name:        tool.Name,
+					Description: tool.Description,
+					Server:      "shell_exec",
+					Builtin:     true,
+				})
+			}
+		}
+	}
+
 	// GitHub tools
 	if githubProvider != nil {
-		for _, tool := range githubProvider.Tools() {
+		for _, tool := range providerTools("github-bot", githubProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "github-bot", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1309,7 +2118,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Downloads tools
 	if downloadsProvider != nil {
-		for _, tool := range downloadsProvider.Tools() {
+		for _, tool := range providerTools("downloads", downloadsProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "downloads", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1323,7 +2132,7 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 
 	// Files tools
 	if filesProvider != nil {
-		for _, tool := range filesProvider.Tools() {
+		for _, tool := range providerTools("file_registry", filesProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "file_registry", tool.Name); enabled {
 				tools = append(tools, api.ToolInfo{
 					Name:        tool.Name,
@@ -1358,41 +2167,47 @@ func (d *DianeStatusProvider) GetToolsForContext(contextName string) []api.ToolI
 	// Suppress unused variable warning
 	_ = addProviderTools
 
-	return tools
+	return sortToolInfos(customizeToolInfos(categorizeToolInfos(tools)))
 }
 
 func (d *DianeStatusProvider) countTotalTools() int {
 	total := 9 // Built-in job tools count
 
 	if appleProvider != nil {
-		total += len(appleProvider.Tools())
+		total += len(providerTools("apple", appleProvider.Tools))
 	}
 	if googleProvider != nil {
-		total += len(googleProvider.Tools())
+		total += len(providerTools("google", googleProvider.Tools))
 	}
 	if infrastructureProvider != nil {
-		total += len(infrastructureProvider.Tools())
+		total += len(providerTools("infrastructure", infrastructureProvider.Tools))
 	}
 	if notificationsProvider != nil {
-		total += len(notificationsProvider.Tools())
+		total += len(providerTools("discord", notificationsProvider.Tools))
 	}
 	if financeProvider != nil {
-		total += len(financeProvider.Tools())
+		total += len(providerTools("finance", financeProvider.Tools))
 	}
 	if placesProvider != nil {
-		total += len(placesProvider.Tools())
+		total += len(providerTools("places", placesProvider.Tools))
 	}
 	if weatherProvider != nil {
-		total += len(weatherProvider.Tools())
+		total += len(providerTools("weather", weatherProvider.Tools))
+	}
+	if httpRequestProvider != nil {
+		total += len(providerTools("http_request", httpRequestProvider.Tools))
+	}
+	if shellExecProvider != nil {
+		total += len(providerTools("shell_exec", shellExecProvider.Tools))
 	}
 	if githubProvider != nil {
-		total += len(githubProvider.Tools())
+		total += len(providerTools("github-bot", githubProvider.Tools))
 	}
 	if downloadsProvider != nil {
-		total += len(downloadsProvider.Tools())
+		total += len(providerTools("downloads", downloadsProvider.Tools))
 	}
 	if filesProvider != nil {
-		total += len(filesProvider.Tools())
+		total += len(providerTools("file_registry", filesProvider.Tools))
 	}
 	if proxy != nil {
 		total += proxy.GetTotalToolCount()
@@ -1427,10 +2242,11 @@ type MCPHandlerAdapter struct {
 func (h *MCPHandlerAdapter) HandleRequest(req api.MCPRequest) api.MCPResponse {
 	// Convert api.MCPRequest to local MCPRequest
 	localReq := MCPRequest{
-		JSONRPC: req.JSONRPC,
-		ID:      req.ID,
-		Method:  req.Method,
-		Params:  req.Params,
+		JSONRPC:   req.JSONRPC,
+		ID:        req.ID,
+		Method:    req.Method,
+		Params:    req.Params,
+		RequestID: req.RequestID,
 	}
 
 	// Call the existing handleRequest function
@@ -1467,10 +2283,11 @@ func (h *MCPHandlerAdapter) GetTools() ([]api.ToolInfo, error) {
 func (h *MCPHandlerAdapter) HandleRequestWithContext(req api.MCPRequest, contextName string) api.MCPResponse {
 	// Convert api.MCPRequest to local MCPRequest
 	localReq := MCPRequest{
-		JSONRPC: req.JSONRPC,
-		ID:      req.ID,
-		Method:  req.Method,
-		Params:  req.Params,
+		JSONRPC:   req.JSONRPC,
+		ID:        req.ID,
+		Method:    req.Method,
+		Params:    req.Params,
+		RequestID: req.RequestID,
 	}
 
 	// Call the context-aware handleRequest function
@@ -1508,6 +2325,9 @@ type MCPRequest struct {
 	ID      interface{}     `json:"id"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
+
+	// RequestID identifies the request in logs and proxied calls
+	RequestID string `json:"-"`
 }
 
 type MCPResponse struct {
@@ -1518,8 +2338,46 @@ type MCPResponse struct {
 }
 
 type MCPError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// errCodeRequestTimeout is the JSON-RPC error code MCP uses for timed-out requests
+const errCodeRequestTimeout = -32001
+
+// proxiedToolError converts an error from a proxied tool call into a
+// response, reporting ok=false if no proxied server has the tool. Timeouts
+// are protocol errors; anything else, such as an oversized result or a server
+// with no free call slot, is a tool failure carrying the proxy's message.
+func proxiedToolError(name string, err error) (MCPResponse, bool) {
+	if resp, ok := toolTimeoutResponse(err); ok {
+		return resp, true
+	}
+	if err.Error() == fmt.Sprintf("unknown tool: %s", name) {
+		return MCPResponse{}, false
+	}
+	return toolCallError(err), true
+}
+
+// toolTimeoutResponse converts a proxied tool timeout into an MCP error,
+// reporting ok=false if err is not a timeout.
+func toolTimeoutResponse(err error) (MCPResponse, bool) {
+	var timeoutErr *mcpproxy.ToolTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		return MCPResponse{}, false
+	}
+	return MCPResponse{
+		Error: &MCPError{
+			Code:    errCodeRequestTimeout,
+			Message: timeoutErr.Error(),
+			Data: map[string]interface{}{
+				"server":          timeoutErr.Server,
+				"tool":            timeoutErr.Tool,
+				"timeout_seconds": timeoutErr.Timeout.Seconds(),
+			},
+		},
+	}, true
 }
 
 // acquireLock tries to acquire an exclusive lock on the lock file.
@@ -1551,6 +2409,18 @@ func acquireLock(lockPath string) (*os.File, error) {
 	return file, nil
 }
 
+// reexecSelf replaces the current process with a fresh copy of the same
+// binary, keeping the original arguments and environment. It only returns on
+// failure.
+func reexecSelf() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+	slog.Info("Re-executing Diane", "path", exe, "args", os.Args)
+	return syscall.Exec(exe, os.Args, os.Environ())
+}
+
 // releaseLock releases the file lock and removes the lock file.
 func releaseLock(file *os.File, lockPath string) {
 	if file != nil {
@@ -1561,6 +2431,22 @@ func releaseLock(file *os.File, lockPath string) {
 }
 
 func main() {
+	// --profile picks the instance (directory, socket, ports) for both the
+	// CLI and the daemon, so it has to be applied before anything else
+	args, err := cli.ApplyProfileFlag(os.Args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// --config points the daemon (and commands that read config.json) at
+	// another config file
+	args, err = cli.ApplyConfigFlag(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = args
+
 	// --- Subcommand dispatch (before any server initialization) ---
 	// CTL commands (status, health, info, etc.) only need the API client,
 	// not the full server. Dispatch them immediately and exit.
@@ -1601,11 +2487,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Load configuration from ~/.diane/config.json (with env var overrides)
+	// Load configuration from ~/.diane/config.json or --config (with env var overrides)
 	cfg := config.Load()
 
 	// Initialize structured logging
-	logDir := filepath.Join(home, ".diane")
+	logDir := filepath.Join(home, config.DirName())
 	if err := logger.Init(logger.Config{
 		LogDir:    logDir,
 		Debug:     cfg.Debug,
@@ -1619,11 +2505,24 @@ func main() {
 	mode := "stdio"
 	if serveMode {
 		mode = "serve"
+		restartCh = make(chan struct{}, 1)
 	}
 	slog.Info("Diane server starting", "version", getVersion(), "pid", os.Getpid(), "mode", mode)
 
+	// Registered before any other cleanup so that, on a requested restart, the
+	// re-exec happens only after the lock, sockets and child servers are released.
+	restarting := false
+	defer func() {
+		if restarting {
+			if err := reexecSelf(); err != nil {
+				slog.Error("Failed to restart Diane", "error", err)
+				os.Exit(1)
+			}
+		}
+	}()
+
 	// Single instance check: try to acquire exclusive lock on lock file
-	lockFile := filepath.Join(home, ".diane", "diane.lock")
+	lockFile := filepath.Join(home, config.DirName(), "diane.lock")
 	lock, err := acquireLock(lockFile)
 	if err != nil {
 		logger.Fatal("Another instance of Diane is already running", "error", err)
@@ -1631,14 +2530,14 @@ func main() {
 	defer releaseLock(lock, lockFile)
 
 	// Write PID file for reload command
-	pidFile := filepath.Join(home, ".diane", "mcp.pid")
+	pidFile := filepath.Join(home, config.DirName(), "mcp.pid")
 	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d", os.Getpid())), 0644); err != nil {
 		slog.Warn("Failed to write PID file", "error", err)
 	}
 	defer os.Remove(pidFile)
 
 	// Initialize database (shared across jobs, agents, and legacy migration)
-	dbPath := filepath.Join(home, ".diane", "cron.db")
+	dbPath := filepath.Join(home, config.DirName(), "cron.db")
 	var err2 error
 	database, err2 = db.New(dbPath)
 	if err2 != nil {
@@ -1661,9 +2560,16 @@ func main() {
 		contextStore = store.NewEmergentContextStore(emergentClient)
 		mcpServerStore = store.NewEmergentMCPServerStore(emergentClient)
 		jobStore = store.NewEmergentJobStore(emergentClient)
-		executionStore = store.NewEmergentExecutionStore(emergentClient)
+		execStore := store.NewEmergentExecutionStore(emergentClient)
+		execStore.SetMaxOutputBytes(cfg.Jobs.MaxOutputBytes)
+		executionStore = execStore
 		agentStore = store.NewEmergentAgentStore(emergentClient)
-		slog.Info("Emergent stores initialized (slave, context, mcp_server, job, execution, agent)")
+		toolCustomizationStore = store.NewEmergentToolCustomizationStore(emergentClient)
+		notifyTemplateStore = store.NewEmergentNotifyTemplateStore(emergentClient)
+		slog.Info("Emergent stores initialized (slave, context, mcp_server, job, execution, agent, tool_customization, notify_template)")
+		if err := loadToolCustomizations(); err != nil {
+			slog.Warn("Failed to load tool customizations", "error", err)
+		}
 	}
 
 	// One-time migration: copy MCP servers, placements, contexts, and tool
@@ -1684,6 +2590,23 @@ func main() {
 	// Store slave config globally BEFORE creating the MCP proxy, so that
 	// LoadMCPServerConfigs() can use the correct hostID for placement filtering.
 	slaveConfig = cfg.Slave
+	if cfg.Proxy.MaxResultBytes > 0 {
+		maxResultBytes = cfg.Proxy.MaxResultBytes
+	}
+	if cfg.ToolCache.Disabled {
+		toolCache.SetEnabled(false)
+		slog.Info("Tool result cache disabled")
+	}
+	if cfg.NTPServer != "" {
+		ntpServer = cfg.NTPServer
+	}
+
+	// Pool settings apply to clients as they start, so set them first
+	mcpproxy.SetPoolConfig(mcpproxy.PoolConfig{
+		MaxIdleConnsPerHost: cfg.Proxy.Pool.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.Proxy.Pool.MaxConnsPerHost,
+		IdleConnTimeout:     time.Duration(cfg.Proxy.Pool.IdleConnTimeout) * time.Second,
+	})
 
 	// Initialize MCP proxy from Emergent-backed store
 	if mcpServerStore != nil {
@@ -1691,6 +2614,11 @@ func main() {
 		proxy, err = mcpproxy.NewProxy(provider)
 		if err != nil {
 			slog.Warn("Failed to initialize MCP proxy", "error", err)
+		} else {
+			if cfg.Proxy.ToolTimeout > 0 {
+				proxy.SetToolTimeout(time.Duration(cfg.Proxy.ToolTimeout) * time.Second)
+			}
+			proxy.SetMaxResultBytes(maxResultBytes)
 		}
 	} else {
 		slog.Warn("MCP proxy not available: MCP server store not initialized")
@@ -1703,7 +2631,7 @@ func main() {
 
 	// Initialize slave manager (for master/slave pairing)
 	if slaveStore != nil && proxy != nil {
-		dianeDir := filepath.Join(home, ".diane")
+		dianeDir := filepath.Join(home, config.DirName())
 		ca, err := slave.NewCertificateAuthority(dianeDir)
 		if err != nil {
 			slog.Warn("Failed to initialize CA for slave manager", "error", err)
@@ -1712,8 +2640,22 @@ func main() {
 			if err != nil {
 				slog.Warn("Failed to initialize slave manager", "error", err)
 			} else {
+				routing, err := slave.ParseRoutingPreference(cfg.Slave.ToolRouting)
+				if err != nil {
+					slog.Warn("Invalid slave.tool_routing, using local-first", "error", err)
+				}
+				slaveManager.SetRoutingPreference(routing)
+				if cfg.Slave.ReconnectGrace != 0 {
+					slaveManager.SetReconnectGrace(time.Duration(cfg.Slave.ReconnectGrace) * time.Second)
+				}
+				if err := slaveManager.GetPairingService().SetAutoApprove(cfg.Slave.AutoApproveHosts, cfg.Slave.AutoApproveToken); err != nil {
+					slog.Warn("Invalid slave auto-approval rules, pairing requires manual approval", "error", err)
+				} else if len(cfg.Slave.AutoApproveHosts) > 0 || cfg.Slave.AutoApproveToken != "" {
+					slog.Info("Slave pairing auto-approval enabled", "hosts", cfg.Slave.AutoApproveHosts, "token", cfg.Slave.AutoApproveToken != "")
+				}
+
 				// Initialize the slave server (doesn't start HTTP yet, just sets up handlers)
-				if err := slaveManager.StartServer(":8765", ca); err != nil {
+				if err := slaveManager.StartServer(fmt.Sprintf(":%d", config.MCPHTTPPort()), ca); err != nil {
 					slog.Warn("Failed to initialize slave server", "error", err)
 				} else {
 					slog.Info("Slave manager initialized")
@@ -1780,6 +2722,7 @@ func main() {
 	if isBuiltinEnabled("google") {
 		googleProvider = google.NewProvider()
 		if err := googleProvider.CheckDependencies(); err != nil {
+			builtinSetupErrors["google"] = err
 			slog.Warn("Google tools not available", "error", err)
 			googleProvider = nil
 		} else {
@@ -1793,6 +2736,7 @@ func main() {
 	if isBuiltinEnabled("infrastructure") {
 		infrastructureProvider = infrastructure.NewProvider()
 		if err := infrastructureProvider.CheckDependencies(); err != nil {
+			builtinSetupErrors["infrastructure"] = err
 			slog.Warn("Infrastructure tools not available", "error", err)
 			infrastructureProvider = nil
 		} else {
@@ -1806,19 +2750,25 @@ func main() {
 	if isBuiltinEnabled("discord") {
 		notificationsProvider = notifications.NewProvider()
 		if err := notificationsProvider.CheckDependencies(); err != nil {
+			builtinSetupErrors["discord"] = err
 			slog.Warn("Notifications tools not available", "error", err)
 			notificationsProvider = nil
 		} else {
+			if notifyTemplateStore != nil {
+				notificationsProvider.SetTemplateStore(notifyTemplateStore)
+			}
 			slog.Info("Notifications tools initialized successfully")
 		}
 	} else {
 		slog.Debug("Notifications tools disabled via placement configuration")
 	}
+	jobs.SetFailureHook(notifyJobFailure)
 
 	// Initialize Finance tools provider (Enable Banking, Actual Budget, Bank Sync, if enabled)
 	if isBuiltinEnabled("finance") {
 		financeProvider = finance.NewProvider()
 		if err := financeProvider.CheckDependencies(); err != nil {
+			builtinSetupErrors["finance"] = err
 			slog.Warn("Finance tools not available", "error", err)
 			financeProvider = nil
 		} else {
@@ -1832,6 +2782,7 @@ func main() {
 	if isBuiltinEnabled("places") {
 		placesProvider = places.NewProvider()
 		if err := placesProvider.CheckDependencies(); err != nil {
+			builtinSetupErrors["places"] = err
 			slog.Warn("Google Places tools not available", "error", err)
 			placesProvider = nil
 		} else {
@@ -1845,6 +2796,7 @@ func main() {
 	if isBuiltinEnabled("weather") {
 		weatherProvider = weather.NewProvider()
 		if err := weatherProvider.CheckDependencies(); err != nil {
+			builtinSetupErrors["weather"] = err
 			slog.Warn("Weather tools not available", "error", err)
 			weatherProvider = nil
 		} else {
@@ -1854,11 +2806,49 @@ func main() {
 		slog.Debug("Weather tools disabled via placement configuration")
 	}
 
+	// Initialize the generic HTTP request tool. It needs both the builtin
+	// server enabled and an explicit host allowlist in config.json.
+	if cfg.HTTPRequest.Enabled && isBuiltinEnabled("http_request") {
+		httpRequestProvider = httprequest.NewProvider(httprequest.Config{
+			AllowedHosts:     cfg.HTTPRequest.AllowedHosts,
+			MaxResponseBytes: cfg.HTTPRequest.MaxResponseBytes,
+			Timeout:          time.Duration(cfg.HTTPRequest.Timeout) * time.Second,
+		})
+		if err := httpRequestProvider.CheckDependencies(); err != nil {
+			slog.Warn("HTTP request tool not available", "error", err)
+			httpRequestProvider = nil
+		} else {
+			slog.Info("HTTP request tool initialized successfully")
+		}
+	} else {
+		slog.Debug("HTTP request tool disabled")
+	}
+
+	// Initialize the shell_exec tool. Like http_request it needs both the
+	// builtin server enabled and an explicit allowlist in config.json.
+	if cfg.ShellExec.Enabled && isBuiltinEnabled("shell_exec") {
+		shellExecProvider = shellexec.NewProvider(shellexec.Config{
+			AllowedCommands: cfg.ShellExec.AllowedCommands,
+			AllowShell:      cfg.ShellExec.AllowShellMetacharacters,
+			Timeout:         time.Duration(cfg.ShellExec.Timeout) * time.Second,
+			MaxOutputBytes:  cfg.ShellExec.MaxOutputBytes,
+		})
+		if err := shellExecProvider.CheckDependencies(); err != nil {
+			slog.Warn("Shell exec tool not available", "error", err)
+			shellExecProvider = nil
+		} else {
+			slog.Info("Shell exec tool initialized successfully")
+		}
+	} else {
+		slog.Debug("Shell exec tool disabled")
+	}
+
 	// Initialize GitHub Bot tools provider (if enabled)
 	if isBuiltinEnabled("github-bot") {
 		var githubErr error
 		githubProvider, githubErr = githubbot.NewProvider()
 		if githubErr != nil {
+			builtinSetupErrors["github-bot"] = githubErr
 			slog.Warn("GitHub Bot tools not available", "error", githubErr)
 			githubProvider = nil
 		} else {
@@ -1871,8 +2861,13 @@ func main() {
 	// Initialize Downloads tools provider (if enabled)
 	if isBuiltinEnabled("downloads") {
 		var downloadsErr error
-		downloadsProvider, downloadsErr = downloads.NewProvider()
+		downloadsProvider, downloadsErr = downloads.NewProvider(downloads.Config{
+			MaxBytes:            cfg.Downloads.MaxBytes,
+			AllowedContentTypes: cfg.Downloads.AllowedContentTypes,
+			AllowedHosts:        cfg.Downloads.AllowedHosts,
+		})
 		if downloadsErr != nil {
+			builtinSetupErrors["downloads"] = downloadsErr
 			slog.Warn("Downloads tools not available", "error", downloadsErr)
 			downloadsProvider = nil
 		} else {
@@ -1887,6 +2882,7 @@ func main() {
 		var filesErr error
 		filesProvider, filesErr = files.NewProvider()
 		if filesErr != nil {
+			builtinSetupErrors["file_registry"] = filesErr
 			slog.Warn("Files tools not available", "error", filesErr)
 			filesProvider = nil
 		} else {
@@ -1900,13 +2896,21 @@ func main() {
 			filesProvider.Close()
 		}
 	}()
+	// When the file registry is available, downloads_extract can index what
+	// it unpacks and drive_upload registers the copies it uploads
+	if downloadsProvider != nil && filesProvider != nil {
+		downloadsProvider.SetRegistrar(filesProvider.RegisterDirectory)
+	}
+	if googleProvider != nil && filesProvider != nil {
+		googleProvider.SetFileRegistry(filesProvider)
+	}
 
 	// Start the Unix socket API server for companion app
 	statusProvider := &DianeStatusProvider{}
 
 	// Initialize slave client (now that providers are ready)
 	if cfg.Slave.Enabled && cfg.Slave.MasterURL != "" {
-		dianeDir := filepath.Join(home, ".diane")
+		dianeDir := filepath.Join(home, config.DirName())
 		certPath := filepath.Join(dianeDir, "slave-cert.pem")
 		keyPath := filepath.Join(dianeDir, "slave-key.pem")
 		caPath := filepath.Join(dianeDir, "slave-ca-cert.pem")
@@ -1937,6 +2941,9 @@ func main() {
 						slog.Error("Failed to initialize slave client", "error", err, "master", cfg.Slave.MasterURL)
 					} else {
 						slaveClient = client
+						if cfg.Slave.CertRenewDays > 0 {
+							slaveClient.SetCertRenewBefore(time.Duration(cfg.Slave.CertRenewDays) * 24 * time.Hour)
+						}
 						// Wire up the proxy so master tools can be registered on the slave
 						if proxy != nil {
 							slaveClient.SetProxy(proxy)
@@ -1977,8 +2984,11 @@ func main() {
 
 	// Start the MCP HTTP/SSE server for network-based MCP clients
 	mcpHandler := &MCPHandlerAdapter{statusProvider: statusProvider}
-	// Use port 8765 for HTTP (standard) and 8766 for HTTPS (secure/slave)
-	mcpHTTPServer = api.NewMCPHTTPServer(statusProvider, mcpHandler, 8765, 8766)
+	// Use port 8765 for HTTP (standard) and 8766 for HTTPS (secure/slave),
+	// shifted by the profile's port offset
+	mcpHTTPServer = api.NewMCPHTTPServer(statusProvider, mcpHandler, config.MCPHTTPPort(), config.MCPHTTPSPort())
+	mcpHTTPServer.SetSSEHeartbeat(time.Duration(cfg.MCPHTTP.SSEHeartbeat) * time.Second)
+	mcpHTTPServer.SetAdminToken(cfg.MCPHTTP.AdminToken)
 
 	// Register slave routes on the public-facing MCP server so slaves can pair remotely
 	// This exposes /api/slaves/... endpoints
@@ -2024,8 +3034,8 @@ func main() {
 	if err := mcpHTTPServer.Start(); err != nil {
 		slog.Warn("Failed to start MCP HTTP server", "error", err)
 	} else {
-		slog.Info("MCP HTTP server started", "port", 8765)
-		slog.Info("MCP HTTPS server started", "port", 8766)
+		slog.Info("MCP HTTP server started", "port", config.MCPHTTPPort())
+		slog.Info("MCP HTTPS server started", "port", config.MCPHTTPSPort())
 	}
 	defer func() {
 		if mcpHTTPServer != nil {
@@ -2040,7 +3050,7 @@ func main() {
 		go func() {
 			for range sigChan {
 				slog.Info("Received SIGUSR1, reloading MCP configuration")
-				if err := proxy.Reload(); err != nil {
+				if _, err := proxy.Reload(); err != nil {
 					slog.Error("Failed to reload MCP config", "error", err)
 				}
 			}
@@ -2052,9 +3062,12 @@ func main() {
 		// Block until SIGINT or SIGTERM for graceful shutdown.
 		slog.Info("Diane running in serve mode (no stdio). Press Ctrl+C to stop.")
 		fmt.Fprintf(os.Stderr, "Diane %s running in serve mode (pid %d)\n", getVersion(), os.Getpid())
-		fmt.Fprintf(os.Stderr, "  Unix socket: ~/.diane/diane.sock\n")
-		fmt.Fprintf(os.Stderr, "  MCP HTTP: http://localhost:8765\n")
-		fmt.Fprintf(os.Stderr, "  MCP HTTPS: https://localhost:8766 (secure/slave)\n")
+		if profile := config.Profile(); profile != "" {
+			fmt.Fprintf(os.Stderr, "  Profile: %s\n", profile)
+		}
+		fmt.Fprintf(os.Stderr, "  Unix socket: %s\n", api.GetSocketPath())
+		fmt.Fprintf(os.Stderr, "  MCP HTTP: http://localhost:%d\n", config.MCPHTTPPort())
+		fmt.Fprintf(os.Stderr, "  MCP HTTPS: https://localhost:%d (secure/slave)\n", config.MCPHTTPSPort())
 		if cfg.HTTP.Port > 0 {
 			if cfg.HTTP.APIKey != "" {
 				fmt.Fprintf(os.Stderr, "  Remote API: http://0.0.0.0:%d (API key auth)\n", cfg.HTTP.Port)
@@ -2066,9 +3079,15 @@ func main() {
 
 		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-		sig := <-quit
-		slog.Info("Received shutdown signal", "signal", sig)
-		fmt.Fprintf(os.Stderr, "\nShutting down...\n")
+		select {
+		case sig := <-quit:
+			slog.Info("Received shutdown signal", "signal", sig)
+			fmt.Fprintf(os.Stderr, "\nShutting down...\n")
+		case <-restartCh:
+			slog.Info("Restart requested, shutting down for re-exec")
+			fmt.Fprintf(os.Stderr, "\nRestarting...\n")
+			restarting = true
+		}
 		// Deferred cleanup runs on return
 		return
 	}
@@ -2096,6 +3115,10 @@ func main() {
 			break
 		}
 
+		// Stdio has no headers, so a calling Diane passes its request ID in _meta
+		if req.RequestID = mcpproxy.RequestIDFromParams(req.Params); req.RequestID == "" {
+			req.RequestID = logger.NewRequestID()
+		}
 		resp := handleRequest(req)
 		resp.JSONRPC = "2.0"
 		resp.ID = req.ID
@@ -2107,21 +3130,26 @@ func main() {
 }
 
 func handleRequest(req MCPRequest) MCPResponse {
+	ctx := logger.WithRequestID(context.Background(), req.RequestID)
+	slog.DebugContext(ctx, "Handling MCP request", "method", req.Method)
+
 	switch req.Method {
 	case "initialize":
-		return initialize()
+		return initialize(req.Params)
 	case "tools/list":
 		return listTools()
 	case "tools/call":
-		return callTool(req.Params)
+		return routedToolCall(ctx, req.Params, "", callTool)
 	case "prompts/list":
 		return listPrompts()
 	case "prompts/get":
 		return getPrompt(req.Params)
 	case "resources/list":
-		return listResources()
+		return listResources("")
 	case "resources/read":
-		return readResource(req.Params)
+		return readResource(req.Params, "")
+	case "completion/complete":
+		return complete(req.Params, nil)
 	default:
 		return MCPResponse{
 			Error: &MCPError{
@@ -2134,21 +3162,28 @@ func handleRequest(req MCPRequest) MCPResponse {
 
 // handleRequestWithContext handles MCP requests with context-aware filtering
 func handleRequestWithContext(req MCPRequest, contextName string) MCPResponse {
+	ctx := logger.WithRequestID(context.Background(), req.RequestID)
+	slog.DebugContext(ctx, "Handling MCP request", "method", req.Method, "context", contextName)
+
 	switch req.Method {
 	case "initialize":
-		return initialize()
+		return initialize(req.Params)
 	case "tools/list":
 		return listToolsForContext(contextName)
 	case "tools/call":
-		return callToolForContext(req.Params, contextName)
+		return routedToolCall(ctx, req.Params, contextName, func(ctx context.Context, params json.RawMessage) MCPResponse {
+			return callToolForContext(ctx, params, contextName)
+		})
 	case "prompts/list":
 		return listPromptsForContext(contextName)
 	case "prompts/get":
 		return getPromptForContext(req.Params, contextName)
 	case "resources/list":
-		return listResources()
+		return listResources(contextName)
 	case "resources/read":
-		return readResource(req.Params)
+		return readResource(req.Params, contextName)
+	case "completion/complete":
+		return complete(req.Params, contextServerEnabled(contextName))
 	default:
 		return MCPResponse{
 			Error: &MCPError{
@@ -2185,10 +3220,24 @@ func forwardProxiedNotifications(p *mcpproxy.Proxy) {
 	}
 }
 
-func initialize() MCPResponse {
+func initialize(params json.RawMessage) MCPResponse {
+	var req struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ClientInfo      struct {
+			Name string `json:"name"`
+		} `json:"clientInfo"`
+	}
+	if len(params) > 0 {
+		json.Unmarshal(params, &req)
+	}
+	version, fellBack := mcpproxy.NegotiateProtocolVersion(req.ProtocolVersion)
+	if fellBack {
+		slog.Warn("MCP client requested an unsupported protocol version", "client", req.ClientInfo.Name, "requested", req.ProtocolVersion, "using", version)
+	}
+
 	return MCPResponse{
 		Result: map[string]interface{}{
-			"protocolVersion": "2024-11-05",
+			"protocolVersion": version,
 			"capabilities": map[string]interface{}{
 				"tools": map[string]interface{}{
 					"listChanged": true, // Diane supports dynamic tool list updates from proxied servers
@@ -2200,6 +3249,7 @@ func initialize() MCPResponse {
 					"subscribe":   false,
 					"listChanged": false,
 				},
+				"completions": map[string]interface{}{},
 			},
 			"serverInfo": map[string]interface{}{
 				"name":    "diane",
@@ -2209,7 +3259,8 @@ func initialize() MCPResponse {
 	}
 }
 
-func listTools() MCPResponse {
+// builtinTools returns the definitions of all tools Diane serves itself
+func builtinTools() []map[string]interface{} {
 	// Built-in tools
 	tools := []map[string]interface{}{
 		{
@@ -2237,12 +3288,34 @@ func listTools() MCPResponse {
 					},
 					"schedule": map[string]interface{}{
 						"type":        "string",
-						"description": "Cron schedule expression (e.g., '* * * * *' for every minute)",
+						"description": "Cron schedule: 5 fields (minute hour day-of-month month day-of-week, e.g. '*/15 * * * *') or 6 with a leading seconds field (e.g. '*/30 * * * * *'); @yearly/@monthly/@weekly/@daily/@hourly; or plain English after @natural (e.g. '@natural every weekday at 9am'). Quartz-style '?' and year fields are rejected",
 					},
 					"command": map[string]interface{}{
 						"type":        "string",
 						"description": "Shell command to execute",
 					},
+					"max_output_bytes": map[string]interface{}{
+						"type":        "integer",
+						"description": "Cap on the stdout and stderr stored per run, cut from the middle when exceeded (0 = the global limit)",
+					},
+					"concurrency": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"skip", "queue", "allow"},
+						"description": "What to do when the job fires while its previous run is still going: skip (default; recorded in job_logs as skipped), queue (run once it finishes) or allow (run alongside it)",
+					},
+					"timeout_seconds": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum runtime in seconds; a run still going then has its whole process group killed and is recorded as failed (0 = no limit)",
+					},
+					"env": map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": map[string]interface{}{"type": "string"},
+						"description":          "Environment variables to set for the command, as an object of name to string value (added to the daemon's environment)",
+					},
+					"workdir": map[string]interface{}{
+						"type":        "string",
+						"description": "Absolute directory to run the command in (default: the user's home directory)",
+					},
 				},
 				"required": []string{"name", "schedule", "command"},
 			},
@@ -2322,9 +3395,23 @@ func listTools() MCPResponse {
 				},
 			},
 		},
+		{
+			"name":        "job_retry",
+			"description": "Re-run the job behind a failed or skipped execution (from job_logs), recording a new execution linked to it. Uses the job's current command and waits for it to finish.",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"execution_id": map[string]interface{}{
+						"type":        "integer",
+						"description": "ID of the failed execution to retry",
+					},
+				},
+				"required": []string{"execution_id"},
+			},
+		},
 		{
 			"name":        "server_status",
-			"description": "Check if diane server is running",
+			"description": "Get Diane's health: version, uptime, tool count, and each MCP server's connection, error and auth state",
 			"inputSchema": map[string]interface{}{
 				"type":       "object",
 				"properties": map[string]interface{}{},
@@ -2415,11 +3502,12 @@ func listTools() MCPResponse {
 				"required": []string{"session_id"},
 			},
 		},
+		contextDiscoverTool(),
 	}
 
 	// Add Apple tools (reminders + contacts)
 	if appleProvider != nil {
-		for _, tool := range appleProvider.Tools() {
+		for _, tool := range providerTools("apple", appleProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2430,7 +3518,7 @@ func listTools() MCPResponse {
 
 	// Add Google tools (gmail, drive, sheets, calendar)
 	if googleProvider != nil {
-		for _, tool := range googleProvider.Tools() {
+		for _, tool := range providerTools("google", googleProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2441,7 +3529,7 @@ func listTools() MCPResponse {
 
 	// Add Infrastructure tools (Cloudflare DNS)
 	if infrastructureProvider != nil {
-		for _, tool := range infrastructureProvider.Tools() {
+		for _, tool := range providerTools("infrastructure", infrastructureProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2452,7 +3540,7 @@ func listTools() MCPResponse {
 
 	// Add Notifications tools (Discord, Home Assistant)
 	if notificationsProvider != nil {
-		for _, tool := range notificationsProvider.Tools() {
+		for _, tool := range providerTools("discord", notificationsProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2463,7 +3551,7 @@ func listTools() MCPResponse {
 
 	// Add Finance tools (Enable Banking, Actual Budget, Bank Sync)
 	if financeProvider != nil {
-		for _, tool := range financeProvider.Tools() {
+		for _, tool := range providerTools("finance", financeProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2474,7 +3562,7 @@ func listTools() MCPResponse {
 
 	// Add Google Places tools
 	if placesProvider != nil {
-		for _, tool := range placesProvider.Tools() {
+		for _, tool := range providerTools("places", placesProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2485,7 +3573,29 @@ func listTools() MCPResponse {
 
 	// Add Weather tools
 	if weatherProvider != nil {
-		for _, tool := range weatherProvider.Tools() {
+		for _, tool := range providerTools("weather", weatherProvider.Tools) {
+			tools = append(tools, map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"inputSchema": tool.InputSchema,
+			})
+		}
+	}
+
+	// Add HTTP request tool
+	if httpRequestProvider != nil {
+		for _, tool := range providerTools("http_request", httpRequestProvider.Tools) {
+			tools = append(tools, map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"inputSchema": tool.InputSchema,
+			})
+		}
+	}
+
+	// Add Shell exec tool
+	if shellExecProvider != nil {
+		for _, tool := range providerTools("shell_exec", shellExecProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2496,7 +3606,7 @@ func listTools() MCPResponse {
 
 	// Add GitHub Bot tools
 	if githubProvider != nil {
-		for _, tool := range githubProvider.Tools() {
+		for _, tool := range providerTools("github-bot", githubProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2507,7 +3617,7 @@ func listTools() MCPResponse {
 
 	// Add Downloads tools
 	if downloadsProvider != nil {
-		for _, tool := range downloadsProvider.Tools() {
+		for _, tool := range providerTools("downloads", downloadsProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2518,7 +3628,7 @@ func listTools() MCPResponse {
 
 	// Add Files tools
 	if filesProvider != nil {
-		for _, tool := range filesProvider.Tools() {
+		for _, tool := range providerTools("file_registry", filesProvider.Tools) {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name,
 				"description": tool.Description,
@@ -2527,6 +3637,13 @@ func listTools() MCPResponse {
 		}
 	}
 
+	return tools
+}
+
+// listTools returns builtin tools followed by tools from proxied MCP servers
+func listTools() MCPResponse {
+	tools := builtinTools()
+
 	// Add proxied tools from other MCP servers
 	if proxy != nil {
 		proxiedTools, err := proxy.ListAllTools()
@@ -2539,28 +3656,427 @@ func listTools() MCPResponse {
 
 	return MCPResponse{
 		Result: map[string]interface{}{
-			"tools": tools,
+			"tools": sortToolDefs(customizeToolDefs(categorizeToolDefs(tools))),
 		},
 	}
 }
 
-func callTool(params json.RawMessage) MCPResponse {
-	var call struct {
-		Name      string                 `json:"name"`
-		Arguments map[string]interface{} `json:"arguments"`
+// providerTools returns a builtin provider's tools from its Tools method. A
+// provider whose Tools panics is logged, marked degraded under server, and
+// contributes no tools.
+func providerTools[T any](server string, listTools func() []T) []T {
+	list, err := tools.SafeTools(listTools)
+	providerHealth.RecordTools(server, err)
+	if err != nil {
+		slog.Error("Skipping tools of failing provider", "server", server, "error", err)
 	}
+	return list
+}
 
-	if err := json.Unmarshal(params, &call); err != nil {
-		return MCPResponse{
-			Error: &MCPError{
-				Code:    -32602,
-				Message: fmt.Sprintf("Invalid params: %v", err),
-			},
+// sortToolDefs orders a tools/list result so it is stable and configurable:
+// by the priority of the tool's server (highest first; builtin tools and
+// servers without one count as 0), then builtin tools before proxied ones,
+// then by server and name, so identical tool sets always list identically
+func sortToolDefs(defs []map[string]interface{}) []map[string]interface{} {
+	var priorities map[string]int
+	if proxy != nil {
+		priorities = proxy.ServerPriorities()
+	}
+	type key struct {
+		priority int
+		proxied  bool
+		server   string
+		name     string
+	}
+	keyOf := func(def map[string]interface{}) key {
+		name, _ := def["name"].(string)
+		server, _ := def["_server"].(string)
+		return key{priorities[server], server != "", server, name}
+	}
+	sort.SliceStable(defs, func(i, j int) bool {
+		a, b := keyOf(defs[i]), keyOf(defs[j])
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		if a.proxied != b.proxied {
+			return !a.proxied
+		}
+		if a.server != b.server {
+			return a.server < b.server
+		}
+		return a.name < b.name
+	})
+	return defs
+}
+
+// sortToolInfos orders tool infos by server, then by name
+func sortToolInfos(infos []api.ToolInfo) []api.ToolInfo {
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Server != infos[j].Server {
+			return infos[i].Server < infos[j].Server
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// builtinCategories is the category of each builtin server's tools, unless
+// its provider categorizes a tool itself
+var builtinCategories = map[string]string{
+	"jobs":           tools.CategoryAutomation,
+	"contexts":       tools.CategoryContexts,
+	"agents":         tools.CategoryAgents,
+	"infrastructure": tools.CategoryInfrastructure,
+	"discord":        tools.CategoryNotifications,
+	"finance":        tools.CategoryFinance,
+	"places":         tools.CategoryPlaces,
+	"weather":        tools.CategoryWeather,
+	"http_request":   tools.CategoryWeb,
+	"shell_exec":     tools.CategorySystem,
+	"github-bot":     tools.CategoryDevelopment,
+	"downloads":      tools.CategoryFiles,
+	"file_registry":  tools.CategoryFiles,
+}
+
+// builtinToolCategory returns the category of a tool Diane serves itself, or
+// "" if it isn't a builtin tool
+func builtinToolCategory(name string) string {
+	var server string
+	var provider interface{}
+	switch {
+	case name == "server_status":
+		return tools.CategorySystem
+	case strings.HasPrefix(name, "job_"):
+		server = "jobs"
+	case strings.HasPrefix(name, "context_"):
+		server = "contexts"
+	case strings.HasPrefix(name, "agent_session_"):
+		server = "agents"
+	case appleProvider != nil && appleProvider.HasTool(name):
+		server, provider = "apple", appleProvider
+	case googleProvider != nil && googleProvider.HasTool(name):
+		server, provider = "google", googleProvider
+	case infrastructureProvider != nil && infrastructureProvider.HasTool(name):
+		server = "infrastructure"
+	case notificationsProvider != nil && notificationsProvider.HasTool(name):
+		server = "discord"
+	case financeProvider != nil && financeProvider.HasTool(name):
+		server = "finance"
+	case placesProvider != nil && placesProvider.HasTool(name):
+		server = "places"
+	case weatherProvider != nil && weatherProvider.HasTool(name):
+		server = "weather"
+	case httpRequestProvider != nil && httpRequestProvider.HasTool(name):
+		server = "http_request"
+	case shellExecProvider != nil && shellExecProvider.HasTool(name):
+		server = "shell_exec"
+	case githubProvider != nil && githubProvider.HasTool(name):
+		server = "github-bot"
+	case downloadsProvider != nil && downloadsProvider.HasTool(name):
+		server = "downloads"
+	case filesProvider != nil && filesProvider.HasTool(name):
+		server = "file_registry"
+	default:
+		return ""
+	}
+	if categorized, ok := provider.(tools.CategorizedProvider); ok {
+		if category := categorized.ToolCategory(name); category != "" {
+			return category
 		}
 	}
+	return builtinCategories[server]
+}
 
-	switch call.Name {
-	case "job_list":
+// categorizeToolDefs adds the category of builtin tools to their MCP tool
+// definitions, as _meta.category. Proxied definitions are left alone.
+func categorizeToolDefs(defs []map[string]interface{}) []map[string]interface{} {
+	for _, def := range defs {
+		if _, proxied := def["_server"]; proxied {
+			continue
+		}
+		name, _ := def["name"].(string)
+		category := builtinToolCategory(name)
+		if category == "" {
+			continue
+		}
+		meta, _ := def["_meta"].(map[string]interface{})
+		if meta == nil {
+			meta = make(map[string]interface{})
+			def["_meta"] = meta
+		}
+		meta["category"] = category
+	}
+	return defs
+}
+
+// categorizeToolInfos sets the category of builtin tools
+func categorizeToolInfos(infos []api.ToolInfo) []api.ToolInfo {
+	for i := range infos {
+		if infos[i].Builtin {
+			infos[i].Category = builtinToolCategory(infos[i].Name)
+		}
+	}
+	return infos
+}
+
+// toolCustomizations caches the stored tool customizations by tool name, so
+// listing tools doesn't query the store each time
+var toolCustomizations = struct {
+	sync.RWMutex
+	byName map[string]store.ToolCustomization
+}{byName: map[string]store.ToolCustomization{}}
+
+// loadToolCustomizations refreshes toolCustomizations from the store
+func loadToolCustomizations() error {
+	if toolCustomizationStore == nil {
+		return nil
+	}
+	list, err := toolCustomizationStore.ListToolCustomizations(context.Background())
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]store.ToolCustomization, len(list))
+	for _, c := range list {
+		byName[c.ToolName] = c
+	}
+	toolCustomizations.Lock()
+	toolCustomizations.byName = byName
+	toolCustomizations.Unlock()
+	return nil
+}
+
+func lookupToolCustomization(name string) (store.ToolCustomization, bool) {
+	toolCustomizations.RLock()
+	defer toolCustomizations.RUnlock()
+	c, ok := toolCustomizations.byName[name]
+	return c, ok
+}
+
+// customizedSchema hides a customization's arguments from schema. If the
+// tool has changed so they can no longer be hidden, the schema is left as is.
+func customizedSchema(name string, schema map[string]interface{}, c store.ToolCustomization) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	hidden, err := tools.HideArguments(schema, c.HiddenArgs)
+	if err != nil {
+		slog.Warn("Ignoring hidden arguments of customized tool", "tool", name, "error", err)
+		return schema
+	}
+	return hidden
+}
+
+// customizeToolDefs applies tool customizations to MCP tool definitions.
+// Proxied definitions are shared with the proxy's cache, so customized ones
+// are copied rather than changed in place.
+func customizeToolDefs(defs []map[string]interface{}) []map[string]interface{} {
+	for i, def := range defs {
+		name, _ := def["name"].(string)
+		c, ok := lookupToolCustomization(name)
+		if !ok {
+			continue
+		}
+		customized := make(map[string]interface{}, len(def))
+		for k, v := range def {
+			customized[k] = v
+		}
+		if c.Description != "" {
+			customized["description"] = c.Description
+		}
+		if schema, ok := def["inputSchema"].(map[string]interface{}); ok {
+			customized["inputSchema"] = customizedSchema(name, schema, c)
+		}
+		defs[i] = customized
+	}
+	return defs
+}
+
+// customizeToolInfos applies tool customizations to tool infos, marking the
+// customized ones
+func customizeToolInfos(infos []api.ToolInfo) []api.ToolInfo {
+	for i := range infos {
+		t := &infos[i]
+		c, ok := lookupToolCustomization(t.Name)
+		if !ok {
+			continue
+		}
+		t.Customized = true
+		t.HiddenArgs = c.HiddenArgs
+		if c.Description != "" {
+			t.OriginalDescription = t.Description
+			t.Description = c.Description
+		}
+		t.InputSchema = customizedSchema(t.Name, t.InputSchema, c)
+	}
+	return infos
+}
+
+// SetToolCustomization overrides the description a tool is advertised with
+// and hides some of its optional arguments. An empty description and no
+// hidden arguments remove the customization.
+func (d *DianeStatusProvider) SetToolCustomization(c api.ToolCustomization) error {
+	if toolCustomizationStore == nil {
+		return fmt.Errorf("tool customization store not initialized")
+	}
+
+	var tool *api.ToolInfo
+	all := d.uncustomizedTools()
+	for i := range all {
+		if all[i].Name == c.Tool {
+			tool = &all[i]
+			break
+		}
+	}
+	if tool == nil {
+		return fmt.Errorf("tool not found: %s", c.Tool)
+	}
+	if len(c.HiddenArgs) > 0 {
+		if _, err := tools.HideArguments(tool.InputSchema, c.HiddenArgs); err != nil {
+			return fmt.Errorf("cannot hide arguments of %s: %w", c.Tool, err)
+		}
+	}
+
+	ctx := context.Background()
+	var err error
+	if c.Description == "" && len(c.HiddenArgs) == 0 {
+		err = toolCustomizationStore.DeleteToolCustomization(ctx, c.Tool)
+	} else {
+		err = toolCustomizationStore.SaveToolCustomization(ctx, store.ToolCustomization{
+			ToolName:    c.Tool,
+			Description: c.Description,
+			HiddenArgs:  c.HiddenArgs,
+			UpdatedAt:   time.Now(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return loadToolCustomizations()
+}
+
+// routedToolCall runs a tools/call locally unless a connected slave exposes a
+// tool of the same name, in which case the slave manager picks the host
+// according to its routing preference (local-first by default). ctx carries
+// the request ID passed on to proxied servers and slaves.
+func routedToolCall(ctx context.Context, params json.RawMessage, contextName string, local func(context.Context, json.RawMessage) MCPResponse) MCPResponse {
+	if slaveManager == nil || (contextName != "" && contextStore == nil) {
+		return local(ctx, params)
+	}
+
+	var call struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return local(ctx, params)
+	}
+	slaves := slaveManager.SlavesWithTool(call.Name)
+	if len(slaves) == 0 {
+		return local(ctx, params)
+	}
+
+	var contextFilter mcpproxy.ContextFilter
+	if contextName != "" {
+		contextFilter = store.NewContextFilterAdapter(contextStore)
+	}
+
+	var localResp *MCPResponse
+	result, host, err := slaveManager.RouteToolCall(ctx, contextName, contextFilter, call.Name, call.Arguments, slaves,
+		func() (interface{}, bool, error) {
+			resp := local(ctx, params)
+			result, found, err := routeLocal(resp)
+			if found {
+				localResp = &resp
+			}
+			return result, found, err
+		})
+	if host == slave.LocalHost {
+		return *localResp
+	}
+	if err != nil {
+		// Prefer the master's own error, which keeps its JSON-RPC code
+		if localResp != nil {
+			return *localResp
+		}
+		if resp, ok := toolTimeoutResponse(err); ok {
+			return resp
+		}
+		return MCPResponse{
+			Error: &MCPError{
+				Code:    -32000,
+				Message: err.Error(),
+			},
+		}
+	}
+	return MCPResponse{Result: result}
+}
+
+// routeLocal reads the master's own answer to a routed tools/call: a
+// method-not-found error means the master lacks the tool, and a JSON-RPC
+// error or an isError result is a failure, so the call falls through to
+// the next host
+func routeLocal(resp MCPResponse) (result interface{}, found bool, err error) {
+	if resp.Error != nil && resp.Error.Code == -32601 {
+		return nil, false, nil
+	}
+	if resp.Error != nil {
+		return nil, true, fmt.Errorf("%s", resp.Error.Message)
+	}
+	if message, failed := errorResult(resp.Result); failed {
+		return nil, true, fmt.Errorf("%s", message)
+	}
+	return resp.Result, true, nil
+}
+
+// errorResult reports whether a tools/call result has isError set, and
+// its text
+func errorResult(result interface{}) (string, bool) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", false
+	}
+	var r struct {
+		IsError bool `json:"isError"`
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if json.Unmarshal(data, &r) != nil || !r.IsError {
+		return "", false
+	}
+	var texts []string
+	for _, c := range r.Content {
+		if c.Text != "" {
+			texts = append(texts, c.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return "tool call failed", true
+	}
+	return strings.Join(texts, "\n"), true
+}
+
+func callTool(ctx context.Context, params json.RawMessage) MCPResponse {
+	var call struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+
+	if err := json.Unmarshal(params, &call); err != nil {
+		return MCPResponse{
+			Error: &MCPError{
+				Code:    -32602,
+				Message: fmt.Sprintf("Invalid params: %v", err),
+			},
+		}
+	}
+
+	if resp, ok := validateToolArguments(call.Name, call.Arguments); !ok {
+		return resp
+	}
+
+	switch call.Name {
+	case "job_list":
 		return jobList(call.Arguments)
 	case "job_add":
 		return jobAdd(call.Arguments)
@@ -2576,6 +4092,8 @@ func callTool(params json.RawMessage) MCPResponse {
 		return resumeAll()
 	case "job_logs":
 		return getLogs(call.Arguments)
+	case "job_retry":
+		return jobRetry(call.Arguments)
 	case "server_status":
 		return getStatus()
 	case "agent_session_start":
@@ -2588,153 +4106,140 @@ func callTool(params json.RawMessage) MCPResponse {
 		return agentSessionClose(call.Arguments)
 	case "agent_session_messages":
 		return agentSessionMessages(call.Arguments)
+	case "context_list", "context_create", "context_set_tools":
+		return callContextTool(call.Name, call.Arguments, "")
+	case "context_discover":
+		return contextDiscover("")
 	default:
 		// Try Apple tools first
 		if appleProvider != nil && appleProvider.HasTool(call.Name) {
-			result, err := appleProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(appleProvider, call.Name, call.Arguments)
+			providerHealth.Record("apple", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Google tools
 		if googleProvider != nil && googleProvider.HasTool(call.Name) {
-			result, err := googleProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(googleProvider, call.Name, call.Arguments)
+			providerHealth.Record("google", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Infrastructure tools (Cloudflare DNS)
 		if infrastructureProvider != nil && infrastructureProvider.HasTool(call.Name) {
-			result, err := infrastructureProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(infrastructureProvider, call.Name, call.Arguments)
+			providerHealth.Record("infrastructure", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Notifications tools (Discord, Home Assistant)
 		if notificationsProvider != nil && notificationsProvider.HasTool(call.Name) {
-			result, err := notificationsProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(notificationsProvider, call.Name, call.Arguments)
+			providerHealth.Record("discord", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Finance tools (Enable Banking, Actual Budget, Bank Sync)
 		if financeProvider != nil && financeProvider.HasTool(call.Name) {
-			result, err := financeProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(financeProvider, call.Name, call.Arguments)
+			providerHealth.Record("finance", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Google Places tools
 		if placesProvider != nil && placesProvider.HasTool(call.Name) {
-			result, err := placesProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(placesProvider, call.Name, call.Arguments)
+			providerHealth.Record("places", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Weather tools
 		if weatherProvider != nil && weatherProvider.HasTool(call.Name) {
-			result, err := weatherProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(weatherProvider, call.Name, call.Arguments)
+			providerHealth.Record("weather", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
+		}
+
+		// Try HTTP request tool
+		if httpRequestProvider != nil && httpRequestProvider.HasTool(call.Name) {
+			result, err := cachedCall(httpRequestProvider, call.Name, call.Arguments)
+			providerHealth.Record("http_request", err)
+			if err != nil {
+				return toolCallError(err)
+			}
+			return builtinToolResult(call.Name, result)
+		}
+
+		// Try Shell exec tool
+		if shellExecProvider != nil && shellExecProvider.HasTool(call.Name) {
+			result, err := cachedCall(shellExecProvider, call.Name, call.Arguments)
+			providerHealth.Record("shell_exec", err)
+			if err != nil {
+				return toolCallError(err)
+			}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try GitHub Bot tools
 		if githubProvider != nil && githubProvider.HasTool(call.Name) {
-			result, err := githubProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(githubProvider, call.Name, call.Arguments)
+			providerHealth.Record("github-bot", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Downloads tools
 		if downloadsProvider != nil && downloadsProvider.HasTool(call.Name) {
-			result, err := downloadsProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(downloadsProvider, call.Name, call.Arguments)
+			providerHealth.Record("downloads", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try Files tools
 		if filesProvider != nil && filesProvider.HasTool(call.Name) {
-			result, err := filesProvider.Call(call.Name, call.Arguments)
+			result, err := cachedCall(filesProvider, call.Name, call.Arguments)
+			providerHealth.Record("file_registry", err)
 			if err != nil {
-				return MCPResponse{
-					Error: &MCPError{
-						Code:    -1,
-						Message: err.Error(),
-					},
-				}
+				return toolCallError(err)
 			}
-			return MCPResponse{Result: result}
+			return builtinToolResult(call.Name, result)
 		}
 
 		// Try proxied tools
 		if proxy != nil {
-			result, err := proxy.CallTool(call.Name, call.Arguments)
+			result, err := proxy.CallToolForContext(ctx, "", call.Name, call.Arguments, nil)
 			if err == nil {
 				return MCPResponse{Result: result}
 			}
+			if resp, ok := proxiedToolError(call.Name, err); ok {
+				return resp
+			}
 		}
 		return MCPResponse{
 			Error: &MCPError{
@@ -2778,9 +4283,14 @@ func listToolsForContext(contextName string) MCPResponse {
 		{"job_add", "Add a new cron job with schedule and command", map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"name":     map[string]interface{}{"type": "string", "description": "Unique name for the job"},
-				"schedule": map[string]interface{}{"type": "string", "description": "Cron schedule expression"},
-				"command":  map[string]interface{}{"type": "string", "description": "Shell command to execute"},
+				"name":             map[string]interface{}{"type": "string", "description": "Unique name for the job"},
+				"schedule":         map[string]interface{}{"type": "string", "description": "Cron schedule: 5 fields, 6 with leading seconds, an @daily-style macro, or @natural <phrase>"},
+				"command":          map[string]interface{}{"type": "string", "description": "Shell command to execute"},
+				"max_output_bytes": map[string]interface{}{"type": "integer", "description": "Per-run output cap in bytes (0 = the global limit)"},
+				"concurrency":      map[string]interface{}{"type": "string", "enum": []string{"skip", "queue", "allow"}, "description": "Overlapping runs: skip (default), queue or allow"},
+				"timeout_seconds":  map[string]interface{}{"type": "integer", "description": "Maximum runtime in seconds, then the run is killed (0 = no limit)"},
+				"env":              map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}, "description": "Extra environment variables for the command"},
+				"workdir":          map[string]interface{}{"type": "string", "description": "Absolute working directory (default: home directory)"},
 			},
 			"required": []string{"name", "schedule", "command"},
 		}},
@@ -2814,6 +4324,13 @@ func listToolsForContext(contextName string) MCPResponse {
 				"limit": map[string]interface{}{"type": "integer", "description": "Maximum number of logs to return"},
 			},
 		}},
+		{"job_retry", "Re-run the job behind a failed execution", map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"execution_id": map[string]interface{}{"type": "integer", "description": "ID of the failed execution to retry"},
+			},
+			"required": []string{"execution_id"},
+		}},
 		{"server_status", "Get Diane server status and statistics", map[string]interface{}{"type": "object"}},
 		{"agent_session_start", "Start a new multi-turn session with an ACP agent", map[string]interface{}{
 			"type": "object",
@@ -2869,6 +4386,13 @@ func listToolsForContext(contextName string) MCPResponse {
 		}
 	}
 
+	// Context management tools are only offered to the admin context;
+	// discovery is offered to every context
+	if contextName == adminContextName {
+		tools = append(tools, contextTools()...)
+	}
+	tools = append(tools, contextDiscoverTool())
+
 	// Helper to add provider tools with context check
 	addProviderToolsWithContext := func(providerTools []struct {
 		Name        string
@@ -2889,7 +4413,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Apple tools
 	if appleProvider != nil {
-		for _, tool := range appleProvider.Tools() {
+		for _, tool := range providerTools("apple", appleProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "apple", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2902,7 +4426,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Google tools
 	if googleProvider != nil {
-		for _, tool := range googleProvider.Tools() {
+		for _, tool := range providerTools("google", googleProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "google", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2915,7 +4439,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Infrastructure tools
 	if infrastructureProvider != nil {
-		for _, tool := range infrastructureProvider.Tools() {
+		for _, tool := range providerTools("infrastructure", infrastructureProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "infrastructure", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2928,7 +4452,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Notifications tools
 	if notificationsProvider != nil {
-		for _, tool := range notificationsProvider.Tools() {
+		for _, tool := range providerTools("discord", notificationsProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "discord", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2941,7 +4465,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Finance tools
 	if financeProvider != nil {
-		for _, tool := range financeProvider.Tools() {
+		for _, tool := range providerTools("finance", financeProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "finance", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2954,7 +4478,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Places tools
 	if placesProvider != nil {
-		for _, tool := range placesProvider.Tools() {
+		for _, tool := range providerTools("places", placesProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "places", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2967,7 +4491,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Weather tools
 	if weatherProvider != nil {
-		for _, tool := range weatherProvider.Tools() {
+		for _, tool := range providerTools("weather", weatherProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "weather", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2978,9 +4502,35 @@ func listToolsForContext(contextName string) MCPResponse {
 		}
 	}
 
+	// HTTP request tool
+	if httpRequestProvider != nil {
+		for _, tool := range providerTools("http_request", httpRequestProvider.Tools) {
+			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "http_request", tool.Name); enabled {
+				tools = append(tools, map[string]interface{}{
+					"name":        tool.Name,
+					"description": tool.Description,
+					"inputSchema": tool.InputSchema,
+				})
+			}
+		}
+	}
+
+	// Shell exec tool
+	if shellExecProvider != nil {
+		for _, tool := range providerTools("shell_exec", shellExecProvider.Tools) {
+			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "shell_exec", tool.Name); enabled {
+				tools = append(tools, map[string]interface{}{
+					"name":        tool.Name,
+					"description": tool.Description,
+					"inputSchema": tool.InputSchema,
+				})
+			}
+		}
+	}
+
 	// GitHub tools
 	if githubProvider != nil {
-		for _, tool := range githubProvider.Tools() {
+		for _, tool := range providerTools("github-bot", githubProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "github-bot", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -2993,7 +4543,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Downloads tools
 	if downloadsProvider != nil {
-		for _, tool := range downloadsProvider.Tools() {
+		for _, tool := range providerTools("downloads", downloadsProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "downloads", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -3006,7 +4556,7 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	// Files tools
 	if filesProvider != nil {
-		for _, tool := range filesProvider.Tools() {
+		for _, tool := range providerTools("file_registry", filesProvider.Tools) {
 			if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "file_registry", tool.Name); enabled {
 				tools = append(tools, map[string]interface{}{
 					"name":        tool.Name,
@@ -3029,13 +4579,55 @@ func listToolsForContext(contextName string) MCPResponse {
 
 	return MCPResponse{
 		Result: map[string]interface{}{
-			"tools": tools,
+			"tools": sortToolDefs(customizeToolDefs(categorizeToolDefs(tools))),
 		},
 	}
 }
 
+// builtinSchemas maps each builtin tool name to its InputSchema. Providers
+// are set once at startup, so it is built on the first validated call
+// rather than on every one.
+var (
+	builtinSchemasOnce sync.Once
+	builtinSchemas     map[string]map[string]interface{}
+)
+
+func builtinToolSchema(name string) (map[string]interface{}, bool) {
+	builtinSchemasOnce.Do(func() {
+		builtinSchemas = make(map[string]map[string]interface{})
+		for _, tool := range append(builtinTools(), contextTools()...) {
+			toolName, _ := tool["name"].(string)
+			schema, _ := tool["inputSchema"].(map[string]interface{})
+			builtinSchemas[toolName] = schema
+		}
+	})
+	schema, ok := builtinSchemas[name]
+	return schema, ok
+}
+
+// validateToolArguments checks arguments against a builtin tool's InputSchema
+// so malformed calls fail with -32602 instead of deep inside a provider.
+// Proxied tools are left to their own servers to validate.
+func validateToolArguments(name string, arguments map[string]interface{}) (MCPResponse, bool) {
+	schema, ok := builtinToolSchema(name)
+	if !ok {
+		return MCPResponse{}, true
+	}
+	errs := tools.ValidateArguments(schema, arguments)
+	if len(errs) == 0 {
+		return MCPResponse{}, true
+	}
+	return MCPResponse{
+		Error: &MCPError{
+			Code:    -32602,
+			Message: fmt.Sprintf("Invalid params for %s: %s", name, strings.Join(errs, "; ")),
+			Data:    map[string]interface{}{"errors": errs},
+		},
+	}, false
+}
+
 // callToolForContext calls a tool with context validation
-func callToolForContext(params json.RawMessage, contextName string) MCPResponse {
+func callToolForContext(ctx context.Context, params json.RawMessage, contextName string) MCPResponse {
 	var call struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
@@ -3050,9 +4642,13 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 		}
 	}
 
+	if resp, ok := validateToolArguments(call.Name, call.Arguments); !ok {
+		return resp
+	}
+
 	// Get context filter from Emergent store
 	if contextStore == nil {
-		slog.Warn("Context store not initialized for context validation")
+		slog.WarnContext(ctx, "Context store not initialized for context validation")
 		return MCPResponse{
 			Error: &MCPError{
 				Code:    -32000,
@@ -3063,6 +4659,15 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 
 	contextFilter := store.NewContextFilterAdapter(contextStore)
 
+	// Context management is reserved for the admin context; any context may
+	// discover the others
+	if isContextTool(call.Name) {
+		return callContextTool(call.Name, call.Arguments, contextName)
+	}
+	if call.Name == "context_discover" {
+		return contextDiscover(contextName)
+	}
+
 	// Check if tool is enabled in context for built-in tools
 	isBuiltinTool := map[string]string{
 		"job_list":               "jobs",
@@ -3073,6 +4678,7 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 		"job_pause":              "jobs",
 		"job_resume":             "jobs",
 		"job_logs":               "jobs",
+		"job_retry":              "jobs",
 		"server_status":          "jobs",
 		"agent_session_start":    "agents",
 		"agent_session_prompt":   "agents",
@@ -3108,6 +4714,8 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 			return resumeAll()
 		case "job_logs":
 			return getLogs(call.Arguments)
+		case "job_retry":
+			return jobRetry(call.Arguments)
 		case "server_status":
 			return getStatus()
 		case "agent_session_start":
@@ -3133,11 +4741,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := appleProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(appleProvider, call.Name, call.Arguments)
+		providerHealth.Record("apple", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Google tools
@@ -3150,11 +4759,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := googleProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(googleProvider, call.Name, call.Arguments)
+		providerHealth.Record("google", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Infrastructure tools
@@ -3167,11 +4777,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := infrastructureProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(infrastructureProvider, call.Name, call.Arguments)
+		providerHealth.Record("infrastructure", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Notifications tools
@@ -3184,11 +4795,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := notificationsProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(notificationsProvider, call.Name, call.Arguments)
+		providerHealth.Record("discord", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Finance tools
@@ -3201,11 +4813,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := financeProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(financeProvider, call.Name, call.Arguments)
+		providerHealth.Record("finance", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Places tools
@@ -3218,11 +4831,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := placesProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(placesProvider, call.Name, call.Arguments)
+		providerHealth.Record("places", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Weather tools
@@ -3235,11 +4849,48 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := weatherProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(weatherProvider, call.Name, call.Arguments)
+		providerHealth.Record("weather", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
+	}
+
+	// Check HTTP request tool
+	if httpRequestProvider != nil && httpRequestProvider.HasTool(call.Name) {
+		if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "http_request", call.Name); !enabled {
+			return MCPResponse{
+				Error: &MCPError{
+					Code:    -32601,
+					Message: fmt.Sprintf("Tool %s is not enabled in context %s", call.Name, contextName),
+				},
+			}
+		}
+		result, err := cachedCall(httpRequestProvider, call.Name, call.Arguments)
+		providerHealth.Record("http_request", err)
+		if err != nil {
+			return toolCallError(err)
+		}
+		return builtinToolResult(call.Name, result)
+	}
+
+	// Check Shell exec tool
+	if shellExecProvider != nil && shellExecProvider.HasTool(call.Name) {
+		if enabled, _ := contextFilter.IsToolEnabledInContext(contextName, "shell_exec", call.Name); !enabled {
+			return MCPResponse{
+				Error: &MCPError{
+					Code:    -32601,
+					Message: fmt.Sprintf("Tool %s is not enabled in context %s", call.Name, contextName),
+				},
+			}
+		}
+		result, err := cachedCall(shellExecProvider, call.Name, call.Arguments)
+		providerHealth.Record("shell_exec", err)
+		if err != nil {
+			return toolCallError(err)
+		}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check GitHub tools
@@ -3252,11 +4903,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := githubProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(githubProvider, call.Name, call.Arguments)
+		providerHealth.Record("github-bot", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Downloads tools
@@ -3269,11 +4921,12 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := downloadsProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(downloadsProvider, call.Name, call.Arguments)
+		providerHealth.Record("downloads", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Check Files tools
@@ -3286,19 +4939,23 @@ func callToolForContext(params json.RawMessage, contextName string) MCPResponse
 				},
 			}
 		}
-		result, err := filesProvider.Call(call.Name, call.Arguments)
+		result, err := cachedCall(filesProvider, call.Name, call.Arguments)
+		providerHealth.Record("file_registry", err)
 		if err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
-		return MCPResponse{Result: result}
+		return builtinToolResult(call.Name, result)
 	}
 
 	// Try proxied tools with context validation
 	if proxy != nil {
-		result, err := proxy.CallToolForContext(contextName, call.Name, call.Arguments, contextFilter)
+		result, err := proxy.CallToolForContext(ctx, contextName, call.Name, call.Arguments, contextFilter)
 		if err == nil {
 			return MCPResponse{Result: result}
 		}
+		if resp, ok := toolTimeoutResponse(err); ok {
+			return resp
+		}
 		// Check if it's a context access error
 		if err.Error() != fmt.Sprintf("unknown tool: %s", call.Name) {
 			return MCPResponse{
@@ -3445,6 +5102,19 @@ func listPromptsForContext(contextName string) MCPResponse {
 		}
 	}
 
+	// Built-in daily briefing prompt, limited to the sources this context allows
+	isEnabled := func(server string) bool {
+		for _, s := range enabledServers {
+			if s == server {
+				return true
+			}
+		}
+		return false
+	}
+	if err == nil && availableBriefingSources(isEnabled).any() {
+		prompts = append(prompts, promptToMap(briefingPrompt))
+	}
+
 	// Add prompts from external MCP servers via proxy with context check
 	if proxy != nil {
 		externalPrompts, err := proxy.ListPromptsForContext(contextName, contextFilter)
@@ -3550,6 +5220,11 @@ func getPromptForContext(params json.RawMessage, contextName string) MCPResponse
 		}
 	}
 
+	// Try the built-in daily briefing
+	if messages := getBriefingPrompt(req.Name, req.Arguments, availableBriefingSources(isServerEnabled)); messages != nil {
+		return convertMessages(messages)
+	}
+
 	// Try external MCP servers via proxy (if name has server prefix)
 	if proxy != nil {
 		result, err := proxy.GetPromptForContext(contextName, req.Name, req.Arguments, contextFilter)
@@ -3650,6 +5325,11 @@ func listPrompts() MCPResponse {
 		prompts = append(prompts, prompt)
 	}
 
+	// Built-in daily briefing prompt
+	if availableBriefingSources(nil).any() {
+		prompts = append(prompts, promptToMap(briefingPrompt))
+	}
+
 	// Add prompts from external MCP servers via proxy
 	if proxy != nil {
 		externalPrompts, err := proxy.ListAllPrompts()
@@ -3726,6 +5406,11 @@ func getPrompt(params json.RawMessage) MCPResponse {
 		return convertMessages(messages)
 	}
 
+	// Try the built-in daily briefing
+	if messages := getBriefingPrompt(req.Name, req.Arguments, availableBriefingSources(nil)); messages != nil {
+		return convertMessages(messages)
+	}
+
 	// Try external MCP servers via proxy (if name has server prefix)
 	if proxy != nil {
 		result, err := proxy.GetPrompt(req.Name, req.Arguments)
@@ -3777,6 +5462,7 @@ func getJobsPrompt(name string, args map[string]string) []tools.PromptMessage {
    - "every day at midnight" = "0 0 * * *"
    - "every monday at 9am" = "0 9 * * 1"
    - "every 5 minutes" = "*/5 * * * *"
+   - "every 30 seconds" = "*/30 * * * * *" (a sixth field, at the front, is seconds)
    
 2. Generate a meaningful job name from the task description (lowercase, hyphens, no spaces)
 
@@ -3854,7 +5540,9 @@ func getJobsPrompt(name string, args map[string]string) []tools.PromptMessage {
    - Specific fixes to try
    - Commands to verify the fix
 
-5. If there are no failures, confirm the jobs are healthy and report success rate`, jobFilter, limit, logFilter),
+5. Once a failure's cause is fixed, re-run that execution with job_retry
+
+6. If there are no failures, confirm the jobs are healthy and report success rate`, jobFilter, limit, logFilter),
 				},
 			},
 		}
@@ -3863,9 +5551,143 @@ func getJobsPrompt(name string, args map[string]string) []tools.PromptMessage {
 	return nil
 }
 
+// --- Daily briefing prompt ---
+
+// briefingPrompt composes the weather, calendar, email and finance providers
+// into one morning summary. It is listed whenever at least one of them is
+// available, and only asks for the sources that are.
+var briefingPrompt = tools.Prompt{
+	Name:        "daily_briefing",
+	Description: "Gather today's weather, calendar events, important unread email and over-budget spending into a concise briefing",
+	Arguments: []tools.PromptArgument{
+		{Name: "location", Description: "Location for the weather forecast (e.g., 'Oslo')", Required: false},
+		{Name: "budget_id", Description: "Actual Budget sync ID for the spending check (optional, defaults to the first budget)", Required: false},
+		{Name: "threshold", Description: "Flag budget categories at or above this percent used (default: 80)", Required: false},
+	},
+}
+
+// briefingSources records which builtin providers the daily briefing can draw on
+type briefingSources struct {
+	weather, calendar, email, finance bool
+}
+
+func (b briefingSources) any() bool {
+	return b.weather || b.calendar || b.email || b.finance
+}
+
+// availableBriefingSources checks which providers are loaded and, via enabled,
+// allowed for the caller (pass nil outside a context).
+func availableBriefingSources(enabled func(server string) bool) briefingSources {
+	allowed := func(server string) bool {
+		return enabled == nil || enabled(server)
+	}
+	var b briefingSources
+	b.weather = weatherProvider != nil && allowed("weather")
+	if googleProvider != nil && allowed("google") {
+		b.calendar = googleProvider.HasTool("calendar_list_events")
+		b.email = googleProvider.HasTool("gmail_search")
+	}
+	b.finance = financeProvider != nil && allowed("finance") && financeProvider.HasTool("finance_budget_report")
+	return b
+}
+
+// getBriefingPrompt builds the daily_briefing prompt, or returns nil if name
+// isn't the briefing or none of its sources are available.
+func getBriefingPrompt(name string, args map[string]string, sources briefingSources) []tools.PromptMessage {
+	if name != briefingPrompt.Name || !sources.any() {
+		return nil
+	}
+	getArg := func(key, defaultVal string) string {
+		if val, ok := args[key]; ok && val != "" {
+			return val
+		}
+		return defaultVal
+	}
+
+	var steps, skipped []string
+	if sources.weather {
+		location := getArg("location", "")
+		if location != "" {
+			steps = append(steps, fmt.Sprintf("**Weather**: Use weather_search_location_weather with location=%q. Report today's high/low, precipitation and anything notable (rain, wind, snow).", location))
+		} else {
+			steps = append(steps, "**Weather**: Use weather_search_location_weather for the user's home location (ask once if you don't know it). Report today's high/low, precipitation and anything notable (rain, wind, snow).")
+		}
+	} else {
+		skipped = append(skipped, "weather")
+	}
+	if sources.calendar {
+		steps = append(steps, "**Calendar**: Use calendar_list_events for today. List events in time order with start times, and point out conflicts or back-to-back meetings.")
+	} else {
+		skipped = append(skipped, "calendar")
+	}
+	if sources.email {
+		steps = append(steps, "**Email**: Use gmail_search with query \"is:unread is:important newer_than:1d\". Summarize at most 5 messages in one line each (sender, subject, what's needed).")
+	} else {
+		skipped = append(skipped, "email")
+	}
+	if sources.finance {
+		threshold := getArg("threshold", "80")
+		if budgetID := getArg("budget_id", ""); budgetID != "" {
+			steps = append(steps, fmt.Sprintf("**Spending**: Use finance_budget_report with budget_id=%q and threshold=%s for the current month. Mention only flagged categories.", budgetID, threshold))
+		} else {
+			steps = append(steps, fmt.Sprintf("**Spending**: Use actualbudget_list_budgets to find the budget, then finance_budget_report with threshold=%s for the current month. Mention only flagged categories.", threshold))
+		}
+	} else {
+		skipped = append(skipped, "finance")
+	}
+
+	var text strings.Builder
+	text.WriteString("Prepare my daily briefing for today.\n\n**Gather:**\n")
+	for i, step := range steps {
+		fmt.Fprintf(&text, "%d. %s\n", i+1, step)
+	}
+	text.WriteString("\nIf any tool call fails, note that section as unavailable in one line and carry on with the rest.\n")
+	if len(skipped) > 0 {
+		fmt.Fprintf(&text, "Skip these sections, their tools aren't available: %s.\n", strings.Join(skipped, ", "))
+	}
+	text.WriteString(`
+**Format:**
+- Open with a one-sentence overview of the day
+- One short section per source, in the order above, omitting empty ones
+- End with up to 3 action items
+- Keep it under 200 words so it reads well as a chat message`)
+
+	return []tools.PromptMessage{
+		{
+			Role: "user",
+			Content: tools.PromptContent{
+				Type: "text",
+				Text: text.String(),
+			},
+		},
+	}
+}
+
+// promptToMap converts a prompt definition to its prompts/list form
+func promptToMap(p tools.Prompt) map[string]interface{} {
+	prompt := map[string]interface{}{
+		"name":        p.Name,
+		"description": p.Description,
+	}
+	if len(p.Arguments) > 0 {
+		args := make([]map[string]interface{}, len(p.Arguments))
+		for i, arg := range p.Arguments {
+			args[i] = map[string]interface{}{
+				"name":        arg.Name,
+				"description": arg.Description,
+				"required":    arg.Required,
+			}
+		}
+		prompt["arguments"] = args
+	}
+	return prompt
+}
+
 // --- Resources ---
 
-func listResources() MCPResponse {
+// listResources lists the resources available to a client connected through
+// contextName ("" for none)
+func listResources(contextName string) MCPResponse {
 	var resources []map[string]interface{}
 
 	// Collect resources from Google provider
@@ -3896,6 +5718,16 @@ func listResources() MCPResponse {
 		}
 	}
 
+	// Diane's own logs
+	for _, r := range logResources(contextName) {
+		resources = append(resources, map[string]interface{}{
+			"uri":         r.URI,
+			"name":        r.Name,
+			"description": r.Description,
+			"mimeType":    r.MimeType,
+		})
+	}
+
 	// Add resources from external MCP servers via proxy
 	if proxy != nil {
 		externalResources, err := proxy.ListAllResources()
@@ -3913,7 +5745,9 @@ func listResources() MCPResponse {
 	}
 }
 
-func readResource(params json.RawMessage) MCPResponse {
+// readResource reads a resource for a client connected through contextName
+// ("" for none)
+func readResource(params json.RawMessage, contextName string) MCPResponse {
 	var req struct {
 		URI string `json:"uri"`
 	}
@@ -3932,15 +5766,12 @@ func readResource(params json.RawMessage) MCPResponse {
 		if rp, ok := interface{}(googleProvider).(tools.ResourceProvider); ok {
 			content, err := rp.ReadResource(req.URI)
 			if err == nil && content != nil {
+				if err := checkBuiltinResource(content); err != nil {
+					return MCPResponse{Error: &MCPError{Code: -32000, Message: err.Error()}}
+				}
 				return MCPResponse{
 					Result: map[string]interface{}{
-						"contents": []map[string]interface{}{
-							{
-								"uri":      content.URI,
-								"mimeType": content.MimeType,
-								"text":     content.Text,
-							},
-						},
+						"contents": []*tools.ResourceContent{content},
 					},
 				}
 			}
@@ -3952,21 +5783,33 @@ func readResource(params json.RawMessage) MCPResponse {
 		if rp, ok := interface{}(downloadsProvider).(tools.ResourceProvider); ok {
 			content, err := rp.ReadResource(req.URI)
 			if err == nil && content != nil {
+				if err := checkBuiltinResource(content); err != nil {
+					return MCPResponse{Error: &MCPError{Code: -32000, Message: err.Error()}}
+				}
 				return MCPResponse{
 					Result: map[string]interface{}{
-						"contents": []map[string]interface{}{
-							{
-								"uri":      content.URI,
-								"mimeType": content.MimeType,
-								"text":     content.Text,
-							},
-						},
+						"contents": []*tools.ResourceContent{content},
 					},
 				}
 			}
 		}
 	}
 
+	// Diane's own logs
+	if content, ok, err := readLogResource(req.URI, contextName); ok {
+		if err == nil {
+			err = checkBuiltinResource(content)
+		}
+		if err != nil {
+			return MCPResponse{Error: &MCPError{Code: -32000, Message: err.Error()}}
+		}
+		return MCPResponse{
+			Result: map[string]interface{}{
+				"contents": []*tools.ResourceContent{content},
+			},
+		}
+	}
+
 	// Try external MCP servers via proxy
 	if proxy != nil {
 		result, err := proxy.ReadResource(req.URI)
@@ -3975,6 +5818,10 @@ func readResource(params json.RawMessage) MCPResponse {
 				Result: result,
 			}
 		}
+		var tooLarge *mcpproxy.ResultTooLargeError
+		if errors.As(err, &tooLarge) {
+			return MCPResponse{Error: &MCPError{Code: -32000, Message: err.Error()}}
+		}
 	}
 
 	return MCPResponse{
@@ -3985,6 +5832,196 @@ func readResource(params json.RawMessage) MCPResponse {
 	}
 }
 
+// cachedCall runs a builtin provider's tool, reusing a recent result for
+// identical arguments if the provider declares the tool cacheable
+func cachedCall(provider interface {
+	Call(name string, args map[string]interface{}) (interface{}, error)
+}, name string, args map[string]interface{}) (interface{}, error) {
+	var ttl time.Duration
+	if cacheable, ok := provider.(tools.CacheableProvider); ok {
+		ttl = cacheable.CacheTTL(name)
+	}
+	return toolCache.Call(name, args, ttl, func(args map[string]interface{}) (interface{}, error) {
+		return provider.Call(name, args)
+	})
+}
+
+// builtinToolResult applies the proxy's result size cap to a builtin tool's
+// result, truncating text and refusing results too large to truncate, such
+// as a big image
+func builtinToolResult(tool string, result interface{}) MCPResponse {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return mcpToolError(fmt.Sprintf("failed to encode result: %v", err))
+	}
+	capped, err := mcpproxy.CapToolResult(tool, data, maxResultBytes)
+	if err != nil {
+		return mcpToolError(err.Error())
+	}
+	if len(capped) == len(data) {
+		return MCPResponse{Result: result}
+	}
+	return MCPResponse{Result: capped}
+}
+
+// checkBuiltinResource applies the proxy's result size cap to a resource
+// served by a builtin provider, text or blob
+func checkBuiltinResource(content *tools.ResourceContent) error {
+	if size := len(content.Text) + len(content.Blob); size > maxResultBytes {
+		return &mcpproxy.ResultTooLargeError{What: "resource " + content.URI, Size: size, Limit: maxResultBytes}
+	}
+	return nil
+}
+
+// completionMaxValues is the most values a completion result may carry
+const completionMaxValues = 100
+
+// complete handles completion/complete for prompt arguments and resource
+// URIs. Providers implementing tools.Completer are asked first; built-in
+// arguments such as job_name fall back to Diane's own data. Anything else
+// gets an empty completion rather than an error. enabled restricts which
+// servers are consulted, nil allowing all.
+func complete(params json.RawMessage, enabled func(server string) bool) MCPResponse {
+	var req struct {
+		Ref      tools.CompletionRef `json:"ref"`
+		Argument struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"argument"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return MCPResponse{
+			Error: &MCPError{
+				Code:    -32602,
+				Message: fmt.Sprintf("Invalid params: %v", err),
+			},
+		}
+	}
+	if req.Ref.Type != "ref/prompt" && req.Ref.Type != "ref/resource" {
+		return MCPResponse{
+			Error: &MCPError{
+				Code:    -32602,
+				Message: fmt.Sprintf("Invalid params: unsupported ref type %q", req.Ref.Type),
+			},
+		}
+	}
+
+	// Providers are added only when set, so a nil pointer never ends up
+	// wrapped in a non-nil interface
+	completers := map[string]interface{}{}
+	if googleProvider != nil {
+		completers["google"] = googleProvider
+	}
+	if notificationsProvider != nil {
+		completers["discord"] = notificationsProvider
+	}
+	if downloadsProvider != nil {
+		completers["downloads"] = downloadsProvider
+	}
+	for server, provider := range completers {
+		if enabled != nil && !enabled(server) {
+			continue
+		}
+		if c, ok := provider.(tools.Completer); ok {
+			if values, ok := c.Complete(req.Ref, req.Argument.Name, req.Argument.Value); ok {
+				return completionResponse(values, req.Argument.Value)
+			}
+		}
+	}
+
+	return completionResponse(builtinCompletions(req.Ref, req.Argument.Name, enabled), req.Argument.Value)
+}
+
+// builtinCompletions suggests values for arguments that refer to Diane's own
+// objects, whichever prompt they belong to
+func builtinCompletions(ref tools.CompletionRef, argument string, enabled func(server string) bool) []string {
+	if ref.Type != "ref/prompt" {
+		return nil
+	}
+	ctx := context.Background()
+
+	switch argument {
+	case "job_name":
+		if jobStore == nil || (enabled != nil && !enabled("jobs")) {
+			return nil
+		}
+		jobs, err := jobStore.ListJobs(ctx, false)
+		if err != nil {
+			slog.Warn("Failed to list jobs for completion", "error", err)
+			return nil
+		}
+		names := make([]string, 0, len(jobs))
+		for _, j := range jobs {
+			names = append(names, j.Name)
+		}
+		return names
+	case "context", "context_name":
+		if contextStore == nil {
+			return nil
+		}
+		contexts, err := contextStore.ListContexts(ctx)
+		if err != nil {
+			slog.Warn("Failed to list contexts for completion", "error", err)
+			return nil
+		}
+		names := make([]string, 0, len(contexts))
+		for _, c := range contexts {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+	return nil
+}
+
+// completionResponse filters candidates to those starting with the typed
+// value (case-insensitively) and caps them at the protocol's 100 values
+func completionResponse(candidates []string, value string) MCPResponse {
+	prefix := strings.ToLower(value)
+	values := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c), prefix) {
+			values = append(values, c)
+		}
+	}
+	sort.Strings(values)
+
+	total := len(values)
+	if total > completionMaxValues {
+		values = values[:completionMaxValues]
+	}
+	return MCPResponse{
+		Result: map[string]interface{}{
+			"completion": map[string]interface{}{
+				"values":  values,
+				"total":   total,
+				"hasMore": total > completionMaxValues,
+			},
+		},
+	}
+}
+
+// contextServerEnabled returns a check for whether a built-in server is
+// enabled in the given context. It fails closed if the context store is
+// unavailable.
+func contextServerEnabled(contextName string) func(server string) bool {
+	return func(server string) bool {
+		if contextStore == nil {
+			return false
+		}
+		enabledServers, err := store.NewContextFilterAdapter(contextStore).GetEnabledServersForContext(contextName)
+		if err != nil {
+			return false
+		}
+		for _, s := range enabledServers {
+			if s == server {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 func getDB() (*db.DB, error) {
 	if database == nil {
 		return nil, fmt.Errorf("database not initialized")
@@ -3992,6 +6029,23 @@ func getDB() (*db.DB, error) {
 	return database, nil
 }
 
+// mcpToolError reports a failed tool call as a result with isError set, so
+// the model sees the failure and can react, rather than as a protocol error
+func mcpToolError(text string) MCPResponse {
+	return MCPResponse{Result: tools.ErrorContent(text)}
+}
+
+// toolCallError turns an error from a builtin tool into a response. Errors a
+// provider raised with tools.ErrorResponse and a JSON-RPC code (e.g. -32602
+// for bad arguments) stay protocol errors; anything else is a tool failure.
+func toolCallError(err error) MCPResponse {
+	var toolErr *tools.ToolError
+	if errors.As(err, &toolErr) && toolErr.Code != 0 {
+		return MCPResponse{Error: &MCPError{Code: toolErr.Code, Message: toolErr.Message}}
+	}
+	return mcpToolError(err.Error())
+}
+
 // Helper to format tool response in MCP content format
 func mcpTextResponse(text string) MCPResponse {
 	return MCPResponse{
@@ -4008,7 +6062,7 @@ func mcpTextResponse(text string) MCPResponse {
 
 func jobList(args map[string]interface{}) MCPResponse {
 	if jobStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job store not initialized"}}
+		return mcpToolError("job store not initialized")
 	}
 
 	enabledOnly := false
@@ -4019,7 +6073,7 @@ func jobList(args map[string]interface{}) MCPResponse {
 	ctx := context.Background()
 	jobs, err := jobStore.ListJobs(ctx, enabledOnly)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	// Format as JSON string for text response
@@ -4043,43 +6097,107 @@ func jobAdd(args map[string]interface{}) MCPResponse {
 	command, _ := args["command"].(string)
 
 	if name == "" || schedule == "" || command == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "name, schedule, and command are required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "name, schedule, and command are required"}}
+	}
+	maxOutputBytes := 0
+	if v, ok := args["max_output_bytes"].(float64); ok {
+		maxOutputBytes = int(v)
+	}
+	if maxOutputBytes < 0 {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "max_output_bytes must not be negative"}}
+	}
+	timeoutSeconds := 0
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		timeoutSeconds = int(v)
+	}
+	if timeoutSeconds < 0 {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "timeout_seconds must not be negative"}}
+	}
+	concurrencyArg, _ := args["concurrency"].(string)
+	concurrency, err := cron.ParseConcurrency(concurrencyArg)
+	if err != nil {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: err.Error()}}
+	}
+	env, err := jobs.ParseEnv(args["env"])
+	if err != nil {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: err.Error()}}
+	}
+	workdir, _ := args["workdir"].(string)
+	if err := cron.ValidateRunEnvironment(env, workdir); err != nil {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: err.Error()}}
 	}
 
 	if jobStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job store not initialized"}}
+		return mcpToolError("job store not initialized")
+	}
+
+	schedule, phrase, err := jobs.ResolveSchedule(schedule)
+	if err != nil {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: err.Error()}}
+	}
+	nextRuns, err := jobs.ValidateSchedule(schedule, time.Now())
+	if err != nil {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: err.Error()}}
 	}
 
 	ctx := context.Background()
 	job, err := jobStore.CreateJob(ctx, name, command, schedule)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
+	}
+	if phrase != "" {
+		if err := jobStore.SetScheduleText(ctx, job.ID, phrase); err != nil {
+			return toolCallError(err)
+		}
+		job.ScheduleText = phrase
+	}
+	if maxOutputBytes > 0 {
+		if err := jobStore.SetMaxOutputBytes(ctx, job.ID, maxOutputBytes); err != nil {
+			return toolCallError(err)
+		}
+		job.MaxOutputBytes = maxOutputBytes
+	}
+	if err := jobStore.SetConcurrency(ctx, job.ID, concurrency); err != nil {
+		return toolCallError(err)
+	}
+	job.Concurrency = concurrency
+	if len(env) > 0 || workdir != "" {
+		if err := jobStore.SetRunEnvironment(ctx, job.ID, env, workdir); err != nil {
+			return toolCallError(err)
+		}
+		job.Env, job.Workdir = env, workdir
+	}
+	if timeoutSeconds > 0 {
+		if err := jobStore.SetTimeout(ctx, job.ID, timeoutSeconds); err != nil {
+			return toolCallError(err)
+		}
+		job.TimeoutSeconds = timeoutSeconds
 	}
 
 	jobJSON, _ := json.MarshalIndent(job, "", "  ")
-	message := fmt.Sprintf("Job '%s' created successfully\n\n%s", name, string(jobJSON))
+	message := fmt.Sprintf("Job '%s' created successfully\n\n%s%s\n%s", name, jobs.FormatResolved(schedule, phrase), jobs.FormatNextRuns(nextRuns), string(jobJSON))
 	return mcpTextResponse(message)
 }
 
 func jobEnable(args map[string]interface{}) MCPResponse {
 	jobIdentifier, _ := args["job"].(string)
 	if jobIdentifier == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job identifier is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "job identifier is required"}}
 	}
 
 	if jobStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job store not initialized"}}
+		return mcpToolError("job store not initialized")
 	}
 
 	ctx := context.Background()
 	job, err := jobStore.GetJobByName(ctx, jobIdentifier)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	enabled := true
 	if err := jobStore.UpdateJob(ctx, job.ID, nil, nil, &enabled); err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	return mcpTextResponse(fmt.Sprintf("Job '%s' enabled", jobIdentifier))
@@ -4088,22 +6206,22 @@ func jobEnable(args map[string]interface{}) MCPResponse {
 func jobDisable(args map[string]interface{}) MCPResponse {
 	jobIdentifier, _ := args["job"].(string)
 	if jobIdentifier == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job identifier is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "job identifier is required"}}
 	}
 
 	if jobStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job store not initialized"}}
+		return mcpToolError("job store not initialized")
 	}
 
 	ctx := context.Background()
 	job, err := jobStore.GetJobByName(ctx, jobIdentifier)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	enabled := false
 	if err := jobStore.UpdateJob(ctx, job.ID, nil, nil, &enabled); err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	return mcpTextResponse(fmt.Sprintf("Job '%s' disabled", jobIdentifier))
@@ -4112,21 +6230,21 @@ func jobDisable(args map[string]interface{}) MCPResponse {
 func jobDelete(args map[string]interface{}) MCPResponse {
 	jobIdentifier, _ := args["job"].(string)
 	if jobIdentifier == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job identifier is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "job identifier is required"}}
 	}
 
 	if jobStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job store not initialized"}}
+		return mcpToolError("job store not initialized")
 	}
 
 	ctx := context.Background()
 	job, err := jobStore.GetJobByName(ctx, jobIdentifier)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	if err := jobStore.DeleteJob(ctx, job.ID); err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	return mcpTextResponse(fmt.Sprintf("Job '%s' deleted", jobIdentifier))
@@ -4134,20 +6252,20 @@ func jobDelete(args map[string]interface{}) MCPResponse {
 
 func pauseAll() MCPResponse {
 	if jobStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job store not initialized"}}
+		return mcpToolError("job store not initialized")
 	}
 
 	ctx := context.Background()
 	jobs, err := jobStore.ListJobs(ctx, true)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	count := 0
 	enabled := false
 	for _, job := range jobs {
 		if err := jobStore.UpdateJob(ctx, job.ID, nil, nil, &enabled); err != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+			return toolCallError(err)
 		}
 		count++
 	}
@@ -4157,13 +6275,13 @@ func pauseAll() MCPResponse {
 
 func resumeAll() MCPResponse {
 	if jobStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "job store not initialized"}}
+		return mcpToolError("job store not initialized")
 	}
 
 	ctx := context.Background()
 	allJobs, err := jobStore.ListJobs(ctx, false)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	count := 0
@@ -4171,7 +6289,7 @@ func resumeAll() MCPResponse {
 	for _, job := range allJobs {
 		if !job.Enabled {
 			if err := jobStore.UpdateJob(ctx, job.ID, nil, nil, &enabled); err != nil {
-				return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+				return toolCallError(err)
 			}
 			count++
 		}
@@ -4182,7 +6300,7 @@ func resumeAll() MCPResponse {
 
 func getLogs(args map[string]interface{}) MCPResponse {
 	if jobStore == nil || executionStore == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "stores not initialized"}}
+		return mcpToolError("stores not initialized")
 	}
 
 	limit := 10
@@ -4201,62 +6319,385 @@ func getLogs(args map[string]interface{}) MCPResponse {
 	if jobName != "" {
 		job, jobErr := jobStore.GetJobByName(ctx, jobName)
 		if jobErr != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: jobErr.Error()}}
+			return toolCallError(jobErr)
 		}
 		var execErr error
 		executions, execErr = executionStore.ListJobExecutions(ctx, &job.ID, limit, 0)
 		if execErr != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: execErr.Error()}}
+			return toolCallError(execErr)
 		}
 	} else {
 		var execErr error
 		executions, execErr = executionStore.ListJobExecutions(ctx, nil, limit, 0)
 		if execErr != nil {
-			return MCPResponse{Error: &MCPError{Code: -1, Message: execErr.Error()}}
+			return toolCallError(execErr)
 		}
 	}
 
-	logsJSON, _ := json.MarshalIndent(executions, "", "  ")
+	logsJSON, _ := json.MarshalIndent(jobs.WithWorkdirs(ctx, jobStore, executions), "", "  ")
 	return mcpTextResponse(string(logsJSON))
 }
 
+func jobRetry(args map[string]interface{}) MCPResponse {
+	if jobStore == nil || executionStore == nil {
+		return mcpToolError("stores not initialized")
+	}
+
+	id, _ := args["execution_id"].(float64)
+	if id <= 0 {
+		return mcpToolError("execution_id is required")
+	}
+
+	execution, err := jobs.Retry(context.Background(), jobStore, executionStore, int64(id))
+	if err != nil {
+		return toolCallError(err)
+	}
+
+	executionJSON, _ := json.MarshalIndent(execution, "", "  ")
+	return mcpTextResponse(jobs.FormatRetry(execution) + "\n\n" + string(executionJSON))
+}
+
 func getStatus() MCPResponse {
-	home, err := os.UserHomeDir()
+	status := (&DianeStatusProvider{}).GetStatus()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Diane %s is running (PID %d), up %s, %d tools\n", status.Version, status.PID, status.Uptime, status.TotalTools)
+	if status.SlaveMode {
+		if status.SlaveConnected {
+			fmt.Fprintf(&sb, "Slave of %s: connected\n", status.MasterURL)
+		} else {
+			fmt.Fprintf(&sb, "Slave of %s: %s\n", status.MasterURL, status.SlaveError)
+		}
+	}
+
+	var problems []string
+	for _, srv := range status.MCPServers {
+		switch {
+		case !srv.Enabled:
+			continue
+		case srv.Degraded:
+			problems = append(problems, fmt.Sprintf("%s: degraded (%s)", srv.Name, srv.Error))
+		case srv.Error != "":
+			problems = append(problems, fmt.Sprintf("%s: %s", srv.Name, srv.Error))
+		case !srv.Connected:
+			problems = append(problems, fmt.Sprintf("%s: disconnected", srv.Name))
+		case srv.RequiresAuth && !srv.Authenticated:
+			problems = append(problems, fmt.Sprintf("%s: not authenticated", srv.Name))
+		}
+	}
+	if len(problems) == 0 {
+		sb.WriteString("All enabled MCP servers are healthy\n")
+	} else {
+		fmt.Fprintf(&sb, "%d MCP servers need attention:\n", len(problems))
+		for _, p := range problems {
+			fmt.Fprintf(&sb, "  - %s\n", p)
+		}
+	}
+
+	statusJSON, _ := json.MarshalIndent(status, "", "  ")
+	sb.WriteString("\n")
+	sb.Write(statusJSON)
+	return mcpTextResponse(sb.String())
+}
+
+// --- Context Management Tools ---
+
+// adminContextName is the only context whose clients may call the context
+// management tools, so an agent can't widen the scope it was given. The
+// HTTP transport only lets a client into it with the admin token.
+const adminContextName = api.AdminContextName
+
+// contextTools returns the definitions of the context management tools
+func contextTools() []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"name":        "context_list",
+			"description": "List all contexts with their enabled MCP servers",
+			"inputSchema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			"name":        "context_create",
+			"description": "Create a new context, optionally enabling a set of MCP servers in it. Use this to give a delegated task a narrower set of tools.",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the new context",
+					},
+					"description": map[string]interface{}{
+						"type":        "string",
+						"description": "What the context is for",
+					},
+					"servers": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "MCP servers to enable in the context (all their tools enabled)",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			"name":        "context_set_tools",
+			"description": "Enable or disable an MCP server, or individual tools of it, in a context. The server is added to the context if it isn't already.",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"context": map[string]interface{}{
+						"type":        "string",
+						"description": "Context to change",
+					},
+					"server": map[string]interface{}{
+						"type":        "string",
+						"description": "MCP server name",
+					},
+					"server_enabled": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Enable or disable the whole server in the context (default true)",
+					},
+					"enable": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Tool names to enable",
+					},
+					"disable": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Tool names to disable",
+					},
+				},
+				"required": []string{"context", "server"},
+			},
+		},
+	}
+}
+
+// contextDiscoverTool returns the definition of context_discover, which,
+// unlike the management tools, every client may call
+func contextDiscoverTool() map[string]interface{} {
+	return map[string]interface{}{
+		"name":        "context_discover",
+		"description": "List the contexts a client can connect to (with ?context=<name>), each with its description and number of available tools, so you can pick or suggest the one that fits a task",
+		"inputSchema": map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	}
+}
+
+// contextDiscover lists contexts for clients choosing a scope. current is
+// the context the caller is connected through, marked in the result.
+// Privileged contexts are only listed to callers already in one.
+func contextDiscover(current string) MCPResponse {
+	if contextStore == nil {
+		return mcpToolError("context store not initialized")
+	}
+
+	contexts, err := contextStore.ListContexts(context.Background())
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
-	// Check mcp.pid first (Go server)
-	pidFile := filepath.Join(home, ".diane", "mcp.pid")
-	pidBytes, err := os.ReadFile(pidFile)
-	if err == nil {
-		return mcpTextResponse(fmt.Sprintf("Diane MCP Server is running (PID: %s)", strings.TrimSpace(string(pidBytes))))
+	provider := &DianeStatusProvider{}
+	contexts = discoverableContexts(contexts, current)
+	result := make([]map[string]interface{}, 0, len(contexts))
+	for _, c := range contexts {
+		entry := map[string]interface{}{
+			"name":        c.Name,
+			"description": c.Description,
+			"is_default":  c.IsDefault,
+			"tool_count":  len(provider.GetToolsForContext(c.Name)),
+		}
+		if c.Name == current {
+			entry["current"] = true
+		}
+		result = append(result, entry)
 	}
 
-	// Fallback to server.pid (legacy/Python server)
-	pidFile = filepath.Join(home, ".diane", "server.pid")
-	pidBytes, err = os.ReadFile(pidFile)
-	if err == nil {
-		return mcpTextResponse(fmt.Sprintf("Legacy Diane Server is running (PID: %s)", strings.TrimSpace(string(pidBytes))))
+	resultJSON, _ := json.MarshalIndent(result, "", "  ")
+	return mcpTextResponse(string(resultJSON))
+}
+
+// isPrivilegedContext reports whether a context grants more than its tools,
+// like the admin context's context management
+func isPrivilegedContext(name string) bool {
+	return name == adminContextName
+}
+
+// discoverableContexts returns the contexts a caller connected through
+// current may discover: all of them from a privileged context, otherwise
+// only the unprivileged ones
+func discoverableContexts(contexts []db.Context, current string) []db.Context {
+	if isPrivilegedContext(current) {
+		return contexts
+	}
+	visible := make([]db.Context, 0, len(contexts))
+	for _, c := range contexts {
+		if !isPrivilegedContext(c.Name) {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+func isContextTool(name string) bool {
+	return name == "context_list" || name == "context_create" || name == "context_set_tools"
+}
+
+// callContextTool runs a context management tool on behalf of a client
+// connected through contextName
+func callContextTool(name string, args map[string]interface{}, contextName string) MCPResponse {
+	if contextName != adminContextName {
+		return MCPResponse{
+			Error: &MCPError{
+				Code:    -32601,
+				Message: fmt.Sprintf("Tool %s is only available in the %s context", name, adminContextName),
+			},
+		}
+	}
+	if contextStore == nil {
+		return mcpToolError("context store not initialized")
+	}
+
+	switch name {
+	case "context_list":
+		return contextList()
+	case "context_create":
+		return contextCreate(args)
+	default:
+		return contextSetTools(args)
+	}
+}
+
+func contextList() MCPResponse {
+	ctx := context.Background()
+	contexts, err := contextStore.ListContexts(ctx)
+	if err != nil {
+		return toolCallError(err)
+	}
+
+	result := make([]map[string]interface{}, 0, len(contexts))
+	for _, c := range contexts {
+		servers, err := contextStore.GetServersForContext(ctx, c.Name)
+		if err != nil {
+			return toolCallError(err)
+		}
+		enabled := []string{}
+		for _, srv := range servers {
+			if srv.Enabled {
+				enabled = append(enabled, srv.ServerName)
+			}
+		}
+		result = append(result, map[string]interface{}{
+			"name":        c.Name,
+			"description": c.Description,
+			"is_default":  c.IsDefault,
+			"servers":     enabled,
+		})
+	}
+
+	resultJSON, _ := json.MarshalIndent(result, "", "  ")
+	return mcpTextResponse(string(resultJSON))
+}
+
+func contextCreate(args map[string]interface{}) MCPResponse {
+	name, _ := args["name"].(string)
+	if name == "" {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "name is required"}}
+	}
+	description, _ := args["description"].(string)
+
+	ctx := context.Background()
+	c := &db.Context{Name: name, Description: description}
+	if err := contextStore.CreateContext(ctx, c); err != nil {
+		return mcpToolError(fmt.Sprintf("failed to create context: %v", err))
+	}
+
+	var added []string
+	for _, s := range stringArgs(args, "servers") {
+		if err := contextStore.AddServerToContext(ctx, name, s, true); err != nil {
+			return mcpToolError(fmt.Sprintf("context %s created, but adding server %s failed: %v", name, s, err))
+		}
+		added = append(added, s)
 	}
 
-	return mcpTextResponse("Server is not running")
+	slog.Info("Context created via MCP", "context", name, "servers", added)
+	if len(added) == 0 {
+		return mcpTextResponse(fmt.Sprintf("Created context %s with no servers", name))
+	}
+	return mcpTextResponse(fmt.Sprintf("Created context %s with servers: %s", name, strings.Join(added, ", ")))
+}
+
+func contextSetTools(args map[string]interface{}) MCPResponse {
+	contextName, _ := args["context"].(string)
+	serverName, _ := args["server"].(string)
+	if contextName == "" || serverName == "" {
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "context and server are required"}}
+	}
+	serverEnabled := true
+	if v, ok := args["server_enabled"].(bool); ok {
+		serverEnabled = v
+	}
+
+	ctx := context.Background()
+	if err := contextStore.AddServerToContext(ctx, contextName, serverName, serverEnabled); err != nil {
+		return toolCallError(err)
+	}
+
+	toolUpdates := make(map[string]bool)
+	for _, t := range stringArgs(args, "enable") {
+		toolUpdates[t] = true
+	}
+	for _, t := range stringArgs(args, "disable") {
+		if toolUpdates[t] {
+			return MCPResponse{Error: &MCPError{Code: -32602, Message: fmt.Sprintf("tool %s is in both enable and disable", t)}}
+		}
+		toolUpdates[t] = false
+	}
+	if len(toolUpdates) > 0 {
+		if err := contextStore.BulkSetToolsEnabled(ctx, contextName, serverName, toolUpdates); err != nil {
+			return toolCallError(err)
+		}
+	}
+
+	slog.Info("Context tools updated via MCP", "context", contextName, "server", serverName, "server_enabled", serverEnabled, "tools", len(toolUpdates))
+	state := "enabled"
+	if !serverEnabled {
+		state = "disabled"
+	}
+	return mcpTextResponse(fmt.Sprintf("Server %s %s in context %s; %d tool override(s) applied", serverName, state, contextName, len(toolUpdates)))
+}
+
+// stringArgs reads a string array argument, skipping non-string items
+func stringArgs(args map[string]interface{}, key string) []string {
+	items, _ := args[key].([]interface{})
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
 }
 
 // --- ACP Session Tools ---
 
 func agentSessionStart(args map[string]interface{}) MCPResponse {
 	if apiServer == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "API server not initialized"}}
+		return mcpToolError("API server not initialized")
 	}
 	mgr := apiServer.GetACPManager()
 	if mgr == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "ACP manager not initialized"}}
+		return mcpToolError("ACP manager not initialized")
 	}
 
 	agentName, _ := args["agent"].(string)
 	if agentName == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "agent name is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "agent name is required"}}
 	}
 
 	workDir, _ := args["workdir"].(string)
@@ -4264,7 +6705,7 @@ func agentSessionStart(args map[string]interface{}) MCPResponse {
 
 	info, err := mgr.StartSession(agentName, workDir, title)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	infoJSON, _ := json.MarshalIndent(info, "", "  ")
@@ -4273,26 +6714,26 @@ func agentSessionStart(args map[string]interface{}) MCPResponse {
 
 func agentSessionPrompt(args map[string]interface{}) MCPResponse {
 	if apiServer == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "API server not initialized"}}
+		return mcpToolError("API server not initialized")
 	}
 	mgr := apiServer.GetACPManager()
 	if mgr == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "ACP manager not initialized"}}
+		return mcpToolError("ACP manager not initialized")
 	}
 
 	sessionID, _ := args["session_id"].(string)
 	if sessionID == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "session_id is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "session_id is required"}}
 	}
 
 	prompt, _ := args["prompt"].(string)
 	if prompt == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "prompt is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "prompt is required"}}
 	}
 
 	run, err := mgr.PromptSession(sessionID, prompt)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	runJSON, _ := json.MarshalIndent(run, "", "  ")
@@ -4301,11 +6742,11 @@ func agentSessionPrompt(args map[string]interface{}) MCPResponse {
 
 func agentSessionList(args map[string]interface{}) MCPResponse {
 	if apiServer == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "API server not initialized"}}
+		return mcpToolError("API server not initialized")
 	}
 	mgr := apiServer.GetACPManager()
 	if mgr == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "ACP manager not initialized"}}
+		return mcpToolError("ACP manager not initialized")
 	}
 
 	agentName, _ := args["agent"].(string)
@@ -4313,7 +6754,7 @@ func agentSessionList(args map[string]interface{}) MCPResponse {
 
 	sessions, err := mgr.ListSessionsFromStore(agentName, status)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	sessionsJSON, _ := json.MarshalIndent(sessions, "", "  ")
@@ -4322,20 +6763,20 @@ func agentSessionList(args map[string]interface{}) MCPResponse {
 
 func agentSessionClose(args map[string]interface{}) MCPResponse {
 	if apiServer == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "API server not initialized"}}
+		return mcpToolError("API server not initialized")
 	}
 	mgr := apiServer.GetACPManager()
 	if mgr == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "ACP manager not initialized"}}
+		return mcpToolError("ACP manager not initialized")
 	}
 
 	sessionID, _ := args["session_id"].(string)
 	if sessionID == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "session_id is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "session_id is required"}}
 	}
 
 	if err := mgr.CloseSession(sessionID); err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	return mcpTextResponse(fmt.Sprintf("Session '%s' closed", sessionID))
@@ -4343,21 +6784,21 @@ func agentSessionClose(args map[string]interface{}) MCPResponse {
 
 func agentSessionMessages(args map[string]interface{}) MCPResponse {
 	if apiServer == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "API server not initialized"}}
+		return mcpToolError("API server not initialized")
 	}
 	mgr := apiServer.GetACPManager()
 	if mgr == nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "ACP manager not initialized"}}
+		return mcpToolError("ACP manager not initialized")
 	}
 
 	sessionID, _ := args["session_id"].(string)
 	if sessionID == "" {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: "session_id is required"}}
+		return MCPResponse{Error: &MCPError{Code: -32602, Message: "session_id is required"}}
 	}
 
 	messages, err := mgr.GetSessionMessages(sessionID)
 	if err != nil {
-		return MCPResponse{Error: &MCPError{Code: -1, Message: err.Error()}}
+		return toolCallError(err)
 	}
 
 	messagesJSON, _ := json.MarshalIndent(messages, "", "  ")
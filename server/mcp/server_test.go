@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/db"
+	"github.com/diane-assistant/diane/internal/mcpproxy"
+	"github.com/diane-assistant/diane/mcp/tools"
+	"github.com/diane-assistant/diane/mcp/tools/weather"
+)
+
+func TestCallContextToolRequiresAdmin(t *testing.T) {
+	for _, contextName := range []string{"", "personal", "Admin"} {
+		resp := callContextTool("context_set_tools", map[string]interface{}{"context": "personal", "server": "shell"}, contextName)
+		if resp.Error == nil || resp.Error.Code != -32601 || !strings.Contains(resp.Error.Message, "only available in the admin context") {
+			t.Errorf("context %q: expected the tool to be refused, got %+v", contextName, resp)
+		}
+	}
+
+	// The admin context gets past the gate (and then needs the store)
+	resp := callContextTool("context_list", nil, adminContextName)
+	if resp.Error != nil {
+		t.Errorf("expected the admin context to be let through, got %+v", resp.Error)
+	}
+}
+
+func TestDiscoverableContexts(t *testing.T) {
+	contexts := []db.Context{{Name: "personal", IsDefault: true}, {Name: adminContextName}, {Name: "work"}}
+	names := func(cs []db.Context) string {
+		var n []string
+		for _, c := range cs {
+			n = append(n, c.Name)
+		}
+		return strings.Join(n, ",")
+	}
+
+	for _, current := range []string{"", "personal", "Admin"} {
+		if got := names(discoverableContexts(contexts, current)); got != "personal,work" {
+			t.Errorf("from %q: discovered %s, want the admin context hidden", current, got)
+		}
+	}
+	if got := names(discoverableContexts(contexts, adminContextName)); got != "personal,admin,work" {
+		t.Errorf("from the admin context: discovered %s, want every context", got)
+	}
+}
+
+func TestToolCallError(t *testing.T) {
+	resp := toolCallError(tools.ErrorResponse(-32602, "bad argument: limit"))
+	if resp.Error == nil || resp.Error.Code != -32602 || resp.Error.Message != "bad argument: limit" || resp.Result != nil {
+		t.Errorf("expected a coded tool error to stay a protocol error, got %+v", resp)
+	}
+
+	resp = toolCallError(fmt.Errorf("search: %w", errors.New("backend unavailable")))
+	if resp.Error != nil {
+		t.Fatalf("expected a plain error to become a tool result, got %+v", resp.Error)
+	}
+	if text, failed := errorResult(resp.Result); !failed || text != "search: backend unavailable" {
+		t.Errorf("expected an isError result with the message, got %q, %v", text, failed)
+	}
+
+	resp = toolCallError(tools.ErrorResponse(0, "no code"))
+	if resp.Error != nil {
+		t.Errorf("expected a tool error without a code to be a tool result, got %+v", resp.Error)
+	}
+}
+
+func TestRouteLocal(t *testing.T) {
+	ok := mcpTextResponse("done")
+	proxied := MCPResponse{Result: json.RawMessage(`{"content":[{"type":"text","text":"quota exceeded"}],"isError":true}`)}
+
+	tests := []struct {
+		name      string
+		resp      MCPResponse
+		wantFound bool
+		wantErr   string
+	}{
+		{"success", ok, true, ""},
+		{"master lacks the tool", MCPResponse{Error: &MCPError{Code: -32601, Message: "Unknown tool"}}, false, ""},
+		{"protocol error", MCPResponse{Error: &MCPError{Code: -32000, Message: "boom"}}, true, "boom"},
+		{"isError result", mcpToolError("backend unavailable"), true, "backend unavailable"},
+		{"proxied isError result", proxied, true, "quota exceeded"},
+	}
+	for _, tt := range tests {
+		result, found, err := routeLocal(tt.resp)
+		if found != tt.wantFound {
+			t.Errorf("%s: found = %v, want %v", tt.name, found, tt.wantFound)
+		}
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		case tt.wantErr == "" && tt.wantFound && result == nil:
+			t.Errorf("%s: expected the result to be passed on", tt.name)
+		}
+	}
+}
+
+func TestBuiltinToolResultSizeCap(t *testing.T) {
+	old := maxResultBytes
+	maxResultBytes = 1024
+	defer func() { maxResultBytes = old }()
+
+	small := tools.TextContent("fits")
+	if resp := builtinToolResult("t", small); resp.Error != nil || fmt.Sprint(resp.Result) != fmt.Sprint(small) {
+		t.Errorf("expected a small result to pass through as is, got %+v", resp)
+	}
+
+	resp := builtinToolResult("t", tools.TextContent(strings.Repeat("long line\n", 500)))
+	raw, ok := resp.Result.(json.RawMessage)
+	if resp.Error != nil || !ok || len(raw) > maxResultBytes || !strings.Contains(string(raw), "[truncated: tool result was") {
+		t.Errorf("expected oversized text to be truncated to the cap, got %d bytes: %+v", len(raw), resp.Error)
+	}
+
+	resp = builtinToolResult("t", tools.ImageContent(make([]byte, 2048), "image/png", "map"))
+	if text, failed := errorResult(resp.Result); resp.Error != nil || !failed || !strings.Contains(text, "1024") {
+		t.Errorf("expected an oversized image to be refused as a tool error, got %q, %v", text, failed)
+	}
+}
+
+func TestBlobResources(t *testing.T) {
+	old := maxResultBytes
+	maxResultBytes = 1024
+	defer func() { maxResultBytes = old }()
+
+	blob := &tools.ResourceContent{URI: "diane://file.png", MimeType: "image/png", Blob: strings.Repeat("A", 512)}
+	if err := checkBuiltinResource(blob); err != nil {
+		t.Errorf("expected a blob under the cap to be served, got %v", err)
+	}
+	data, _ := json.Marshal(map[string]interface{}{"contents": []*tools.ResourceContent{blob}})
+	if !strings.Contains(string(data), `"blob":"AAAA`) || strings.Contains(string(data), `"text"`) {
+		t.Errorf("expected the blob to be served without a text field, got %s", data)
+	}
+
+	blob.Blob = strings.Repeat("A", 2048)
+	var tooLarge *mcpproxy.ResultTooLargeError
+	if err := checkBuiltinResource(blob); !errors.As(err, &tooLarge) || tooLarge.Size != 2048 {
+		t.Errorf("expected an oversized blob to be refused, got %v", err)
+	}
+}
+
+// completionValues runs completion/complete and returns the suggested values
+func completionValues(t *testing.T, params string, enabled func(string) bool) ([]string, bool) {
+	t.Helper()
+	resp := complete(json.RawMessage(params), enabled)
+	if resp.Error != nil {
+		t.Fatalf("%s: unexpected error %+v", params, resp.Error)
+	}
+	completion := resp.Result.(map[string]interface{})["completion"].(map[string]interface{})
+	return completion["values"].([]string), completion["hasMore"].(bool)
+}
+
+func TestComplete(t *testing.T) {
+	useJobStores(t)
+
+	for _, params := range []string{`not json`, `{"ref":{"type":"ref/tool","name":"x"},"argument":{"name":"a"}}`} {
+		if resp := complete(json.RawMessage(params), nil); resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%s: expected invalid params, got %+v", params, resp)
+		}
+	}
+
+	jobName := `{"ref":{"type":"ref/prompt","name":"discord_notify_job_result"},"argument":{"name":"job_name","value":"BA"}}`
+	if values, _ := completionValues(t, jobName, nil); strings.Join(values, ",") != "backup" {
+		t.Errorf("job_name: got %v, want the job matched case-insensitively", values)
+	}
+	if values, _ := completionValues(t, jobName, func(server string) bool { return server != "jobs" }); len(values) != 0 {
+		t.Errorf("expected no job names when the jobs server is disabled, got %v", values)
+	}
+
+	// Unknown arguments and resource refs get an empty completion
+	if values, _ := completionValues(t, `{"ref":{"type":"ref/resource","uri":"diane://x"},"argument":{"name":"job_name","value":""}}`, nil); len(values) != 0 {
+		t.Errorf("expected an empty completion, got %v", values)
+	}
+}
+
+func TestCompletionResponseCapsValues(t *testing.T) {
+	var candidates []string
+	for i := 0; i < completionMaxValues+5; i++ {
+		candidates = append(candidates, fmt.Sprintf("job-%03d", i))
+	}
+	completion := completionResponse(append(candidates, "other"), "JOB").Result.(map[string]interface{})["completion"].(map[string]interface{})
+	if values := completion["values"].([]string); len(values) != completionMaxValues || values[0] != "job-000" {
+		t.Errorf("expected the first %d sorted values, got %d starting %v", completionMaxValues, len(values), values[:1])
+	}
+	if completion["total"] != completionMaxValues+5 || completion["hasMore"] != true {
+		t.Errorf("expected the full total and hasMore, got %v", completion)
+	}
+}
+
+func TestDegradedError(t *testing.T) {
+	tracker := tools.NewHealthTracker()
+	tracker.RecordTools("places", errors.New("listing tools panicked"))
+	if got := degradedError(tracker.Health("places")); got != "listing tools panicked" {
+		t.Errorf("degraded only by its tool listing: got %q", got)
+	}
+
+	down := fmt.Errorf("weather %w: connection refused", tools.ErrBackendUnreachable)
+	for i := 0; i < tools.DegradedAfter; i++ {
+		tracker.Record("weather", down)
+	}
+	if got := degradedError(tracker.Health("weather")); got != down.Error() {
+		t.Errorf("degraded by failed calls: got %q", got)
+	}
+}
+
+func TestValidateToolArguments(t *testing.T) {
+	resp, ok := validateToolArguments("job_add", map[string]interface{}{"name": "backup"})
+	if ok || resp.Error == nil || resp.Error.Code != -32602 {
+		t.Fatalf("expected -32602 for missing job_add arguments, got %+v", resp)
+	}
+	if _, ok := validateToolArguments("job_add", map[string]interface{}{
+		"name": "backup", "schedule": "0 3 * * *", "command": "restic backup",
+	}); !ok {
+		t.Error("expected valid job_add arguments to pass")
+	}
+	if _, ok := validateToolArguments("some_proxied_tool", nil); !ok {
+		t.Error("expected tools outside the builtin set to be left unvalidated")
+	}
+}
+
+func TestBriefingPrompt(t *testing.T) {
+	all := briefingSources{weather: true, calendar: true, email: true, finance: true}
+	msgs := getBriefingPrompt("daily_briefing", map[string]string{"location": "Oslo", "budget_id": "b-1"}, all)
+	if len(msgs) != 1 {
+		t.Fatalf("expected one message, got %d", len(msgs))
+	}
+	text := msgs[0].Content.Text
+	for _, want := range []string{
+		`weather_search_location_weather with location="Oslo"`,
+		"calendar_list_events",
+		"gmail_search",
+		`finance_budget_report with budget_id="b-1" and threshold=80`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the briefing, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Skip these sections") {
+		t.Errorf("nothing should be skipped with every source available, got:\n%s", text)
+	}
+
+	// Only the available sources are gathered; the rest are named as skipped
+	msgs = getBriefingPrompt("daily_briefing", nil, briefingSources{weather: true})
+	text = msgs[0].Content.Text
+	if !strings.Contains(text, "1. **Weather**") || strings.Contains(text, "2.") {
+		t.Errorf("expected weather as the only step, got:\n%s", text)
+	}
+	if !strings.Contains(text, "Skip these sections, their tools aren't available: calendar, email, finance.") {
+		t.Errorf("expected the missing sources to be skipped, got:\n%s", text)
+	}
+
+	if msgs := getBriefingPrompt("daily_briefing", nil, briefingSources{}); msgs != nil {
+		t.Errorf("expected no prompt without any source, got %+v", msgs)
+	}
+	if msgs := getBriefingPrompt("other", nil, all); msgs != nil {
+		t.Errorf("expected no prompt for another name, got %+v", msgs)
+	}
+}
+
+func TestAvailableBriefingSources(t *testing.T) {
+	savedWeather, savedGoogle, savedFinance := weatherProvider, googleProvider, financeProvider
+	t.Cleanup(func() {
+		weatherProvider, googleProvider, financeProvider = savedWeather, savedGoogle, savedFinance
+	})
+	weatherProvider, googleProvider, financeProvider = weather.NewProvider(), nil, nil
+
+	if got := availableBriefingSources(nil); got != (briefingSources{weather: true}) {
+		t.Errorf("outside a context: got %+v, want weather only", got)
+	}
+	if got := availableBriefingSources(func(server string) bool { return server != "weather" }); got.any() {
+		t.Errorf("with weather disabled in the context: got %+v, want none", got)
+	}
+}
+
+func TestProxiedToolError(t *testing.T) {
+	if _, ok := proxiedToolError("missing", errors.New("unknown tool: missing")); ok {
+		t.Error("expected an unknown tool to fall through to not found")
+	}
+
+	tooLarge := &mcpproxy.ResultTooLargeError{What: "result of tool dump", Size: 2048, Limit: 1024}
+	resp, ok := proxiedToolError("dump", tooLarge)
+	if !ok || resp.Error != nil {
+		t.Fatalf("expected an oversized result to be a tool error, got %+v", resp)
+	}
+	if text, failed := errorResult(resp.Result); !failed || !strings.Contains(text, "2048 bytes, over the 1024 byte limit") {
+		t.Errorf("expected the size and limit in the error, got %q, %v", text, failed)
+	}
+
+	busy := errors.New("server fragile is at its concurrency limit (1); no slot freed up within 30s")
+	resp, ok = proxiedToolError("work", busy)
+	if text, failed := errorResult(resp.Result); !ok || !failed || text != busy.Error() {
+		t.Errorf("expected a busy server to be reported as such, got %+v", resp)
+	}
+
+	resp, ok = proxiedToolError("wait", &mcpproxy.ToolTimeoutError{Server: "slow", Tool: "wait", Timeout: time.Second})
+	if !ok || resp.Error == nil || resp.Error.Code != errCodeRequestTimeout {
+		t.Errorf("expected a timeout to stay a protocol error, got %+v", resp)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 // --- Helper Functions ---
@@ -244,6 +246,14 @@ func (p *Provider) HasTool(name string) bool {
 	return false
 }
 
+// ToolCategory separates the reminders tools from the contacts tools
+func (p *Provider) ToolCategory(name string) string {
+	if strings.Contains(name, "reminder") {
+		return tools.CategoryTasks
+	}
+	return tools.CategoryContacts
+}
+
 func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
 	switch name {
 	// Reminders
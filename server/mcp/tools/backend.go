@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNotConfigured marks a provider whose credentials or settings are missing
+var ErrNotConfigured = errors.New("not configured")
+
+// ErrBackendUnauthorized marks a backend that answered but rejected the
+// configured credentials
+var ErrBackendUnauthorized = errors.New("credentials rejected")
+
+// BackendChecker is optionally implemented by providers that depend on an
+// external backend, so doctor can verify it is reachable and accepts the
+// configured credentials
+type BackendChecker interface {
+	// CheckBackend makes the cheapest authenticated call the backend offers.
+	// Failures should wrap ErrNotConfigured, ErrBackendUnauthorized or
+	// ErrBackendUnreachable where they apply.
+	CheckBackend(ctx context.Context) error
+}
+
+// CheckHTTP sends req and classifies the outcome for provider: network
+// failures wrap ErrBackendUnreachable, 401 and 403 wrap
+// ErrBackendUnauthorized and any other non-2xx status is an error. The
+// request's context bounds the check.
+func CheckHTTP(provider string, req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return WrapUnreachable(provider, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s %w (HTTP %d)", provider, ErrBackendUnauthorized, resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("%s backend returned HTTP %d", provider, resp.StatusCode)
+	}
+	return nil
+}
+
+// BackendCheckStatus maps the result of a backend check to a doctor status.
+// Missing configuration is a warning since the provider may simply be
+// unused; a backend that is down or rejects the credentials is a failure.
+func BackendCheckStatus(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrNotConfigured):
+		return "warn"
+	default:
+		return "fail"
+	}
+}
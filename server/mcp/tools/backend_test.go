@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckHTTP(t *testing.T) {
+	statuses := map[string]int{"/ok": 200, "/unauthorized": 401, "/forbidden": 403, "/broken": 500}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[r.URL.Path])
+	}))
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	defer srv.Close()
+
+	check := func(url string) error {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return CheckHTTP("test", req)
+	}
+
+	if err := check(srv.URL + "/ok"); err != nil {
+		t.Errorf("200: unexpected error %v", err)
+	}
+	for _, path := range []string{"/unauthorized", "/forbidden"} {
+		if err := check(srv.URL + path); !errors.Is(err, ErrBackendUnauthorized) {
+			t.Errorf("%s: got %v, want ErrBackendUnauthorized", path, err)
+		}
+	}
+	err := check(srv.URL + "/broken")
+	if err == nil || errors.Is(err, ErrBackendUnauthorized) || errors.Is(err, ErrBackendUnreachable) {
+		t.Errorf("500: got %v, want a plain error", err)
+	}
+	if err := check(down.URL); !errors.Is(err, ErrBackendUnreachable) {
+		t.Errorf("closed server: got %v, want ErrBackendUnreachable", err)
+	}
+}
+
+func TestBackendCheckStatus(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{nil, "ok"},
+		{fmt.Errorf("google %w: no token", ErrNotConfigured), "warn"},
+		{fmt.Errorf("google %w (HTTP 401)", ErrBackendUnauthorized), "fail"},
+		{fmt.Errorf("google %w: timeout", ErrBackendUnreachable), "fail"},
+		{errors.New("google backend returned HTTP 500"), "fail"},
+	} {
+		if got := BackendCheckStatus(tc.err); got != tc.want {
+			t.Errorf("BackendCheckStatus(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// NoCacheArg is the tool-call argument that bypasses the result cache for
+// one call. It is removed from the arguments before the tool runs.
+const NoCacheArg = "no_cache"
+
+// CacheableProvider is optionally implemented by providers with read-only,
+// idempotent tools whose results may be reused for identical calls
+type CacheableProvider interface {
+	// CacheTTL returns how long a result of the named tool may be reused,
+	// or 0 if it must not be cached
+	CacheTTL(name string) time.Duration
+}
+
+// AddNoCacheArg documents NoCacheArg in a cacheable tool's input schema
+func AddNoCacheArg(schema map[string]interface{}) {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		properties = make(map[string]interface{})
+		schema["properties"] = properties
+	}
+	properties[NoCacheArg] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "If true, fetch a fresh result instead of reusing a recent identical call's",
+	}
+}
+
+// ResultCache holds the results of cacheable tool calls, keyed by tool name
+// and arguments, until their TTL passes
+type ResultCache struct {
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+	disabled bool
+	now      func() time.Time
+}
+
+type cacheEntry struct {
+	result  interface{}
+	expires time.Time
+}
+
+// NewResultCache creates an empty, enabled result cache
+func NewResultCache() *ResultCache {
+	return &ResultCache{
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+// SetEnabled turns caching on or off. Turning it off drops cached results.
+func (c *ResultCache) SetEnabled(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disabled = !enabled
+	if !enabled {
+		c.entries = make(map[string]cacheEntry)
+	}
+}
+
+// Call runs call with args unless a result for the same tool and arguments
+// was cached within ttl. Successful results are cached when ttl is positive.
+// NoCacheArg is stripped from args; if set, the call always runs and its
+// result replaces the cached one.
+func (c *ResultCache) Call(tool string, args map[string]interface{}, ttl time.Duration, call func(map[string]interface{}) (interface{}, error)) (interface{}, error) {
+	args, noCache := takeNoCache(args)
+
+	c.mu.Lock()
+	disabled := c.disabled
+	c.mu.Unlock()
+	if ttl <= 0 || disabled {
+		return call(args)
+	}
+
+	key, err := cacheKey(tool, args)
+	if err != nil {
+		return call(args)
+	}
+
+	if !noCache {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && c.now().Before(entry.expires) {
+			return entry.result, nil
+		}
+	}
+
+	result, err := call(args)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if !c.disabled {
+		c.entries[key] = cacheEntry{result: result, expires: now.Add(ttl)}
+	}
+	return result, nil
+}
+
+// takeNoCache returns args without NoCacheArg and whether it was set.
+// args itself is not modified.
+func takeNoCache(args map[string]interface{}) (map[string]interface{}, bool) {
+	v, ok := args[NoCacheArg]
+	if !ok {
+		return args, false
+	}
+	rest := make(map[string]interface{}, len(args)-1)
+	for k, val := range args {
+		if k != NoCacheArg {
+			rest[k] = val
+		}
+	}
+	noCache, _ := v.(bool)
+	return rest, noCache
+}
+
+// cacheKey identifies a call by tool name and arguments. Map keys are
+// marshalled in sorted order, so equal arguments give equal keys.
+func cacheKey(tool string, args map[string]interface{}) (string, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return tool + "\x00" + string(data), nil
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResultCache(t *testing.T) {
+	now := time.Now()
+	c := NewResultCache()
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	var lastArgs map[string]interface{}
+	call := func(args map[string]interface{}) (interface{}, error) {
+		calls++
+		lastArgs = args
+		return calls, nil
+	}
+	get := func(args map[string]interface{}, ttl time.Duration) interface{} {
+		t.Helper()
+		result, err := c.Call("weather_get_weather", args, ttl, call)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	oslo := map[string]interface{}{"latitude": 59.9, "longitude": 10.7}
+	if got := get(oslo, time.Minute); got != 1 {
+		t.Fatalf("first call = %v, want 1", got)
+	}
+	// Same arguments in another order hit the cache
+	if got := get(map[string]interface{}{"longitude": 10.7, "latitude": 59.9}, time.Minute); got != 1 {
+		t.Errorf("repeated call = %v, want the cached 1", got)
+	}
+	if got := get(map[string]interface{}{"latitude": 60.4, "longitude": 5.3}, time.Minute); got != 2 {
+		t.Errorf("different arguments = %v, want 2", got)
+	}
+
+	// no_cache bypasses the cache, is not passed on, and refreshes the entry
+	bypass := map[string]interface{}{"latitude": 59.9, "longitude": 10.7, NoCacheArg: true}
+	if got := get(bypass, time.Minute); got != 3 {
+		t.Errorf("no_cache call = %v, want 3", got)
+	}
+	if _, ok := lastArgs[NoCacheArg]; ok {
+		t.Error("no_cache should be stripped before the tool runs")
+	}
+	if _, ok := bypass[NoCacheArg]; !ok {
+		t.Error("the caller's arguments should not be modified")
+	}
+	if got := get(oslo, time.Minute); got != 3 {
+		t.Errorf("call after no_cache = %v, want the refreshed 3", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got := get(oslo, time.Minute); got != 4 {
+		t.Errorf("call after expiry = %v, want 4", got)
+	}
+
+	// Tools without a TTL are never cached
+	if get(oslo, 0) != 5 || get(oslo, 0) != 6 {
+		t.Error("uncacheable tool results should not be reused")
+	}
+
+	c.SetEnabled(false)
+	if get(oslo, time.Minute) != 7 || get(oslo, time.Minute) != 8 {
+		t.Error("a disabled cache should not reuse results")
+	}
+}
+
+func TestResultCacheSkipsErrors(t *testing.T) {
+	c := NewResultCache()
+	calls := 0
+	failing := func(args map[string]interface{}) (interface{}, error) {
+		calls++
+		return nil, errors.New("upstream down")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Call("places_search", map[string]interface{}{"query": "cafe"}, time.Minute, failing); err == nil {
+			t.Fatal("expected the error to be returned")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("failed results should not be cached, got %d calls", calls)
+	}
+}
+
+func TestAddNoCacheArg(t *testing.T) {
+	schema := map[string]interface{}{"type": "object"}
+	AddNoCacheArg(schema)
+	properties, _ := schema["properties"].(map[string]interface{})
+	if _, ok := properties[NoCacheArg]; !ok {
+		t.Errorf("expected %s in properties, got %v", NoCacheArg, schema)
+	}
+	if errs := ValidateArguments(schema, map[string]interface{}{NoCacheArg: true}); len(errs) > 0 {
+		t.Errorf("no_cache should validate, got %v", errs)
+	}
+}
@@ -0,0 +1,30 @@
+package tools
+
+// Tool categories, for clients that group tools by what they work with
+// rather than by the server that provides them
+const (
+	CategoryEmail          = "email"
+	CategoryCalendar       = "calendar"
+	CategoryContacts       = "contacts"
+	CategoryTasks          = "tasks"
+	CategoryFiles          = "files"
+	CategoryFinance        = "finance"
+	CategoryPlaces         = "places"
+	CategoryWeather        = "weather"
+	CategoryNotifications  = "notifications"
+	CategoryInfrastructure = "infrastructure"
+	CategoryDevelopment    = "development"
+	CategoryWeb            = "web"
+	CategorySystem         = "system"
+	CategoryAutomation     = "automation"
+	CategoryAgents         = "agents"
+	CategoryContexts       = "contexts"
+)
+
+// CategorizedProvider is optionally implemented by providers whose tools
+// don't all share one category
+type CategorizedProvider interface {
+	// ToolCategory returns the category of the named tool, or "" to use the
+	// provider's default
+	ToolCategory(name string) string
+}
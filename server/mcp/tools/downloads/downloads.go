@@ -17,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/config"
 	"github.com/diane-assistant/diane/mcp/tools"
 )
 
@@ -61,27 +62,36 @@ type Provider struct {
 	downloads   map[string]*Download
 	mu          sync.RWMutex
 	httpClient  *http.Client
+	registrar   Registrar
+	policy      Config
 }
 
-// NewProvider creates a new downloads provider
-func NewProvider() (*Provider, error) {
+// NewProvider creates a new downloads provider that enforces cfg's limits
+func NewProvider(cfg Config) (*Provider, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	downloadDir := filepath.Join(home, ".diane", "downloads")
+	downloadDir := filepath.Join(home, config.DirName(), "downloads")
 	if err := os.MkdirAll(downloadDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create downloads directory: %w", err)
 	}
 
-	return &Provider{
+	return newProvider(downloadDir, cfg), nil
+}
+
+func newProvider(downloadDir string, cfg Config) *Provider {
+	p := &Provider{
 		downloadDir: downloadDir,
 		downloads:   make(map[string]*Download),
-		httpClient: &http.Client{
-			Timeout: 30 * time.Minute, // Long timeout for large files
-		},
-	}, nil
+		policy:      cfg.normalize(),
+	}
+	p.httpClient = &http.Client{
+		Timeout:       30 * time.Minute, // Long timeout for large files
+		CheckRedirect: p.checkRedirect,
+	}
+	return p
 }
 
 // Name returns the provider name
@@ -99,7 +109,7 @@ func (p *Provider) Tools() []Tool {
 	return []Tool{
 		{
 			Name:        "downloads_start",
-			Description: "Start downloading a file from a URL. Downloads run asynchronously in the background - this tool returns immediately with a download ID. Use downloads_status with the returned ID to check progress and completion. Files are saved to ~/.diane/downloads/. Only HTTP and HTTPS URLs are supported.",
+			Description: "Start downloading a file from a URL. Downloads run asynchronously in the background - this tool returns immediately with a download ID. Use downloads_status with the returned ID to check progress and completion. Files are saved to ~/.diane/downloads/. Only HTTP and HTTPS URLs are supported. The server may restrict downloads by host, content type and size; a download that breaks these limits is refused or aborted with a 'download policy violation' error.",
 			InputSchema: map[string]interface{}{
 				"type":     "object",
 				"required": []string{"url"},
@@ -150,13 +160,44 @@ func (p *Provider) Tools() []Tool {
 				},
 			},
 		},
+		{
+			Name:        "downloads_extract",
+			Description: "Unpack a downloaded .zip, .tar.gz or .tgz archive into a directory and list the extracted files. Entries that would land outside the target directory fail the extraction, links are skipped, and the total uncompressed size is capped (1 GiB by default). Optionally registers the extracted files in the file index, like file_registry_crawl.",
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []string{"filename"},
+				"properties": map[string]interface{}{
+					"filename": map[string]interface{}{
+						"type":        "string",
+						"description": "The archive's filename in the downloads directory, as shown by downloads_list",
+					},
+					"target_dir": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory to extract into, relative to the downloads directory (an absolute path must also be inside it). Defaults to a new directory named after the archive. Existing files are never overwritten",
+					},
+					"register": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Register the extracted files in the file index (file_registry). Default: false",
+					},
+					"tags": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Tags to apply to the registered files (with register)",
+					},
+					"max_bytes": map[string]interface{}{
+						"type":        "integer",
+						"description": "Lower cap on the total uncompressed size, in bytes (0 = the 1 GiB default, which is also the maximum)",
+					},
+				},
+			},
+		},
 	}
 }
 
 // HasTool checks if a tool name belongs to this provider
 func (p *Provider) HasTool(name string) bool {
 	switch name {
-	case "downloads_start", "downloads_status", "downloads_list", "downloads_delete":
+	case "downloads_start", "downloads_status", "downloads_list", "downloads_delete", "downloads_extract":
 		return true
 	}
 	return false
@@ -173,6 +214,8 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.listFiles(args)
 	case "downloads_delete":
 		return p.deleteFile(args)
+	case "downloads_extract":
+		return p.extractArchive(args)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
@@ -193,6 +236,9 @@ func (p *Provider) startDownload(args map[string]interface{}) (interface{}, erro
 	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
 		return nil, fmt.Errorf("only http and https URLs are supported")
 	}
+	if err := p.checkURL(parsedURL); err != nil {
+		return nil, err
+	}
 
 	// Generate download ID
 	id := generateID()
@@ -252,6 +298,10 @@ func (p *Provider) performDownload(download *Download) {
 		p.updateStatus(download.ID, StatusFailed, fmt.Sprintf("server returned %s", resp.Status), 0)
 		return
 	}
+	if err := p.checkResponse(resp); err != nil {
+		p.updateStatus(download.ID, StatusFailed, err.Error(), 0)
+		return
+	}
 
 	// Update filename from Content-Disposition if available and not custom
 	p.mu.RLock()
@@ -305,6 +355,15 @@ func (p *Provider) performDownload(download *Download) {
 			}
 			bytesWritten += int64(written)
 
+			// Content-Length can be missing or wrong, so the cap is
+			// enforced on what actually arrives
+			if p.policy.MaxBytes > 0 && bytesWritten > p.policy.MaxBytes {
+				file.Close()
+				os.Remove(filePath)
+				p.updateStatus(download.ID, StatusFailed, fmt.Sprintf("%v: download exceeded downloads.max_bytes (%d) and was aborted", ErrPolicyViolation, p.policy.MaxBytes), bytesWritten)
+				return
+			}
+
 			// Update progress periodically
 			p.mu.Lock()
 			download.BytesWritten = bytesWritten
@@ -623,6 +682,7 @@ All downloaded files are saved to: ` + "`~/.diane/downloads/`" + `
 | downloads_status | Check download progress/completion |
 | downloads_list | List files in downloads directory |
 | downloads_delete | Remove a downloaded file |
+| downloads_extract | Unpack a .zip/.tar.gz archive, optionally indexing the files |
 
 ## Async Download Pattern
 
@@ -687,6 +747,18 @@ downloads_delete filename="file.pdf"
 ` + "```" + `
 Use the filename only (not the full path). This prevents accidental deletion of files outside the downloads directory.
 
+## Extracting Archives
+
+` + "```" + `
+downloads_extract filename="dataset.zip" register=true tags=["dataset"]
+` + "```" + `
+Unpacks into ` + "`~/.diane/downloads/dataset/`" + ` (or ` + "`target_dir`" + `) and returns the extracted paths and a count. With register=true the files are added to the file index.
+
+- Entries with absolute paths or ` + "`..`" + ` that would escape the target fail the whole extraction, and nothing is left behind
+- Symlinks and other special entries are skipped and listed under ` + "`skipped`" + `
+- The total uncompressed size is capped at 1 GiB (lower it with max_bytes); bigger archives are rejected
+- Existing files are never overwritten
+
 ## Filename Handling
 
 - If no custom filename is provided, it's extracted from the URL path
@@ -697,6 +769,7 @@ Use the filename only (not the full path). This prevents accidental deletion of
 ## Limitations
 
 - Only HTTP and HTTPS URLs are supported
+- The server may allow only some hosts, content types and sizes (the ` + "`downloads`" + ` section of config.json); a download breaking them fails with a "download policy violation" error
 - Download status is tracked in memory (session only) - restarting Diane clears the download history
 - Files on disk persist across restarts (use downloads_list to see them)
 - Maximum download timeout is 30 minutes per file
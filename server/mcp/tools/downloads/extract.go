@@ -0,0 +1,328 @@
+package downloads
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diane-assistant/diane/mcp/tools"
+)
+
+// DefaultMaxExtractBytes caps the total uncompressed size downloads_extract
+// writes, as a guard against zip bombs
+const DefaultMaxExtractBytes int64 = 1 << 30
+
+// errExtractTooLarge is returned once an archive's contents pass the size cap
+var errExtractTooLarge = errors.New("archive exceeds the uncompressed size limit")
+
+// Registrar indexes every file under a directory in the file registry and
+// returns a summary of what it registered
+type Registrar func(dir string, tags []string) (map[string]interface{}, error)
+
+// SetRegistrar lets downloads_extract register what it extracts. Without
+// one, the register option is rejected.
+func (p *Provider) SetRegistrar(r Registrar) {
+	p.registrar = r
+}
+
+// archiveFormat returns "zip" or "tar.gz" for a supported archive name,
+// or "" otherwise
+func archiveFormat(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	}
+	return ""
+}
+
+// archiveBase strips the archive extension from name
+func archiveBase(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(lower, ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// withinDir reports whether path is root or lies under it, once the
+// symlinks in the part of path that exists are resolved, so a link can't
+// lead the extraction out of root
+func withinDir(root, path string) bool {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false
+	}
+	// Resolve the longest existing prefix; the rest is created as plain
+	// directories
+	existing, rest := path, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return false
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(resolvedRoot, filepath.Join(resolved, rest))
+	return err == nil && (rel == "." || filepath.IsLocal(rel))
+}
+
+// extractor writes archive entries under dir, refusing entries that would
+// land outside it and stopping once more than limit bytes are written
+type extractor struct {
+	dir     string
+	limit   int64
+	written int64
+	files   []string
+	skipped []string
+}
+
+// entryPath resolves an archive entry name inside the target directory
+func (x *extractor) entryPath(name string) (string, error) {
+	rel := filepath.FromSlash(strings.TrimPrefix(name, "./"))
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("entry %q escapes the target directory", name)
+	}
+	return filepath.Join(x.dir, rel), nil
+}
+
+func (x *extractor) mkdir(name string) error {
+	path, err := x.entryPath(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+// writeFile copies r to the entry's path, counting the bytes actually
+// written against the limit rather than trusting the archive's headers
+func (x *extractor) writeFile(name string, r io.Reader, mode os.FileMode) error {
+	path, err := x.entryPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// O_EXCL so a repeated entry can't overwrite one written earlier
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	x.files = append(x.files, path)
+	n, err := io.Copy(f, io.LimitReader(r, x.limit-x.written+1))
+	x.written += n
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if x.written > x.limit {
+		return errExtractTooLarge
+	}
+	return nil
+}
+
+func (x *extractor) extractZip(archivePath string) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip: %w", err)
+	}
+	defer zr.Close()
+
+	// Check the declared sizes first so an obvious bomb fails before
+	// anything is written
+	var declared uint64
+	for _, f := range zr.File {
+		declared += f.UncompressedSize64
+	}
+	if declared > uint64(x.limit) {
+		return errExtractTooLarge
+	}
+
+	for _, f := range zr.File {
+		switch mode := f.Mode(); {
+		case mode.IsDir():
+			if err := x.mkdir(f.Name); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", f.Name, err)
+			}
+			err = x.writeFile(f.Name, rc, mode)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			// Symlinks could point outside the target directory
+			x.skipped = append(x.skipped, f.Name)
+		}
+	}
+	return nil
+}
+
+func (x *extractor) extractTarGz(archivePath string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar: %w", err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := x.mkdir(hdr.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := x.writeFile(hdr.Name, tr, hdr.FileInfo().Mode()); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			// pax metadata, not an entry
+		default:
+			// Links and special files could point outside the target
+			// directory or aren't meaningful to index
+			x.skipped = append(x.skipped, hdr.Name)
+		}
+	}
+}
+
+// extractArchive unpacks a downloaded archive
+func (p *Provider) extractArchive(args map[string]interface{}) (interface{}, error) {
+	filename, ok := args["filename"].(string)
+	if !ok || filename == "" {
+		return nil, fmt.Errorf("filename is required")
+	}
+	// Like downloads_delete, only files in the downloads directory
+	filename = filepath.Base(filename)
+	archivePath := filepath.Join(p.downloadDir, filename)
+	if info, err := os.Stat(archivePath); err != nil {
+		return nil, fmt.Errorf("file not found: %s", filename)
+	} else if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory, not an archive", filename)
+	}
+	format := archiveFormat(filename)
+	if format == "" {
+		return nil, fmt.Errorf("unsupported archive type for %s (expected .zip, .tar.gz or .tgz)", filename)
+	}
+
+	register := tools.GetBool(args, "register", false)
+	if register && p.registrar == nil {
+		return nil, fmt.Errorf("register requires the file_registry tools, which are not available")
+	}
+	var tags []string
+	if raw, ok := args["tags"].([]interface{}); ok {
+		for _, t := range raw {
+			if s, ok := t.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+	}
+	limit := int64(tools.GetInt(args, "max_bytes", 0))
+	if limit < 0 {
+		return nil, fmt.Errorf("max_bytes must not be negative")
+	}
+	if limit == 0 || limit > DefaultMaxExtractBytes {
+		limit = DefaultMaxExtractBytes
+	}
+
+	targetDir := tools.GetString(args, "target_dir")
+	switch {
+	case targetDir == "":
+		targetDir = p.getUniqueFilePath(filepath.Join(p.downloadDir, sanitizeFilename(archiveBase(filename))))
+	case !filepath.IsAbs(targetDir):
+		targetDir = filepath.Join(p.downloadDir, targetDir)
+	}
+	targetDir = filepath.Clean(targetDir)
+	if !withinDir(p.downloadDir, targetDir) {
+		return nil, fmt.Errorf("target_dir must be inside the downloads directory (%s)", p.downloadDir)
+	}
+	created := false
+	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
+		created = true
+	}
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	x := &extractor{dir: targetDir, limit: limit}
+	var err error
+	if format == "zip" {
+		err = x.extractZip(archivePath)
+	} else {
+		err = x.extractTarGz(archivePath)
+	}
+	if err != nil {
+		// Don't leave a partial extraction behind
+		if created {
+			os.RemoveAll(targetDir)
+		} else {
+			for _, f := range x.files {
+				os.Remove(f)
+			}
+		}
+		if errors.Is(err, errExtractTooLarge) {
+			return nil, fmt.Errorf("%s: %w (%d bytes)", filename, err, limit)
+		}
+		return nil, fmt.Errorf("failed to extract %s: %w", filename, err)
+	}
+	slog.Info("Archive extracted", "archive", archivePath, "target", targetDir, "files", len(x.files), "bytes", x.written)
+
+	result := map[string]interface{}{
+		"message":    "Archive extracted",
+		"archive":    filename,
+		"target_dir": targetDir,
+		"count":      len(x.files),
+		"bytes":      x.written,
+		"files":      x.files,
+	}
+	if len(x.skipped) > 0 {
+		result["skipped"] = x.skipped
+	}
+	if register {
+		summary, err := p.registrar(targetDir, tags)
+		if err != nil {
+			result["registry_error"] = err.Error()
+		} else {
+			result["registry"] = summary
+		}
+	}
+	return textContent(result), nil
+}
@@ -0,0 +1,163 @@
+package downloads
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestProvider(t *testing.T) *Provider {
+	t.Helper()
+	return newProvider(t.TempDir(), Config{})
+}
+
+func writeZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// resultJSON decodes the JSON text of a tool result
+func resultJSON(t *testing.T, result interface{}) map[string]interface{} {
+	t.Helper()
+	content := result.(map[string]interface{})["content"].([]map[string]interface{})
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(content[0]["text"].(string)), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestExtractZip(t *testing.T) {
+	p := newTestProvider(t)
+	writeZip(t, filepath.Join(p.downloadDir, "data.zip"), map[string]string{
+		"a.txt":        "alpha",
+		"nested/b.txt": "beta",
+	})
+
+	var registeredDir string
+	p.SetRegistrar(func(dir string, tags []string) (map[string]interface{}, error) {
+		registeredDir = dir
+		return map[string]interface{}{"registered": 2}, nil
+	})
+
+	result, err := p.Call("downloads_extract", map[string]interface{}{"filename": "data.zip", "register": true})
+	if err != nil {
+		t.Fatalf("downloads_extract failed: %v", err)
+	}
+	out := resultJSON(t, result)
+	target := filepath.Join(p.downloadDir, "data")
+	if out["target_dir"] != target || out["count"] != float64(2) {
+		t.Errorf("unexpected result: %v", out)
+	}
+	if data, err := os.ReadFile(filepath.Join(target, "nested", "b.txt")); err != nil || string(data) != "beta" {
+		t.Errorf("nested/b.txt = %q, %v", data, err)
+	}
+	if registeredDir != target || out["registry"] == nil {
+		t.Errorf("expected %s to be registered, got %q (%v)", target, registeredDir, out["registry"])
+	}
+}
+
+func TestExtractRejectsTraversal(t *testing.T) {
+	p := newTestProvider(t)
+	writeZip(t, filepath.Join(p.downloadDir, "evil.zip"), map[string]string{
+		"ok.txt":           "fine",
+		"../../escape.txt": "gotcha",
+	})
+
+	_, err := p.Call("downloads_extract", map[string]interface{}{"filename": "evil.zip"})
+	if err == nil || !strings.Contains(err.Error(), "escapes the target directory") {
+		t.Fatalf("expected a traversal error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p.downloadDir, "evil")); !os.IsNotExist(err) {
+		t.Error("expected the partial extraction to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(p.downloadDir), "escape.txt")); !os.IsNotExist(err) {
+		t.Error("entry was written outside the target directory")
+	}
+}
+
+func TestExtractTarGzSkipsLinksAndCapsSize(t *testing.T) {
+	p := newTestProvider(t)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	content := strings.Repeat("x", 100)
+	tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755})
+	tw.WriteHeader(&tar.Header{Name: "dir/file.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})
+	tw.Write([]byte(content))
+	tw.WriteHeader(&tar.Header{Name: "dir/link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"})
+	tw.Close()
+	gz.Close()
+	if err := os.WriteFile(filepath.Join(p.downloadDir, "bundle.tar.gz"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := p.Call("downloads_extract", map[string]interface{}{"filename": "bundle.tar.gz", "target_dir": "out"})
+	if err != nil {
+		t.Fatalf("downloads_extract failed: %v", err)
+	}
+	out := resultJSON(t, result)
+	if out["count"] != float64(1) || len(out["skipped"].([]interface{})) != 1 {
+		t.Errorf("expected one file and the link skipped, got %v", out)
+	}
+	if _, err := os.Lstat(filepath.Join(p.downloadDir, "out", "dir", "link")); !os.IsNotExist(err) {
+		t.Error("symlink entry should not be created")
+	}
+
+	_, err = p.Call("downloads_extract", map[string]interface{}{"filename": "bundle.tar.gz", "target_dir": "small", "max_bytes": float64(50)})
+	if err == nil || !strings.Contains(err.Error(), "size limit") {
+		t.Errorf("expected the size cap to be enforced, got %v", err)
+	}
+
+	if _, err := p.Call("downloads_extract", map[string]interface{}{"filename": "bundle.tar.gz", "register": true}); err == nil {
+		t.Error("expected register to fail without a registrar")
+	}
+}
+
+func TestExtractTargetDirMustStayInDownloads(t *testing.T) {
+	p := newTestProvider(t)
+	writeZip(t, filepath.Join(p.downloadDir, "data.zip"), map[string]string{"a.txt": "alpha"})
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(p.downloadDir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{outside, filepath.Join(p.downloadDir, "..", "escape"), "../escape", "link", "link/sub"} {
+		_, err := p.Call("downloads_extract", map[string]interface{}{"filename": "data.zip", "target_dir": target})
+		if err == nil || !strings.Contains(err.Error(), "inside the downloads directory") {
+			t.Errorf("target_dir %q: expected it to be refused, got %v", target, err)
+		}
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Errorf("files were written outside the downloads directory: %v", entries)
+	}
+
+	inside := filepath.Join(p.downloadDir, "sets", "one")
+	result, err := p.Call("downloads_extract", map[string]interface{}{"filename": "data.zip", "target_dir": inside})
+	if err != nil {
+		t.Fatalf("absolute target_dir inside the downloads directory: %v", err)
+	}
+	if out := resultJSON(t, result); out["target_dir"] != inside {
+		t.Errorf("unexpected target_dir %v", out["target_dir"])
+	}
+}
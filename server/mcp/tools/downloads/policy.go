@@ -0,0 +1,99 @@
+package downloads
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/diane-assistant/diane/mcp/tools/httprequest"
+)
+
+// ErrPolicyViolation marks a download refused or aborted because it broke
+// one of the configured limits
+var ErrPolicyViolation = errors.New("download policy violation")
+
+// maxRedirects bounds redirect chains, each hop of which is checked
+const maxRedirects = 10
+
+// Config restricts what the provider may download. Zero values mean no
+// restriction.
+type Config struct {
+	// MaxBytes caps the size of a single download
+	MaxBytes int64
+	// AllowedContentTypes are media types ("application/pdf") or type
+	// wildcards ("image/*")
+	AllowedContentTypes []string
+	// AllowedHosts are written as for http_request: hostnames, wildcard
+	// subdomains ("*.example.com") or host:port pairs
+	AllowedHosts []string
+}
+
+// normalize lower-cases the allowlists and drops blank entries
+func (c Config) normalize() Config {
+	clean := func(list []string) []string {
+		var out []string
+		for _, s := range list {
+			if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	c.AllowedContentTypes = clean(c.AllowedContentTypes)
+	c.AllowedHosts = clean(c.AllowedHosts)
+	return c
+}
+
+// checkURL applies the host allowlist
+func (p *Provider) checkURL(u *url.URL) error {
+	if len(p.policy.AllowedHosts) > 0 && !httprequest.AllowsURL(p.policy.AllowedHosts, u) {
+		return fmt.Errorf("%w: host %s is not in downloads.allowed_hosts", ErrPolicyViolation, u.Host)
+	}
+	return nil
+}
+
+// checkRedirect keeps every redirect hop on the host allowlist
+func (p *Provider) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return p.checkURL(req.URL)
+}
+
+// checkResponse applies the size and content-type limits to a response
+// before its body is read
+func (p *Provider) checkResponse(resp *http.Response) error {
+	if p.policy.MaxBytes > 0 && resp.ContentLength > p.policy.MaxBytes {
+		return fmt.Errorf("%w: size %d bytes exceeds downloads.max_bytes (%d)", ErrPolicyViolation, resp.ContentLength, p.policy.MaxBytes)
+	}
+	if len(p.policy.AllowedContentTypes) == 0 {
+		return nil
+	}
+	header := resp.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return fmt.Errorf("%w: content type %q is not in downloads.allowed_content_types", ErrPolicyViolation, header)
+	}
+	if !contentTypeAllowed(p.policy.AllowedContentTypes, mediaType) {
+		return fmt.Errorf("%w: content type %s is not in downloads.allowed_content_types", ErrPolicyViolation, mediaType)
+	}
+	return nil
+}
+
+// contentTypeAllowed matches a media type against exact entries and
+// "type/*" wildcards
+func contentTypeAllowed(allowed []string, mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	for _, entry := range allowed {
+		if entry == mediaType || entry == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(entry, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package downloads
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForDownload polls until the download finishes
+func waitForDownload(t *testing.T, p *Provider, id string) Download {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		p.mu.RLock()
+		d := *p.downloads[id]
+		p.mu.RUnlock()
+		if d.Status == StatusCompleted || d.Status == StatusFailed {
+			return d
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("download %s did not finish", id)
+	return Download{}
+}
+
+func startTestDownload(t *testing.T, p *Provider, rawURL string) Download {
+	t.Helper()
+	result, err := p.Call("downloads_start", map[string]interface{}{"url": rawURL})
+	if err != nil {
+		t.Fatalf("downloads_start failed: %v", err)
+	}
+	return waitForDownload(t, p, resultJSON(t, result)["id"].(string))
+}
+
+func TestDownloadPolicy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.7 small"))
+		case "/big.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte(strings.Repeat("x", 1000)))
+		case "/stream.pdf":
+			// Chunked, so there's no Content-Length to check up front
+			w.Header().Set("Content-Type", "application/pdf")
+			for i := 0; i < 10; i++ {
+				w.Write([]byte(strings.Repeat("x", 100)))
+				w.(http.Flusher).Flush()
+			}
+		case "/tool.sh":
+			w.Header().Set("Content-Type", "application/x-sh")
+			w.Write([]byte("echo hi"))
+		case "/away":
+			http.Redirect(w, r, "http://elsewhere.invalid/file.pdf", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	p := newProvider(t.TempDir(), Config{
+		MaxBytes:            500,
+		AllowedContentTypes: []string{"application/pdf", "image/*"},
+		AllowedHosts:        []string{u.Host},
+	})
+
+	if d := startTestDownload(t, p, srv.URL+"/report.pdf"); d.Status != StatusCompleted {
+		t.Fatalf("allowed download failed: %s", d.Error)
+	}
+
+	for path, want := range map[string]string{
+		"/big.pdf":    "exceeds downloads.max_bytes",
+		"/stream.pdf": "was aborted",
+		"/tool.sh":    "content type application/x-sh",
+		"/away":       "elsewhere.invalid is not in downloads.allowed_hosts",
+	} {
+		d := startTestDownload(t, p, srv.URL+path)
+		if d.Status != StatusFailed || !strings.Contains(d.Error, ErrPolicyViolation.Error()) || !strings.Contains(d.Error, want) {
+			t.Errorf("%s: got %s %q, want a policy violation mentioning %q", path, d.Status, d.Error, want)
+		}
+	}
+	if _, err := os.Stat(p.downloadDir + "/stream.pdf"); !os.IsNotExist(err) {
+		t.Error("expected the aborted download to be removed")
+	}
+
+	if _, err := p.Call("downloads_start", map[string]interface{}{"url": "https://example.com/file.pdf"}); err == nil || !strings.Contains(err.Error(), "allowed_hosts") {
+		t.Errorf("expected a disallowed host to be refused up front, got %v", err)
+	}
+}
+
+func TestContentTypeAllowed(t *testing.T) {
+	allowed := []string{"application/pdf", "image/*"}
+	for mediaType, want := range map[string]bool{
+		"application/pdf": true,
+		"image/png":       true,
+		"Image/PNG":       true,
+		"application/zip": false,
+		"imagex/png":      false,
+		"text/html":       false,
+	} {
+		if got := contentTypeAllowed(allowed, mediaType); got != want {
+			t.Errorf("contentTypeAllowed(%q) = %v, want %v", mediaType, got, want)
+		}
+	}
+}
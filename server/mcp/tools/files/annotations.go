@@ -0,0 +1,146 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// annotationsProperty is the file object property holding key/value
+// annotations. Unlike tags (labels) they are structured, e.g.
+// {"project": "acme", "review_due": "2025-07"}.
+const annotationsProperty = "annotations"
+
+// annotationValue renders a scalar annotation value as a string, so
+// "true" and true annotate (and match) alike
+func annotationValue(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case bool:
+		return strconv.FormatBool(val), true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(val), true
+	}
+	return "", false
+}
+
+// getAnnotations reads an object argument of scalar values. Missing means
+// nil; anything else that isn't an object of scalars is an error.
+func getAnnotations(args map[string]interface{}, key string) (map[string]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object of key/value pairs", key)
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if k == "" {
+			return nil, fmt.Errorf("%s keys must not be empty", key)
+		}
+		s, ok := annotationValue(v)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s must be a string, number or boolean", key, k)
+		}
+		out[k] = s
+	}
+	return out, nil
+}
+
+// objectAnnotations returns the annotations stored on a file object
+func objectAnnotations(props map[string]interface{}) map[string]string {
+	raw, _ := props[annotationsProperty].(map[string]interface{})
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if s, ok := annotationValue(v); ok {
+			out[k] = s
+		}
+	}
+	return out
+}
+
+// matchesAnnotationFilter checks that the object has every wanted
+// annotation with an equal value
+func matchesAnnotationFilter(props map[string]interface{}, want map[string]string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	have := objectAnnotations(props)
+	for k, v := range want {
+		if got, ok := have[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Provider) annotate(args map[string]interface{}) (interface{}, error) {
+	set, err := getAnnotations(args, "set")
+	if err != nil {
+		return nil, err
+	}
+	remove := getStringArray(args, "remove")
+	if len(set) == 0 && len(remove) == 0 {
+		return nil, fmt.Errorf("at least one of 'set' or 'remove' is required")
+	}
+	for _, k := range remove {
+		if _, ok := set[k]; ok {
+			return nil, fmt.Errorf("annotation %q is both set and removed", k)
+		}
+	}
+
+	id := getString(args, "id")
+	source := getString(args, "source")
+	path := getString(args, "path")
+
+	if id == "" && (source == "" || path == "") {
+		return nil, fmt.Errorf("either 'id' or both 'source' and 'path' are required")
+	}
+
+	ctx := context.Background()
+	fileID, obj, err := p.resolveFileID(ctx, id, source, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Property updates merge top-level keys, so write the whole map back
+	annotations := objectAnnotations(obj.Properties)
+	for _, k := range remove {
+		delete(annotations, k)
+	}
+	for k, v := range set {
+		annotations[k] = v
+	}
+	stored := make(map[string]interface{}, len(annotations))
+	for k, v := range annotations {
+		stored[k] = v
+	}
+	_, err = p.client.Graph.UpdateObject(ctx, fileID, &graph.UpdateObjectRequest{
+		Properties: map[string]interface{}{annotationsProperty: stored},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update annotations: %w", err)
+	}
+
+	setKeys := make([]string, 0, len(set))
+	for k := range set {
+		setKeys = append(setKeys, k)
+	}
+	sort.Strings(setKeys)
+	return textContent(map[string]interface{}{
+		"status":      "annotated",
+		"id":          fileID,
+		"set":         setKeys,
+		"removed":     remove,
+		"annotations": annotations,
+		"message":     fmt.Sprintf("Set %d and removed %d annotation(s)", len(set), len(remove)),
+	}), nil
+}
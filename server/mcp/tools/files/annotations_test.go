@@ -0,0 +1,53 @@
+package files
+
+import "testing"
+
+func TestGetAnnotations(t *testing.T) {
+	got, err := getAnnotations(map[string]interface{}{
+		"set": map[string]interface{}{"project": "acme", "confidential": true, "year": float64(2025)},
+	}, "set")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["project"] != "acme" || got["confidential"] != "true" || got["year"] != "2025" {
+		t.Errorf("unexpected annotations: %v", got)
+	}
+
+	if got, err := getAnnotations(map[string]interface{}{}, "set"); got != nil || err != nil {
+		t.Errorf("missing argument = %v, %v; want nil, nil", got, err)
+	}
+	for _, bad := range []interface{}{
+		"project=acme",
+		map[string]interface{}{"": "x"},
+		map[string]interface{}{"nested": map[string]interface{}{"a": "b"}},
+	} {
+		if _, err := getAnnotations(map[string]interface{}{"set": bad}, "set"); err == nil {
+			t.Errorf("expected an error for %v", bad)
+		}
+	}
+}
+
+func TestMatchesAnnotationFilter(t *testing.T) {
+	props := map[string]interface{}{
+		"filename":    "plan.pdf",
+		"annotations": map[string]interface{}{"project": "acme", "confidential": "true"},
+	}
+	tests := []struct {
+		want  map[string]string
+		match bool
+	}{
+		{nil, true},
+		{map[string]string{"project": "acme"}, true},
+		{map[string]string{"project": "acme", "confidential": "true"}, true},
+		{map[string]string{"project": "other"}, false},
+		{map[string]string{"review_due": "2025-07"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchesAnnotationFilter(props, tt.want); got != tt.match {
+			t.Errorf("matchesAnnotationFilter(%v) = %v, want %v", tt.want, got, tt.match)
+		}
+	}
+	if matchesAnnotationFilter(map[string]interface{}{}, map[string]string{"project": "acme"}) {
+		t.Error("a file without annotations should not match")
+	}
+}
@@ -0,0 +1,126 @@
+package files
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// tempDirs are path segments marking a copy as temporary or disposable
+var tempDirs = map[string]bool{
+	"tmp": true, "temp": true, ".tmp": true, "cache": true, "caches": true,
+	".cache": true, ".trash": true, "trash": true, "$recycle.bin": true,
+}
+
+// copyNameRe matches names file managers give to copies: "x copy.pdf",
+// "x (2).pdf", "Copy of x.pdf", "x~.pdf", "~$x.docx"
+var copyNameRe = regexp.MustCompile(`(?i)( copy( \d+)?|\s\(\d+\))(\.[^.]*)?$|^copy of |^~\$|~$|\.(tmp|bak|part|crdownload)$`)
+
+// isTempPath reports whether a path looks like a temporary or copied file
+// rather than the one a user means to keep
+func isTempPath(p string) bool {
+	p = strings.ReplaceAll(p, "\\", "/")
+	if strings.HasPrefix(p, "/var/folders/") { // macOS per-user temp
+		return true
+	}
+	for _, seg := range strings.Split(path.Dir(p), "/") {
+		if tempDirs[strings.ToLower(seg)] {
+			return true
+		}
+	}
+	return copyNameRe.MatchString(path.Base(p))
+}
+
+// rankDuplicates orders copies of the same content from the best one to
+// keep to the most removable: files outside temp paths and not named like
+// copies first, then by position of their source in preferSources (unlisted
+// sources after listed ones), then active files before missing ones, then
+// the most recently verified, and finally the oldest record, as the
+// original. objs is sorted in place.
+func rankDuplicates(objs []*graph.GraphObject, preferSources []string) {
+	rank := make(map[string]int, len(preferSources))
+	for i, s := range preferSources {
+		if _, ok := rank[s]; !ok {
+			rank[s] = i
+		}
+	}
+	sourceRank := func(obj *graph.GraphObject) int {
+		s, _ := obj.Properties["source"].(string)
+		if r, ok := rank[s]; ok {
+			return r
+		}
+		return len(preferSources)
+	}
+	isTemp := func(obj *graph.GraphObject) bool {
+		p, _ := obj.Properties["path"].(string)
+		return isTempPath(p)
+	}
+	isActive := func(obj *graph.GraphObject) bool {
+		return obj.Status == nil || *obj.Status == "active"
+	}
+	verifiedAt := func(obj *graph.GraphObject) time.Time {
+		s, _ := obj.Properties["verified_at"].(string)
+		t, _ := time.Parse(time.RFC3339, s)
+		return t
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		a, b := objs[i], objs[j]
+		if ta, tb := isTemp(a), isTemp(b); ta != tb {
+			return !ta
+		}
+		if ra, rb := sourceRank(a), sourceRank(b); ra != rb {
+			return ra < rb
+		}
+		if aa, ab := isActive(a), isActive(b); aa != ab {
+			return aa
+		}
+		if va, vb := verifiedAt(a), verifiedAt(b); !va.Equal(vb) {
+			return va.After(vb)
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// dedupeGroup describes a duplicate group with a suggested keeper. With
+// apply set, the removable copies are removed from the index (the files
+// themselves are never touched) and the outcome is reported per copy.
+func (p *Provider) dedupeGroup(ctx context.Context, hash string, objs []*graph.GraphObject, preferSources []string, apply bool) map[string]interface{} {
+	rankDuplicates(objs, preferSources)
+
+	removable := make([]map[string]interface{}, 0, len(objs)-1)
+	var removed, failed int
+	for _, obj := range objs[1:] {
+		m := graphObjectToMap(obj)
+		if apply {
+			if err := p.client.Graph.DeleteObject(ctx, obj.ID); err != nil {
+				m["remove_error"] = err.Error()
+				failed++
+			} else {
+				m["removed"] = true
+				removed++
+			}
+		}
+		removable = append(removable, m)
+	}
+
+	group := map[string]interface{}{
+		"content_hash": hash,
+		"keeper":       graphObjectToMap(objs[0]),
+		"removable":    removable,
+		"count":        len(objs),
+	}
+	if apply {
+		group["removed"] = removed
+		group["failed"] = failed
+	}
+	return group
+}
@@ -0,0 +1,64 @@
+package files
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+func TestIsTempPath(t *testing.T) {
+	for p, want := range map[string]bool{
+		"/Users/me/docs/report.pdf":          false,
+		"/Users/me/Downloads/report.pdf":     false,
+		"/tmp/report.pdf":                    true,
+		"/Users/me/.Trash/report.pdf":        true,
+		"/var/folders/xy/T/report.pdf":       true,
+		"/Users/me/docs/report copy.pdf":     true,
+		"/Users/me/docs/report (2).pdf":      true,
+		"My Drive/Copy of report.pdf":        true,
+		"C:\\Users\\me\\Temp\\report.pdf":    true,
+		"/Users/me/docs/~$report.docx":       true,
+		"/Users/me/docs/report.pdf.part":     true,
+		"/Users/me/docs/copywriting/doc.pdf": false,
+	} {
+		if got := isTempPath(p); got != want {
+			t.Errorf("isTempPath(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestRankDuplicates(t *testing.T) {
+	missing := "missing"
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	file := func(id, source, path string, verified time.Time, created int) *graph.GraphObject {
+		props := map[string]interface{}{"source": source, "path": path}
+		if !verified.IsZero() {
+			props["verified_at"] = verified.Format(time.RFC3339)
+		}
+		return &graph.GraphObject{ID: id, Properties: props, CreatedAt: day(created)}
+	}
+
+	objs := []*graph.GraphObject{
+		file("tmp", "local", "/tmp/a.pdf", day(20), 1),
+		file("drive", "gdrive", "My Drive/a.pdf", day(10), 1),
+		file("stale", "local", "/docs/old/a.pdf", day(5), 1),
+		file("fresh", "local", "/docs/a.pdf", day(9), 3),
+		file("newer-record", "local", "/docs/b/a.pdf", time.Time{}, 4),
+		file("older-record", "local", "/docs/c/a.pdf", time.Time{}, 2),
+	}
+	objs[2].Status = &missing
+
+	rankDuplicates(objs, []string{"local", "gdrive"})
+	want := []string{"fresh", "older-record", "newer-record", "stale", "drive", "tmp"}
+	if got := sortedIDs(objs); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Without a source preference, the most recently verified copy wins
+	rankDuplicates(objs, nil)
+	if objs[0].ID != "drive" {
+		t.Errorf("expected drive as keeper without a source preference, got %v", sortedIDs(objs))
+	}
+}
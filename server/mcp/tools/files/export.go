@@ -0,0 +1,311 @@
+package files
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+
+	"github.com/diane-assistant/diane/internal/config"
+)
+
+const (
+	exportDefaultPageSize = 200
+	exportMaxPageSize     = 500
+)
+
+// exportPropertyColumns are the file properties written as their own CSV
+// columns, in the order file_registry_register documents them. Any other
+// properties end up JSON-encoded in the trailing "extra" column.
+var exportPropertyColumns = []string{
+	"source", "path", "filename", "extension", "size", "mime_type", "is_directory",
+	"content_hash", "partial_hash", "source_file_id", "category", "subcategory",
+	"created_at", "modified_at", "content_preview", "content_text",
+}
+
+// exportHeader returns the CSV header row
+func exportHeader() []string {
+	header := []string{"id", "key", "status", "version", "indexed_at", "tags"}
+	header = append(header, exportPropertyColumns...)
+	return append(header, "extra")
+}
+
+// exportRow flattens a file object into a CSV row matching exportHeader.
+// Tags are joined with ";" so they survive a spreadsheet round-trip.
+func exportRow(obj *graph.GraphObject, includeContent bool) []string {
+	var key, status string
+	if obj.Key != nil {
+		key = *obj.Key
+	}
+	if obj.Status != nil {
+		status = *obj.Status
+	}
+	tags := append([]string(nil), obj.Labels...)
+	sort.Strings(tags)
+
+	row := []string{
+		obj.ID,
+		key,
+		status,
+		strconv.Itoa(obj.Version),
+		obj.CreatedAt.UTC().Format(time.RFC3339),
+		strings.Join(tags, ";"),
+	}
+
+	known := make(map[string]bool, len(exportPropertyColumns))
+	for _, col := range exportPropertyColumns {
+		known[col] = true
+		if col == "content_text" && !includeContent {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, exportCell(obj.Properties[col]))
+	}
+
+	extra := make(map[string]interface{})
+	for k, v := range obj.Properties {
+		if !known[k] {
+			extra[k] = v
+		}
+	}
+	if len(extra) > 0 {
+		b, _ := json.Marshal(extra)
+		row = append(row, string(b))
+	} else {
+		row = append(row, "")
+	}
+	return row
+}
+
+// exportCell formats a single property value for CSV output
+func exportCell(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
+
+// exportRecordWriter writes exported files one at a time so pages never
+// have to be held in memory together.
+type exportRecordWriter interface {
+	write(obj *graph.GraphObject) error
+	close() error
+}
+
+type csvExportWriter struct {
+	w              *csv.Writer
+	includeContent bool
+}
+
+func newCSVExportWriter(out io.Writer, includeContent bool) (*csvExportWriter, error) {
+	w := csv.NewWriter(out)
+	if err := w.Write(exportHeader()); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: w, includeContent: includeContent}, nil
+}
+
+func (c *csvExportWriter) write(obj *graph.GraphObject) error {
+	return c.w.Write(exportRow(obj, c.includeContent))
+}
+
+func (c *csvExportWriter) close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonExportWriter struct {
+	out            io.Writer
+	includeContent bool
+	count          int
+}
+
+func newJSONExportWriter(out io.Writer, includeContent bool) (*jsonExportWriter, error) {
+	if _, err := io.WriteString(out, "["); err != nil {
+		return nil, err
+	}
+	return &jsonExportWriter{out: out, includeContent: includeContent}, nil
+}
+
+func (j *jsonExportWriter) write(obj *graph.GraphObject) error {
+	m := graphObjectToMap(obj)
+	if !j.includeContent {
+		if _, ok := obj.Properties["content_text"]; ok {
+			props := make(map[string]interface{}, len(obj.Properties))
+			for k, v := range obj.Properties {
+				if k != "content_text" {
+					props[k] = v
+				}
+			}
+			m["properties"] = props
+		}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	sep := "\n  "
+	if j.count > 0 {
+		sep = ",\n  "
+	}
+	j.count++
+	if _, err := io.WriteString(j.out, sep); err != nil {
+		return err
+	}
+	_, err = j.out.Write(b)
+	return err
+}
+
+func (j *jsonExportWriter) close() error {
+	end := "]\n"
+	if j.count > 0 {
+		end = "\n]\n"
+	}
+	_, err := io.WriteString(j.out, end)
+	return err
+}
+
+// defaultExportPath returns ~/.diane/exports/file-registry-<timestamp>.<ext>
+func defaultExportPath(format string, now time.Time) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	name := fmt.Sprintf("file-registry-%s.%s", now.Format("20060102-150405"), format)
+	return filepath.Join(home, config.DirName(), "exports", name), nil
+}
+
+func (p *Provider) export(args map[string]interface{}) (interface{}, error) {
+	format := strings.ToLower(getString(args, "format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return nil, fmt.Errorf("format must be 'csv' or 'json'")
+	}
+
+	sources := getStringArray(args, "sources")
+	categories := getStringArray(args, "categories")
+	tags := getStringArray(args, "tags")
+	status := getString(args, "status")
+	includeContent := false
+	if b := getBool(args, "include_content"); b != nil {
+		includeContent = *b
+	}
+	pageSize := getInt(args, "page_size", exportDefaultPageSize)
+	if pageSize <= 0 || pageSize > exportMaxPageSize {
+		pageSize = exportDefaultPageSize
+	}
+
+	outPath := getString(args, "output_path")
+	if outPath == "" {
+		var err error
+		if outPath, err = defaultExportPath(format, time.Now()); err != nil {
+			return nil, err
+		}
+	} else if strings.HasPrefix(outPath, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			outPath = filepath.Join(home, outPath[2:])
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	// Write to a temp file and rename, so a failed export never leaves a
+	// truncated file where a previous good one was.
+	tmp, err := os.CreateTemp(filepath.Dir(outPath), ".file-registry-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	var w exportRecordWriter
+	if format == "csv" {
+		w, err = newCSVExportWriter(tmp, includeContent)
+	} else {
+		w, err = newJSONExportWriter(tmp, includeContent)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	sourceSet := toSet(sources)
+	catSet := toSet(categories)
+	tagSet := toSet(tags)
+
+	ctx := context.Background()
+	exported, scanned, pages := 0, 0, 0
+	cursor := ""
+	for {
+		resp, err := p.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+			Type:   "file",
+			Labels: tags,
+			Status: status,
+			Limit:  pageSize,
+			Cursor: cursor,
+			Order:  "asc",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("export failed after %d files: %w", exported, err)
+		}
+		pages++
+
+		for _, obj := range resp.Items {
+			scanned++
+			if !matchesFilters(obj.Properties, sourceSet, catSet) || !matchesTagFilter(obj.Labels, tagSet) {
+				continue
+			}
+			if err := w.write(obj); err != nil {
+				return nil, fmt.Errorf("failed to write export: %w", err)
+			}
+			exported++
+		}
+
+		if resp.NextCursor == nil || *resp.NextCursor == "" || len(resp.Items) == 0 {
+			break
+		}
+		cursor = *resp.NextCursor
+	}
+
+	if err := w.close(); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), outPath); err != nil {
+		return nil, fmt.Errorf("failed to save export: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"path":     outPath,
+		"format":   format,
+		"exported": exported,
+		"scanned":  scanned,
+		"pages":    pages,
+	}
+	if info, err := os.Stat(outPath); err == nil {
+		result["bytes"] = info.Size()
+	}
+	return textContent(result), nil
+}
@@ -0,0 +1,122 @@
+package files
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+func testExportObject() *graph.GraphObject {
+	key := "local:/docs/report.pdf"
+	status := "active"
+	return &graph.GraphObject{
+		ID:        "obj-1",
+		Key:       &key,
+		Status:    &status,
+		Version:   2,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Labels:    []string{"work", "finance"},
+		Properties: map[string]interface{}{
+			"source":       "local",
+			"path":         "/docs/report.pdf",
+			"filename":     "report.pdf",
+			"size":         float64(1048576),
+			"is_directory": false,
+			"content_text": "quarterly numbers",
+			"owner":        "me",
+		},
+	}
+}
+
+func TestExportRow(t *testing.T) {
+	header := exportHeader()
+	row := exportRow(testExportObject(), false)
+	if len(row) != len(header) {
+		t.Fatalf("row has %d cells, header has %d", len(row), len(header))
+	}
+
+	cells := make(map[string]string, len(header))
+	for i, col := range header {
+		cells[col] = row[i]
+	}
+	want := map[string]string{
+		"id":           "obj-1",
+		"key":          "local:/docs/report.pdf",
+		"status":       "active",
+		"version":      "2",
+		"indexed_at":   "2026-03-01T12:00:00Z",
+		"tags":         "finance;work",
+		"size":         "1048576",
+		"is_directory": "false",
+		"content_text": "",
+		"extra":        `{"owner":"me"}`,
+	}
+	for col, v := range want {
+		if cells[col] != v {
+			t.Errorf("%s: expected %q, got %q", col, v, cells[col])
+		}
+	}
+
+	row = exportRow(testExportObject(), true)
+	for i, col := range header {
+		if col == "content_text" && row[i] != "quarterly numbers" {
+			t.Errorf("expected content_text with include_content, got %q", row[i])
+		}
+	}
+}
+
+func TestExportWriters(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newCSVExportWriter(&buf, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := w.write(testExportObject()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected header + 2 rows, got %d records", len(records))
+	}
+
+	for _, n := range []int{0, 2} {
+		buf.Reset()
+		j, err := newJSONExportWriter(&buf, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < n; i++ {
+			if err := j.write(testExportObject()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := j.close(); err != nil {
+			t.Fatal(err)
+		}
+		var items []map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+			t.Fatalf("invalid JSON for %d items: %v\n%s", n, err, buf.String())
+		}
+		if len(items) != n {
+			t.Errorf("expected %d items, got %d", n, len(items))
+		}
+		for _, item := range items {
+			props, _ := item["properties"].(map[string]interface{})
+			if _, ok := props["content_text"]; ok {
+				t.Error("expected content_text to be omitted by default")
+			}
+		}
+	}
+}
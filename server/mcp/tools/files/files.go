@@ -26,6 +26,7 @@ import (
 	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
 
 	"github.com/diane-assistant/diane/internal/emergent"
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 // Tool represents an MCP tool definition
@@ -125,6 +126,13 @@ func numberProperty(description string) map[string]interface{} {
 	}
 }
 
+func objectProperty(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": description,
+	}
+}
+
 // Tools returns the list of file management tools
 func (p *Provider) Tools() []Tool {
 	return []Tool{
@@ -169,18 +177,20 @@ Required: source, path, and content_hash. Other fields are optional but improve
 		},
 		{
 			Name:        "file_registry_search",
-			Description: "Search for files using full-text search across filenames, paths, and content. Supports filtering by source, category, tags, and type. Returns paginated results.",
+			Description: "Search for files using full-text search across filenames, paths, and content. Supports filtering by source, category, tags, annotations, and type, and sorting by size, dates, or filename. Returns paginated results.",
 			InputSchema: objectSchema(
 				map[string]interface{}{
-					"query":      stringProperty("Full-text search query (searches path, filename, content)"),
-					"sources":    arrayProperty("Filter by sources (e.g., ['local', 'gdrive'])", "string"),
-					"categories": arrayProperty("Filter by categories (e.g., ['document', 'image'])", "string"),
-					"tags":       arrayProperty("Filter by tags/labels (files must have ALL these tags)", "string"),
-					"any_tags":   arrayProperty("Filter by tags/labels (files must have ANY of these tags)", "string"),
-					"status":     stringProperty("Filter by status: 'active', 'missing', 'deleted'"),
-					"limit":      intProperty("Max results to return", 20),
-					"cursor":     stringProperty("Pagination cursor from previous search results"),
-					"order":      stringProperty("Sort direction: 'asc' or 'desc' (default: desc by updated_at)"),
+					"query":       stringProperty("Full-text search query (searches path, filename, content)"),
+					"sources":     arrayProperty("Filter by sources (e.g., ['local', 'gdrive'])", "string"),
+					"categories":  arrayProperty("Filter by categories (e.g., ['document', 'image'])", "string"),
+					"tags":        arrayProperty("Filter by tags/labels (files must have ALL these tags)", "string"),
+					"any_tags":    arrayProperty("Filter by tags/labels (files must have ANY of these tags)", "string"),
+					"annotations": objectProperty("Filter by annotations: files must have every key with an equal value (e.g., {\"project\": \"acme\"})"),
+					"status":      stringProperty("Filter by status: 'active', 'missing', 'deleted'"),
+					"limit":       intProperty("Max results to return", 20),
+					"cursor":      stringProperty("Pagination cursor from previous search results"),
+					"order_by":    stringProperty("Field to sort by: 'updated_at' (default), 'size', 'created_at', 'modified_at', or 'filename'. Sorting by anything but updated_at, or sorting query matches at all, scans up to 5000 matching files and does not return a cursor."),
+					"order":       stringProperty("Sort direction: 'asc' or 'desc' (default: desc)"),
 				},
 				nil,
 			),
@@ -227,6 +237,20 @@ Required: source, path, and content_hash. Other fields are optional but improve
 				[]string{"tags"},
 			),
 		},
+		{
+			Name:        "file_registry_annotate",
+			Description: "Set or remove key/value annotations on a file, e.g. project=acme or review_due=2025-07. Annotations are structured metadata kept separately from tags; filter on them with file_registry_search.",
+			InputSchema: objectSchema(
+				map[string]interface{}{
+					"id":     stringProperty("File ID (UUID)"),
+					"source": stringProperty("Source identifier (alternative to id)"),
+					"path":   stringProperty("Path within source (alternative to id)"),
+					"set":    objectProperty("Annotations to add or overwrite, as key/value pairs (values are stored as strings)"),
+					"remove": arrayProperty("Annotation keys to remove", "string"),
+				},
+				nil,
+			),
+		},
 		{
 			Name:        "file_registry_tags",
 			Description: "List all tags/labels in the file index with usage information.",
@@ -237,11 +261,14 @@ Required: source, path, and content_hash. Other fields are optional but improve
 		},
 		{
 			Name:        "file_registry_duplicates",
-			Description: "Find duplicate files based on content hash. Groups files with identical content across all sources.",
+			Description: "Find duplicate files based on content hash. Groups files with identical content across all sources. With suggest_keeper, each group gets a suggested copy to keep and the removable rest; with apply, the removable copies are also removed from the index (never from their source).",
 			InputSchema: objectSchema(
 				map[string]interface{}{
-					"content_hash": stringProperty("Find duplicates of a specific content hash"),
-					"limit":        intProperty("Max results to return", 20),
+					"content_hash":   stringProperty("Find duplicates of a specific content hash"),
+					"limit":          intProperty("Max results to return", 20),
+					"suggest_keeper": boolProperty("Rank each group's copies and suggest which to keep: copies outside temp/cache/trash paths and not named like copies first, then by prefer_sources, then active before missing, then most recently verified, then the oldest record"),
+					"prefer_sources": arrayProperty("Sources in order of preference for the keeper, e.g. ['local', 'gdrive']", "string"),
+					"apply":          boolProperty("Remove the non-keepers from the index (soft-delete; the files themselves are not touched). Implies suggest_keeper"),
 				},
 				nil,
 			),
@@ -405,6 +432,35 @@ Required: source, path, and content_hash. Other fields are optional but improve
 				[]string{"path"},
 			),
 		},
+		{
+			Name:        "file_registry_sync_status",
+			Description: "Check whether the indexed files of a local source still exist on disk, without re-registering anything. Reports counts of present, missing and modified (size or modification time changed) files. With mark_missing, files gone from disk are set to status 'missing', and missing files that have reappeared are set back to 'active'.",
+			InputSchema: objectSchema(
+				map[string]interface{}{
+					"source":       stringProperty("Source whose files live on this machine (default: 'local')"),
+					"path_prefix":  stringProperty("Only check files under this directory (e.g. /Users/me/Documents)"),
+					"mark_missing": boolProperty("Update the status of files that are missing from disk (or have reappeared). Default: false"),
+				},
+				nil,
+			),
+		},
+		{
+			Name:        "file_registry_export",
+			Description: "Export the file index (or a filtered subset) to a CSV or JSON file for offline analysis or backup. Includes all metadata columns and tags. Pages through the index internally, writing each page as it arrives, so large indexes are safe to export. Returns the path of the written file.",
+			InputSchema: objectSchema(
+				map[string]interface{}{
+					"format":          stringProperty("Export format: 'csv' (default, one row per file, tags joined with ';') or 'json' (array of file objects)"),
+					"output_path":     stringProperty("File to write (default: ~/.diane/exports/file-registry-<timestamp>.<format>)"),
+					"sources":         arrayProperty("Only export files from these sources (e.g., ['local', 'gdrive'])", "string"),
+					"categories":      arrayProperty("Only export files in these categories (e.g., ['document', 'image'])", "string"),
+					"tags":            arrayProperty("Only export files that have ALL these tags", "string"),
+					"status":          stringProperty("Only export files with this status: 'active', 'missing', 'deleted'"),
+					"include_content": boolProperty("Include the extracted content_text column, which can be large. Default: false"),
+					"page_size":       intProperty("Files fetched per page while exporting (max 500)", 200),
+				},
+				nil,
+			),
+		},
 	}
 }
 
@@ -412,18 +468,20 @@ Required: source, path, and content_hash. Other fields are optional but improve
 func (p *Provider) HasTool(name string) bool {
 	switch name {
 	case "file_registry_register", "file_registry_get", "file_registry_search", "file_registry_semantic_search",
-		"file_registry_tag", "file_registry_untag", "file_registry_tags", "file_registry_duplicates",
+		"file_registry_tag", "file_registry_untag", "file_registry_annotate", "file_registry_tags", "file_registry_duplicates",
 		"file_registry_remove", "file_registry_verify", "file_registry_stats", "file_registry_recent", "file_registry_similar",
 		"file_registry_batch_register", "file_registry_batch_get", "file_registry_batch_tag",
 		"file_registry_batch_untag", "file_registry_batch_remove",
-		"file_registry_crawl":
+		"file_registry_crawl", "file_registry_sync_status", "file_registry_export":
 		return true
 	}
 	return false
 }
 
 // Call executes a file management tool
-func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+func (p *Provider) Call(name string, args map[string]interface{}) (result interface{}, err error) {
+	defer func() { err = tools.WrapUnreachable(p.Name(), err) }()
+
 	switch name {
 	case "file_registry_register":
 		return p.register(args)
@@ -437,6 +495,8 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.tag(args)
 	case "file_registry_untag":
 		return p.untag(args)
+	case "file_registry_annotate":
+		return p.annotate(args)
 	case "file_registry_tags":
 		return p.tags(args)
 	case "file_registry_duplicates":
@@ -463,6 +523,10 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.batchRemove(args)
 	case "file_registry_crawl":
 		return p.crawl(args)
+	case "file_registry_sync_status":
+		return p.syncStatus(args)
+	case "file_registry_export":
+		return p.export(args)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
@@ -622,6 +686,16 @@ func (p *Provider) resolveFileID(ctx context.Context, id, source, path string) (
 // --- Tool Implementations ---
 
 func (p *Provider) register(args map[string]interface{}) (interface{}, error) {
+	result, err := p.RegisterFile(args)
+	if err != nil {
+		return nil, err
+	}
+	return textContent(result), nil
+}
+
+// RegisterFile registers a single file from file_registry_register
+// arguments and returns the registration summary
+func (p *Provider) RegisterFile(args map[string]interface{}) (map[string]interface{}, error) {
 	source := getString(args, "source")
 	path := getString(args, "path")
 	contentHash := getString(args, "content_hash")
@@ -699,12 +773,30 @@ func (p *Provider) register(args map[string]interface{}) (interface{}, error) {
 	}
 
 	slog.Info("File registered", "key", key, "id", obj.ID)
-	return textContent(map[string]interface{}{
+	return map[string]interface{}{
 		"status":  "registered",
 		"id":      obj.ID,
 		"key":     key,
 		"message": fmt.Sprintf("File registered: %s", filename),
-	}), nil
+	}, nil
+}
+
+// LocalPath returns where a registered file lives on this machine. Only
+// files from the local source have one.
+func (p *Provider) LocalPath(id string) (string, error) {
+	_, obj, err := p.resolveFileID(context.Background(), id, "", "")
+	if err != nil {
+		return "", err
+	}
+	source, _ := obj.Properties["source"].(string)
+	path, _ := obj.Properties["path"].(string)
+	if source != "local" {
+		return "", fmt.Errorf("file %s is in %s, not on this machine", id, source)
+	}
+	if path == "" {
+		return "", fmt.Errorf("file %s has no path", id)
+	}
+	return path, nil
 }
 
 func (p *Provider) get(args map[string]interface{}) (interface{}, error) {
@@ -730,15 +822,30 @@ func (p *Provider) search(args map[string]interface{}) (interface{}, error) {
 	sources := getStringArray(args, "sources")
 	categories := getStringArray(args, "categories")
 	tags := getStringArray(args, "tags")
+	annotations, err := getAnnotations(args, "annotations")
+	if err != nil {
+		return nil, err
+	}
 	status := getString(args, "status")
 	limit := getInt(args, "limit", 20)
 	cursor := getString(args, "cursor")
 	order := getString(args, "order")
+	orderBy := getString(args, "order_by")
+	if err := validateOrder(orderBy, order); err != nil {
+		return nil, err
+	}
+	customSort := orderBy != "" && orderBy != "updated_at"
+	if customSort && cursor != "" {
+		return nil, fmt.Errorf("cursor pagination is only supported when ordering by updated_at")
+	}
 
 	ctx := context.Background()
 
 	if query != "" {
 		// Full-text search via FTS
+		if orderBy != "" {
+			return p.sortedSearch(ctx, query, sources, categories, tags, annotations, status, orderBy, order, limit)
+		}
 		ftsOpts := &graph.FTSSearchOptions{
 			Query:  query,
 			Types:  []string{"file"},
@@ -751,7 +858,7 @@ func (p *Provider) search(args map[string]interface{}) (interface{}, error) {
 			return nil, fmt.Errorf("search failed: %w", err)
 		}
 
-		results := searchResultsToMaps(ftsResp.Data, sources, categories, tags)
+		results := searchResultsToMaps(ftsResp.Data, sources, categories, tags, annotations)
 		return textContent(map[string]interface{}{
 			"results": results,
 			"total":   len(results),
@@ -759,6 +866,9 @@ func (p *Provider) search(args map[string]interface{}) (interface{}, error) {
 	}
 
 	// No query — browse/filter mode
+	if customSort {
+		return p.sortedBrowse(ctx, sources, categories, tags, annotations, status, orderBy, order, limit)
+	}
 	listOpts := &graph.ListObjectsOptions{
 		Type:   "file",
 		Labels: tags,
@@ -772,7 +882,7 @@ func (p *Provider) search(args map[string]interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
-	results := filterObjectResults(resp.Items, sources, categories, tags)
+	results := filterObjectResults(resp.Items, sources, categories, tags, annotations)
 	var nextCursor *string
 	if resp.NextCursor != nil {
 		nextCursor = resp.NextCursor
@@ -788,8 +898,8 @@ func (p *Provider) search(args map[string]interface{}) (interface{}, error) {
 	return textContent(response), nil
 }
 
-// searchResultsToMaps converts search results to display maps with optional source/category/tag filtering.
-func searchResultsToMaps(items []*graph.SearchResultItem, sources, categories, tags []string) []map[string]interface{} {
+// searchResultsToMaps converts search results to display maps with optional source/category/tag/annotation filtering.
+func searchResultsToMaps(items []*graph.SearchResultItem, sources, categories, tags []string, annotations map[string]string) []map[string]interface{} {
 	sourceSet := toSet(sources)
 	catSet := toSet(categories)
 	tagSet := toSet(tags)
@@ -802,7 +912,7 @@ func searchResultsToMaps(items []*graph.SearchResultItem, sources, categories, t
 		if !matchesFilters(item.Object.Properties, sourceSet, catSet) {
 			continue
 		}
-		if !matchesTagFilter(item.Object.Labels, tagSet) {
+		if !matchesTagFilter(item.Object.Labels, tagSet) || !matchesAnnotationFilter(item.Object.Properties, annotations) {
 			continue
 		}
 		m := graphObjectToMap(item.Object)
@@ -812,8 +922,8 @@ func searchResultsToMaps(items []*graph.SearchResultItem, sources, categories, t
 	return results
 }
 
-// filterObjectResults filters GraphObjects by source, category, and tag properties.
-func filterObjectResults(items []*graph.GraphObject, sources, categories, tags []string) []map[string]interface{} {
+// filterObjectResults filters GraphObjects by source, category, tag, and annotation properties.
+func filterObjectResults(items []*graph.GraphObject, sources, categories, tags []string, annotations map[string]string) []map[string]interface{} {
 	sourceSet := toSet(sources)
 	catSet := toSet(categories)
 	tagSet := toSet(tags)
@@ -823,7 +933,7 @@ func filterObjectResults(items []*graph.GraphObject, sources, categories, tags [
 		if !matchesFilters(obj.Properties, sourceSet, catSet) {
 			continue
 		}
-		if !matchesTagFilter(obj.Labels, tagSet) {
+		if !matchesTagFilter(obj.Labels, tagSet) || !matchesAnnotationFilter(obj.Properties, annotations) {
 			continue
 		}
 		results = append(results, graphObjectToMap(obj))
@@ -897,7 +1007,7 @@ func (p *Provider) semanticSearch(args map[string]interface{}) (interface{}, err
 		return nil, fmt.Errorf("semantic search failed: %w", err)
 	}
 
-	results := searchResultsToMaps(resp.Data, sources, categories, tags)
+	results := searchResultsToMaps(resp.Data, sources, categories, tags, nil)
 	return textContent(map[string]interface{}{
 		"results": results,
 		"total":   len(results),
@@ -1005,6 +1115,11 @@ func (p *Provider) tags(args map[string]interface{}) (interface{}, error) {
 func (p *Provider) duplicates(args map[string]interface{}) (interface{}, error) {
 	contentHash := getString(args, "content_hash")
 	limit := getInt(args, "limit", 20)
+	preferSources := getStringArray(args, "prefer_sources")
+	apply := getBool(args, "apply")
+	applyRemovals := apply != nil && *apply
+	suggest := getBool(args, "suggest_keeper")
+	suggestKeeper := applyRemovals || (suggest != nil && *suggest)
 
 	ctx := context.Background()
 
@@ -1020,12 +1135,17 @@ func (p *Provider) duplicates(args map[string]interface{}) (interface{}, error)
 	if contentHash != "" {
 		// Find files with this specific content hash
 		var matches []map[string]interface{}
+		var matchObjs []*graph.GraphObject
 		for _, obj := range resp.Items {
 			hash, _ := obj.Properties["content_hash"].(string)
 			if hash == contentHash {
 				matches = append(matches, graphObjectToMap(obj))
+				matchObjs = append(matchObjs, obj)
 			}
 		}
+		if suggestKeeper && len(matchObjs) > 1 {
+			return textContent(p.dedupeGroup(ctx, contentHash, matchObjs, preferSources, applyRemovals)), nil
+		}
 		return textContent(map[string]interface{}{
 			"content_hash": contentHash,
 			"duplicates":   matches,
@@ -1045,7 +1165,9 @@ func (p *Provider) duplicates(args map[string]interface{}) (interface{}, error)
 
 	var duplicateGroups []map[string]interface{}
 	for hash, files := range groups {
-		if len(files) > 1 {
+		if len(files) > 1 && suggestKeeper {
+			duplicateGroups = append(duplicateGroups, p.dedupeGroup(ctx, hash, files, preferSources, applyRemovals))
+		} else if len(files) > 1 {
 			fileMaps := make([]map[string]interface{}, len(files))
 			for i, f := range files {
 				fileMaps[i] = graphObjectToMap(f)
@@ -1061,10 +1183,18 @@ func (p *Provider) duplicates(args map[string]interface{}) (interface{}, error)
 		}
 	}
 
-	return textContent(map[string]interface{}{
+	response := map[string]interface{}{
 		"duplicate_groups": duplicateGroups,
 		"total_groups":     len(duplicateGroups),
-	}), nil
+	}
+	if applyRemovals {
+		removed := 0
+		for _, g := range duplicateGroups {
+			removed += g["removed"].(int)
+		}
+		response["removed"] = removed
+	}
+	return textContent(response), nil
 }
 
 func (p *Provider) remove(args map[string]interface{}) (interface{}, error) {
@@ -1822,6 +1952,29 @@ func hashFile(path string) (fullHash, partialHash string, err error) {
 }
 
 func (p *Provider) crawl(args map[string]interface{}) (interface{}, error) {
+	result, err := p.crawlDirectory(args)
+	if err != nil {
+		return nil, err
+	}
+	return textContent(result), nil
+}
+
+// RegisterDirectory registers every file under dir, hidden ones included,
+// as file_registry_crawl does, and returns the crawl summary. Files already
+// in the index are skipped.
+func (p *Provider) RegisterDirectory(dir string, tags []string) (map[string]interface{}, error) {
+	args := map[string]interface{}{"path": dir, "include_hidden": true}
+	if len(tags) > 0 {
+		tagArgs := make([]interface{}, len(tags))
+		for i, t := range tags {
+			tagArgs[i] = t
+		}
+		args["tags"] = tagArgs
+	}
+	return p.crawlDirectory(args)
+}
+
+func (p *Provider) crawlDirectory(args map[string]interface{}) (map[string]interface{}, error) {
 	rootPath := getString(args, "path")
 	if rootPath == "" {
 		return nil, fmt.Errorf("path is required")
@@ -1961,14 +2114,14 @@ func (p *Provider) crawl(args map[string]interface{}) (interface{}, error) {
 			}
 			totalSize += f.Info.Size()
 		}
-		return textContent(map[string]interface{}{
+		return map[string]interface{}{
 			"status":       "dry_run",
 			"path":         absRoot,
 			"total_found":  len(files),
 			"total_size":   totalSize,
 			"by_category":  byCategory,
 			"by_extension": byExtension,
-		}), nil
+		}, nil
 	}
 
 	// Phase 2: Hash files and register in batches using concurrent workers
@@ -2103,5 +2256,5 @@ func (p *Provider) crawl(args map[string]interface{}) (interface{}, error) {
 		response["errors"] = errors
 	}
 
-	return textContent(response), nil
+	return response, nil
 }
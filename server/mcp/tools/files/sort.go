@@ -0,0 +1,238 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// Emergent only orders listings by updated_at, so any other sort key is
+// applied here after fetching. sortScanLimit bounds how much of the index a
+// single sorted browse will read.
+const (
+	sortScanLimit = 5000
+	sortPageSize  = 500
+)
+
+// searchOrderFields are the file_registry_search order_by keys. Except for
+// updated_at, which is the object's own timestamp, each is a property set by
+// file_registry_register.
+var searchOrderFields = map[string]bool{
+	"updated_at":  true,
+	"created_at":  true,
+	"modified_at": true,
+	"size":        true,
+	"filename":    true,
+}
+
+func validateOrder(orderBy, order string) error {
+	if orderBy != "" && !searchOrderFields[orderBy] {
+		return fmt.Errorf("invalid order_by %q (expected one of: size, created_at, modified_at, filename, updated_at)", orderBy)
+	}
+	if order != "" && order != "asc" && order != "desc" {
+		return fmt.Errorf("invalid order %q (expected 'asc' or 'desc')", order)
+	}
+	return nil
+}
+
+// sortObjects orders file objects by the given field. Files missing the
+// field always sort last, whichever the direction.
+func sortObjects(objs []*graph.GraphObject, orderBy, order string) {
+	desc := order != "asc"
+	sort.SliceStable(objs, func(i, j int) bool {
+		a, aok := sortValue(objs[i], orderBy)
+		b, bok := sortValue(objs[j], orderBy)
+		if !aok || !bok {
+			return aok && !bok
+		}
+		if desc {
+			return compareSortValues(b, a) < 0
+		}
+		return compareSortValues(a, b) < 0
+	})
+}
+
+// sortSearchResults reorders full-text results by a field instead of score
+func sortSearchResults(items []*graph.SearchResultItem, orderBy, order string) []*graph.SearchResultItem {
+	var objs []*graph.GraphObject
+	byObj := make(map[*graph.GraphObject]*graph.SearchResultItem, len(items))
+	for _, item := range items {
+		if item.Object != nil {
+			objs = append(objs, item.Object)
+			byObj[item.Object] = item
+		}
+	}
+	sortObjects(objs, orderBy, order)
+	sorted := make([]*graph.SearchResultItem, len(objs))
+	for i, obj := range objs {
+		sorted[i] = byObj[obj]
+	}
+	return sorted
+}
+
+// sortedBrowse pages through the index collecting files that match the
+// filters, then sorts them and returns the first limit.
+func (p *Provider) sortedBrowse(ctx context.Context, sources, categories, tags []string, annotations map[string]string, status, orderBy, order string, limit int) (interface{}, error) {
+	sourceSet := toSet(sources)
+	catSet := toSet(categories)
+	tagSet := toSet(tags)
+
+	var matched []*graph.GraphObject
+	scanned := 0
+	truncated := false
+	cursor := ""
+	for {
+		resp, err := p.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+			Type:   "file",
+			Labels: tags,
+			Status: status,
+			Limit:  sortPageSize,
+			Cursor: cursor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		for _, obj := range resp.Items {
+			scanned++
+			if matchesFilters(obj.Properties, sourceSet, catSet) && matchesTagFilter(obj.Labels, tagSet) &&
+				matchesAnnotationFilter(obj.Properties, annotations) {
+				matched = append(matched, obj)
+			}
+		}
+		if resp.NextCursor == nil || *resp.NextCursor == "" || len(resp.Items) == 0 {
+			break
+		}
+		if scanned >= sortScanLimit {
+			truncated = true
+			break
+		}
+		cursor = *resp.NextCursor
+	}
+
+	sortObjects(matched, orderBy, order)
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	results := make([]map[string]interface{}, 0, len(matched))
+	for _, obj := range matched {
+		results = append(results, graphObjectToMap(obj))
+	}
+	response := map[string]interface{}{
+		"results":  results,
+		"total":    len(results),
+		"order_by": orderBy,
+		"scanned":  scanned,
+	}
+	if truncated {
+		response["truncated"] = true
+		response["note"] = fmt.Sprintf("only the %d most recently updated files were sorted; narrow the filters for a complete ordering", scanned)
+	}
+	return textContent(response), nil
+}
+
+// sortedSearch pages through the full-text matches for query collecting
+// files that match the filters, then sorts them and returns the first
+// limit, so the order covers every match rather than just the best scoring.
+func (p *Provider) sortedSearch(ctx context.Context, query string, sources, categories, tags []string, annotations map[string]string, status, orderBy, order string, limit int) (interface{}, error) {
+	sourceSet := toSet(sources)
+	catSet := toSet(categories)
+	tagSet := toSet(tags)
+
+	var matched []*graph.SearchResultItem
+	scanned := 0
+	truncated := false
+	for {
+		resp, err := p.client.Graph.FTSSearch(ctx, &graph.FTSSearchOptions{
+			Query:  query,
+			Types:  []string{"file"},
+			Labels: tags,
+			Status: status,
+			Limit:  sortPageSize,
+			Offset: scanned,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		for _, item := range resp.Data {
+			scanned++
+			if item.Object != nil && matchesFilters(item.Object.Properties, sourceSet, catSet) &&
+				matchesTagFilter(item.Object.Labels, tagSet) && matchesAnnotationFilter(item.Object.Properties, annotations) {
+				matched = append(matched, item)
+			}
+		}
+		if !resp.HasMore || len(resp.Data) == 0 {
+			break
+		}
+		if scanned >= sortScanLimit {
+			truncated = true
+			break
+		}
+	}
+
+	matched = sortSearchResults(matched, orderBy, order)
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	results := searchResultsToMaps(matched, nil, nil, nil, nil)
+	response := map[string]interface{}{
+		"results":  results,
+		"total":    len(results),
+		"order_by": orderBy,
+		"scanned":  scanned,
+	}
+	if truncated {
+		response["truncated"] = true
+		response["note"] = fmt.Sprintf("only the %d best matching files were sorted; narrow the query or filters for a complete ordering", scanned)
+	}
+	return textContent(response), nil
+}
+
+// sortValue extracts a comparable value (float64, time.Time or string)
+func sortValue(obj *graph.GraphObject, field string) (interface{}, bool) {
+	switch field {
+	case "updated_at":
+		// Every update creates a new object version, so the version's
+		// creation time is when the file record was last updated.
+		return obj.CreatedAt, !obj.CreatedAt.IsZero()
+	case "size":
+		n, ok := obj.Properties["size"].(float64)
+		return n, ok
+	case "filename":
+		s, _ := obj.Properties["filename"].(string)
+		return strings.ToLower(s), s != ""
+	default:
+		s, _ := obj.Properties[field].(string)
+		if s == "" {
+			return nil, false
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, true
+		}
+		return s, true
+	}
+}
+
+func compareSortValues(a, b interface{}) int {
+	switch av := a.(type) {
+	case float64:
+		bv, _ := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return av.Compare(bv)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
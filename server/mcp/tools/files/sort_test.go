@@ -0,0 +1,119 @@
+package files
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/emergent-company/emergent/apps/server-go/pkg/sdk"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+func sortTestObjects() []*graph.GraphObject {
+	return []*graph.GraphObject{
+		{ID: "small", Properties: map[string]interface{}{"filename": "b.pdf", "size": float64(10), "modified_at": "2026-01-02T00:00:00Z"}},
+		{ID: "unsized", Properties: map[string]interface{}{"filename": "C.pdf"}},
+		{ID: "large", Properties: map[string]interface{}{"filename": "a.pdf", "size": float64(5000), "modified_at": "2025-06-01T00:00:00Z"}},
+		{ID: "medium", Properties: map[string]interface{}{"filename": "d.pdf", "size": float64(300), "modified_at": "2026-03-01T00:00:00Z"},
+			CreatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func sortedIDs(objs []*graph.GraphObject) []string {
+	ids := make([]string, len(objs))
+	for i, o := range objs {
+		ids[i] = o.ID
+	}
+	return ids
+}
+
+func TestSortObjects(t *testing.T) {
+	tests := []struct {
+		orderBy, order string
+		want           []string
+	}{
+		{"size", "", []string{"large", "medium", "small", "unsized"}},
+		{"size", "asc", []string{"small", "medium", "large", "unsized"}},
+		{"modified_at", "asc", []string{"large", "small", "medium", "unsized"}},
+		{"filename", "asc", []string{"large", "small", "unsized", "medium"}},
+		{"updated_at", "desc", []string{"medium", "small", "unsized", "large"}},
+	}
+	for _, tt := range tests {
+		objs := sortTestObjects()
+		sortObjects(objs, tt.orderBy, tt.order)
+		got := sortedIDs(objs)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s %s: expected %v, got %v", tt.orderBy, tt.order, tt.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	if err := validateOrder("size", "asc"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateOrder("", ""); err != nil {
+		t.Errorf("unexpected error for defaults: %v", err)
+	}
+	if err := validateOrder("owner", ""); err == nil {
+		t.Error("expected error for unknown order_by")
+	}
+	if err := validateOrder("size", "biggest"); err == nil {
+		t.Error("expected error for invalid order")
+	}
+}
+
+func TestSortedSearchCoversEveryMatch(t *testing.T) {
+	// Two pages of matches, the smallest file on the second
+	pages := map[string]string{
+		"":  `{"data":[{"score":0.9,"object":{"id":"large","properties":{"filename":"a.pdf","size":5000}}},{"score":0.8,"object":{"id":"medium","properties":{"filename":"b.pdf","size":300}}}],"hasMore":true}`,
+		"2": `{"data":[{"score":0.5,"object":{"id":"small","properties":{"filename":"c.pdf","size":10}}}],"hasMore":false}`,
+	}
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/objects/fts") {
+			http.NotFound(w, r)
+			return
+		}
+		queries = append(queries, r.URL.Query().Get("q"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[r.URL.Query().Get("offset")]))
+	}))
+	defer ts.Close()
+	client, err := sdk.New(sdk.Config{ServerURL: ts.URL, Auth: sdk.AuthConfig{Mode: "apikey", APIKey: "test"}, ProjectID: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Provider{client: client}
+
+	result, err := p.search(map[string]interface{}{"query": "invoice", "order_by": "size", "order": "asc", "limit": float64(2)})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	var got struct {
+		Results []struct {
+			ID string `json:"id"`
+		} `json:"results"`
+		Scanned int `json:"scanned"`
+	}
+	if err := json.Unmarshal([]byte(text), &got); err != nil {
+		t.Fatalf("unexpected result %s: %v", text, err)
+	}
+	var ids []string
+	for _, r := range got.Results {
+		ids = append(ids, r.ID)
+	}
+	if strings.Join(ids, ",") != "small,medium" || got.Scanned != 3 {
+		t.Errorf("got %v after scanning %d, want small,medium from all 3 matches", ids, got.Scanned)
+	}
+	if len(queries) != 2 || queries[1] != "invoice" {
+		t.Errorf("expected both pages to be searched for the query, got %q", queries)
+	}
+}
@@ -0,0 +1,179 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// syncStatusListLimit caps how many missing/modified paths are listed in the
+// response; the counts always cover every file checked.
+const syncStatusListLimit = 100
+
+// Disk states reported by file_registry_sync_status
+const (
+	diskPresent   = "present"
+	diskMissing   = "missing"
+	diskModified  = "modified"
+	diskUnchecked = "unchecked"
+)
+
+// checkOnDisk compares an indexed file's recorded metadata with what is on
+// disk now. A file is modified when its size or modification time no longer
+// match; files without an absolute path can't be checked.
+func checkOnDisk(props map[string]interface{}) string {
+	path, _ := props["path"].(string)
+	if path == "" || !filepath.IsAbs(path) {
+		return diskUnchecked
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return diskMissing
+	}
+	if err != nil {
+		return diskUnchecked
+	}
+	if info.IsDir() {
+		return diskPresent
+	}
+
+	if size, ok := props["size"].(float64); ok && int64(size) != info.Size() {
+		return diskModified
+	}
+	if s, ok := props["modified_at"].(string); ok {
+		if recorded, err := time.Parse(time.RFC3339, s); err == nil && !recorded.Equal(info.ModTime().UTC().Truncate(time.Second)) {
+			return diskModified
+		}
+	}
+	return diskPresent
+}
+
+func (p *Provider) syncStatus(args map[string]interface{}) (interface{}, error) {
+	source := getString(args, "source")
+	if source == "" {
+		source = "local"
+	}
+	prefix := getString(args, "path_prefix")
+	if strings.HasPrefix(prefix, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			prefix = filepath.Join(home, prefix[2:])
+		}
+	}
+	markMissing := false
+	if b := getBool(args, "mark_missing"); b != nil {
+		markMissing = *b
+	}
+
+	counts := map[string]int{diskPresent: 0, diskMissing: 0, diskModified: 0, diskUnchecked: 0}
+	var missing, modified []map[string]interface{}
+	marked, restored := 0, 0
+	var updateErrors []string
+
+	ctx := context.Background()
+	cursor := ""
+	for {
+		resp, err := p.client.Graph.ListObjects(ctx, &graph.ListObjectsOptions{
+			Type:   "file",
+			Limit:  sortPageSize,
+			Cursor: cursor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+
+		for _, obj := range resp.Items {
+			if src, _ := obj.Properties["source"].(string); src != source {
+				continue
+			}
+			path, _ := obj.Properties["path"].(string)
+			if prefix != "" && !strings.HasPrefix(path, prefix) {
+				continue
+			}
+			var status string
+			if obj.Status != nil {
+				status = *obj.Status
+			}
+
+			state := checkOnDisk(obj.Properties)
+			counts[state]++
+			entry := map[string]interface{}{"id": obj.ID, "path": path}
+
+			switch state {
+			case diskMissing:
+				if len(missing) < syncStatusListLimit {
+					missing = append(missing, entry)
+				}
+				if markMissing && status != "missing" {
+					if err := p.setFileStatus(ctx, obj.ID, "missing"); err != nil {
+						updateErrors = append(updateErrors, fmt.Sprintf("%s: %v", path, err))
+					} else {
+						marked++
+					}
+				}
+			case diskModified:
+				if len(modified) < syncStatusListLimit {
+					modified = append(modified, entry)
+				}
+			}
+
+			// A file previously marked missing that has come back is active again
+			if markMissing && status == "missing" && (state == diskPresent || state == diskModified) {
+				if err := p.setFileStatus(ctx, obj.ID, "active"); err != nil {
+					updateErrors = append(updateErrors, fmt.Sprintf("%s: %v", path, err))
+				} else {
+					restored++
+				}
+			}
+		}
+
+		if resp.NextCursor == nil || *resp.NextCursor == "" || len(resp.Items) == 0 {
+			break
+		}
+		cursor = *resp.NextCursor
+	}
+
+	checked := counts[diskPresent] + counts[diskMissing] + counts[diskModified] + counts[diskUnchecked]
+	result := map[string]interface{}{
+		"source":    source,
+		"checked":   checked,
+		"present":   counts[diskPresent],
+		"missing":   counts[diskMissing],
+		"modified":  counts[diskModified],
+		"unchecked": counts[diskUnchecked],
+	}
+	if prefix != "" {
+		result["path_prefix"] = prefix
+	}
+	if len(missing) > 0 {
+		result["missing_files"] = missing
+	}
+	if len(modified) > 0 {
+		result["modified_files"] = modified
+		result["hint"] = "Re-register modified files (or run file_registry_crawl) to refresh their hashes"
+	}
+	if markMissing {
+		result["marked_missing"] = marked
+		result["restored_active"] = restored
+		if len(updateErrors) > 0 {
+			result["errors"] = updateErrors
+		}
+		slog.Info("File registry sync status", "source", source, "checked", checked, "marked_missing", marked, "restored", restored)
+	}
+	return textContent(result), nil
+}
+
+func (p *Provider) setFileStatus(ctx context.Context, id, status string) error {
+	_, err := p.client.Graph.UpdateObject(ctx, id, &graph.UpdateObjectRequest{
+		Status: strPtr(status),
+	})
+	return err
+}
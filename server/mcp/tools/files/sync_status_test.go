@@ -0,0 +1,39 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		props map[string]interface{}
+		want  string
+	}{
+		{"unchanged", map[string]interface{}{"path": path, "size": float64(5), "modified_at": "2026-02-01T10:00:00Z"}, diskPresent},
+		{"no recorded metadata", map[string]interface{}{"path": path}, diskPresent},
+		{"size changed", map[string]interface{}{"path": path, "size": float64(3)}, diskModified},
+		{"mtime changed", map[string]interface{}{"path": path, "modified_at": "2025-12-01T10:00:00Z"}, diskModified},
+		{"deleted", map[string]interface{}{"path": filepath.Join(dir, "gone.txt")}, diskMissing},
+		{"relative path", map[string]interface{}{"path": "Documents/notes.txt"}, diskUnchecked},
+		{"directory", map[string]interface{}{"path": dir, "size": float64(1)}, diskPresent},
+	}
+	for _, tt := range tests {
+		if got := checkOnDisk(tt.props); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
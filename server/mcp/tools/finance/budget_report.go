@@ -0,0 +1,217 @@
+package finance
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Budget Report ---
+
+// budgetCategoryReport is one category's spend against its budget for a month.
+// Amounts are in currency units; Actual stores them as integer cents.
+type budgetCategoryReport struct {
+	Group     string  `json:"group"`
+	Category  string  `json:"category"`
+	Budgeted  float64 `json:"budgeted"`
+	Spent     float64 `json:"spent"`
+	Remaining float64 `json:"remaining"`
+	// PercentUsed is nil when nothing was budgeted for the category
+	PercentUsed *float64 `json:"percent_used"`
+	Flagged     bool     `json:"flagged"`
+}
+
+type budgetReportTotals struct {
+	Budgeted    float64  `json:"budgeted"`
+	Spent       float64  `json:"spent"`
+	Remaining   float64  `json:"remaining"`
+	PercentUsed *float64 `json:"percent_used"`
+}
+
+type budgetReport struct {
+	Month            string                 `json:"month"`
+	ThresholdPercent float64                `json:"threshold_percent"`
+	Categories       []budgetCategoryReport `json:"categories"`
+	Flagged          []string               `json:"flagged"`
+	Totals           budgetReportTotals     `json:"totals"`
+	// Summary is a one-line description an agent can post as-is
+	Summary string `json:"summary"`
+}
+
+func (p *Provider) financeBudgetReport(args map[string]interface{}) (interface{}, error) {
+	budgetID, err := getStringRequired(args, "budget_id")
+	if err != nil {
+		return nil, err
+	}
+	month := getString(args, "month")
+	if month == "" {
+		month = time.Now().Format("2006-01")
+	}
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return nil, fmt.Errorf("invalid month %q (expected YYYY-MM)", month)
+	}
+	threshold := getNumber(args, "threshold", 100)
+	if threshold <= 0 {
+		return nil, fmt.Errorf("threshold must be a positive percentage")
+	}
+
+	var filter []string
+	for _, name := range strings.Split(getString(args, "categories"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filter = append(filter, name)
+		}
+	}
+
+	result, err := runActualCLI("get-budget-month", budgetID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := buildBudgetReport(result, month, filter, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	output, _ := json.MarshalIndent(report, "", "  ")
+	return textContent(string(output)), nil
+}
+
+// buildBudgetReport turns the CLI's get-budget-month output (Actual's
+// getBudgetMonth shape: categoryGroups[].categories[] with integer-cent
+// budgeted/spent fields, spending negative) into a per-category report.
+// Income groups and hidden categories are skipped. A category is flagged
+// once its spend reaches threshold percent of its budget, or if it has
+// spending with no budget at all.
+func buildBudgetReport(raw interface{}, month string, filter []string, threshold float64) (*budgetReport, error) {
+	data, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected budget month format from Actual Budget CLI")
+	}
+	groups, _ := data["categoryGroups"].([]interface{})
+
+	wanted := make(map[string]bool, len(filter))
+	for _, name := range filter {
+		wanted[strings.ToLower(name)] = true
+	}
+
+	report := &budgetReport{
+		Month:            month,
+		ThresholdPercent: threshold,
+		Categories:       []budgetCategoryReport{},
+		Flagged:          []string{},
+	}
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok || isTrue(group["is_income"]) {
+			continue
+		}
+		groupName, _ := group["name"].(string)
+		categories, _ := group["categories"].([]interface{})
+
+		for _, c := range categories {
+			cat, ok := c.(map[string]interface{})
+			if !ok || isTrue(cat["is_income"]) || isTrue(cat["hidden"]) {
+				continue
+			}
+			name, _ := cat["name"].(string)
+			if len(wanted) > 0 && !wanted[strings.ToLower(name)] {
+				continue
+			}
+
+			budgeted := centsToUnits(cat["budgeted"])
+			spent := -centsToUnits(cat["spent"])
+			entry := budgetCategoryReport{
+				Group:       groupName,
+				Category:    name,
+				Budgeted:    budgeted,
+				Spent:       spent,
+				Remaining:   roundTo(budgeted-spent, 2),
+				PercentUsed: percentOf(spent, budgeted),
+			}
+			if entry.PercentUsed != nil {
+				entry.Flagged = *entry.PercentUsed >= threshold
+			} else {
+				entry.Flagged = spent > 0
+			}
+
+			report.Categories = append(report.Categories, entry)
+			report.Totals.Budgeted += budgeted
+			report.Totals.Spent += spent
+		}
+	}
+
+	// Most-used categories first, so the ones worth mentioning lead
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		return usedRank(report.Categories[i]) > usedRank(report.Categories[j])
+	})
+
+	var flaggedDesc []string
+	for _, c := range report.Categories {
+		if !c.Flagged {
+			continue
+		}
+		report.Flagged = append(report.Flagged, c.Category)
+		if c.PercentUsed != nil {
+			flaggedDesc = append(flaggedDesc, fmt.Sprintf("%s (%.0f%%)", c.Category, *c.PercentUsed))
+		} else {
+			flaggedDesc = append(flaggedDesc, fmt.Sprintf("%s (unbudgeted)", c.Category))
+		}
+	}
+
+	report.Totals.Budgeted = roundTo(report.Totals.Budgeted, 2)
+	report.Totals.Spent = roundTo(report.Totals.Spent, 2)
+	report.Totals.Remaining = roundTo(report.Totals.Budgeted-report.Totals.Spent, 2)
+	report.Totals.PercentUsed = percentOf(report.Totals.Spent, report.Totals.Budgeted)
+
+	if len(flaggedDesc) == 0 {
+		report.Summary = fmt.Sprintf("%s: all %d categories are below %.0f%% of budget", month, len(report.Categories), threshold)
+	} else {
+		report.Summary = fmt.Sprintf("%s: %d of %d categories at or above %.0f%% of budget: %s",
+			month, len(flaggedDesc), len(report.Categories), threshold, strings.Join(flaggedDesc, ", "))
+	}
+
+	return report, nil
+}
+
+// usedRank orders categories by percent used, with unbudgeted spending first
+func usedRank(c budgetCategoryReport) float64 {
+	if c.PercentUsed == nil {
+		if c.Spent > 0 {
+			return math.Inf(1)
+		}
+		return -1
+	}
+	return *c.PercentUsed
+}
+
+func centsToUnits(v interface{}) float64 {
+	n, _ := v.(float64)
+	return n / 100
+}
+
+func percentOf(part, whole float64) *float64 {
+	if whole <= 0 {
+		return nil
+	}
+	pct := roundTo(part/whole*100, 1)
+	return &pct
+}
+
+func roundTo(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
+
+// isTrue accepts both booleans and Actual's 0/1 integer flags
+func isTrue(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case float64:
+		return b != 0
+	}
+	return false
+}
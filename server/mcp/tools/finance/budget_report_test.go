@@ -0,0 +1,86 @@
+package finance
+
+import (
+	"strings"
+	"testing"
+)
+
+func testBudgetMonth() interface{} {
+	return map[string]interface{}{
+		"month": "2026-03",
+		"categoryGroups": []interface{}{
+			map[string]interface{}{
+				"name": "Everyday",
+				"categories": []interface{}{
+					map[string]interface{}{"name": "Groceries", "budgeted": float64(50000), "spent": float64(-42500)},
+					map[string]interface{}{"name": "Dining", "budgeted": float64(10000), "spent": float64(-12000)},
+					map[string]interface{}{"name": "Gifts", "budgeted": float64(0), "spent": float64(-2000)},
+					map[string]interface{}{"name": "Old", "budgeted": float64(5000), "spent": float64(-5000), "hidden": true},
+				},
+			},
+			map[string]interface{}{
+				"name":      "Income",
+				"is_income": true,
+				"categories": []interface{}{
+					map[string]interface{}{"name": "Salary", "budgeted": float64(0), "spent": float64(300000)},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildBudgetReport(t *testing.T) {
+	report, err := buildBudgetReport(testBudgetMonth(), "2026-03", nil, 80)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Categories) != 3 {
+		t.Fatalf("expected 3 expense categories (hidden and income skipped), got %d", len(report.Categories))
+	}
+
+	// Sorted by usage, unbudgeted spending first
+	order := []string{"Gifts", "Dining", "Groceries"}
+	for i, name := range order {
+		if report.Categories[i].Category != name {
+			t.Errorf("position %d: expected %s, got %s", i, name, report.Categories[i].Category)
+		}
+	}
+
+	groceries := report.Categories[2]
+	if groceries.Budgeted != 500 || groceries.Spent != 425 || groceries.Remaining != 75 {
+		t.Errorf("unexpected groceries amounts: %+v", groceries)
+	}
+	if groceries.PercentUsed == nil || *groceries.PercentUsed != 85 || !groceries.Flagged {
+		t.Errorf("expected groceries flagged at 85%%, got %+v", groceries)
+	}
+	if report.Categories[0].PercentUsed != nil || !report.Categories[0].Flagged {
+		t.Errorf("expected unbudgeted spending to be flagged with no percent, got %+v", report.Categories[0])
+	}
+
+	if len(report.Flagged) != 3 {
+		t.Errorf("expected 3 flagged categories, got %v", report.Flagged)
+	}
+	if report.Totals.Budgeted != 600 || report.Totals.Spent != 565 {
+		t.Errorf("unexpected totals: %+v", report.Totals)
+	}
+	if !strings.Contains(report.Summary, "Groceries (85%)") {
+		t.Errorf("expected summary to mention groceries, got %q", report.Summary)
+	}
+}
+
+func TestBuildBudgetReport_CategoryFilter(t *testing.T) {
+	report, err := buildBudgetReport(testBudgetMonth(), "2026-03", []string{"groceries"}, 90)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Categories) != 1 || report.Categories[0].Category != "Groceries" {
+		t.Fatalf("expected only Groceries, got %+v", report.Categories)
+	}
+	if report.Categories[0].Flagged || len(report.Flagged) != 0 {
+		t.Errorf("expected groceries below 90%% threshold, got %+v", report.Categories[0])
+	}
+	if !strings.Contains(report.Summary, "all 1 categories are below 90%") {
+		t.Errorf("unexpected summary: %q", report.Summary)
+	}
+}
@@ -19,6 +19,9 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 // --- Configuration ---
@@ -279,6 +282,8 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+	// CacheTTL, if set, lets identical calls within it reuse the result
+	CacheTTL time.Duration `json:"-"`
 }
 
 // Provider implements ToolProvider for finance tools
@@ -305,7 +310,7 @@ func (p *Provider) CheckDependencies() error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	secretsDir = filepath.Join(home, ".diane", "secrets")
+	secretsDir = filepath.Join(home, config.DirName(), "secrets")
 
 	// Check Enable Banking
 	ebConfigPath := filepath.Join(secretsDir, "enablebanking-config.json")
@@ -366,11 +371,11 @@ func (p *Provider) CheckDependencies() error {
 
 // Tools returns all finance tools
 func (p *Provider) Tools() []Tool {
-	var tools []Tool
+	var list []Tool
 
 	// Enable Banking tools
 	if p.enableBankingAvailable {
-		tools = append(tools, []Tool{
+		list = append(list, []Tool{
 			{
 				Name:        "enablebanking_list_banks",
 				Description: "List available banks (ASPSPs) in Enable Banking for a specific country. Returns bank names, countries, and supported services (AIS/PIS).",
@@ -430,7 +435,7 @@ func (p *Provider) Tools() []Tool {
 
 	// Actual Budget tools
 	if p.actualBudgetAvailable {
-		tools = append(tools, []Tool{
+		list = append(list, []Tool{
 			{
 				Name:        "actualbudget_list_budgets",
 				Description: "List all budget files available on the Actual Budget server",
@@ -569,6 +574,20 @@ func (p *Provider) Tools() []Tool {
 					[]string{"budget_id"},
 				),
 			},
+			{
+				Name:        "finance_budget_report",
+				CacheTTL:    5 * time.Minute,
+				Description: "Summarize spend vs budget by category for a month: budgeted, spent, remaining and percent used, flagging categories at or above a threshold",
+				InputSchema: objectSchema(
+					map[string]interface{}{
+						"budget_id":  stringProperty("The sync ID (groupId) of the budget file"),
+						"month":      stringProperty("Month in YYYY-MM format (default: current month)"),
+						"categories": stringProperty("Comma-separated category names to include (default: all expense categories)"),
+						"threshold":  numberProperty("Flag categories whose spend reaches this percent of budget (default: 100)"),
+					},
+					[]string{"budget_id"},
+				),
+			},
 			{
 				Name:        "actualbudget_get_rules",
 				Description: "Get all rules from an Actual Budget file",
@@ -627,7 +646,7 @@ func (p *Provider) Tools() []Tool {
 
 	// Bank Sync tools (requires both Enable Banking and Actual Budget)
 	if p.bankSyncAvailable {
-		tools = append(tools, []Tool{
+		list = append(list, []Tool{
 			{
 				Name:        "banksync_list_mappings",
 				Description: "List all bank account to Actual Budget account mappings",
@@ -648,25 +667,32 @@ func (p *Provider) Tools() []Tool {
 			},
 			{
 				Name:        "banksync_sync_bank_to_actual",
-				Description: "Sync transactions from Enable Banking to Actual Budget for a specific account mapping",
+				Description: "Sync transactions from Enable Banking to Actual Budget for a specific account mapping. Resumes from the account's last sync, so repeated calls only import new transactions",
 				InputSchema: objectSchema(
 					map[string]interface{}{
 						"bank_account_id": stringProperty("The Enable Banking account ID to sync (must have a configured mapping)"),
-						"days_back":       numberProperty("Number of days back to fetch transactions (default: 30)"),
+						"days_back":       numberProperty("Fetch this many days back instead of resuming from the last sync (default for the first sync: 30)"),
+						"full_resync":     boolProperty("Discard the sync cursor and re-sync the whole days_back range"),
 					},
 					[]string{"bank_account_id"},
 				),
 			},
 			{
 				Name:        "banksync_sync_all_accounts",
-				Description: "Sync transactions from all enabled bank accounts to Actual Budget",
+				Description: "Sync transactions from all enabled bank accounts to Actual Budget, resuming each from its last sync",
 				InputSchema: objectSchema(
 					map[string]interface{}{
-						"days_back": numberProperty("Number of days back to fetch transactions (default: 30)"),
+						"days_back":   numberProperty("Fetch this many days back instead of resuming from the last sync (default for the first sync: 30)"),
+						"full_resync": boolProperty("Discard the sync cursors and re-sync the whole days_back range"),
 					},
 					nil,
 				),
 			},
+			{
+				Name:        "finance_sync_status",
+				Description: "Show each mapped bank account's last sync time, sync cursor and imported transaction count",
+				InputSchema: objectSchema(map[string]interface{}{}, nil),
+			},
 			{
 				Name:        "banksync_setup_list_actual_accounts",
 				Description: "Helper tool to list all Actual Budget accounts for easy mapping setup",
@@ -680,7 +706,22 @@ func (p *Provider) Tools() []Tool {
 		}...)
 	}
 
-	return tools
+	for _, tool := range list {
+		if tool.CacheTTL > 0 {
+			tools.AddNoCacheArg(tool.InputSchema)
+		}
+	}
+	return list
+}
+
+// CacheTTL implements tools.CacheableProvider
+func (p *Provider) CacheTTL(name string) time.Duration {
+	for _, tool := range p.Tools() {
+		if tool.Name == name {
+			return tool.CacheTTL
+		}
+	}
+	return 0
 }
 
 // HasTool checks if a tool name belongs to this provider
@@ -694,7 +735,9 @@ func (p *Provider) HasTool(name string) bool {
 }
 
 // Call executes a tool by name
-func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+func (p *Provider) Call(name string, args map[string]interface{}) (result interface{}, err error) {
+	defer func() { err = tools.WrapUnreachable(p.Name(), err) }()
+
 	switch name {
 	// Enable Banking tools
 	case "enablebanking_list_banks":
@@ -735,6 +778,8 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.abGetAccountBalance(args)
 	case "actualbudget_sync_budget":
 		return p.abSyncBudget(args)
+	case "finance_budget_report":
+		return p.financeBudgetReport(args)
 	case "actualbudget_get_rules":
 		return p.abGetRules(args)
 	case "actualbudget_create_rule":
@@ -757,6 +802,8 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.bsSyncAllAccounts(args)
 	case "banksync_setup_list_actual_accounts":
 		return p.bsSetupListActualAccounts(args)
+	case "finance_sync_status":
+		return p.bsSyncStatus(args)
 
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
@@ -1331,6 +1378,23 @@ func (p *Provider) bsSyncBankToActual(args map[string]interface{}) (interface{},
 		return nil, err
 	}
 	daysBack := int(getNumber(args, "days_back", 30))
+	_, explicitRange := args["days_back"]
+	fullResync, _ := getBool(args, "full_resync")
+
+	syncStateMu.Lock()
+	defer syncStateMu.Unlock()
+
+	state, err := loadSyncState()
+	if err != nil {
+		return nil, err
+	}
+	acctState := state.Accounts[bankAccountID]
+	if acctState == nil || fullResync {
+		acctState = &accountSyncState{}
+		if prev := state.Accounts[bankAccountID]; prev != nil {
+			acctState.TransactionCount = prev.TransactionCount
+		}
+	}
 
 	config, err := loadBankMappingConfig()
 	if err != nil {
@@ -1355,11 +1419,16 @@ func (p *Provider) bsSyncBankToActual(args map[string]interface{}) (interface{},
 		return nil, fmt.Errorf("mapping not configured. Please set actual_account_id using update_mapping")
 	}
 
-	// Calculate date range
+	// Calculate date range: resume from a few days before the sync cursor
+	// unless an explicit days_back was given or there is no cursor yet
 	endDate := time.Now()
 	startDate := endDate.AddDate(0, 0, -daysBack)
 	dateFrom := startDate.Format("2006-01-02")
 	dateTo := endDate.Format("2006-01-02")
+	incremental := !explicitRange && acctState.Cursor != ""
+	if incremental {
+		dateFrom = acctState.fetchFrom()
+	}
 
 	// Fetch transactions from Enable Banking
 	endpoint := fmt.Sprintf("/accounts/%s/transactions?date_from=%s&date_to=%s", bankAccountID, dateFrom, dateTo)
@@ -1370,18 +1439,28 @@ func (p *Provider) bsSyncBankToActual(args map[string]interface{}) (interface{},
 
 	transactions, ok := result["transactions"].([]interface{})
 	if !ok || len(transactions) == 0 {
+		acctState.LastSyncedAt = time.Now()
+		acctState.LastImported = 0
+		state.Accounts[bankAccountID] = acctState
+		if err := saveSyncState(state); err != nil {
+			return nil, err
+		}
+
 		response := map[string]interface{}{
 			"message":        "No transactions to sync",
 			"bank_account":   mapping.BankAccountName,
 			"actual_account": mapping.ActualAccountName,
 			"date_range":     map[string]string{"from": dateFrom, "to": dateTo},
+			"incremental":    incremental,
 		}
 		output, _ := json.MarshalIndent(response, "", "  ")
 		return textContent(string(output)), nil
 	}
 
-	// Transform to Actual Budget format
+	// Transform to Actual Budget format, skipping anything a previous sync
+	// already imported
 	var actualTxns []map[string]interface{}
+	var importedDates, importedIDs []string
 	for _, t := range transactions {
 		tx := t.(map[string]interface{})
 
@@ -1431,6 +1510,9 @@ func (p *Provider) bsSyncBankToActual(args map[string]interface{}) (interface{},
 		if txID == "" {
 			txID = fmt.Sprintf("%s-%f-%s", date, amount, payeeName)
 		}
+		if !acctState.isNew(date, txID) {
+			continue
+		}
 
 		// Get status
 		status, _ := tx["status"].(string)
@@ -1444,13 +1526,24 @@ func (p *Provider) bsSyncBankToActual(args map[string]interface{}) (interface{},
 			"imported_id": txID,
 			"cleared":     cleared,
 		})
+		importedDates = append(importedDates, date)
+		importedIDs = append(importedIDs, txID)
 	}
 
 	// Import to Actual Budget
-	txnsJSON, _ := json.Marshal(actualTxns)
-	_, err = runActualCLI("import-transactions", mapping.ActualBudgetID, mapping.ActualAccountID, string(txnsJSON))
-	if err != nil {
-		return nil, fmt.Errorf("failed to import transactions: %w", err)
+	if len(actualTxns) > 0 {
+		txnsJSON, _ := json.Marshal(actualTxns)
+		_, err = runActualCLI("import-transactions", mapping.ActualBudgetID, mapping.ActualAccountID, string(txnsJSON))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import transactions: %w", err)
+		}
+	}
+
+	// Only advance the cursor once the import has succeeded
+	acctState.advance(importedDates, importedIDs, time.Now())
+	state.Accounts[bankAccountID] = acctState
+	if err := saveSyncState(state); err != nil {
+		return nil, fmt.Errorf("transactions imported but failed to save sync cursor: %w", err)
 	}
 
 	response := map[string]interface{}{
@@ -1460,14 +1553,15 @@ func (p *Provider) bsSyncBankToActual(args map[string]interface{}) (interface{},
 		"date_range":     map[string]string{"from": dateFrom, "to": dateTo},
 		"fetched":        len(transactions),
 		"imported":       len(actualTxns),
+		"skipped":        len(transactions) - len(actualTxns),
+		"incremental":    incremental,
+		"cursor":         acctState.Cursor,
 	}
 	output, _ := json.MarshalIndent(response, "", "  ")
 	return textContent(string(output)), nil
 }
 
 func (p *Provider) bsSyncAllAccounts(args map[string]interface{}) (interface{}, error) {
-	daysBack := int(getNumber(args, "days_back", 30))
-
 	config, err := loadBankMappingConfig()
 	if err != nil {
 		return nil, err
@@ -1490,10 +1584,14 @@ func (p *Provider) bsSyncAllAccounts(args map[string]interface{}) (interface{},
 
 	var results []map[string]interface{}
 	for _, m := range enabledMappings {
-		result, err := p.bsSyncBankToActual(map[string]interface{}{
-			"bank_account_id": m.BankAccountID,
-			"days_back":       float64(daysBack),
-		})
+		syncArgs := map[string]interface{}{"bank_account_id": m.BankAccountID}
+		if daysBack, ok := args["days_back"]; ok {
+			syncArgs["days_back"] = daysBack
+		}
+		if fullResync, ok := args["full_resync"]; ok {
+			syncArgs["full_resync"] = fullResync
+		}
+		result, err := p.bsSyncBankToActual(syncArgs)
 		if err != nil {
 			results = append(results, map[string]interface{}{
 				"mapping": m.Name,
@@ -1525,6 +1623,51 @@ func (p *Provider) bsSyncAllAccounts(args map[string]interface{}) (interface{},
 	return textContent(string(output)), nil
 }
 
+func (p *Provider) bsSyncStatus(args map[string]interface{}) (interface{}, error) {
+	config, err := loadBankMappingConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	syncStateMu.Lock()
+	state, err := loadSyncState()
+	syncStateMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []map[string]interface{}
+	for _, m := range config.Mappings {
+		entry := map[string]interface{}{
+			"mapping":           m.Name,
+			"bank_account_id":   m.BankAccountID,
+			"actual_account":    m.ActualAccountName,
+			"enabled":           m.Enabled,
+			"last_synced_at":    nil,
+			"cursor":            nil,
+			"transaction_count": 0,
+			"last_imported":     0,
+		}
+		if s := state.Accounts[m.BankAccountID]; s != nil {
+			if !s.LastSyncedAt.IsZero() {
+				entry["last_synced_at"] = s.LastSyncedAt.Format(time.RFC3339)
+			}
+			if s.Cursor != "" {
+				entry["cursor"] = s.Cursor
+			}
+			entry["transaction_count"] = s.TransactionCount
+			entry["last_imported"] = s.LastImported
+		}
+		accounts = append(accounts, entry)
+	}
+
+	response := map[string]interface{}{
+		"accounts": accounts,
+	}
+	output, _ := json.MarshalIndent(response, "", "  ")
+	return textContent(string(output)), nil
+}
+
 func (p *Provider) bsSetupListActualAccounts(args map[string]interface{}) (interface{}, error) {
 	budgetID := getString(args, "budget_id")
 	if budgetID == "" {
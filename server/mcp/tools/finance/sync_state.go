@@ -0,0 +1,156 @@
+package finance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// --- Incremental Bank Sync State ---
+
+// accountSyncState tracks how far a bank account has been synced into Actual
+// Budget, so repeated syncs only fetch and import new transactions.
+type accountSyncState struct {
+	LastSyncedAt time.Time `json:"last_synced_at"`
+	// Cursor is the latest transaction date (YYYY-MM-DD) imported so far.
+	// The next sync fetches from syncOverlapDays before it, since banks often
+	// post transactions a few days after the date they carry.
+	Cursor string `json:"cursor,omitempty"`
+	// RecentIDs are the imported_ids already imported, by date, for the
+	// dates from TrackedFrom on; those dates are re-fetched and the IDs
+	// skipped. Anything dated before TrackedFrom counts as imported.
+	RecentIDs   map[string][]string `json:"recent_ids,omitempty"`
+	TrackedFrom string              `json:"tracked_from,omitempty"`
+	// CursorIDs is the imported_ids for the Cursor date alone, as kept by
+	// older versions; loadSyncState moves them into RecentIDs.
+	CursorIDs        []string `json:"cursor_ids,omitempty"`
+	TransactionCount int      `json:"transaction_count"`
+	LastImported     int      `json:"last_imported"`
+}
+
+// syncOverlapDays is how many days before the cursor an incremental sync
+// fetches again, to catch transactions posted late with an earlier date
+const syncOverlapDays = 7
+
+type bankSyncState struct {
+	Accounts map[string]*accountSyncState `json:"accounts"`
+}
+
+// syncStateMu serializes syncs so concurrent calls can't both import the
+// same range before either has saved its cursor.
+var syncStateMu sync.Mutex
+
+func syncStatePath() string {
+	return filepath.Join(secretsDir, "bank-sync-state.json")
+}
+
+func loadSyncState() (*bankSyncState, error) {
+	state := &bankSyncState{Accounts: make(map[string]*accountSyncState)}
+	data, err := os.ReadFile(syncStatePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bank sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse bank sync state: %w", err)
+	}
+	if state.Accounts == nil {
+		state.Accounts = make(map[string]*accountSyncState)
+	}
+	for _, acct := range state.Accounts {
+		// Older state only knows the IDs for the cursor date, and imported
+		// nothing before it again
+		if acct.RecentIDs == nil && acct.Cursor != "" {
+			acct.RecentIDs = map[string][]string{acct.Cursor: acct.CursorIDs}
+			acct.TrackedFrom = acct.Cursor
+		}
+		acct.CursorIDs = nil
+	}
+	return state, nil
+}
+
+func saveSyncState(state *bankSyncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+	// Write to a temp file and rename so a crash can't leave a truncated cursor
+	tmp := syncStatePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, syncStatePath())
+}
+
+// fetchFrom returns the date an incremental sync fetches from: the overlap
+// window before the cursor, but not before the dates whose IDs are known.
+func (s *accountSyncState) fetchFrom() string {
+	from := shiftDate(s.Cursor, -syncOverlapDays)
+	if from < s.TrackedFrom {
+		return s.TrackedFrom
+	}
+	return from
+}
+
+// isNew reports whether a transaction dated date with imported_id id hasn't
+// been imported by a previous sync.
+func (s *accountSyncState) isNew(date, id string) bool {
+	if s == nil || s.Cursor == "" {
+		return true
+	}
+	if date < s.TrackedFrom {
+		return false
+	}
+	for _, seen := range s.RecentIDs[date] {
+		if seen == id {
+			return false
+		}
+	}
+	return true
+}
+
+// advance moves the cursor past the transactions just imported and records
+// their IDs, forgetting those that have left the overlap window. dates and
+// ids are parallel slices describing each imported transaction.
+func (s *accountSyncState) advance(dates, ids []string, syncedAt time.Time) {
+	if s.RecentIDs == nil {
+		s.RecentIDs = make(map[string][]string)
+	}
+	for i, date := range dates {
+		if date > s.Cursor {
+			s.Cursor = date
+		}
+		s.RecentIDs[date] = append(s.RecentIDs[date], ids[i])
+	}
+	if s.Cursor != "" {
+		if from := shiftDate(s.Cursor, -syncOverlapDays); from > s.TrackedFrom {
+			s.TrackedFrom = from
+		}
+	}
+	for date, seen := range s.RecentIDs {
+		if date < s.TrackedFrom {
+			delete(s.RecentIDs, date)
+			continue
+		}
+		sort.Strings(seen)
+	}
+	s.LastSyncedAt = syncedAt
+	s.LastImported = len(ids)
+	s.TransactionCount += len(ids)
+}
+
+// shiftDate moves a YYYY-MM-DD date by days, returning it unchanged if it
+// doesn't parse
+func shiftDate(date string, days int) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(0, 0, days).Format("2006-01-02")
+}
@@ -0,0 +1,141 @@
+package finance
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAccountSyncState_IsNew(t *testing.T) {
+	var empty *accountSyncState
+	if !empty.isNew("2026-01-01", "a") {
+		t.Error("expected every transaction to be new without a cursor")
+	}
+
+	s := &accountSyncState{
+		Cursor:      "2026-03-10",
+		TrackedFrom: "2026-03-03",
+		RecentIDs:   map[string][]string{"2026-03-10": {"seen"}, "2026-03-08": {"earlier"}},
+	}
+	tests := []struct {
+		date, id string
+		want     bool
+	}{
+		{"2026-03-02", "old", false},
+		{"2026-03-10", "seen", false},
+		{"2026-03-10", "late-posted", true},
+		{"2026-03-08", "earlier", false},
+		{"2026-03-08", "booked-late", true},
+		{"2026-03-11", "seen", true},
+	}
+	for _, tt := range tests {
+		if got := s.isNew(tt.date, tt.id); got != tt.want {
+			t.Errorf("isNew(%s, %s) = %v, want %v", tt.date, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestAccountSyncState_Advance(t *testing.T) {
+	s := &accountSyncState{
+		Cursor:           "2026-03-10",
+		TrackedFrom:      "2026-03-03",
+		RecentIDs:        map[string][]string{"2026-03-10": {"a"}, "2026-03-04": {"old"}},
+		TransactionCount: 5,
+	}
+	now := time.Now()
+
+	s.advance([]string{"2026-03-10", "2026-03-12", "2026-03-11", "2026-03-12"}, []string{"b", "e", "c", "d"}, now)
+
+	if s.Cursor != "2026-03-12" || s.TrackedFrom != "2026-03-05" {
+		t.Errorf("expected cursor 2026-03-12 tracked from 2026-03-05, got %s from %s", s.Cursor, s.TrackedFrom)
+	}
+	if ids := s.RecentIDs["2026-03-12"]; len(ids) != 2 || ids[0] != "d" || ids[1] != "e" {
+		t.Errorf("expected IDs [d e] for the cursor date, got %v", ids)
+	}
+	if _, ok := s.RecentIDs["2026-03-04"]; ok {
+		t.Error("expected IDs that left the overlap window to be dropped")
+	}
+	if s.TransactionCount != 9 || s.LastImported != 4 {
+		t.Errorf("expected count 9 / last 4, got %d / %d", s.TransactionCount, s.LastImported)
+	}
+	if !s.LastSyncedAt.Equal(now) {
+		t.Errorf("expected LastSyncedAt to be updated")
+	}
+	if from := s.fetchFrom(); from != "2026-03-05" {
+		t.Errorf("expected the next sync to fetch from 2026-03-05, got %s", from)
+	}
+
+	// Re-syncing the window imports nothing already imported, but does pick
+	// up a transaction posted since with an earlier date
+	for date, ids := range map[string][]string{"2026-03-10": {"a", "b"}, "2026-03-11": {"c"}, "2026-03-12": {"d", "e"}} {
+		for _, id := range ids {
+			if s.isNew(date, id) {
+				t.Errorf("transaction %s on %s should not be re-imported", id, date)
+			}
+		}
+	}
+	if !s.isNew("2026-03-11", "posted-late") {
+		t.Error("a late-posted transaction inside the window should be imported")
+	}
+}
+
+func TestAccountSyncState_FirstSync(t *testing.T) {
+	s := &accountSyncState{}
+	s.advance([]string{"2026-02-01", "2026-03-01"}, []string{"a", "b"}, time.Now())
+	if s.Cursor != "2026-03-01" || s.TrackedFrom != "2026-02-22" {
+		t.Errorf("expected cursor 2026-03-01 tracked from 2026-02-22, got %s from %s", s.Cursor, s.TrackedFrom)
+	}
+	if s.isNew("2026-02-01", "a") || s.isNew("2026-03-01", "b") {
+		t.Error("imported transactions should not be new")
+	}
+}
+
+func TestSyncState_RoundTrip(t *testing.T) {
+	old := secretsDir
+	secretsDir = t.TempDir()
+	defer func() { secretsDir = old }()
+
+	state, err := loadSyncState()
+	if err != nil {
+		t.Fatalf("loading missing state: %v", err)
+	}
+	if len(state.Accounts) != 0 {
+		t.Fatalf("expected empty state, got %v", state.Accounts)
+	}
+
+	state.Accounts["acct-1"] = &accountSyncState{Cursor: "2026-03-12", TrackedFrom: "2026-03-05", RecentIDs: map[string][]string{"2026-03-12": {"x"}}, TransactionCount: 3}
+	if err := saveSyncState(state); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded, err := loadSyncState()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	got := loaded.Accounts["acct-1"]
+	if got == nil || got.Cursor != "2026-03-12" || got.TrackedFrom != "2026-03-05" || got.isNew("2026-03-12", "x") || got.TransactionCount != 3 {
+		t.Errorf("unexpected reloaded state: %+v", got)
+	}
+}
+
+func TestSyncState_MigratesCursorIDs(t *testing.T) {
+	old := secretsDir
+	secretsDir = t.TempDir()
+	defer func() { secretsDir = old }()
+
+	legacy := `{"accounts": {"acct-1": {"cursor": "2026-03-12", "cursor_ids": ["x"], "transaction_count": 3}}}`
+	if err := os.WriteFile(syncStatePath(), []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	state, err := loadSyncState()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got := state.Accounts["acct-1"]
+	if got.TrackedFrom != "2026-03-12" || got.CursorIDs != nil || got.fetchFrom() != "2026-03-12" {
+		t.Errorf("unexpected migrated state: %+v", got)
+	}
+	if got.isNew("2026-03-12", "x") || got.isNew("2026-03-11", "unknown") || !got.isNew("2026-03-12", "y") {
+		t.Error("expected the migrated state to skip what the old cursor had imported")
+	}
+}
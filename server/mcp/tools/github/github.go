@@ -1,9 +1,11 @@
-// Package github provides GitHub App-based tools for commenting on issues
-// as the Diane bot with a separate identity from the user.
+// Package github provides GitHub App-based tools for commenting on issues,
+// opening issues and pull requests and watching workflow runs as the Diane
+// bot, with a separate identity from the user.
 package github
 
 import (
 	"bytes"
+	"context"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/json"
@@ -11,18 +13,24 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 // getConfigPath returns the path to the GitHub bot config file
 func getConfigPath() string {
 	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".diane", "secrets", "github-bot-token.json")
+	return filepath.Join(home, config.DirName(), "secrets", "github-bot-token.json")
 }
 
 const userAgent = "diane-assistant-bot"
@@ -174,13 +182,141 @@ func (p *Provider) Tools() []Tool {
 				},
 			},
 		},
+		{
+			Name:        "github_create_issue",
+			Description: "Open a new GitHub issue as Diane bot, e.g. to file a bug found while working. Returns the issue number and URL.",
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []string{"title"},
+				"properties": map[string]interface{}{
+					"owner": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository owner (default: the configured owner)",
+					},
+					"repo": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository name (default: the configured repo)",
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue title",
+					},
+					"body": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue markdown body",
+					},
+					"labels": map[string]interface{}{
+						"type":        "string",
+						"description": "Comma-separated labels to apply (e.g., 'bug,urgent')",
+					},
+					"assignees": map[string]interface{}{
+						"type":        "string",
+						"description": "Comma-separated GitHub usernames to assign",
+					},
+				},
+			},
+		},
+		{
+			Name:        "github_create_pr",
+			Description: "Open a pull request as Diane bot from an existing branch, e.g. for a generated change. Returns the pull request number and URL.",
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []string{"title", "head", "base"},
+				"properties": map[string]interface{}{
+					"owner": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository owner (default: the configured owner)",
+					},
+					"repo": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository name (default: the configured repo)",
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "Pull request title",
+					},
+					"head": map[string]interface{}{
+						"type":        "string",
+						"description": "Branch with the changes; use 'user:branch' for a branch in a fork",
+					},
+					"base": map[string]interface{}{
+						"type":        "string",
+						"description": "Branch to merge into (e.g., 'main')",
+					},
+					"body": map[string]interface{}{
+						"type":        "string",
+						"description": "Pull request markdown body",
+					},
+					"draft": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Open as a draft pull request",
+					},
+				},
+			},
+		},
+		{
+			Name:        "github_list_workflow_runs",
+			Description: "List recent GitHub Actions workflow runs with their status, conclusion and URL, newest first. Use status 'failure' to find red builds.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"owner": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository owner (default: the configured owner)",
+					},
+					"repo": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository name (default: the configured repo)",
+					},
+					"branch": map[string]interface{}{
+						"type":        "string",
+						"description": "Only runs for this branch",
+					},
+					"status": map[string]interface{}{
+						"type":        "string",
+						"description": "Only runs with this status or conclusion: queued, in_progress, completed, success, failure, cancelled, timed_out, ...",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of runs to return (default 10, max 100)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "github_rerun_workflow",
+			Description: "Re-run a GitHub Actions workflow run, e.g. one that failed on a flaky test. Set failed_only to re-run just the failed jobs.",
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []string{"run_id"},
+				"properties": map[string]interface{}{
+					"owner": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository owner (default: the configured owner)",
+					},
+					"repo": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository name (default: the configured repo)",
+					},
+					"run_id": map[string]interface{}{
+						"type":        "number",
+						"description": "Workflow run ID (from github_list_workflow_runs)",
+					},
+					"failed_only": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Re-run only the failed jobs and their dependents",
+					},
+				},
+			},
+		},
 	}
 }
 
 // HasTool checks if a tool name belongs to this provider
 func (p *Provider) HasTool(name string) bool {
 	switch name {
-	case "github_bot_comment_as_bot", "github_bot_react_as_bot", "github_bot_manage_labels":
+	case "github_bot_comment_as_bot", "github_bot_react_as_bot", "github_bot_manage_labels",
+		"github_create_issue", "github_create_pr", "github_list_workflow_runs", "github_rerun_workflow":
 		return true
 	}
 	return false
@@ -195,22 +331,21 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.reactAsBot(args)
 	case "github_bot_manage_labels":
 		return p.manageLabels(args)
+	case "github_create_issue":
+		return p.createIssue(args)
+	case "github_create_pr":
+		return p.createPR(args)
+	case "github_list_workflow_runs":
+		return p.listWorkflowRuns(args)
+	case "github_rerun_workflow":
+		return p.rerunWorkflow(args)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
 }
 
-// getInstallationToken gets or refreshes the GitHub installation token
-func (p *Provider) getInstallationToken() (string, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	// Check cache
-	if p.cachedToken != "" && time.Now().Before(p.tokenExpiry) {
-		return p.cachedToken, nil
-	}
-
-	// Generate JWT
+// appJWT signs a short-lived JWT that authenticates as the GitHub App itself
+func (p *Provider) appJWT() (string, error) {
 	now := time.Now()
 	claims := jwt.MapClaims{
 		"iat": now.Add(-60 * time.Second).Unix(), // Clock skew tolerance
@@ -223,6 +358,43 @@ func (p *Provider) getInstallationToken() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT: %w", err)
 	}
+	return jwtStr, nil
+}
+
+// CheckBackend implements tools.BackendChecker by looking up the
+// configured installation as the app, which needs a valid app ID and key
+// and an installation that still exists
+func (p *Provider) CheckBackend(ctx context.Context) error {
+	jwtStr, err := p.appJWT()
+	if err != nil {
+		return fmt.Errorf("github %w: %v", tools.ErrNotConfigured, err)
+	}
+	url := fmt.Sprintf("https://api.github.com/app/installations/%s", p.config.InstallationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwtStr)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", userAgent)
+	return tools.CheckHTTP("github", req)
+}
+
+// getInstallationToken gets or refreshes the GitHub installation token
+func (p *Provider) getInstallationToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Check cache
+	if p.cachedToken != "" && time.Now().Before(p.tokenExpiry) {
+		return p.cachedToken, nil
+	}
+
+	jwtStr, err := p.appJWT()
+	if err != nil {
+		return "", err
+	}
 
 	// Request installation access token
 	url := fmt.Sprintf("https://api.github.com/app/installations/%s/access_tokens", p.config.InstallationID)
@@ -418,6 +590,321 @@ func (p *Provider) manageLabels(args map[string]interface{}) (interface{}, error
 		joinLabels(added), joinLabels(removed))), nil
 }
 
+// repoArgs returns the owner and repo named in args, defaulting to the
+// configured repository
+func (p *Provider) repoArgs(args map[string]interface{}) (string, string) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	if owner == "" {
+		owner = p.config.Owner
+	}
+	if repo == "" {
+		repo = p.config.Repo
+	}
+	return owner, repo
+}
+
+// repoRequest sends a request to a repository endpoint as the bot, with
+// payload (if not nil) as the JSON body
+func (p *Provider) repoRequest(method, owner, repo, endpoint string, payload interface{}) (*http.Response, error) {
+	token, err := p.getInstallationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, _ := json.Marshal(payload)
+		body = bytes.NewReader(data)
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/%s", owner, repo, endpoint)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", userAgent)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	return client.Do(req)
+}
+
+// created is the part of a created issue or pull request the tools report
+type created struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// create POSTs payload to a repository endpoint as the bot and decodes the
+// created issue or pull request. what names it in errors.
+func (p *Provider) create(owner, repo, endpoint, what string, payload interface{}) (*created, error) {
+	resp, err := p.repoRequest("POST", owner, repo, endpoint, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, createError(what, owner+"/"+repo, resp.StatusCode, respBody)
+	}
+
+	var result created
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse created %s: %w", what, err)
+	}
+	return &result, nil
+}
+
+// createError explains a failed create with the common causes spelled out:
+// missing app permissions, an unknown repository and, for pull requests, a
+// head branch that doesn't exist or has nothing to merge
+func createError(what, repo string, status int, body []byte) error {
+	var apiErr struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	json.Unmarshal(body, &apiErr)
+
+	permission := "Issues"
+	if what == "pull request" {
+		permission = "Pull requests"
+	}
+
+	switch status {
+	case http.StatusForbidden:
+		return fmt.Errorf("the GitHub App may not create a %s in %s: grant it %s read & write access and install it on the repository (%s)",
+			what, repo, permission, apiErr.Message)
+	case http.StatusNotFound:
+		return fmt.Errorf("repository %s not found, or the GitHub App is not installed on it", repo)
+	case http.StatusGone:
+		return fmt.Errorf("%ss are disabled in %s", what, repo)
+	case http.StatusUnprocessableEntity:
+		var details []string
+		for _, e := range apiErr.Errors {
+			switch {
+			case e.Field == "head" && e.Code == "invalid":
+				details = append(details, "head branch not found (push it first)")
+			case e.Field == "base" && e.Code == "invalid":
+				details = append(details, "base branch not found")
+			case e.Message != "":
+				details = append(details, e.Message)
+			case e.Field != "":
+				details = append(details, fmt.Sprintf("%s is %s", e.Field, e.Code))
+			}
+		}
+		if len(details) == 0 {
+			details = append(details, apiErr.Message)
+		}
+		return fmt.Errorf("GitHub rejected the %s for %s: %s", what, repo, strings.Join(details, "; "))
+	}
+	return fmt.Errorf("GitHub API error: %d - %s", status, string(body))
+}
+
+// createIssue opens an issue as the bot
+func (p *Provider) createIssue(args map[string]interface{}) (interface{}, error) {
+	title, _ := args["title"].(string)
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	owner, repo := p.repoArgs(args)
+	if owner == "" || repo == "" {
+		return nil, fmt.Errorf("owner and repo are required (no default repository configured)")
+	}
+
+	payload := map[string]interface{}{"title": title}
+	if body, ok := args["body"].(string); ok && body != "" {
+		payload["body"] = body
+	}
+	if labels, ok := args["labels"].(string); ok && labels != "" {
+		payload["labels"] = splitLabels(labels)
+	}
+	if assignees, ok := args["assignees"].(string); ok && assignees != "" {
+		payload["assignees"] = splitLabels(assignees)
+	}
+
+	issue, err := p.create(owner, repo, "issues", "issue", payload)
+	if err != nil {
+		return nil, err
+	}
+	return textContent(fmt.Sprintf("✅ Created issue #%d as diane-assistant[bot]: %s", issue.Number, issue.HTMLURL)), nil
+}
+
+// createPR opens a pull request as the bot
+func (p *Provider) createPR(args map[string]interface{}) (interface{}, error) {
+	title, _ := args["title"].(string)
+	head, _ := args["head"].(string)
+	base, _ := args["base"].(string)
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if head == "" || base == "" {
+		return nil, fmt.Errorf("head and base are required")
+	}
+	owner, repo := p.repoArgs(args)
+	if owner == "" || repo == "" {
+		return nil, fmt.Errorf("owner and repo are required (no default repository configured)")
+	}
+
+	payload := map[string]interface{}{"title": title, "head": head, "base": base}
+	if body, ok := args["body"].(string); ok && body != "" {
+		payload["body"] = body
+	}
+	if draft, ok := args["draft"].(bool); ok && draft {
+		payload["draft"] = true
+	}
+
+	pr, err := p.create(owner, repo, "pulls", "pull request", payload)
+	if err != nil {
+		return nil, err
+	}
+	return textContent(fmt.Sprintf("✅ Created pull request #%d as diane-assistant[bot]: %s", pr.Number, pr.HTMLURL)), nil
+}
+
+// workflowRun is the part of a workflow run github_list_workflow_runs reports
+type workflowRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	RunNumber  int    `json:"run_number"`
+	RunAttempt int    `json:"run_attempt"`
+	Event      string `json:"event"`
+	HeadBranch string `json:"head_branch"`
+	HeadSHA    string `json:"head_sha"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// formatWorkflowRuns renders runs one per line for the tool result
+func formatWorkflowRuns(repo string, runs []workflowRun) string {
+	if len(runs) == 0 {
+		return fmt.Sprintf("No matching workflow runs in %s", repo)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d workflow runs in %s:\n", len(runs), repo)
+	for _, r := range runs {
+		state := r.Status
+		if r.Status == "completed" && r.Conclusion != "" {
+			state = r.Conclusion
+		}
+		sha := r.HeadSHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		fmt.Fprintf(&sb, "- %s #%d (run %d, attempt %d): %s on %s@%s, %s %s\n  %s\n",
+			r.Name, r.RunNumber, r.ID, r.RunAttempt, state, r.HeadBranch, sha, r.Event, r.CreatedAt, r.HTMLURL)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// actionsError explains a failed Actions request, spelling out missing app
+// permissions and unknown repositories or runs
+func actionsError(action, repo, access string, status int, body []byte) error {
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &apiErr)
+
+	switch status {
+	case http.StatusForbidden:
+		return fmt.Errorf("the GitHub App may not %s in %s: grant it Actions %s access (%s)", action, repo, access, apiErr.Message)
+	case http.StatusNotFound:
+		return fmt.Errorf("failed to %s: repository %s or run not found, or the GitHub App is not installed on it", action, repo)
+	}
+	if apiErr.Message != "" {
+		return fmt.Errorf("failed to %s in %s: %s", action, repo, apiErr.Message)
+	}
+	return fmt.Errorf("GitHub API error: %d - %s", status, string(body))
+}
+
+// listWorkflowRuns lists recent workflow runs
+func (p *Provider) listWorkflowRuns(args map[string]interface{}) (interface{}, error) {
+	owner, repo := p.repoArgs(args)
+	if owner == "" || repo == "" {
+		return nil, fmt.Errorf("owner and repo are required (no default repository configured)")
+	}
+	limit := 10
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	query := url.Values{"per_page": {strconv.Itoa(limit)}}
+	if branch, ok := args["branch"].(string); ok && branch != "" {
+		query.Set("branch", branch)
+	}
+	if status, ok := args["status"].(string); ok && status != "" {
+		query.Set("status", status)
+	}
+
+	resp, err := p.repoRequest("GET", owner, repo, "actions/runs?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, actionsError("list workflow runs", owner+"/"+repo, "read", resp.StatusCode, respBody)
+	}
+
+	var result struct {
+		WorkflowRuns []workflowRun `json:"workflow_runs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow runs: %w", err)
+	}
+
+	return textContent(formatWorkflowRuns(owner+"/"+repo, result.WorkflowRuns)), nil
+}
+
+// rerunWorkflow re-runs a workflow run, or just its failed jobs
+func (p *Provider) rerunWorkflow(args map[string]interface{}) (interface{}, error) {
+	var runID int64
+	switch v := args["run_id"].(type) {
+	case float64:
+		runID = int64(v)
+	case string:
+		runID, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if runID <= 0 {
+		return nil, fmt.Errorf("run_id is required")
+	}
+	owner, repo := p.repoArgs(args)
+	if owner == "" || repo == "" {
+		return nil, fmt.Errorf("owner and repo are required (no default repository configured)")
+	}
+
+	endpoint := fmt.Sprintf("actions/runs/%d/rerun", runID)
+	what := "workflow run"
+	if failedOnly, ok := args["failed_only"].(bool); ok && failedOnly {
+		endpoint = fmt.Sprintf("actions/runs/%d/rerun-failed-jobs", runID)
+		what = "failed jobs of workflow run"
+	}
+
+	resp, err := p.repoRequest("POST", owner, repo, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-run workflow: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, actionsError("re-run workflows", owner+"/"+repo, "read & write", resp.StatusCode, respBody)
+	}
+
+	return textContent(fmt.Sprintf("✅ Re-running %s %d in %s/%s", what, runID, owner, repo)), nil
+}
+
 // splitLabels splits comma-separated labels and trims whitespace
 func splitLabels(s string) []string {
 	var labels []string
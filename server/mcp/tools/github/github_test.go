@@ -0,0 +1,93 @@
+package github
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateError(t *testing.T) {
+	tests := []struct {
+		what   string
+		status int
+		body   string
+		want   string
+	}{
+		{"issue", http.StatusForbidden, `{"message":"Resource not accessible by integration"}`, "Issues read & write"},
+		{"pull request", http.StatusForbidden, `{"message":"Resource not accessible by integration"}`, "Pull requests read & write"},
+		{"issue", http.StatusNotFound, `{"message":"Not Found"}`, "not installed"},
+		{"issue", http.StatusGone, `{"message":"Issues are disabled for this repo"}`, "issues are disabled"},
+		{"pull request", http.StatusUnprocessableEntity,
+			`{"message":"Validation Failed","errors":[{"resource":"PullRequest","field":"head","code":"invalid"}]}`,
+			"head branch not found"},
+		{"pull request", http.StatusUnprocessableEntity,
+			`{"message":"Validation Failed","errors":[{"resource":"PullRequest","code":"custom","message":"A pull request already exists for me:fix."}]}`,
+			"A pull request already exists"},
+		{"issue", http.StatusInternalServerError, `oops`, "500"},
+	}
+	for _, tt := range tests {
+		err := createError(tt.what, "me/repo", tt.status, []byte(tt.body))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("createError(%s, %d) = %v, want it to mention %q", tt.what, tt.status, err, tt.want)
+		}
+	}
+}
+
+func TestCreateArgs(t *testing.T) {
+	p := &Provider{config: &Config{Owner: "me", Repo: "repo"}}
+
+	if owner, repo := p.repoArgs(map[string]interface{}{}); owner != "me" || repo != "repo" {
+		t.Errorf("expected the configured repo by default, got %s/%s", owner, repo)
+	}
+	if owner, repo := p.repoArgs(map[string]interface{}{"owner": "other", "repo": "thing"}); owner != "other" || repo != "thing" {
+		t.Errorf("expected the given repo, got %s/%s", owner, repo)
+	}
+
+	if _, err := p.Call("github_create_issue", map[string]interface{}{"body": "no title"}); err == nil {
+		t.Error("expected an error for an issue without a title")
+	}
+	if _, err := p.Call("github_create_pr", map[string]interface{}{"title": "Fix", "head": "fix"}); err == nil {
+		t.Error("expected an error for a pull request without a base")
+	}
+
+	unconfigured := &Provider{config: &Config{}}
+	if _, err := unconfigured.Call("github_create_issue", map[string]interface{}{"title": "Bug"}); err == nil ||
+		!strings.Contains(err.Error(), "owner and repo") {
+		t.Errorf("expected an error without a repository, got %v", err)
+	}
+}
+
+func TestFormatWorkflowRuns(t *testing.T) {
+	runs := []workflowRun{
+		{ID: 111, Name: "CI", RunNumber: 42, RunAttempt: 1, Event: "push", HeadBranch: "main",
+			HeadSHA: "0123456789abcdef", Status: "completed", Conclusion: "failure", HTMLURL: "https://github.com/me/repo/actions/runs/111"},
+		{ID: 112, Name: "CI", RunNumber: 43, RunAttempt: 2, Event: "pull_request", HeadBranch: "fix",
+			HeadSHA: "fedcba", Status: "in_progress"},
+	}
+	out := formatWorkflowRuns("me/repo", runs)
+	for _, want := range []string{"2 workflow runs in me/repo", "CI #42 (run 111, attempt 1): failure on main@0123456", "actions/runs/111", "in_progress on fix@fedcba"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if out := formatWorkflowRuns("me/repo", nil); !strings.Contains(out, "No matching") {
+		t.Errorf("unexpected empty result: %s", out)
+	}
+}
+
+func TestActionsErrorAndRerunArgs(t *testing.T) {
+	err := actionsError("re-run workflows", "me/repo", "read & write", http.StatusForbidden, []byte(`{"message":"Resource not accessible by integration"}`))
+	if err == nil || !strings.Contains(err.Error(), "Actions read & write") {
+		t.Errorf("unexpected permission error: %v", err)
+	}
+	err = actionsError("re-run workflows", "me/repo", "read & write", http.StatusForbidden, []byte(`{"message":"Unable to re-run this workflow run because it was created over a month ago"}`))
+	if err == nil || !strings.Contains(err.Error(), "over a month ago") {
+		t.Errorf("expected GitHub's reason to be kept, got %v", err)
+	}
+
+	p := &Provider{config: &Config{Owner: "me", Repo: "repo"}}
+	if _, err := p.Call("github_rerun_workflow", map[string]interface{}{"failed_only": true}); err == nil ||
+		!strings.Contains(err.Error(), "run_id") {
+		t.Errorf("expected an error without run_id, got %v", err)
+	}
+}
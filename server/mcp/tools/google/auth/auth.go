@@ -15,6 +15,8 @@ import (
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 // Google OAuth endpoints for device flow
@@ -83,7 +85,7 @@ func LoadToken(account string) (*oauth2.Token, error) {
 	}
 
 	// Try diane secrets location first
-	tokenPath := filepath.Join(home, ".diane", "secrets", "google", fmt.Sprintf("token_%s.json", account))
+	tokenPath := filepath.Join(home, config.DirName(), "secrets", "google", fmt.Sprintf("token_%s.json", account))
 	tokenData, err := os.ReadFile(tokenPath)
 	if err != nil {
 		// Try gog tokens location (backward compatibility)
@@ -118,7 +120,7 @@ func LoadCredentials() ([]byte, error) {
 	}
 
 	// Try diane secrets location first
-	credPath := filepath.Join(home, ".diane", "secrets", "google", "credentials.json")
+	credPath := filepath.Join(home, config.DirName(), "secrets", "google", "credentials.json")
 	credData, err := os.ReadFile(credPath)
 	if err != nil {
 		// Try gog config location
@@ -239,7 +241,7 @@ func GetTokenPath(account string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".diane", "secrets", "google", fmt.Sprintf("token_%s.json", account)), nil
+	return filepath.Join(home, config.DirName(), "secrets", "google", fmt.Sprintf("token_%s.json", account)), nil
 }
 
 // SaveToken saves an OAuth token for an account to ~/.diane/secrets/google/token_{account}.json
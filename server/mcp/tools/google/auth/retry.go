@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Retry limits for Google API calls. A call is tried at most
+// retryMaxAttempts times and never waits past retryMaxElapsed in total, so
+// a struggling API slows a tool call down but can't hang it.
+const (
+	retryMaxAttempts = 4
+	retryBaseDelay   = 500 * time.Millisecond
+	retryMaxDelay    = 8 * time.Second
+	retryMaxElapsed  = 30 * time.Second
+)
+
+// NewHTTPClient returns an HTTP client that authorizes requests with ts and
+// retries transient failures. Google API clients should be built with it
+// (option.WithHTTPClient) rather than option.WithTokenSource.
+func NewHTTPClient(ts oauth2.TokenSource) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{
+			base:        &oauth2.Transport{Source: ts, Base: http.DefaultTransport},
+			maxAttempts: retryMaxAttempts,
+			baseDelay:   retryBaseDelay,
+			maxDelay:    retryMaxDelay,
+			maxElapsed:  retryMaxElapsed,
+		},
+	}
+}
+
+// retryTransport retries requests that failed transiently, with exponential
+// backoff or the server's Retry-After. Reads are retried on rate limiting,
+// 5xx responses and network errors. Writes are retried only on 429, which
+// Google returns before doing any work, since a write that hit a 5xx or
+// network error may already have been applied.
+type retryTransport struct {
+	base        http.RoundTripper
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	maxElapsed  time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Media uploads retry their own chunks; and a body that can't be
+	// rewound can't be sent twice
+	if strings.HasPrefix(req.URL.Path, "/upload/") || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return t.base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxAttempts || !retryable(req, resp, err) {
+			return resp, err
+		}
+		delay := t.backoff(attempt, resp)
+		if time.Since(start)+delay > t.maxElapsed {
+			return resp, err
+		}
+
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		slog.Debug("Retrying Google API request",
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"status", status,
+			"error", err,
+			"attempt", attempt,
+			"delay", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryable reports whether a failed attempt is worth repeating
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	read := req.Method == http.MethodGet || req.Method == http.MethodHead
+	if err != nil {
+		// A cancelled caller isn't a transient failure
+		return read && req.Context().Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return read
+	}
+	return false
+}
+
+// backoff returns how long to wait before the next attempt: the server's
+// Retry-After if it sent one, otherwise baseDelay doubled per attempt up to
+// maxDelay. A Retry-After past the time budget ends the retries.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+	return min(t.baseDelay<<(attempt-1), t.maxDelay)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an
+// HTTP date
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
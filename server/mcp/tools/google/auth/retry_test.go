@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetryClient starts a server answering requests with statuses in
+// turn (repeating the last), echoing the request body. It returns a
+// retrying client, the server's URL and a count of requests served.
+func newTestRetryClient(t *testing.T, statuses ...int) (*http.Client, string, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		n := int(calls.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	client := &http.Client{Transport: &retryTransport{
+		base:        http.DefaultTransport,
+		maxAttempts: 4,
+		baseDelay:   time.Millisecond,
+		maxDelay:    5 * time.Millisecond,
+		maxElapsed:  time.Second,
+	}}
+	return client, srv.URL, &calls
+}
+
+func TestRetryTransportRetriesReads(t *testing.T) {
+	client, url, calls := newTestRetryClient(t, 503, 429, 200)
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 || calls.Load() != 3 {
+		t.Errorf("got %d after %d calls, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	client, url, calls := newTestRetryClient(t, 503)
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 503 || calls.Load() != 4 {
+		t.Errorf("got %d after %d calls, want 503 after 4", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransportWrites(t *testing.T) {
+	// A 5xx write may have been applied, so it isn't repeated
+	client, url, calls := newTestRetryClient(t, 503, 200)
+	resp, err := client.Post(url, "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 503 || calls.Load() != 1 {
+		t.Errorf("got %d after %d calls, want 503 after 1", resp.StatusCode, calls.Load())
+	}
+
+	// A rate-limited one wasn't, and is resent with its body
+	client, url, calls = newTestRetryClient(t, 429, 200)
+	resp, err = client.Post(url, "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || calls.Load() != 2 || string(body) != `{"a":1}` {
+		t.Errorf("got %d %q after %d calls, want 200 with the body after 2", resp.StatusCode, body, calls.Load())
+	}
+}
+
+func TestRetryTransportTimeBudget(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &retryTransport{
+		base: http.DefaultTransport, maxAttempts: 4, baseDelay: time.Millisecond,
+		maxDelay: time.Millisecond, maxElapsed: time.Second,
+	}}
+	start := time.Now()
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 || time.Since(start) > time.Second {
+		t.Errorf("a Retry-After past the budget should end retries; got %d calls in %s", calls.Load(), time.Since(start))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"Thu, 01 Jan 2026 12:00:10 GMT", 10 * time.Second, true},
+		{"Thu, 01 Jan 2026 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; want %s, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/diane-assistant/diane/mcp/tools"
+	"github.com/diane-assistant/diane/mcp/tools/google/auth"
+	"golang.org/x/oauth2"
+)
+
+// tokenInfoURL validates an access token without needing any API scope
+const tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// CheckBackend implements tools.BackendChecker. It gets an access token for
+// the default account, refreshing it if needed, and asks Google whether the
+// token is valid, which catches revoked as well as expired credentials.
+func (p *Provider) CheckBackend(ctx context.Context) error {
+	ts, err := auth.GetTokenSource(ctx, "")
+	if err != nil {
+		return fmt.Errorf("google %w: %v", tools.ErrNotConfigured, err)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		// The oauth2 package flattens transport errors, so anything that
+		// isn't a token endpoint answer is treated as a network failure
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			reason := retrieveErr.ErrorCode
+			if reason == "" {
+				reason = retrieveErr.Response.Status
+			}
+			return fmt.Errorf("google %w: token refresh failed (%s)", tools.ErrBackendUnauthorized, reason)
+		}
+		return fmt.Errorf("google %w: %v", tools.ErrBackendUnreachable, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		tokenInfoURL+"?access_token="+url.QueryEscape(token.AccessToken), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return tools.WrapUnreachable("google", err)
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized:
+		// tokeninfo answers 400 for an invalid or revoked token
+		return fmt.Errorf("google %w: access token is invalid or revoked", tools.ErrBackendUnauthorized)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("google backend returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -107,7 +107,7 @@ func NewClient(account string) (*Client, error) {
 		return nil, fmt.Errorf("failed to get token source: %w", err)
 	}
 
-	srv, err := calendar.NewService(ctx, option.WithTokenSource(tokenSource))
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(auth.NewHTTPClient(tokenSource)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Calendar service: %w", err)
 	}
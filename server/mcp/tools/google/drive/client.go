@@ -41,7 +41,7 @@ func NewClient(account string) (*Client, error) {
 		return nil, fmt.Errorf("failed to get token source: %w", err)
 	}
 
-	srv, err := drive.NewService(ctx, option.WithTokenSource(tokenSource))
+	srv, err := drive.NewService(ctx, option.WithHTTPClient(auth.NewHTTPClient(tokenSource)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Drive service: %w", err)
 	}
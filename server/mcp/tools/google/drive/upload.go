@@ -0,0 +1,108 @@
+package drive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/diane-assistant/diane/mcp/tools/google/auth"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// UploadScope is the OAuth scope uploads need: it lets Diane create files
+// and manage the ones it created, without access to the rest of Drive
+const UploadScope = drive.DriveFileScope
+
+// uploadChunkSize is the resumable upload chunk size. Files up to this size
+// go up in a single request; larger ones use a resumable session, sent and
+// retried a chunk at a time.
+const uploadChunkSize = 8 * 1024 * 1024
+
+// ErrScopeMissing means the account's token was granted without UploadScope
+var ErrScopeMissing = errors.New("google account is not authorized to upload to Drive")
+
+// NewUploadClient creates a Drive client that can create files. It asks for
+// UploadScope, which a token granted for read-only access does not carry;
+// uploads with such a token fail with ErrScopeMissing.
+func NewUploadClient(account string) (*Client, error) {
+	if account == "" {
+		account = "default"
+	}
+
+	ctx := context.Background()
+
+	tokenSource, err := auth.GetTokenSource(ctx, account, UploadScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token source: %w", err)
+	}
+
+	srv, err := drive.NewService(ctx, option.WithHTTPClient(auth.NewHTTPClient(tokenSource)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Drive service: %w", err)
+	}
+
+	return &Client{srv: srv, account: account}, nil
+}
+
+// UploadedFile describes a file created by UploadFile
+type UploadedFile struct {
+	FileInfo
+	MD5Checksum string   `json:"md5Checksum,omitempty"`
+	Parents     []string `json:"parents,omitempty"`
+}
+
+// UploadFile uploads content as a new file called name. folderID places it
+// in a folder (My Drive's root if empty); mimeType may be empty to let Drive
+// detect it.
+func (c *Client) UploadFile(ctx context.Context, content io.Reader, name, folderID, mimeType string) (*UploadedFile, error) {
+	meta := &drive.File{Name: name, MimeType: mimeType}
+	if folderID != "" {
+		meta.Parents = []string{folderID}
+	}
+
+	f, err := c.srv.Files.Create(meta).
+		Media(content, googleapi.ChunkSize(uploadChunkSize)).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, modifiedTime, size, webViewLink, md5Checksum, parents").
+		Context(ctx).
+		Do()
+	if err != nil {
+		if scopeMissing(err) {
+			return nil, fmt.Errorf("%w: re-authorize the %s account with the %s scope", ErrScopeMissing, c.account, UploadScope)
+		}
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return &UploadedFile{
+		FileInfo: FileInfo{
+			ID:           f.Id,
+			Name:         f.Name,
+			MimeType:     f.MimeType,
+			ModifiedTime: f.ModifiedTime,
+			Size:         f.Size,
+			WebViewLink:  f.WebViewLink,
+		},
+		MD5Checksum: f.Md5Checksum,
+		Parents:     f.Parents,
+	}, nil
+}
+
+// scopeMissing reports whether Drive refused a request because the token
+// lacks a scope, as opposed to the user lacking access to the folder
+func scopeMissing(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusForbidden {
+		return false
+	}
+	for _, item := range apiErr.Errors {
+		if item.Reason == "insufficientPermissions" {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message), "insufficient authentication scopes")
+}
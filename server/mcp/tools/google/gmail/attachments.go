@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"google.golang.org/api/gmail/v1"
+
+	"github.com/diane-assistant/diane/internal/config"
 )
 
 // AttachmentInfo represents attachment metadata without content
@@ -130,7 +132,7 @@ func (s *Service) saveAttachmentFile(messageID, filename string, data []byte) (s
 	}
 
 	// Create directory: ~/.diane/attachments/{messageID}/
-	dir := filepath.Join(home, ".diane", "attachments", messageID)
+	dir := filepath.Join(home, config.DirName(), "attachments", messageID)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create attachment directory: %w", err)
 	}
@@ -195,7 +197,7 @@ func (s *Service) CleanupOldAttachments(maxAge time.Duration) (int, error) {
 		return 0, err
 	}
 
-	attachmentsDir := filepath.Join(home, ".diane", "attachments")
+	attachmentsDir := filepath.Join(home, config.DirName(), "attachments")
 	if _, err := os.Stat(attachmentsDir); os.IsNotExist(err) {
 		return 0, nil // No attachments directory
 	}
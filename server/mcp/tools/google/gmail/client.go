@@ -39,7 +39,7 @@ func NewClient(account string) (*Client, error) {
 	}
 
 	// Create Gmail service
-	srv, err := gmail.NewService(ctx, option.WithTokenSource(tokenSource))
+	srv, err := gmail.NewService(ctx, option.WithHTTPClient(auth.NewHTTPClient(tokenSource)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gmail service: %w", err)
 	}
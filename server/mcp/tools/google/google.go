@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/diane-assistant/diane/mcp/tools"
 	"github.com/diane-assistant/diane/mcp/tools/google/calendar"
 	"github.com/diane-assistant/diane/mcp/tools/google/drive"
 	"github.com/diane-assistant/diane/mcp/tools/google/gmail"
@@ -97,7 +98,9 @@ type Tool struct {
 }
 
 // Provider implements ToolProvider for Google services
-type Provider struct{}
+type Provider struct {
+	registry FileRegistry
+}
 
 // NewProvider creates a new Google tools provider
 func NewProvider() *Provider {
@@ -287,7 +290,6 @@ func (p *Provider) Tools() []Tool {
 			),
 		},
 		// Drive tools
-		// Drive tools
 		{
 			Name:        "drive_search",
 			Description: "Search Google Drive for files and folders using query syntax. Returns file metadata including ID, name, mimeType, and shared status.",
@@ -311,6 +313,27 @@ func (p *Provider) Tools() []Tool {
 				nil,
 			),
 		},
+		{
+			Name:        "drive_upload",
+			Description: "Upload a local file to Google Drive, by path or file_registry ID. Large files use a resumable upload. Returns the new Drive file ID and link, and registers the uploaded copy in the file registry under source 'gdrive'. Needs the drive.file OAuth scope.",
+			InputSchema: objectSchema(
+				map[string]interface{}{
+					"path":      stringProperty("Local path of the file to upload (alternative to file_id)"),
+					"file_id":   stringProperty("file_registry ID of a local file to upload (alternative to path)"),
+					"folder_id": stringProperty("ID of the Drive folder to upload into (default: My Drive root)"),
+					"name":      stringProperty("Name for the Drive file (default: the local filename)"),
+					"mime_type": stringProperty("MIME type of the file (default: detected by Drive)"),
+					"register":  boolProperty("Register the uploaded copy in the file registry (default: true)"),
+					"tags": map[string]interface{}{
+						"type":        "array",
+						"description": "Tags for the registered copy",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+					"account": stringProperty("Google account email to use (optional)"),
+				},
+				nil,
+			),
+		},
 		// Sheets tools
 		{
 			Name:        "sheets_get",
@@ -339,15 +362,20 @@ func (p *Provider) Tools() []Tool {
 		},
 		{
 			Name:        "sheets_append",
-			Description: "Append data to a Google Sheet. Adds new rows at the end of the range.",
+			Description: "Append data to a Google Sheet. Adds new rows at the end of the range and returns the updated range. Pass a single row as 'row' (e.g., to log a daily metric from a job) or several as 'values'.",
 			InputSchema: objectSchema(
 				map[string]interface{}{
 					"sheetId": stringProperty("The Google Sheets ID (from the URL)"),
 					"range":   stringProperty("Range in A1 notation specifying columns (e.g., 'Sheet1!A:C')"),
-					"values":  stringProperty("JSON array of arrays with row values (e.g., '[[\"x\",\"y\",\"z\"]]')"),
-					"account": stringProperty("Google account email to use (optional)"),
+					"values":  stringProperty("JSON array of arrays with row values (e.g., '[[\"x\",\"y\",\"z\"]]'); alternative to row"),
+					"row": map[string]interface{}{
+						"type":        "array",
+						"description": "A single row of cell values (e.g., [\"2025-07-01\", 42, \"=B2*2\"]); alternative to values",
+					},
+					"value_input_option": stringProperty("How values are interpreted: 'USER_ENTERED' (default) parses formulas, dates and numbers as if typed into Sheets; 'RAW' stores them as-is"),
+					"account":            stringProperty("Google account email to use (optional)"),
 				},
-				[]string{"sheetId", "range", "values"},
+				[]string{"sheetId", "range"},
 			),
 		},
 		{
@@ -493,8 +521,23 @@ func (p *Provider) HasTool(name string) bool {
 	return false
 }
 
+// ToolCategory groups Google tools by the service they use
+func (p *Provider) ToolCategory(name string) string {
+	switch {
+	case strings.HasPrefix(name, "gmail_"):
+		return tools.CategoryEmail
+	case strings.HasPrefix(name, "calendar_"):
+		return tools.CategoryCalendar
+	case strings.HasPrefix(name, "drive_"), strings.HasPrefix(name, "sheets_"):
+		return tools.CategoryFiles
+	}
+	return ""
+}
+
 // Call executes a tool by name
-func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+func (p *Provider) Call(name string, args map[string]interface{}) (result interface{}, err error) {
+	defer func() { err = tools.WrapUnreachable(p.Name(), err) }()
+
 	switch name {
 	// Gmail
 	case "gmail_search":
@@ -532,6 +575,8 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.searchFiles(args)
 	case "drive_list":
 		return p.listFiles(args)
+	case "drive_upload":
+		return p.uploadToDrive(args)
 	// Sheets
 	case "sheets_get":
 		return p.getSheet(args)
@@ -1208,16 +1253,25 @@ func (p *Provider) appendSheet(args map[string]interface{}) (interface{}, error)
 	if err != nil {
 		return nil, err
 	}
-	valuesStr, err := getStringRequired(args, "values")
-	if err != nil {
-		return nil, err
+	valuesStr := getString(args, "values")
+	row, hasRow := args["row"].([]interface{})
+	if (valuesStr == "") == !hasRow {
+		return nil, fmt.Errorf("exactly one of values or row is required")
+	}
+	option := strings.ToUpper(getString(args, "value_input_option"))
+	if option != "" && option != sheets.ValueInputRaw && option != sheets.ValueInputUserEntered {
+		return nil, fmt.Errorf("invalid value_input_option %q: must be RAW or USER_ENTERED", option)
 	}
 
 	account := getString(args, "account")
 
-	// Parse JSON values
 	var values [][]interface{}
-	if err := json.Unmarshal([]byte(valuesStr), &values); err != nil {
+	if hasRow {
+		if len(row) == 0 {
+			return nil, fmt.Errorf("row must not be empty")
+		}
+		values = [][]interface{}{row}
+	} else if err := json.Unmarshal([]byte(valuesStr), &values); err != nil {
 		return nil, fmt.Errorf("failed to parse values JSON: %w", err)
 	}
 
@@ -1226,7 +1280,7 @@ func (p *Provider) appendSheet(args map[string]interface{}) (interface{}, error)
 		return nil, fmt.Errorf("failed to create Sheets client: %w", err)
 	}
 
-	result, err := client.AppendRows(sheetId, rangeArg, values)
+	result, err := client.AppendRows(sheetId, rangeArg, values, option)
 	if err != nil {
 		return nil, fmt.Errorf("failed to append to sheet: %w", err)
 	}
@@ -325,6 +325,7 @@ func TestHasTool(t *testing.T) {
 		// Drive
 		"drive_search",
 		"drive_list",
+		"drive_upload",
 		// Sheets
 		"sheets_get",
 		"sheets_update",
@@ -365,6 +366,29 @@ func TestHasTool(t *testing.T) {
 	}
 }
 
+func TestToolCategory(t *testing.T) {
+	p := NewProvider()
+
+	// Every tool is categorized by the service it uses
+	for _, tool := range p.Tools() {
+		if p.ToolCategory(tool.Name) == "" {
+			t.Errorf("Provider.ToolCategory(%q) is empty", tool.Name)
+		}
+	}
+
+	tests := map[string]string{
+		"gmail_search":         "email",
+		"calendar_list_events": "calendar",
+		"drive_search":         "files",
+		"sheets_get":           "files",
+	}
+	for name, want := range tests {
+		if got := p.ToolCategory(name); got != want {
+			t.Errorf("Provider.ToolCategory(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
 // =============================================================================
 // Tool Schema Validation Tests
 // =============================================================================
@@ -449,6 +473,22 @@ func TestCallMissingRequiredArgs(t *testing.T) {
 			args:     map[string]interface{}{"sheetId": "abc"},
 			errorMsg: "range",
 		},
+		{
+			tool:     "sheets_append",
+			args:     map[string]interface{}{"sheetId": "abc", "range": "Log!A:C"},
+			errorMsg: "values or row",
+		},
+		{
+			tool: "sheets_append",
+			args: map[string]interface{}{"sheetId": "abc", "range": "Log!A:C",
+				"row": []interface{}{"2025-07-01", 42.0}, "value_input_option": "FORMULA"},
+			errorMsg: "value_input_option",
+		},
+		{
+			tool:     "drive_upload",
+			args:     map[string]interface{}{},
+			errorMsg: "path or file_id",
+		},
 		{
 			tool:     "calendar_create_event",
 			args:     map[string]interface{}{},
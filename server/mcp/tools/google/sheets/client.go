@@ -50,7 +50,7 @@ func NewClient(account string) (*Client, error) {
 		return nil, fmt.Errorf("failed to get token source: %w", err)
 	}
 
-	srv, err := sheets.NewService(ctx, option.WithTokenSource(tokenSource))
+	srv, err := sheets.NewService(ctx, option.WithHTTPClient(auth.NewHTTPClient(tokenSource)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Sheets service: %w", err)
 	}
@@ -99,14 +99,27 @@ type UpdateResult struct {
 	UpdatedCells   int64  `json:"updatedCells"`
 }
 
-// AppendRows appends rows to a spreadsheet
-func (c *Client) AppendRows(spreadsheetID, rangeA1 string, values [][]interface{}) (*AppendResult, error) {
+// Value input options: how the Sheets API interprets written values
+const (
+	// ValueInputRaw stores values exactly as given, so "=A1" stays text
+	ValueInputRaw = "RAW"
+	// ValueInputUserEntered parses values as if typed into the UI, turning
+	// "=A1" into a formula and "2025-07-01" into a date
+	ValueInputUserEntered = "USER_ENTERED"
+)
+
+// AppendRows appends rows to a spreadsheet, interpreting the values
+// according to valueInputOption (ValueInputUserEntered if empty)
+func (c *Client) AppendRows(spreadsheetID, rangeA1 string, values [][]interface{}, valueInputOption string) (*AppendResult, error) {
+	if valueInputOption == "" {
+		valueInputOption = ValueInputUserEntered
+	}
 	vr := &sheets.ValueRange{
 		Values: values,
 	}
 
 	resp, err := c.srv.Spreadsheets.Values.Append(spreadsheetID, rangeA1, vr).
-		ValueInputOption("USER_ENTERED").
+		ValueInputOption(valueInputOption).
 		InsertDataOption("INSERT_ROWS").
 		Do()
 	if err != nil {
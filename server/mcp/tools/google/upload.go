@@ -0,0 +1,120 @@
+package google
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/diane-assistant/diane/mcp/tools/google/drive"
+)
+
+// FileRegistry is the part of the file registry drive_upload uses: to find
+// a registered file on disk and to register the uploaded copy
+type FileRegistry interface {
+	// LocalPath returns the on-disk path of a registered file
+	LocalPath(id string) (string, error)
+	// RegisterFile takes file_registry_register arguments
+	RegisterFile(args map[string]interface{}) (map[string]interface{}, error)
+}
+
+// SetFileRegistry connects drive_upload to the file registry. Without one,
+// uploads by registry ID are rejected and uploads aren't registered.
+func (p *Provider) SetFileRegistry(r FileRegistry) {
+	p.registry = r
+}
+
+func (p *Provider) uploadToDrive(args map[string]interface{}) (interface{}, error) {
+	path := getString(args, "path")
+	fileID := getString(args, "file_id")
+	if (path == "") == (fileID == "") {
+		return nil, fmt.Errorf("exactly one of path or file_id is required")
+	}
+	if fileID != "" {
+		if p.registry == nil {
+			return nil, fmt.Errorf("file_id requires the file_registry tools, which are not available")
+		}
+		var err error
+		if path, err = p.registry.LocalPath(fileID); err != nil {
+			return nil, fmt.Errorf("failed to resolve file_id: %w", err)
+		}
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	} else if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory; only files can be uploaded", path)
+	}
+
+	name := getString(args, "name")
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	register := true
+	if v, ok := args["register"].(bool); ok {
+		register = v
+	}
+	account := getString(args, "account")
+
+	client, err := drive.NewUploadClient(account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Drive client: %w", err)
+	}
+
+	// Hash while uploading, for the registry's duplicate detection
+	hasher := sha256.New()
+	uploaded, err := client.UploadFile(context.Background(), io.TeeReader(f, hasher), name, getString(args, "folder_id"), getString(args, "mime_type"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"file": uploaded,
+	}
+	if register && p.registry != nil {
+		summary, err := p.registry.RegisterFile(driveRegistration(uploaded, hex.EncodeToString(hasher.Sum(nil)), args["tags"]))
+		if err != nil {
+			result["registry_error"] = err.Error()
+		} else {
+			result["registry"] = summary
+		}
+	}
+	return textContent(drive.ToJSON(result)), nil
+}
+
+// driveRegistration builds the file_registry_register arguments for an
+// uploaded file. Its path is the Drive file ID, which unlike a name is
+// unique within the source.
+func driveRegistration(f *drive.UploadedFile, contentHash string, tags interface{}) map[string]interface{} {
+	args := map[string]interface{}{
+		"source":         "gdrive",
+		"path":           f.ID,
+		"filename":       f.Name,
+		"content_hash":   contentHash,
+		"source_file_id": f.ID,
+		"mime_type":      f.MimeType,
+		"size":           float64(f.Size),
+	}
+	if t, err := time.Parse(time.RFC3339, f.ModifiedTime); err == nil {
+		args["modified_at"] = t.UTC().Format(time.RFC3339)
+	}
+	if tags != nil {
+		args["tags"] = tags
+	}
+	return args
+}
@@ -0,0 +1,66 @@
+package google
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/diane-assistant/diane/mcp/tools/google/drive"
+)
+
+type fakeRegistry struct {
+	paths map[string]string
+}
+
+func (r *fakeRegistry) LocalPath(id string) (string, error) {
+	if path, ok := r.paths[id]; ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("file not found: %s", id)
+}
+
+func (r *fakeRegistry) RegisterFile(args map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": "registered"}, nil
+}
+
+func TestUploadToDriveResolvesSource(t *testing.T) {
+	p := NewProvider()
+	if _, err := p.Call("drive_upload", map[string]interface{}{"path": "/tmp/a", "file_id": "f1"}); err == nil || !strings.Contains(err.Error(), "exactly one") {
+		t.Errorf("expected path and file_id to be exclusive, got %v", err)
+	}
+	if _, err := p.Call("drive_upload", map[string]interface{}{"file_id": "f1"}); err == nil || !strings.Contains(err.Error(), "file_registry") {
+		t.Errorf("expected file_id to need the registry, got %v", err)
+	}
+
+	dir := t.TempDir()
+	p.SetFileRegistry(&fakeRegistry{paths: map[string]string{"dir": dir}})
+	if _, err := p.Call("drive_upload", map[string]interface{}{"file_id": "missing"}); err == nil || !strings.Contains(err.Error(), "failed to resolve file_id") {
+		t.Errorf("expected an unknown file_id to fail, got %v", err)
+	}
+	if _, err := p.Call("drive_upload", map[string]interface{}{"file_id": "dir"}); err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("expected a directory to be refused, got %v", err)
+	}
+}
+
+func TestDriveRegistration(t *testing.T) {
+	f := &drive.UploadedFile{FileInfo: drive.FileInfo{
+		ID:           "1AbC",
+		Name:         "report.pdf",
+		MimeType:     "application/pdf",
+		ModifiedTime: "2026-05-01T10:00:00.000Z",
+		Size:         2048,
+	}}
+	args := driveRegistration(f, "deadbeef", []interface{}{"uploaded"})
+	if args["source"] != "gdrive" || args["path"] != "1AbC" || args["source_file_id"] != "1AbC" {
+		t.Errorf("unexpected source/path: %v", args)
+	}
+	if args["filename"] != "report.pdf" || args["content_hash"] != "deadbeef" || args["size"] != float64(2048) {
+		t.Errorf("unexpected metadata: %v", args)
+	}
+	if args["modified_at"] != "2026-05-01T10:00:00Z" {
+		t.Errorf("modified_at = %v", args["modified_at"])
+	}
+	if tags, _ := args["tags"].([]interface{}); len(tags) != 1 {
+		t.Errorf("tags = %v", args["tags"])
+	}
+}
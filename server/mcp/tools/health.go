@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrBackendUnreachable marks a tool failure caused by a provider's external
+// backend (an API or service) not answering, as opposed to bad input
+var ErrBackendUnreachable = errors.New("backend unreachable")
+
+// WrapUnreachable rewrites err as "<provider> backend unreachable: ..." when
+// it is a network failure, so callers see why the tool failed and
+// HealthTracker can tell. Other errors are returned unchanged.
+func WrapUnreachable(provider string, err error) error {
+	if err == nil || errors.Is(err, ErrBackendUnreachable) || !isNetworkError(err) {
+		return err
+	}
+	return fmt.Errorf("%s %w: %w", provider, ErrBackendUnreachable, err)
+}
+
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.As(err, &opErr) ||
+		errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// DegradedAfter is how many consecutive unreachable-backend failures mark a
+// provider as degraded
+const DegradedAfter = 3
+
+// ProviderHealth is what a HealthTracker knows about one provider
+type ProviderHealth struct {
+	Degraded            bool
+	ConsecutiveFailures int
+	LastError           string
+	LastFailure         time.Time
+	// ToolsError is set while listing the provider's tools fails, which
+	// also marks it degraded
+	ToolsError string
+}
+
+// HealthTracker follows the outcome of each provider's tool calls so status
+// reports can flag a provider whose backend has gone away since startup.
+// Only ErrBackendUnreachable failures count; any success clears them.
+type HealthTracker struct {
+	mu          sync.Mutex
+	health      map[string]*ProviderHealth
+	toolsErrors map[string]string
+}
+
+// NewHealthTracker creates a HealthTracker with every provider healthy
+func NewHealthTracker() *HealthTracker {
+	return &HealthTracker{
+		health:      make(map[string]*ProviderHealth),
+		toolsErrors: make(map[string]string),
+	}
+}
+
+// Record notes the outcome of a call to provider
+func (t *HealthTracker) Record(provider string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		delete(t.health, provider)
+		return
+	}
+	if !errors.Is(err, ErrBackendUnreachable) {
+		return
+	}
+	h, ok := t.health[provider]
+	if !ok {
+		h = &ProviderHealth{}
+		t.health[provider] = h
+	}
+	h.ConsecutiveFailures++
+	h.LastError = err.Error()
+	h.LastFailure = time.Now()
+	h.Degraded = h.ConsecutiveFailures >= DegradedAfter
+}
+
+// RecordTools notes the outcome of listing provider's tools
+func (t *HealthTracker) RecordTools(provider string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		delete(t.toolsErrors, provider)
+		return
+	}
+	t.toolsErrors[provider] = err.Error()
+}
+
+// Health returns what is known about provider; the zero value means healthy
+func (t *HealthTracker) Health(provider string) ProviderHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var h ProviderHealth
+	if known, ok := t.health[provider]; ok {
+		h = *known
+	}
+	if msg, ok := t.toolsErrors[provider]; ok {
+		h.ToolsError = msg
+		h.Degraded = true
+	}
+	return h
+}
+
+// SafeTools calls a provider's Tools method, turning a panic into an error so
+// one broken provider can't take down tool discovery for the rest
+func SafeTools[T any](listTools func() []T) (list []T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			list, err = nil, fmt.Errorf("listing tools panicked: %v", r)
+		}
+	}()
+	return listTools(), nil
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWrapUnreachable(t *testing.T) {
+	if err := WrapUnreachable("weather", nil); err != nil {
+		t.Errorf("nil error wrapped as %v", err)
+	}
+	plain := errors.New("location is required")
+	if err := WrapUnreachable("weather", plain); err != plain {
+		t.Errorf("non-network error changed to %v", err)
+	}
+
+	_, netErr := http.Get("http://127.0.0.1:1/")
+	err := WrapUnreachable("file_registry", fmt.Errorf("search failed: %w", netErr))
+	if !errors.Is(err, ErrBackendUnreachable) || !strings.HasPrefix(err.Error(), "file_registry backend unreachable: ") {
+		t.Errorf("unexpected wrapped error: %v", err)
+	}
+	if again := WrapUnreachable("file_registry", err); again.Error() != err.Error() {
+		t.Errorf("wrapping twice changed the message to %q", again)
+	}
+}
+
+func TestHealthTracker(t *testing.T) {
+	tr := NewHealthTracker()
+	down := fmt.Errorf("places %w: connection refused", ErrBackendUnreachable)
+
+	tr.Record("places", errors.New("query is required"))
+	if h := tr.Health("places"); h.ConsecutiveFailures != 0 {
+		t.Error("argument errors should not count against a provider's health")
+	}
+
+	for i := 1; i < DegradedAfter; i++ {
+		tr.Record("places", down)
+	}
+	if tr.Health("places").Degraded {
+		t.Errorf("degraded after only %d failures", DegradedAfter-1)
+	}
+	tr.Record("places", down)
+	if h := tr.Health("places"); !h.Degraded || h.LastError != down.Error() {
+		t.Errorf("expected degraded with the last error, got %+v", h)
+	}
+	if tr.Health("weather").Degraded {
+		t.Error("other providers should be unaffected")
+	}
+
+	tr.Record("places", nil)
+	if tr.Health("places").Degraded {
+		t.Error("a successful call should clear degraded")
+	}
+}
+
+func TestHealthTrackerRecordTools(t *testing.T) {
+	tr := NewHealthTracker()
+	tr.RecordTools("google", errors.New("listing tools panicked: nil map"))
+	if h := tr.Health("google"); !h.Degraded || h.ToolsError == "" {
+		t.Errorf("expected degraded with a tools error, got %+v", h)
+	}
+
+	tr.Record("google", nil)
+	if !tr.Health("google").Degraded {
+		t.Error("a successful call should not clear a tools listing failure")
+	}
+	tr.RecordTools("google", nil)
+	if tr.Health("google").Degraded {
+		t.Error("a successful listing should clear degraded")
+	}
+}
+
+func TestSafeTools(t *testing.T) {
+	list, err := SafeTools(func() []Tool { return []Tool{{Name: "ok"}} })
+	if err != nil || len(list) != 1 {
+		t.Fatalf("SafeTools = %v, %v", list, err)
+	}
+
+	list, err = SafeTools(func() []Tool {
+		var m map[string][]Tool
+		m["x"] = nil
+		return nil
+	})
+	if err == nil || list != nil {
+		t.Fatalf("expected an error from a panicking provider, got %v, %v", list, err)
+	}
+	if !strings.Contains(err.Error(), "panicked") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+// Package httprequest provides a generic http_request tool for one-off REST
+// calls that don't warrant their own MCP server. It is disabled unless
+// configured, and only ever contacts hosts on an explicit allowlist.
+package httprequest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxResponseBytes = 64 * 1024
+	defaultTimeout          = 30 * time.Second
+	maxRedirects            = 5
+)
+
+var allowedMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+}
+
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// Tool represents an MCP tool definition
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// Config controls what the provider may do. Zero values fall back to the
+// package defaults, except AllowedHosts: with no hosts nothing is allowed.
+type Config struct {
+	// AllowedHosts are hostnames ("api.example.com"), wildcard subdomains
+	// ("*.example.com") or host:port pairs. Entries without a port only
+	// allow the scheme's default port.
+	AllowedHosts     []string
+	MaxResponseBytes int64
+	Timeout          time.Duration
+}
+
+// Provider implements the http_request tool
+type Provider struct {
+	allowed    []string
+	maxBytes   int64
+	httpClient *http.Client
+}
+
+// NewProvider creates a new http_request provider
+func NewProvider(cfg Config) *Provider {
+	p := &Provider{maxBytes: cfg.MaxResponseBytes}
+	for _, h := range cfg.AllowedHosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			p.allowed = append(p.allowed, h)
+		}
+	}
+	if p.maxBytes <= 0 {
+		p.maxBytes = defaultMaxResponseBytes
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	p.httpClient = &http.Client{
+		Timeout: timeout,
+		// Every redirect hop must stay on the allowlist too
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return p.checkURL(req.URL)
+		},
+	}
+	return p
+}
+
+// CheckDependencies verifies at least one host is allowed
+func (p *Provider) CheckDependencies() error {
+	if len(p.allowed) == 0 {
+		return fmt.Errorf("http_request.allowed_hosts is empty in config.json")
+	}
+	return nil
+}
+
+// Tools returns the list of HTTP tools
+func (p *Provider) Tools() []Tool {
+	return []Tool{
+		{
+			Name: "http_request",
+			Description: fmt.Sprintf("Make an HTTP request to an allowlisted host and return the status, headers and body. "+
+				"Only these hosts may be contacted: %s. Response bodies are truncated to %d bytes.",
+				strings.Join(p.allowed, ", "), p.maxBytes),
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []string{"url"},
+				"properties": map[string]interface{}{
+					"method": map[string]interface{}{
+						"type":        "string",
+						"description": "HTTP method: GET (default), HEAD, POST, PUT, PATCH or DELETE",
+					},
+					"url": map[string]interface{}{
+						"type":        "string",
+						"description": "Full http:// or https:// URL on an allowed host",
+					},
+					"headers": map[string]interface{}{
+						"type":                 "object",
+						"description":          "Request headers as name/value pairs",
+						"additionalProperties": map[string]interface{}{"type": "string"},
+					},
+					"body": map[string]interface{}{
+						"type":        "string",
+						"description": "Request body (e.g. a JSON document; set Content-Type in headers)",
+					},
+				},
+			},
+		},
+	}
+}
+
+// HasTool checks if a tool name belongs to this provider
+func (p *Provider) HasTool(name string) bool {
+	return name == "http_request"
+}
+
+// Call executes an HTTP tool
+func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+	switch name {
+	case "http_request":
+		return p.request(args)
+	default:
+		return nil, fmt.Errorf("unknown tool: %s", name)
+	}
+}
+
+func (p *Provider) request(args map[string]interface{}) (interface{}, error) {
+	rawURL, _ := args["url"].(string)
+	if rawURL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	method, _ := args["method"].(string)
+	method = strings.ToUpper(method)
+	if method == "" {
+		method = "GET"
+	}
+	if !allowedMethods[method] {
+		return nil, fmt.Errorf("unsupported method %q", method)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if err := p.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if b, _ := args["body"].(string); b != "" {
+		body = strings.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if headers, ok := args["headers"].(map[string]interface{}); ok {
+		for k, v := range headers {
+			if s, ok := v.(string); ok {
+				req.Header.Set(k, s)
+			}
+		}
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, p.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	truncated := int64(len(data)) > p.maxBytes
+	if truncated {
+		data = data[:p.maxBytes]
+	}
+
+	headers := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
+
+	result := map[string]interface{}{
+		"status":      resp.StatusCode,
+		"status_text": http.StatusText(resp.StatusCode),
+		"url":         resp.Request.URL.String(),
+		"headers":     headers,
+		"truncated":   truncated,
+	}
+	if utf8.Valid(data) {
+		result["body"] = string(data)
+	} else {
+		result["body_base64"] = base64.StdEncoding.EncodeToString(data)
+	}
+	return textContent(result), nil
+}
+
+// checkURL rejects anything that isn't plain http(s) to an allowed host
+func (p *Provider) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("only http and https URLs are allowed")
+	}
+	if u.User != nil {
+		return fmt.Errorf("credentials in the URL are not allowed; use an Authorization header")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("url has no host")
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPorts[u.Scheme]
+	}
+	if !hostAllowed(p.allowed, host, port, u.Scheme) {
+		return fmt.Errorf("host %s is not in http_request.allowed_hosts", u.Host)
+	}
+	return nil
+}
+
+// AllowsURL reports whether u's host and port are on an allowlist written
+// like Config.AllowedHosts
+func AllowsURL(allowed []string, u *url.URL) bool {
+	port := u.Port()
+	if port == "" {
+		port = defaultPorts[u.Scheme]
+	}
+	return hostAllowed(allowed, strings.ToLower(u.Hostname()), port, u.Scheme)
+}
+
+// hostAllowed matches host and port against the allowlist
+func hostAllowed(allowed []string, host, port, scheme string) bool {
+	defaultPort := port == defaultPorts[scheme]
+	for _, entry := range allowed {
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort == "" && !defaultPort {
+			continue
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		if entryHost == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(entryHost, "*."); ok && strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func textContent(data interface{}) map[string]interface{} {
+	jsonBytes, _ := json.MarshalIndent(data, "", "  ")
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": string(jsonBytes),
+			},
+		},
+	}
+}
@@ -3,12 +3,16 @@ package infrastructure
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 // --- Configuration ---
@@ -23,7 +27,7 @@ func getCloudflareConfig() (*cloudflareConfig, error) {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	configPath := filepath.Join(home, ".diane", "secrets", "cloudflare-config.json")
+	configPath := filepath.Join(home, config.DirName(), "secrets", "cloudflare-config.json")
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("Cloudflare config not found at %s. Please create it with your API token", configPath)
@@ -234,6 +238,21 @@ func (p *Provider) CheckDependencies() error {
 	return err
 }
 
+// CheckBackend implements tools.BackendChecker using Cloudflare's token
+// verification endpoint
+func (p *Provider) CheckBackend(ctx context.Context) error {
+	config, err := getCloudflareConfig()
+	if err != nil {
+		return fmt.Errorf("cloudflare %w: %v", tools.ErrNotConfigured, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.cloudflare.com/client/v4/user/tokens/verify", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+config.APIToken)
+	return tools.CheckHTTP("cloudflare", req)
+}
+
 // Tools returns all infrastructure tools
 func (p *Provider) Tools() []Tool {
 	return []Tool{
@@ -342,7 +361,9 @@ func (p *Provider) HasTool(name string) bool {
 }
 
 // Call executes a tool by name
-func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+func (p *Provider) Call(name string, args map[string]interface{}) (result interface{}, err error) {
+	defer func() { err = tools.WrapUnreachable(p.Name(), err) }()
+
 	switch name {
 	case "cloudflare_list_zones":
 		return p.listZones(args)
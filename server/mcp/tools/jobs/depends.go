@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/diane-assistant/diane/internal/cron"
+	"github.com/diane-assistant/diane/internal/db"
+	"github.com/diane-assistant/diane/internal/store"
+)
+
+// CheckDependsOn checks that making job name depend on parent keeps the
+// jobs' dependencies valid: parent must exist and the jobs must not end up
+// depending on each other in a cycle. An empty parent is always valid.
+func CheckDependsOn(ctx context.Context, jobStore store.JobStore, name, parent string) error {
+	if parent == "" {
+		return nil
+	}
+	jobs, err := jobStore.ListJobs(ctx, false)
+	if err != nil {
+		return err
+	}
+	parents := make(map[string]string, len(jobs)+1)
+	for _, j := range jobs {
+		parents[j.Name] = j.DependsOn
+	}
+	parents[name] = parent
+	// A job whose parent was deleted never fires, so it can't be in a cycle
+	for n, p := range parents {
+		if _, ok := parents[p]; !ok && n != name {
+			parents[n] = ""
+		}
+	}
+	return cron.CheckDependencies(parents)
+}
+
+// FireDependents runs the jobs that depend on parent once a run of it has
+// finished. Only retries call it, once the new run ends: job_retry and the
+// daemon's RetryJobExecution; scheduled runs do not fire dependents. When
+// finished succeeded, each enabled, unpaused shell job that depends on
+// parent is run and recorded as an execution; otherwise each is recorded as
+// skipped. Either way the dependents' own dependents follow, so a failure
+// skips the whole chain below it. It returns the executions it recorded, in order.
+func FireDependents(ctx context.Context, jobStore store.JobStore, executionStore store.ExecutionStore, parent *db.Job, finished *db.JobExecution) ([]*db.JobExecution, error) {
+	jobs, err := jobStore.ListJobs(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var recorded []*db.JobExecution
+	for _, dep := range jobs {
+		if dep.DependsOn != parent.Name || dep.ActionType == "agent" || dep.IsPaused(finished.StartedAt) {
+			continue
+		}
+
+		var e *db.JobExecution
+		if Failed(finished) {
+			e, err = skipDependent(ctx, executionStore, dep, parent)
+		} else {
+			e, err = runDependent(ctx, executionStore, dep, finished)
+		}
+		if err != nil {
+			slog.Warn("Failed to fire dependent job", "job", dep.Name, "parent", parent.Name, "error", err)
+			continue
+		}
+		recorded = append(recorded, e)
+
+		more, err := FireDependents(ctx, jobStore, executionStore, dep, e)
+		if err != nil {
+			return recorded, err
+		}
+		recorded = append(recorded, more...)
+	}
+	return recorded, nil
+}
+
+// ParentOutputEnv is the environment variable a dependent job's command
+// gets its parent run's structured output in, as JSON, when the parent's
+// output format is json
+const ParentOutputEnv = "DIANE_PARENT_OUTPUT"
+
+// runDependent runs a dependent job's command after its parent's run
+// finished and records the execution. When the job's concurrency policy is
+// skip and a previous run is still going, the run is recorded as skipped.
+func runDependent(ctx context.Context, executionStore store.ExecutionStore, job *db.Job, finished *db.JobExecution) (*db.JobExecution, error) {
+	release, err := runLimiter.Acquire(ctx, job.ID, job.Concurrency)
+	if errors.Is(err, cron.ErrStillRunning) {
+		id, err := executionStore.RecordSkippedExecution(ctx, job.ID, err.Error())
+		if err != nil {
+			return nil, err
+		}
+		return executionStore.GetJobExecution(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	id, err := executionStore.CreateJobExecution(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	opts := RunOptions(job)
+	if finished.Output != nil {
+		data, err := json.Marshal(finished.Output)
+		if err != nil {
+			return nil, err
+		}
+		env := make(map[string]string, len(opts.Env)+1)
+		for k, v := range opts.Env {
+			env[k] = v
+		}
+		env[ParentOutputEnv] = string(data)
+		opts.Env = env
+	}
+	return execute(ctx, executionStore, job, id, opts)
+}
+
+// skipDependent records that a dependent job didn't run because its parent
+// didn't succeed
+func skipDependent(ctx context.Context, executionStore store.ExecutionStore, job, parent *db.Job) (*db.JobExecution, error) {
+	id, err := executionStore.RecordSkippedExecution(ctx, job.ID, fmt.Sprintf("parent job '%s' did not succeed", parent.Name))
+	if err != nil {
+		return nil, err
+	}
+	return executionStore.GetJobExecution(ctx, id)
+}
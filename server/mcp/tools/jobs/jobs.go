@@ -7,7 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/internal/cron"
 	"github.com/diane-assistant/diane/internal/db"
 	"github.com/diane-assistant/diane/internal/emergent"
 	"github.com/diane-assistant/diane/internal/store"
@@ -84,9 +88,17 @@ func (p *Provider) Tools() []Tool {
 			Description: "Add a new cron job with schedule and command",
 			InputSchema: tools.ObjectSchema(
 				map[string]interface{}{
-					"name":     tools.StringProperty("Unique name for the job", true),
-					"schedule": tools.StringProperty("Cron schedule expression (e.g., '* * * * *' for every minute)", true),
-					"command":  tools.StringProperty("Shell command to execute", true),
+					"name":             tools.StringProperty("Unique name for the job", true),
+					"schedule":         tools.StringProperty("Cron schedule: 5 fields (minute hour day-of-month month day-of-week, e.g. '*/15 * * * *') or 6 with a leading seconds field (e.g. '*/30 * * * * *'); @yearly/@monthly/@weekly/@daily/@hourly; or plain English after @natural (e.g. '@natural every weekday at 9am'). Quartz-style '?' and year fields are rejected", true),
+					"command":          tools.StringProperty("Shell command to execute", true),
+					"max_output_bytes": tools.IntProperty("Cap on the stdout and stderr stored per run, cut from the middle when exceeded (0 = the global limit)", 0),
+					"concurrency":      tools.StringProperty("What to do when the job fires while its previous run is still going: skip (default; recorded in job_logs as skipped), queue (run once it finishes) or allow (run alongside it)", false),
+					"env":              map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}, "description": "Environment variables to set for the command, as an object of name to string value (added to the daemon's environment)"},
+					"timeout_seconds":  tools.IntProperty("Maximum runtime in seconds; a run still going then has its whole process group killed and is recorded as failed (0 = no limit)", 0),
+					"workdir":          tools.StringProperty("Absolute directory to run the command in (default: the user's home directory)", false),
+					"output_format":    tools.StringProperty("How a successful run's stdout is read: text (default) or json, which parses it and stores the data on the execution (shown by job_logs and passed to dependent jobs in $DIANE_PARENT_OUTPUT); stdout that isn't valid JSON fails the run", false),
+					"notify_template":  tools.StringProperty("Name of a notify template (see `diane notify template list`) to send to Discord or Home Assistant whenever a run fails; it renders with job_name, exit_code, error, output (the tail of the run's output) and timestamp", false),
+					"depends_on":       tools.StringProperty("Name of a job this one also runs after, besides its own schedule. Only runs started by job_retry fire dependents; scheduled runs do not. A successful retried run fires this job and a failed one skips it (recorded in job_logs as skipped)", false),
 				},
 				[]string{"name", "schedule", "command"},
 			),
@@ -142,6 +154,16 @@ func (p *Provider) Tools() []Tool {
 				nil,
 			),
 		},
+		{
+			Name:        "job_retry",
+			Description: "Re-run the job behind a failed or skipped execution (from job_logs), recording a new execution linked to it. Uses the job's current command and waits for it to finish.",
+			InputSchema: tools.ObjectSchema(
+				map[string]interface{}{
+					"execution_id": tools.IntProperty("ID of the failed execution to retry", 0),
+				},
+				[]string{"execution_id"},
+			),
+		},
 		{
 			Name:        "server_status",
 			Description: "Get Diane server status and statistics",
@@ -179,6 +201,8 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.jobResume()
 	case "job_logs":
 		return p.jobLogs(args)
+	case "job_retry":
+		return p.jobRetry(args)
 	case "server_status":
 		return p.serverStatus()
 	default:
@@ -207,19 +231,185 @@ func (p *Provider) jobAdd(args map[string]interface{}) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	dependsOn := tools.GetString(args, "depends_on")
 	command, err := tools.GetStringRequired(args, "command")
 	if err != nil {
 		return nil, err
 	}
+	maxOutputBytes := tools.GetInt(args, "max_output_bytes", 0)
+	if maxOutputBytes < 0 {
+		return nil, fmt.Errorf("max_output_bytes must not be negative")
+	}
+	timeoutSeconds := tools.GetInt(args, "timeout_seconds", 0)
+	if timeoutSeconds < 0 {
+		return nil, fmt.Errorf("timeout_seconds must not be negative")
+	}
+	concurrency, err := cron.ParseConcurrency(tools.GetString(args, "concurrency"))
+	if err != nil {
+		return nil, err
+	}
+	outputFormat, err := cron.ParseOutputFormat(tools.GetString(args, "output_format"))
+	if err != nil {
+		return nil, err
+	}
+	env, err := ParseEnv(args["env"])
+	if err != nil {
+		return nil, err
+	}
+	workdir := tools.GetString(args, "workdir")
+	if err := cron.ValidateRunEnvironment(env, workdir); err != nil {
+		return nil, err
+	}
+	notifyTemplate := tools.GetString(args, "notify_template")
+
+	schedule, phrase, err := ResolveSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+	nextRuns, err := ValidateSchedule(schedule, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
 	ctx := context.Background()
+	if err := CheckDependsOn(ctx, p.jobStore, name, dependsOn); err != nil {
+		return nil, err
+	}
 	job, err := p.jobStore.CreateJob(ctx, name, command, schedule)
 	if err != nil {
 		return nil, err
 	}
+	if phrase != "" {
+		if err := p.jobStore.SetScheduleText(ctx, job.ID, phrase); err != nil {
+			return nil, err
+		}
+		job.ScheduleText = phrase
+	}
+	if maxOutputBytes > 0 {
+		if err := p.jobStore.SetMaxOutputBytes(ctx, job.ID, maxOutputBytes); err != nil {
+			return nil, err
+		}
+		job.MaxOutputBytes = maxOutputBytes
+	}
+	if err := p.jobStore.SetConcurrency(ctx, job.ID, concurrency); err != nil {
+		return nil, err
+	}
+	job.Concurrency = concurrency
+	if len(env) > 0 || workdir != "" {
+		if err := p.jobStore.SetRunEnvironment(ctx, job.ID, env, workdir); err != nil {
+			return nil, err
+		}
+		job.Env, job.Workdir = env, workdir
+	}
+	if timeoutSeconds > 0 {
+		if err := p.jobStore.SetTimeout(ctx, job.ID, timeoutSeconds); err != nil {
+			return nil, err
+		}
+		job.TimeoutSeconds = timeoutSeconds
+	}
+	if outputFormat != "" {
+		if err := p.jobStore.SetOutputFormat(ctx, job.ID, outputFormat); err != nil {
+			return nil, err
+		}
+		job.OutputFormat = outputFormat
+	}
+	if notifyTemplate != "" {
+		if err := p.jobStore.SetNotifyTemplate(ctx, job.ID, notifyTemplate); err != nil {
+			return nil, err
+		}
+		job.NotifyTemplate = notifyTemplate
+	}
+	if dependsOn != "" {
+		if err := p.jobStore.SetDependsOn(ctx, job.ID, dependsOn); err != nil {
+			return nil, err
+		}
+		job.DependsOn = dependsOn
+	}
 
+	runs := FormatNextRuns(nextRuns)
+	if dependsOn != "" {
+		runs = fmt.Sprintf("Also runs after each successful retried run of '%s'\n\n%s", dependsOn, runs)
+	}
 	jobJSON, _ := json.MarshalIndent(job, "", "  ")
-	return tools.TextContent(fmt.Sprintf("Job '%s' created successfully\n\n%s", name, string(jobJSON))), nil
+	return tools.TextContent(fmt.Sprintf("Job '%s' created successfully\n\n%s%s\n%s", name, FormatResolved(schedule, phrase), runs, string(jobJSON))), nil
+}
+
+// ResolveSchedule turns an "@natural ..." schedule into a cron expression,
+// returning it along with the phrase. Other schedules are returned as is
+// with an empty phrase.
+func ResolveSchedule(schedule string) (string, string, error) {
+	if !cron.IsNatural(schedule) {
+		return schedule, "", nil
+	}
+	resolved, err := cron.ParseNatural(schedule)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	phrase := strings.TrimSpace(strings.TrimSpace(schedule)[len(cron.NaturalPrefix):])
+	return resolved, phrase, nil
+}
+
+// ParseEnv reads the env argument of job_add: an object whose values are
+// all strings. A missing argument gives a nil map.
+func ParseEnv(v interface{}) (map[string]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("env must be an object of variable names to string values")
+	}
+	env := make(map[string]string, len(obj))
+	for k, val := range obj {
+		s, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("env value for %s must be a string", k)
+		}
+		env[k] = s
+	}
+	return env, nil
+}
+
+// FormatResolved notes the cron expression a natural-language schedule was
+// resolved to, or returns "" for a plain cron schedule
+func FormatResolved(schedule, phrase string) string {
+	if phrase == "" {
+		return ""
+	}
+	return fmt.Sprintf("Resolved %q to cron: %s\n\n", phrase, schedule)
+}
+
+// ValidateSchedule parses a cron schedule and returns the next three times
+// after now that it fires. Expressions that don't parse, or that never fire
+// (e.g. February 30th), are rejected.
+func ValidateSchedule(schedule string, now time.Time) ([]time.Time, error) {
+	parsed, err := cron.Parse(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	nextRuns := parsed.NextN(now, 3)
+	if len(nextRuns) == 0 {
+		return nil, fmt.Errorf("invalid schedule %q: it never fires", schedule)
+	}
+	return nextRuns, nil
+}
+
+// FormatNextRuns lists run times for a tool response, with seconds when a
+// schedule fires mid-minute
+func FormatNextRuns(times []time.Time) string {
+	layout := "Mon 2006-01-02 15:04 MST"
+	for _, t := range times {
+		if t.Second() != 0 {
+			layout = "Mon 2006-01-02 15:04:05 MST"
+			break
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("Next runs:\n")
+	for _, t := range times {
+		fmt.Fprintf(&sb, "  - %s\n", t.Format(layout))
+	}
+	return sb.String()
 }
 
 func (p *Provider) jobEnable(args map[string]interface{}) (interface{}, error) {
@@ -343,7 +533,51 @@ func (p *Provider) jobLogs(args map[string]interface{}) (interface{}, error) {
 		return nil, err
 	}
 
-	return tools.JSONContent(executions)
+	return tools.JSONContent(WithWorkdirs(ctx, p.jobStore, executions))
+}
+
+// LogEntry is a job_logs entry: an execution along with the directory its
+// job currently runs in
+type LogEntry struct {
+	*db.JobExecution
+	Workdir string
+}
+
+// WithWorkdirs pairs each execution with its job's effective working
+// directory. Executions whose job can't be found get an empty Workdir.
+func WithWorkdirs(ctx context.Context, jobStore store.JobStore, executions []*db.JobExecution) []LogEntry {
+	workdirs := make(map[int64]string)
+	entries := make([]LogEntry, 0, len(executions))
+	for _, e := range executions {
+		workdir, ok := workdirs[e.JobID]
+		if !ok {
+			if job, err := jobStore.GetJob(ctx, e.JobID); err == nil {
+				workdir = cron.EffectiveWorkdir(job.Workdir)
+			}
+			workdirs[e.JobID] = workdir
+		}
+		entries = append(entries, LogEntry{JobExecution: e, Workdir: workdir})
+	}
+	return entries
+}
+
+func (p *Provider) jobRetry(args map[string]interface{}) (interface{}, error) {
+	id := tools.GetInt(args, "execution_id", 0)
+	if id <= 0 {
+		return nil, fmt.Errorf("execution_id is required")
+	}
+
+	ctx := context.Background()
+	execution, err := Retry(ctx, p.jobStore, p.executionStore, int64(id))
+	if err != nil {
+		return nil, err
+	}
+	if job, err := p.jobStore.GetJob(ctx, execution.JobID); err == nil {
+		go FireDependents(ctx, p.jobStore, p.executionStore, job, execution)
+	}
+
+	executionJSON, _ := json.MarshalIndent(execution, "", "  ")
+	return tools.TextContent(FormatRetry(execution) + "\n\n" + string(executionJSON)), nil
 }
 
 func (p *Provider) serverStatus() (interface{}, error) {
@@ -352,7 +586,7 @@ func (p *Provider) serverStatus() (interface{}, error) {
 		return nil, err
 	}
 
-	pidFile := filepath.Join(home, ".diane", "server.pid")
+	pidFile := filepath.Join(home, config.DirName(), "server.pid")
 	pidBytes, err := os.ReadFile(pidFile)
 	if err != nil {
 		return tools.TextContent("Server is not running"), nil
@@ -435,6 +669,9 @@ func (p *Provider) promptCreateScheduledTask(args map[string]string) []tools.Pro
    - "every day at midnight" = "0 0 * * *"
    - "every monday at 9am" = "0 9 * * 1"
    - "every 5 minutes" = "*/5 * * * *"
+   - "every 30 seconds" = "*/30 * * * * *" (a sixth field, at the front, is seconds)
+   If unsure, pass the frequency as "@natural <description>" (e.g. "@natural every weekday at 9am")
+   and job_add will resolve it to cron for you.
    
 2. Generate a meaningful job name from the task description (lowercase, hyphens, no spaces)
 
@@ -445,7 +682,7 @@ func (p *Provider) promptCreateScheduledTask(args map[string]string) []tools.Pro
 
 4. After creating, use job_list to verify the job was created correctly
 
-5. Explain when the job will next run`, taskDesc, frequency, command),
+5. Explain when the job will next run, using the next run times job_add returns`, taskDesc, frequency, command),
 			},
 		},
 	}
@@ -512,7 +749,9 @@ func (p *Provider) promptTroubleshootFailures(args map[string]string) []tools.Pr
    - Specific fixes to try
    - Commands to verify the fix
 
-5. If there are no failures, confirm the jobs are healthy and report success rate`, jobFilter, limit, func() string {
+5. Once a failure's cause is fixed, re-run that execution with job_retry
+
+6. If there are no failures, confirm the jobs are healthy and report success rate`, jobFilter, limit, func() string {
 					if jobName != "" {
 						return fmt.Sprintf(" and job_name='%s'", jobName)
 					}
@@ -2,12 +2,14 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/diane-assistant/diane/internal/cron"
 	"github.com/diane-assistant/diane/internal/db"
 )
 
@@ -97,8 +99,9 @@ func (s *mockJobStore) UpdateJobFull(_ context.Context, id int64, command, sched
 	if command != nil {
 		j.Command = *command
 	}
-	if schedule != nil {
+	if schedule != nil && *schedule != j.Schedule {
 		j.Schedule = *schedule
+		j.ScheduleText = ""
 	}
 	if enabled != nil {
 		j.Enabled = *enabled
@@ -107,6 +110,109 @@ func (s *mockJobStore) UpdateJobFull(_ context.Context, id int64, command, sched
 	return nil
 }
 
+func (s *mockJobStore) SetScheduleText(_ context.Context, id int64, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.ScheduleText = text
+	return nil
+}
+
+func (s *mockJobStore) SetMaxOutputBytes(_ context.Context, id int64, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.MaxOutputBytes = n
+	return nil
+}
+
+func (s *mockJobStore) SetConcurrency(_ context.Context, id int64, policy string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.Concurrency = policy
+	return nil
+}
+
+func (s *mockJobStore) SetRunEnvironment(_ context.Context, id int64, env map[string]string, workdir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.Env = env
+	j.Workdir = workdir
+	return nil
+}
+
+func (s *mockJobStore) SetTimeout(_ context.Context, id int64, seconds int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.TimeoutSeconds = seconds
+	return nil
+}
+
+func (s *mockJobStore) SetDependsOn(_ context.Context, id int64, parent string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.DependsOn = parent
+	return nil
+}
+
+func (s *mockJobStore) SetOutputFormat(_ context.Context, id int64, format string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.OutputFormat = format
+	return nil
+}
+
+func (s *mockJobStore) SetNotifyTemplate(_ context.Context, id int64, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	j.NotifyTemplate = name
+	return nil
+}
+
+func (s *mockJobStore) SetPaused(_ context.Context, id int64, paused bool, until *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: id=%d", id)
+	}
+	if !paused {
+		until = nil
+	}
+	j.Paused, j.PausedUntil = paused, until
+	return nil
+}
+
 func (s *mockJobStore) DeleteJob(_ context.Context, id int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -114,19 +220,81 @@ func (s *mockJobStore) DeleteJob(_ context.Context, id int64) error {
 	return nil
 }
 
-type mockExecutionStore struct{}
+type mockExecutionStore struct {
+	mu         sync.Mutex
+	executions []*db.JobExecution
+}
 
-func (s *mockExecutionStore) CreateJobExecution(_ context.Context, _ int64) (int64, error) {
-	return 1, nil
+func (s *mockExecutionStore) CreateJobExecution(_ context.Context, jobID int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &db.JobExecution{ID: int64(len(s.executions) + 1), JobID: jobID, StartedAt: time.Now()}
+	s.executions = append(s.executions, e)
+	return e.ID, nil
 }
-func (s *mockExecutionStore) UpdateJobExecution(_ context.Context, _ int64, _ int, _, _ string, _ error) error {
+func (s *mockExecutionStore) CreateRetryExecution(_ context.Context, jobID, retryOf int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &db.JobExecution{ID: int64(len(s.executions) + 1), JobID: jobID, StartedAt: time.Now(), RetryOf: &retryOf}
+	s.executions = append(s.executions, e)
+	return e.ID, nil
+}
+func (s *mockExecutionStore) UpdateJobExecution(_ context.Context, id int64, exitCode int, stdout, stderr string, execErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.executions {
+		if e.ID == id {
+			now := time.Now()
+			e.EndedAt, e.ExitCode, e.Stdout, e.Stderr = &now, &exitCode, stdout, stderr
+			if execErr != nil {
+				msg := execErr.Error()
+				e.Error = &msg
+			}
+		}
+	}
+	return nil
+}
+func (s *mockExecutionStore) SetExecutionOutput(_ context.Context, id int64, output any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.executions {
+		if e.ID == id {
+			e.Output = output
+		}
+	}
 	return nil
 }
-func (s *mockExecutionStore) GetJobExecution(_ context.Context, _ int64) (*db.JobExecution, error) {
+func (s *mockExecutionStore) RecordSkippedExecution(_ context.Context, jobID int64, reason string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	e := &db.JobExecution{
+		ID: int64(len(s.executions) + 1), JobID: jobID, StartedAt: now, EndedAt: &now,
+		Error: &reason, Status: db.ExecutionStatusSkipped,
+	}
+	s.executions = append(s.executions, e)
+	return e.ID, nil
+}
+func (s *mockExecutionStore) GetJobExecution(_ context.Context, id int64) (*db.JobExecution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.executions {
+		if e.ID == id {
+			return e, nil
+		}
+	}
 	return nil, fmt.Errorf("not found")
 }
-func (s *mockExecutionStore) ListJobExecutions(_ context.Context, _ *int64, _, _ int) ([]*db.JobExecution, error) {
-	return nil, nil
+func (s *mockExecutionStore) ListJobExecutions(_ context.Context, jobID *int64, _, _ int) ([]*db.JobExecution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*db.JobExecution
+	for _, e := range s.executions {
+		if jobID == nil || e.JobID == *jobID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
 }
 func (s *mockExecutionStore) DeleteOldExecutions(_ context.Context, _ int) (int64, error) {
 	return 0, nil
@@ -153,6 +321,7 @@ func TestProviderTools(t *testing.T) {
 		"job_pause",
 		"job_resume",
 		"job_logs",
+		"job_retry",
 		"server_status",
 	}
 
@@ -187,6 +356,7 @@ func TestHasTool(t *testing.T) {
 		{"job_pause", true},
 		{"job_resume", true},
 		{"job_logs", true},
+		{"job_retry", true},
 		{"server_status", true},
 		{"unknown_tool", false},
 	}
@@ -385,6 +555,613 @@ func TestJobAddMissingArgs(t *testing.T) {
 	}
 }
 
+func TestJobAddInvalidSchedule(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	_, err := p.Call("job_add", map[string]interface{}{
+		"name":     "typo",
+		"schedule": "* * * *",
+		"command":  "echo test",
+	})
+	if err == nil || !contains(err.Error(), "expected 5 fields") {
+		t.Fatalf("expected a descriptive schedule error, got %v", err)
+	}
+
+	result, _ := p.Call("job_list", nil)
+	if containsTextResult(result, "typo") {
+		t.Error("a job with an invalid schedule should not be stored")
+	}
+}
+
+func TestJobAddShowsNextRuns(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	result, err := p.Call("job_add", map[string]interface{}{
+		"name":     "hourly",
+		"schedule": "0 * * * *",
+		"command":  "echo test",
+	})
+	if err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	if !containsTextResult(result, "Next runs:") {
+		t.Error("expected the next run times in the result")
+	}
+}
+
+func TestJobAddNaturalSchedule(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	result, err := p.Call("job_add", map[string]interface{}{
+		"name":     "standup",
+		"schedule": "@natural every weekday at 9am",
+		"command":  "echo standup",
+	})
+	if err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	if !containsTextResult(result, "0 9 * * 1-5") {
+		t.Error("expected the resolved cron expression in the result")
+	}
+
+	job, err := p.jobStore.GetJobByName(context.Background(), "standup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Schedule != "0 9 * * 1-5" || job.ScheduleText != "every weekday at 9am" {
+		t.Errorf("expected both the cron expression and the phrase stored, got %q / %q", job.Schedule, job.ScheduleText)
+	}
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":     "vague",
+		"schedule": "@natural now and then",
+		"command":  "echo",
+	}); err == nil {
+		t.Error("expected an unrecognised phrase to be rejected")
+	}
+}
+
+func TestJobAddMaxOutputBytes(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":             "chatty",
+		"schedule":         "0 * * * *",
+		"command":          "yes | head -n 100000",
+		"max_output_bytes": float64(4096),
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	job, err := p.jobStore.GetJobByName(context.Background(), "chatty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.MaxOutputBytes != 4096 {
+		t.Errorf("expected max_output_bytes 4096, got %d", job.MaxOutputBytes)
+	}
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":             "negative",
+		"schedule":         "0 * * * *",
+		"command":          "echo",
+		"max_output_bytes": float64(-1),
+	}); err == nil {
+		t.Error("expected a negative limit to be rejected")
+	}
+}
+
+func TestJobAddConcurrency(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":     "default",
+		"schedule": "* * * * *",
+		"command":  "sleep 120",
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":        "overlapping",
+		"schedule":    "* * * * *",
+		"command":     "sleep 120",
+		"concurrency": "allow",
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	ctx := context.Background()
+	for name, want := range map[string]string{"default": "skip", "overlapping": "allow"} {
+		job, err := p.jobStore.GetJobByName(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Concurrency != want {
+			t.Errorf("%s: concurrency = %q, want %q", name, job.Concurrency, want)
+		}
+	}
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":        "bad",
+		"schedule":    "* * * * *",
+		"command":     "echo",
+		"concurrency": "sometimes",
+	}); err == nil {
+		t.Error("expected an unknown concurrency policy to be rejected")
+	}
+}
+
+func TestRunsObeyConcurrency(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, args := range []map[string]interface{}{
+		{"name": "backup", "schedule": "@daily", "command": "echo backup"},
+		{"name": "upload", "schedule": "@weekly", "command": "echo uploaded", "depends_on": "backup"},
+		{"name": "sync", "schedule": "@daily", "command": "echo synced", "concurrency": "queue"},
+	} {
+		if _, err := p.Call("job_add", args); err != nil {
+			t.Fatalf("job_add failed: %v", err)
+		}
+	}
+	backup, _ := p.jobStore.GetJobByName(ctx, "backup")
+	upload, _ := p.jobStore.GetJobByName(ctx, "upload")
+	syncJob, _ := p.jobStore.GetJobByName(ctx, "sync")
+
+	// While a run of upload holds it, skip refuses a retry and records a
+	// dependent run as skipped
+	release, err := runLimiter.Acquire(ctx, upload.ID, upload.Concurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skippedID, _ := p.executionStore.RecordSkippedExecution(ctx, upload.ID, "parent job 'backup' did not succeed")
+	if _, err := Retry(ctx, p.jobStore, p.executionStore, skippedID); !errors.Is(err, cron.ErrStillRunning) {
+		t.Errorf("expected the retry to be refused, got %v", err)
+	}
+	code := 0
+	now := time.Now()
+	succeeded := &db.JobExecution{JobID: backup.ID, StartedAt: now, EndedAt: &now, ExitCode: &code}
+	fired, err := FireDependents(ctx, p.jobStore, p.executionStore, backup, succeeded)
+	if err != nil {
+		t.Fatalf("FireDependents: %v", err)
+	}
+	if len(fired) != 1 || fired[0].Status != db.ExecutionStatusSkipped || fired[0].Error == nil || *fired[0].Error != cron.ErrStillRunning.Error() {
+		t.Fatalf("expected upload to be skipped, got %+v", fired)
+	}
+	release()
+	if e, err := Retry(ctx, p.jobStore, p.executionStore, skippedID); err != nil || e.Stdout != "uploaded\n" {
+		t.Fatalf("expected the retry to run once upload is free, got %+v, %v", e, err)
+	}
+
+	// queue waits for the run holding the job to finish
+	release, err = runLimiter.Acquire(ctx, syncJob.ID, syncJob.Concurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skippedID, _ = p.executionStore.RecordSkippedExecution(ctx, syncJob.ID, "previous run still in progress")
+	done := make(chan error, 1)
+	go func() {
+		_, err := Retry(ctx, p.jobStore, p.executionStore, skippedID)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("expected the retry to wait, it finished with %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("queued retry failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued retry didn't run after the previous run finished")
+	}
+}
+
+func TestJobAddEnvironment(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":     "deploy",
+		"schedule": "@daily",
+		"command":  `echo "$PWD $STAGE"`,
+		"env":      map[string]interface{}{"STAGE": "prod"},
+		"workdir":  dir,
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	ctx := context.Background()
+	job, err := p.jobStore.GetJobByName(ctx, "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Env["STAGE"] != "prod" || job.Workdir != dir {
+		t.Errorf("got env %v, workdir %q", job.Env, job.Workdir)
+	}
+
+	// A retry runs the command with the job's environment, and job_logs
+	// shows where it ran
+	skippedID, err := p.executionStore.RecordSkippedExecution(ctx, job.ID, "previous run still in progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Call("job_retry", map[string]interface{}{"execution_id": float64(skippedID)}); err != nil {
+		t.Fatalf("job_retry failed: %v", err)
+	}
+	executions, _ := p.executionStore.ListJobExecutions(ctx, &job.ID, 10, 0)
+	if got := executions[len(executions)-1].Stdout; got != dir+" prod\n" {
+		t.Errorf("retry stdout = %q, want %q", got, dir+" prod\n")
+	}
+	result, err := p.Call("job_logs", map[string]interface{}{"job_name": "deploy"})
+	if err != nil {
+		t.Fatalf("job_logs failed: %v", err)
+	}
+	if !containsTextResult(result, `"Workdir": "`+dir+`"`) {
+		t.Errorf("expected the workdir in job_logs, got %v", result)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"name": "relative", "schedule": "@daily", "command": "true", "workdir": "some/dir"},
+		{"name": "bad-env", "schedule": "@daily", "command": "true", "env": map[string]interface{}{"A=B": "x"}},
+		{"name": "non-string", "schedule": "@daily", "command": "true", "env": map[string]interface{}{"N": float64(1)}},
+	} {
+		if _, err := p.Call("job_add", args); err == nil {
+			t.Errorf("%s: expected job_add to fail", args["name"])
+		}
+	}
+}
+
+func TestJobAddTimeout(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":            "hangs",
+		"schedule":        "@daily",
+		"command":         "sleep 30",
+		"timeout_seconds": float64(1),
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	ctx := context.Background()
+	job, _ := p.jobStore.GetJobByName(ctx, "hangs")
+	if job.TimeoutSeconds != 1 {
+		t.Fatalf("timeout = %d, want 1", job.TimeoutSeconds)
+	}
+
+	skippedID, err := p.executionStore.RecordSkippedExecution(ctx, job.ID, "previous run still in progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := p.Call("job_retry", map[string]interface{}{"execution_id": float64(skippedID)})
+	if err != nil {
+		t.Fatalf("job_retry failed: %v", err)
+	}
+	if !containsTextResult(result, "failed: timed out after 1s") {
+		t.Errorf("expected the run to time out, got %v", result)
+	}
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name": "negative", "schedule": "@daily", "command": "true", "timeout_seconds": float64(-1),
+	}); err == nil {
+		t.Error("expected a negative timeout to be rejected")
+	}
+}
+
+func TestJobLogsShowsSkipped(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":     "slow",
+		"schedule": "* * * * *",
+		"command":  "sleep 120",
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	job, _ := p.jobStore.GetJobByName(context.Background(), "slow")
+	if _, err := p.executionStore.RecordSkippedExecution(context.Background(), job.ID, "previous run still in progress"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := p.Call("job_logs", map[string]interface{}{"job_name": "slow"})
+	if err != nil {
+		t.Fatalf("job_logs failed: %v", err)
+	}
+	if !containsTextResult(result, db.ExecutionStatusSkipped) {
+		t.Error("expected the skipped fire in job_logs")
+	}
+}
+
+func TestJobRetry(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name":     "flaky",
+		"schedule": "@daily",
+		"command":  "echo fixed",
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	job, _ := p.jobStore.GetJobByName(context.Background(), "flaky")
+	failedID, err := p.executionStore.RecordSkippedExecution(context.Background(), job.ID, "previous run still in progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := p.Call("job_retry", map[string]interface{}{"execution_id": float64(failedID)})
+	if err != nil {
+		t.Fatalf("job_retry failed: %v", err)
+	}
+	if !containsTextResult(result, "succeeded") {
+		t.Errorf("expected the retry to succeed, got %v", result)
+	}
+
+	executions, _ := p.executionStore.ListJobExecutions(context.Background(), &job.ID, 10, 0)
+	if len(executions) != 2 {
+		t.Fatalf("expected a new execution, got %d", len(executions))
+	}
+	retry := executions[1]
+	if retry.RetryOf == nil || *retry.RetryOf != failedID {
+		t.Errorf("retry should link to execution %d, got %v", failedID, retry.RetryOf)
+	}
+	if retry.ExitCode == nil || *retry.ExitCode != 0 || retry.Stdout != "fixed\n" {
+		t.Errorf("unexpected retry result: exit %v, stdout %q", retry.ExitCode, retry.Stdout)
+	}
+
+	// The retry succeeded, so it can't be retried itself
+	if _, err := p.Call("job_retry", map[string]interface{}{"execution_id": float64(retry.ID)}); err == nil {
+		t.Error("expected retrying a successful execution to fail")
+	}
+}
+
+func TestJobAddDependsOn(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name": "backup", "schedule": "@daily", "command": "echo backup",
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	// Only retried runs fire dependents, so a dependent still needs its own
+	// schedule to run at all
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name": "upload", "command": "echo upload", "depends_on": "backup",
+	}); err == nil {
+		t.Error("expected a dependent job without a schedule to be rejected")
+	}
+	result, err := p.Call("job_add", map[string]interface{}{
+		"name": "upload", "schedule": "@weekly", "command": "echo upload", "depends_on": "backup",
+	})
+	if err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	if !containsTextResult(result, "Also runs after each successful retried run of 'backup'") {
+		t.Errorf("expected the dependency in the response, got %v", result)
+	}
+	ctx := context.Background()
+	if job, _ := p.jobStore.GetJobByName(ctx, "upload"); job.DependsOn != "backup" || job.Schedule != "@weekly" {
+		t.Errorf("unexpected job: depends_on %q, schedule %q", job.DependsOn, job.Schedule)
+	}
+
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name": "orphan", "schedule": "@daily", "command": "true", "depends_on": "missing",
+	}); err == nil {
+		t.Error("expected an unknown parent to be rejected")
+	}
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name": "self", "schedule": "@daily", "command": "true", "depends_on": "self",
+	}); err == nil {
+		t.Error("expected a job depending on itself to be rejected")
+	}
+
+	// A job still pointing at a deleted "cleanup" would close a cycle once
+	// cleanup is re-added to run after it
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name": "archive", "schedule": "@daily", "command": "true", "depends_on": "upload",
+	}); err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	archive, _ := p.jobStore.GetJobByName(ctx, "archive")
+	p.jobStore.SetDependsOn(ctx, archive.ID, "cleanup")
+	_, err = p.Call("job_add", map[string]interface{}{
+		"name": "cleanup", "schedule": "@daily", "command": "true", "depends_on": "archive",
+	})
+	if err == nil || !contains(err.Error(), "dependency cycle") {
+		t.Errorf("expected the cycle to be rejected, got %v", err)
+	}
+}
+
+func TestFireDependents(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, args := range []map[string]interface{}{
+		{"name": "backup", "schedule": "@daily", "command": "echo backup"},
+		{"name": "upload", "schedule": "@weekly", "command": "echo uploaded", "depends_on": "backup"},
+		{"name": "notify", "schedule": "@weekly", "command": "echo notified", "depends_on": "upload"},
+	} {
+		if _, err := p.Call("job_add", args); err != nil {
+			t.Fatalf("job_add failed: %v", err)
+		}
+	}
+	backup, _ := p.jobStore.GetJobByName(ctx, "backup")
+	code := 0
+	now := time.Now()
+	succeeded := &db.JobExecution{JobID: backup.ID, StartedAt: now, EndedAt: &now, ExitCode: &code}
+
+	fired, err := FireDependents(ctx, p.jobStore, p.executionStore, backup, succeeded)
+	if err != nil {
+		t.Fatalf("FireDependents: %v", err)
+	}
+	if len(fired) != 2 || fired[0].Stdout != "uploaded\n" || fired[1].Stdout != "notified\n" {
+		t.Fatalf("expected upload then notify to run, got %+v", fired)
+	}
+
+	failedCode := 1
+	failed := &db.JobExecution{JobID: backup.ID, StartedAt: now, EndedAt: &now, ExitCode: &failedCode}
+	skipped, err := FireDependents(ctx, p.jobStore, p.executionStore, backup, failed)
+	if err != nil {
+		t.Fatalf("FireDependents: %v", err)
+	}
+	if len(skipped) != 2 {
+		t.Fatalf("expected the whole chain to be skipped, got %d executions", len(skipped))
+	}
+	for _, e := range skipped {
+		if e.Status != db.ExecutionStatusSkipped || e.Error == nil {
+			t.Errorf("expected a skipped execution, got %+v", e)
+		}
+	}
+	if *skipped[0].Error != "parent job 'backup' did not succeed" {
+		t.Errorf("unexpected skip reason %q", *skipped[0].Error)
+	}
+}
+
+func TestJobOutputFormatJSON(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, args := range []map[string]interface{}{
+		{"name": "count", "schedule": "@daily", "command": `echo '{"files": 3}'`, "output_format": "json"},
+		{"name": "report", "schedule": "@weekly", "command": `echo "$DIANE_PARENT_OUTPUT"`, "depends_on": "count"},
+		{"name": "broken", "schedule": "@daily", "command": "echo done", "output_format": "json"},
+	} {
+		if _, err := p.Call("job_add", args); err != nil {
+			t.Fatalf("job_add failed: %v", err)
+		}
+	}
+	if _, err := p.Call("job_add", map[string]interface{}{
+		"name": "yaml", "schedule": "@daily", "command": "true", "output_format": "yaml",
+	}); err == nil {
+		t.Error("expected an unknown output format to be rejected")
+	}
+
+	count, _ := p.jobStore.GetJobByName(ctx, "count")
+	skippedID, _ := p.executionStore.RecordSkippedExecution(ctx, count.ID, "previous run still in progress")
+	e, err := Retry(ctx, p.jobStore, p.executionStore, skippedID)
+	if err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	if out, ok := e.Output.(map[string]any); !ok || out["files"] != float64(3) {
+		t.Fatalf("expected the parsed output on the execution, got %#v", e.Output)
+	}
+
+	fired, err := FireDependents(ctx, p.jobStore, p.executionStore, count, e)
+	if err != nil || len(fired) != 1 {
+		t.Fatalf("FireDependents: %v, %d executions", err, len(fired))
+	}
+	if fired[0].Stdout != "{\"files\":3}\n" {
+		t.Errorf("expected the dependent to get the parent's output, got %q", fired[0].Stdout)
+	}
+
+	broken, _ := p.jobStore.GetJobByName(ctx, "broken")
+	skippedID, _ = p.executionStore.RecordSkippedExecution(ctx, broken.ID, "previous run still in progress")
+	e, err = Retry(ctx, p.jobStore, p.executionStore, skippedID)
+	if err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	if !Failed(e) || e.Error == nil || !contains(*e.Error, "invalid JSON output") || e.Output != nil {
+		t.Errorf("expected output that isn't JSON to fail the run, got %+v", e)
+	}
+}
+
+func TestJobNotifyTemplateFailureHook(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var notified []string
+	SetFailureHook(func(job *db.Job, e *db.JobExecution) {
+		notified = append(notified, job.NotifyTemplate+":"+job.Name)
+	})
+	defer SetFailureHook(nil)
+
+	for _, args := range []map[string]interface{}{
+		{"name": "flaky", "schedule": "@daily", "command": "exit 3", "notify_template": "job-failed"},
+		{"name": "fine", "schedule": "@daily", "command": "true", "notify_template": "job-failed"},
+		{"name": "quiet", "schedule": "@daily", "command": "exit 1"},
+	} {
+		if _, err := p.Call("job_add", args); err != nil {
+			t.Fatalf("job_add failed: %v", err)
+		}
+	}
+
+	for _, name := range []string{"flaky", "fine", "quiet"} {
+		job, _ := p.jobStore.GetJobByName(ctx, name)
+		skippedID, _ := p.executionStore.RecordSkippedExecution(ctx, job.ID, "previous run still in progress")
+		if _, err := Retry(ctx, p.jobStore, p.executionStore, skippedID); err != nil {
+			t.Fatalf("Retry %s: %v", name, err)
+		}
+	}
+	if len(notified) != 1 || notified[0] != "job-failed:flaky" {
+		t.Errorf("expected only the failed run of flaky to notify, got %v", notified)
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	runs, err := ValidateSchedule("0 9 * * mon", now)
+	if err != nil {
+		t.Fatalf("ValidateSchedule: %v", err)
+	}
+	want := []time.Time{
+		time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC),
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("got %d runs, want %d", len(runs), len(want))
+	}
+	for i := range want {
+		if !runs[i].Equal(want[i]) {
+			t.Errorf("run %d = %v, want %v", i, runs[i], want[i])
+		}
+	}
+
+	if _, err := ValidateSchedule("0 0 30 2 *", now); err == nil || !contains(err.Error(), "never fires") {
+		t.Errorf("expected February 30th to be rejected, got %v", err)
+	}
+}
+
+func TestJobAddSecondsSchedule(t *testing.T) {
+	p, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	result, err := p.Call("job_add", map[string]interface{}{
+		"name":     "heartbeat",
+		"schedule": "*/30 * * * * *",
+		"command":  "echo beat",
+	})
+	if err != nil {
+		t.Fatalf("job_add failed: %v", err)
+	}
+	if !containsTextResult(result, ":30 ") && !containsTextResult(result, ":00 ") {
+		t.Errorf("expected next runs with seconds, got %v", result)
+	}
+
+	// A Quartz-style trailing year must not be read as seconds-first
+	_, err = p.Call("job_add", map[string]interface{}{
+		"name":     "quartz",
+		"schedule": "0 0 12 * * 2030",
+		"command":  "echo",
+	})
+	if err == nil || !contains(err.Error(), "year") {
+		t.Errorf("expected a year field to be rejected, got %v", err)
+	}
+}
+
 func TestJobLogs(t *testing.T) {
 	p, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diane-assistant/diane/internal/cron"
+	"github.com/diane-assistant/diane/internal/db"
+	"github.com/diane-assistant/diane/internal/store"
+)
+
+// Failed reports whether an execution finished without succeeding: it
+// exited non-zero, hit an error or was skipped
+func Failed(e *db.JobExecution) bool {
+	if e.EndedAt == nil {
+		return false
+	}
+	return e.Status == db.ExecutionStatusSkipped || e.Error != nil || (e.ExitCode != nil && *e.ExitCode != 0)
+}
+
+// Retry re-runs the job behind a failed execution and records the run as a
+// new execution whose RetryOf points at it. The job's current command is
+// used, so a fixed command is picked up, along with its current environment,
+// working directory and timeout. Retry waits for the run to finish, for at
+// most cron.RunTimeout, and returns the new execution. Only shell jobs can be
+// retried. The retry obeys the job's concurrency policy like any other run:
+// under skip it fails while another run of the job is in progress, and under
+// queue it waits for that run.
+func Retry(ctx context.Context, jobStore store.JobStore, executionStore store.ExecutionStore, executionID int64) (*db.JobExecution, error) {
+	prev, err := executionStore.GetJobExecution(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+	if prev.EndedAt == nil {
+		return nil, fmt.Errorf("execution %d is still running", executionID)
+	}
+	if !Failed(prev) {
+		return nil, fmt.Errorf("execution %d succeeded; only failed or skipped executions can be retried", executionID)
+	}
+	job, err := jobStore.GetJob(ctx, prev.JobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.ActionType == "agent" {
+		return nil, fmt.Errorf("job '%s' runs an agent; only shell jobs can be retried", job.Name)
+	}
+
+	release, err := runLimiter.Acquire(ctx, job.ID, job.Concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("job '%s': %w", job.Name, err)
+	}
+	defer release()
+
+	id, err := executionStore.CreateRetryExecution(ctx, job.ID, prev.ID)
+	if err != nil {
+		return nil, err
+	}
+	return execute(ctx, executionStore, job, id, RunOptions(job))
+}
+
+// runLimiter applies jobs' concurrency policies to every run started here
+var runLimiter = cron.NewLimiter()
+
+// execute runs job's command for execution id, for at most cron.RunTimeout,
+// and records the outcome. When the job's output format is json, a
+// successful run's stdout is parsed and stored on the execution, and stdout
+// that doesn't parse fails the run.
+func execute(ctx context.Context, executionStore store.ExecutionStore, job *db.Job, id int64, opts cron.RunOptions) (*db.JobExecution, error) {
+	runCtx, cancel := context.WithTimeout(ctx, cron.RunTimeout)
+	defer cancel()
+	exitCode, stdout, stderr, runErr := cron.RunShell(runCtx, job.Command, opts)
+
+	var output any
+	if runErr == nil && exitCode == 0 {
+		output, runErr = cron.ParseOutput(job.OutputFormat, stdout)
+	}
+
+	// Record the outcome even if the caller has gone away mid-run
+	ctx = context.WithoutCancel(ctx)
+	if err := executionStore.UpdateJobExecution(ctx, id, exitCode, stdout, stderr, runErr); err != nil {
+		return nil, err
+	}
+	if output != nil {
+		if err := executionStore.SetExecutionOutput(ctx, id, output); err != nil {
+			return nil, err
+		}
+	}
+	e, err := executionStore.GetJobExecution(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if Failed(e) && job.NotifyTemplate != "" && failureHook != nil {
+		failureHook(job, e)
+	}
+	return e, nil
+}
+
+// failureHook is called after a run of a job with a notify template fails
+var failureHook func(job *db.Job, e *db.JobExecution)
+
+// SetFailureHook sets the function called after a run of a job with a
+// notify template fails, which sends the notification. It must not block
+// for long: runs wait for it.
+func SetFailureHook(hook func(job *db.Job, e *db.JobExecution)) {
+	failureHook = hook
+}
+
+// RunOptions returns how job's command is run: in its effective working
+// directory, with its environment variables and under its timeout
+func RunOptions(job *db.Job) cron.RunOptions {
+	return cron.RunOptions{
+		Dir:     cron.EffectiveWorkdir(job.Workdir),
+		Env:     job.Env,
+		Timeout: time.Duration(job.TimeoutSeconds) * time.Second,
+	}
+}
+
+// FormatRetry summarizes a retry for a tool response
+func FormatRetry(e *db.JobExecution) string {
+	outcome := "succeeded"
+	switch {
+	case e.Error != nil:
+		outcome = "failed: " + *e.Error
+	case e.ExitCode != nil && *e.ExitCode != 0:
+		outcome = fmt.Sprintf("failed with exit code %d", *e.ExitCode)
+	}
+	return fmt.Sprintf("Retried execution %d as execution %d, which %s", *e.RetryOf, e.ID, outcome)
+}
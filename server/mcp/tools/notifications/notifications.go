@@ -3,8 +3,10 @@ package notifications
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +16,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/internal/store"
 	"github.com/diane-assistant/diane/mcp/tools"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -63,7 +67,7 @@ func loadChannelMappings() (channelMappings, error) {
 
 	// Try DIANE config directories
 	paths := []string{
-		filepath.Join(home, ".diane", "secrets", "discord-channels.json"),
+		filepath.Join(home, config.DirName(), "secrets", "discord-channels.json"),
 		filepath.Join(home, ".opencode", "secrets", "discord-channels.json"),
 	}
 
@@ -143,7 +147,7 @@ func getHomeAssistantConfig() (*homeAssistantConfig, error) {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	configPath := filepath.Join(home, ".diane", "secrets", "homeassistant-config.json")
+	configPath := filepath.Join(home, config.DirName(), "secrets", "homeassistant-config.json")
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("Home Assistant config not found. Create %s with server_url, access_token, notify_service", configPath)
@@ -215,6 +219,7 @@ type Tool struct {
 type Provider struct {
 	discordAvailable       bool
 	homeAssistantAvailable bool
+	templates              store.NotifyTemplateStore // nil until SetTemplateStore
 }
 
 // NewProvider creates a new notifications tools provider
@@ -244,6 +249,33 @@ func (p *Provider) CheckDependencies() error {
 	return nil
 }
 
+// CheckBackend implements tools.BackendChecker by checking the credentials
+// of each configured service: the Discord bot's own user and the Home
+// Assistant API root
+func (p *Provider) CheckBackend(ctx context.Context) error {
+	var errs []error
+	if token, err := getDiscordBotToken(); err == nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://discord.com/api/v10/users/@me", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bot "+token)
+		errs = append(errs, tools.CheckHTTP("discord", req))
+	}
+	if ha, err := getHomeAssistantConfig(); err == nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(ha.ServerURL, "/")+"/api/", nil)
+		if err != nil {
+			return fmt.Errorf("home assistant %w: bad server_url: %v", tools.ErrNotConfigured, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+ha.AccessToken)
+		errs = append(errs, tools.CheckHTTP("home assistant", req))
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("notifications %w: neither Discord nor Home Assistant is set up", tools.ErrNotConfigured)
+	}
+	return errors.Join(errs...)
+}
+
 // Tools returns all notification tools
 func (p *Provider) Tools() []Tool {
 	var tools []Tool
@@ -256,14 +288,18 @@ func (p *Provider) Tools() []Tool {
 				Description: `Send a notification message to Discord channel via the Kimaki bot.
 
 Uses the Discord bot credentials stored in Kimaki database.
-Perfect for automation notifications, cron job results, and alerts.`,
+Perfect for automation notifications, cron job results, and alerts.
+Pass template (and template_data) instead of message to use a saved
+notify template, so alerts share one format.`,
 				InputSchema: objectSchema(
 					map[string]interface{}{
-						"message":      stringProperty("Message content to send"),
-						"title":        stringProperty("Optional title/header (will be bolded)"),
-						"channel_name": stringProperty("Channel name from config, channel ID (digits only), or default #diane"),
+						"message":       stringProperty("Message content to send (required unless template is set)"),
+						"title":         stringProperty("Optional title/header (will be bolded)"),
+						"channel_name":  stringProperty("Channel name from config, channel ID (digits only), or default #diane"),
+						"template":      stringProperty(templateDescription),
+						"template_data": stringProperty(templateDataDescription),
 					},
-					[]string{"message"},
+					nil,
 				),
 			},
 			{
@@ -328,14 +364,16 @@ Use for yes/no questions, multiple choice, approval workflows.`,
 		tools = append(tools, []Tool{
 			{
 				Name:        "homeassistant_send_notification",
-				Description: "Send a notification to Home Assistant companion app. Use for cron job summaries, alerts, and important updates.",
+				Description: "Send a notification to Home Assistant companion app. Use for cron job summaries, alerts, and important updates. Pass template (and template_data) instead of message to use a saved notify template.",
 				InputSchema: objectSchema(
 					map[string]interface{}{
-						"message": stringProperty("Notification message text"),
-						"title":   stringProperty("Notification title (optional)"),
-						"data":    stringProperty("Additional notification data as JSON string (optional)"),
+						"message":       stringProperty("Notification message text (required unless template is set)"),
+						"title":         stringProperty("Notification title (optional)"),
+						"data":          stringProperty("Additional notification data as JSON string (optional)"),
+						"template":      stringProperty(templateDescription),
+						"template_data": stringProperty(templateDataDescription),
 					},
-					[]string{"message"},
+					nil,
 				),
 			},
 			{
@@ -404,7 +442,7 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 // --- Discord Tool Implementations ---
 
 func (p *Provider) discordSendNotification(args map[string]interface{}) (interface{}, error) {
-	message, err := getStringRequired(args, "message")
+	message, err := p.messageArg(args)
 	if err != nil {
 		return nil, err
 	}
@@ -716,7 +754,7 @@ func discordAPICall(botToken, channelID, endpoint, method string, payload interf
 // --- Home Assistant Tool Implementations ---
 
 func (p *Provider) haSendNotification(args map[string]interface{}) (interface{}, error) {
-	message, err := getStringRequired(args, "message")
+	message, err := p.messageArg(args)
 	if err != nil {
 		return nil, err
 	}
@@ -908,6 +946,31 @@ func (p *Provider) Prompts() []tools.Prompt {
 	return prompts
 }
 
+// Complete implements tools.Completer for the Discord prompts: channel
+// suggests the names in discord-channels.json and success true or false.
+// Other arguments, like job_name, are left to the server's own completions.
+func (p *Provider) Complete(ref tools.CompletionRef, argument, value string) ([]string, bool) {
+	if !p.discordAvailable || ref.Type != "ref/prompt" || !strings.HasPrefix(ref.Name, "discord_") {
+		return nil, false
+	}
+	switch argument {
+	case "channel":
+		mappings, err := loadChannelMappings()
+		if err != nil {
+			return nil, true
+		}
+		names := make([]string, 0, len(mappings))
+		for name := range mappings {
+			names = append(names, name)
+		}
+		return names, true
+	case "success":
+		return []string{"true", "false"}, true
+	default:
+		return nil, false
+	}
+}
+
 // GetPrompt returns a prompt with arguments substituted
 func (p *Provider) GetPrompt(name string, args map[string]string) ([]tools.PromptMessage, error) {
 	switch name {
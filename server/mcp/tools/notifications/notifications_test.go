@@ -1,7 +1,17 @@
 package notifications
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/internal/store"
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 func TestProviderName(t *testing.T) {
@@ -377,6 +387,54 @@ func TestPromptJobResultContent(t *testing.T) {
 	}
 }
 
+// fakeTemplates is an in-memory store.NotifyTemplateStore
+type fakeTemplates map[string]string
+
+func (f fakeTemplates) ListNotifyTemplates(context.Context) ([]store.NotifyTemplate, error) {
+	return nil, nil
+}
+
+func (f fakeTemplates) GetNotifyTemplate(_ context.Context, name string) (*store.NotifyTemplate, error) {
+	body, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("notify template not found: %s", name)
+	}
+	return &store.NotifyTemplate{Name: name, Body: body}, nil
+}
+
+func (f fakeTemplates) SaveNotifyTemplate(context.Context, store.NotifyTemplate) error { return nil }
+
+func (f fakeTemplates) DeleteNotifyTemplate(context.Context, string) error { return nil }
+
+func TestMessageArg(t *testing.T) {
+	p := &Provider{}
+	if got, err := p.messageArg(map[string]interface{}{"message": "hello"}); err != nil || got != "hello" {
+		t.Errorf("messageArg = %q, %v", got, err)
+	}
+	if _, err := p.messageArg(map[string]interface{}{}); err == nil {
+		t.Error("expected an error without message or template")
+	}
+	if _, err := p.messageArg(map[string]interface{}{"template": "job-failed"}); err == nil {
+		t.Error("expected an error when templates are not available")
+	}
+
+	p.SetTemplateStore(fakeTemplates{"job-failed": "{{.job_name}} exited {{.exit_code}}{{with .message}} ({{.}}){{end}}"})
+	got, err := p.messageArg(map[string]interface{}{
+		"template":      "job-failed",
+		"template_data": `{"job_name": "backup", "exit_code": 2}`,
+		"message":       "disk full",
+	})
+	if err != nil || got != "backup exited 2 (disk full)" {
+		t.Errorf("messageArg = %q, %v", got, err)
+	}
+	if _, err := p.messageArg(map[string]interface{}{"template": "missing"}); err == nil || !contains(err.Error(), "not found") {
+		t.Errorf("expected an unknown template to be reported, got %v", err)
+	}
+	if _, err := p.messageArg(map[string]interface{}{"template": "job-failed", "template_data": "{"}); err == nil {
+		t.Error("expected invalid template_data to be rejected")
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
@@ -389,3 +447,38 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestComplete(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	secrets := filepath.Join(home, config.DirName(), "secrets")
+	if err := os.MkdirAll(secrets, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(secrets, "discord-channels.json"), []byte(`{"alerts":"1","builds":"2"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provider{discordAvailable: true}
+	ref := tools.CompletionRef{Type: "ref/prompt", Name: "discord_notify_job_result"}
+
+	values, ok := p.Complete(ref, "channel", "")
+	sort.Strings(values)
+	if !ok || strings.Join(values, ",") != "alerts,builds" {
+		t.Errorf("channel: got %v, %v; want the configured channels", values, ok)
+	}
+	if values, ok := p.Complete(ref, "success", "t"); !ok || strings.Join(values, ",") != "true,false" {
+		t.Errorf("success: got %v, %v", values, ok)
+	}
+
+	// job_name is left to the server, as are other providers' prompts
+	if _, ok := p.Complete(ref, "job_name", ""); ok {
+		t.Error("expected job_name to be left to the server")
+	}
+	if _, ok := p.Complete(tools.CompletionRef{Type: "ref/prompt", Name: "gmail_inbox_triage"}, "channel", ""); ok {
+		t.Error("expected another provider's prompt to be declined")
+	}
+	if _, ok := (&Provider{}).Complete(ref, "channel", ""); ok {
+		t.Error("expected no completions without Discord")
+	}
+}
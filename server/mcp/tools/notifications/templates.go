@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/diane-assistant/diane/internal/notify"
+	"github.com/diane-assistant/diane/internal/store"
+)
+
+const (
+	templateDescription     = "Name of a saved notify template (see `diane notify template list`) to render as the message"
+	templateDataDescription = "Values for the template as a JSON object string, e.g. {\"job_name\": \"backup\", \"exit_code\": 1}; message, if also given, is available as .message"
+)
+
+// SetTemplateStore lets the notification tools render saved notify
+// templates
+func (p *Provider) SetTemplateStore(s store.NotifyTemplateStore) {
+	p.templates = s
+}
+
+// messageArg returns the message a send tool was asked for: the rendered
+// template when the template argument is set, the message argument
+// otherwise
+func (p *Provider) messageArg(args map[string]interface{}) (string, error) {
+	name := getString(args, "template")
+	if name == "" {
+		return getStringRequired(args, "message")
+	}
+
+	data := map[string]any{}
+	if raw := getString(args, "template_data"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return "", fmt.Errorf("invalid template_data JSON: %w", err)
+		}
+	}
+	if message := getString(args, "message"); message != "" {
+		data["message"] = message
+	}
+	return p.RenderTemplate(context.Background(), name, data)
+}
+
+// RenderTemplate renders the saved notify template called name against data
+func (p *Provider) RenderTemplate(ctx context.Context, name string, data map[string]any) (string, error) {
+	if p.templates == nil {
+		return "", fmt.Errorf("notify templates are not available (Emergent not configured)")
+	}
+	t, err := p.templates.GetNotifyTemplate(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return notify.Render(t.Name, t.Body, data)
+}
+
+// Notify sends message with an optional title to Discord's default
+// channel, or to Home Assistant when Discord isn't set up
+func (p *Provider) Notify(title, message string) error {
+	args := map[string]interface{}{"title": title, "message": message}
+	var err error
+	switch {
+	case p.discordAvailable:
+		_, err = p.discordSendNotification(args)
+	case p.homeAssistantAvailable:
+		_, err = p.haSendNotification(args)
+	default:
+		err = fmt.Errorf("no notification services available (Discord or Home Assistant)")
+	}
+	return err
+}
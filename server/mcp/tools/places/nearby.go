@@ -0,0 +1,199 @@
+package places
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+const nearbySearchURL = "https://maps.googleapis.com/maps/api/place/nearbysearch/json"
+
+// earthRadiusMeters is the mean Earth radius used for distances
+const earthRadiusMeters = 6371000
+
+// distanceMeters returns the great-circle (haversine) distance between two
+// points given in degrees
+func distanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+// nearbyCenter resolves the search center from lat/lon or a location query
+func nearbyCenter(args map[string]interface{}) (lat, lng float64, err error) {
+	hasLat := args["lat"] != nil
+	hasLon := args["lon"] != nil
+	if hasLat != hasLon {
+		return 0, 0, fmt.Errorf("lat and lon must be given together")
+	}
+	if hasLat {
+		lat, lng = getNumber(args, "lat", 0), getNumber(args, "lon", 0)
+		if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return 0, 0, fmt.Errorf("lat/lon out of range")
+		}
+		return lat, lng, nil
+	}
+	location := getString(args, "location")
+	if location == "" {
+		return 0, 0, fmt.Errorf("either lat/lon or location is required")
+	}
+	return geocodeLocation(location)
+}
+
+// nearbyPlace is one result of places_nearby
+type nearbyPlace struct {
+	Rank             int      `json:"rank"`
+	Name             string   `json:"name"`
+	Address          string   `json:"address"`
+	Rating           float64  `json:"rating,omitempty"`
+	UserRatingsTotal int      `json:"user_ratings_total,omitempty"`
+	OpenNow          *bool    `json:"open_now"`
+	DistanceMeters   int      `json:"distance_meters"`
+	Types            []string `json:"types,omitempty"`
+	PlaceID          string   `json:"place_id"`
+	Location         struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	} `json:"location"`
+}
+
+// findPlacesNearby lists places of a type or matching a keyword around a
+// center, nearest first. Google ranks by distance only without a radius, so
+// the radius is applied here: results past it are dropped, and since later
+// pages are farther still, no next page is offered once one is.
+func (p *Provider) findPlacesNearby(args map[string]interface{}) (interface{}, error) {
+	lat, lng, err := nearbyCenter(args)
+	if err != nil {
+		return nil, err
+	}
+
+	radius := getNumber(args, "radius", 1000)
+	if radius <= 0 || radius > 50000 {
+		return nil, fmt.Errorf("radius must be between 1 and 50000 meters")
+	}
+
+	placeType := getString(args, "type")
+	keyword := getString(args, "keyword")
+	pageToken := getString(args, "page_token")
+	if placeType == "" && keyword == "" && pageToken == "" {
+		return nil, fmt.Errorf("type or keyword is required")
+	}
+
+	params := url.Values{}
+	params.Set("key", config.APIKey)
+	if pageToken != "" {
+		// A page token carries the original search; other parameters are ignored
+		params.Set("pagetoken", pageToken)
+	} else {
+		params.Set("location", fmt.Sprintf("%f,%f", lat, lng))
+		params.Set("rankby", "distance")
+		if placeType != "" {
+			params.Set("type", placeType)
+		}
+		if keyword != "" {
+			params.Set("keyword", keyword)
+		}
+		if openNow, _ := getBool(args, "open_now"); openNow {
+			params.Set("opennow", "true")
+		}
+	}
+
+	resp, err := http.Get(nearbySearchURL + "?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("nearby places request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	var data struct {
+		Status        string `json:"status"`
+		ErrorMessage  string `json:"error_message"`
+		NextPageToken string `json:"next_page_token"`
+		Results       []struct {
+			Name             string   `json:"name"`
+			Vicinity         string   `json:"vicinity"`
+			Rating           float64  `json:"rating"`
+			UserRatingsTotal int      `json:"user_ratings_total"`
+			Types            []string `json:"types"`
+			PlaceID          string   `json:"place_id"`
+			OpeningHours     *struct {
+				OpenNow bool `json:"open_now"`
+			} `json:"opening_hours"`
+			Geometry struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse nearby places response: %w", err)
+	}
+
+	switch data.Status {
+	case "OK", "ZERO_RESULTS":
+	case "INVALID_REQUEST":
+		if pageToken != "" {
+			return nil, fmt.Errorf("page_token is invalid or not ready yet; tokens take a few seconds to become valid, so retry shortly")
+		}
+		fallthrough
+	default:
+		return nil, fmt.Errorf("Google Places API error: %s - %s", data.Status, data.ErrorMessage)
+	}
+
+	places := make([]nearbyPlace, 0, len(data.Results))
+	pastRadius := false
+	for _, r := range data.Results {
+		d := distanceMeters(lat, lng, r.Geometry.Location.Lat, r.Geometry.Location.Lng)
+		if d > radius {
+			pastRadius = true
+			continue
+		}
+		place := nearbyPlace{
+			Name:             r.Name,
+			Address:          r.Vicinity,
+			Rating:           r.Rating,
+			UserRatingsTotal: r.UserRatingsTotal,
+			DistanceMeters:   int(math.Round(d)),
+			Types:            r.Types,
+			PlaceID:          r.PlaceID,
+		}
+		if r.OpeningHours != nil {
+			open := r.OpeningHours.OpenNow
+			place.OpenNow = &open
+		}
+		place.Location.Lat = r.Geometry.Location.Lat
+		place.Location.Lng = r.Geometry.Location.Lng
+		places = append(places, place)
+	}
+	sort.SliceStable(places, func(i, j int) bool {
+		return places[i].DistanceMeters < places[j].DistanceMeters
+	})
+	for i := range places {
+		places[i].Rank = i + 1
+	}
+
+	response := map[string]interface{}{
+		"center":        map[string]float64{"lat": lat, "lng": lng},
+		"radius_meters": int(radius),
+		"total_results": len(places),
+		"places":        places,
+	}
+	if data.NextPageToken != "" && !pastRadius {
+		response["next_page_token"] = data.NextPageToken
+	}
+	if len(places) == 0 {
+		response["message"] = fmt.Sprintf("No places found within %d meters.", int(radius))
+	}
+
+	result, _ := json.MarshalIndent(response, "", "  ")
+	return textContent(string(result)), nil
+}
@@ -0,0 +1,45 @@
+package places
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceMeters(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		want                   float64
+	}{
+		{"same point", 52.52, 13.405, 52.52, 13.405, 0},
+		{"one degree of latitude", 0, 0, 1, 0, 111195},
+		{"Paris to London", 48.8566, 2.3522, 51.5074, -0.1278, 343556},
+	}
+	for _, tt := range tests {
+		got := distanceMeters(tt.lat1, tt.lng1, tt.lat2, tt.lng2)
+		if math.Abs(got-tt.want) > tt.want*0.001+1 {
+			t.Errorf("%s: got %.0f, want ~%.0f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNearbyCenter(t *testing.T) {
+	lat, lng, err := nearbyCenter(map[string]interface{}{"lat": 52.5, "lon": 13.4})
+	if err != nil || lat != 52.5 || lng != 13.4 {
+		t.Errorf("got %v,%v, %v", lat, lng, err)
+	}
+	// Coordinates given as a location are parsed without geocoding
+	if lat, lng, err = nearbyCenter(map[string]interface{}{"location": "48.85, 2.29"}); err != nil || lat != 48.85 || lng != 2.29 {
+		t.Errorf("got %v,%v, %v", lat, lng, err)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"lat": 52.5},
+		{"lat": 95.0, "lon": 0.0},
+		{},
+	} {
+		if _, _, err := nearbyCenter(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
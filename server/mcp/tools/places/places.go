@@ -11,6 +11,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+
+	dianeconfig "github.com/diane-assistant/diane/internal/config"
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 // --- Configuration ---
@@ -149,6 +153,8 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+	// CacheTTL, if set, lets identical calls within it reuse the result
+	CacheTTL time.Duration `json:"-"`
 }
 
 // Provider implements ToolProvider for Google Places tools
@@ -173,7 +179,7 @@ func (p *Provider) CheckDependencies() error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	secretsDir = filepath.Join(home, ".diane", "secrets")
+	secretsDir = filepath.Join(home, dianeconfig.DirName(), "secrets")
 	configPath := filepath.Join(secretsDir, "google-places-config.json")
 
 	data, err := os.ReadFile(configPath)
@@ -201,9 +207,10 @@ func (p *Provider) Tools() []Tool {
 		return nil
 	}
 
-	return []Tool{
+	list := []Tool{
 		{
 			Name:        "places_search",
+			CacheTTL:    time.Hour,
 			Description: "Search for places using Google Places API. Find restaurants, cafes, attractions, hotels, shops, and more. Supports text search with optional location bias and filters.",
 			InputSchema: objectSchema(
 				map[string]interface{}{
@@ -220,6 +227,7 @@ func (p *Provider) Tools() []Tool {
 		},
 		{
 			Name:        "places_get_details",
+			CacheTTL:    time.Hour,
 			Description: "Get detailed information about a specific place using its Place ID. Includes photos, reviews, opening hours, contact info, and more.",
 			InputSchema: objectSchema(
 				map[string]interface{}{
@@ -231,6 +239,7 @@ func (p *Provider) Tools() []Tool {
 		},
 		{
 			Name:        "places_find_nearby",
+			CacheTTL:    time.Hour,
 			Description: "Find places near a specific location or coordinates. Great for 'what's near me' queries. Returns places within a radius sorted by prominence or distance.",
 			InputSchema: objectSchema(
 				map[string]interface{}{
@@ -245,7 +254,70 @@ func (p *Provider) Tools() []Tool {
 				[]string{"location", "radius"},
 			),
 		},
+		{
+			Name:        "places_nearby",
+			CacheTTL:    time.Hour,
+			Description: "Find places of a type or matching a keyword within a radius of a point, nearest first (e.g., coffee shops within 500m). Each result has name, address, rating, open-now status and distance in meters. Pass next_page_token back as page_token, with the same center and radius, for more results.",
+			InputSchema: objectSchema(
+				map[string]interface{}{
+					"lat":        numberProperty("Latitude of the center (requires lon)"),
+					"lon":        numberProperty("Longitude of the center (requires lat)"),
+					"location":   stringProperty("Center as an address or place name, instead of lat/lon"),
+					"radius":     numberProperty("Search radius in meters (default: 1000, max: 50000)"),
+					"type":       stringProperty("Place type (e.g., 'cafe', 'restaurant', 'pharmacy', 'atm'). Type or keyword is required."),
+					"keyword":    stringProperty("Keyword to match (e.g., 'coffee', 'vegan', 'pizza'). Type or keyword is required."),
+					"open_now":   boolProperty("Only return places open now"),
+					"page_token": stringProperty("next_page_token from a previous places_nearby call, to fetch the next page"),
+				},
+				nil,
+			),
+		},
+		{
+			Name:        "places_autocomplete",
+			CacheTTL:    time.Hour,
+			Description: "Autocomplete a partial place name or address into ranked predictions. Each prediction has a place_id that can be passed to places_get_details. Useful for disambiguating vague input.",
+			InputSchema: objectSchema(
+				map[string]interface{}{
+					"query":  stringProperty("Partial text to complete (e.g., 'eiffel', '221b baker')"),
+					"lat":    numberProperty("Latitude to bias predictions towards (requires lon)"),
+					"lon":    numberProperty("Longitude to bias predictions towards (requires lat)"),
+					"radius": numberProperty("Bias radius in meters around lat/lon (default: 50000, max: 50000)"),
+					"types":  stringProperty("Restrict predictions by type (e.g., 'establishment', 'address', 'geocode', '(cities)', '(regions)'). Comma-separate up to 5 types."),
+				},
+				[]string{"query"},
+			),
+		},
+		{
+			Name:        "places_static_map",
+			CacheTTL:    time.Hour,
+			Description: "Render a map image centered on a place or coordinates, optionally with markers. Returns a PNG image.",
+			InputSchema: objectSchema(
+				map[string]interface{}{
+					"center":  stringProperty("Map center as address/place name or coordinates 'lat,lng'"),
+					"zoom":    numberProperty("Zoom level from 0 (world) to 21 (building) (default: 14)"),
+					"width":   numberProperty("Image width in pixels (default: 600, max: 640)"),
+					"height":  numberProperty("Image height in pixels (default: 400, max: 640)"),
+					"markers": stringProperty("Places to mark, separated by '|' (e.g., 'Louvre, Paris|48.8584,2.2945'). Default: a marker at the center."),
+					"maptype": stringProperty("Map type: roadmap (default), satellite, terrain or hybrid"),
+				},
+				[]string{"center"},
+			),
+		},
+	}
+	for _, tool := range list {
+		tools.AddNoCacheArg(tool.InputSchema)
+	}
+	return list
+}
+
+// CacheTTL implements tools.CacheableProvider
+func (p *Provider) CacheTTL(name string) time.Duration {
+	for _, tool := range p.Tools() {
+		if tool.Name == name {
+			return tool.CacheTTL
+		}
 	}
+	return 0
 }
 
 // HasTool checks if a tool belongs to this provider
@@ -257,7 +329,9 @@ func (p *Provider) HasTool(name string) bool {
 }
 
 // Call executes a tool by name
-func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+func (p *Provider) Call(name string, args map[string]interface{}) (result interface{}, err error) {
+	defer func() { err = tools.WrapUnreachable(p.Name(), err) }()
+
 	if !p.available {
 		return nil, fmt.Errorf("Google Places tools not available")
 	}
@@ -269,6 +343,12 @@ func (p *Provider) Call(name string, args map[string]interface{}) (interface{},
 		return p.getPlaceDetails(args)
 	case "places_find_nearby":
 		return p.findNearbyPlaces(args)
+	case "places_nearby":
+		return p.findPlacesNearby(args)
+	case "places_autocomplete":
+		return p.autocompletePlaces(args)
+	case "places_static_map":
+		return p.staticMap(args)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
@@ -655,3 +735,178 @@ func (p *Provider) findNearbyPlaces(args map[string]interface{}) (interface{}, e
 
 	return textContent(string(result)), nil
 }
+
+// autocompleteURL is the Places Autocomplete API endpoint; tests point it elsewhere
+var autocompleteURL = "https://maps.googleapis.com/maps/api/place/autocomplete/json"
+
+func (p *Provider) autocompletePlaces(args map[string]interface{}) (interface{}, error) {
+	query, err := getStringRequired(args, "query")
+	if err != nil {
+		return nil, err
+	}
+
+	hasLat := args["lat"] != nil
+	hasLon := args["lon"] != nil
+	if hasLat != hasLon {
+		return nil, fmt.Errorf("lat and lon must be given together")
+	}
+
+	apiURL := fmt.Sprintf("%s?input=%s&key=%s", autocompleteURL, url.QueryEscape(query), config.APIKey)
+
+	if hasLat {
+		lat := getNumber(args, "lat", 0)
+		lon := getNumber(args, "lon", 0)
+		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return nil, fmt.Errorf("lat/lon out of range")
+		}
+		radius := getNumber(args, "radius", 50000)
+		if radius <= 0 || radius > 50000 {
+			radius = 50000
+		}
+		apiURL += fmt.Sprintf("&location=%f,%f&radius=%d", lat, lon, int(radius))
+	}
+
+	if types := getString(args, "types"); types != "" {
+		var parts []string
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				parts = append(parts, t)
+			}
+		}
+		if len(parts) > 5 {
+			return nil, fmt.Errorf("at most 5 types can be given, got %d", len(parts))
+		}
+		apiURL += "&types=" + url.QueryEscape(strings.Join(parts, "|"))
+	}
+
+	resp, err := http.Get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("places autocomplete request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	var data struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Predictions  []struct {
+			Description          string   `json:"description"`
+			PlaceID              string   `json:"place_id"`
+			Types                []string `json:"types"`
+			DistanceMeters       *int     `json:"distance_meters"`
+			StructuredFormatting struct {
+				MainText      string `json:"main_text"`
+				SecondaryText string `json:"secondary_text"`
+			} `json:"structured_formatting"`
+		} `json:"predictions"`
+	}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse autocomplete response: %w", err)
+	}
+
+	if data.Status != "OK" && data.Status != "ZERO_RESULTS" {
+		return nil, fmt.Errorf("Google Places API error: %s - %s", data.Status, data.ErrorMessage)
+	}
+
+	if data.Status == "ZERO_RESULTS" {
+		return textContent("No place predictions found."), nil
+	}
+
+	// Predictions come back ranked by relevance; keep that order
+	var predictions []map[string]interface{}
+	for i, pred := range data.Predictions {
+		prediction := map[string]interface{}{
+			"rank":           i + 1,
+			"place_id":       pred.PlaceID,
+			"description":    pred.Description,
+			"main_text":      pred.StructuredFormatting.MainText,
+			"secondary_text": pred.StructuredFormatting.SecondaryText,
+			"types":          pred.Types,
+		}
+		if pred.DistanceMeters != nil {
+			prediction["distance_meters"] = *pred.DistanceMeters
+		}
+		predictions = append(predictions, prediction)
+	}
+
+	result, _ := json.MarshalIndent(map[string]interface{}{
+		"query":         query,
+		"total_results": len(predictions),
+		"predictions":   predictions,
+	}, "", "  ")
+
+	return textContent(string(result)), nil
+}
+
+// maxStaticMapBytes bounds a static map download. Maps at the largest size
+// are a few hundred KB, so anything bigger is not an image we asked for.
+const maxStaticMapBytes = 2 << 20
+
+// staticMapURL is the Static Maps API endpoint; tests point it elsewhere
+var staticMapURL = "https://maps.googleapis.com/maps/api/staticmap"
+
+func (p *Provider) staticMap(args map[string]interface{}) (interface{}, error) {
+	center, err := getStringRequired(args, "center")
+	if err != nil {
+		return nil, err
+	}
+
+	zoom := int(getNumber(args, "zoom", 14))
+	if zoom < 0 || zoom > 21 {
+		return nil, fmt.Errorf("zoom must be between 0 and 21")
+	}
+	width := int(getNumber(args, "width", 600))
+	height := int(getNumber(args, "height", 400))
+	if width < 1 || width > 640 || height < 1 || height > 640 {
+		return nil, fmt.Errorf("width and height must be between 1 and 640")
+	}
+	mapType := getString(args, "maptype")
+	switch mapType {
+	case "":
+		mapType = "roadmap"
+	case "roadmap", "satellite", "terrain", "hybrid":
+	default:
+		return nil, fmt.Errorf("invalid maptype %q (expected roadmap, satellite, terrain or hybrid)", mapType)
+	}
+
+	params := url.Values{}
+	params.Set("center", center)
+	params.Set("zoom", fmt.Sprintf("%d", zoom))
+	params.Set("size", fmt.Sprintf("%dx%d", width, height))
+	params.Set("maptype", mapType)
+	params.Set("format", "png")
+	params.Set("key", config.APIKey)
+	markers := getString(args, "markers")
+	if markers == "" {
+		markers = center
+	}
+	for _, m := range strings.Split(markers, "|") {
+		if m = strings.TrimSpace(m); m != "" {
+			params.Add("markers", m)
+		}
+	}
+
+	resp, err := http.Get(staticMapURL + "?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("static map request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxStaticMapBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static map: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Google Static Maps API error: %s - %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if len(body) > maxStaticMapBytes {
+		return nil, fmt.Errorf("static map is over %d bytes", maxStaticMapBytes)
+	}
+	mimeType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, fmt.Errorf("Google Static Maps API returned %s instead of an image", mimeType)
+	}
+
+	return tools.ImageContent(body, mimeType, fmt.Sprintf("Map of %s (zoom %d, %dx%d, %s)", center, zoom, width, height, mapType)), nil
+}
@@ -0,0 +1,155 @@
+package places
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// useTestAPI points the provider at a test server with a fake API key for
+// the duration of a test
+func useTestAPI(t *testing.T, target *string, handler http.HandlerFunc) {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	oldConfig, oldTarget := config, *target
+	config, *target = &placesConfig{APIKey: "test-key"}, ts.URL
+	t.Cleanup(func() {
+		ts.Close()
+		config, *target = oldConfig, oldTarget
+	})
+}
+
+func TestStaticMapArguments(t *testing.T) {
+	p := &Provider{available: true}
+	for _, args := range []map[string]interface{}{
+		{},
+		{"center": "Berlin", "zoom": float64(22)},
+		{"center": "Berlin", "width": float64(641)},
+		{"center": "Berlin", "height": float64(0)},
+		{"center": "Berlin", "maptype": "street"},
+	} {
+		if _, err := p.Call("places_static_map", args); err == nil {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestStaticMap(t *testing.T) {
+	png := []byte("\x89PNG fake image")
+	var query url.Values
+	useTestAPI(t, &staticMapURL, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	})
+
+	p := &Provider{available: true}
+	result, err := p.Call("places_static_map", map[string]interface{}{
+		"center":  "Louvre, Paris",
+		"zoom":    float64(16),
+		"markers": "Louvre, Paris| 48.8584,2.2945 |",
+		"maptype": "satellite",
+	})
+	if err != nil {
+		t.Fatalf("places_static_map: %v", err)
+	}
+
+	if query.Get("center") != "Louvre, Paris" || query.Get("zoom") != "16" || query.Get("size") != "600x400" ||
+		query.Get("maptype") != "satellite" || query.Get("key") != "test-key" {
+		t.Errorf("unexpected request %v", query)
+	}
+	if markers := strings.Join(query["markers"], "|"); markers != "Louvre, Paris|48.8584,2.2945" {
+		t.Errorf("markers = %q, want each trimmed and empty ones dropped", markers)
+	}
+
+	content := result.(map[string]interface{})["content"].([]map[string]interface{})
+	if len(content) != 2 || content[0]["type"] != "image" || content[0]["mimeType"] != "image/png" || content[0]["data"] != base64.StdEncoding.EncodeToString(png) {
+		t.Fatalf("expected the image, got %v", content)
+	}
+	if caption, _ := content[1]["text"].(string); !strings.Contains(caption, "Map of Louvre, Paris (zoom 16, 600x400, satellite)") {
+		t.Errorf("unexpected caption %q", caption)
+	}
+}
+
+func TestStaticMapRejectsNonImages(t *testing.T) {
+	useTestAPI(t, &staticMapURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html>quota exceeded</html>"))
+	})
+	p := &Provider{available: true}
+	if _, err := p.Call("places_static_map", map[string]interface{}{"center": "Berlin"}); err == nil || !strings.Contains(err.Error(), "instead of an image") {
+		t.Errorf("expected a non-image response to be rejected, got %v", err)
+	}
+}
+
+func TestAutocompleteArguments(t *testing.T) {
+	useTestAPI(t, &autocompleteURL, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("invalid arguments reached the API: %s", r.URL)
+	})
+	p := &Provider{available: true}
+	for _, args := range []map[string]interface{}{
+		{},
+		{"query": "cafe", "lat": float64(52.5)},
+		{"query": "cafe", "lon": float64(13.4)},
+		{"query": "cafe", "lat": float64(91), "lon": float64(13.4)},
+		{"query": "cafe", "lat": float64(52.5), "lon": float64(-181)},
+		{"query": "cafe", "types": "a,b,c,d,e,f"},
+	} {
+		if _, err := p.Call("places_autocomplete", args); err == nil {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestAutocomplete(t *testing.T) {
+	var query url.Values
+	useTestAPI(t, &autocompleteURL, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"status": "OK", "predictions": [
+			{"description": "Café Einstein, Berlin", "place_id": "p1", "types": ["cafe"], "distance_meters": 120,
+			 "structured_formatting": {"main_text": "Café Einstein", "secondary_text": "Berlin"}},
+			{"description": "Café Kranzler, Berlin", "place_id": "p2", "types": ["cafe"]}
+		]}`))
+	})
+
+	p := &Provider{available: true}
+	result, err := p.Call("places_autocomplete", map[string]interface{}{
+		"query":  "café ber",
+		"lat":    float64(52.5),
+		"lon":    float64(13.4),
+		"radius": float64(100000),
+		"types":  " cafe , ,restaurant",
+	})
+	if err != nil {
+		t.Fatalf("places_autocomplete: %v", err)
+	}
+
+	if query.Get("input") != "café ber" || query.Get("key") != "test-key" ||
+		query.Get("location") != "52.500000,13.400000" || query.Get("types") != "cafe|restaurant" {
+		t.Errorf("unexpected request %v", query)
+	}
+	if query.Get("radius") != "50000" {
+		t.Errorf("radius = %q, want an out-of-range radius capped at 50000", query.Get("radius"))
+	}
+
+	content := result.(map[string]interface{})["content"].([]map[string]interface{})
+	text, _ := content[0]["text"].(string)
+	for _, want := range []string{`"total_results": 2`, `"rank": 1`, `"place_id": "p1"`, `"distance_meters": 120`, `"main_text": "Café Einstein"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %s in the result, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestAutocompleteAPIError(t *testing.T) {
+	useTestAPI(t, &autocompleteURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "REQUEST_DENIED", "error_message": "bad key"}`))
+	})
+	p := &Provider{available: true}
+	if _, err := p.Call("places_autocomplete", map[string]interface{}{"query": "cafe"}); err == nil || !strings.Contains(err.Error(), "REQUEST_DENIED - bad key") {
+		t.Errorf("expected the API error, got %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidateArguments checks tool-call arguments against a tool's InputSchema and
+// returns one message per invalid field (nil if the arguments are valid).
+//
+// It implements the subset of JSON Schema used by Diane's builtin tools:
+// type, properties, required, enum, items and additionalProperties=false.
+// Unknown keywords are ignored, and a nil or empty schema accepts anything.
+func ValidateArguments(schema map[string]interface{}, args map[string]interface{}) []string {
+	if len(schema) == 0 {
+		return nil
+	}
+	var obj interface{} = args
+	if args == nil {
+		obj = map[string]interface{}{}
+	}
+	return validateValue("", schema, obj)
+}
+
+// HideArguments returns a copy of schema without the named properties, so a
+// tool can be offered with fewer arguments. schema itself is not modified.
+// Hiding an argument the schema doesn't have, or one it requires, is an
+// error: the tool could no longer be called correctly.
+func HideArguments(schema map[string]interface{}, hidden []string) (map[string]interface{}, error) {
+	if len(hidden) == 0 {
+		return schema, nil
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	for _, name := range toStrings(schema["required"]) {
+		required[name] = true
+	}
+	isHidden := make(map[string]bool, len(hidden))
+	for _, name := range hidden {
+		if _, ok := properties[name]; !ok {
+			return nil, fmt.Errorf("no argument %q", name)
+		}
+		if required[name] {
+			return nil, fmt.Errorf("argument %q is required", name)
+		}
+		isHidden[name] = true
+	}
+
+	kept := make(map[string]interface{}, len(properties))
+	for name, prop := range properties {
+		if !isHidden[name] {
+			kept[name] = prop
+		}
+	}
+	out := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		out[k] = v
+	}
+	out["properties"] = kept
+	return out, nil
+}
+
+func validateValue(path string, schema map[string]interface{}, value interface{}) []string {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if valueHasType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", fieldName(path), strings.Join(types, " or "), jsonTypeOf(value))}
+		}
+	}
+
+	if enum := toSlice(schema["enum"]); len(enum) > 0 {
+		found := false
+		for _, allowed := range enum {
+			if valuesEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return []string{fmt.Sprintf("%s: must be one of %v", fieldName(path), enum)}
+		}
+	}
+
+	var errs []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		errs = append(errs, validateObject(path, schema, v)...)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, validateValue(fmt.Sprintf("%s[%d]", path, i), items, item)...)
+			}
+		}
+	}
+	return errs
+}
+
+func validateObject(path string, schema map[string]interface{}, obj map[string]interface{}) []string {
+	var errs []string
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	for _, name := range toStrings(schema["required"]) {
+		if v, ok := obj[name]; !ok || v == nil {
+			errs = append(errs, fmt.Sprintf("%s: required field is missing", fieldName(joinPath(path, name))))
+		}
+	}
+
+	// Sort keys so error messages are stable
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, name := range keys {
+		value := obj[name]
+		propSchema, known := properties[name].(map[string]interface{})
+		if !known {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				errs = append(errs, fmt.Sprintf("%s: unknown field", fieldName(joinPath(path, name))))
+			}
+			continue
+		}
+		// Optional fields sent as null are treated as absent
+		if value == nil {
+			continue
+		}
+		errs = append(errs, validateValue(joinPath(path, name), propSchema, value)...)
+	}
+	return errs
+}
+
+// valueHasType reports whether a decoded JSON value matches a JSON Schema type
+func valueHasType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		return isNumber(value)
+	case "integer":
+		if !isNumber(value) {
+			return false
+		}
+		f := toFloat(value)
+		return f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	// Unknown types are not enforced
+	return true
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if isNumber(value) {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case float64, float32, int, int32, int64:
+		return true
+	}
+	return false
+}
+
+func toFloat(value interface{}) float64 {
+	switch n := value.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return 0
+}
+
+func valuesEqual(a, b interface{}) bool {
+	if isNumber(a) && isNumber(b) {
+		return toFloat(a) == toFloat(b)
+	}
+	return a == b
+}
+
+// schemaTypes normalizes the "type" keyword, which may be a string or a list
+func schemaTypes(v interface{}) []string {
+	if s, ok := v.(string); ok {
+		return []string{s}
+	}
+	return toStrings(v)
+}
+
+// toStrings accepts both []string (schemas built in Go) and []interface{}
+// (schemas decoded from JSON).
+func toStrings(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func toSlice(v interface{}) []interface{} {
+	switch list := v.(type) {
+	case []interface{}:
+		return list
+	case []string:
+		out := make([]interface{}, len(list))
+		for i, s := range list {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func fieldName(path string) string {
+	if path == "" {
+		return "arguments"
+	}
+	return path
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":    map[string]interface{}{"type": "string"},
+			"limit":   map[string]interface{}{"type": "integer"},
+			"lat":     map[string]interface{}{"type": "number"},
+			"enabled": map[string]interface{}{"type": "boolean"},
+			"units":   map[string]interface{}{"type": "string", "enum": []string{"metric", "imperial"}},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+		"required": []string{"name"},
+	}
+}
+
+func TestValidateArguments_Valid(t *testing.T) {
+	args := map[string]interface{}{
+		"name":    "job",
+		"limit":   float64(10),
+		"lat":     52.2,
+		"enabled": true,
+		"units":   "metric",
+		"tags":    []interface{}{"a", "b"},
+		"extra":   "ignored",
+	}
+	if errs := ValidateArguments(testSchema(), args); len(errs) != 0 {
+		t.Errorf("expected no errors, got: %v", errs)
+	}
+}
+
+func TestValidateArguments_Errors(t *testing.T) {
+	args := map[string]interface{}{
+		"limit":   "ten",
+		"lat":     "52.2",
+		"enabled": "yes",
+		"units":   "kelvin",
+		"tags":    []interface{}{"a", float64(2)},
+	}
+	errs := ValidateArguments(testSchema(), args)
+
+	want := []string{
+		"name: required field is missing",
+		"enabled: expected boolean, got string",
+		"lat: expected number, got string",
+		"limit: expected integer, got string",
+		"tags[1]: expected string, got number",
+		"units: must be one of",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(errs), errs)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(errs[i], w) {
+			t.Errorf("error %d: expected prefix %q, got %q", i, w, errs[i])
+		}
+	}
+}
+
+func TestValidateArguments_IntegerRejectsFraction(t *testing.T) {
+	errs := ValidateArguments(testSchema(), map[string]interface{}{"name": "x", "limit": 1.5})
+	if len(errs) != 1 || !strings.Contains(errs[0], "expected integer") {
+		t.Errorf("expected integer error, got: %v", errs)
+	}
+}
+
+func TestValidateArguments_NilArgsAndNullOptional(t *testing.T) {
+	if errs := ValidateArguments(testSchema(), nil); len(errs) != 1 {
+		t.Errorf("expected missing required field error for nil args, got: %v", errs)
+	}
+	errs := ValidateArguments(testSchema(), map[string]interface{}{"name": "x", "limit": nil})
+	if len(errs) != 0 {
+		t.Errorf("expected null optional field to be accepted, got: %v", errs)
+	}
+}
+
+func TestValidateArguments_EmptySchema(t *testing.T) {
+	if errs := ValidateArguments(nil, map[string]interface{}{"anything": 1}); errs != nil {
+		t.Errorf("expected nil schema to accept anything, got: %v", errs)
+	}
+}
+
+func TestHideArguments(t *testing.T) {
+	schema := testSchema()
+	hidden, err := HideArguments(schema, []string{"lat", "tags"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props := hidden["properties"].(map[string]interface{})
+	if _, ok := props["lat"]; ok || len(props) != 4 {
+		t.Errorf("expected lat and tags hidden, got %v", props)
+	}
+	if len(schema["properties"].(map[string]interface{})) != 6 {
+		t.Error("original schema should be unchanged")
+	}
+
+	if _, err := HideArguments(schema, []string{"name"}); err == nil || !strings.Contains(err.Error(), "required") {
+		t.Errorf("expected error hiding a required argument, got %v", err)
+	}
+	if _, err := HideArguments(schema, []string{"nope"}); err == nil {
+		t.Error("expected error hiding an unknown argument")
+	}
+}
@@ -3,6 +3,7 @@ package tools
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os/exec"
@@ -95,6 +96,25 @@ type ResourceProvider interface {
 	ReadResource(uri string) (*ResourceContent, error)
 }
 
+// --- MCP Completion ---
+
+// CompletionRef identifies what a completion/complete request is for: a
+// prompt ("ref/prompt", with Name) or a resource ("ref/resource", with URI)
+type CompletionRef struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// Completer is optionally implemented by providers that can suggest values
+// for their prompt arguments or resource URIs
+type Completer interface {
+	// Complete returns candidate values for the named argument, given the
+	// partial value typed so far. ok is false if ref isn't one of this
+	// provider's prompts or resources.
+	Complete(ref CompletionRef, argument, value string) (values []string, ok bool)
+}
+
 // --- Response Helpers ---
 
 // TextContent creates an MCP text content response
@@ -109,6 +129,22 @@ func TextContent(text string) map[string]interface{} {
 	}
 }
 
+// ImageContent creates an MCP image content response, base64 encoding data.
+// A non-empty caption is added as a text block after the image.
+func ImageContent(data []byte, mimeType, caption string) map[string]interface{} {
+	content := []map[string]interface{}{
+		{
+			"type":     "image",
+			"data":     base64.StdEncoding.EncodeToString(data),
+			"mimeType": mimeType,
+		},
+	}
+	if caption != "" {
+		content = append(content, map[string]interface{}{"type": "text", "text": caption})
+	}
+	return map[string]interface{}{"content": content}
+}
+
 // JSONContent creates an MCP text content response from JSON data
 func JSONContent(data interface{}) (map[string]interface{}, error) {
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
@@ -118,6 +154,15 @@ func JSONContent(data interface{}) (map[string]interface{}, error) {
 	return TextContent(string(jsonBytes)), nil
 }
 
+// ErrorContent creates an MCP tool result reporting a failure. Tool failures
+// go in the result with isError set, so the model can see them; JSON-RPC
+// errors are for protocol problems like unknown tools or bad arguments.
+func ErrorContent(text string) map[string]interface{} {
+	result := TextContent(text)
+	result["isError"] = true
+	return result
+}
+
 // ErrorResponse creates an MCP error response
 func ErrorResponse(code int, message string) error {
 	return &ToolError{Code: code, Message: message}
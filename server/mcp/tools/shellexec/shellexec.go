@@ -0,0 +1,433 @@
+// Package shellexec provides an opt-in shell_exec tool that runs commands
+// from a configured allowlist. Commands are executed directly, without a
+// shell, unless shell metacharacters are explicitly allowed.
+package shellexec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultTimeout        = 30 * time.Second
+	defaultMaxOutputBytes = 64 * 1024
+
+	// killGrace is how long to wait for output pipes to close after killing
+	// a command, in case something outside its process group holds them
+	killGrace = 5 * time.Second
+)
+
+// shellMetacharacters change what a shell would run; without a shell they'd
+// be passed through literally, which is never what the caller meant. A
+// leading "~/" is expanded without a shell, so it isn't one of them.
+const shellMetacharacters = ";&|<>$`(){}*?[]!\n"
+
+// Tool represents an MCP tool definition
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// Config controls which commands may run. Zero values fall back to the
+// package defaults, except AllowedCommands: with none nothing can run.
+type Config struct {
+	// AllowedCommands are the exact binaries that may be run, as a bare
+	// name ("git") or an absolute path ("/usr/local/bin/backup.sh"). The
+	// command's first word must match an entry exactly.
+	AllowedCommands []string
+	// AllowShell runs commands through "sh -c" so pipes, redirects and
+	// globs work. Every command in a pipeline or list must still be on
+	// the allowlist, and command substitution is always refused.
+	AllowShell     bool
+	Timeout        time.Duration
+	MaxOutputBytes int
+}
+
+// Provider implements the shell_exec tool
+type Provider struct {
+	allowed    map[string]bool
+	allowShell bool
+	timeout    time.Duration
+	maxOutput  int
+}
+
+// NewProvider creates a new shell_exec provider
+func NewProvider(cfg Config) *Provider {
+	p := &Provider{
+		allowed:    make(map[string]bool),
+		allowShell: cfg.AllowShell,
+		timeout:    cfg.Timeout,
+		maxOutput:  cfg.MaxOutputBytes,
+	}
+	for _, c := range cfg.AllowedCommands {
+		if c = strings.TrimSpace(c); c != "" {
+			p.allowed[c] = true
+		}
+	}
+	if p.timeout <= 0 {
+		p.timeout = defaultTimeout
+	}
+	if p.maxOutput <= 0 {
+		p.maxOutput = defaultMaxOutputBytes
+	}
+	return p
+}
+
+// CheckDependencies verifies at least one command is allowed
+func (p *Provider) CheckDependencies() error {
+	if len(p.allowed) == 0 {
+		return fmt.Errorf("shell_exec.allowed_commands is empty in config.json")
+	}
+	return nil
+}
+
+// Tools returns the list of shell tools
+func (p *Provider) Tools() []Tool {
+	names := make([]string, 0, len(p.allowed))
+	for c := range p.allowed {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+	mode := "Commands run directly, without a shell: pipes, redirects, globs and variables are not supported."
+	if p.allowShell {
+		mode = "Commands run through sh, so pipes and redirects work, but every command used must be allowed."
+	}
+	return []Tool{
+		{
+			Name: "shell_exec",
+			Description: fmt.Sprintf("Run an allowlisted command and return its stdout, stderr and exit code. Allowed commands: %s. %s",
+				strings.Join(names, ", "), mode),
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []string{"command"},
+				"properties": map[string]interface{}{
+					"command": map[string]interface{}{
+						"type":        "string",
+						"description": "Command line to run, e.g. \"git -C ~/notes status --short\". Quote arguments containing spaces.",
+					},
+					"cwd": map[string]interface{}{
+						"type":        "string",
+						"description": "Working directory (default: home directory)",
+					},
+					"timeout": map[string]interface{}{
+						"type":        "number",
+						"description": fmt.Sprintf("Timeout in seconds (default and maximum: %d)", int(p.timeout.Seconds())),
+					},
+				},
+			},
+		},
+	}
+}
+
+// HasTool checks if a tool name belongs to this provider
+func (p *Provider) HasTool(name string) bool {
+	return name == "shell_exec"
+}
+
+// Call executes a shell tool
+func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+	switch name {
+	case "shell_exec":
+		return p.exec(args)
+	default:
+		return nil, fmt.Errorf("unknown tool: %s", name)
+	}
+}
+
+func (p *Provider) exec(args map[string]interface{}) (interface{}, error) {
+	command, _ := args["command"].(string)
+	if strings.TrimSpace(command) == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+
+	argv, err := p.checkCommand(command)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := p.timeout
+	if secs, ok := args["timeout"].(float64); ok && secs > 0 && time.Duration(secs*float64(time.Second)) < timeout {
+		timeout = time.Duration(secs * float64(time.Second))
+	}
+
+	home, _ := os.UserHomeDir()
+	cwd, _ := args["cwd"].(string)
+	if cwd == "" {
+		cwd = home
+	} else if strings.HasPrefix(cwd, "~/") {
+		cwd = filepath.Join(home, cwd[2:])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if argv == nil {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	} else {
+		cmd = exec.CommandContext(ctx, argv[0], argv[1:]...)
+	}
+	cmd.Dir = cwd
+	// Kill the whole process group on timeout, so children a shell
+	// spawned don't outlive it
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = killGrace
+	stdout := &cappedBuffer{max: p.maxOutput}
+	stderr := &cappedBuffer{max: p.maxOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	runErr := cmd.Run()
+	duration := time.Since(start)
+
+	exitCode := 0
+	timedOut := ctx.Err() == context.DeadlineExceeded
+	var exitErr *exec.ExitError
+	switch {
+	case timedOut:
+		exitCode = -1
+	case errors.As(runErr, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case runErr != nil:
+		return nil, fmt.Errorf("failed to run command: %w", runErr)
+	}
+
+	result := map[string]interface{}{
+		"command":     command,
+		"exit_code":   exitCode,
+		"stdout":      stdout.String(),
+		"stderr":      stderr.String(),
+		"duration_ms": duration.Milliseconds(),
+	}
+	if timedOut {
+		result["timed_out"] = true
+		result["error"] = fmt.Sprintf("command killed after %s", timeout)
+	}
+	if stdout.truncated || stderr.truncated {
+		result["truncated"] = true
+	}
+	return textContent(result), nil
+}
+
+// checkCommand validates a command line against the allowlist. It returns
+// the argv to execute directly, or nil when the command must go through
+// the shell.
+func (p *Provider) checkCommand(command string) ([]string, error) {
+	argv, hasMeta, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("command is required")
+	}
+
+	if !hasMeta {
+		if !p.allowed[argv[0]] {
+			return nil, fmt.Errorf("command %q is not in shell_exec.allowed_commands", argv[0])
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			for i, arg := range argv[1:] {
+				if strings.HasPrefix(arg, "~/") {
+					argv[i+1] = filepath.Join(home, arg[2:])
+				}
+			}
+		}
+		return argv, nil
+	}
+
+	if !p.allowShell {
+		return nil, fmt.Errorf("shell metacharacters are not allowed; run a single command without pipes, redirects, globs or variables")
+	}
+	if strings.Contains(command, "$(") || strings.Contains(command, "`") {
+		return nil, fmt.Errorf("command substitution is not allowed")
+	}
+	commands, hasRedirect, err := shellCommands(command)
+	if err != nil {
+		return nil, err
+	}
+	for i, words := range commands {
+		if len(words) == 0 || words[0] == "" {
+			if hasRedirect[i] || len(words) > 0 {
+				return nil, fmt.Errorf("every command must start with an allowed command, not a redirect")
+			}
+			continue
+		}
+		if !p.allowed[words[0]] {
+			return nil, fmt.Errorf("command %q is not in shell_exec.allowed_commands", words[0])
+		}
+	}
+	return nil, nil
+}
+
+// splitCommand splits a command line into words, honouring single and
+// double quotes and backslash escapes. hasMeta reports whether any shell
+// metacharacter appears outside quotes.
+func splitCommand(s string) (words []string, hasMeta bool, err error) {
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			if strings.ContainsRune(shellMetacharacters, r) {
+				hasMeta = true
+			}
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, false, fmt.Errorf("unterminated quote or escape in command")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, hasMeta, nil
+}
+
+// shellCommands splits a shell command line into the words of the
+// individual commands of its pipelines and lists. It tokenizes like
+// splitCommand, so quotes and backslash escapes can't hide a separator, and
+// leaves out redirects and their targets. hasRedirect reports, per command,
+// whether it had any redirect, so a command made only of redirects can be
+// told apart from an empty list entry.
+func shellCommands(s string) (commands [][]string, hasRedirect []bool, err error) {
+	var words []string
+	redirect := false
+	var cur strings.Builder
+	inWord := false
+	target := false // the next word is a redirect target
+	var quote rune
+	escaped := false
+	afterRedirect := false // still in a redirect operator such as ">&" or ">>"
+
+	endWord := func() {
+		if inWord {
+			if target {
+				target = false
+			} else {
+				words = append(words, cur.String())
+			}
+			cur.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		commands = append(commands, words)
+		hasRedirect = append(hasRedirect, redirect)
+		words, redirect, target = nil, false, false
+	}
+
+	for _, r := range s {
+		if afterRedirect && quote == 0 && !escaped && (r == '>' || r == '<' || r == '&' || r == '|') {
+			continue
+		}
+		afterRedirect = false
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			endWord()
+		case r == '<' || r == '>':
+			// A word glued to the operator, like the 2 in 2>, is a file
+			// descriptor rather than an argument
+			if inWord {
+				cur.Reset()
+				inWord = false
+			}
+			redirect, target, afterRedirect = true, true, true
+		case r == '|' || r == ';' || r == '&' || r == '\n' || r == '(' || r == ')':
+			endCommand()
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, nil, fmt.Errorf("unterminated quote or escape in command")
+	}
+	endCommand()
+	return commands, hasRedirect, nil
+}
+
+// cappedBuffer keeps at most max bytes of output, discarding the rest
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(b []byte) (int, error) {
+	if room := c.max - c.buf.Len(); room < len(b) {
+		c.truncated = true
+		if room > 0 {
+			c.buf.Write(b[:room])
+		}
+		return len(b), nil
+	}
+	return c.buf.Write(b)
+}
+
+func (c *cappedBuffer) String() string {
+	return c.buf.String()
+}
+
+func textContent(data interface{}) map[string]interface{} {
+	jsonBytes, _ := json.MarshalIndent(data, "", "  ")
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": string(jsonBytes),
+			},
+		},
+	}
+}
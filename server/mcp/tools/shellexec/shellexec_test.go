@@ -0,0 +1,108 @@
+package shellexec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitCommand(t *testing.T) {
+	words, hasMeta, err := splitCommand(`git commit -m "fix: a; b" 'it''s' a\ b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"git", "commit", "-m", "fix: a; b", "its", "a b"}
+	if strings.Join(words, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %q, got %q", want, words)
+	}
+	if hasMeta {
+		t.Error("quoted metacharacters should not count")
+	}
+
+	if _, hasMeta, _ := splitCommand("ls *.txt"); !hasMeta {
+		t.Error("expected unquoted glob to be detected")
+	}
+	if _, _, err := splitCommand(`echo "unterminated`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
+
+func TestCheckCommand(t *testing.T) {
+	strict := NewProvider(Config{AllowedCommands: []string{"echo", "uptime"}})
+	shell := NewProvider(Config{AllowedCommands: []string{"echo", "grep"}, AllowShell: true})
+
+	tests := []struct {
+		name    string
+		p       *Provider
+		command string
+		wantErr string
+	}{
+		{"allowed", strict, "echo hello", ""},
+		{"not allowed", strict, "rm -rf /tmp/x", "not in shell_exec.allowed_commands"},
+		{"path does not match name", strict, "/bin/echo hi", "not in shell_exec.allowed_commands"},
+		{"pipe refused", strict, "echo hi | grep h", "metacharacters are not allowed"},
+		{"chained refused", strict, "echo hi; rm -rf /", "metacharacters are not allowed"},
+		{"pipe allowed", shell, "echo hi | grep h > out.txt", ""},
+		{"pipe to other binary", shell, "echo hi | sh", `"sh" is not in`},
+		{"list with other binary", shell, "echo hi && rm x", `"rm" is not in`},
+		{"substitution", shell, "echo $(rm x)", "command substitution"},
+		{"backticks", shell, "echo `rm x`", "command substitution"},
+		{"redirect first", shell, ">/dev/null id", `"id" is not in`},
+		{"only a redirect", shell, "echo hi; > /etc/motd", "not a redirect"},
+		{"redirect then allowed", shell, "2>/dev/null echo hi", ""},
+		{"redirect to fd", shell, "echo oops >&2 | grep o", ""},
+		{"escaped quotes hide separator", shell, `echo \"; id; echo \"`, `"id" is not in`},
+		{"redirect target is not a command", shell, "echo hi >& rm", ""},
+		{"process substitution", shell, "grep x <(rm y)", `"rm" is not in`},
+	}
+	for _, tt := range tests {
+		_, err := tt.p.checkCommand(tt.command)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestExec(t *testing.T) {
+	p := NewProvider(Config{AllowedCommands: []string{"sh"}, MaxOutputBytes: 5})
+	result, err := p.Call("shell_exec", map[string]interface{}{"command": `sh -c "echo hello world; echo oops >&2; exit 3"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	var out struct {
+		ExitCode  int    `json:"exit_code"`
+		Stdout    string `json:"stdout"`
+		Stderr    string `json:"stderr"`
+		Truncated bool   `json:"truncated"`
+	}
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ExitCode != 3 || out.Stdout != "hello" || out.Stderr != "oops\n" || !out.Truncated {
+		t.Errorf("unexpected result: %+v", out)
+	}
+}
+
+func TestExecTimeoutKillsProcessGroup(t *testing.T) {
+	p := NewProvider(Config{AllowedCommands: []string{"sh"}})
+	start := time.Now()
+	result, err := p.Call("shell_exec", map[string]interface{}{
+		"command": `sh -c "sleep 30 & wait"`,
+		"timeout": 0.2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected the background sleep to be killed with the shell, took %s", elapsed)
+	}
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, `"timed_out": true`) {
+		t.Errorf("expected a timed out result, got %s", text)
+	}
+}
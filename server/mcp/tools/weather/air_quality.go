@@ -0,0 +1,199 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const airQualityURL = "https://air-quality-api.open-meteo.com/v1/air-quality"
+
+// airPollutants maps Open-Meteo's pollutant variables to display names. Each
+// has a matching "us_aqi_<variable>" sub-index used to find the dominant one.
+var airPollutants = []struct {
+	variable string
+	name     string
+}{
+	{"pm2_5", "PM2.5"},
+	{"pm10", "PM10"},
+	{"ozone", "O3"},
+	{"nitrogen_dioxide", "NO2"},
+	{"sulphur_dioxide", "SO2"},
+	{"carbon_monoxide", "CO"},
+}
+
+// getAirQuality returns current air quality on the US EPA AQI scale
+func (p *Provider) getAirQuality(args map[string]interface{}) (interface{}, error) {
+	lat, lon, location, err := resolveLocation(args)
+	if err != nil {
+		return nil, err
+	}
+
+	forecastDays := 3
+	if v, ok := args["forecast_days"].(float64); ok {
+		forecastDays = int(v)
+	}
+	if forecastDays < 0 || forecastDays > 5 {
+		return nil, fmt.Errorf("forecast_days must be between 0 and 5")
+	}
+
+	current := []string{"us_aqi"}
+	for _, pol := range airPollutants {
+		current = append(current, pol.variable, "us_aqi_"+pol.variable)
+	}
+	apiURL := fmt.Sprintf("%s?latitude=%f&longitude=%f&current=%s&timezone=auto",
+		airQualityURL, lat, lon, strings.Join(current, ","))
+	if forecastDays > 0 {
+		// The API counts today as the first forecast day
+		apiURL += fmt.Sprintf("&hourly=us_aqi&forecast_days=%d", forecastDays+1)
+	}
+
+	data, err := fetchAirQuality(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	aqi, hasAQI := numberField(data.Current, "us_aqi")
+	result := map[string]interface{}{
+		"location": location,
+		"source":   "Open-Meteo air quality (CAMS)",
+		"scale":    "US EPA AQI (0-500)",
+	}
+	if t, ok := data.Current["time"].(string); ok {
+		result["time"] = t
+	}
+	if hasAQI {
+		result["aqi"] = int(aqi)
+		result["category"] = aqiCategory(int(aqi))
+	} else {
+		result["aqi"] = nil
+		result["category"] = "unknown"
+	}
+
+	// Dominant pollutant is the one with the highest AQI sub-index
+	pollutants := map[string]interface{}{}
+	dominant, dominantAQI := "", -1.0
+	for _, pol := range airPollutants {
+		entry := map[string]interface{}{"unit": data.CurrentUnits[pol.variable]}
+		if v, ok := numberField(data.Current, pol.variable); ok {
+			entry["concentration"] = v
+		}
+		if v, ok := numberField(data.Current, "us_aqi_"+pol.variable); ok {
+			entry["aqi"] = int(v)
+			if v > dominantAQI {
+				dominant, dominantAQI = pol.name, v
+			}
+		}
+		pollutants[pol.name] = entry
+	}
+	result["pollutants"] = pollutants
+	if dominant != "" {
+		result["dominant_pollutant"] = dominant
+	}
+
+	if forecastDays > 0 {
+		result["forecast"] = dailyAQIForecast(data.Hourly.Time, data.Hourly.USAQI, forecastDays)
+	}
+
+	return textContent(result), nil
+}
+
+type airQualityResponse struct {
+	Error        bool                   `json:"error"`
+	Reason       string                 `json:"reason"`
+	CurrentUnits map[string]string      `json:"current_units"`
+	Current      map[string]interface{} `json:"current"`
+	Hourly       struct {
+		Time  []string   `json:"time"`
+		USAQI []*float64 `json:"us_aqi"`
+	} `json:"hourly"`
+}
+
+func fetchAirQuality(apiURL string) (*airQualityResponse, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch air quality: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var data airQualityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse air quality response: %w", err)
+	}
+	if data.Error || resp.StatusCode != http.StatusOK {
+		if data.Reason != "" {
+			return nil, fmt.Errorf("Open-Meteo API error: %s", data.Reason)
+		}
+		return nil, fmt.Errorf("Open-Meteo API returned %s", resp.Status)
+	}
+	return &data, nil
+}
+
+// dailyAQIForecast reduces hourly AQI to each day's peak, skipping today
+// (already covered by the current reading).
+func dailyAQIForecast(times []string, values []*float64, days int) []map[string]interface{} {
+	peaks := map[string]float64{}
+	var dates []string
+	for i, t := range times {
+		v := valueAt(values, i)
+		if v == nil || len(t) < 10 {
+			continue
+		}
+		date := t[:10]
+		if prev, seen := peaks[date]; !seen {
+			dates = append(dates, date)
+			peaks[date] = *v
+		} else if *v > prev {
+			peaks[date] = *v
+		}
+	}
+	sort.Strings(dates)
+	if len(dates) > 0 {
+		dates = dates[1:]
+	}
+	if len(dates) > days {
+		dates = dates[:days]
+	}
+
+	forecast := []map[string]interface{}{}
+	for _, date := range dates {
+		forecast = append(forecast, map[string]interface{}{
+			"date":     date,
+			"max_aqi":  int(peaks[date]),
+			"category": aqiCategory(int(peaks[date])),
+		})
+	}
+	return forecast
+}
+
+// aqiCategory returns the US EPA health category for an AQI value
+func aqiCategory(aqi int) string {
+	switch {
+	case aqi <= 50:
+		return "Good"
+	case aqi <= 100:
+		return "Moderate"
+	case aqi <= 150:
+		return "Unhealthy for Sensitive Groups"
+	case aqi <= 200:
+		return "Unhealthy"
+	case aqi <= 300:
+		return "Very Unhealthy"
+	}
+	return "Hazardous"
+}
+
+func numberField(m map[string]interface{}, key string) (float64, bool) {
+	v, ok := m[key].(float64)
+	return v, ok
+}
@@ -0,0 +1,45 @@
+package weather
+
+import "testing"
+
+func TestAQICategory(t *testing.T) {
+	tests := map[int]string{
+		0:   "Good",
+		50:  "Good",
+		51:  "Moderate",
+		101: "Unhealthy for Sensitive Groups",
+		151: "Unhealthy",
+		201: "Very Unhealthy",
+		301: "Hazardous",
+	}
+	for aqi, want := range tests {
+		if got := aqiCategory(aqi); got != want {
+			t.Errorf("aqiCategory(%d) = %q, want %q", aqi, got, want)
+		}
+	}
+}
+
+func TestDailyAQIForecast(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	times := []string{
+		"2026-03-20T22:00", "2026-03-20T23:00",
+		"2026-03-21T08:00", "2026-03-21T14:00",
+		"2026-03-22T09:00", "2026-03-22T10:00",
+		"2026-03-23T09:00",
+	}
+	values := []*float64{f(200), f(10), f(40), f(120), f(30), nil, f(60)}
+
+	forecast := dailyAQIForecast(times, values, 2)
+	if len(forecast) != 2 {
+		t.Fatalf("expected 2 forecast days (today skipped), got %d: %v", len(forecast), forecast)
+	}
+	if forecast[0]["date"] != "2026-03-21" || forecast[0]["max_aqi"] != 120 {
+		t.Errorf("unexpected first day: %v", forecast[0])
+	}
+	if forecast[0]["category"] != "Unhealthy for Sensitive Groups" {
+		t.Errorf("unexpected first day category: %v", forecast[0]["category"])
+	}
+	if forecast[1]["date"] != "2026-03-22" || forecast[1]["max_aqi"] != 30 {
+		t.Errorf("unexpected second day: %v", forecast[1])
+	}
+}
@@ -0,0 +1,196 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Open-Meteo's archive is built from ERA5 reanalysis: it starts in 1940 and
+// lags real time by a few days.
+const (
+	historyArchiveURL = "https://archive-api.open-meteo.com/v1/archive"
+	historyLagDays    = 5
+	historyMaxDays    = 366
+)
+
+var historyEarliest = time.Date(1940, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// getWeatherHistory returns daily observed weather for a past date range
+func (p *Provider) getWeatherHistory(args map[string]interface{}) (interface{}, error) {
+	dateStr, _ := args["date"].(string)
+	if dateStr == "" {
+		return nil, fmt.Errorf("date is required")
+	}
+	endStr, _ := args["end_date"].(string)
+
+	start, end, err := validateHistoryRange(dateStr, endStr, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	lat, lon, location, err := resolveLocation(args)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s?latitude=%f&longitude=%f&start_date=%s&end_date=%s"+
+		"&daily=temperature_2m_max,temperature_2m_min,precipitation_sum,weather_code&timezone=auto",
+		historyArchiveURL, lat, lon, start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	data, err := fetchHistory(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []map[string]interface{}
+	for i, date := range data.Daily.Time {
+		day := map[string]interface{}{"date": date}
+		if v := valueAt(data.Daily.TemperatureMax, i); v != nil {
+			day["temperature_max"] = *v
+		}
+		if v := valueAt(data.Daily.TemperatureMin, i); v != nil {
+			day["temperature_min"] = *v
+		}
+		if v := valueAt(data.Daily.PrecipitationSum, i); v != nil {
+			day["precipitation"] = *v
+		}
+		if v := valueAt(data.Daily.WeatherCode, i); v != nil {
+			day["conditions"] = wmoCondition(int(*v))
+		}
+		days = append(days, day)
+	}
+
+	result := map[string]interface{}{
+		"location": location,
+		"source":   "Open-Meteo historical weather archive",
+		"units": map[string]string{
+			"temperature":   data.DailyUnits.TemperatureMax,
+			"precipitation": data.DailyUnits.PrecipitationSum,
+		},
+		"days": days,
+	}
+	return textContent(result), nil
+}
+
+// validateHistoryRange parses the requested dates and checks they fall
+// inside the archive's window. end defaults to start.
+func validateHistoryRange(startStr, endStr string, now time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", startStr)
+	}
+	end := start
+	if endStr != "" {
+		end, err = time.Parse("2006-01-02", endStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date %q (expected YYYY-MM-DD)", endStr)
+		}
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date %s is before date %s", endStr, startStr)
+	}
+
+	latest := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -historyLagDays)
+	if start.Before(historyEarliest) || end.After(latest) {
+		return time.Time{}, time.Time{}, fmt.Errorf("historical weather is only available from %s to %s (about %d days ago); use weather_get_weather for recent or future dates",
+			historyEarliest.Format("2006-01-02"), latest.Format("2006-01-02"), historyLagDays)
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; days > historyMaxDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range covers %d days; at most %d days can be requested at once", days, historyMaxDays)
+	}
+	return start, end, nil
+}
+
+type historyResponse struct {
+	Error      bool   `json:"error"`
+	Reason     string `json:"reason"`
+	DailyUnits struct {
+		TemperatureMax   string `json:"temperature_2m_max"`
+		PrecipitationSum string `json:"precipitation_sum"`
+	} `json:"daily_units"`
+	Daily struct {
+		Time             []string   `json:"time"`
+		TemperatureMax   []*float64 `json:"temperature_2m_max"`
+		TemperatureMin   []*float64 `json:"temperature_2m_min"`
+		PrecipitationSum []*float64 `json:"precipitation_sum"`
+		WeatherCode      []*float64 `json:"weather_code"`
+	} `json:"daily"`
+}
+
+func fetchHistory(apiURL string) (*historyResponse, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weather history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var data historyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse weather history response: %w", err)
+	}
+	if data.Error || resp.StatusCode != http.StatusOK {
+		if data.Reason != "" {
+			return nil, fmt.Errorf("Open-Meteo API error: %s", data.Reason)
+		}
+		return nil, fmt.Errorf("Open-Meteo API returned %s", resp.Status)
+	}
+	return &data, nil
+}
+
+// valueAt returns the i'th value, or nil if it is missing from the archive
+func valueAt(values []*float64, i int) *float64 {
+	if i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+// wmoCondition describes a WMO weather interpretation code
+func wmoCondition(code int) string {
+	switch code {
+	case 0:
+		return "clear sky"
+	case 1:
+		return "mainly clear"
+	case 2:
+		return "partly cloudy"
+	case 3:
+		return "overcast"
+	case 45, 48:
+		return "fog"
+	case 51, 53, 55:
+		return "drizzle"
+	case 56, 57:
+		return "freezing drizzle"
+	case 61, 63:
+		return "rain"
+	case 65:
+		return "heavy rain"
+	case 66, 67:
+		return "freezing rain"
+	case 71, 73:
+		return "snow"
+	case 75:
+		return "heavy snow"
+	case 77:
+		return "snow grains"
+	case 80, 81, 82:
+		return "rain showers"
+	case 85, 86:
+		return "snow showers"
+	case 95:
+		return "thunderstorm"
+	case 96, 99:
+		return "thunderstorm with hail"
+	}
+	return "unknown"
+}
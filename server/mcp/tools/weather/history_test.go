@@ -0,0 +1,39 @@
+package weather
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateHistoryRange(t *testing.T) {
+	now := time.Date(2026, 3, 20, 15, 0, 0, 0, time.UTC)
+
+	start, end, err := validateHistoryRange("2025-03-20", "", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !start.Equal(end) {
+		t.Errorf("expected end to default to start, got %s..%s", start, end)
+	}
+
+	if _, _, err := validateHistoryRange("2025-03-01", "2025-03-31", now); err != nil {
+		t.Errorf("unexpected error for a month range: %v", err)
+	}
+
+	tests := []struct {
+		name, start, end, want string
+	}{
+		{"bad format", "20-03-2025", "", "invalid date"},
+		{"end before start", "2025-03-10", "2025-03-01", "before date"},
+		{"before archive", "1939-12-31", "", "only available from 1940-01-01"},
+		{"too recent", "2026-03-18", "", "only available from"},
+		{"range too long", "2020-01-01", "2021-06-01", "at most 366 days"},
+	}
+	for _, tt := range tests {
+		_, _, err := validateHistoryRange(tt.start, tt.end, now)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
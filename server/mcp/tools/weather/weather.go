@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/diane-assistant/diane/mcp/tools"
 )
 
 const userAgent = "diane github.com/diane-assistant/diane"
@@ -18,6 +20,8 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+	// CacheTTL, if set, lets identical calls within it reuse the result
+	CacheTTL time.Duration `json:"-"`
 }
 
 // Provider implements weather tools
@@ -36,9 +40,10 @@ func (p *Provider) CheckDependencies() error {
 
 // Tools returns the list of weather tools
 func (p *Provider) Tools() []Tool {
-	return []Tool{
+	list := []Tool{
 		{
 			Name:        "weather_get_weather",
+			CacheTTL:    10 * time.Minute,
 			Description: "Get weather forecast from yr.no (Norwegian Meteorological Institute). Provides detailed weather data including temperature, precipitation, wind, and conditions. Supports any location worldwide using latitude/longitude coordinates.",
 			InputSchema: map[string]interface{}{
 				"type":     "object",
@@ -61,6 +66,7 @@ func (p *Provider) Tools() []Tool {
 		},
 		{
 			Name:        "weather_search_location_weather",
+			CacheTTL:    10 * time.Minute,
 			Description: "Search for a location by name and get its weather forecast. This tool first geocodes the location name to coordinates, then fetches weather from yr.no. For cities, countries, or addresses.",
 			InputSchema: map[string]interface{}{
 				"type":     "object",
@@ -73,25 +79,102 @@ func (p *Provider) Tools() []Tool {
 				},
 			},
 		},
+		{
+			Name:        "weather_history",
+			CacheTTL:    6 * time.Hour,
+			Description: "Get observed daily weather for a past date or date range from the Open-Meteo historical archive (1940 onwards, up to about 5 days ago). Returns daily high/low temperature, precipitation and conditions. Give either a location name or latitude/longitude.",
+			InputSchema: map[string]interface{}{
+				"type":     "object",
+				"required": []string{"date"},
+				"properties": map[string]interface{}{
+					"date": map[string]interface{}{
+						"type":        "string",
+						"description": "Date in YYYY-MM-DD format (start of the range if end_date is given)",
+					},
+					"end_date": map[string]interface{}{
+						"type":        "string",
+						"description": "Optional end date in YYYY-MM-DD format (inclusive, at most 366 days after date)",
+					},
+					"location": map[string]interface{}{
+						"type":        "string",
+						"description": "Location name (city, address, landmark, etc.)",
+					},
+					"latitude": map[string]interface{}{
+						"type":        "number",
+						"description": "Latitude of the location (-90 to 90), instead of location",
+					},
+					"longitude": map[string]interface{}{
+						"type":        "number",
+						"description": "Longitude of the location (-180 to 180), instead of location",
+					},
+				},
+			},
+		},
+		{
+			Name:        "weather_air_quality",
+			CacheTTL:    10 * time.Minute,
+			Description: "Get current air quality from Open-Meteo: US EPA AQI, dominant pollutant, health category and raw pollutant concentrations, plus a daily AQI forecast for the next few days. Give either a location name or latitude/longitude.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"location": map[string]interface{}{
+						"type":        "string",
+						"description": "Location name (city, address, landmark, etc.)",
+					},
+					"latitude": map[string]interface{}{
+						"type":        "number",
+						"description": "Latitude of the location (-90 to 90), instead of location",
+					},
+					"longitude": map[string]interface{}{
+						"type":        "number",
+						"description": "Longitude of the location (-180 to 180), instead of location",
+					},
+					"forecast_days": map[string]interface{}{
+						"type":        "integer",
+						"description": "Days of AQI forecast to include (0-5, default: 3)",
+					},
+				},
+			},
+		},
+	}
+	for _, tool := range list {
+		tools.AddNoCacheArg(tool.InputSchema)
 	}
+	return list
+}
+
+// CacheTTL implements tools.CacheableProvider
+func (p *Provider) CacheTTL(name string) time.Duration {
+	for _, tool := range p.Tools() {
+		if tool.Name == name {
+			return tool.CacheTTL
+		}
+	}
+	return 0
 }
 
 // HasTool checks if a tool name belongs to this provider
 func (p *Provider) HasTool(name string) bool {
 	switch name {
-	case "weather_get_weather", "weather_search_location_weather":
+	case "weather_get_weather", "weather_search_location_weather", "weather_history", "weather_air_quality":
 		return true
 	}
 	return false
 }
 
 // Call executes a weather tool
-func (p *Provider) Call(name string, args map[string]interface{}) (interface{}, error) {
+func (p *Provider) Call(name string, args map[string]interface{}) (result interface{}, err error) {
+	defer func() { err = tools.WrapUnreachable("weather", err) }()
+
 	switch name {
 	case "weather_get_weather":
 		return p.getWeather(args)
 	case "weather_search_location_weather":
 		return p.searchLocationWeather(args)
+	case "weather_history":
+		return p.getWeatherHistory(args)
+	case "weather_air_quality":
+		return p.getAirQuality(args)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
@@ -150,25 +233,51 @@ func (p *Provider) searchLocationWeather(args map[string]interface{}) (interface
 		return nil, fmt.Errorf("location is required")
 	}
 
-	// Geocode using Nominatim (OpenStreetMap)
+	lat, lon, name, err := geocode(location)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch weather
+	apiURL := fmt.Sprintf("https://api.met.no/weatherapi/locationforecast/2.0/compact?lat=%f&lon=%f", lat, lon)
+	weatherData, err := p.fetchWeather(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build response
+	result := map[string]interface{}{
+		"location": map[string]interface{}{
+			"name":      name,
+			"latitude":  lat,
+			"longitude": lon,
+		},
+	}
+
+	p.formatWeatherResponse(result, weatherData)
+	return textContent(result), nil
+}
+
+// geocode resolves a location name to coordinates using Nominatim (OpenStreetMap)
+func geocode(location string) (lat, lon float64, displayName string, err error) {
 	geocodeURL := fmt.Sprintf("https://nominatim.openstreetmap.org/search?q=%s&format=json&limit=1",
 		url.QueryEscape(location))
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	req, err := http.NewRequest("GET", geocodeURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return 0, 0, "", fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("User-Agent", userAgent)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("geocoding failed: %w", err)
+		return 0, 0, "", fmt.Errorf("geocoding failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("geocoding failed: %s", resp.Status)
+		return 0, 0, "", fmt.Errorf("geocoding failed: %s", resp.Status)
 	}
 
 	var geocodeData []struct {
@@ -177,36 +286,48 @@ func (p *Provider) searchLocationWeather(args map[string]interface{}) (interface
 		DisplayName string `json:"display_name"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&geocodeData); err != nil {
-		return nil, fmt.Errorf("failed to parse geocode response: %w", err)
+		return 0, 0, "", fmt.Errorf("failed to parse geocode response: %w", err)
 	}
 
 	if len(geocodeData) == 0 {
-		return nil, fmt.Errorf("location not found: %s", location)
+		return 0, 0, "", fmt.Errorf("location not found: %s", location)
 	}
 
 	// Parse coordinates
-	var lat, lon float64
 	fmt.Sscanf(geocodeData[0].Lat, "%f", &lat)
 	fmt.Sscanf(geocodeData[0].Lon, "%f", &lon)
+	return lat, lon, geocodeData[0].DisplayName, nil
+}
 
-	// Fetch weather
-	apiURL := fmt.Sprintf("https://api.met.no/weatherapi/locationforecast/2.0/compact?lat=%f&lon=%f", lat, lon)
-	weatherData, err := p.fetchWeather(apiURL)
-	if err != nil {
-		return nil, err
-	}
-
-	// Build response
-	result := map[string]interface{}{
-		"location": map[string]interface{}{
-			"name":      geocodeData[0].DisplayName,
-			"latitude":  lat,
-			"longitude": lon,
-		},
+// resolveLocation reads either a "location" name (geocoded) or
+// "latitude"/"longitude" from tool arguments, returning the coordinates and a
+// location object for the response.
+func resolveLocation(args map[string]interface{}) (lat, lon float64, location map[string]interface{}, err error) {
+	location = map[string]interface{}{}
+	if name, _ := args["location"].(string); name != "" {
+		var displayName string
+		lat, lon, displayName, err = geocode(name)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		location["name"] = displayName
+	} else {
+		var okLat, okLon bool
+		lat, okLat = args["latitude"].(float64)
+		lon, okLon = args["longitude"].(float64)
+		if !okLat || !okLon {
+			return 0, 0, nil, fmt.Errorf("either location or latitude and longitude are required")
+		}
+		if lat < -90 || lat > 90 {
+			return 0, 0, nil, fmt.Errorf("latitude must be between -90 and 90")
+		}
+		if lon < -180 || lon > 180 {
+			return 0, 0, nil, fmt.Errorf("longitude must be between -180 and 180")
+		}
 	}
-
-	p.formatWeatherResponse(result, weatherData)
-	return textContent(result), nil
+	location["latitude"] = lat
+	location["longitude"] = lon
+	return lat, lon, location, nil
 }
 
 // fetchWeather makes HTTP request to yr.no API